@@ -39,8 +39,10 @@ import (
 	"github.com/kcp-dev/kcp/pkg/cmd/help"
 	"github.com/kcp-dev/kcp/pkg/embeddedetcd"
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
+	"github.com/kcp-dev/kcp/pkg/perf"
 	"github.com/kcp-dev/kcp/pkg/server"
 	"github.com/kcp-dev/kcp/pkg/server/options"
+	"github.com/kcp-dev/kcp/pkg/storagegc"
 )
 
 func main() {
@@ -175,6 +177,53 @@ func main() {
 	startCmd.AddCommand(startOptionsCmd)
 	cmd.AddCommand(startCmd)
 
+	perfOptions := perf.NewOptions()
+	perfCmd := &cobra.Command{
+		Use:   "perf",
+		Short: "Run a load test against a running kcp installation",
+		Long: help.Doc(`
+			Run a load test against a running kcp installation
+
+			Creates a load test workspace with the given number of child workspaces,
+			binds an APIExport in some of them, and creates, updates and deletes
+			objects of the exported API at the given rate. At the end, the latency
+			percentiles of all operations are reported, in order to validate the
+			sizing of an installation before rolling it out.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if errs := perfOptions.Validate(); len(errs) > 0 {
+				return errors.NewAggregate(errs)
+			}
+			return perf.Run(genericapiserver.SetupSignalContext(), perfOptions, cmd.OutOrStdout())
+		},
+	}
+	perfOptions.AddFlags(perfCmd.Flags())
+	cmd.AddCommand(perfCmd)
+
+	storageGCOptions := storagegc.NewOptions()
+	storageGCCmd := &cobra.Command{
+		Use:   "storage-gc",
+		Short: "Garbage collect the etcd storage of orphaned APIExport identities",
+		Long: help.Doc(`
+			Garbage collect the etcd storage of orphaned APIExport identities
+
+			Scans the etcd of a kcp shard for objects stored under APIExport identity
+			hashes, and deletes those of identities that are neither referenced by an
+			APIExport nor by an APIBinding anymore, e.g. after an APIExport was deleted
+			while objects were still bound. The reclaimed space is reported per identity
+			and resource. By default, this is a dry-run only reporting what would be
+			deleted.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if errs := storageGCOptions.Validate(); len(errs) > 0 {
+				return errors.NewAggregate(errs)
+			}
+			return storagegc.Run(genericapiserver.SetupSignalContext(), storageGCOptions, cmd.OutOrStdout())
+		},
+	}
+	storageGCOptions.AddFlags(storageGCCmd.Flags())
+	cmd.AddCommand(storageGCCmd)
+
 	setPartialUsageAndHelpFunc(startCmd, namedStartFlagSets, cols, []string{
 		"etcd-servers",
 		"batteries-included",
@@ -27,6 +27,7 @@ import (
 	"k8s.io/component-base/version"
 	"k8s.io/klog/v2"
 
+	apiexportcmd "github.com/kcp-dev/kcp/pkg/cliplugins/apiexport/cmd"
 	bindcmd "github.com/kcp-dev/kcp/pkg/cliplugins/bind/cmd"
 	claimscmd "github.com/kcp-dev/kcp/pkg/cliplugins/claims/cmd"
 	crdcmd "github.com/kcp-dev/kcp/pkg/cliplugins/crd/cmd"
@@ -88,5 +89,8 @@ func KubectlKcpCommand() *cobra.Command {
 	claimsCmd := claimscmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
 	root.AddCommand(claimsCmd)
 
+	apiexportCmd := apiexportcmd.New(genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	root.AddCommand(apiexportCmd)
+
 	return root
 }
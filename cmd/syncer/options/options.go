@@ -45,6 +45,7 @@ type Options struct {
 	SyncedResourceTypes           []string
 	DNSImage                      string
 	DownstreamNamespaceCleanDelay time.Duration
+	MutatorPlugins                []string
 
 	APIImportPollInterval time.Duration
 }
@@ -58,6 +59,7 @@ func NewOptions() *Options {
 		QPS:                           30,
 		Burst:                         20,
 		SyncedResourceTypes:           []string{},
+		MutatorPlugins:                []string{},
 		Logs:                          logs,
 		APIImportPollInterval:         1 * time.Minute,
 		DownstreamNamespaceCleanDelay: 30 * time.Second,
@@ -82,6 +84,7 @@ func (options *Options) AddFlags(fs *pflag.FlagSet) {
 		"Options are:\n"+strings.Join(kcpfeatures.KnownFeatures(), "\n")) // hide kube-only gates
 	fs.StringVar(&options.DNSImage, "dns-image", options.DNSImage, "kcp DNS server image.")
 	fs.DurationVar(&options.DownstreamNamespaceCleanDelay, "downstream-namespace-clean-delay", options.DownstreamNamespaceCleanDelay, "Time to wait before deleting a downstream namespace, defaults to 30s.")
+	fs.StringArrayVar(&options.MutatorPlugins, "mutator-plugin", options.MutatorPlugins, "Path to a Go plugin exporting a resource mutator applied during downsync and upsync. Can be repeated; mutators run in the given order.")
 
 	options.Logs.AddFlags(fs)
 }
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"time"
@@ -33,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authorization/union"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/client-go/pkg/version"
 	"k8s.io/client-go/tools/clientcmd"
@@ -43,7 +45,9 @@ import (
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
+	"github.com/kcp-dev/kcp/pkg/memory"
 	"github.com/kcp-dev/kcp/pkg/server/bootstrap"
+	virtualauthorization "github.com/kcp-dev/kcp/pkg/virtual/framework/authorization"
 	virtualrootapiserver "github.com/kcp-dev/kcp/pkg/virtual/framework/rootapiserver"
 )
 
@@ -133,6 +137,7 @@ func Run(ctx context.Context, o *options.Options) error {
 	metav1.AddToGroupVersion(scheme, schema.GroupVersion{Group: "", Version: "v1"})
 	codecs := serializer.NewCodecFactory(scheme)
 	recommendedConfig := genericapiserver.NewRecommendedConfig(codecs)
+	recommendedConfig.EnableMetrics = true
 	if err := o.SecureServing.ApplyTo(&recommendedConfig.Config.SecureServing); err != nil {
 		return err
 	}
@@ -142,6 +147,17 @@ func Run(ctx context.Context, o *options.Options) error {
 	if err := o.Authorization.ApplyTo(&recommendedConfig.Config, virtualWorkspaces); err != nil {
 		return err
 	}
+	var delegatingAuthorization genericapiserver.AuthorizationInfo
+	if err := o.DelegatingAuthorization.ApplyTo(&delegatingAuthorization); err != nil {
+		return err
+	}
+	if delegatingAuthorization.Authorizer != nil {
+		// requests outside of virtual workspaces, e.g. /metrics, are authorized by the kcp instance
+		recommendedConfig.Authorization.Authorizer = union.New(
+			recommendedConfig.Authorization.Authorizer,
+			virtualauthorization.NewNonVirtualWorkspaceAuthorizer(delegatingAuthorization.Authorizer),
+		)
+	}
 	if err := o.Audit.ApplyTo(&recommendedConfig.Config); err != nil {
 		return err
 	}
@@ -152,6 +168,9 @@ func Run(ctx context.Context, o *options.Options) error {
 	if err != nil {
 		return err
 	}
+	if rootAPIServerConfig.ExtraConfig.TrustedProxies, err = o.VirtualWorkspaces.TrustedProxyNetworks(); err != nil {
+		return err
+	}
 
 	completedRootAPIServerConfig := rootAPIServerConfig.Complete()
 	rootAPIServer, err := completedRootAPIServerConfig.New(genericapiserver.NewEmptyDelegate())
@@ -165,11 +184,47 @@ func Run(ctx context.Context, o *options.Options) error {
 		return err
 	}
 
+	if o.MetricsAddress != "" {
+		if err := serveMetrics(ctx, o.MetricsAddress, recommendedConfig.SecureServing, preparedRootAPIServer.GenericAPIServer.Handler); err != nil {
+			return err
+		}
+		logger.Info("Serving metrics and health endpoints", "address", o.MetricsAddress)
+	}
+
+	governor, err := memory.NewGovernor(&o.MemoryGovernor)
+	if err != nil {
+		return err
+	}
+	if governor != nil {
+		go governor.Start(ctx)
+	}
+
 	logger.Info("Starting virtual workspace apiserver on ", "externalAddress", rootAPIServerConfig.GenericConfig.ExternalAddress, "version", version.Get().String())
 
 	return preparedRootAPIServer.Run(ctx.Done())
 }
 
+// serveMetrics serves the metrics and health endpoints of the given handler on an additional listener,
+// using the certificates of the secure port. Authentication and authorization are done by the handler.
+func serveMetrics(ctx context.Context, address string, secureServing *genericapiserver.SecureServingInfo, handler http.Handler) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on metrics address %q: %w", address, err)
+	}
+
+	servingInfo := *secureServing
+	servingInfo.Listener = listener
+
+	mux := http.NewServeMux()
+	for _, path := range []string{"/metrics", "/healthz", "/readyz", "/livez"} {
+		mux.Handle(path, handler)
+		mux.Handle(path+"/", handler)
+	}
+
+	_, _, err = servingInfo.Serve(mux, time.Minute, ctx.Done())
+	return err
+}
+
 func readKubeConfig(kubeConfigFile, context string) (clientcmd.ClientConfig, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	loadingRules.ExplicitPath = kubeConfigFile
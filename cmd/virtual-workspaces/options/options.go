@@ -29,6 +29,7 @@ import (
 	genericapiserveroptions "k8s.io/apiserver/pkg/server/options"
 	"k8s.io/component-base/logs"
 
+	"github.com/kcp-dev/kcp/pkg/memory"
 	virtualworkspacesoptions "github.com/kcp-dev/kcp/pkg/virtual/options"
 )
 
@@ -49,10 +50,18 @@ type Options struct {
 	Authorization  virtualworkspacesoptions.Authorization
 	Audit          genericapiserveroptions.AuditOptions
 
+	// DelegatingAuthorization authorizes requests not targeting a virtual workspace, e.g. /metrics,
+	// against the kcp instance via SubjectAccessReviews.
+	DelegatingAuthorization genericapiserveroptions.DelegatingAuthorizationOptions
+
 	Logs logs.Options
 
 	VirtualWorkspaces virtualworkspacesoptions.Options
 	ProfilerAddress   string
+	MetricsAddress    string
+
+	// MemoryGovernor evicts the caches of the virtual workspaces under memory pressure.
+	MemoryGovernor memory.Options
 }
 
 func NewOptions() *Options {
@@ -67,22 +76,30 @@ func NewOptions() *Options {
 		Audit:          *genericapiserveroptions.NewAuditOptions(),
 		Logs:           *logs.NewOptions(),
 
+		DelegatingAuthorization: *genericapiserveroptions.NewDelegatingAuthorizationOptions(),
+
 		VirtualWorkspaces: *virtualworkspacesoptions.NewOptions(),
 		ProfilerAddress:   "",
+		MetricsAddress:    "",
+
+		MemoryGovernor: *memory.DefaultOptions(),
 	}
 
 	opts.SecureServing.ServerCert.CertKey.CertFile = filepath.Join(".", ".kcp", "apiserver.crt")
 	opts.SecureServing.ServerCert.CertKey.KeyFile = filepath.Join(".", ".kcp", "apiserver.key")
 	opts.SecureServing.BindPort = 6444
 	opts.Authentication.SkipInClusterLookup = true
+	opts.DelegatingAuthorization.RemoteKubeConfigFileOptional = true
 	return opts
 }
 
 func (o *Options) AddFlags(flags *pflag.FlagSet) {
 	o.SecureServing.AddFlags(flags)
 	o.Authentication.AddFlags(flags)
+	o.DelegatingAuthorization.AddFlags(flags)
 	o.Logs.AddFlags(flags)
 	o.VirtualWorkspaces.AddFlags(flags)
+	memory.BindOptions(&o.MemoryGovernor, flags)
 
 	flags.StringVar(&o.KubeconfigFile, "kubeconfig", o.KubeconfigFile,
 		"The kubeconfig file of the KCP instance that hosts workspaces.")
@@ -90,13 +107,18 @@ func (o *Options) AddFlags(flags *pflag.FlagSet) {
 
 	flags.StringVar(&o.Context, "context", o.Context, "Name of the context in the kubeconfig file to use")
 	flags.StringVar(&o.ProfilerAddress, "profiler-address", "", "[Address]:port to bind the profiler to")
+	flags.StringVar(&o.MetricsAddress, "metrics-address", "", "[Address]:port to additionally serve /metrics, /healthz, /readyz and /livez on, with the certificates, authentication and authorization of the secure port")
 }
 
 func (o *Options) Validate() error {
 	errs := []error{}
 	errs = append(errs, o.SecureServing.Validate()...)
 	errs = append(errs, o.Authentication.Validate()...)
+	errs = append(errs, o.DelegatingAuthorization.Validate()...)
 	errs = append(errs, o.VirtualWorkspaces.Validate()...)
+	if err := o.MemoryGovernor.Validate(); err != nil {
+		errs = append(errs, err)
+	}
 
 	if len(o.KubeconfigFile) == 0 {
 		errs = append(errs, fmt.Errorf("--kubeconfig is required for this command"))
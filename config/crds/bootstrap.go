@@ -47,14 +47,30 @@ var raw embed.FS
 // CreateFromFS creates the given CRDs using the target client from the
 // provided filesystem and waits for it to become established. This call is blocking.
 func CreateFromFS(ctx context.Context, client apiextensionsv1client.CustomResourceDefinitionInterface, fs embed.FS, grs ...metav1.GroupResource) error {
+	return inParallel(ctx, grs, func(gr metav1.GroupResource) error {
+		return createSingleFromFS(ctx, client, gr, fs)
+	})
+}
+
+// CreateMissing creates those of the given CRDs that do not exist yet using the target client, and
+// waits for all of them to become established in parallel. Existing CRDs are not updated, whatever
+// their schema is. This call is blocking.
+func CreateMissing(ctx context.Context, client apiextensionsv1client.CustomResourceDefinitionInterface, crds ...*apiextensionsv1.CustomResourceDefinition) error {
+	return inParallel(ctx, crds, func(crd *apiextensionsv1.CustomResourceDefinition) error {
+		return createSingle(ctx, client, crd, false)
+	})
+}
+
+// inParallel calls f for every item in parallel, retrying retryable errors, and aggregates the errors.
+func inParallel[T any](ctx context.Context, items []T, f func(T) error) error {
 	wg := sync.WaitGroup{}
-	bootstrapErrChan := make(chan error, len(grs))
-	for _, gk := range grs {
+	bootstrapErrChan := make(chan error, len(items))
+	for _, item := range items {
 		wg.Add(1)
-		go func(gr metav1.GroupResource) {
+		go func(item T) {
 			defer wg.Done()
 			err := retryRetryableErrors(func() error {
-				return createSingleFromFS(ctx, client, gr, fs)
+				return f(item)
 			})
 			// wait.Poll functions return ErrWaitTimeout instead the context cancellation error, for backward compatibility reasons, see:
 			// https://github.com/kubernetes/kubernetes/blob/b5f8cca701575678819b5e9e6372df989ab6799f/staging/src/k8s.io/apimachinery/pkg/util/wait/wait.go
@@ -65,7 +81,7 @@ func CreateFromFS(ctx context.Context, client apiextensionsv1client.CustomResour
 				err = ctx.Err()
 			}
 			bootstrapErrChan <- err
-		}(gk)
+		}(item)
 	}
 	wg.Wait()
 	close(bootstrapErrChan)
@@ -123,7 +139,13 @@ func CRD(fs embed.FS, gr metav1.GroupResource) (*apiextensionsv1.CustomResourceD
 	return crd, nil
 }
 
+// CreateSingle creates or updates the given CRD using the target client and waits for it to become
+// established. This call is blocking.
 func CreateSingle(ctx context.Context, client apiextensionsv1client.CustomResourceDefinitionInterface, rawCRD *apiextensionsv1.CustomResourceDefinition) error {
+	return createSingle(ctx, client, rawCRD, true)
+}
+
+func createSingle(ctx context.Context, client apiextensionsv1client.CustomResourceDefinitionInterface, rawCRD *apiextensionsv1.CustomResourceDefinition, update bool) error {
 	logger := klog.FromContext(ctx).WithValues("crd", rawCRD.Name)
 	start := time.Now()
 	logger.V(4).Info("bootstrapping CRD")
@@ -142,7 +164,7 @@ func CreateSingle(ctx context.Context, client apiextensionsv1client.CustomResour
 					if err != nil {
 						return fmt.Errorf("error getting CRD %s: %w", rawCRD.Name, err)
 					}
-					updateNeeded = true
+					updateNeeded = update
 				} else {
 					return fmt.Errorf("error creating CRD %s: %w", rawCRD.Name, err)
 				}
@@ -153,7 +175,7 @@ func CreateSingle(ctx context.Context, client apiextensionsv1client.CustomResour
 			return fmt.Errorf("error fetching CRD %s: %w", rawCRD.Name, err)
 		}
 	} else {
-		updateNeeded = true
+		updateNeeded = update
 	}
 	logger = logging.WithObject(logger, crd)
 
@@ -197,6 +219,11 @@ func retryRetryableErrors(f func() error) error {
 	}, f)
 }
 
+// Get returns the embedded CRD for the given GroupResource.
+func Get(gr metav1.GroupResource) (*apiextensionsv1.CustomResourceDefinition, error) {
+	return CRD(raw, gr)
+}
+
 // Unmarshal YAML-decodes the give embedded file name into the target.
 func Unmarshal(fileName string, crd *apiextensionsv1.CustomResourceDefinition) error {
 	bs, err := raw.ReadFile(fileName)
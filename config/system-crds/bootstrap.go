@@ -38,24 +38,32 @@ import (
 //go:embed *.yaml
 var fs embed.FS
 
-// Bootstrap creates CRDs and the resources in this package by continuously retrying the list.
+// GroupResources is the full list of CRDs that kcp owns and manages in the system:system-crds logical cluster. Our custom
+// CRD lister serves them to every workspace, from the CRD informer cache, with priority over CRDs coming from APIBindings
+// and local CRDs. See pkg/server/apiextensions.go getSystemCRD. These CRDs should never be installed in any other
+// logical cluster.
+//
+// Existing system CRDs are not updated on start. New schemas are rolled out by the system CRD controller once every shard
+// ships them, see pkg/reconciler/apis/systemcrds.
+var GroupResources = []metav1.GroupResource{
+	{Group: apis.GroupName, Resource: "apiexports"},
+	{Group: apis.GroupName, Resource: "apibindings"},
+	{Group: apis.GroupName, Resource: "apiresourceschemas"},
+}
+
+// Bootstrap creates missing CRDs and the resources in this package by continuously retrying the list.
 // This is blocking, i.e. it only returns (with error) when the context is closed or with nil when
 // the bootstrapping is successfully completed.
 func Bootstrap(ctx context.Context, crdClient apiextensionsclient.Interface, discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, batteriesIncluded sets.String) error {
 	logger := klog.FromContext(ctx)
-	// This is the full list of CRDs that kcp owns and manages in the system:system-crds logical cluster. Our custom CRD
-	// lister currently has a hard-coded list of which system CRDs are made available to which workspaces. See
-	// pkg/server/apiextensions.go newSystemCRDProvider for the list. These CRDs should never be installed in any other
-	// logical cluster.
-	// TODO(sttts): get rid of this and enforce/support schema evolution while allowing wildcard informers to work
-	crds := []metav1.GroupResource{
-		{Group: apis.GroupName, Resource: "apiexports"},
-		{Group: apis.GroupName, Resource: "apibindings"},
-		{Group: apis.GroupName, Resource: "apiresourceschemas"},
+
+	crds, err := CRDs()
+	if err != nil {
+		return err
 	}
 
 	if err := wait.PollImmediateInfiniteWithContext(ctx, time.Second, func(ctx context.Context) (bool, error) {
-		if err := configcrds.Create(ctx, crdClient.ApiextensionsV1().CustomResourceDefinitions(), crds...); err != nil {
+		if err := configcrds.CreateMissing(ctx, crdClient.ApiextensionsV1().CustomResourceDefinitions(), crds...); err != nil {
 			logger.Error(err, "failed to bootstrap system CRDs, retrying")
 			return false, nil // keep retrying
 		}
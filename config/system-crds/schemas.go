@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package systemcrds
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	configcrds "github.com/kcp-dev/kcp/config/crds"
+)
+
+const (
+	// SchemaAnnotationKey is the annotation on a system CRD holding the hash of the schema it has been
+	// created or last updated with.
+	SchemaAnnotationKey = "apis.kcp.dev/system-crd-schema"
+
+	// ShardSchemasAnnotationKey is the annotation on a ClusterWorkspaceShard listing the system CRD schemas
+	// the shard ships, as comma separated <crd name>=<schema hash> pairs. A shard acknowledges a new schema
+	// by shipping it. The schema is rolled out once every shard acknowledged it.
+	ShardSchemasAnnotationKey = "internal.apis.kcp.dev/system-crd-schemas"
+)
+
+// CRDs returns the system CRDs shipped with this binary, annotated with the hash of their schema.
+func CRDs() ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	crds := make([]*apiextensionsv1.CustomResourceDefinition, 0, len(GroupResources))
+	for _, gr := range GroupResources {
+		crd, err := configcrds.Get(gr)
+		if err != nil {
+			return nil, err
+		}
+		hash, err := SchemaHash(crd)
+		if err != nil {
+			return nil, err
+		}
+		if crd.Annotations == nil {
+			crd.Annotations = map[string]string{}
+		}
+		crd.Annotations[SchemaAnnotationKey] = hash
+		crds = append(crds, crd)
+	}
+	return crds, nil
+}
+
+// SchemaHash returns the hash of the spec of the given CRD.
+func SchemaHash(crd *apiextensionsv1.CustomResourceDefinition) (string, error) {
+	bs, err := json.Marshal(crd.Spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the spec of CRD %s: %w", crd.Name, err)
+	}
+	hash := sha256.Sum256(bs)
+	return hex.EncodeToString(hash[:8]), nil
+}
+
+// FormatShardSchemas returns the value of the ShardSchemasAnnotationKey annotation for the given CRDs.
+func FormatShardSchemas(crds []*apiextensionsv1.CustomResourceDefinition) string {
+	pairs := make([]string, 0, len(crds))
+	for _, crd := range crds {
+		pairs = append(pairs, crd.Name+"="+crd.Annotations[SchemaAnnotationKey])
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// ParseShardSchemas returns the schema hashes by CRD name of the value of the ShardSchemasAnnotationKey
+// annotation. Malformed pairs are skipped.
+func ParseShardSchemas(value string) map[string]string {
+	schemas := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		name, hash, found := strings.Cut(pair, "=")
+		if !found || name == "" || hash == "" {
+			continue
+		}
+		schemas[name] = hash
+	}
+	return schemas
+}
@@ -21,23 +21,27 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	"github.com/kcp-dev/logicalcluster/v2"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authentication/user"
-	"k8s.io/apiserver/pkg/authorization/authorizer"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/warning"
 	"k8s.io/klog/v2"
 
 	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1/permissionclaims"
 	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	"github.com/kcp-dev/kcp/pkg/reference"
 )
 
 const (
@@ -50,6 +54,7 @@ func Register(plugins *admission.Plugins) {
 			return &apiBindingAdmission{
 				Handler:          admission.NewHandler(admission.Create, admission.Update),
 				createAuthorizer: delegated.NewDelegatedAuthorizer,
+				now:              time.Now,
 			}, nil
 		})
 }
@@ -59,6 +64,15 @@ type apiBindingAdmission struct {
 	deepSARClient kcpkubernetesclientset.ClusterInterface
 
 	createAuthorizer delegated.DelegatedAuthorizerFactory
+
+	listAPIBindings        func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	getAPIExport           func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)
+	getAPIResourceSchema   func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	listAPIResourceSchemas func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIResourceSchema, error)
+	getCRD                 func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
+	listCRDs               func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error)
+
+	now func() time.Time
 }
 
 // Ensure that the required admission interfaces are implemented.
@@ -67,6 +81,8 @@ var (
 	_ = admission.MutationInterface(&apiBindingAdmission{})
 	_ = admission.InitializationValidator(&apiBindingAdmission{})
 	_ = kcpinitializers.WantsDeepSARClient(&apiBindingAdmission{})
+	_ = kcpinitializers.WantsKcpInformers(&apiBindingAdmission{})
+	_ = kcpinitializers.WantsApiExtensionsInformers(&apiBindingAdmission{})
 )
 
 func (o *apiBindingAdmission) Admit(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
@@ -181,7 +197,7 @@ func (o *apiBindingAdmission) Validate(ctx context.Context, a admission.Attribut
 	}
 
 	// Access check
-	if err := o.checkAPIExportAccess(ctx, a.GetUserInfo(), logicalcluster.New(apiBinding.Spec.Reference.Workspace.Path), apiBinding.Spec.Reference.Workspace.ExportName); err != nil {
+	if err := o.checkAPIExportAccess(ctx, a.GetUserInfo(), logicalcluster.New(apiBinding.Spec.Reference.Workspace.Path), *apiBinding.Spec.Reference.Workspace); err != nil {
 		action := "create"
 		if a.GetOperation() == admission.Update {
 			action = "update"
@@ -189,10 +205,38 @@ func (o *apiBindingAdmission) Validate(ctx context.Context, a admission.Attribut
 		return admission.NewForbidden(a, fmt.Errorf("unable to %s APIImport: %w", action, err))
 	}
 
+	// Reject new bindings to API versions past their sunset, see sunsetVersions.
+	if a.GetOperation() == admission.Create {
+		if sunset := o.sunsetVersions(apiBinding); len(sunset) > 0 {
+			return admission.NewForbidden(a, fmt.Errorf("APIExport %s serves API versions past their sunset, no new bindings are allowed: %s", apiBinding.Spec.Reference.Workspace.ExportName, strings.Join(sunset, "; ")))
+		}
+	}
+
+	// Warn about outdated APIs, see outdatedSchemaWarnings.
+	if a.GetOperation() == admission.Create {
+		for _, w := range o.outdatedSchemaWarnings(apiBinding) {
+			warning.AddWarning(ctx, "", w)
+		}
+	}
+
+	// Bind compatibility check and preview, see checkBindCompatibility and bindPreview.
+	if a.GetOperation() == admission.Create && a.IsDryRun() {
+		cluster, err := genericapirequest.ValidClusterFrom(ctx)
+		if err != nil {
+			return admission.NewForbidden(a, fmt.Errorf("error determining workspace: %w", err))
+		}
+		if errs := o.checkBindCompatibility(cluster.Name, apiBinding); len(errs) > 0 {
+			return admission.NewForbidden(a, fmt.Errorf("binding would not succeed: %v", errs))
+		}
+		for _, w := range o.bindPreview(apiBinding) {
+			warning.AddWarning(ctx, "", w)
+		}
+	}
+
 	return nil
 }
 
-func (o *apiBindingAdmission) checkAPIExportAccess(ctx context.Context, user user.Info, apiExportClusterName logicalcluster.Name, apiExportName string) error {
+func (o *apiBindingAdmission) checkAPIExportAccess(ctx context.Context, user user.Info, apiExportClusterName logicalcluster.Name, exportRef apisv1alpha1.WorkspaceExportReference) error {
 	logger := klog.FromContext(ctx)
 	authz, err := o.createAuthorizer(apiExportClusterName, o.deepSARClient)
 	if err != nil {
@@ -202,20 +246,10 @@ func (o *apiBindingAdmission) checkAPIExportAccess(ctx context.Context, user use
 		return errors.New("unable to authorize request")
 	}
 
-	bindAttr := authorizer.AttributesRecord{
-		User:            user,
-		Verb:            "bind",
-		APIGroup:        apisv1alpha1.SchemeGroupVersion.Group,
-		APIVersion:      apisv1alpha1.SchemeGroupVersion.Version,
-		Resource:        "apiexports",
-		Name:            apiExportName,
-		ResourceRequest: true,
-	}
-
-	if decision, _, err := authz.Authorize(ctx, bindAttr); err != nil {
-		return fmt.Errorf("unable to determine access to apiexports: %w", err)
-	} else if decision != authorizer.DecisionAllow {
-		return fmt.Errorf("no permission to bind to export %q", apiExportName)
+	if allowed, err := reference.Authorize(ctx, authz, user, reference.ForAPIExport(exportRef), "bind"); err != nil {
+		return err
+	} else if !allowed {
+		return fmt.Errorf("no permission to bind to export %q", exportRef.ExportName)
 	}
 
 	return nil
@@ -226,6 +260,12 @@ func (o *apiBindingAdmission) ValidateInitialization() error {
 	if o.deepSARClient == nil {
 		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
 	}
+	if o.getAPIExport == nil {
+		return fmt.Errorf(PluginName + " plugin needs kcp informers")
+	}
+	if o.listCRDs == nil {
+		return fmt.Errorf(PluginName + " plugin needs apiextensions informers")
+	}
 
 	return nil
 }
@@ -22,17 +22,22 @@ import (
 	"errors"
 	"math/big"
 	"testing"
+	"time"
 
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	"github.com/kcp-dev/logicalcluster/v2"
 	"github.com/stretchr/testify/require"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/warning"
 
 	"github.com/kcp-dev/kcp/pkg/admission/helpers"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
@@ -204,6 +209,23 @@ func TestValidate(t *testing.T) {
 			),
 			authzDecision: authorizer.DecisionAllow,
 		},
+		{
+			name: "Create: namespaces pass when valid",
+			attr: createAttr(
+				newAPIBinding().withName("test").withAbsoluteWorkspaceReference("root:org:workspaceName", "someExport").withNamespaces("team-a", "team-b").
+					withLabel(apisv1alpha1.InternalAPIBindingExportLabelKey, toSha224Base62("root:org:workspaceName:someExport")).APIBinding,
+			),
+			authzDecision: authorizer.DecisionAllow,
+		},
+		{
+			name: "Create: invalid namespaces fail",
+			attr: createAttr(
+				newAPIBinding().withName("test").withAbsoluteWorkspaceReference("root:org:workspaceName", "someExport").withNamespaces("team-a", "Team_B").
+					withLabel(apisv1alpha1.InternalAPIBindingExportLabelKey, toSha224Base62("root:org:workspaceName:someExport")).APIBinding,
+			),
+			authzDecision:  authorizer.DecisionAllow,
+			expectedErrors: []string{"spec.namespaces[1]: Invalid value"},
+		},
 		{
 			name: "Create: complete root absolute workspace reference passes when authorized",
 			attr: createAttr(
@@ -443,6 +465,9 @@ func TestValidate(t *testing.T) {
 						tc.authzError,
 					}, nil
 				},
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+				},
 			}
 
 			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.From(tc.attr.GetObject().(metav1.Object))})
@@ -508,6 +533,11 @@ func (b *bindingBuilder) withLabel(k, v string) *bindingBuilder {
 	return b
 }
 
+func (b *bindingBuilder) withNamespaces(namespaces ...string) *bindingBuilder {
+	b.Spec.Namespaces = namespaces
+	return b
+}
+
 func (b *bindingBuilder) withPhase(phase apisv1alpha1.APIBindingPhaseType) *bindingBuilder {
 	b.Status.Phase = phase
 	return b
@@ -522,3 +552,240 @@ func toBase62(hash [28]byte) string {
 	i.SetBytes(hash[:])
 	return i.Text(62)
 }
+
+func TestValidateDryRunCompatibility(t *testing.T) {
+	widgetsSchema := func(name string, versions ...string) *apisv1alpha1.APIResourceSchema {
+		schema := &apisv1alpha1.APIResourceSchema{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)},
+			Spec: apisv1alpha1.APIResourceSchemaSpec{
+				Group: "kcp.dev",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget", ListKind: "WidgetList"},
+			},
+		}
+		for _, v := range versions {
+			schema.Spec.Versions = append(schema.Spec.Versions, apisv1alpha1.APIResourceVersion{Name: v, Served: true})
+		}
+		return schema
+	}
+	widgetsCRD := func(name string, storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "kcp.dev",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets"},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				AcceptedNames:  apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget", ListKind: "WidgetList"},
+				StoredVersions: storedVersions,
+			},
+		}
+	}
+
+	apiExports := map[string]*apisv1alpha1.APIExport{
+		"widgets":            {Spec: apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{"today.widgets.kcp.dev"}}},
+		"other-widgets":      {Spec: apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{"other.widgets.kcp.dev"}}},
+		"deprecated-widgets": {Spec: apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{"deprecated.widgets.kcp.dev"}}},
+		"sunset-widgets":     {Spec: apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{"sunset.widgets.kcp.dev"}}},
+		"sunsetting-widgets": {Spec: apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{"sunsetting.widgets.kcp.dev"}}},
+		"claiming-widgets": {
+			ObjectMeta: metav1.ObjectMeta{Name: "claiming-widgets"},
+			Spec: apisv1alpha1.APIExportSpec{
+				LatestResourceSchemas: []string{"today.widgets.kcp.dev"},
+				PermissionClaims: []apisv1alpha1.PermissionClaim{
+					{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true},
+					{GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"}, ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "default"}}},
+				},
+			},
+		},
+	}
+	schemas := map[string]*apisv1alpha1.APIResourceSchema{
+		"today.widgets.kcp.dev":      widgetsSchema("today.widgets.kcp.dev", "v1"),
+		"other.widgets.kcp.dev":      widgetsSchema("other.widgets.kcp.dev", "v1"),
+		"deprecated.widgets.kcp.dev": widgetsSchema("deprecated.widgets.kcp.dev", "v1"),
+		"sunset.widgets.kcp.dev":     widgetsSchema("sunset.widgets.kcp.dev", "v1", "v2"),
+		"sunsetting.widgets.kcp.dev": widgetsSchema("sunsetting.widgets.kcp.dev", "v1", "v2"),
+	}
+	schemas["deprecated.widgets.kcp.dev"].Spec.Versions[0].Deprecated = true
+	schemas["sunset.widgets.kcp.dev"].Spec.Versions[0].Deprecated = true
+	schemas["sunset.widgets.kcp.dev"].Spec.Versions[0].SunsetTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	schemas["sunsetting.widgets.kcp.dev"].Spec.Versions[0].Deprecated = true
+	schemas["sunsetting.widgets.kcp.dev"].Spec.Versions[0].SunsetTime = &metav1.Time{Time: time.Now().Add(24 * time.Hour)}
+	newerWidgetsSchema := widgetsSchema("tomorrow.widgets.kcp.dev", "v1")
+	newerWidgetsSchema.CreationTimestamp = metav1.Now()
+
+	tests := []struct {
+		name           string
+		dryRun         bool
+		exportName     string
+		bindings       []*apisv1alpha1.APIBinding
+		crds           []*apiextensionsv1.CustomResourceDefinition
+		schemas        []*apisv1alpha1.APIResourceSchema
+		acceptedClaims []apisv1alpha1.AcceptablePermissionClaim
+		expectedErrors []string
+		expectedWarns  []string
+	}{
+		{
+			name:          "compatible",
+			dryRun:        true,
+			exportName:    "widgets",
+			expectedWarns: []string{"would bind widgets.kcp.dev in versions [v1], installing a CustomResourceDefinition from APIResourceSchema today.widgets.kcp.dev"},
+		},
+		{
+			name:       "compatible with permission claims",
+			dryRun:     true,
+			exportName: "claiming-widgets",
+			acceptedClaims: []apisv1alpha1.AcceptablePermissionClaim{
+				{PermissionClaim: apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true}, State: apisv1alpha1.ClaimAccepted},
+			},
+			expectedWarns: []string{
+				"would bind widgets.kcp.dev",
+				"APIExport claiming-widgets claims permissions on configmaps (all objects): accepted",
+				"APIExport claiming-widgets claims permissions on secrets (objects matching its resource selectors): not accepted yet",
+			},
+		},
+		{
+			name:           "export not found",
+			dryRun:         true,
+			exportName:     "unknown",
+			expectedErrors: []string{`spec.reference.workspace.exportName: Not found: "unknown"`},
+		},
+		{
+			name:       "conflicting binding",
+			dryRun:     true,
+			exportName: "widgets",
+			bindings: []*apisv1alpha1.APIBinding{
+				newAPIBinding().withName("other").withAbsoluteWorkspaceReference("root:org:provider", "other-widgets").withBoundSchema("kcp.dev", "widgets", "other.widgets.kcp.dev").APIBinding,
+			},
+			expectedErrors: []string{"naming conflict with a bound API other"},
+		},
+		{
+			name:       "conflicting CRD with stored versions not served",
+			dryRun:     true,
+			exportName: "widgets",
+			crds:       []*apiextensionsv1.CustomResourceDefinition{widgetsCRD("widgets.kcp.dev", "v1alpha1", "v1")},
+			expectedErrors: []string{
+				`overlaps with "widgets.kcp.dev" CustomResourceDefinition`,
+				"objects of CustomResourceDefinition widgets.kcp.dev are stored in versions [v1alpha1] that APIResourceSchema today.widgets.kcp.dev does not serve",
+			},
+		},
+		{
+			name:       "not checked without dry-run",
+			exportName: "widgets",
+			crds:       []*apiextensionsv1.CustomResourceDefinition{widgetsCRD("widgets.kcp.dev", "v1alpha1")},
+		},
+		{
+			name:          "deprecated schema warns",
+			exportName:    "deprecated-widgets",
+			expectedWarns: []string{"APIResourceSchema deprecated.widgets.kcp.dev: kcp.dev/v1 Widget is deprecated"},
+		},
+		{
+			name:          "version before its sunset warns",
+			exportName:    "sunsetting-widgets",
+			expectedWarns: []string{"APIResourceSchema sunsetting.widgets.kcp.dev: kcp.dev/v1 Widget is deprecated; sunset on "},
+		},
+		{
+			name:           "version past its sunset rejected",
+			exportName:     "sunset-widgets",
+			expectedErrors: []string{"no new bindings are allowed: widgets.kcp.dev v1: kcp.dev/v1 Widget is deprecated; sunset on "},
+		},
+		{
+			name:       "superseded schema warns",
+			exportName: "widgets",
+			schemas: []*apisv1alpha1.APIResourceSchema{
+				schemas["today.widgets.kcp.dev"],
+				newerWidgetsSchema,
+			},
+			expectedWarns: []string{"APIResourceSchema today.widgets.kcp.dev is superseded by the newer APIResourceSchema tomorrow.widgets.kcp.dev"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &apiBindingAdmission{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kcpkubernetesclientset.ClusterInterface) (authorizer.Authorizer, error) {
+					return &fakeAuthorizer{authorizer.DecisionAllow, nil}, nil
+				},
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					return tc.bindings, nil
+				},
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					require.Equal(t, "root:org:provider", clusterName.String())
+					if export, ok := apiExports[name]; ok {
+						return export, nil
+					}
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+				},
+				getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+					if schema, ok := schemas[name]; ok {
+						return schema, nil
+					}
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiresourceschemas"), name)
+				},
+				getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+					return widgetsCRD(name, "v1"), nil
+				},
+				listAPIResourceSchemas: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIResourceSchema, error) {
+					require.Equal(t, "root:org:provider", clusterName.String())
+					return tc.schemas, nil
+				},
+				listCRDs: func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+					return tc.crds, nil
+				},
+				now: time.Now,
+			}
+
+			apiBinding := newAPIBinding().
+				withName("test").
+				withAbsoluteWorkspaceReference("root:org:provider", tc.exportName).
+				withLabel(apisv1alpha1.InternalAPIBindingExportLabelKey, toSha224Base62("root:org:provider:"+tc.exportName)).APIBinding
+			apiBinding.Spec.PermissionClaims = tc.acceptedClaims
+			attr := admission.NewAttributesRecord(
+				helpers.ToUnstructuredOrDie(apiBinding),
+				nil,
+				apisv1alpha1.Kind("APIBinding").WithVersion("v1alpha1"),
+				"",
+				apiBinding.Name,
+				apisv1alpha1.Resource("apibindings").WithVersion("v1alpha1"),
+				"",
+				admission.Create,
+				&metav1.CreateOptions{},
+				tc.dryRun,
+				&user.DefaultInfo{},
+			)
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org:ws")})
+			recorder := &fakeWarningRecorder{}
+			ctx = warning.WithWarningRecorder(ctx, recorder)
+
+			err := o.Validate(ctx, attr, nil)
+
+			wantErr := len(tc.expectedErrors) > 0
+			require.Equal(t, wantErr, err != nil, "err: %v", err)
+			for _, expected := range tc.expectedErrors {
+				require.Contains(t, err.Error(), expected)
+			}
+			require.Len(t, recorder.warnings, len(tc.expectedWarns))
+			for i, expected := range tc.expectedWarns {
+				require.Contains(t, recorder.warnings[i], expected)
+			}
+		})
+	}
+}
+
+func (b *bindingBuilder) withBoundSchema(group, resource, schemaName string) *bindingBuilder {
+	b.Status.BoundResources = append(b.Status.BoundResources, apisv1alpha1.BoundAPIResource{
+		Group:    group,
+		Resource: resource,
+		Schema:   apisv1alpha1.BoundAPIResourceSchema{Name: schemaName, UID: schemaName},
+	})
+	return b
+}
+
+type fakeWarningRecorder struct {
+	warnings []string
+}
+
+func (r *fakeWarningRecorder) AddWarning(_, text string) {
+	r.warnings = append(r.warnings, text)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kcpapiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/kcp/informers/externalversions"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+)
+
+// SetKcpInformers is an admission plugin initializer function that injects the kcp informers used by the
+// bind compatibility check.
+func (o *apiBindingAdmission) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	apiBindingLister := informers.Apis().V1alpha1().APIBindings().Lister()
+	apiExportLister := informers.Apis().V1alpha1().APIExports().Lister()
+	apiResourceSchemaLister := informers.Apis().V1alpha1().APIResourceSchemas().Lister()
+
+	o.listAPIBindings = func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+		return apiBindingLister.Cluster(clusterName).List(labels.Everything())
+	}
+	o.getAPIExport = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+		return apiExportLister.Cluster(clusterName).Get(name)
+	}
+	o.getAPIResourceSchema = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+		return apiResourceSchemaLister.Cluster(clusterName).Get(name)
+	}
+	o.listAPIResourceSchemas = func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIResourceSchema, error) {
+		return apiResourceSchemaLister.Cluster(clusterName).List(labels.Everything())
+	}
+}
+
+// SetApiExtensionsInformers is an admission plugin initializer function that injects the CRD informer used by
+// the bind compatibility check.
+func (o *apiBindingAdmission) SetApiExtensionsInformers(informers kcpapiextensionsinformers.SharedInformerFactory) {
+	crdLister := informers.Apiextensions().V1().CustomResourceDefinitions().Lister()
+
+	o.getCRD = func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+		return crdLister.Cluster(clusterName).Get(name)
+	}
+	o.listCRDs = func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+		return crdLister.Cluster(clusterName).List(labels.Everything())
+	}
+}
+
+// checkBindCompatibility reports everything that would keep the APIBinding from binding the referenced
+// APIExport in the given workspace: naming conflicts with APIs bound already, and CRDs of the same group and
+// resource, including whether objects of those CRDs are stored in versions the APIExport serves.
+//
+// It runs for dry-run creations only, such that users can check an APIBinding with
+// `kubectl create --dry-run=server` before committing to it.
+func (o *apiBindingAdmission) checkBindCompatibility(clusterName logicalcluster.Name, apiBinding *apisv1alpha1.APIBinding) field.ErrorList {
+	var errs field.ErrorList
+	exportPath := field.NewPath("spec", "reference", "workspace", "exportName")
+
+	apiBinding = apiBinding.DeepCopy()
+	if apiBinding.Annotations == nil {
+		apiBinding.Annotations = map[string]string{}
+	}
+	apiBinding.Annotations[logicalcluster.AnnotationKey] = clusterName.String()
+
+	exportClusterName := logicalcluster.New(apiBinding.Spec.Reference.Workspace.Path)
+	apiExport, err := o.getAPIExport(exportClusterName, apiBinding.Spec.Reference.Workspace.ExportName)
+	if apierrors.IsNotFound(err) {
+		return append(errs, field.NotFound(exportPath, apiBinding.Spec.Reference.Workspace.ExportName))
+	} else if err != nil {
+		return append(errs, field.InternalError(exportPath, err))
+	}
+
+	crds, err := o.listCRDs(clusterName)
+	if err != nil {
+		return append(errs, field.InternalError(exportPath, err))
+	}
+
+	for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
+		schema, err := o.getAPIResourceSchema(exportClusterName, schemaName)
+		if err != nil {
+			errs = append(errs, field.InternalError(exportPath, fmt.Errorf("APIResourceSchema %s: %w", schemaName, err)))
+			continue
+		}
+
+		checker := apibinding.NewConflictChecker(o.listAPIBindings, o.getAPIExport, o.getAPIResourceSchema, o.getCRD, o.listCRDs)
+		if err := checker.CheckForConflicts(schema, apiBinding); err != nil {
+			errs = append(errs, field.Forbidden(exportPath, fmt.Sprintf("APIResourceSchema %s: %v", schemaName, err)))
+		}
+
+		served := sets.NewString()
+		for _, version := range schema.Spec.Versions {
+			if version.Served {
+				served.Insert(version.Name)
+			}
+		}
+		for _, crd := range crds {
+			if crd.Spec.Group != schema.Spec.Group || crd.Spec.Names.Plural != schema.Spec.Names.Plural {
+				continue
+			}
+			if unserved := sets.NewString(crd.Status.StoredVersions...).Difference(served); unserved.Len() > 0 {
+				errs = append(errs, field.Forbidden(exportPath, fmt.Sprintf("objects of CustomResourceDefinition %s are stored in versions %v that APIResourceSchema %s does not serve", crd.Name, unserved.List(), schemaName)))
+			}
+		}
+	}
+
+	return errs
+}
+
+// bindPreview describes what the APIBinding would do when created: the resources it would bind and the CRDs
+// installed for them, and the permission claims of the APIExport with the state the APIBinding gives them.
+// Together with checkBindCompatibility, it lets users preview an APIBinding with a dry-run creation.
+// Lookup errors are ignored here, they are reported by checkBindCompatibility.
+func (o *apiBindingAdmission) bindPreview(apiBinding *apisv1alpha1.APIBinding) []string {
+	exportClusterName := logicalcluster.New(apiBinding.Spec.Reference.Workspace.Path)
+	apiExport, err := o.getAPIExport(exportClusterName, apiBinding.Spec.Reference.Workspace.ExportName)
+	if err != nil {
+		return nil
+	}
+
+	var preview []string
+	for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
+		schema, err := o.getAPIResourceSchema(exportClusterName, schemaName)
+		if err != nil {
+			continue
+		}
+		var served []string
+		for _, version := range schema.Spec.Versions {
+			if version.Served {
+				served = append(served, version.Name)
+			}
+		}
+		preview = append(preview, fmt.Sprintf("would bind %s.%s in versions %v, installing a CustomResourceDefinition from APIResourceSchema %s", schema.Spec.Names.Plural, schema.Spec.Group, served, schemaName))
+	}
+
+	for _, claim := range apiExport.Spec.PermissionClaims {
+		state := "not accepted yet"
+		for _, acceptable := range apiBinding.Spec.PermissionClaims {
+			if acceptable.Equal(claim) {
+				state = strings.ToLower(string(acceptable.State))
+				break
+			}
+		}
+		scope := "all objects"
+		if !claim.All {
+			scope = "objects matching its resource selectors"
+		}
+		preview = append(preview, fmt.Sprintf("APIExport %s claims permissions on %s (%s): %s", apiExport.Name, claim.String(), scope, state))
+	}
+
+	return preview
+}
+
+// outdatedSchemaWarnings returns warnings for the APIResourceSchemas of the referenced APIExport that are
+// deprecated or superseded by newer ones, such that consumers know they start from an old API. Lookup errors
+// are ignored here, they surface on the APIBinding conditions.
+func (o *apiBindingAdmission) outdatedSchemaWarnings(apiBinding *apisv1alpha1.APIBinding) []string {
+	exportClusterName := logicalcluster.New(apiBinding.Spec.Reference.Workspace.Path)
+	apiExport, err := o.getAPIExport(exportClusterName, apiBinding.Spec.Reference.Workspace.ExportName)
+	if err != nil {
+		return nil
+	}
+	available, err := o.listAPIResourceSchemas(exportClusterName)
+	if err != nil {
+		return nil
+	}
+
+	var warnings []string
+	for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
+		schema, err := o.getAPIResourceSchema(exportClusterName, schemaName)
+		if err != nil {
+			continue
+		}
+		warnings = append(warnings, apibinding.OutdatedSchemaWarnings(schema, available)...)
+	}
+	return warnings
+}
+
+// sunsetVersions returns the served versions of the APIResourceSchemas of the referenced APIExport whose
+// sunset time has passed, together with their deprecation warning. Lookup errors are ignored here, they
+// surface on the APIBinding conditions.
+func (o *apiBindingAdmission) sunsetVersions(apiBinding *apisv1alpha1.APIBinding) []string {
+	exportClusterName := logicalcluster.New(apiBinding.Spec.Reference.Workspace.Path)
+	apiExport, err := o.getAPIExport(exportClusterName, apiBinding.Spec.Reference.Workspace.ExportName)
+	if err != nil {
+		return nil
+	}
+
+	var sunset []string
+	for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
+		schema, err := o.getAPIResourceSchema(exportClusterName, schemaName)
+		if err != nil {
+			continue
+		}
+		for _, version := range schema.Spec.Versions {
+			if !version.Served || version.SunsetTime == nil || o.now().Before(version.SunsetTime.Time) {
+				continue
+			}
+			sunset = append(sunset, fmt.Sprintf("%s.%s %s: %s", schema.Spec.Names.Plural, schema.Spec.Group, version.Name, version.DeprecationWarningFor(schema.Spec.Group, schema.Spec.Names.Kind)))
+		}
+	}
+	return sunset
+}
@@ -19,9 +19,11 @@ package apibinding
 import (
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reference"
 )
 
 // ValidateAPIBinding validates an APIBinding.
@@ -30,6 +32,12 @@ func ValidateAPIBinding(apiBinding *apisv1alpha1.APIBinding) field.ErrorList {
 
 	allErrs = append(allErrs, ValidateAPIBindingReference(apiBinding.Spec.Reference, field.NewPath("spec", "reference"))...)
 
+	for i, ns := range apiBinding.Spec.Namespaces {
+		for _, msg := range validation.IsDNS1123Label(ns) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "namespaces").Index(i), ns, msg))
+		}
+	}
+
 	return allErrs
 }
 
@@ -52,14 +60,16 @@ func ValidateAPIBindingUpdate(oldBinding, newBinding *apisv1alpha1.APIBinding) f
 }
 
 // ValidateAPIBindingReference validates an APIBinding's ExportReference.
-func ValidateAPIBindingReference(reference apisv1alpha1.ExportReference, path *field.Path) field.ErrorList {
+func ValidateAPIBindingReference(ref apisv1alpha1.ExportReference, path *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
 	// For now, workspace is required via OpenAPI. But just in case...
-	if workspace := reference.Workspace; workspace != nil {
+	if workspace := ref.Workspace; workspace != nil {
 		// These are required by OpenAPI, but just in case...
 		if workspace.Path == "" {
 			allErrs = append(allErrs, field.Required(path.Child("workspace").Child("path"), ""))
+		} else {
+			allErrs = append(allErrs, reference.ValidateWorkspacePath(workspace.Path, path.Child("workspace").Child("path"))...)
 		}
 
 		if workspace.ExportName == "" {
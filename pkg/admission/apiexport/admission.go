@@ -21,11 +21,16 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/kcp-dev/logicalcluster/v2"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 
+	kcpinitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
 	"github.com/kcp-dev/kcp/pkg/apis/apis"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	builtinapiexport "github.com/kcp-dev/kcp/pkg/virtual/apiexport/schemas/builtin"
@@ -47,6 +52,8 @@ type APIExportAdmission struct {
 	*admission.Handler
 
 	isBuiltIn func(apisv1alpha1.GroupResource) bool
+
+	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
 }
 
 // NewAPIExportAdmission constructs a new APIExportAdmission admission plugin.
@@ -58,7 +65,11 @@ func NewAPIExportAdmission(isBuiltIn func(apisv1alpha1.GroupResource) bool) *API
 }
 
 // Ensure that the required admission interfaces are implemented.
-var _ = admission.ValidationInterface(&APIExportAdmission{})
+var (
+	_ = admission.ValidationInterface(&APIExportAdmission{})
+	_ = admission.InitializationValidator(&APIExportAdmission{})
+	_ = kcpinitializers.WantsKcpInformers(&APIExportAdmission{})
+)
 
 // Validate ensures that the APIExport is valid.
 func (e *APIExportAdmission) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) (err error) {
@@ -79,6 +90,15 @@ func (e *APIExportAdmission) Validate(ctx context.Context, a admission.Attribute
 		return fmt.Errorf("failed to convert unstructured to APIExport: %w", err)
 	}
 
+	if ae.Spec.Identity != nil && ae.Spec.Identity.SecretRef != nil && ae.Spec.Identity.ProviderRef != nil {
+		return admission.NewForbidden(a,
+			field.Invalid(
+				field.NewPath("spec").
+					Child("identity"),
+				"",
+				"secretRef and providerRef are mutually exclusive"))
+	}
+
 	for i, pc := range ae.Spec.PermissionClaims {
 		if pc.IdentityHash == "" && !e.isBuiltIn(pc.GroupResource) && pc.Group != apis.GroupName {
 			return admission.NewForbidden(a,
@@ -92,5 +112,36 @@ func (e *APIExportAdmission) Validate(ctx context.Context, a admission.Attribute
 		}
 	}
 
+	if a.GetOperation() == admission.Update {
+		if _, allowed := ae.Annotations[apisv1alpha1.AnnotationAllowIncompatibleSchemaChangesKey]; allowed {
+			return nil
+		}
+
+		u, ok := a.GetOldObject().(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected type %T", a.GetOldObject())
+		}
+		old := &apisv1alpha1.APIExport{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, old); err != nil {
+			return fmt.Errorf("failed to convert unstructured to APIExport: %w", err)
+		}
+
+		if sets.NewString(old.Spec.LatestResourceSchemas...).IsSuperset(sets.NewString(ae.Spec.LatestResourceSchemas...)) {
+			return nil
+		}
+
+		cluster, err := genericapirequest.ValidClusterFrom(ctx)
+		if err != nil {
+			return err
+		}
+		errs, err := e.validateSchemaEvolution(cluster.Name, old, ae)
+		if err != nil {
+			return admission.NewForbidden(a, err)
+		}
+		if len(errs) > 0 {
+			return admission.NewForbidden(a, fmt.Errorf("incompatible APIResourceSchema changes, set the %s annotation to allow them: %w", apisv1alpha1.AnnotationAllowIncompatibleSchemaChangesKey, errs.ToAggregate()))
+		}
+	}
+
 	return nil
 }
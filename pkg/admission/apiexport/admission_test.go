@@ -22,6 +22,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -73,6 +74,7 @@ func TestAdmission(t *testing.T) {
 		hasIdentity bool
 		isBuiltIn   bool
 		modifyPCs   func([]apisv1alpha1.PermissionClaim) []apisv1alpha1.PermissionClaim
+		identity    *apisv1alpha1.Identity
 		want        error
 	}{
 		"NotAPIExportKind": {
@@ -179,6 +181,28 @@ func TestAdmission(t *testing.T) {
 			hasIdentity: true,
 			isBuiltIn:   false,
 		},
+		"ValidIdentityProviderRef": {
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			identity: &apisv1alpha1.Identity{
+				ProviderRef: &apisv1alpha1.IdentityProviderReference{Provider: "vault", Key: "cool-something"},
+			},
+		},
+		"ForbiddenIdentitySecretRefAndProviderRef": {
+			kind:        "APIExport",
+			resource:    "apiexports",
+			hasIdentity: true,
+			identity: &apisv1alpha1.Identity{
+				SecretRef:   &corev1.SecretReference{Namespace: "kcp-system", Name: "cool-something"},
+				ProviderRef: &apisv1alpha1.IdentityProviderReference{Provider: "vault", Key: "cool-something"},
+			},
+			want: field.Invalid(
+				field.NewPath("spec").
+					Child("identity"),
+				"",
+				"secretRef and providerRef are mutually exclusive"),
+		},
 		"ValidNoPermissionClaims": {
 			kind:     "APIExport",
 			resource: "apiexports",
@@ -210,6 +234,7 @@ func TestAdmission(t *testing.T) {
 			if tc.modifyPCs != nil {
 				ae.Spec.PermissionClaims = tc.modifyPCs(ae.Spec.PermissionClaims)
 			}
+			ae.Spec.Identity = tc.identity
 			var attr admission.Attributes
 			if tc.update {
 				attr = updateAttr("cool-something", ae, tc.kind, tc.resource)
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexport
+
+import (
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/schemacompat"
+)
+
+// SetKcpInformers is an admission plugin initializer function that injects the kcp informers used by the
+// schema evolution check.
+func (e *APIExportAdmission) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	apiResourceSchemaLister := informers.Apis().V1alpha1().APIResourceSchemas().Lister()
+	e.getAPIResourceSchema = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+		return apiResourceSchemaLister.Cluster(clusterName).Get(name)
+	}
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (e *APIExportAdmission) ValidateInitialization() error {
+	if e.getAPIResourceSchema == nil {
+		return fmt.Errorf(PluginName + " plugin needs kcp informers")
+	}
+	return nil
+}
+
+// validateSchemaEvolution checks that every APIResourceSchema replacing another one for the same group and
+// resource in spec.latestResourceSchemas is a compatible evolution of it: no served version is removed, the scope
+// is unchanged, and the schema of every version still served accepts all objects the old schema accepted, i.e.
+// no fields are removed and no types are changed.
+//
+// Schemas that cannot be found are skipped. The APIBinding controller reports them as invalid.
+func (e *APIExportAdmission) validateSchemaEvolution(clusterName logicalcluster.Name, oldExport, newExport *apisv1alpha1.APIExport) (field.ErrorList, error) {
+	oldNames := map[string]bool{}
+	oldSchemas := map[schema.GroupResource]*apisv1alpha1.APIResourceSchema{}
+	for _, name := range oldExport.Spec.LatestResourceSchemas {
+		oldNames[name] = true
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "latestResourceSchemas")
+	for i, name := range newExport.Spec.LatestResourceSchemas {
+		if oldNames[name] {
+			continue
+		}
+		if len(oldSchemas) == 0 {
+			// lazily look up the old schemas, only if anything has changed
+			for oldName := range oldNames {
+				old, err := e.getAPIResourceSchema(clusterName, oldName)
+				if apierrors.IsNotFound(err) {
+					continue
+				} else if err != nil {
+					return nil, err
+				}
+				oldSchemas[schema.GroupResource{Group: old.Spec.Group, Resource: old.Spec.Names.Plural}] = old
+			}
+		}
+
+		newSchema, err := e.getAPIResourceSchema(clusterName, name)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		old, found := oldSchemas[schema.GroupResource{Group: newSchema.Spec.Group, Resource: newSchema.Spec.Names.Plural}]
+		if !found {
+			continue
+		}
+		allErrs = append(allErrs, schemaEvolutionErrors(fldPath.Index(i), old, newSchema)...)
+	}
+
+	return allErrs, nil
+}
+
+// schemaEvolutionErrors returns the incompatible changes from the old to the new APIResourceSchema.
+func schemaEvolutionErrors(fldPath *field.Path, old, new *apisv1alpha1.APIResourceSchema) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if old.Spec.Scope != new.Spec.Scope {
+		allErrs = append(allErrs, field.Invalid(fldPath, new.Name, fmt.Sprintf("scope changed from %s to %s compared to APIResourceSchema %s", old.Spec.Scope, new.Spec.Scope, old.Name)))
+	}
+
+	for i := range old.Spec.Versions {
+		oldVersion := &old.Spec.Versions[i]
+		if !oldVersion.Served {
+			continue
+		}
+
+		var newVersion *apisv1alpha1.APIResourceVersion
+		for j := range new.Spec.Versions {
+			if new.Spec.Versions[j].Name == oldVersion.Name {
+				newVersion = &new.Spec.Versions[j]
+				break
+			}
+		}
+		if newVersion == nil || !newVersion.Served {
+			allErrs = append(allErrs, field.Invalid(fldPath, new.Name, fmt.Sprintf("version %s served by APIResourceSchema %s is not served anymore", oldVersion.Name, old.Name)))
+			continue
+		}
+
+		oldSchema, err := oldVersion.GetSchema()
+		if err != nil {
+			allErrs = append(allErrs, field.InternalError(fldPath, err))
+			continue
+		}
+		newSchema, err := newVersion.GetSchema()
+		if err != nil {
+			allErrs = append(allErrs, field.InternalError(fldPath, err))
+			continue
+		}
+		if oldSchema == nil || newSchema == nil {
+			continue
+		}
+		if _, err := schemacompat.EnsureStructuralSchemaCompatibility(field.NewPath(oldVersion.Name), oldSchema, newSchema, false); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath, new.Name, fmt.Sprintf("incompatible with APIResourceSchema %s: %v", old.Name, err)))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func widgetSchema(t *testing.T, name string, scope apiextensionsv1.ResourceScope, versions map[string]map[string]string) *apisv1alpha1.APIResourceSchema {
+	t.Helper()
+
+	s := &apisv1alpha1.APIResourceSchema{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: apisv1alpha1.APIResourceSchemaSpec{
+			Group: "example.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+			Scope: scope,
+		},
+	}
+	for version, fields := range versions {
+		props := map[string]apiextensionsv1.JSONSchemaProps{}
+		for fieldName, fieldType := range fields {
+			props[fieldName] = apiextensionsv1.JSONSchemaProps{Type: fieldType}
+		}
+		v := apisv1alpha1.APIResourceVersion{Name: version, Served: true, Storage: true}
+		require.NoError(t, v.SetSchema(&apiextensionsv1.JSONSchemaProps{
+			Type:       "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{"spec": {Type: "object", Properties: props}},
+		}))
+		s.Spec.Versions = append(s.Spec.Versions, v)
+	}
+	return s
+}
+
+func TestSchemaEvolutionErrors(t *testing.T) {
+	old := widgetSchema(t, "v1.widgets.example.io", apiextensionsv1.NamespaceScoped, map[string]map[string]string{"v1": {"size": "integer", "color": "string"}})
+
+	tests := map[string]struct {
+		new     *apisv1alpha1.APIResourceSchema
+		wantErr string
+	}{
+		"added field": {
+			new: widgetSchema(t, "v2.widgets.example.io", apiextensionsv1.NamespaceScoped, map[string]map[string]string{"v1": {"size": "integer", "color": "string", "shape": "string"}}),
+		},
+		"added version": {
+			new: widgetSchema(t, "v2.widgets.example.io", apiextensionsv1.NamespaceScoped, map[string]map[string]string{"v1": {"size": "integer", "color": "string"}, "v2": {"size": "integer"}}),
+		},
+		"removed field": {
+			new:     widgetSchema(t, "v2.widgets.example.io", apiextensionsv1.NamespaceScoped, map[string]map[string]string{"v1": {"size": "integer"}}),
+			wantErr: "incompatible with APIResourceSchema v1.widgets.example.io",
+		},
+		"changed field type": {
+			new:     widgetSchema(t, "v2.widgets.example.io", apiextensionsv1.NamespaceScoped, map[string]map[string]string{"v1": {"size": "string", "color": "string"}}),
+			wantErr: "incompatible with APIResourceSchema v1.widgets.example.io",
+		},
+		"removed version": {
+			new:     widgetSchema(t, "v2.widgets.example.io", apiextensionsv1.NamespaceScoped, map[string]map[string]string{"v2": {"size": "integer", "color": "string"}}),
+			wantErr: "version v1 served by APIResourceSchema v1.widgets.example.io is not served anymore",
+		},
+		"changed scope": {
+			new:     widgetSchema(t, "v2.widgets.example.io", apiextensionsv1.ClusterScoped, map[string]map[string]string{"v1": {"size": "integer", "color": "string"}}),
+			wantErr: "scope changed from Namespaced to Cluster",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := schemaEvolutionErrors(field.NewPath("spec", "latestResourceSchemas").Index(0), old, tc.new)
+			if tc.wantErr == "" {
+				require.Empty(t, errs)
+				return
+			}
+			require.NotEmpty(t, errs)
+			require.Contains(t, errs.ToAggregate().Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestValidateSchemaEvolution(t *testing.T) {
+	schemas := map[string]*apisv1alpha1.APIResourceSchema{}
+	for _, s := range []*apisv1alpha1.APIResourceSchema{
+		widgetSchema(t, "v1.widgets.example.io", apiextensionsv1.NamespaceScoped, map[string]map[string]string{"v1": {"size": "integer", "color": "string"}}),
+		widgetSchema(t, "v2.widgets.example.io", apiextensionsv1.NamespaceScoped, map[string]map[string]string{"v1": {"size": "integer", "color": "string", "shape": "string"}}),
+		widgetSchema(t, "v3.widgets.example.io", apiextensionsv1.NamespaceScoped, map[string]map[string]string{"v1": {"size": "integer"}}),
+	} {
+		schemas[s.Name] = s
+	}
+
+	plugin := NewAPIExportAdmission(func(apisv1alpha1.GroupResource) bool { return false })
+	plugin.getAPIResourceSchema = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+		require.Equal(t, "root:org:ws", clusterName.String())
+		if s, ok := schemas[name]; ok {
+			return s, nil
+		}
+		return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiresourceschemas"), name)
+	}
+
+	export := func(schema string, annotations map[string]string) *apisv1alpha1.APIExport {
+		return &apisv1alpha1.APIExport{
+			ObjectMeta: metav1.ObjectMeta{Name: "widgets", Annotations: annotations},
+			Spec:       apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{schema}},
+		}
+	}
+	validate := func(old, new *apisv1alpha1.APIExport) error {
+		attr := admission.NewAttributesRecord(
+			helpers.ToUnstructuredOrDie(new),
+			helpers.ToUnstructuredOrDie(old),
+			apisv1alpha1.Kind("APIExport").WithVersion("v1alpha1"),
+			"",
+			new.Name,
+			apisv1alpha1.Resource("apiexports").WithVersion("v1alpha1"),
+			"",
+			admission.Update,
+			&metav1.UpdateOptions{},
+			false,
+			&user.DefaultInfo{},
+		)
+		ctx := genericapirequest.WithCluster(context.Background(), genericapirequest.Cluster{Name: logicalcluster.New("root:org:ws")})
+		return plugin.Validate(ctx, attr, nil)
+	}
+
+	require.NoError(t, validate(export("v1.widgets.example.io", nil), export("v2.widgets.example.io", nil)), "adding a field is compatible")
+	require.NoError(t, validate(export("v1.widgets.example.io", nil), export("unknown.widgets.example.io", nil)), "unknown schemas are skipped")
+
+	err := validate(export("v1.widgets.example.io", nil), export("v3.widgets.example.io", nil))
+	require.Error(t, err, "removing a field is incompatible")
+	require.True(t, apierrors.IsForbidden(err))
+
+	require.NoError(t, validate(
+		export("v1.widgets.example.io", nil),
+		export("v3.widgets.example.io", map[string]string{apisv1alpha1.AnnotationAllowIncompatibleSchemaChangesKey: "true"}),
+	), "incompatible changes are allowed with the annotation")
+}
@@ -18,15 +18,20 @@ package apiresourceschema
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/endpoints/request"
+	genericfeatures "k8s.io/apiserver/pkg/features"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	"sigs.k8s.io/yaml"
 
 	"github.com/kcp-dev/kcp/pkg/admission/helpers"
@@ -163,6 +168,17 @@ spec:
 				"spec.group: Invalid value: \"core\": must be empty string for the core group",
 			},
 		},
+		{
+			name: "an APIResourceSchema with validation rules can pass admission",
+			attr: createAttr(validationRulesSchema("self.minReplicas <= self.maxReplicas")),
+		},
+		{
+			name: "validation rules must compile",
+			attr: createAttr(validationRulesSchema("self.minReplicas <= self.unknown")),
+			expectedErrors: []string{
+				"spec.versions[0].schema.openAPIV3Schema.properties[spec].x-kubernetes-validations[0].rule: Invalid value",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -187,6 +203,52 @@ spec:
 	}
 }
 
+func TestValidateValidationRulesFeatureGate(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, genericfeatures.CustomResourceValidationExpressions, false)()
+
+	o := &apiResourceSchemaValidation{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+	}
+	ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org")})
+	err := o.Validate(ctx, createAttr(validationRulesSchema("self.minReplicas <= self.maxReplicas")), nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "spec.versions[0].schema.openAPIV3Schema: Forbidden: x-kubernetes-validations require the CustomResourceValidationExpressions feature gate")
+}
+
+func validationRulesSchema(rule string) *apisv1alpha1.APIResourceSchema {
+	return unmarshalOrDie(fmt.Sprintf(`
+apiVersion: apis.kcp.sh/v1alpha1
+kind: APIResourceSchema
+metadata:
+  name: july.cowboys.wild.west
+spec:
+  group: wild.west
+  names:
+    plural: cowboys
+    singular: cowboy
+    kind: Cowboy
+    listKind: CowboyList
+  scope: Cluster
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      type: object
+      properties:
+        spec:
+          type: object
+          properties:
+            minReplicas:
+              type: integer
+            maxReplicas:
+              type: integer
+          x-kubernetes-validations:
+          - rule: %q
+            message: minReplicas must not be greater than maxReplicas
+`, rule))
+}
+
 func unmarshalOrDie(yml string) *apisv1alpha1.APIResourceSchema {
 	s := apisv1alpha1.APIResourceSchema{}
 	if err := yaml.Unmarshal([]byte(strings.ReplaceAll(yml, "\t", "    ")), &s); err != nil {
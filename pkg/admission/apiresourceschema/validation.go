@@ -32,8 +32,11 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericfeatures "k8s.io/apiserver/pkg/features"
+	"k8s.io/apiserver/pkg/util/webhook"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 )
 
 var (
@@ -156,8 +159,71 @@ func ValidateAPIResourceSchemaSpec(ctx context.Context, spec *apisv1alpha1.APIRe
 		allErrs = append(allErrs, crdvalidation.ValidateCustomResourceDefinitionNames(&crdNames, fldPath.Child("names"))...)
 	}
 
+	allErrs = append(allErrs, ValidateAPIResourceSchemaConversion(spec.Conversion, fldPath.Child("conversion"))...)
+
 	// TODO(sttts): validate predecessors
-	// TODO(sttts): validate conversions
+
+	return allErrs
+}
+
+var acceptedConversionReviewVersions = sets.NewString(apiextensionsv1.SchemeGroupVersion.Version, apiextensionsv1beta1.SchemeGroupVersion.Version)
+
+// ValidateAPIResourceSchemaConversion validates the conversion of an APIResourceSchema. In contrast to CRDs,
+// webhooks cannot be referenced by service because the bound CRDs live in a system workspace.
+func ValidateAPIResourceSchemaConversion(conversion *apiextensionsv1.CustomResourceConversion, fldPath *field.Path) field.ErrorList {
+	if conversion == nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+
+	switch conversion.Strategy {
+	case apiextensionsv1.NoneConverter:
+		if conversion.Webhook != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("webhook"), "should not be set when strategy is not set to Webhook"))
+		}
+	case apiextensionsv1.WebhookConverter:
+		if conversion.Webhook == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("webhook"), "required when strategy is set to Webhook"))
+			break
+		}
+
+		webhookPath := fldPath.Child("webhook")
+		if conversion.Webhook.ClientConfig == nil {
+			allErrs = append(allErrs, field.Required(webhookPath.Child("clientConfig"), "required when strategy is set to Webhook"))
+		} else {
+			clientConfig := conversion.Webhook.ClientConfig
+			if clientConfig.Service != nil {
+				allErrs = append(allErrs, field.Forbidden(webhookPath.Child("clientConfig", "service"), "is not supported for APIResourceSchemas, use url instead"))
+			}
+			if clientConfig.URL == nil {
+				allErrs = append(allErrs, field.Required(webhookPath.Child("clientConfig", "url"), ""))
+			} else {
+				allErrs = append(allErrs, webhook.ValidateWebhookURL(webhookPath.Child("clientConfig", "url"), *clientConfig.URL, true)...)
+			}
+		}
+
+		versionsPath := webhookPath.Child("conversionReviewVersions")
+		if len(conversion.Webhook.ConversionReviewVersions) == 0 {
+			allErrs = append(allErrs, field.Required(versionsPath, fmt.Sprintf("must include at least one of %v", strings.Join(acceptedConversionReviewVersions.List(), ", "))))
+		} else {
+			seen := sets.NewString()
+			for i, v := range conversion.Webhook.ConversionReviewVersions {
+				if seen.Has(v) {
+					allErrs = append(allErrs, field.Invalid(versionsPath.Index(i), v, "duplicate version"))
+				}
+				seen.Insert(v)
+				for _, msg := range utilvalidation.IsDNS1035Label(v) {
+					allErrs = append(allErrs, field.Invalid(versionsPath.Index(i), v, msg))
+				}
+			}
+			if !seen.HasAny(acceptedConversionReviewVersions.List()...) {
+				allErrs = append(allErrs, field.Invalid(versionsPath, conversion.Webhook.ConversionReviewVersions, fmt.Sprintf("must include at least one of %v", strings.Join(acceptedConversionReviewVersions.List(), ", "))))
+			}
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("strategy"), conversion.Strategy, []string{string(apiextensionsv1.NoneConverter), string(apiextensionsv1.WebhookConverter)}))
+	}
 
 	return allErrs
 }
@@ -184,6 +250,17 @@ func ValidateAPIResourceVersion(ctx context.Context, version *apisv1alpha1.APIRe
 	for _, err := range crdvalidation.ValidateDeprecationWarning(version.Deprecated, version.DeprecationWarning) {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("deprecationWarning"), version.DeprecationWarning, err))
 	}
+	if version.SunsetTime != nil {
+		if !version.Deprecated {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sunsetTime"), version.SunsetTime, "can only be set for deprecated versions"))
+		} else if version.DeprecationWarning != nil && len(*version.DeprecationWarning) > 0 {
+			// the sunset time is appended to the warning returned to clients, which must stay valid
+			warning := version.DeprecationWarningFor("", "")
+			for _, err := range crdvalidation.ValidateDeprecationWarning(true, &warning) {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("deprecationWarning"), version.DeprecationWarning, fmt.Sprintf("together with the sunset time: %s", err)))
+			}
+		}
+	}
 
 	if len(version.Schema.Raw) == 0 || string(version.Schema.Raw) == "null" {
 		allErrs = append(allErrs, field.Required(fldPath.Child("schema"), "schemas are required"))
@@ -197,6 +274,12 @@ func ValidateAPIResourceVersion(ctx context.Context, version *apisv1alpha1.APIRe
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("schema"), string(version.Schema.Raw), fmt.Sprintf("invalid schema: %v", err)))
 		} else {
 			allErrs = append(allErrs, crdvalidation.ValidateCustomResourceDefinitionValidation(ctx, &crdSchemaInternal, statusEnabled, defaultValidationOpts, fldPath.Child("schema"))...)
+
+			// bound CRDs drop x-kubernetes-validations when the feature gate is disabled. Fail early instead of
+			// silently serving the resource without the rules.
+			if !kcpfeatures.DefaultFeatureGate.Enabled(genericfeatures.CustomResourceValidationExpressions) && hasValidationRules(crdSchemaInternal.OpenAPIV3Schema) {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("schema", "openAPIV3Schema"), fmt.Sprintf("x-kubernetes-validations require the %s feature gate", genericfeatures.CustomResourceValidationExpressions)))
+			}
 		}
 	}
 
@@ -219,6 +302,16 @@ func ValidateAPIResourceVersion(ctx context.Context, version *apisv1alpha1.APIRe
 	return allErrs
 }
 
+// hasValidationRules returns true if the schema or any of its sub-schemas has x-kubernetes-validations.
+func hasValidationRules(schema *apiextensionsinternal.JSONSchemaProps) bool {
+	if schema == nil {
+		return false
+	}
+	return crdvalidation.SchemaHas(schema, func(s *apiextensionsinternal.JSONSchemaProps) bool {
+		return len(s.XValidations) > 0
+	})
+}
+
 // ValidateAPIResourceSchemaUpdate validates an APIResourceSchema on update.
 func ValidateAPIResourceSchemaUpdate(ctx context.Context, s, old *apisv1alpha1.APIResourceSchema) field.ErrorList {
 	allErrs := ValidateAPIResourceSchema(ctx, s)
@@ -17,8 +17,21 @@ limitations under the License.
 package apiresourceschema
 
 import (
+	"context"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 )
 
 func TestValidationOptionDrift(t *testing.T) {
@@ -45,3 +58,156 @@ func TestValidationOptionDrift(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateAPIResourceSchemaConversion(t *testing.T) {
+	tests := []struct {
+		name       string
+		conversion *apiextensionsv1.CustomResourceConversion
+		wantErrs   []string
+	}{
+		{
+			name: "no conversion",
+		},
+		{
+			name:       "none",
+			conversion: &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter},
+		},
+		{
+			name: "none with webhook",
+			conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.NoneConverter,
+				Webhook:  &apiextensionsv1.WebhookConversion{},
+			},
+			wantErrs: []string{"spec.conversion.webhook: Forbidden: should not be set when strategy is not set to Webhook"},
+		},
+		{
+			name: "webhook with url",
+			conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig:             &apiextensionsv1.WebhookClientConfig{URL: pointer.String("https://example.com/convert")},
+					ConversionReviewVersions: []string{"v2", "v1"},
+				},
+			},
+		},
+		{
+			name:       "webhook without webhook",
+			conversion: &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.WebhookConverter},
+			wantErrs:   []string{"spec.conversion.webhook: Required value: required when strategy is set to Webhook"},
+		},
+		{
+			name: "webhook with service",
+			conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig: &apiextensionsv1.WebhookClientConfig{
+						Service: &apiextensionsv1.ServiceReference{Namespace: "default", Name: "converter"},
+					},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			},
+			wantErrs: []string{
+				"spec.conversion.webhook.clientConfig.service: Forbidden: is not supported for APIResourceSchemas, use url instead",
+				"spec.conversion.webhook.clientConfig.url: Required value",
+			},
+		},
+		{
+			name: "webhook with http url",
+			conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig:             &apiextensionsv1.WebhookClientConfig{URL: pointer.String("http://example.com/convert")},
+					ConversionReviewVersions: []string{"v1"},
+				},
+			},
+			wantErrs: []string{`spec.conversion.webhook.clientConfig.url: Invalid value: "http": 'https' is the only allowed URL scheme`},
+		},
+		{
+			name: "webhook without known review version",
+			conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook: &apiextensionsv1.WebhookConversion{
+					ClientConfig:             &apiextensionsv1.WebhookClientConfig{URL: pointer.String("https://example.com/convert")},
+					ConversionReviewVersions: []string{"v2", "v2"},
+				},
+			},
+			wantErrs: []string{
+				`spec.conversion.webhook.conversionReviewVersions[1]: Invalid value: "v2": duplicate version`,
+				`spec.conversion.webhook.conversionReviewVersions: Invalid value: []string{"v2", "v2"}: must include at least one of v1, v1beta1`,
+			},
+		},
+		{
+			name:       "unknown strategy",
+			conversion: &apiextensionsv1.CustomResourceConversion{Strategy: "Magic"},
+			wantErrs:   []string{`spec.conversion.strategy: Unsupported value: "Magic": supported values: "None", "Webhook"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateAPIResourceSchemaConversion(tt.conversion, field.NewPath("spec", "conversion"))
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Error())
+			}
+			require.Equal(t, tt.wantErrs, got)
+		})
+	}
+}
+
+func TestValidateAPIResourceVersionSunset(t *testing.T) {
+	sunset := &metav1.Time{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name       string
+		deprecated bool
+		warning    *string
+		sunset     *metav1.Time
+		wantErrs   []string
+	}{
+		{
+			name:       "deprecated with sunset",
+			deprecated: true,
+			sunset:     sunset,
+		},
+		{
+			name:       "deprecated with warning and sunset",
+			deprecated: true,
+			warning:    pointer.String("use v2"),
+			sunset:     sunset,
+		},
+		{
+			name:     "sunset without deprecation",
+			sunset:   sunset,
+			wantErrs: []string{"spec.versions[0].sunsetTime: Invalid value", "can only be set for deprecated versions"},
+		},
+		{
+			name:       "warning too long together with sunset",
+			deprecated: true,
+			warning:    pointer.String(strings.Repeat("x", 240)),
+			sunset:     sunset,
+			wantErrs:   []string{"spec.versions[0].deprecationWarning", "together with the sunset time"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version := &apisv1alpha1.APIResourceVersion{
+				Name:               "v1",
+				Served:             true,
+				Storage:            true,
+				Deprecated:         tt.deprecated,
+				DeprecationWarning: tt.warning,
+				SunsetTime:         tt.sunset,
+				Schema:             runtime.RawExtension{Raw: []byte(`{"type":"object"}`)},
+			}
+			errs := ValidateAPIResourceVersion(context.Background(), version, field.NewPath("spec", "versions").Index(0))
+			if len(tt.wantErrs) == 0 {
+				require.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, 1, "errs: %v", errs)
+			for _, want := range tt.wantErrs {
+				require.Contains(t, errs[0].Error(), want)
+			}
+		})
+	}
+}
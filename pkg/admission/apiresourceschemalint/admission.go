@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresourceschemalint
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+const (
+	PluginName = "apis.kcp.dev/APIResourceSchemaLint"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &apiResourceSchemaLint{
+				// APIResourceSchema specs are immutable, hence linting on creation is enough.
+				Handler: admission.NewHandler(admission.Create),
+			}, nil
+		})
+}
+
+// apiResourceSchemaLint rejects APIResourceSchemas that are valid on their own, but would
+// produce bound CRDs that cannot be used as intended, e.g. because objects would be pruned
+// or could never be created.
+type apiResourceSchemaLint struct {
+	*admission.Handler
+}
+
+// Ensure that the required admission interfaces are implemented.
+var _ = admission.ValidationInterface(&apiResourceSchemaLint{})
+
+// Validate lints an APIResourceSchema on create.
+func (o *apiResourceSchemaLint) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) (err error) {
+	if a.GetResource().GroupResource() != apisv1alpha1.Resource("apiresourceschemas") {
+		return nil
+	}
+
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	schema := &apisv1alpha1.APIResourceSchema{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, schema); err != nil {
+		return fmt.Errorf("failed to convert unstructured to APIResourceSchema: %w", err)
+	}
+
+	if errs := LintAPIResourceSchema(schema); len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresourceschemalint
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+// LintAPIResourceSchema returns the findings for the versions of the given APIResourceSchema. Schemas that
+// cannot be parsed are skipped, they are rejected by the APIResourceSchema validation.
+func LintAPIResourceSchema(s *apisv1alpha1.APIResourceSchema) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i := range s.Spec.Versions {
+		allErrs = append(allErrs, LintAPIResourceVersion(&s.Spec.Versions[i], field.NewPath("spec", "versions").Index(i))...)
+	}
+	return allErrs
+}
+
+// LintAPIResourceVersion returns the findings for the schema and subresources of the given version:
+//
+//   - pruning must not be disabled for the whole object,
+//   - spec and status must be objects,
+//   - with the status subresource, status must be in the schema, and must not be required
+//     because it is dropped on create,
+//   - the scale subresource paths must point to fields of the right type.
+func LintAPIResourceVersion(version *apisv1alpha1.APIResourceVersion, fldPath *field.Path) field.ErrorList {
+	if len(version.Schema.Raw) == 0 || string(version.Schema.Raw) == "null" {
+		return nil
+	}
+	var schema apiextensionsv1.JSONSchemaProps
+	if err := json.Unmarshal(version.Schema.Raw, &schema); err != nil {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	schemaPath := fldPath.Child("schema")
+
+	if preservesUnknownFields(&schema) {
+		allErrs = append(allErrs, field.Forbidden(schemaPath.Child("x-kubernetes-preserve-unknown-fields"), "disables pruning of the whole object, including spec and status; set it on the fields that need it instead"))
+	}
+
+	for _, name := range []string{"spec", "status"} {
+		if prop, found := schema.Properties[name]; found && prop.Type != "" && prop.Type != "object" {
+			allErrs = append(allErrs, field.Invalid(schemaPath.Child("properties").Key(name).Child("type"), prop.Type, "must be object"))
+		}
+	}
+
+	if version.Subresources.Status != nil {
+		if _, found := schema.Properties["status"]; !found && !preservesUnknownFields(&schema) {
+			allErrs = append(allErrs, field.Required(schemaPath.Child("properties").Key("status"), "must be specified with the status subresource, otherwise status updates are pruned"))
+		}
+		for i, name := range schema.Required {
+			if name == "status" {
+				allErrs = append(allErrs, field.Invalid(schemaPath.Child("required").Index(i), name, "must not be required with the status subresource, because status is dropped on create"))
+			}
+		}
+	}
+
+	if scale := version.Subresources.Scale; scale != nil {
+		scalePath := fldPath.Child("subresources", "scale")
+		allErrs = append(allErrs, lintScalePath(&schema, scale.SpecReplicasPath, "integer", scalePath.Child("specReplicasPath"))...)
+		allErrs = append(allErrs, lintScalePath(&schema, scale.StatusReplicasPath, "integer", scalePath.Child("statusReplicasPath"))...)
+		if scale.LabelSelectorPath != nil {
+			allErrs = append(allErrs, lintScalePath(&schema, *scale.LabelSelectorPath, "string", scalePath.Child("labelSelectorPath"))...)
+		}
+	}
+
+	return allErrs
+}
+
+// lintScalePath checks that the given JSON path points to a field of the given type. Paths into
+// fields preserving unknown fields cannot be checked and are accepted.
+func lintScalePath(schema *apiextensionsv1.JSONSchemaProps, path, expectedType string, fldPath *field.Path) field.ErrorList {
+	if path == "" {
+		return nil
+	}
+	prop, found := lookupField(schema, path)
+	switch {
+	case !found:
+		return field.ErrorList{field.Invalid(fldPath, path, "must point to a field in the schema")}
+	case prop != nil && prop.Type != "" && prop.Type != expectedType:
+		return field.ErrorList{field.Invalid(fldPath, path, fmt.Sprintf("must point to a field of type %s, not %s", expectedType, prop.Type))}
+	}
+	return nil
+}
+
+// lookupField returns the schema of the field with the given JSON path, e.g. .spec.replicas. If the
+// field is under a field preserving unknown fields, the returned schema is nil, but found is true.
+func lookupField(schema *apiextensionsv1.JSONSchemaProps, path string) (prop *apiextensionsv1.JSONSchemaProps, found bool) {
+	current := schema
+	for _, name := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		if p, ok := current.Properties[name]; ok {
+			current = &p
+			continue
+		}
+		if current.AdditionalProperties != nil && current.AdditionalProperties.Schema != nil {
+			current = current.AdditionalProperties.Schema
+			continue
+		}
+		if preservesUnknownFields(current) {
+			return nil, true
+		}
+		return nil, false
+	}
+	return current, true
+}
+
+func preservesUnknownFields(schema *apiextensionsv1.JSONSchemaProps) bool {
+	return schema.XPreserveUnknownFields != nil && *schema.XPreserveUnknownFields
+}
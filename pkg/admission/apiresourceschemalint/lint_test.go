@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresourceschemalint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestLintAPIResourceVersion(t *testing.T) {
+	const validSchema = `{"type":"object","properties":{"spec":{"type":"object","properties":{"replicas":{"type":"integer"}}},"status":{"type":"object","properties":{"replicas":{"type":"integer"},"selector":{"type":"string"}}}}}`
+
+	tests := []struct {
+		name         string
+		schema       string
+		subresources apiextensionsv1.CustomResourceSubresources
+		wantErrs     []string
+	}{
+		{
+			name:   "valid",
+			schema: validSchema,
+			subresources: apiextensionsv1.CustomResourceSubresources{
+				Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+				Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+					SpecReplicasPath:   ".spec.replicas",
+					StatusReplicasPath: ".status.replicas",
+					LabelSelectorPath:  pointer.String(".status.selector"),
+				},
+			},
+		},
+		{
+			name:   "invalid JSON is left to validation",
+			schema: `{`,
+		},
+		{
+			name:     "pruning disabled for the whole object",
+			schema:   `{"type":"object","x-kubernetes-preserve-unknown-fields":true}`,
+			wantErrs: []string{"spec.versions[0].schema.x-kubernetes-preserve-unknown-fields: Forbidden: disables pruning of the whole object, including spec and status; set it on the fields that need it instead"},
+		},
+		{
+			name:   "spec and status not objects",
+			schema: `{"type":"object","properties":{"spec":{"type":"string"},"status":{"type":"array","items":{"type":"string"}}}}`,
+			wantErrs: []string{
+				`spec.versions[0].schema.properties[spec].type: Invalid value: "string": must be object`,
+				`spec.versions[0].schema.properties[status].type: Invalid value: "array": must be object`,
+			},
+		},
+		{
+			name:   "status subresource without status",
+			schema: `{"type":"object","properties":{"spec":{"type":"object"}}}`,
+			subresources: apiextensionsv1.CustomResourceSubresources{
+				Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+			},
+			wantErrs: []string{"spec.versions[0].schema.properties[status]: Required value: must be specified with the status subresource, otherwise status updates are pruned"},
+		},
+		{
+			name:   "status required with status subresource",
+			schema: `{"type":"object","required":["spec","status"],"properties":{"spec":{"type":"object"},"status":{"type":"object"}}}`,
+			subresources: apiextensionsv1.CustomResourceSubresources{
+				Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+			},
+			wantErrs: []string{`spec.versions[0].schema.required[1]: Invalid value: "status": must not be required with the status subresource, because status is dropped on create`},
+		},
+		{
+			name:   "status required without status subresource",
+			schema: `{"type":"object","required":["status"],"properties":{"status":{"type":"object"}}}`,
+		},
+		{
+			name:   "scale paths not in the schema or of the wrong type",
+			schema: validSchema,
+			subresources: apiextensionsv1.CustomResourceSubresources{
+				Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+					SpecReplicasPath:   ".spec.size",
+					StatusReplicasPath: ".status.selector",
+					LabelSelectorPath:  pointer.String(".status.replicas"),
+				},
+			},
+			wantErrs: []string{
+				`spec.versions[0].subresources.scale.specReplicasPath: Invalid value: ".spec.size": must point to a field in the schema`,
+				`spec.versions[0].subresources.scale.statusReplicasPath: Invalid value: ".status.selector": must point to a field of type integer, not string`,
+				`spec.versions[0].subresources.scale.labelSelectorPath: Invalid value: ".status.replicas": must point to a field of type string, not integer`,
+			},
+		},
+		{
+			name:   "scale paths into fields preserving unknown fields",
+			schema: `{"type":"object","properties":{"spec":{"type":"object","x-kubernetes-preserve-unknown-fields":true},"status":{"type":"object","additionalProperties":{"type":"integer"}}}}`,
+			subresources: apiextensionsv1.CustomResourceSubresources{
+				Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+					SpecReplicasPath:   ".spec.replicas",
+					StatusReplicasPath: ".status.replicas",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version := &apisv1alpha1.APIResourceVersion{
+				Name:         "v1",
+				Schema:       runtime.RawExtension{Raw: []byte(tt.schema)},
+				Subresources: tt.subresources,
+			}
+			errs := LintAPIResourceVersion(version, field.NewPath("spec", "versions").Index(0))
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Error())
+			}
+			require.Equal(t, tt.wantErrs, got)
+		})
+	}
+}
@@ -33,12 +33,14 @@ import (
 	kuser "k8s.io/apiserver/pkg/authentication/user"
 
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 )
 
 // Validate ClusterWorkspace creation and updates for
 // - immutability of fields like type
 // - valid phase transitions fulfilling pre-conditions
-// - status.location.current and status.baseURL cannot be unset.
+// - status.location.current and status.baseURL cannot be unset
+// - the feature gates annotation is valid.
 
 // Mutate ClusterWorkspace creation and updates for
 // - initializers are short enough to be put into a label
@@ -168,6 +170,16 @@ func (o *clusterWorkspace) Validate(ctx context.Context, a admission.Attributes,
 		}
 	}
 
+	for i, alias := range cw.Spec.Aliases {
+		if alias.Name == cw.Name {
+			return admission.NewForbidden(a, field.Invalid(field.NewPath("spec", "aliases").Index(i).Child("name"), alias.Name, "must differ from the workspace name"))
+		}
+	}
+
+	if _, err := kcpfeatures.ParseWorkspaceFeatureGates(cw.Annotations[tenancyv1alpha1.ExperimentalClusterWorkspaceFeatureGatesAnnotationKey]); err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("invalid %s annotation: %w", tenancyv1alpha1.ExperimentalClusterWorkspaceFeatureGatesAnnotationKey, err))
+	}
+
 	if phaseOrdinal[cw.Status.Phase] > phaseOrdinal[tenancyv1alpha1.ClusterWorkspacePhaseInitializing] && len(cw.Status.Initializers) > 0 {
 		return admission.NewForbidden(a, fmt.Errorf("spec.initializers must be empty for phase %s", cw.Status.Phase))
 	}
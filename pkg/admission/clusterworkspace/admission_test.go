@@ -625,6 +625,68 @@ func TestValidate(t *testing.T) {
 			}),
 			expectedErrors: []string{"expected user annotation experimental.tenancy.kcp.dev/owner={\"username\":\"someone\",\"uid\":\"id\",\"groups\":[\"a\",\"b\"],\"extra\":{\"one\":[\"1\",\"01\"]}}"},
 		},
+		{
+			name: "accepts valid feature gates",
+			a: updateAttr(&tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+					Annotations: map[string]string{
+						"experimental.tenancy.kcp.dev/feature-gates": "KCPLocationAPI=false",
+					},
+				},
+			}, &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+			}),
+		},
+		{
+			name: "rejects feature gates that cannot be set per workspace",
+			a: updateAttr(&tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+					Annotations: map[string]string{
+						"experimental.tenancy.kcp.dev/feature-gates": "KCPLocationAPI=true,KCPSyncerTunnel=true",
+					},
+				},
+			}, &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+			}),
+			expectedErrors: []string{`feature "KCPSyncerTunnel" cannot be set per workspace`},
+		},
+		{
+			name: "accepts aliases",
+			a: updateAttr(&tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+					Aliases: []tenancyv1alpha1.ClusterWorkspaceAlias{{Name: "old-test"}},
+				},
+			}, &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+			}),
+		},
+		{
+			name: "rejects an alias equal to the workspace name",
+			a: updateAttr(&tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+					Aliases: []tenancyv1alpha1.ClusterWorkspaceAlias{{Name: "old-test"}, {Name: "test"}},
+				},
+			}, &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+			}),
+			expectedErrors: []string{"spec.aliases[1].name", "must differ from the workspace name"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,337 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacequota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	quota "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/pkg/admission/initializers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacequota"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/ClusterWorkspaceQuota"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(_ io.Reader) (admission.Interface, error) {
+		return NewClusterWorkspaceQuota(), nil
+	})
+}
+
+// clusterWorkspaceQuota rejects the creation of objects in a workspace that would exceed the
+// ClusterWorkspaceQuota of the same name in the parent workspace, and updates growing the etcd
+// storage of the workspace beyond the quota. It also validates ClusterWorkspaceQuotas themselves.
+//
+// Independently of any quota, it rejects the creation of objects of bound resources beyond the
+// object count limit set by the APIExport the resource is bound from. That limit applies per
+// workspace too.
+//
+// Usage is computed from the informers, i.e. a burst of concurrent creations can briefly
+// exceed the quota. The etcd storage is only as recent as the last scan of etcd, and is not
+// enforced before the first scan.
+type clusterWorkspaceQuota struct {
+	*admission.Handler
+
+	getQuota        func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceQuota, error)
+	listAPIBindings func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	getAPIExport    func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)
+	countObjects    clusterworkspacequota.CountObjectsFunc
+	listObjects     clusterworkspacequota.ListObjectsFunc
+	storageUsage    clusterworkspacequota.StorageUsageFunc
+
+	quotasHasSynced      cache.InformerSynced
+	apiBindingsHasSynced cache.InformerSynced
+	apiExportsHasSynced  cache.InformerSynced
+}
+
+var _ admission.ValidationInterface = &clusterWorkspaceQuota{}
+var _ admission.InitializationValidator = &clusterWorkspaceQuota{}
+var _ = initializers.WantsKcpInformers(&clusterWorkspaceQuota{})
+var _ = initializers.WantsDynamicDiscoverySharedInformerFactory(&clusterWorkspaceQuota{})
+var _ = initializers.WantsWorkspaceStorageUsage(&clusterWorkspaceQuota{})
+var _ = initializers.WantsWorkspaceObjectCounts(&clusterWorkspaceQuota{})
+
+// NewClusterWorkspaceQuota returns a new ClusterWorkspaceQuota admission plugin.
+func NewClusterWorkspaceQuota() admission.ValidationInterface {
+	p := &clusterWorkspaceQuota{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+	}
+	p.SetReadyFunc(func() bool {
+		return p.quotasHasSynced() && p.apiBindingsHasSynced() && p.apiExportsHasSynced()
+	})
+	return p
+}
+
+func (p *clusterWorkspaceQuota) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetSubresource() != "" {
+		return nil
+	}
+
+	if a.GetResource().GroupResource() == tenancyv1alpha1.Resource("clusterworkspacequotas") {
+		return p.validateQuota(a)
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if a.GetOperation() == admission.Create {
+		if err := p.validateObjectCountLimit(a, clusterName); err != nil {
+			return err
+		}
+	}
+
+	parent, hasParent := clusterName.Parent()
+	if !hasParent {
+		return nil
+	}
+	workspaceQuota, err := p.getQuota(parent, clusterName.Base())
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	requested, err := p.requested(a)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	requested = quota.Mask(requested, quota.ResourceNames(workspaceQuota.Spec.Hard))
+	if len(requested) == 0 {
+		return nil
+	}
+
+	hard := quota.Mask(workspaceQuota.Spec.Hard, quota.ResourceNames(requested))
+	used, err := clusterworkspacequota.Usage(clusterName, hard, p.countObjects, p.listObjects, p.storageUsage)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	// resources of unknown usage, i.e. the etcd storage before the first scan, are not enforced.
+	requested = quota.Mask(requested, quota.ResourceNames(used))
+	hard = quota.Mask(hard, quota.ResourceNames(used))
+	if ok, exceeded := quota.LessThanOrEqual(quota.Add(used, requested), hard); !ok {
+		return admission.NewForbidden(a, fmt.Errorf("exceeded ClusterWorkspaceQuota %s|%s: requested: %s, used: %s, limited: %s",
+			parent, workspaceQuota.Name,
+			format(quota.Mask(requested, exceeded)),
+			format(quota.Mask(used, exceeded)),
+			format(quota.Mask(hard, exceeded)),
+		))
+	}
+
+	return nil
+}
+
+// validateObjectCountLimit rejects the creation of an object beyond the object count limit of
+// the APIExport its resource is bound from.
+func (p *clusterWorkspaceQuota) validateObjectCountLimit(a admission.Attributes, clusterName logicalcluster.Name) error {
+	gr := a.GetResource().GroupResource()
+	limit, export, err := p.objectCountLimitFor(clusterName, gr)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if limit == nil {
+		return nil
+	}
+
+	count, err := p.countObjects(clusterName, gr)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if count >= limit.Max {
+		return admission.NewForbidden(a, fmt.Errorf("exceeded the limit of %d %s per workspace set by APIExport %s|%s", limit.Max, gr, logicalcluster.From(export), export.Name))
+	}
+	return nil
+}
+
+// objectCountLimitFor returns the object count limit of the APIExport the given resource is bound
+// from, if the bound identity is still the one of the APIExport.
+func (p *clusterWorkspaceQuota) objectCountLimitFor(clusterName logicalcluster.Name, gr schema.GroupResource) (*apisv1alpha1.ObjectCountLimit, *apisv1alpha1.APIExport, error) {
+	bindings, err := p.listAPIBindings(clusterName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, binding := range bindings {
+		if binding.Spec.Reference.Workspace == nil {
+			continue
+		}
+		for _, boundResource := range binding.Status.BoundResources {
+			if boundResource.Group != gr.Group || boundResource.Resource != gr.Resource {
+				continue
+			}
+
+			exportClusterName := clusterName
+			if path := binding.Spec.Reference.Workspace.Path; path != "" {
+				exportClusterName = logicalcluster.New(path)
+			}
+			export, err := p.getAPIExport(exportClusterName, binding.Spec.Reference.Workspace.ExportName)
+			if apierrors.IsNotFound(err) {
+				// the APIExport is gone or not on this shard
+				return nil, nil, nil
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			if export.Status.IdentityHash != boundResource.Schema.IdentityHash {
+				return nil, nil, nil
+			}
+
+			for i := range export.Spec.ObjectCountLimits {
+				limit := &export.Spec.ObjectCountLimits[i]
+				if limit.Group == gr.Group && limit.Resource == gr.Resource {
+					return limit, export, nil
+				}
+			}
+			return nil, nil, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// requested returns the usage the object of a creation adds, or the etcd storage an update grows by.
+func (p *clusterWorkspaceQuota) requested(a admission.Attributes) (corev1.ResourceList, error) {
+	if a.GetOperation() == admission.Create {
+		return clusterworkspacequota.ObjectUsage(a.GetResource().GroupResource(), a.GetObject())
+	}
+
+	newStorage, err := clusterworkspacequota.ObjectStorage(a.GetObject())
+	if err != nil {
+		return nil, err
+	}
+	oldStorage, err := clusterworkspacequota.ObjectStorage(a.GetOldObject())
+	if err != nil {
+		return nil, err
+	}
+	growth := quota.SubtractWithNonNegativeResult(newStorage, oldStorage)
+	return quota.RemoveZeros(quota.Mask(growth, []corev1.ResourceName{tenancyv1alpha1.ResourceStorage})), nil
+}
+
+func (p *clusterWorkspaceQuota) validateQuota(a admission.Attributes) error {
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	workspaceQuota := &tenancyv1alpha1.ClusterWorkspaceQuota{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, workspaceQuota); err != nil {
+		return fmt.Errorf("failed to convert unstructured to ClusterWorkspaceQuota: %w", err)
+	}
+
+	var unsupported []string
+	for name := range workspaceQuota.Spec.Hard {
+		if !clusterworkspacequota.IsSupportedResource(name) {
+			unsupported = append(unsupported, string(name))
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return admission.NewForbidden(a, fmt.Errorf("unsupported resources in spec.hard: %s", strings.Join(unsupported, ", ")))
+	}
+	return nil
+}
+
+// format prints a resource list as "name=quantity" pairs, sorted by name.
+func format(resources corev1.ResourceList) string {
+	pairs := make([]string, 0, len(resources))
+	for name, quantity := range resources {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// SetKcpInformers implements the WantsKcpInformers interface.
+func (p *clusterWorkspaceQuota) SetKcpInformers(f kcpinformers.SharedInformerFactory) {
+	quotasInformer := f.Tenancy().V1alpha1().ClusterWorkspaceQuotas()
+	apiBindingsInformer := f.Apis().V1alpha1().APIBindings()
+	apiExportsInformer := f.Apis().V1alpha1().APIExports()
+
+	p.quotasHasSynced = quotasInformer.Informer().HasSynced
+	p.apiBindingsHasSynced = apiBindingsInformer.Informer().HasSynced
+	p.apiExportsHasSynced = apiExportsInformer.Informer().HasSynced
+
+	p.getQuota = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceQuota, error) {
+		return quotasInformer.Lister().Cluster(clusterName).Get(name)
+	}
+	p.listAPIBindings = func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+		return apiBindingsInformer.Lister().Cluster(clusterName).List(labels.Everything())
+	}
+	p.getAPIExport = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+		return apiExportsInformer.Lister().Cluster(clusterName).Get(name)
+	}
+}
+
+// SetWorkspaceStorageUsage implements the WantsWorkspaceStorageUsage interface.
+func (p *clusterWorkspaceQuota) SetWorkspaceStorageUsage(storageUsage *clusterworkspacequota.StorageUsage) {
+	p.storageUsage = storageUsage.Get
+}
+
+// SetWorkspaceObjectCounts implements the WantsWorkspaceObjectCounts interface.
+func (p *clusterWorkspaceQuota) SetWorkspaceObjectCounts(objectCounts *clusterworkspacequota.ObjectCounts) {
+	p.countObjects = objectCounts.Count
+}
+
+// SetDynamicDiscoverySharedInformerFactory implements the WantsDynamicDiscoverySharedInformerFactory interface.
+func (p *clusterWorkspaceQuota) SetDynamicDiscoverySharedInformerFactory(ddsif *informer.DynamicDiscoverySharedInformerFactory) {
+	p.listObjects = clusterworkspacequota.NewListObjectsFunc(ddsif)
+}
+
+func (p *clusterWorkspaceQuota) ValidateInitialization() error {
+	if p.getQuota == nil {
+		return errors.New("missing getQuota")
+	}
+	if p.listAPIBindings == nil {
+		return errors.New("missing listAPIBindings")
+	}
+	if p.getAPIExport == nil {
+		return errors.New("missing getAPIExport")
+	}
+	if p.countObjects == nil {
+		return errors.New("missing countObjects")
+	}
+	if p.listObjects == nil {
+		return errors.New("missing listObjects")
+	}
+	if p.storageUsage == nil {
+		return errors.New("missing storageUsage")
+	}
+	return nil
+}
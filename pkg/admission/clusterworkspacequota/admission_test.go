@@ -0,0 +1,354 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacequota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+var (
+	widgets = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	pods    = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	quotas  = tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspacequotas")
+)
+
+func createAttr(t *testing.T, obj runtime.Object, resource schema.GroupVersionResource, subresource string) admission.Attributes {
+	t.Helper()
+
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	require.NoError(t, err)
+
+	return admission.NewAttributesRecord(
+		&unstructured.Unstructured{Object: raw},
+		nil,
+		resource.GroupVersion().WithKind("Object"),
+		"default",
+		"object",
+		resource,
+		subresource,
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func pod(cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "main",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse(memory),
+					},
+				},
+			}},
+		},
+	}
+}
+
+func workspaceQuota(hard corev1.ResourceList) *tenancyv1alpha1.ClusterWorkspaceQuota {
+	return &tenancyv1alpha1.ClusterWorkspaceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "consumer",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+		Spec: tenancyv1alpha1.ClusterWorkspaceQuotaSpec{Hard: hard},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		quota       *tenancyv1alpha1.ClusterWorkspaceQuota
+		existing    map[schema.GroupResource][]runtime.Object
+		storage     corev1.ResourceList
+		obj         runtime.Object
+		resource    schema.GroupVersionResource
+		subresource string
+		wantErr     string
+	}{
+		"no quota": {
+			obj:      &unstructured.Unstructured{},
+			resource: widgets,
+		},
+		"below count": {
+			quota:    workspaceQuota(corev1.ResourceList{"count/widgets.example.com": resource.MustParse("2")}),
+			existing: map[schema.GroupResource][]runtime.Object{widgets.GroupResource(): {&unstructured.Unstructured{}}},
+			obj:      &unstructured.Unstructured{},
+			resource: widgets,
+		},
+		"at count": {
+			quota:    workspaceQuota(corev1.ResourceList{"count/widgets.example.com": resource.MustParse("1")}),
+			existing: map[schema.GroupResource][]runtime.Object{widgets.GroupResource(): {&unstructured.Unstructured{}}},
+			obj:      &unstructured.Unstructured{},
+			resource: widgets,
+			wantErr:  "exceeded ClusterWorkspaceQuota root:org|consumer: requested: count/widgets.example.com=1, used: count/widgets.example.com=1, limited: count/widgets.example.com=1",
+		},
+		"other resource": {
+			quota:    workspaceQuota(corev1.ResourceList{"count/gadgets.example.com": resource.MustParse("0")}),
+			obj:      &unstructured.Unstructured{},
+			resource: widgets,
+		},
+		"subresource": {
+			quota:       workspaceQuota(corev1.ResourceList{"count/widgets.example.com": resource.MustParse("0")}),
+			obj:         &unstructured.Unstructured{},
+			resource:    widgets,
+			subresource: "status",
+		},
+		"pod within cpu": {
+			quota:    workspaceQuota(corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")}),
+			existing: map[schema.GroupResource][]runtime.Object{pods.GroupResource(): {pod("500m", "1Gi")}},
+			obj:      pod("500m", "1Gi"),
+			resource: pods,
+		},
+		"pod exceeding cpu": {
+			quota:    workspaceQuota(corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1"), corev1.ResourceRequestsMemory: resource.MustParse("10Gi")}),
+			existing: map[schema.GroupResource][]runtime.Object{pods.GroupResource(): {pod("600m", "1Gi")}},
+			obj:      pod("500m", "1Gi"),
+			resource: pods,
+			wantErr:  "exceeded ClusterWorkspaceQuota root:org|consumer: requested: requests.cpu=500m, used: requests.cpu=600m, limited: requests.cpu=1",
+		},
+		"below storage": {
+			quota:    workspaceQuota(corev1.ResourceList{tenancyv1alpha1.ResourceStorage: resource.MustParse("1Ki")}),
+			storage:  corev1.ResourceList{tenancyv1alpha1.ResourceStorage: resource.MustParse("512")},
+			obj:      &unstructured.Unstructured{},
+			resource: widgets,
+		},
+		"exceeding storage": {
+			quota:    workspaceQuota(corev1.ResourceList{tenancyv1alpha1.ResourceStorage: resource.MustParse("1Ki")}),
+			storage:  corev1.ResourceList{tenancyv1alpha1.ResourceStorage: resource.MustParse("1Ki")},
+			obj:      &unstructured.Unstructured{},
+			resource: widgets,
+			wantErr:  "exceeded ClusterWorkspaceQuota root:org|consumer: requested: tenancy.kcp.dev/storage=2, used: tenancy.kcp.dev/storage=1Ki, limited: tenancy.kcp.dev/storage=1Ki",
+		},
+		"unknown storage": {
+			quota:    workspaceQuota(corev1.ResourceList{tenancyv1alpha1.ResourceStorage: resource.MustParse("0")}),
+			obj:      &unstructured.Unstructured{},
+			resource: widgets,
+		},
+		"valid quota": {
+			obj:      workspaceQuota(corev1.ResourceList{"count/pods": resource.MustParse("1"), corev1.ResourceLimitsMemory: resource.MustParse("1Gi"), tenancyv1alpha1.ResourceStorage: resource.MustParse("1Gi")}),
+			resource: quotas,
+		},
+		"quota with unsupported resource": {
+			obj:      workspaceQuota(corev1.ResourceList{"cpu": resource.MustParse("1"), "storage": resource.MustParse("1Gi")}),
+			resource: quotas,
+			wantErr:  "unsupported resources in spec.hard: cpu, storage",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &clusterWorkspaceQuota{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				getQuota: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceQuota, error) {
+					if tt.quota != nil && clusterName == logicalcluster.From(tt.quota) && name == tt.quota.Name {
+						return tt.quota, nil
+					}
+					return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacequotas"), name)
+				},
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					return nil, nil
+				},
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+				},
+				countObjects: func(clusterName logicalcluster.Name, gr schema.GroupResource) (int64, error) {
+					require.Equal(t, logicalcluster.New("root:org:consumer"), clusterName)
+					return int64(len(tt.existing[gr])), nil
+				},
+				listObjects: func(clusterName logicalcluster.Name, gr schema.GroupResource) ([]runtime.Object, error) {
+					require.Equal(t, logicalcluster.New("root:org:consumer"), clusterName)
+					require.Equal(t, pods.GroupResource(), gr, "only pods are listed")
+					return tt.existing[gr], nil
+				},
+				storageUsage: func(clusterName logicalcluster.Name) (corev1.ResourceList, bool) {
+					require.Equal(t, logicalcluster.New("root:org:consumer"), clusterName)
+					return tt.storage, tt.storage != nil
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org:consumer")})
+			err := p.Validate(ctx, createAttr(t, tt.obj, tt.resource, tt.subresource), nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.True(t, apierrors.IsForbidden(err), "unexpected error: %v", err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func binding(path, exportName, identityHash string) *apisv1alpha1.APIBinding {
+	return &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: exportName},
+		Spec: apisv1alpha1.APIBindingSpec{
+			Reference: apisv1alpha1.ExportReference{
+				Workspace: &apisv1alpha1.WorkspaceExportReference{Path: path, ExportName: exportName},
+			},
+		},
+		Status: apisv1alpha1.APIBindingStatus{
+			BoundResources: []apisv1alpha1.BoundAPIResource{{
+				Group:    widgets.Group,
+				Resource: widgets.Resource,
+				Schema:   apisv1alpha1.BoundAPIResourceSchema{IdentityHash: identityHash},
+			}},
+		},
+	}
+}
+
+func export(identityHash string, limits ...apisv1alpha1.ObjectCountLimit) *apisv1alpha1.APIExport {
+	return &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "widgets",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:provider"},
+		},
+		Spec:   apisv1alpha1.APIExportSpec{ObjectCountLimits: limits},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: identityHash},
+	}
+}
+
+func TestValidateObjectCountLimit(t *testing.T) {
+	widgetsLimit := func(max int64) apisv1alpha1.ObjectCountLimit {
+		return apisv1alpha1.ObjectCountLimit{
+			GroupResource: apisv1alpha1.GroupResource{Group: widgets.Group, Resource: widgets.Resource},
+			Max:           max,
+		}
+	}
+
+	tests := map[string]struct {
+		bindings    []*apisv1alpha1.APIBinding
+		export      *apisv1alpha1.APIExport
+		quota       *tenancyv1alpha1.ClusterWorkspaceQuota
+		count       int64
+		subresource string
+		wantErr     string
+	}{
+		"below limit": {
+			bindings: []*apisv1alpha1.APIBinding{binding("root:provider", "widgets", "id")},
+			export:   export("id", widgetsLimit(3)),
+			count:    2,
+		},
+		"at limit": {
+			bindings: []*apisv1alpha1.APIBinding{binding("root:provider", "widgets", "id")},
+			export:   export("id", widgetsLimit(3)),
+			count:    3,
+			wantErr:  "exceeded the limit of 3 widgets.example.com per workspace set by APIExport root:provider|widgets",
+		},
+		"zero limit": {
+			bindings: []*apisv1alpha1.APIBinding{binding("root:provider", "widgets", "id")},
+			export:   export("id", widgetsLimit(0)),
+			wantErr:  "exceeded the limit of 0 widgets.example.com per workspace set by APIExport root:provider|widgets",
+		},
+		"limit below quota": {
+			bindings: []*apisv1alpha1.APIBinding{binding("root:provider", "widgets", "id")},
+			export:   export("id", widgetsLimit(3)),
+			quota:    workspaceQuota(corev1.ResourceList{"count/widgets.example.com": resource.MustParse("10")}),
+			count:    3,
+			wantErr:  "exceeded the limit of 3 widgets.example.com per workspace set by APIExport root:provider|widgets",
+		},
+		"quota below limit": {
+			bindings: []*apisv1alpha1.APIBinding{binding("root:provider", "widgets", "id")},
+			export:   export("id", widgetsLimit(10)),
+			quota:    workspaceQuota(corev1.ResourceList{"count/widgets.example.com": resource.MustParse("3")}),
+			count:    3,
+			wantErr:  "exceeded ClusterWorkspaceQuota root:org|consumer",
+		},
+		"no limit for resource": {
+			bindings: []*apisv1alpha1.APIBinding{binding("root:provider", "widgets", "id")},
+			export:   export("id"),
+			count:    100,
+		},
+		"other identity": {
+			bindings: []*apisv1alpha1.APIBinding{binding("root:provider", "widgets", "other")},
+			export:   export("id", widgetsLimit(0)),
+		},
+		"export not found": {
+			bindings: []*apisv1alpha1.APIBinding{binding("root:other", "widgets", "id")},
+			export:   export("id", widgetsLimit(0)),
+		},
+		"not bound": {
+			export: export("id", widgetsLimit(0)),
+		},
+		"subresource": {
+			bindings:    []*apisv1alpha1.APIBinding{binding("root:provider", "widgets", "id")},
+			export:      export("id", widgetsLimit(0)),
+			subresource: "status",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &clusterWorkspaceQuota{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				getQuota: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceQuota, error) {
+					if tt.quota != nil && clusterName == logicalcluster.From(tt.quota) && name == tt.quota.Name {
+						return tt.quota, nil
+					}
+					return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacequotas"), name)
+				},
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					require.Equal(t, logicalcluster.New("root:org:consumer"), clusterName)
+					return tt.bindings, nil
+				},
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					if clusterName == logicalcluster.From(tt.export) && name == tt.export.Name {
+						return tt.export, nil
+					}
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+				},
+				countObjects: func(clusterName logicalcluster.Name, gr schema.GroupResource) (int64, error) {
+					require.Equal(t, widgets.GroupResource(), gr)
+					return tt.count, nil
+				},
+				storageUsage: func(clusterName logicalcluster.Name) (corev1.ResourceList, bool) {
+					return nil, false
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org:consumer")})
+			err := p.Validate(ctx, createAttr(t, &unstructured.Unstructured{}, widgets, tt.subresource), nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.True(t, apierrors.IsForbidden(err), "unexpected error: %v", err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
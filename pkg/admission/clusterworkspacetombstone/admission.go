@@ -0,0 +1,315 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacetombstone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/admission/initializers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/ClusterWorkspaceTombstone"
+
+	// UndeleteVerb is the verb on clusterworkspaces that allows to restore a deleted workspace.
+	UndeleteVerb = "undelete"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(_ io.Reader) (admission.Interface, error) {
+		p := &clusterWorkspaceTombstone{
+			Handler:          admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+			createAuthorizer: delegated.NewDelegatedAuthorizer,
+			now:              time.Now,
+		}
+		p.SetReadyFunc(func() bool {
+			return p.tombstonesHasSynced() && p.clusterWorkspacesHasSynced()
+		})
+		return p, nil
+	})
+}
+
+// clusterWorkspaceTombstone protects the content of deleted workspaces that is retained by a
+// ClusterWorkspaceTombstone:
+//
+//   - writes to a workspace with a tombstone are rejected, apart from deletions and updates of objects
+//     being deleted after the tombstone expired, and writes after the workspace has been restored.
+//   - a ClusterWorkspace with the name of a tombstone can only be created by users allowed to undelete
+//     clusterworkspaces. It restores the workspace on the shard of the retained content, with the
+//     type it had before.
+//   - tombstones cannot be created for existing workspaces.
+//
+// The fence is enforced by the shards that have the parent workspace in their informers.
+type clusterWorkspaceTombstone struct {
+	*admission.Handler
+
+	getTombstone        func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceTombstone, error)
+	getClusterWorkspace func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+
+	deepSARClient    kcpkubernetesclientset.ClusterInterface
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+	now              func() time.Time
+
+	tombstonesHasSynced        cache.InformerSynced
+	clusterWorkspacesHasSynced cache.InformerSynced
+}
+
+var _ admission.MutationInterface = &clusterWorkspaceTombstone{}
+var _ admission.ValidationInterface = &clusterWorkspaceTombstone{}
+var _ admission.InitializationValidator = &clusterWorkspaceTombstone{}
+var _ = initializers.WantsKcpInformers(&clusterWorkspaceTombstone{})
+var _ = initializers.WantsDeepSARClient(&clusterWorkspaceTombstone{})
+
+// Admit restores the type and the shard of a deleted workspace when a ClusterWorkspace of the name
+// of a tombstone is created by a user allowed to undelete it.
+func (p *clusterWorkspaceTombstone) Admit(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("clusterworkspaces") || a.GetOperation() != admission.Create {
+		return nil
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	tombstone, err := p.getTombstone(clusterName, a.GetName())
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	if err := p.authorizeUndelete(ctx, a, clusterName, tombstone); err != nil {
+		return err
+	}
+
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	cw := &tenancyv1alpha1.ClusterWorkspace{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, cw); err != nil {
+		return fmt.Errorf("failed to convert unstructured to ClusterWorkspace: %w", err)
+	}
+
+	cw.Spec.Type = tombstone.Spec.Type
+	cw.Spec.Shard = &tenancyv1alpha1.ShardConstraints{Name: tombstone.Spec.Shard}
+	if cw.Annotations == nil {
+		cw.Annotations = map[string]string{}
+	}
+	cw.Annotations[tenancyv1alpha1.ClusterWorkspaceRestoredFromAnnotationKey] = string(tombstone.UID)
+
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cw)
+	if err != nil {
+		return err
+	}
+	u.Object = raw
+	return nil
+}
+
+// Validate rejects writes to workspaces with a tombstone, restoring workspaces without the permission to
+// undelete them, and tombstones of existing workspaces.
+func (p *clusterWorkspaceTombstone) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	if err := p.validateFence(a, clusterName); err != nil {
+		return err
+	}
+
+	switch a.GetResource().GroupResource() {
+	case tenancyv1alpha1.Resource("clusterworkspaces"):
+		if a.GetSubresource() != "" || a.GetOperation() == admission.Delete {
+			return nil
+		}
+		return p.validateClusterWorkspace(ctx, a, clusterName)
+	case tenancyv1alpha1.Resource("clusterworkspacetombstones"):
+		if a.GetOperation() != admission.Create {
+			return nil
+		}
+		cw, err := p.getClusterWorkspace(clusterName, a.GetName())
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		if cw.DeletionTimestamp.IsZero() {
+			return admission.NewForbidden(a, fmt.Errorf("workspace %s|%s exists and is not being deleted", clusterName, a.GetName()))
+		}
+	}
+
+	return nil
+}
+
+// validateFence rejects writes to a workspace whose content is retained by a tombstone, unless the
+// workspace has been restored from it. After the tombstone expired, deletions and updates of objects
+// being deleted are allowed, such that the content, including nested workspaces, can be deleted and
+// finalized.
+func (p *clusterWorkspaceTombstone) validateFence(a admission.Attributes, clusterName logicalcluster.Name) error {
+	parent, hasParent := clusterName.Parent()
+	if !hasParent {
+		return nil
+	}
+	tombstone, err := p.getTombstone(parent, clusterName.Base())
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	cw, err := p.getClusterWorkspace(parent, clusterName.Base())
+	if err != nil && !apierrors.IsNotFound(err) {
+		return apierrors.NewInternalError(err)
+	}
+	if err == nil && cw.DeletionTimestamp.IsZero() && cw.Annotations[tenancyv1alpha1.ClusterWorkspaceRestoredFromAnnotationKey] == string(tombstone.UID) {
+		// restored, the tombstone is removed by the controller eventually
+		return nil
+	}
+
+	if !p.now().Before(tombstone.Spec.ExpirationTime.Time) {
+		switch a.GetOperation() {
+		case admission.Delete:
+			return nil
+		case admission.Update:
+			if old, err := meta.Accessor(a.GetOldObject()); err == nil && !old.GetDeletionTimestamp().IsZero() {
+				return nil
+			}
+		}
+	}
+	return admission.NewForbidden(a, fmt.Errorf("workspace %s has been deleted", clusterName))
+}
+
+func (p *clusterWorkspaceTombstone) validateClusterWorkspace(ctx context.Context, a admission.Attributes, clusterName logicalcluster.Name) error {
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	restoredFrom, restored := u.GetAnnotations()[tenancyv1alpha1.ClusterWorkspaceRestoredFromAnnotationKey]
+
+	if a.GetOperation() == admission.Update {
+		old, ok := a.GetOldObject().(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected type %T", a.GetOldObject())
+		}
+		if oldRestoredFrom, oldRestored := old.GetAnnotations()[tenancyv1alpha1.ClusterWorkspaceRestoredFromAnnotationKey]; oldRestored != restored || oldRestoredFrom != restoredFrom {
+			return admission.NewForbidden(a, fmt.Errorf("annotation %s is immutable", tenancyv1alpha1.ClusterWorkspaceRestoredFromAnnotationKey))
+		}
+		return nil
+	}
+
+	tombstone, err := p.getTombstone(clusterName, a.GetName())
+	if apierrors.IsNotFound(err) {
+		if restored {
+			return admission.NewForbidden(a, fmt.Errorf("annotation %s can only be set when restoring a deleted workspace", tenancyv1alpha1.ClusterWorkspaceRestoredFromAnnotationKey))
+		}
+		return nil
+	}
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	if err := p.authorizeUndelete(ctx, a, clusterName, tombstone); err != nil {
+		return err
+	}
+	if restoredFrom != string(tombstone.UID) {
+		return admission.NewForbidden(a, fmt.Errorf("annotation %s must be %q when restoring the deleted workspace", tenancyv1alpha1.ClusterWorkspaceRestoredFromAnnotationKey, tombstone.UID))
+	}
+	return nil
+}
+
+func (p *clusterWorkspaceTombstone) authorizeUndelete(ctx context.Context, a admission.Attributes, clusterName logicalcluster.Name, tombstone *tenancyv1alpha1.ClusterWorkspaceTombstone) error {
+	authz, err := p.createAuthorizer(clusterName, p.deepSARClient)
+	if err != nil {
+		// Logging a more specific error for the operator
+		klog.FromContext(ctx).Error(err, "error creating authorizer from delegating authorizer config")
+		// Returning a less specific error to the end user
+		return admission.NewForbidden(a, errors.New("unable to authorize request"))
+	}
+
+	decision, _, err := authz.Authorize(ctx, authorizer.AttributesRecord{
+		User:            a.GetUserInfo(),
+		Verb:            UndeleteVerb,
+		APIGroup:        tenancyv1alpha1.SchemeGroupVersion.Group,
+		APIVersion:      tenancyv1alpha1.SchemeGroupVersion.Version,
+		Resource:        "clusterworkspaces",
+		Name:            tombstone.Name,
+		ResourceRequest: true,
+	})
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("unable to determine access to undelete workspace %s|%s: %w", clusterName, tombstone.Name, err))
+	}
+	if decision != authorizer.DecisionAllow {
+		return admission.NewForbidden(a, fmt.Errorf("workspace %s|%s has been deleted and its content is retained until %s, it can only be restored by users allowed to %s clusterworkspaces", clusterName, tombstone.Name, tombstone.Spec.ExpirationTime.UTC().Format(time.RFC3339), UndeleteVerb))
+	}
+	return nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (p *clusterWorkspaceTombstone) ValidateInitialization() error {
+	if p.getTombstone == nil {
+		return fmt.Errorf(PluginName + " plugin needs a ClusterWorkspaceTombstone lister")
+	}
+	if p.deepSARClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
+	}
+	return nil
+}
+
+// SetKcpInformers implements the WantsKcpInformers interface.
+func (p *clusterWorkspaceTombstone) SetKcpInformers(f kcpinformers.SharedInformerFactory) {
+	tombstonesInformer := f.Tenancy().V1alpha1().ClusterWorkspaceTombstones()
+	clusterWorkspacesInformer := f.Tenancy().V1alpha1().ClusterWorkspaces()
+
+	p.tombstonesHasSynced = tombstonesInformer.Informer().HasSynced
+	p.clusterWorkspacesHasSynced = clusterWorkspacesInformer.Informer().HasSynced
+	p.getTombstone = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceTombstone, error) {
+		return tombstonesInformer.Lister().Cluster(clusterName).Get(name)
+	}
+	p.getClusterWorkspace = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		return clusterWorkspacesInformer.Lister().Cluster(clusterName).Get(name)
+	}
+}
+
+// SetDeepSARClient is an admission plugin initializer function that injects a client capable of deep SAR requests into
+// this admission plugin.
+func (p *clusterWorkspaceTombstone) SetDeepSARClient(client kcpkubernetesclientset.ClusterInterface) {
+	p.deepSARClient = client
+}
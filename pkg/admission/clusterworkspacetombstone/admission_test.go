@@ -0,0 +1,229 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacetombstone
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// fakeAuthorizer allows the users in its set to undelete clusterworkspaces.
+type fakeAuthorizer struct {
+	allowed map[string]bool
+}
+
+func (a *fakeAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	if attr.GetVerb() == UndeleteVerb && attr.GetResource() == "clusterworkspaces" && a.allowed[attr.GetUser().GetName()] {
+		return authorizer.DecisionAllow, "", nil
+	}
+	return authorizer.DecisionNoOpinion, "", nil
+}
+
+var now = time.Date(2022, 11, 20, 0, 0, 0, 0, time.UTC)
+
+func newPlugin(tombstones map[logicalcluster.Name]*tenancyv1alpha1.ClusterWorkspaceTombstone, workspaces map[logicalcluster.Name]*tenancyv1alpha1.ClusterWorkspace) *clusterWorkspaceTombstone {
+	return &clusterWorkspaceTombstone{
+		Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+		getTombstone: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceTombstone, error) {
+			if t, found := tombstones[clusterName.Join(name)]; found {
+				return t, nil
+			}
+			return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacetombstones"), name)
+		},
+		getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			if cw, found := workspaces[clusterName.Join(name)]; found {
+				return cw, nil
+			}
+			return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+		},
+		createAuthorizer: func(clusterName logicalcluster.Name, client kcpkubernetesclientset.ClusterInterface) (authorizer.Authorizer, error) {
+			return &fakeAuthorizer{allowed: map[string]bool{"admin": true}}, nil
+		},
+		now: func() time.Time { return now },
+	}
+}
+
+func tombstone(name string, expiration time.Time) *tenancyv1alpha1.ClusterWorkspaceTombstone {
+	return &tenancyv1alpha1.ClusterWorkspaceTombstone{
+		ObjectMeta: metav1.ObjectMeta{Name: name, UID: "tombstone-uid"},
+		Spec: tenancyv1alpha1.ClusterWorkspaceTombstoneSpec{
+			Type:           tenancyv1alpha1.ClusterWorkspaceTypeReference{Name: "team", Path: "root"},
+			Shard:          "beta",
+			ExpirationTime: metav1.NewTime(expiration),
+		},
+	}
+}
+
+func workspace(name string, annotations map[string]string) *tenancyv1alpha1.ClusterWorkspace {
+	return &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+		Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+			Type: tenancyv1alpha1.ClusterWorkspaceTypeReference{Name: "universal", Path: "root"},
+		},
+	}
+}
+
+func attributes(obj, old runtime.Object, clusterName, userName string, operation admission.Operation) (context.Context, admission.Attributes) {
+	resource := tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspaces")
+	kind := tenancyv1alpha1.SchemeGroupVersion.WithKind("ClusterWorkspace")
+	name := "ws"
+	var options runtime.Object = &metav1.CreateOptions{}
+	switch operation {
+	case admission.Update:
+		options = &metav1.UpdateOptions{}
+	case admission.Delete:
+		options = &metav1.DeleteOptions{}
+	}
+	var u, oldU runtime.Object
+	if obj != nil {
+		u = helpers.ToUnstructuredOrDie(obj)
+		if t, ok := obj.(*tenancyv1alpha1.ClusterWorkspaceTombstone); ok {
+			resource = tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspacetombstones")
+			kind = tenancyv1alpha1.SchemeGroupVersion.WithKind("ClusterWorkspaceTombstone")
+			name = t.Name
+		}
+	} else {
+		resource = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+		kind = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+		name = "cm"
+	}
+	if old != nil {
+		oldU = helpers.ToUnstructuredOrDie(old)
+	}
+	ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(clusterName)})
+	return ctx, admission.NewAttributesRecord(u, oldU, kind, "", name, resource, "", operation, options, false, &user.DefaultInfo{Name: userName})
+}
+
+func TestAdmit(t *testing.T) {
+	tombstones := map[logicalcluster.Name]*tenancyv1alpha1.ClusterWorkspaceTombstone{
+		logicalcluster.New("root:org:ws"): tombstone("ws", now.Add(time.Hour)),
+	}
+
+	tests := map[string]struct {
+		cluster   string
+		user      string
+		wantError string
+		wantType  string
+		wantShard string
+	}{
+		"no tombstone":         {cluster: "root:other", user: "user", wantType: "universal"},
+		"restored":             {cluster: "root:org", user: "admin", wantType: "team", wantShard: "beta"},
+		"without undelete":     {cluster: "root:org", user: "user", wantError: "can only be restored by users allowed to undelete clusterworkspaces"},
+		"recreated in sibling": {cluster: "root:other", user: "admin", wantType: "universal"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := newPlugin(tombstones, nil)
+			ctx, a := attributes(workspace("ws", nil), nil, tt.cluster, tt.user, admission.Create)
+
+			err := p.Admit(ctx, a, nil)
+			if tt.wantError != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+
+			cw := &tenancyv1alpha1.ClusterWorkspace{}
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(a.GetObject().(*unstructured.Unstructured).Object, cw)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantType, string(cw.Spec.Type.Name))
+			if tt.wantShard == "" {
+				require.Nil(t, cw.Spec.Shard)
+				require.NotContains(t, cw.Annotations, tenancyv1alpha1.ClusterWorkspaceRestoredFromAnnotationKey)
+				return
+			}
+			require.Equal(t, tt.wantShard, cw.Spec.Shard.Name)
+			require.Equal(t, "tombstone-uid", cw.Annotations[tenancyv1alpha1.ClusterWorkspaceRestoredFromAnnotationKey])
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	restored := map[string]string{tenancyv1alpha1.ClusterWorkspaceRestoredFromAnnotationKey: "tombstone-uid"}
+	tombstones := map[logicalcluster.Name]*tenancyv1alpha1.ClusterWorkspaceTombstone{
+		logicalcluster.New("root:org:ws"):       tombstone("ws", now.Add(time.Hour)),
+		logicalcluster.New("root:org:expired"):  tombstone("expired", now.Add(-time.Hour)),
+		logicalcluster.New("root:org:restored"): tombstone("restored", now.Add(time.Hour)),
+	}
+	workspaces := map[logicalcluster.Name]*tenancyv1alpha1.ClusterWorkspace{
+		logicalcluster.New("root:org:existing"): workspace("existing", nil),
+		logicalcluster.New("root:org:restored"): workspace("restored", restored),
+	}
+	deleting := workspace("ws", nil)
+	deleting.DeletionTimestamp = &metav1.Time{Time: now}
+	deleting.Finalizers = []string{"tenancy.kcp.dev/workspace"}
+	finalized := deleting.DeepCopy()
+	finalized.Finalizers = nil
+
+	tests := map[string]struct {
+		obj, old  runtime.Object
+		cluster   string
+		user      string
+		operation admission.Operation
+		wantError string
+	}{
+		"write to a workspace without tombstone":     {cluster: "root:org:other", operation: admission.Create},
+		"write to a deleted workspace":               {cluster: "root:org:ws", operation: admission.Create, wantError: "workspace root:org:ws has been deleted"},
+		"delete in a deleted workspace":              {cluster: "root:org:ws", operation: admission.Delete, wantError: "workspace root:org:ws has been deleted"},
+		"delete in an expired workspace":             {cluster: "root:org:expired", operation: admission.Delete},
+		"write to an expired workspace":              {cluster: "root:org:expired", operation: admission.Update, wantError: "workspace root:org:expired has been deleted"},
+		"finalize in an expired workspace":           {obj: finalized, old: deleting, cluster: "root:org:expired", operation: admission.Update},
+		"finalize in a deleted workspace":            {obj: finalized, old: deleting, cluster: "root:org:ws", operation: admission.Update, wantError: "workspace root:org:ws has been deleted"},
+		"write to a restored workspace":              {cluster: "root:org:restored", operation: admission.Create},
+		"restore":                                    {obj: workspace("ws", restored), cluster: "root:org", user: "admin", operation: admission.Create},
+		"restore without undelete":                   {obj: workspace("ws", restored), cluster: "root:org", user: "user", operation: admission.Create, wantError: "can only be restored by users allowed to undelete"},
+		"restore without annotation":                 {obj: workspace("ws", nil), cluster: "root:org", user: "admin", operation: admission.Create, wantError: "must be \"tombstone-uid\""},
+		"annotation without tombstone":               {obj: workspace("ws", restored), cluster: "root:other", user: "admin", operation: admission.Create, wantError: "can only be set when restoring a deleted workspace"},
+		"annotation added on update":                 {obj: workspace("ws", restored), old: workspace("ws", nil), cluster: "root:other", user: "admin", operation: admission.Update, wantError: "is immutable"},
+		"annotation kept on update":                  {obj: workspace("ws", restored), old: workspace("ws", restored), cluster: "root:other", user: "user", operation: admission.Update},
+		"tombstone of an existing workspace":         {obj: tombstone("existing", now), cluster: "root:org", operation: admission.Create, wantError: "exists and is not being deleted"},
+		"tombstone of a workspace without workspace": {obj: tombstone("gone", now), cluster: "root:org", operation: admission.Create},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := newPlugin(tombstones, workspaces)
+			ctx, a := attributes(tt.obj, tt.old, tt.cluster, tt.user, tt.operation)
+
+			err := p.Validate(ctx, a, nil)
+			if tt.wantError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantError)
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -67,11 +68,13 @@ func Register(plugins *admission.Plugins) {
 // clusterWorkspaceTypeExists does the following
 //   - it checks existence of ClusterWorkspaceType in the same workspace,
 //   - it applies the ClusterWorkspaceType initializers to the ClusterWorkspace when it
-//     transitions to the Initializing state.
+//     transitions to the Initializing state, including the system:templates initializer
+//     if there are ClusterWorkspaceTemplates for the types.
 type clusterWorkspaceTypeExists struct {
 	*admission.Handler
 	typeLister             tenancyv1alpha1listers.ClusterWorkspaceTypeClusterLister
 	workspaceLister        tenancyv1alpha1listers.ClusterWorkspaceClusterLister
+	templateLister         tenancyv1alpha1listers.ClusterWorkspaceTemplateClusterLister
 	deepSARClient          kcpkubernetesclientset.ClusterInterface
 	transitiveTypeResolver *transitiveTypeResolver
 
@@ -166,6 +169,11 @@ func (o *clusterWorkspaceTypeExists) Admit(ctx context.Context, a admission.Attr
 		return nil
 	}
 
+	// restored workspaces have been initialized before they were deleted
+	if _, restored := cw.Annotations[tenancyv1alpha1.ClusterWorkspaceRestoredFromAnnotationKey]; restored {
+		return nil
+	}
+
 	// add initializers from type and aliases to workspace
 	cwt, err := o.resolveTypeRef(clusterName, cw.Spec.Type)
 	if err != nil {
@@ -182,11 +190,32 @@ func (o *clusterWorkspaceTypeExists) Admit(ctx context.Context, a admission.Attr
 		if len(alias.Spec.DefaultAPIBindings) > 0 {
 			cw.Status.Initializers = initialization.EnsureInitializerPresent(tenancyv1alpha1.ClusterWorkspaceAPIBindingsInitializer, cw.Status.Initializers)
 		}
+		hasTemplates, err := o.hasTemplates(alias)
+		if err != nil {
+			return admission.NewForbidden(a, err)
+		}
+		if hasTemplates {
+			cw.Status.Initializers = initialization.EnsureInitializerPresent(tenancyv1alpha1.ClusterWorkspaceTemplatesInitializer, cw.Status.Initializers)
+		}
 	}
 
 	return updateUnstructured(u, cw)
 }
 
+// hasTemplates returns whether there is a ClusterWorkspaceTemplate for the given type in its workspace.
+func (o *clusterWorkspaceTypeExists) hasTemplates(cwt *tenancyv1alpha1.ClusterWorkspaceType) (bool, error) {
+	templates, err := o.templateLister.Cluster(logicalcluster.From(cwt)).List(labels.Everything())
+	if err != nil {
+		return false, err
+	}
+	for _, template := range templates {
+		if template.Spec.Type == tenancyv1alpha1.ClusterWorkspaceTypeName(cwt.Name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (o *clusterWorkspaceTypeExists) resolveTypeRef(clusterName logicalcluster.Name, ref tenancyv1alpha1.ClusterWorkspaceTypeReference) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
 	if ref.Path != "" {
 		cwt, err := o.typeLister.Cluster(logicalcluster.New(ref.Path)).Get(tenancyv1alpha1.ObjectName(ref.Name))
@@ -309,8 +338,9 @@ func (o *clusterWorkspaceTypeExists) Validate(ctx context.Context, a admission.A
 		}
 	}
 
-	// check initializer from type exist
-	if a.GetOperation() == admission.Update && transitioningToInitializing {
+	// check initializer from type exist, unless the workspace is restored and was initialized before
+	_, restored := cw.Annotations[tenancyv1alpha1.ClusterWorkspaceRestoredFromAnnotationKey]
+	if a.GetOperation() == admission.Update && transitioningToInitializing && !restored {
 		// this is a transition to initializing. Check that all initializers are there
 		// (no other admission plugin removed any).
 		for _, alias := range cwtAliases {
@@ -382,17 +412,22 @@ func (o *clusterWorkspaceTypeExists) ValidateInitialization() error {
 	if o.workspaceLister == nil {
 		return fmt.Errorf(PluginName + " plugin needs an ClusterWorkspace lister")
 	}
+	if o.templateLister == nil {
+		return fmt.Errorf(PluginName + " plugin needs an ClusterWorkspaceTemplate lister")
+	}
 	return nil
 }
 
 func (o *clusterWorkspaceTypeExists) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
 	typesReady := informers.Tenancy().V1alpha1().ClusterWorkspaceTypes().Informer().HasSynced
 	workspacesReady := informers.Tenancy().V1alpha1().ClusterWorkspaces().Informer().HasSynced
+	templatesReady := informers.Tenancy().V1alpha1().ClusterWorkspaceTemplates().Informer().HasSynced
 	o.SetReadyFunc(func() bool {
-		return typesReady() && workspacesReady()
+		return typesReady() && workspacesReady() && templatesReady()
 	})
 	o.typeLister = informers.Tenancy().V1alpha1().ClusterWorkspaceTypes().Lister()
 	o.workspaceLister = informers.Tenancy().V1alpha1().ClusterWorkspaces().Lister()
+	o.templateLister = informers.Tenancy().V1alpha1().ClusterWorkspaceTemplates().Lister()
 }
 
 func (o *clusterWorkspaceTypeExists) SetDeepSARClient(client kcpkubernetesclientset.ClusterInterface) {
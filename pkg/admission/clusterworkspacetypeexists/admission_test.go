@@ -80,6 +80,7 @@ func TestAdmit(t *testing.T) {
 		name        string
 		types       []*tenancyv1alpha1.ClusterWorkspaceType
 		workspaces  []*tenancyv1alpha1.ClusterWorkspace
+		templates   []*tenancyv1alpha1.ClusterWorkspaceTemplate
 		clusterName logicalcluster.Name
 		a           admission.Attributes
 		expectedObj runtime.Object
@@ -110,6 +111,35 @@ func TestAdmit(t *testing.T) {
 				BaseURL:      "https://kcp.bigcorp.com/clusters/org:test",
 			}).ClusterWorkspace,
 		},
+		{
+			name: "adds templates initializer during transition to initializing when a type has templates",
+			types: []*tenancyv1alpha1.ClusterWorkspaceType{
+				newType("root:org:other").ClusterWorkspaceType,
+				newType("root:org:foo").extending("root:org:other").ClusterWorkspaceType,
+			},
+			templates: []*tenancyv1alpha1.ClusterWorkspaceTemplate{
+				newTemplate("root:other:unrelated", "foo"),
+				newTemplate("root:org:bar", "other"),
+			},
+			clusterName: logicalcluster.New("root:org:ws"),
+			a: updateAttr(
+				newWorkspace("root:org:ws:test").withType("root:org:foo").withStatus(tenancyv1alpha1.ClusterWorkspaceStatus{
+					Phase:    tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+					Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "somewhere"},
+					BaseURL:  "https://kcp.bigcorp.com/clusters/org:test",
+				}).ClusterWorkspace,
+				newWorkspace("root:org:ws:test").withType("root:org:foo").withStatus(tenancyv1alpha1.ClusterWorkspaceStatus{
+					Phase:        tenancyv1alpha1.ClusterWorkspacePhaseScheduling,
+					Initializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{},
+				}).ClusterWorkspace,
+			),
+			expectedObj: newWorkspace("root:org:ws:test").withType("root:org:foo").withStatus(tenancyv1alpha1.ClusterWorkspaceStatus{
+				Phase:        tenancyv1alpha1.ClusterWorkspacePhaseInitializing,
+				Initializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{tenancyv1alpha1.ClusterWorkspaceTemplatesInitializer},
+				Location:     tenancyv1alpha1.ClusterWorkspaceLocation{Current: "somewhere"},
+				BaseURL:      "https://kcp.bigcorp.com/clusters/org:test",
+			}).ClusterWorkspace,
+		},
 		{
 			name: "does not add initializer during transition to initializing when type has none",
 			types: []*tenancyv1alpha1.ClusterWorkspaceType{
@@ -307,10 +337,16 @@ func TestAdmit(t *testing.T) {
 				}
 				allWorkspaces[cluster] = append(allWorkspaces[cluster], t)
 			}
+			allTemplates := map[logicalcluster.Name][]*tenancyv1alpha1.ClusterWorkspaceTemplate{}
+			for _, t := range tt.templates {
+				cluster := logicalcluster.From(t)
+				allTemplates[cluster] = append(allTemplates[cluster], t)
+			}
 			o := &clusterWorkspaceTypeExists{
 				Handler:         admission.NewHandler(admission.Create, admission.Update),
 				typeLister:      typeLister,
 				workspaceLister: fakeClusterWorkspaceClusterLister(allWorkspaces),
+				templateLister:  fakeClusterWorkspaceTemplateClusterLister(allTemplates),
 				transitiveTypeResolver: NewTransitiveTypeResolver(func(cluster logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
 					return typeLister.Cluster(cluster).Get(name)
 				}),
@@ -692,6 +728,35 @@ func (l fakeClusterWorkspaceLister) Get(name string) (*tenancyv1alpha1.ClusterWo
 	return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspace"), name)
 }
 
+type fakeClusterWorkspaceTemplateClusterLister map[logicalcluster.Name][]*tenancyv1alpha1.ClusterWorkspaceTemplate
+
+func (l fakeClusterWorkspaceTemplateClusterLister) List(selector labels.Selector) (ret []*tenancyv1alpha1.ClusterWorkspaceTemplate, err error) {
+	var all []*tenancyv1alpha1.ClusterWorkspaceTemplate
+	for _, items := range l {
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+func (l fakeClusterWorkspaceTemplateClusterLister) Cluster(cluster logicalcluster.Name) tenancyv1alpha1listers.ClusterWorkspaceTemplateLister {
+	return fakeClusterWorkspaceTemplateLister(l[cluster])
+}
+
+type fakeClusterWorkspaceTemplateLister []*tenancyv1alpha1.ClusterWorkspaceTemplate
+
+func (l fakeClusterWorkspaceTemplateLister) List(selector labels.Selector) (ret []*tenancyv1alpha1.ClusterWorkspaceTemplate, err error) {
+	return l, nil
+}
+
+func (l fakeClusterWorkspaceTemplateLister) Get(name string) (*tenancyv1alpha1.ClusterWorkspaceTemplate, error) {
+	for _, t := range l {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacetemplate"), name)
+}
+
 type fakeAuthorizer struct {
 	authorized authorizer.Decision
 	err        error
@@ -947,6 +1012,23 @@ func newType(qualifiedName string) builder {
 	}}
 }
 
+// newTemplate returns a template named after the last segment of the qualified name, in the
+// workspace of the rest, for the given type.
+func newTemplate(qualifiedName, typeName string) *tenancyv1alpha1.ClusterWorkspaceTemplate {
+	path, name := logicalcluster.New(qualifiedName).Split()
+	return &tenancyv1alpha1.ClusterWorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: path.String(),
+			},
+		},
+		Spec: tenancyv1alpha1.ClusterWorkspaceTemplateSpec{
+			Type: tenancyv1alpha1.ClusterWorkspaceTypeName(typeName),
+		},
+	}
+}
+
 func (b builder) extending(qualifiedName string) builder {
 	path, name := logicalcluster.New(qualifiedName).Split()
 	b.Spec.Extend.With = append(b.Spec.Extend.With, tenancyv1alpha1.ClusterWorkspaceTypeReference{Path: path.String(), Name: tenancyv1alpha1.ClusterWorkspaceTypeName(name)})
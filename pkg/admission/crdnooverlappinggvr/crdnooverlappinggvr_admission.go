@@ -69,7 +69,8 @@ func (p *crdNoOverlappingGVRAdmission) ValidateInitialization() error {
 	return nil
 }
 
-// Validate checks if the given CRD's Group and Resource don't overlap with bound CRDs
+// Validate checks if the given CRD's Group and Resource don't overlap with bound CRDs,
+// unless the APIBinding allows to be shadowed by CRDs of its workspace.
 func (p *crdNoOverlappingGVRAdmission) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
 	if a.GetResource().GroupResource() != apiextensions.Resource("customresourcedefinitions") {
 		return nil
@@ -95,6 +96,10 @@ func (p *crdNoOverlappingGVRAdmission) Validate(ctx context.Context, a admission
 		return err
 	}
 	for _, apiBindingForCurrentClusterName := range apiBindingsForCurrentClusterName {
+		if apibinding.ShadowedByLocalCRDs(apiBindingForCurrentClusterName) {
+			// the CRD deliberately shadows the resources bound by this APIBinding
+			continue
+		}
 		for _, boundResource := range apiBindingForCurrentClusterName.Status.BoundResources {
 			if boundResource.Group == crd.Spec.Group && boundResource.Resource == crd.Spec.Names.Plural {
 				return admission.NewForbidden(a, fmt.Errorf("cannot create %q CustomResourceDefinition with %q group and %q resource because it overlaps with a bound CustomResourceDefinition for %q APIBinding in %q logical cluster",
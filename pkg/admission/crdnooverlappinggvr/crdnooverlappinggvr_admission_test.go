@@ -71,6 +71,23 @@ func TestValidate(t *testing.T) {
 			initialObjects: []runtime.Object{createBinding("foo1", "root:acme", []apisv1alpha1.BoundAPIResource{{Group: "acme.dev", Resource: "foo"}})},
 		},
 
+		{
+			name: "creating a CRD shadowing an APIBinding with the Shadow local CRD policy is allowed",
+			attr: createAttr(&apiextensions.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: apiextensions.CustomResourceDefinitionSpec{
+					Group: "acme.dev",
+					Names: apiextensions.CustomResourceDefinitionNames{Plural: "foo"},
+				},
+			}),
+			clusterName: "root:acme",
+			initialObjects: []runtime.Object{func() runtime.Object {
+				binding := createBinding("foo1", "root:acme", []apisv1alpha1.BoundAPIResource{{Group: "acme.dev", Resource: "foo"}})
+				binding.Spec.LocalCRDPolicy = apisv1alpha1.APIBindingLocalCRDPolicyShadow
+				return binding
+			}()},
+		},
+
 		{
 			name: "creating a non-conflicting CRD is allowed",
 			attr: createAttr(&apiextensions.CustomResourceDefinition{
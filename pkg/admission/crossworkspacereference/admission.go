@@ -0,0 +1,198 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossworkspacereference
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/admission/initializers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/authorization/delegated"
+	"github.com/kcp-dev/kcp/pkg/reference"
+)
+
+const (
+	PluginName = "kcp.dev/CrossWorkspaceReference"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(_ io.Reader) (admission.Interface, error) {
+		return &crossWorkspaceReference{
+			Handler:          admission.NewHandler(admission.Create, admission.Update),
+			createAuthorizer: delegated.NewDelegatedAuthorizer,
+			referencesFuncs:  referencesFuncs,
+		}, nil
+	})
+}
+
+// checkedReference is a reference of an object, and the verb the user creating the reference must be
+// allowed to use on the referenced object.
+type checkedReference struct {
+	field *field.Path
+	ref   tenancyv1alpha1.WorkspaceObjectReference
+	verb  string
+}
+
+// referencesFunc returns the references of an object that are checked by this plugin.
+type referencesFunc func(u *unstructured.Unstructured) ([]checkedReference, error)
+
+// referencesFuncs are the resources whose references are checked by this plugin. APIBindings are
+// checked by the APIBinding admission plugin, including references within the same workspace.
+var referencesFuncs = map[schema.GroupResource]referencesFunc{
+	workloadv1alpha1.Resource("synctargets"): syncTargetReferences,
+}
+
+// crossWorkspaceReference rejects the creation of references to objects in other workspaces that the
+// requesting user has no access to, through a SubjectAccessReview in the workspace of the referenced
+// object. Otherwise, a user could make kcp act on objects of workspaces they cannot access, just by
+// naming them in an object of their own workspace.
+//
+// On update, only references that are new are checked.
+type crossWorkspaceReference struct {
+	*admission.Handler
+
+	deepSARClient    kcpkubernetesclientset.ClusterInterface
+	createAuthorizer delegated.DelegatedAuthorizerFactory
+	referencesFuncs  map[schema.GroupResource]referencesFunc
+}
+
+var _ admission.ValidationInterface = &crossWorkspaceReference{}
+var _ admission.InitializationValidator = &crossWorkspaceReference{}
+var _ = initializers.WantsDeepSARClient(&crossWorkspaceReference{})
+
+func (p *crossWorkspaceReference) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetSubresource() != "" {
+		return nil
+	}
+	referencesOf, found := p.referencesFuncs[a.GetResource().GroupResource()]
+	if !found {
+		return nil
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	refs, err := referencesOf(u)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+
+	existing := map[tenancyv1alpha1.WorkspaceObjectReference]bool{}
+	if a.GetOperation() == admission.Update {
+		old, ok := a.GetOldObject().(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected type %T", a.GetOldObject())
+		}
+		oldRefs, err := referencesOf(old)
+		if err != nil {
+			return admission.NewForbidden(a, err)
+		}
+		for _, r := range oldRefs {
+			existing[r.ref] = true
+		}
+	}
+
+	for _, r := range refs {
+		refClusterName := reference.ClusterFor(clusterName, r.ref)
+		if refClusterName == clusterName || existing[r.ref] {
+			continue
+		}
+		if err := p.authorize(ctx, a, refClusterName, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *crossWorkspaceReference) authorize(ctx context.Context, a admission.Attributes, refClusterName logicalcluster.Name, r checkedReference) error {
+	authz, err := p.createAuthorizer(refClusterName, p.deepSARClient)
+	if err != nil {
+		// Logging a more specific error for the operator
+		klog.FromContext(ctx).Error(err, "error creating authorizer from delegating authorizer config")
+		// Returning a less specific error to the end user
+		return admission.NewForbidden(a, errors.New("unable to authorize request"))
+	}
+
+	allowed, err := reference.Authorize(ctx, authz, a.GetUserInfo(), r.ref, r.verb)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+	if !allowed {
+		gr := schema.GroupResource{Group: r.ref.Group, Resource: r.ref.Resource}
+		return admission.NewForbidden(a, field.Forbidden(r.field, fmt.Sprintf("no permission to %s %s %s|%s", r.verb, gr, refClusterName, r.ref.Name)))
+	}
+	return nil
+}
+
+// syncTargetReferences returns the references of a SyncTarget to the APIExports it supports. Supporting
+// an APIExport of another workspace requires the permission to bind it.
+func syncTargetReferences(u *unstructured.Unstructured) ([]checkedReference, error) {
+	syncTarget := &workloadv1alpha1.SyncTarget{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, syncTarget); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to SyncTarget: %w", err)
+	}
+
+	var refs []checkedReference
+	for i, export := range syncTarget.Spec.SupportedAPIExports {
+		if export.Workspace == nil {
+			continue
+		}
+		refs = append(refs, checkedReference{
+			field: field.NewPath("spec", "supportedAPIExports").Index(i).Child("workspace"),
+			ref:   reference.ForAPIExport(*export.Workspace),
+			verb:  "bind",
+		})
+	}
+	return refs, nil
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (p *crossWorkspaceReference) ValidateInitialization() error {
+	if p.deepSARClient == nil {
+		return fmt.Errorf(PluginName + " plugin needs a Kubernetes ClusterInterface")
+	}
+	return nil
+}
+
+// SetDeepSARClient is an admission plugin initializer function that injects a client capable of deep SAR requests into
+// this admission plugin.
+func (p *crossWorkspaceReference) SetDeepSARClient(client kcpkubernetesclientset.ClusterInterface) {
+	p.deepSARClient = client
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossworkspacereference
+
+import (
+	"context"
+	"testing"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+// fakeAuthorizer allows binding the APIExports in its set.
+type fakeAuthorizer struct {
+	clusterName logicalcluster.Name
+	allowed     map[string]bool
+}
+
+func (a *fakeAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	if attr.GetVerb() == "bind" && attr.GetAPIGroup() == apisv1alpha1.SchemeGroupVersion.Group && attr.GetResource() == "apiexports" && a.allowed[a.clusterName.String()+"|"+attr.GetName()] {
+		return authorizer.DecisionAllow, "", nil
+	}
+	return authorizer.DecisionNoOpinion, "", nil
+}
+
+func syncTarget(exports ...apisv1alpha1.WorkspaceExportReference) *workloadv1alpha1.SyncTarget {
+	st := &workloadv1alpha1.SyncTarget{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	for i := range exports {
+		st.Spec.SupportedAPIExports = append(st.Spec.SupportedAPIExports, apisv1alpha1.ExportReference{Workspace: &exports[i]})
+	}
+	return st
+}
+
+func TestValidate(t *testing.T) {
+	kubernetes := apisv1alpha1.WorkspaceExportReference{ExportName: "kubernetes"}
+	allowed := apisv1alpha1.WorkspaceExportReference{Path: "root:compute", ExportName: "kubernetes"}
+	forbidden := apisv1alpha1.WorkspaceExportReference{Path: "root:other", ExportName: "kubernetes"}
+	local := apisv1alpha1.WorkspaceExportReference{Path: "root:org:ws", ExportName: "local"}
+
+	tests := map[string]struct {
+		obj, old  runtime.Object
+		wantError string
+	}{
+		"same workspace":                  {obj: syncTarget(kubernetes, local)},
+		"other workspace with access":     {obj: syncTarget(allowed)},
+		"other workspace without access":  {obj: syncTarget(kubernetes, forbidden), wantError: `spec.supportedAPIExports[1].workspace: Forbidden: no permission to bind apiexports.apis.kcp.dev root:other|kubernetes`},
+		"existing reference on update":    {obj: syncTarget(forbidden, kubernetes), old: syncTarget(forbidden)},
+		"new reference on update":         {obj: syncTarget(allowed, forbidden), old: syncTarget(allowed), wantError: "no permission to bind"},
+		"unrelated resources are ignored": {obj: &apisv1alpha1.APIBinding{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &crossWorkspaceReference{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				createAuthorizer: func(clusterName logicalcluster.Name, client kcpkubernetesclientset.ClusterInterface) (authorizer.Authorizer, error) {
+					return &fakeAuthorizer{clusterName: clusterName, allowed: map[string]bool{"root:compute|kubernetes": true}}, nil
+				},
+				referencesFuncs: referencesFuncs,
+			}
+
+			resource := workloadv1alpha1.SchemeGroupVersion.WithResource("synctargets")
+			kind := workloadv1alpha1.SchemeGroupVersion.WithKind("SyncTarget")
+			if _, ok := tt.obj.(*apisv1alpha1.APIBinding); ok {
+				resource = apisv1alpha1.SchemeGroupVersion.WithResource("apibindings")
+				kind = apisv1alpha1.SchemeGroupVersion.WithKind("APIBinding")
+			}
+			operation, options := admission.Create, runtime.Object(&metav1.CreateOptions{})
+			var old runtime.Object
+			if tt.old != nil {
+				operation, options = admission.Update, &metav1.UpdateOptions{}
+				old = helpers.ToUnstructuredOrDie(tt.old)
+			}
+			attr := admission.NewAttributesRecord(helpers.ToUnstructuredOrDie(tt.obj), old, kind, "", "cluster", resource, "", operation, options, false, &user.DefaultInfo{Name: "user"})
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org:ws")})
+
+			err := p.Validate(ctx, attr, nil)
+			if tt.wantError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantError)
+		})
+	}
+}
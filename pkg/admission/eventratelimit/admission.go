@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventratelimit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1/helper"
+)
+
+const (
+	// PluginName is the name of this admission plugin.
+	PluginName = "WorkspaceEventRateLimit"
+
+	// limiterCacheSize bounds the number of logical clusters a rate limiter is kept for.
+	limiterCacheSize = 10000
+	// limiterCacheTTL drops the rate limiters of logical clusters that stopped emitting events, i.e.
+	// did not create any event for that long.
+	limiterCacheTTL = 10 * time.Minute
+)
+
+// Configuration is the configuration of the WorkspaceEventRateLimit plugin, read from the
+// admission control config file.
+type Configuration struct {
+	// EventsPerMinute is the sustained number of events a tenant logical cluster can create per minute.
+	EventsPerMinute int `json:"eventsPerMinute,omitempty"`
+	// Burst is the number of events a tenant logical cluster can create at once.
+	Burst int `json:"burst,omitempty"`
+}
+
+// DefaultConfiguration returns the configuration used if none is given.
+func DefaultConfiguration() *Configuration {
+	return &Configuration{
+		EventsPerMinute: 600,
+		Burst:           100,
+	}
+}
+
+// Register registers the WorkspaceEventRateLimit admission plugin.
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(config io.Reader) (admission.Interface, error) {
+		cfg, err := loadConfiguration(config)
+		if err != nil {
+			return nil, err
+		}
+		return NewEventRateLimit(cfg), nil
+	})
+}
+
+func loadConfiguration(config io.Reader) (*Configuration, error) {
+	cfg := DefaultConfiguration()
+	if config == nil {
+		return cfg, nil
+	}
+
+	bs, err := io.ReadAll(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s configuration: %w", PluginName, err)
+	}
+	if err := yaml.Unmarshal(bs, cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode %s configuration: %w", PluginName, err)
+	}
+	if cfg.EventsPerMinute <= 0 {
+		return nil, fmt.Errorf("%s: eventsPerMinute must be >0, got %d", PluginName, cfg.EventsPerMinute)
+	}
+	if cfg.Burst <= 0 {
+		return nil, fmt.Errorf("%s: burst must be >0, got %d", PluginName, cfg.Burst)
+	}
+	return cfg, nil
+}
+
+// eventRateLimit caps the rate of events created per tenant logical cluster. Events are
+// best-effort, and a single noisy workspace must not be able to fill etcd with them.
+type eventRateLimit struct {
+	*admission.Handler
+
+	config *Configuration
+
+	lock     sync.Mutex
+	limiters *utilcache.LRUExpireCache
+
+	newLimiter func() flowcontrol.RateLimiter
+}
+
+var _ = admission.ValidationInterface(&eventRateLimit{})
+
+// NewEventRateLimit returns a WorkspaceEventRateLimit admission plugin for the given configuration.
+func NewEventRateLimit(config *Configuration) admission.ValidationInterface {
+	return &eventRateLimit{
+		Handler:  admission.NewHandler(admission.Create),
+		config:   config,
+		limiters: utilcache.NewLRUExpireCache(limiterCacheSize),
+		newLimiter: func() flowcontrol.RateLimiter {
+			return flowcontrol.NewTokenBucketRateLimiter(float32(config.EventsPerMinute)/60, config.Burst)
+		},
+	}
+}
+
+var eventResources = map[schema.GroupResource]bool{
+	{Group: "", Resource: "events"}:              true,
+	{Group: "events.k8s.io", Resource: "events"}: true,
+}
+
+// Validate rejects events of tenant logical clusters exceeding the configured rate.
+func (p *eventRateLimit) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if !eventResources[a.GetResource().GroupResource()] || a.GetSubresource() != "" {
+		return nil
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if !helper.IsTenantCluster(clusterName) {
+		return nil
+	}
+
+	if !p.limiterFor(clusterName).TryAccept() {
+		return apierrors.NewTooManyRequestsError(fmt.Sprintf("workspace %s exceeded its limit of %d events per minute", clusterName, p.config.EventsPerMinute))
+	}
+	return nil
+}
+
+func (p *eventRateLimit) limiterFor(cluster logicalcluster.Name) flowcontrol.RateLimiter {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := cluster.String()
+	var limiter flowcontrol.RateLimiter
+	if existing, ok := p.limiters.Get(key); ok {
+		limiter = existing.(flowcontrol.RateLimiter)
+	} else {
+		limiter = p.newLimiter()
+	}
+
+	// add on every event to only expire the limiters of idle clusters. Otherwise a cluster
+	// creating events continuously would get a fresh burst every limiterCacheTTL.
+	p.limiters.Add(key, limiter, limiterCacheTTL)
+	return limiter
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventratelimit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func createAttr(resource schema.GroupVersionResource) admission.Attributes {
+	return admission.NewAttributesRecord(
+		&corev1.Event{},
+		nil,
+		corev1.SchemeGroupVersion.WithKind("Event"),
+		"default",
+		"event",
+		resource,
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func TestValidate(t *testing.T) {
+	events := corev1.SchemeGroupVersion.WithResource("events")
+	configMaps := corev1.SchemeGroupVersion.WithResource("configmaps")
+
+	tests := map[string]struct {
+		cluster  logicalcluster.Name
+		resource schema.GroupVersionResource
+		rejected int
+	}{
+		"tenant events over the burst are rejected": {
+			cluster:  logicalcluster.New("root:org:ws"),
+			resource: events,
+			rejected: 2,
+		},
+		"events.k8s.io events count too": {
+			cluster:  logicalcluster.New("root:org:ws"),
+			resource: schema.GroupVersionResource{Group: "events.k8s.io", Version: "v1", Resource: "events"},
+			rejected: 2,
+		},
+		"root events are not limited": {
+			cluster:  logicalcluster.New("root"),
+			resource: events,
+		},
+		"system events are not limited": {
+			cluster:  logicalcluster.New("system:admin"),
+			resource: events,
+		},
+		"other resources are not limited": {
+			cluster:  logicalcluster.New("root:org:ws"),
+			resource: configMaps,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			plugin := NewEventRateLimit(&Configuration{EventsPerMinute: 1, Burst: 3})
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: tc.cluster})
+
+			rejected := 0
+			for i := 0; i < 5; i++ {
+				if err := plugin.Validate(ctx, createAttr(tc.resource), nil); err != nil {
+					require.True(t, apierrors.IsTooManyRequests(err), "unexpected error: %v", err)
+					rejected++
+				}
+			}
+			require.Equal(t, tc.rejected, rejected)
+		})
+	}
+}
+
+func TestLimiterOutlivesTTLWhileBusy(t *testing.T) {
+	plugin := NewEventRateLimit(&Configuration{EventsPerMinute: 1, Burst: 3}).(*eventRateLimit)
+	clock := clocktesting.NewFakeClock(time.Now())
+	plugin.limiters = utilcache.NewLRUExpireCacheWithClock(limiterCacheSize, clock)
+	ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:org:ws")})
+	events := corev1.SchemeGroupVersion.WithResource("events")
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, plugin.Validate(ctx, createAttr(events), nil))
+	}
+
+	// keep sending beyond the TTL of the first limiter. The bucket must not be refilled by a new limiter.
+	for i := 0; i < 4; i++ {
+		clock.Step(limiterCacheTTL / 2)
+		err := plugin.Validate(ctx, createAttr(events), nil)
+		require.True(t, apierrors.IsTooManyRequests(err), "unexpected error: %v", err)
+	}
+
+	// an idle cluster gets a new limiter
+	clock.Step(limiterCacheTTL + time.Second)
+	require.NoError(t, plugin.Validate(ctx, createAttr(events), nil))
+}
+
+func TestLoadConfiguration(t *testing.T) {
+	cfg, err := loadConfiguration(nil)
+	require.NoError(t, err)
+	require.Equal(t, DefaultConfiguration(), cfg)
+
+	cfg, err = loadConfiguration(strings.NewReader("eventsPerMinute: 30\n"))
+	require.NoError(t, err)
+	require.Equal(t, &Configuration{EventsPerMinute: 30, Burst: 100}, cfg)
+
+	_, err = loadConfiguration(strings.NewReader("burst: 0\n"))
+	require.Error(t, err)
+}
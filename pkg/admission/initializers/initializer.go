@@ -19,12 +19,15 @@ package initializers
 import (
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 
+	kcpapiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/kcp/informers/externalversions"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/admission/initializer"
 	quota "k8s.io/apiserver/pkg/quota/v1"
 
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacequota"
 )
 
 // NewKcpInformersInitializer returns an admission plugin initializer that injects
@@ -47,6 +50,26 @@ func (i *kcpInformersInitializer) Initialize(plugin admission.Interface) {
 	}
 }
 
+// NewApiExtensionsInformersInitializer returns an admission plugin initializer that injects
+// the apiextensions shared informer factory into admission plugins.
+func NewApiExtensionsInformersInitializer(
+	apiExtensionsInformers kcpapiextensionsinformers.SharedInformerFactory,
+) *apiExtensionsInformersInitializer {
+	return &apiExtensionsInformersInitializer{
+		apiExtensionsInformers: apiExtensionsInformers,
+	}
+}
+
+type apiExtensionsInformersInitializer struct {
+	apiExtensionsInformers kcpapiextensionsinformers.SharedInformerFactory
+}
+
+func (i *apiExtensionsInformersInitializer) Initialize(plugin admission.Interface) {
+	if wants, ok := plugin.(WantsApiExtensionsInformers); ok {
+		wants.SetApiExtensionsInformers(i.apiExtensionsInformers)
+	}
+}
+
 // NewKubeClusterClientInitializer returns an admission plugin initializer that injects
 // a kube cluster client into admission plugins.
 func NewKubeClusterClientInitializer(
@@ -142,3 +165,95 @@ func (i *serverShutdownChannelInitializer) Initialize(plugin admission.Interface
 		wants.SetServerShutdownChannel(i.ch)
 	}
 }
+
+// NewDynamicDiscoverySharedInformerFactoryInitializer returns an admission plugin initializer that injects
+// the dynamic discovery shared informer factory into admission plugins.
+func NewDynamicDiscoverySharedInformerFactoryInitializer(
+	dynamicDiscoverySharedInformerFactory *informer.DynamicDiscoverySharedInformerFactory,
+) *dynamicDiscoverySharedInformerFactoryInitializer {
+	return &dynamicDiscoverySharedInformerFactoryInitializer{
+		dynamicDiscoverySharedInformerFactory: dynamicDiscoverySharedInformerFactory,
+	}
+}
+
+type dynamicDiscoverySharedInformerFactoryInitializer struct {
+	dynamicDiscoverySharedInformerFactory *informer.DynamicDiscoverySharedInformerFactory
+}
+
+func (i *dynamicDiscoverySharedInformerFactoryInitializer) Initialize(plugin admission.Interface) {
+	if wants, ok := plugin.(WantsDynamicDiscoverySharedInformerFactory); ok {
+		wants.SetDynamicDiscoverySharedInformerFactory(i.dynamicDiscoverySharedInformerFactory)
+	}
+}
+
+// NewShardNameInitializer returns an admission plugin initializer that injects
+// the name of this shard into admission plugins.
+func NewShardNameInitializer(shardName string) *shardNameInitializer {
+	return &shardNameInitializer{
+		shardName: shardName,
+	}
+}
+
+type shardNameInitializer struct {
+	shardName string
+}
+
+func (i *shardNameInitializer) Initialize(plugin admission.Interface) {
+	if wants, ok := plugin.(WantsShardName); ok {
+		wants.SetShardName(i.shardName)
+	}
+}
+
+// NewWorkspaceStorageUsageInitializer returns an admission plugin initializer that injects
+// the etcd storage of the workspaces of this shard into admission plugins.
+func NewWorkspaceStorageUsageInitializer(storageUsage *clusterworkspacequota.StorageUsage) *workspaceStorageUsageInitializer {
+	return &workspaceStorageUsageInitializer{
+		storageUsage: storageUsage,
+	}
+}
+
+type workspaceStorageUsageInitializer struct {
+	storageUsage *clusterworkspacequota.StorageUsage
+}
+
+func (i *workspaceStorageUsageInitializer) Initialize(plugin admission.Interface) {
+	if wants, ok := plugin.(WantsWorkspaceStorageUsage); ok {
+		wants.SetWorkspaceStorageUsage(i.storageUsage)
+	}
+}
+
+// NewWorkspaceObjectCountsInitializer returns an admission plugin initializer that injects
+// the object counts of the workspaces of this shard into admission plugins.
+func NewWorkspaceObjectCountsInitializer(objectCounts *clusterworkspacequota.ObjectCounts) *workspaceObjectCountsInitializer {
+	return &workspaceObjectCountsInitializer{
+		objectCounts: objectCounts,
+	}
+}
+
+type workspaceObjectCountsInitializer struct {
+	objectCounts *clusterworkspacequota.ObjectCounts
+}
+
+func (i *workspaceObjectCountsInitializer) Initialize(plugin admission.Interface) {
+	if wants, ok := plugin.(WantsWorkspaceObjectCounts); ok {
+		wants.SetWorkspaceObjectCounts(i.objectCounts)
+	}
+}
+
+// NewWorkspaceAuthenticationAllowPrivateNetworksInitializer returns an admission plugin initializer that injects
+// whether WorkspaceAuthenticationConfigurations may use loopback, link-local or private addresses.
+func NewWorkspaceAuthenticationAllowPrivateNetworksInitializer(allow bool) *workspaceAuthenticationAllowPrivateNetworksInitializer {
+	return &workspaceAuthenticationAllowPrivateNetworksInitializer{
+		allow: allow,
+	}
+}
+
+type workspaceAuthenticationAllowPrivateNetworksInitializer struct {
+	allow bool
+}
+
+func (i *workspaceAuthenticationAllowPrivateNetworksInitializer) Initialize(plugin admission.Interface) {
+	if wants, ok := plugin.(WantsWorkspaceAuthenticationAllowPrivateNetworks); ok {
+		wants.SetWorkspaceAuthenticationAllowPrivateNetworks(i.allow)
+	}
+}
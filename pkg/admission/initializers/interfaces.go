@@ -19,8 +19,12 @@ package initializers
 import (
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 
+	kcpapiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/kcp/informers/externalversions"
+
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacequota"
 )
 
 // WantsKcpInformers interface should be implemented by admission plugins
@@ -29,6 +33,12 @@ type WantsKcpInformers interface {
 	SetKcpInformers(kcpinformers.SharedInformerFactory)
 }
 
+// WantsApiExtensionsInformers interface should be implemented by admission plugins
+// that want to have an apiextensions informer factory injected.
+type WantsApiExtensionsInformers interface {
+	SetApiExtensionsInformers(kcpapiextensionsinformers.SharedInformerFactory)
+}
+
 // WantsKubeClusterClient interface should be implemented by admission plugins
 // that want to have a kube cluster client injected.
 type WantsKubeClusterClient interface {
@@ -53,3 +63,33 @@ type WantsDeepSARClient interface {
 type WantsServerShutdownChannel interface {
 	SetServerShutdownChannel(<-chan struct{})
 }
+
+// WantsDynamicDiscoverySharedInformerFactory interface should be implemented by admission plugins
+// that want to have the metadata-only informers of all resources injected.
+type WantsDynamicDiscoverySharedInformerFactory interface {
+	SetDynamicDiscoverySharedInformerFactory(*informer.DynamicDiscoverySharedInformerFactory)
+}
+
+// WantsShardName interface should be implemented by admission plugins
+// that want to know the name of the shard they run in.
+type WantsShardName interface {
+	SetShardName(string)
+}
+
+// WantsWorkspaceStorageUsage interface should be implemented by admission plugins
+// that want to know the etcd storage of the workspaces of the shard.
+type WantsWorkspaceStorageUsage interface {
+	SetWorkspaceStorageUsage(*clusterworkspacequota.StorageUsage)
+}
+
+// WantsWorkspaceObjectCounts interface should be implemented by admission plugins
+// that want to count the objects of the workspaces of the shard.
+type WantsWorkspaceObjectCounts interface {
+	SetWorkspaceObjectCounts(*clusterworkspacequota.ObjectCounts)
+}
+
+// WantsWorkspaceAuthenticationAllowPrivateNetworks interface should be implemented by admission plugins
+// that want to know whether workspace authentication may use loopback, link-local or private addresses.
+type WantsWorkspaceAuthenticationAllowPrivateNetworks interface {
+	SetWorkspaceAuthenticationAllowPrivateNetworks(bool)
+}
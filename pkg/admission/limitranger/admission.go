@@ -22,18 +22,26 @@ import (
 	"io"
 	"sync"
 
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
 	kcpkubernetesclient "github.com/kcp-dev/client-go/kubernetes"
 	kcpcorev1listers "github.com/kcp-dev/client-go/listers/core/v1"
 	"github.com/kcp-dev/logicalcluster/v2"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/clientsethack"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/apiserver/pkg/informerfactoryhack"
 	"k8s.io/client-go/informers"
 	kubernetesclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/kubernetes/plugin/pkg/admission/limitranger"
+
+	"github.com/kcp-dev/kcp/pkg/admission/initializers"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
 )
 
 const (
@@ -58,16 +66,27 @@ type workspaceLimitRanger struct {
 	client kcpkubernetesclient.ClusterInterface
 	lister kcpcorev1listers.LimitRangeClusterLister
 
-	lock      sync.RWMutex
+	lock sync.RWMutex
+	// delegates holds a LimitRanger per logical cluster. They are dropped when the ClusterWorkspace
+	// of the logical cluster is deleted.
 	delegates map[logicalcluster.Name]*limitranger.LimitRanger
 }
 
+var _ = initializers.WantsKcpInformers(&workspaceLimitRanger{})
+
 // SetExternalKubeInformerFactory implements the WantsExternalKubeInformerFactory interface.
 func (l *workspaceLimitRanger) SetExternalKubeInformerFactory(f informers.SharedInformerFactory) {
 	l.lister = informerfactoryhack.Unwrap(f).Core().V1().LimitRanges().Lister()
 	l.SetReadyFunc(informerfactoryhack.Unwrap(f).Core().V1().LimitRanges().Informer().HasSynced)
 }
 
+// SetKcpInformers implements the WantsKcpInformers interface.
+func (l *workspaceLimitRanger) SetKcpInformers(f kcpinformers.SharedInformerFactory) {
+	f.Tenancy().V1alpha1().ClusterWorkspaces().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: l.forgetDelegate,
+	})
+}
+
 // SetExternalKubeClientSet implements the WantsExternalKubeClientSet interface.
 func (l *workspaceLimitRanger) SetExternalKubeClientSet(client kubernetesclient.Interface) {
 	l.client = clientsethack.Unwrap(client)
@@ -90,6 +109,9 @@ func (l *workspaceLimitRanger) Admit(ctx context.Context, a admission.Attributes
 	if err != nil {
 		return apierrors.NewInternalError(err)
 	}
+	if u, ok := a.GetObject().(*unstructured.Unstructured); ok {
+		return l.admitUnstructured(clusterName, a, u)
+	}
 	delegate, err := l.delegateFor(clusterName)
 	if err != nil {
 		return apierrors.NewInternalError(err)
@@ -103,6 +125,10 @@ func (l *workspaceLimitRanger) Validate(ctx context.Context, a admission.Attribu
 	if err != nil {
 		return apierrors.NewInternalError(err)
 	}
+	if _, ok := a.GetObject().(*unstructured.Unstructured); ok {
+		// the upstream LimitRanger only understands typed objects
+		return nil
+	}
 	delegate, err := l.delegateFor(clusterName)
 	if err != nil {
 		return apierrors.NewInternalError(err)
@@ -134,5 +160,53 @@ func (l *workspaceLimitRanger) delegateFor(cluster logicalcluster.Name) (*limitr
 	}
 	delegate.SetExternalKubeClientSet(l.client.Cluster(cluster))
 	delegate.SetExternalKubeLister(l.lister.Cluster(cluster))
+	l.delegates[cluster] = delegate
 	return delegate, nil
 }
+
+// forgetDelegate drops the delegate of the logical cluster of a deleted ClusterWorkspace.
+func (l *workspaceLimitRanger) forgetDelegate(obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	parent, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	delete(l.delegates, parent.Join(name))
+}
+
+// admitUnstructured defaults the container resources of pod-spec-able resources served from CRDs
+// according to the LimitRanges in the namespace, before they are synced to a physical cluster.
+func (l *workspaceLimitRanger) admitUnstructured(cluster logicalcluster.Name, a admission.Attributes, u *unstructured.Unstructured) error {
+	if a.GetSubresource() != "" || a.GetNamespace() == "" {
+		return nil
+	}
+	podSpecPath, found := podSpecPaths[a.GetResource().GroupResource()]
+	if !found {
+		return nil
+	}
+	// containers of pods are immutable, hence only templates can be defaulted on update
+	if a.GetOperation() == admission.Update && len(podSpecPath) == 1 {
+		return nil
+	}
+
+	limitRanges, err := l.lister.Cluster(cluster).LimitRanges(a.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if len(limitRanges) == 0 {
+		return nil
+	}
+
+	if err := defaultResources(limitRanges, u, podSpecPath); err != nil {
+		return apierrors.NewBadRequest(fmt.Sprintf("failed to default container resources: %v", err))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limitranger
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/plugin/pkg/admission/limitranger"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestForgetDelegate(t *testing.T) {
+	l := &workspaceLimitRanger{
+		delegates: map[logicalcluster.Name]*limitranger.LimitRanger{
+			logicalcluster.New("root:org:ws"):    {},
+			logicalcluster.New("root:org:other"): {},
+		},
+	}
+	workspace := func(name string) *tenancyv1alpha1.ClusterWorkspace {
+		return &tenancyv1alpha1.ClusterWorkspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+			},
+		}
+	}
+
+	l.forgetDelegate(workspace("ws"))
+	require.NotContains(t, l.delegates, logicalcluster.New("root:org:ws"))
+	require.Contains(t, l.delegates, logicalcluster.New("root:org:other"))
+
+	l.forgetDelegate(cache.DeletedFinalStateUnknown{Key: "root:org|other", Obj: workspace("other")})
+	require.Empty(t, l.delegates)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limitranger
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// podSpecPaths are the paths of the pod specs in the pod-spec-able resources. In kcp these
+// resources are usually served from CRDs, i.e. they are admitted as unstructured objects
+// the upstream LimitRanger does not handle.
+var podSpecPaths = map[schema.GroupResource][]string{
+	{Resource: "pods"}:                        {"spec"},
+	{Resource: "podtemplates"}:                {"template", "spec"},
+	{Resource: "replicationcontrollers"}:      {"spec", "template", "spec"},
+	{Group: "apps", Resource: "deployments"}:  {"spec", "template", "spec"},
+	{Group: "apps", Resource: "replicasets"}:  {"spec", "template", "spec"},
+	{Group: "apps", Resource: "statefulsets"}: {"spec", "template", "spec"},
+	{Group: "apps", Resource: "daemonsets"}:   {"spec", "template", "spec"},
+	{Group: "batch", Resource: "jobs"}:        {"spec", "template", "spec"},
+	{Group: "batch", Resource: "cronjobs"}:    {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// defaultResources sets the default requests and limits of the container limit range items
+// on all containers of the pod spec at the given path which do not specify them.
+func defaultResources(limitRanges []*corev1.LimitRange, obj *unstructured.Unstructured, podSpecPath []string) error {
+	requests, limits := containerDefaults(limitRanges)
+	if len(requests) == 0 && len(limits) == 0 {
+		return nil
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		path := append(append([]string(nil), podSpecPath...), field)
+		containers, found, err := unstructured.NestedSlice(obj.Object, path...)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		for i := range containers {
+			container, ok := containers[i].(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("unexpected type %T of %v[%d]", containers[i], path, i)
+			}
+
+			var resources corev1.ResourceRequirements
+			if raw, found := container["resources"]; found && raw != nil {
+				rawMap, ok := raw.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("unexpected type %T of %v[%d].resources", raw, path, i)
+				}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawMap, &resources); err != nil {
+					return err
+				}
+			}
+
+			if !mergeDefaults(&resources, requests, limits) {
+				continue
+			}
+
+			raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&resources)
+			if err != nil {
+				return err
+			}
+			container["resources"] = raw
+		}
+
+		if err := unstructured.SetNestedSlice(obj.Object, containers, path...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// containerDefaults returns the default requests and limits of the container items of the
+// limit ranges. The first limit range by name defining a default for a resource wins.
+func containerDefaults(limitRanges []*corev1.LimitRange) (requests, limits corev1.ResourceList) {
+	limitRanges = append([]*corev1.LimitRange(nil), limitRanges...)
+	sort.Slice(limitRanges, func(i, j int) bool { return limitRanges[i].Name < limitRanges[j].Name })
+
+	requests, limits = corev1.ResourceList{}, corev1.ResourceList{}
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for k, v := range item.DefaultRequest {
+				if _, found := requests[k]; !found {
+					requests[k] = v.DeepCopy()
+				}
+			}
+			for k, v := range item.Default {
+				if _, found := limits[k]; !found {
+					limits[k] = v.DeepCopy()
+				}
+			}
+		}
+	}
+	return requests, limits
+}
+
+// mergeDefaults sets the default requests and limits not set in resources. Like pod defaulting,
+// a limit without request implies the same request. It returns whether resources changed.
+func mergeDefaults(resources *corev1.ResourceRequirements, requests, limits corev1.ResourceList) bool {
+	changed := false
+
+	for k, v := range resources.Limits {
+		if _, found := resources.Requests[k]; found {
+			continue
+		}
+		if resources.Requests == nil {
+			resources.Requests = corev1.ResourceList{}
+		}
+		resources.Requests[k] = v.DeepCopy()
+		changed = true
+	}
+
+	for k, v := range limits {
+		if _, found := resources.Limits[k]; found {
+			continue
+		}
+		if resources.Limits == nil {
+			resources.Limits = corev1.ResourceList{}
+		}
+		resources.Limits[k] = v.DeepCopy()
+		changed = true
+	}
+
+	for k, v := range requests {
+		if _, found := resources.Requests[k]; found {
+			continue
+		}
+		if resources.Requests == nil {
+			resources.Requests = corev1.ResourceList{}
+		}
+		resources.Requests[k] = v.DeepCopy()
+		changed = true
+	}
+
+	return changed
+}
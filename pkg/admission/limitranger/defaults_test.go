@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package limitranger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestDefaultResources(t *testing.T) {
+	limitRanges := []*corev1.LimitRange{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "b"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+				Type:           corev1.LimitTypeContainer,
+				Default:        corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+				DefaultRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+			}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "a"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{
+				{
+					Type:    corev1.LimitTypePod,
+					Default: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10")},
+				},
+				{
+					Type:           corev1.LimitTypeContainer,
+					Default:        corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+					DefaultRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				},
+			}},
+		},
+	}
+
+	tests := map[string]struct {
+		resource  schema.GroupResource
+		obj       map[string]interface{}
+		wantPaths map[string]string
+	}{
+		"deployment without resources": {
+			resource: schema.GroupResource{Group: "apps", Resource: "deployments"},
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "app"}},
+				}}},
+			},
+			wantPaths: map[string]string{
+				"limits.cpu":      "500m",
+				"limits.memory":   "1Gi",
+				"requests.cpu":    "100m",
+				"requests.memory": "512Mi",
+			},
+		},
+		"pod with explicit limit": {
+			resource: schema.GroupResource{Resource: "pods"},
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{
+						"name":      "app",
+						"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "50m"}},
+					}},
+				},
+			},
+			wantPaths: map[string]string{
+				"limits.cpu":      "50m",
+				"limits.memory":   "1Gi",
+				"requests.cpu":    "50m",
+				"requests.memory": "512Mi",
+			},
+		},
+		"cronjob with explicit request": {
+			resource: schema.GroupResource{Group: "batch", Resource: "cronjobs"},
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{"jobTemplate": map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{
+						"name":      "app",
+						"resources": map[string]interface{}{"requests": map[string]interface{}{"memory": "64Mi"}},
+					}},
+				}}}}},
+			},
+			wantPaths: map[string]string{
+				"limits.cpu":      "500m",
+				"limits.memory":   "1Gi",
+				"requests.cpu":    "100m",
+				"requests.memory": "64Mi",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			u := &unstructured.Unstructured{Object: tt.obj}
+			path := podSpecPaths[tt.resource]
+			require.NotEmpty(t, path)
+
+			err := defaultResources(limitRanges, u, path)
+			require.NoError(t, err)
+
+			containers, found, err := unstructured.NestedSlice(u.Object, append(path, "containers")...)
+			require.NoError(t, err)
+			require.True(t, found)
+			require.Len(t, containers, 1)
+
+			resources := containers[0].(map[string]interface{})["resources"].(map[string]interface{})
+			for p, want := range tt.wantPaths {
+				got, found, err := unstructured.NestedString(resources, strings.Split(p, ".")...)
+				require.NoError(t, err)
+				require.True(t, found, "%s not found", p)
+				require.Equal(t, resource.MustParse(want).String(), resource.MustParse(got).String(), p)
+			}
+		})
+	}
+}
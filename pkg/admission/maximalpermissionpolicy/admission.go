@@ -0,0 +1,220 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maximalpermissionpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/pkg/admission/initializers"
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+const (
+	PluginName = "apis.kcp.dev/MaximalPermissionPolicy"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(_ io.Reader) (admission.Interface, error) {
+		return NewMaximalPermissionPolicy(), nil
+	})
+}
+
+// maximalPermissionPolicy enforces the protected fields of the maximal permission policy of the APIExport a
+// resource is bound from. Protected fields cannot be set on creation or changed on update, except by members
+// of system:masters, like the APIExport virtual workspace writing on behalf of the service provider.
+//
+// The verbs permitted on bound resources are capped by the maximal permission policy authorizer. This plugin
+// complements it for what an authorizer cannot see, the content of the request.
+type maximalPermissionPolicy struct {
+	*admission.Handler
+
+	listAPIBindings func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	getAPIExport    func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)
+
+	apiBindingsHasSynced cache.InformerSynced
+	apiExportsHasSynced  cache.InformerSynced
+}
+
+var _ admission.ValidationInterface = &maximalPermissionPolicy{}
+var _ admission.InitializationValidator = &maximalPermissionPolicy{}
+var _ = initializers.WantsKcpInformers(&maximalPermissionPolicy{})
+
+// NewMaximalPermissionPolicy returns a new MaximalPermissionPolicy admission plugin.
+func NewMaximalPermissionPolicy() admission.ValidationInterface {
+	p := &maximalPermissionPolicy{
+		Handler: admission.NewHandler(admission.Create, admission.Update),
+	}
+	p.SetReadyFunc(func() bool {
+		return p.apiBindingsHasSynced() && p.apiExportsHasSynced()
+	})
+	return p
+}
+
+func (p *maximalPermissionPolicy) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetSubresource() != "" && a.GetSubresource() != "status" {
+		return nil
+	}
+	if a.GetUserInfo() != nil && sets.NewString(a.GetUserInfo().GetGroups()...).Has(user.SystemPrivilegedGroup) {
+		return nil
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	gr := a.GetResource().GroupResource()
+	protected, export, err := p.protectedFieldsFor(clusterName, gr)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if protected == nil {
+		return nil
+	}
+
+	obj, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	var old *unstructured.Unstructured
+	if a.GetOperation() == admission.Update {
+		if old, ok = a.GetOldObject().(*unstructured.Unstructured); !ok {
+			return fmt.Errorf("unexpected type %T", a.GetOldObject())
+		}
+	}
+
+	var errs field.ErrorList
+	for _, path := range protected.Paths {
+		fields := strings.Split(path, ".")
+		value, found, err := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+		if err != nil {
+			// not a map along the path, i.e. the field cannot be set
+			found = false
+		}
+
+		if old == nil {
+			if found {
+				errs = append(errs, field.Forbidden(field.NewPath(fields[0], fields[1:]...), fmt.Sprintf("is protected by APIExport %s|%s and must not be set", logicalcluster.From(export), export.Name)))
+			}
+			continue
+		}
+
+		oldValue, oldFound, err := unstructured.NestedFieldNoCopy(old.Object, fields...)
+		if err != nil {
+			oldFound = false
+		}
+		if found != oldFound || !equality.Semantic.DeepEqual(value, oldValue) {
+			errs = append(errs, field.Forbidden(field.NewPath(fields[0], fields[1:]...), fmt.Sprintf("is protected by APIExport %s|%s and must not be changed", logicalcluster.From(export), export.Name)))
+		}
+	}
+	if len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
+	}
+
+	return nil
+}
+
+// protectedFieldsFor returns the protected fields of the APIExport the given resource is bound from,
+// if the bound identity is still the one of the APIExport.
+func (p *maximalPermissionPolicy) protectedFieldsFor(clusterName logicalcluster.Name, gr schema.GroupResource) (*apisv1alpha1.ProtectedFields, *apisv1alpha1.APIExport, error) {
+	bindings, err := p.listAPIBindings(clusterName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, binding := range bindings {
+		if binding.Spec.Reference.Workspace == nil {
+			continue
+		}
+		for _, boundResource := range binding.Status.BoundResources {
+			if boundResource.Group != gr.Group || boundResource.Resource != gr.Resource {
+				continue
+			}
+
+			exportClusterName := clusterName
+			if path := binding.Spec.Reference.Workspace.Path; path != "" {
+				exportClusterName = logicalcluster.New(path)
+			}
+			export, err := p.getAPIExport(exportClusterName, binding.Spec.Reference.Workspace.ExportName)
+			if apierrors.IsNotFound(err) {
+				// the APIExport is gone or not on this shard
+				return nil, nil, nil
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			if export.Status.IdentityHash != boundResource.Schema.IdentityHash || export.Spec.MaximalPermissionPolicy == nil {
+				return nil, nil, nil
+			}
+
+			for i := range export.Spec.MaximalPermissionPolicy.ProtectedFields {
+				protected := &export.Spec.MaximalPermissionPolicy.ProtectedFields[i]
+				if protected.Group == gr.Group && protected.Resource == gr.Resource {
+					return protected, export, nil
+				}
+			}
+			return nil, nil, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// SetKcpInformers implements the WantsKcpInformers interface.
+func (p *maximalPermissionPolicy) SetKcpInformers(f kcpinformers.SharedInformerFactory) {
+	apiBindingsInformer := f.Apis().V1alpha1().APIBindings()
+	apiExportsInformer := f.Apis().V1alpha1().APIExports()
+
+	p.apiBindingsHasSynced = apiBindingsInformer.Informer().HasSynced
+	p.apiExportsHasSynced = apiExportsInformer.Informer().HasSynced
+
+	p.listAPIBindings = func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+		return apiBindingsInformer.Lister().Cluster(clusterName).List(labels.Everything())
+	}
+	p.getAPIExport = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+		return apiExportsInformer.Lister().Cluster(clusterName).Get(name)
+	}
+}
+
+func (p *maximalPermissionPolicy) ValidateInitialization() error {
+	if p.listAPIBindings == nil {
+		return errors.New("missing listAPIBindings")
+	}
+	if p.getAPIExport == nil {
+		return errors.New("missing getAPIExport")
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maximalpermissionpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+var widgets = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+
+func widget(spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if spec != nil {
+		obj.Object["spec"] = spec
+	}
+	return obj
+}
+
+func attr(obj, old runtime.Object, subresource string, userInfo user.Info) admission.Attributes {
+	op := admission.Create
+	var opts runtime.Object = &metav1.CreateOptions{}
+	if old != nil {
+		op = admission.Update
+		opts = &metav1.UpdateOptions{}
+	}
+	return admission.NewAttributesRecord(
+		obj,
+		old,
+		widgets.GroupVersion().WithKind("Widget"),
+		"default",
+		"widget",
+		widgets,
+		subresource,
+		op,
+		opts,
+		false,
+		userInfo,
+	)
+}
+
+func binding(identityHash string) *apisv1alpha1.APIBinding {
+	return &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets"},
+		Spec: apisv1alpha1.APIBindingSpec{
+			Reference: apisv1alpha1.ExportReference{
+				Workspace: &apisv1alpha1.WorkspaceExportReference{Path: "root:provider", ExportName: "widgets"},
+			},
+		},
+		Status: apisv1alpha1.APIBindingStatus{
+			BoundResources: []apisv1alpha1.BoundAPIResource{{
+				Group:    widgets.Group,
+				Resource: widgets.Resource,
+				Schema:   apisv1alpha1.BoundAPIResourceSchema{IdentityHash: identityHash},
+			}},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "widgets",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:provider"},
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			MaximalPermissionPolicy: &apisv1alpha1.MaximalPermissionPolicy{
+				Local: &apisv1alpha1.LocalAPIExportPolicy{},
+				ProtectedFields: []apisv1alpha1.ProtectedFields{{
+					GroupResource: apisv1alpha1.GroupResource{Group: widgets.Group, Resource: widgets.Resource},
+					Paths:         []string{"spec.foo"},
+				}},
+			},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "id"},
+	}
+	consumer := &user.DefaultInfo{Name: "adam", Groups: []string{"system:authenticated"}}
+	privileged := &user.DefaultInfo{Name: "system:apiserver", Groups: []string{user.SystemPrivilegedGroup}}
+
+	tests := map[string]struct {
+		bindings []*apisv1alpha1.APIBinding
+		attr     admission.Attributes
+		wantErr  bool
+	}{
+		"create without protected field": {
+			bindings: []*apisv1alpha1.APIBinding{binding("id")},
+			attr:     attr(widget(map[string]interface{}{"bar": "x"}), nil, "", consumer),
+		},
+		"create with protected field": {
+			bindings: []*apisv1alpha1.APIBinding{binding("id")},
+			attr:     attr(widget(map[string]interface{}{"foo": "x"}), nil, "", consumer),
+			wantErr:  true,
+		},
+		"update not changing protected field": {
+			bindings: []*apisv1alpha1.APIBinding{binding("id")},
+			attr:     attr(widget(map[string]interface{}{"foo": "x", "bar": "y"}), widget(map[string]interface{}{"foo": "x"}), "", consumer),
+		},
+		"update changing protected field": {
+			bindings: []*apisv1alpha1.APIBinding{binding("id")},
+			attr:     attr(widget(map[string]interface{}{"foo": "y"}), widget(map[string]interface{}{"foo": "x"}), "", consumer),
+			wantErr:  true,
+		},
+		"update removing protected field": {
+			bindings: []*apisv1alpha1.APIBinding{binding("id")},
+			attr:     attr(widget(nil), widget(map[string]interface{}{"foo": "x"}), "", consumer),
+			wantErr:  true,
+		},
+		"status update changing protected field": {
+			bindings: []*apisv1alpha1.APIBinding{binding("id")},
+			attr:     attr(widget(map[string]interface{}{"foo": "y"}), widget(map[string]interface{}{"foo": "x"}), "status", consumer),
+			wantErr:  true,
+		},
+		"scale update": {
+			bindings: []*apisv1alpha1.APIBinding{binding("id")},
+			attr:     attr(widget(map[string]interface{}{"foo": "y"}), widget(map[string]interface{}{"foo": "x"}), "scale", consumer),
+		},
+		"privileged user": {
+			bindings: []*apisv1alpha1.APIBinding{binding("id")},
+			attr:     attr(widget(map[string]interface{}{"foo": "y"}), widget(map[string]interface{}{"foo": "x"}), "", privileged),
+		},
+		"other identity": {
+			bindings: []*apisv1alpha1.APIBinding{binding("other")},
+			attr:     attr(widget(map[string]interface{}{"foo": "x"}), nil, "", consumer),
+		},
+		"not bound": {
+			attr: attr(widget(map[string]interface{}{"foo": "x"}), nil, "", consumer),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &maximalPermissionPolicy{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					require.Equal(t, logicalcluster.New("root:consumer"), clusterName)
+					return tt.bindings, nil
+				},
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					if clusterName == logicalcluster.From(export) && name == export.Name {
+						return export, nil
+					}
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), name)
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New("root:consumer")})
+			err := p.Validate(ctx, tt.attr, nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, apierrors.IsForbidden(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -23,14 +23,19 @@ import (
 	"io"
 	"strings"
 
+	kcpcorev1informers "github.com/kcp-dev/client-go/informers/core/v1"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apiserver/pkg/admission"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/informerfactoryhack"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	apisv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/permissionclaim"
 )
 
@@ -47,7 +52,10 @@ func Register(plugins *admission.Plugins) {
 type mutatingPermissionClaims struct {
 	*admission.Handler
 
+	apiBindingInformer   apisv1alpha1informers.APIBindingClusterInformer
 	apiBindingsHasSynced cache.InformerSynced
+	namespaceInformer    kcpcorev1informers.NamespaceClusterInformer
+	namespacesHasSynced  cache.InformerSynced
 
 	permissionClaimLabeler *permissionclaim.Labeler
 }
@@ -67,7 +75,7 @@ func NewMutatingPermissionClaims() admission.MutationInterface {
 
 	p.SetReadyFunc(
 		func() bool {
-			return p.apiBindingsHasSynced()
+			return p.apiBindingsHasSynced() && p.namespacesHasSynced()
 		},
 	)
 
@@ -89,7 +97,7 @@ func (m *mutatingPermissionClaims) Admit(ctx context.Context, a admission.Attrib
 		return err
 	}
 
-	expectedLabels, err := m.permissionClaimLabeler.LabelsFor(ctx, clusterName, a.GetResource().GroupResource(), a.GetName())
+	expectedLabels, err := m.permissionClaimLabeler.LabelsFor(ctx, clusterName, a.GetResource().GroupResource(), a.GetName(), a.GetNamespace(), u.GetLabels())
 	if err != nil {
 		return err
 	}
@@ -130,7 +138,7 @@ func (m *mutatingPermissionClaims) Validate(ctx context.Context, a admission.Att
 		return err
 	}
 
-	expectedLabels, err := m.permissionClaimLabeler.LabelsFor(ctx, clusterName, a.GetResource().GroupResource(), a.GetName())
+	expectedLabels, err := m.permissionClaimLabeler.LabelsFor(ctx, clusterName, a.GetResource().GroupResource(), a.GetName(), a.GetNamespace(), u.GetLabels())
 	if err != nil {
 		return err
 	}
@@ -164,19 +172,24 @@ func (m *mutatingPermissionClaims) Validate(ctx context.Context, a admission.Att
 
 // SetKcpInformers implements the WantsExternalKcpInformerFactory interface.
 func (m *mutatingPermissionClaims) SetKcpInformers(f kcpinformers.SharedInformerFactory) {
-	m.apiBindingsHasSynced = f.Apis().V1alpha1().APIBindings().Informer().HasSynced
+	m.apiBindingInformer = f.Apis().V1alpha1().APIBindings()
+	m.apiBindingsHasSynced = m.apiBindingInformer.Informer().HasSynced
+}
 
-	m.permissionClaimLabeler = permissionclaim.NewLabeler(
-		f.Apis().V1alpha1().APIBindings(),
-	)
+// SetExternalKubeInformerFactory implements the WantsExternalKubeInformerFactory interface.
+func (m *mutatingPermissionClaims) SetExternalKubeInformerFactory(f informers.SharedInformerFactory) {
+	m.namespaceInformer = informerfactoryhack.Unwrap(f).Core().V1().Namespaces()
+	m.namespacesHasSynced = m.namespaceInformer.Informer().HasSynced
 }
 
 func (m *mutatingPermissionClaims) ValidateInitialization() error {
 	if m.apiBindingsHasSynced == nil {
 		return errors.New("missing apiBindingsHasSynced")
 	}
-	if m.permissionClaimLabeler == nil {
-		return errors.New("missing permissionClaimLabeler")
+	if m.namespacesHasSynced == nil {
+		return errors.New("missing namespacesHasSynced")
 	}
+
+	m.permissionClaimLabeler = permissionclaim.NewLabeler(m.apiBindingInformer, m.namespaceInformer)
 	return nil
 }
@@ -43,14 +43,20 @@ import (
 	"github.com/kcp-dev/kcp/pkg/admission/apibindingfinalizer"
 	"github.com/kcp-dev/kcp/pkg/admission/apiexport"
 	"github.com/kcp-dev/kcp/pkg/admission/apiresourceschema"
+	"github.com/kcp-dev/kcp/pkg/admission/apiresourceschemalint"
 	"github.com/kcp-dev/kcp/pkg/admission/clusterworkspace"
 	"github.com/kcp-dev/kcp/pkg/admission/clusterworkspacefinalizer"
+	"github.com/kcp-dev/kcp/pkg/admission/clusterworkspacequota"
 	"github.com/kcp-dev/kcp/pkg/admission/clusterworkspaceshard"
+	"github.com/kcp-dev/kcp/pkg/admission/clusterworkspacetombstone"
 	"github.com/kcp-dev/kcp/pkg/admission/clusterworkspacetype"
 	"github.com/kcp-dev/kcp/pkg/admission/clusterworkspacetypeexists"
 	"github.com/kcp-dev/kcp/pkg/admission/crdnooverlappinggvr"
+	"github.com/kcp-dev/kcp/pkg/admission/crossworkspacereference"
+	"github.com/kcp-dev/kcp/pkg/admission/eventratelimit"
 	"github.com/kcp-dev/kcp/pkg/admission/kubequota"
 	kcplimitranger "github.com/kcp-dev/kcp/pkg/admission/limitranger"
+	"github.com/kcp-dev/kcp/pkg/admission/maximalpermissionpolicy"
 	kcpmutatingwebhook "github.com/kcp-dev/kcp/pkg/admission/mutatingwebhook"
 	workspacenamespacelifecycle "github.com/kcp-dev/kcp/pkg/admission/namespacelifecycle"
 	"github.com/kcp-dev/kcp/pkg/admission/permissionclaims"
@@ -59,23 +65,29 @@ import (
 	"github.com/kcp-dev/kcp/pkg/admission/reservedmetadata"
 	"github.com/kcp-dev/kcp/pkg/admission/reservednames"
 	kcpvalidatingwebhook "github.com/kcp-dev/kcp/pkg/admission/validatingwebhook"
+	"github.com/kcp-dev/kcp/pkg/admission/workspaceauthentication"
+	"github.com/kcp-dev/kcp/pkg/admission/workspacemigration"
 )
 
 // AllOrderedPlugins is the list of all the plugins in order.
 var AllOrderedPlugins = beforeWebhooks(kubeapiserveroptions.AllOrderedPlugins,
 	workspacenamespacelifecycle.PluginName,
 	apiresourceschema.PluginName,
+	apiresourceschemalint.PluginName,
 	clusterworkspace.PluginName,
 	clusterworkspacefinalizer.PluginName,
 	clusterworkspaceshard.PluginName,
+	clusterworkspacetombstone.PluginName,
 	clusterworkspacetype.PluginName,
 	clusterworkspacetypeexists.PluginName,
 	apiexport.PluginName,
 	apibinding.PluginName,
 	apibindingfinalizer.PluginName,
+	crossworkspacereference.PluginName,
 	kcpvalidatingwebhook.PluginName,
 	kcpmutatingwebhook.PluginName,
 	kcplimitranger.PluginName,
+	eventratelimit.PluginName,
 	reservedcrdannotations.PluginName,
 	reservedcrdgroups.PluginName,
 	reservednames.PluginName,
@@ -83,6 +95,10 @@ var AllOrderedPlugins = beforeWebhooks(kubeapiserveroptions.AllOrderedPlugins,
 	reservedmetadata.PluginName,
 	permissionclaims.PluginName,
 	kubequota.PluginName,
+	maximalpermissionpolicy.PluginName,
+	clusterworkspacequota.PluginName,
+	workspacemigration.PluginName,
+	workspaceauthentication.PluginName,
 )
 
 func beforeWebhooks(recommended []string, plugins ...string) []string {
@@ -103,16 +119,20 @@ func RegisterAllKcpAdmissionPlugins(plugins *admission.Plugins) {
 	clusterworkspace.Register(plugins)
 	clusterworkspacefinalizer.Register(plugins)
 	clusterworkspaceshard.Register(plugins)
+	clusterworkspacetombstone.Register(plugins)
 	clusterworkspacetype.Register(plugins)
 	clusterworkspacetypeexists.Register(plugins)
 	apiresourceschema.Register(plugins)
+	apiresourceschemalint.Register(plugins)
 	apiexport.Register(plugins)
 	apibinding.Register(plugins)
 	apibindingfinalizer.Register(plugins)
+	crossworkspacereference.Register(plugins)
 	workspacenamespacelifecycle.Register(plugins)
 	kcpvalidatingwebhook.Register(plugins)
 	kcpmutatingwebhook.Register(plugins)
 	kcplimitranger.Register(plugins)
+	eventratelimit.Register(plugins)
 	reservedcrdannotations.Register(plugins)
 	reservedcrdgroups.Register(plugins)
 	reservednames.Register(plugins)
@@ -120,11 +140,16 @@ func RegisterAllKcpAdmissionPlugins(plugins *admission.Plugins) {
 	reservedmetadata.Register(plugins)
 	permissionclaims.Register(plugins)
 	kubequota.Register(plugins)
+	maximalpermissionpolicy.Register(plugins)
+	clusterworkspacequota.Register(plugins)
+	workspacemigration.Register(plugins)
+	workspaceauthentication.Register(plugins)
 }
 
 var defaultOnPluginsInKcp = sets.NewString(
 	workspacenamespacelifecycle.PluginName, // WorkspaceNamespaceLifecycle
 	kcplimitranger.PluginName,              // WorkspaceLimitRanger
+	eventratelimit.PluginName,              // WorkspaceEventRateLimit
 	certapproval.PluginName,                // CertificateApproval
 	certsigning.PluginName,                 // CertificateSigning
 	certsubjectrestriction.PluginName,      // CertificateSubjectRestriction
@@ -133,12 +158,15 @@ var defaultOnPluginsInKcp = sets.NewString(
 	clusterworkspace.PluginName,
 	clusterworkspacefinalizer.PluginName,
 	clusterworkspaceshard.PluginName,
+	clusterworkspacetombstone.PluginName,
 	clusterworkspacetype.PluginName,
 	clusterworkspacetypeexists.PluginName,
 	apiresourceschema.PluginName,
+	apiresourceschemalint.PluginName,
 	apiexport.PluginName,
 	apibinding.PluginName,
 	apibindingfinalizer.PluginName,
+	crossworkspacereference.PluginName,
 	kcpvalidatingwebhook.PluginName,
 	kcpmutatingwebhook.PluginName,
 	reservedcrdannotations.PluginName,
@@ -146,6 +174,10 @@ var defaultOnPluginsInKcp = sets.NewString(
 	reservednames.PluginName,
 	permissionclaims.PluginName,
 	kubequota.PluginName,
+	maximalpermissionpolicy.PluginName,
+	clusterworkspacequota.PluginName,
+	workspacemigration.PluginName,
+	workspaceauthentication.PluginName,
 )
 
 // defaultOnKubePluginsInKube is a copy of kubeapiserveroptions.defaultOnKubePlugins.
@@ -47,6 +47,10 @@ var (
 	labelAllowList = []string{
 		apisv1alpha1.APIExportPermissionClaimLabelPrefix + "*", // protected by the permissionclaim admission plugin
 	}
+	// annotationDenyList are reserved annotations with keys not ending in kcp.dev.
+	annotationDenyList = []string{
+		tenancyv1alpha1.WorkspaceRestoreUIDAnnotationKey, // sets the UID of the object on create
+	}
 )
 
 // Register registers the reserved metadata plugin for creation and updates.
@@ -92,20 +96,20 @@ func (o *reservedMetadata) Validate(ctx context.Context, a admission.Attributes,
 		return nil
 	}
 
-	if k, ok := hasPrivilegedModification(newMeta.GetAnnotations(), oldMeta.GetAnnotations(), annotationAllowList); ok {
+	if k, ok := hasPrivilegedModification(newMeta.GetAnnotations(), oldMeta.GetAnnotations(), annotationAllowList, annotationDenyList); ok {
 		return admission.NewForbidden(a, fmt.Errorf("modification of reserved annotation: %q", k))
 	}
 
-	if k, ok := hasPrivilegedModification(newMeta.GetLabels(), oldMeta.GetLabels(), labelAllowList); ok {
+	if k, ok := hasPrivilegedModification(newMeta.GetLabels(), oldMeta.GetLabels(), labelAllowList, nil); ok {
 		return admission.NewForbidden(a, fmt.Errorf("modification of reserved label: %q", k))
 	}
 
 	return nil
 }
 
-func hasPrivilegedModification(new, old map[string]string, allowList []string) (key string, modified bool) {
+func hasPrivilegedModification(new, old map[string]string, allowList, denyList []string) (key string, modified bool) {
 	hasChanged := func(k, v1, v2 string, v2present bool) bool {
-		return (!v2present || v1 != v2) && isPrivileged(k, allowList)
+		return (!v2present || v1 != v2) && isPrivileged(k, allowList, denyList)
 	}
 
 	for k, v1 := range old {
@@ -127,7 +131,10 @@ func hasPrivilegedModification(new, old map[string]string, allowList []string) (
 	return "", false
 }
 
-func isPrivileged(key string, allowList []string) bool {
+func isPrivileged(key string, allowList, denyList []string) bool {
+	if slices.Contains(denyList, key) {
+		return true
+	}
 	for i := range allowList {
 		if strings.HasSuffix(allowList[i], "*") && strings.HasPrefix(key, allowList[i][:len(allowList[i])-1]) {
 			return false
@@ -26,6 +26,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authentication/user"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 )
 
 func newAttr(obj, oldObject runtime.Object, op admission.Operation, user user.Info) admission.Attributes {
@@ -552,6 +554,39 @@ func TestAdmission(t *testing.T) {
 			),
 			wantErr: "forbidden: modification of reserved annotation: \"some.kcp.dev\"",
 		},
+		{
+			testName: "added restore UID annotation",
+			attr: newAttr(
+				&v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+						Annotations: map[string]string{
+							tenancyv1alpha1.WorkspaceRestoreUIDAnnotationKey: "uid",
+						},
+					},
+				},
+				nil,
+				admission.Create,
+				&user.DefaultInfo{},
+			),
+			wantErr: "forbidden: modification of reserved annotation: \"experimental.tenancy.kcp.dev/restore-uid\"",
+		},
+		{
+			testName: "added restore UID annotation by system:masters",
+			attr: newAttr(
+				&v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+						Annotations: map[string]string{
+							tenancyv1alpha1.WorkspaceRestoreUIDAnnotationKey: "uid",
+						},
+					},
+				},
+				nil,
+				admission.Create,
+				&user.DefaultInfo{Groups: []string{user.SystemPrivilegedGroup}},
+			),
+		},
 		{
 			testName: "changed kcp.dev annotation",
 			attr: newAttr(
@@ -108,22 +108,24 @@ func (p *WebhookDispatcher) Dispatch(ctx context.Context, attr admission.Attribu
 		return admission.NewForbidden(attr, fmt.Errorf("not yet ready to handle request"))
 	}
 
-	var whAccessor []webhook.WebhookAccessor
-
-	// Determine the type of request, is it api binding or not.
+	// Resources bound via an APIBinding are first subject to the webhooks registered in the workspace of
+	// the APIExport, i.e. the hooks of the API provider. This allows the API provider to default and validate
+	// their API in every consuming workspace.
 	if workspace, isAPIBinding, err := p.getAPIBindingWorkspace(attr, lcluster); err != nil {
 		return err
-	} else if isAPIBinding {
-		whAccessor = p.hookSource.Webhooks(workspace)
+	} else if isAPIBinding && workspace != lcluster {
 		attr.SetCluster(workspace)
-		klog.V(7).Infof("restricting call to api registration hooks in cluster: %v", workspace)
-	} else {
-		whAccessor = p.hookSource.Webhooks(lcluster)
-		attr.SetCluster(lcluster)
-		klog.V(7).Infof("restricting call to hooks in cluster: %v", lcluster)
+		klog.V(7).Infof("calling api registration hooks in cluster: %v", workspace)
+		if err := p.dispatcher.Dispatch(ctx, attr, o, p.hookSource.Webhooks(workspace)); err != nil {
+			return err
+		}
 	}
 
-	return p.dispatcher.Dispatch(ctx, attr, o, whAccessor)
+	// Then the webhooks registered in the requesting workspace itself are called. Webhooks of
+	// other workspaces are never called.
+	attr.SetCluster(lcluster)
+	klog.V(7).Infof("restricting call to hooks in cluster: %v", lcluster)
+	return p.dispatcher.Dispatch(ctx, attr, o, p.hookSource.Webhooks(lcluster))
 }
 
 func (p *WebhookDispatcher) getAPIBindingWorkspace(attr admission.Attributes, clusterName logicalcluster.Name) (logicalcluster.Name, bool, error) {
@@ -18,7 +18,7 @@ package webhook
 
 import (
 	"context"
-	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -58,26 +58,15 @@ func attr(gvk schema.GroupVersionKind, name, resource string, op admission.Opera
 }
 
 type validatingDispatcher struct {
-	hooks map[logicalcluster.Name][]webhook.WebhookAccessor
+	calls [][]string
 }
 
 func (d *validatingDispatcher) Dispatch(ctx context.Context, a admission.Attributes, o admission.ObjectInterfaces, hooks []webhook.WebhookAccessor) error {
-	if len(hooks) != len(d.hooks) {
-		return fmt.Errorf("invalid number of hooks sent to dispatcher")
-	}
-	uidMatches := map[string]*struct{}{}
+	uids := []string{}
 	for _, h := range hooks {
-		for _, allHooks := range d.hooks {
-			for _, expectedHook := range allHooks {
-				if h.GetUID() == expectedHook.GetUID() {
-					uidMatches[h.GetUID()] = &struct{}{}
-				}
-			}
-		}
-	}
-	if len(uidMatches) != len(d.hooks) {
-		return fmt.Errorf("hooks UID did not match expected")
+		uids = append(uids, h.GetUID())
 	}
+	d.calls = append(d.calls, uids)
 	return nil
 }
 
@@ -99,7 +88,7 @@ func TestDispatch(t *testing.T) {
 		name                string
 		attr                admission.Attributes
 		cluster             string
-		expectedHooks       map[logicalcluster.Name][]webhook.WebhookAccessor
+		expectedCalls       [][]string
 		hooksInSource       map[logicalcluster.Name][]webhook.WebhookAccessor
 		hookSourceNotSynced bool
 		apiBindings         []*v1alpha1.APIBinding
@@ -107,17 +96,15 @@ func TestDispatch(t *testing.T) {
 		wantErr             bool
 	}{
 		{
-			name: "call for APIBinding only calls hooks in api registration logical cluster",
+			name: "call for APIBinding calls hooks in api registration logical cluster, then in the logical cluster",
 			attr: attr(
 				schema.GroupVersionKind{Kind: "Cowboy", Group: "wildwest.dev", Version: "v1"},
 				"bound-resource",
 				"cowboys",
 				admission.Create,
 			),
-			cluster: "root:org:dest-cluster",
-			expectedHooks: map[logicalcluster.Name][]webhook.WebhookAccessor{
-				logicalcluster.New("root:org:source-cluster"): {webhook.NewValidatingWebhookAccessor("1", "api-registration-hook", nil)},
-			},
+			cluster:       "root:org:dest-cluster",
+			expectedCalls: [][]string{{"1"}, {"2"}},
 			hooksInSource: map[logicalcluster.Name][]webhook.WebhookAccessor{
 				logicalcluster.New("root:org:source-cluster"): {webhook.NewValidatingWebhookAccessor("1", "api-registration-hook", nil)},
 				logicalcluster.New("root:org:dest-cluster"):   {webhook.NewValidatingWebhookAccessor("2", "secrets", nil)},
@@ -156,10 +143,8 @@ func TestDispatch(t *testing.T) {
 				"cowboys",
 				admission.Create,
 			),
-			cluster: "root:org:dest-cluster",
-			expectedHooks: map[logicalcluster.Name][]webhook.WebhookAccessor{
-				logicalcluster.New("root:org:dest-cluster"): {webhook.NewValidatingWebhookAccessor("3", "secrets", nil)},
-			},
+			cluster:       "root:org:dest-cluster",
+			expectedCalls: [][]string{{"3"}},
 			hooksInSource: map[logicalcluster.Name][]webhook.WebhookAccessor{
 				logicalcluster.New("root:org:source-cluster"): {webhook.NewValidatingWebhookAccessor("1", "cowboy-hook", nil)},
 				logicalcluster.New("root:org:source-cluster"): {webhook.NewValidatingWebhookAccessor("2", "secrets", nil)},
@@ -174,10 +159,8 @@ func TestDispatch(t *testing.T) {
 				"cowboys",
 				admission.Create,
 			),
-			cluster: "root:org:dest-cluster",
-			expectedHooks: map[logicalcluster.Name][]webhook.WebhookAccessor{
-				logicalcluster.New("root:org:dest-cluster"): {webhook.NewValidatingWebhookAccessor("3", "secrets", nil)},
-			},
+			cluster:       "root:org:dest-cluster",
+			expectedCalls: [][]string{{"3"}},
 			hooksInSource: map[logicalcluster.Name][]webhook.WebhookAccessor{
 				logicalcluster.New("root:org:source-cluster"): {webhook.NewValidatingWebhookAccessor("1", "cowboy-hook", nil)},
 				logicalcluster.New("root:org:source-cluster"): {webhook.NewValidatingWebhookAccessor("2", "secrets", nil)},
@@ -218,6 +201,46 @@ func TestDispatch(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "call for APIBinding to an APIExport in the same logical cluster calls hooks only once",
+			attr: attr(
+				schema.GroupVersionKind{Kind: "Cowboy", Group: "wildwest.dev", Version: "v1"},
+				"bound-resource",
+				"cowboys",
+				admission.Create,
+			),
+			cluster:       "root:org:dest-cluster",
+			expectedCalls: [][]string{{"2"}},
+			hooksInSource: map[logicalcluster.Name][]webhook.WebhookAccessor{
+				logicalcluster.New("root:org:source-cluster"): {webhook.NewValidatingWebhookAccessor("1", "api-registration-hook", nil)},
+				logicalcluster.New("root:org:dest-cluster"):   {webhook.NewValidatingWebhookAccessor("2", "cowboy-hook", nil)},
+			},
+			apiBindings: []*v1alpha1.APIBinding{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "one",
+						Annotations: map[string]string{
+							logicalcluster.AnnotationKey: "root:org:dest-cluster",
+						},
+					},
+					Spec: v1alpha1.APIBindingSpec{
+						Reference: v1alpha1.ExportReference{
+							Workspace: &v1alpha1.WorkspaceExportReference{
+								Path: "root:org:dest-cluster",
+							},
+						},
+					},
+					Status: v1alpha1.APIBindingStatus{
+						BoundResources: []v1alpha1.BoundAPIResource{
+							{
+								Group:    "wildwest.dev",
+								Resource: "cowboys",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "API Bindings Lister not synced",
 			attr: attr(
@@ -254,9 +277,10 @@ func TestDispatch(t *testing.T) {
 			fakeClient := kcpfakeclient.NewSimpleClientset(toObjects(tc.apiBindings)...)
 			fakeInformerFactory := kcpinformers.NewSharedInformerFactory(fakeClient, time.Hour)
 
+			dispatcher := &validatingDispatcher{}
 			o := &WebhookDispatcher{
 				Handler:                 admission.NewHandler(admission.Connect, admission.Create, admission.Delete, admission.Update),
-				dispatcher:              &validatingDispatcher{hooks: tc.expectedHooks},
+				dispatcher:              dispatcher,
 				hookSource:              &fakeHookSource{hooks: tc.hooksInSource, hasSynced: !tc.hookSourceNotSynced},
 				apiBindingClusterLister: fakeInformerFactory.Apis().V1alpha1().APIBindings().Lister(),
 				apiBindingsHasSynced:    tc.apiBindingsSynced,
@@ -278,6 +302,9 @@ func TestDispatch(t *testing.T) {
 			if err := o.Dispatch(ctx, tc.attr, nil); (err != nil) != tc.wantErr {
 				t.Fatalf("Dispatch() error = %v, wantErr %v", err, tc.wantErr)
 			}
+			if !tc.wantErr && !reflect.DeepEqual(dispatcher.calls, tc.expectedCalls) {
+				t.Errorf("unexpected hooks dispatched: got %v, want %v", dispatcher.calls, tc.expectedCalls)
+			}
 		})
 	}
 }
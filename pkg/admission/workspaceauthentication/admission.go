@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspaceauthentication
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/initializers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/WorkspaceAuthenticationConfiguration"
+
+	// MaxConfigurationsPerWorkspace is the maximum number of WorkspaceAuthenticationConfigurations in a
+	// logical cluster. Every configuration is tried for bearer tokens rejected by kcp itself.
+	MaxConfigurationsPerWorkspace = 10
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName,
+		func(_ io.Reader) (admission.Interface, error) {
+			return &workspaceAuthentication{
+				Handler: admission.NewHandler(admission.Create, admission.Update),
+			}, nil
+		})
+}
+
+// workspaceAuthentication validates WorkspaceAuthenticationConfigurations:
+//   - the username prefix cannot disable prefixing.
+//   - a groups prefix is required if the issuer or the webhook asserts groups.
+//   - webhooks are only accepted in organization workspaces.
+//   - certificate authorities must be PEM encoded certificates.
+//   - issuer and webhook URLs must be https, and must not point to loopback, link-local or private
+//     addresses unless allowed by the operator.
+//   - a logical cluster holds at most MaxConfigurationsPerWorkspace configurations.
+type workspaceAuthentication struct {
+	*admission.Handler
+
+	allowPrivateNetworks bool
+	listConfigs          func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceAuthenticationConfiguration, error)
+}
+
+var _ = admission.ValidationInterface(&workspaceAuthentication{})
+var _ = admission.InitializationValidator(&workspaceAuthentication{})
+var _ = initializers.WantsKcpInformers(&workspaceAuthentication{})
+var _ = initializers.WantsWorkspaceAuthenticationAllowPrivateNetworks(&workspaceAuthentication{})
+
+func (p *workspaceAuthentication) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() != tenancyv1alpha1.Resource("workspaceauthenticationconfigurations") {
+		return nil
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	config := &tenancyv1alpha1.WorkspaceAuthenticationConfiguration{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, config); err != nil {
+		return fmt.Errorf("failed to convert unstructured to WorkspaceAuthenticationConfiguration: %w", err)
+	}
+
+	if errs := validateSpec(clusterName, &config.Spec, p.allowPrivateNetworks, field.NewPath("spec")); len(errs) > 0 {
+		return admission.NewForbidden(a, errs.ToAggregate())
+	}
+
+	if a.GetOperation() == admission.Create {
+		configs, err := p.listConfigs(clusterName)
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		if len(configs) >= MaxConfigurationsPerWorkspace {
+			return admission.NewForbidden(a, fmt.Errorf("at most %d WorkspaceAuthenticationConfigurations are allowed per workspace", MaxConfigurationsPerWorkspace))
+		}
+	}
+
+	return nil
+}
+
+// SetKcpInformers is an admission plugin initializer function that injects the kcp informers used to
+// count the configurations of a workspace.
+func (p *workspaceAuthentication) SetKcpInformers(informers kcpinformers.SharedInformerFactory) {
+	configLister := informers.Tenancy().V1alpha1().WorkspaceAuthenticationConfigurations().Lister()
+	p.listConfigs = func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceAuthenticationConfiguration, error) {
+		return configLister.Cluster(clusterName).List(labels.Everything())
+	}
+}
+
+// SetWorkspaceAuthenticationAllowPrivateNetworks is an admission plugin initializer function that injects
+// whether issuers and webhooks may be on loopback, link-local or private addresses.
+func (p *workspaceAuthentication) SetWorkspaceAuthenticationAllowPrivateNetworks(allow bool) {
+	p.allowPrivateNetworks = allow
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (p *workspaceAuthentication) ValidateInitialization() error {
+	if p.listConfigs == nil {
+		return fmt.Errorf(PluginName + " plugin needs kcp informers")
+	}
+	return nil
+}
+
+func validateSpec(clusterName logicalcluster.Name, spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec, allowPrivateNetworks bool, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if spec.Webhook == nil || spec.IssuerURL != "" {
+		errs = append(errs, validateURL(spec.IssuerURL, allowPrivateNetworks, fldPath.Child("issuerURL"))...)
+	}
+
+	if spec.UsernamePrefix == "-" {
+		errs = append(errs, field.Invalid(fldPath.Child("usernamePrefix"), spec.UsernamePrefix, "usernames of workspace issuers are always prefixed"))
+	}
+	if spec.GroupsPrefix == "" && (spec.GroupsClaim != "" || spec.Webhook != nil) {
+		errs = append(errs, field.Required(fldPath.Child("groupsPrefix"), "required if groups are asserted"))
+	}
+	if spec.CertificateAuthority != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(spec.CertificateAuthority)) {
+		errs = append(errs, field.Invalid(fldPath.Child("certificateAuthority"), "<omitted>", "must hold PEM encoded certificates"))
+	}
+
+	if spec.Webhook != nil {
+		if parent, hasParent := clusterName.Parent(); !hasParent || parent != tenancyv1alpha1.RootCluster {
+			errs = append(errs, field.Forbidden(fldPath.Child("webhook"), "only allowed in organization workspaces"))
+		}
+		if ca := spec.Webhook.CertificateAuthority; ca != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(ca)) {
+			errs = append(errs, field.Invalid(fldPath.Child("webhook", "certificateAuthority"), "<omitted>", "must hold PEM encoded certificates"))
+		}
+		errs = append(errs, validateURL(spec.Webhook.URL, allowPrivateNetworks, fldPath.Child("webhook", "url"))...)
+	}
+
+	return errs
+}
+
+// validateURL checks that the given issuer or webhook URL is https, and that its host is neither localhost nor
+// an IP address for which IsPrivateIP is true, unless allowPrivateNetworks is set. Host names resolving to such
+// addresses are refused when connecting.
+func validateURL(rawURL string, allowPrivateNetworks bool, fldPath *field.Path) field.ErrorList {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return field.ErrorList{field.Invalid(fldPath, rawURL, "must be an https URL")}
+	}
+	if allowPrivateNetworks {
+		return nil
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return field.ErrorList{field.Forbidden(fldPath, "must not point to localhost")}
+	}
+	if ip := net.ParseIP(host); ip != nil && IsPrivateIP(ip) {
+		return field.ErrorList{field.Forbidden(fldPath, "must not point to a loopback, link-local or private address")}
+	}
+	return nil
+}
+
+// IsPrivateIP returns true if the given address is an unspecified, loopback, link-local or private (RFC 1918,
+// RFC 4193) address, i.e. one that workspace issuers and webhooks must not use to reach into the network of kcp.
+func IsPrivateIP(ip net.IP) bool {
+	return ip.IsUnspecified() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsPrivate()
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspaceauthentication
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/helpers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func createAttr(config *tenancyv1alpha1.WorkspaceAuthenticationConfiguration) admission.Attributes {
+	return admission.NewAttributesRecord(
+		helpers.ToUnstructuredOrDie(config),
+		nil,
+		tenancyv1alpha1.Kind("WorkspaceAuthenticationConfiguration").WithVersion("v1alpha1"),
+		"",
+		config.Name,
+		tenancyv1alpha1.Resource("workspaceauthenticationconfigurations").WithVersion("v1alpha1"),
+		"",
+		admission.Create,
+		&metav1.CreateOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func TestValidate(t *testing.T) {
+	oidc := tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec{
+		IssuerURL: "https://idp.example.com",
+		ClientID:  "kcp",
+	}
+	webhook := tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec{
+		Webhook:      &tenancyv1alpha1.WorkspaceAuthenticationWebhook{URL: "https://idp.example.com"},
+		GroupsPrefix: "idp:",
+	}
+
+	tests := map[string]struct {
+		cluster              string
+		spec                 func(spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec)
+		webhook              bool
+		allowPrivateNetworks bool
+		existing             int
+		wantErr              string
+	}{
+		"oidc": {
+			cluster: "root:org:team",
+		},
+		"oidc with groups": {
+			cluster: "root:org:team",
+			spec: func(spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec) {
+				spec.GroupsClaim = "groups"
+				spec.GroupsPrefix = "idp:"
+			},
+		},
+		"groups without prefix": {
+			cluster: "root:org:team",
+			spec: func(spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec) {
+				spec.GroupsClaim = "groups"
+			},
+			wantErr: "spec.groupsPrefix: Required value",
+		},
+		"disabled username prefix": {
+			cluster: "root:org:team",
+			spec: func(spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec) {
+				spec.UsernamePrefix = "-"
+			},
+			wantErr: "spec.usernamePrefix: Invalid value",
+		},
+		"invalid certificate authority": {
+			cluster: "root:org:team",
+			spec: func(spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec) {
+				spec.CertificateAuthority = "not a certificate"
+			},
+			wantErr: "spec.certificateAuthority: Invalid value",
+		},
+		"webhook in organization": {
+			cluster: "root:org",
+			webhook: true,
+		},
+		"webhook below organization": {
+			cluster: "root:org:team",
+			webhook: true,
+			wantErr: "spec.webhook: Forbidden",
+		},
+		"webhook without groups prefix": {
+			cluster: "root:org",
+			webhook: true,
+			spec: func(spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec) {
+				spec.GroupsPrefix = ""
+			},
+			wantErr: "spec.groupsPrefix: Required value",
+		},
+		"http issuer": {
+			cluster: "root:org:team",
+			spec: func(spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec) {
+				spec.IssuerURL = "http://idp.example.com"
+			},
+			wantErr: "spec.issuerURL: Invalid value",
+		},
+		"http webhook": {
+			cluster: "root:org",
+			webhook: true,
+			spec: func(spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec) {
+				spec.Webhook.URL = "http://idp.example.com"
+			},
+			wantErr: "spec.webhook.url: Invalid value",
+		},
+		"issuer on localhost": {
+			cluster: "root:org:team",
+			spec: func(spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec) {
+				spec.IssuerURL = "https://localhost:6443"
+			},
+			wantErr: "spec.issuerURL: Forbidden",
+		},
+		"issuer on a link-local address": {
+			cluster: "root:org:team",
+			spec: func(spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec) {
+				spec.IssuerURL = "https://169.254.169.254"
+			},
+			wantErr: "spec.issuerURL: Forbidden",
+		},
+		"webhook on a private address": {
+			cluster: "root:org",
+			webhook: true,
+			spec: func(spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec) {
+				spec.Webhook.URL = "https://[fd00::1]:8443/authenticate"
+			},
+			wantErr: "spec.webhook.url: Forbidden",
+		},
+		"private address allowed by the operator": {
+			cluster: "root:org",
+			webhook: true,
+			spec: func(spec *tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec) {
+				spec.Webhook.URL = "https://10.0.0.1/authenticate"
+			},
+			allowPrivateNetworks: true,
+		},
+		"last allowed configuration": {
+			cluster:  "root:org:team",
+			existing: MaxConfigurationsPerWorkspace - 1,
+		},
+		"too many configurations": {
+			cluster:  "root:org:team",
+			existing: MaxConfigurationsPerWorkspace,
+			wantErr:  "at most 10 WorkspaceAuthenticationConfigurations",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := &tenancyv1alpha1.WorkspaceAuthenticationConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "idp"},
+			}
+			if tt.webhook {
+				config.Spec = *webhook.DeepCopy()
+			} else {
+				config.Spec = *oidc.DeepCopy()
+			}
+			if tt.spec != nil {
+				tt.spec(&config.Spec)
+			}
+
+			p := &workspaceAuthentication{
+				Handler:              admission.NewHandler(admission.Create, admission.Update),
+				allowPrivateNetworks: tt.allowPrivateNetworks,
+				listConfigs: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceAuthenticationConfiguration, error) {
+					return make([]*tenancyv1alpha1.WorkspaceAuthenticationConfiguration, tt.existing), nil
+				},
+			}
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tt.cluster)})
+			err := p.Validate(ctx, createAttr(config), nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.True(t, apierrors.IsForbidden(err), "unexpected error: %v", err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
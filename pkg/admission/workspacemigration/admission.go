@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacemigration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/pkg/admission/initializers"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+const (
+	PluginName = "tenancy.kcp.dev/WorkspaceMigration"
+)
+
+func Register(plugins *admission.Plugins) {
+	plugins.Register(PluginName, func(_ io.Reader) (admission.Interface, error) {
+		return NewWorkspaceMigration(), nil
+	})
+}
+
+// workspaceMigration rejects writes to workspaces that are fenced by a WorkspaceMigration,
+// and keeps the target shard of a WorkspaceMigration immutable.
+//
+// A workspace is fenced while its ClusterWorkspace in the parent workspace carries the
+// experimental.tenancy.kcp.dev/migrating-to annotation. The fence is enforced by the shard
+// the workspace is currently scheduled to, from the ClusterWorkspace in its informers. Hence
+// only workspaces on the shard of their parent workspace are migrated. Writes of the migration
+// to the target shard are not fenced.
+type workspaceMigration struct {
+	*admission.Handler
+
+	getClusterWorkspace func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	shardName           string
+
+	clusterWorkspacesHasSynced cache.InformerSynced
+}
+
+var _ admission.ValidationInterface = &workspaceMigration{}
+var _ admission.InitializationValidator = &workspaceMigration{}
+var _ = initializers.WantsKcpInformers(&workspaceMigration{})
+var _ = initializers.WantsShardName(&workspaceMigration{})
+
+// NewWorkspaceMigration returns a new WorkspaceMigration admission plugin.
+func NewWorkspaceMigration() admission.ValidationInterface {
+	p := &workspaceMigration{
+		Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+	}
+	p.SetReadyFunc(func() bool {
+		return p.clusterWorkspacesHasSynced()
+	})
+	return p
+}
+
+func (p *workspaceMigration) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) error {
+	if a.GetResource().GroupResource() == tenancyv1alpha1.Resource("workspacemigrations") && a.GetOperation() == admission.Update && a.GetSubresource() == "" {
+		return p.validateMigration(a)
+	}
+
+	clusterName, err := genericapirequest.ClusterNameFrom(ctx)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	parent, hasParent := clusterName.Parent()
+	if !hasParent {
+		return nil
+	}
+	workspace, err := p.getClusterWorkspace(parent, clusterName.Base())
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	if workspace.Status.Location.Current != p.shardName {
+		return nil
+	}
+	if target, fenced := workspace.Annotations[tenancyv1alpha1.WorkspaceMigrationFenceAnnotationKey]; fenced && target != p.shardName {
+		return admission.NewForbidden(a, fmt.Errorf("workspace %s is being migrated to shard %q, try again later", clusterName, target))
+	}
+	return nil
+}
+
+func (p *workspaceMigration) validateMigration(a admission.Attributes) error {
+	u, ok := a.GetObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetObject())
+	}
+	migration := &tenancyv1alpha1.WorkspaceMigration{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, migration); err != nil {
+		return fmt.Errorf("failed to convert unstructured to WorkspaceMigration: %w", err)
+	}
+	u, ok = a.GetOldObject().(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", a.GetOldObject())
+	}
+	old := &tenancyv1alpha1.WorkspaceMigration{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, old); err != nil {
+		return fmt.Errorf("failed to convert unstructured to WorkspaceMigration: %w", err)
+	}
+
+	if old.Spec.TargetShard != migration.Spec.TargetShard {
+		return admission.NewForbidden(a, errors.New("spec.targetShard is immutable"))
+	}
+	return nil
+}
+
+// SetKcpInformers implements the WantsKcpInformers interface.
+func (p *workspaceMigration) SetKcpInformers(f kcpinformers.SharedInformerFactory) {
+	clusterWorkspacesInformer := f.Tenancy().V1alpha1().ClusterWorkspaces()
+
+	p.clusterWorkspacesHasSynced = clusterWorkspacesInformer.Informer().HasSynced
+	p.getClusterWorkspace = func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		return clusterWorkspacesInformer.Lister().Cluster(clusterName).Get(name)
+	}
+}
+
+// SetShardName implements the WantsShardName interface.
+func (p *workspaceMigration) SetShardName(shardName string) {
+	p.shardName = shardName
+}
+
+func (p *workspaceMigration) ValidateInitialization() error {
+	if p.getClusterWorkspace == nil {
+		return errors.New("missing getClusterWorkspace")
+	}
+	if p.shardName == "" {
+		return errors.New("missing shardName")
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacemigration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/admission"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+var (
+	widgets    = schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	migrations = tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacemigrations")
+)
+
+func toUnstructured(t *testing.T, obj runtime.Object) *unstructured.Unstructured {
+	t.Helper()
+
+	if obj == nil {
+		return nil
+	}
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	require.NoError(t, err)
+	return &unstructured.Unstructured{Object: raw}
+}
+
+func attr(t *testing.T, obj, old runtime.Object, resource schema.GroupVersionResource, op admission.Operation) admission.Attributes {
+	t.Helper()
+
+	var newObj, oldObj runtime.Object
+	if u := toUnstructured(t, obj); u != nil {
+		newObj = u
+	}
+	if u := toUnstructured(t, old); u != nil {
+		oldObj = u
+	}
+	return admission.NewAttributesRecord(
+		newObj,
+		oldObj,
+		resource.GroupVersion().WithKind("Object"),
+		"",
+		"object",
+		resource,
+		"",
+		op,
+		nil,
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func migration(target string) *tenancyv1alpha1.WorkspaceMigration {
+	return &tenancyv1alpha1.WorkspaceMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "consumer",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+		Spec: tenancyv1alpha1.WorkspaceMigrationSpec{TargetShard: target},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		current     string
+		cluster     string
+		obj, old    runtime.Object
+		resource    schema.GroupVersionResource
+		op          admission.Operation
+		wantErr     string
+	}{
+		"create in unfenced workspace": {
+			cluster:  "root:org:consumer",
+			obj:      &unstructured.Unstructured{},
+			resource: widgets,
+			op:       admission.Create,
+		},
+		"create in fenced workspace": {
+			annotations: map[string]string{tenancyv1alpha1.WorkspaceMigrationFenceAnnotationKey: "beta"},
+			cluster:     "root:org:consumer",
+			obj:         &unstructured.Unstructured{},
+			resource:    widgets,
+			op:          admission.Create,
+			wantErr:     `workspace root:org:consumer is being migrated to shard "beta"`,
+		},
+		"delete in fenced workspace": {
+			annotations: map[string]string{tenancyv1alpha1.WorkspaceMigrationFenceAnnotationKey: "beta"},
+			cluster:     "root:org:consumer",
+			old:         &unstructured.Unstructured{},
+			resource:    widgets,
+			op:          admission.Delete,
+			wantErr:     `workspace root:org:consumer is being migrated to shard "beta"`,
+		},
+		"create in fenced workspace on target shard": {
+			annotations: map[string]string{tenancyv1alpha1.WorkspaceMigrationFenceAnnotationKey: "alpha"},
+			cluster:     "root:org:consumer",
+			obj:         &unstructured.Unstructured{},
+			resource:    widgets,
+			op:          admission.Create,
+		},
+		"create in fenced workspace on other shard": {
+			annotations: map[string]string{tenancyv1alpha1.WorkspaceMigrationFenceAnnotationKey: "beta"},
+			current:     "gamma",
+			cluster:     "root:org:consumer",
+			obj:         &unstructured.Unstructured{},
+			resource:    widgets,
+			op:          admission.Create,
+		},
+		"create in other workspace": {
+			annotations: map[string]string{tenancyv1alpha1.WorkspaceMigrationFenceAnnotationKey: "beta"},
+			cluster:     "root:org:other",
+			obj:         &unstructured.Unstructured{},
+			resource:    widgets,
+			op:          admission.Create,
+		},
+		"create in root": {
+			cluster:  "root",
+			obj:      &unstructured.Unstructured{},
+			resource: widgets,
+			op:       admission.Create,
+		},
+		"unchanged target shard": {
+			cluster:  "root:org",
+			obj:      migration("beta"),
+			old:      migration("beta"),
+			resource: migrations,
+			op:       admission.Update,
+		},
+		"changed target shard": {
+			cluster:  "root:org",
+			obj:      migration("gamma"),
+			old:      migration("beta"),
+			resource: migrations,
+			op:       admission.Update,
+			wantErr:  "spec.targetShard is immutable",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			workspace := &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "consumer",
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey: "root:org",
+					},
+				},
+			}
+			for k, v := range tt.annotations {
+				workspace.Annotations[k] = v
+			}
+			workspace.Status.Location.Current = "alpha"
+			if tt.current != "" {
+				workspace.Status.Location.Current = tt.current
+			}
+
+			p := &workspaceMigration{
+				Handler:   admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+				shardName: "alpha",
+				getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					if clusterName == logicalcluster.From(workspace) && name == workspace.Name {
+						return workspace, nil
+					}
+					return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+				},
+			}
+
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: logicalcluster.New(tt.cluster)})
+			err := p.Validate(ctx, attr(t, tt.obj, tt.old, tt.resource, tt.op), nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.True(t, apierrors.IsForbidden(err), "unexpected error: %v", err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
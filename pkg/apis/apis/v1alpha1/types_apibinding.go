@@ -79,8 +79,101 @@ type APIBindingSpec struct {
 	//
 	// +optional
 	PermissionClaims []AcceptablePermissionClaim `json:"permissionClaims,omitempty"`
+
+	// conflictPolicy determines what happens if another APIBinding in this workspace binds
+	// one of the same group resources:
+	//
+	// - Reject (default): the conflicting resources are not bound.
+	// - PriorityOrder: the resources are bound, and requests are served by the APIBinding with
+	//   the highest priority.
+	// - FirstWins: the resources are bound, and requests are served by the oldest APIBinding.
+	//
+	// Overlapping bindings are only allowed if neither APIBinding rejects conflicts. Among those,
+	// requests are served by the APIBinding with the highest priority, where FirstWins counts as
+	// priority 0, then by the oldest, then by name.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=Reject;PriorityOrder;FirstWins
+	ConflictPolicy APIBindingConflictPolicy `json:"conflictPolicy,omitempty"`
+
+	// localCRDPolicy determines what happens if a CustomResourceDefinition in this workspace
+	// defines one of the group resources bound by this APIBinding:
+	//
+	// - Reject (default): such CustomResourceDefinitions cannot be created, and resources overlapping
+	//   with an existing CustomResourceDefinition are not bound.
+	// - Shadow: the resources are bound, such CustomResourceDefinitions can be created, and requests
+	//   are served by the CustomResourceDefinition instead of the APIBinding. This allows to deliberately
+	//   replace a bound API with a local one, e.g. for testing. The BoundResourcesServed condition
+	//   reports the shadowed resources.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=Reject;Shadow
+	LocalCRDPolicy APIBindingLocalCRDPolicy `json:"localCRDPolicy,omitempty"`
+
+	// namespaces restricts the bound resources to the given namespaces of this workspace, so that a
+	// team can consume an API within its namespaces without making it available to the whole workspace.
+	// Namespaced resources are only served in these namespaces, and when listed across all namespaces.
+	// Discovery, which is not specific to a namespace, lists them nevertheless. Cluster-scoped resources
+	// are neither served nor discovered.
+	//
+	// If empty, the bound resources are served in the whole workspace.
+	//
+	// +optional
+	// +listType=set
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// priority of this APIBinding when resolving conflicts with the PriorityOrder policy.
+	// Higher values win.
+	//
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// schemaRevision pins the bound APIs to a revision of their APIResourceSchemas, i.e. to the
+	// APIResourceSchemas named <schemaRevision>.<resource>.<group> in the workspace of the APIExport,
+	// instead of following spec.latestResourceSchemas of the APIExport. This allows consumers to opt
+	// out of automatic schema updates by the API service provider.
+	//
+	// If the revision does not exist for a resource, the currently bound APIResourceSchema is kept,
+	// or the latest one is bound if the resource is not bound yet. The APIResourceSchemasLatest
+	// condition reports the drift from the latest APIResourceSchemas.
+	//
+	// "latest" (the default) follows spec.latestResourceSchemas of the APIExport.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z0-9])?$`
+	SchemaRevision string `json:"schemaRevision,omitempty"`
 }
 
+// LatestSchemaRevision is the schema revision of an APIBinding following the latest APIResourceSchemas
+// of the APIExport.
+const LatestSchemaRevision = "latest"
+
+// APIBindingConflictPolicy determines how a conflict between APIBindings binding the same
+// group resource in a workspace is resolved.
+type APIBindingConflictPolicy string
+
+const (
+	// APIBindingConflictPolicyReject refuses to bind resources that are bound by another APIBinding.
+	APIBindingConflictPolicyReject APIBindingConflictPolicy = "Reject"
+	// APIBindingConflictPolicyPriorityOrder serves conflicting resources from the APIBinding with the
+	// highest spec.priority.
+	APIBindingConflictPolicyPriorityOrder APIBindingConflictPolicy = "PriorityOrder"
+	// APIBindingConflictPolicyFirstWins serves conflicting resources from the oldest APIBinding.
+	APIBindingConflictPolicyFirstWins APIBindingConflictPolicy = "FirstWins"
+)
+
+// APIBindingLocalCRDPolicy determines how a conflict between an APIBinding and a CustomResourceDefinition
+// of the same group resource in a workspace is resolved.
+type APIBindingLocalCRDPolicy string
+
+const (
+	// APIBindingLocalCRDPolicyReject refuses CustomResourceDefinitions overlapping with the bound resources.
+	APIBindingLocalCRDPolicyReject APIBindingLocalCRDPolicy = "Reject"
+	// APIBindingLocalCRDPolicyShadow serves overlapping resources from the CustomResourceDefinitions of the
+	// workspace instead of the APIBinding.
+	APIBindingLocalCRDPolicyShadow APIBindingLocalCRDPolicy = "Shadow"
+)
+
 // AcceptablePermissionClaim is a PermissionClaim that records if the user accepts or rejects it.
 type AcceptablePermissionClaim struct {
 	PermissionClaim `json:",inline"`
@@ -171,6 +264,30 @@ type APIBindingStatus struct {
 	// the binding to grant.
 	// +optional
 	ExportPermissionClaims []PermissionClaim `json:"exportPermissionClaims,omitempty"`
+
+	// usage is the use of the bound resources in the workspace, as aggregated into the usage
+	// of the APIExport. It is recomputed periodically by the shard of the workspace.
+	//
+	// +optional
+	Usage *APIBindingUsage `json:"usage,omitempty"`
+}
+
+// APIBindingUsage describes the use of the bound resources in the workspace of an APIBinding.
+type APIBindingUsage struct {
+	// resources lists the number of objects of every bound resource in the workspace.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=resource
+	Resources []ResourceUsage `json:"resources,omitempty"`
+
+	// lastUsedTime is the latest time an object of a bound resource was created or updated
+	// in the workspace, as far as still recorded in the objects. It is unset if there are
+	// no objects.
+	//
+	// +optional
+	LastUsedTime *metav1.Time `json:"lastUsedTime,omitempty"`
 }
 
 // These are valid conditions of APIBinding.
@@ -219,6 +336,41 @@ const (
 	// PermissionClaimsApplied is a condition for APIBinding that indicates that all the accepted permission claims
 	// have been applied.
 	PermissionClaimsApplied conditionsv1alpha1.ConditionType = "PermissionClaimsApplied"
+
+	// BoundAPIVersionsUpToDate is a condition for APIBinding that indicates whether objects of the bound APIs are
+	// stored in versions that the APIExport provider deprecated.
+	BoundAPIVersionsUpToDate conditionsv1alpha1.ConditionType = "BoundAPIVersionsUpToDate"
+
+	// DeprecatedAPIVersionsInUseReason is a reason for the BoundAPIVersionsUpToDate condition that objects of at
+	// least one bound API are stored in a deprecated version. The message contains the deprecation warnings.
+	DeprecatedAPIVersionsInUseReason = "DeprecatedAPIVersionsInUse"
+
+	// APIResourceSchemasCurrent is an informational condition for APIBinding that indicates whether the
+	// APIResourceSchemas of the referenced APIExport are current, i.e. not deprecated and not superseded by newer
+	// APIResourceSchemas in the export workspace.
+	APIResourceSchemasCurrent conditionsv1alpha1.ConditionType = "APIResourceSchemasCurrent"
+
+	// OutdatedAPIResourceSchemasReason is a reason for the APIResourceSchemasCurrent condition that at least one
+	// APIResourceSchema of the APIExport is deprecated or superseded. The message contains the details.
+	OutdatedAPIResourceSchemasReason = "OutdatedAPIResourceSchemas"
+
+	// APIResourceSchemasLatest is an informational condition for APIBinding that indicates whether the bound
+	// APIResourceSchemas are the latest ones of the referenced APIExport, or drifted from them because
+	// the APIBinding is pinned to a schema revision.
+	APIResourceSchemasLatest conditionsv1alpha1.ConditionType = "APIResourceSchemasLatest"
+
+	// SchemaRevisionPinnedReason is a reason for the APIResourceSchemasLatest condition that at least one bound
+	// APIResourceSchema is not the latest one because of spec.schemaRevision. The message contains the drift.
+	SchemaRevisionPinnedReason = "SchemaRevisionPinned"
+
+	// BoundResourcesServed is a condition for APIBinding that indicates whether all the bound resources are
+	// served from the APIBinding, i.e. none of them is shadowed by a CustomResourceDefinition of the workspace.
+	BoundResourcesServed conditionsv1alpha1.ConditionType = "BoundResourcesServed"
+
+	// ShadowedByLocalCRDsReason is a reason for the BoundResourcesServed condition that at least one bound
+	// resource is served by a CustomResourceDefinition of the workspace, because of spec.localCRDPolicy.
+	// The message contains the shadowed resources.
+	ShadowedByLocalCRDsReason = "ShadowedByLocalCRDs"
 )
 
 // These are annotations for bound CRDs
@@ -291,6 +443,21 @@ type BoundAPIResourceSchema struct {
 	// +required
 	// +kubebuilder:validation:MinLength=1
 	IdentityHash string `json:"identityHash"`
+
+	// storageIdentityHash is the identity hash under which the objects are stored in etcd.
+	// It differs from identityHash after the identity of the APIExport was rotated.
+	// Empty means identityHash.
+	//
+	// +optional
+	StorageIdentityHash string `json:"storageIdentityHash,omitempty"`
+}
+
+// StorageIdentity returns the identity hash determining the etcd prefix of the bound objects.
+func (s BoundAPIResourceSchema) StorageIdentity() string {
+	if s.StorageIdentityHash != "" {
+		return s.StorageIdentityHash
+	}
+	return s.IdentityHash
 }
 
 // APIBindingList is a list of APIBinding resources
@@ -35,6 +35,25 @@ const (
 	APIExportVirtualWorkspaceURLsReady conditionsv1alpha1.ConditionType = "VirtualWorkspaceURLsReady"
 
 	ErrorGeneratingURLsReason = "ErrorGeneratingURLs"
+
+	// APIExportBoundSchemasConsistent is true if all APIBindings to the APIExport have bound the same
+	// APIResourceSchema for each group and resource. Wildcard requests for skewed resources are served
+	// with the greatest common denominator of the bound schemas.
+	APIExportBoundSchemasConsistent conditionsv1alpha1.ConditionType = "BoundSchemasConsistent"
+
+	BoundSchemasSkewedReason = "BoundSchemasSkewed"
+)
+
+const (
+	// AnnotationAllowIncompatibleSchemaChangesKey on an APIExport allows replacing an APIResourceSchema in
+	// spec.latestResourceSchemas by one that is not a compatible evolution of it, e.g. removing fields or
+	// served versions, or changing field types.
+	AnnotationAllowIncompatibleSchemaChangesKey = "apis.kcp.dev/allow-incompatible-schema-changes"
+
+	// AnnotationRotateIdentityKey on an APIExport holds the identity hash the APIExport is rotated to. When the
+	// identity referenced by spec.identity changes to this hash, it replaces status.identityHash. Objects of
+	// the bound resources stay stored under status.storageIdentityHash.
+	AnnotationRotateIdentityKey = "apis.kcp.dev/rotate-identity"
 )
 
 // These are for APIExport identity.
@@ -154,6 +173,91 @@ type APIExportSpec struct {
 	// +listMapKey=group
 	// +listMapKey=resource
 	PermissionClaims []PermissionClaim `json:"permissionClaims,omitempty"`
+
+	// objectCountLimits cap the number of objects of the exported resources that each
+	// workspace binding to this APIExport may create. Objects in all namespaces of the
+	// workspace are counted together. Resources without limit are not capped.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=resource
+	ObjectCountLimits []ObjectCountLimit `json:"objectCountLimits,omitempty"`
+
+	// schemaRetention configures what happens to APIResourceSchemas in the workspace of
+	// this APIExport that are superseded by one of its latestResourceSchemas and no longer
+	// bound by any APIBinding. If unset, superseded schemas are kept.
+	//
+	// +optional
+	SchemaRetention *SchemaRetentionPolicy `json:"schemaRetention,omitempty"`
+
+	// virtualWorkspaceAccess restricts the clients that can reach the virtual workspace URLs of this
+	// APIExport, e.g. to the controllers of the service provider. If unset, every client authorized
+	// by the virtual workspace can reach them.
+	//
+	// +optional
+	VirtualWorkspaceAccess *VirtualWorkspaceAccess `json:"virtualWorkspaceAccess,omitempty"`
+}
+
+// SchemaRetentionAction is what happens to a superseded APIResourceSchema after its retention period.
+//
+// +kubebuilder:validation:Enum=Archive;Delete
+type SchemaRetentionAction string
+
+const (
+	// SchemaRetentionActionArchive labels the APIResourceSchema with APIResourceSchemaArchivedLabel.
+	SchemaRetentionActionArchive SchemaRetentionAction = "Archive"
+	// SchemaRetentionActionDelete deletes the APIResourceSchema.
+	SchemaRetentionActionDelete SchemaRetentionAction = "Delete"
+)
+
+// APIResourceSchemaArchivedLabel is set to "true" on APIResourceSchemas archived by the schema
+// retention policy of an APIExport.
+const APIResourceSchemaArchivedLabel = "apis.kcp.dev/archived"
+
+// SchemaRetentionPolicy defines the retention of superseded APIResourceSchemas of an APIExport.
+type SchemaRetentionPolicy struct {
+	// retentionPeriod is how long a superseded APIResourceSchema is retained, counted from
+	// the creation of the APIResourceSchema superseding it. A schema still bound by an
+	// APIBinding is retained regardless.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	RetentionPeriod metav1.Duration `json:"retentionPeriod"`
+
+	// action is what happens to a superseded APIResourceSchema after the retention period:
+	// "Archive" labels it with apis.kcp.dev/archived=true, "Delete" deletes it.
+	//
+	// +optional
+	// +kubebuilder:default=Archive
+	Action SchemaRetentionAction `json:"action,omitempty"`
+}
+
+// VirtualWorkspaceAccess restricts the clients that can reach a virtual workspace URL. A request
+// must match every criterion that is set.
+type VirtualWorkspaceAccess struct {
+	// sourceCIDRs are the networks requests must come from, e.g. 10.0.0.0/8. The client address
+	// is the last entry of the X-Forwarded-For header if set, e.g. by the kcp front-proxy, or the
+	// peer address of the connection otherwise. If empty, requests can come from any network.
+	// Requests are rejected if an entry is not a valid CIDR.
+	//
+	// +optional
+	// +listType=set
+	SourceCIDRs []string `json:"sourceCIDRs,omitempty"`
+
+	// users are the names of the users allowed to reach the virtual workspace, e.g.
+	// system:serviceaccount:default:syncer. A request is allowed if its user is listed in users,
+	// or one of its groups in groups. If both are empty, every user is allowed.
+	//
+	// +optional
+	// +listType=set
+	Users []string `json:"users,omitempty"`
+
+	// groups are the groups of users allowed to reach the virtual workspace.
+	//
+	// +optional
+	// +listType=set
+	Groups []string `json:"groups,omitempty"`
 }
 
 // Identity defines the identity of an APIExport, i.e. determines the etcd prefix
@@ -163,6 +267,31 @@ type Identity struct {
 	//
 	// +optional
 	SecretRef *corev1.SecretReference `json:"secretRef,omitempty"`
+
+	// providerRef is a reference to an API identity stored outside of kcp, e.g. in a KMS
+	// or an external secret manager, which is fetched through an identity provider configured
+	// on the kcp server. It is mutually exclusive with secretRef.
+	//
+	// +optional
+	ProviderRef *IdentityProviderReference `json:"providerRef,omitempty"`
+}
+
+// IdentityProviderReference references an API identity held by an identity provider.
+type IdentityProviderReference struct {
+	// provider is the name of the identity provider as configured on the kcp server.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Provider string `json:"provider"`
+
+	// key identifies the API identity within the provider, e.g. the name of a secret
+	// in the secret manager.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
 }
 
 // MaximalPermissionPolicy is a wrapper type around the multiple options that would be allowed.
@@ -170,6 +299,30 @@ type MaximalPermissionPolicy struct {
 	// local is the policy that is defined in same workspace as the API Export.
 	// +optional
 	Local *LocalAPIExportPolicy `json:"local,omitempty"`
+
+	// protectedFields are fields of the exported resources that users of workspaces binding to this
+	// APIExport cannot set on creation or change on update, e.g. because only the service provider
+	// writes them through the APIExport virtual workspace. Members of the system:masters group are
+	// not restricted.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=resource
+	ProtectedFields []ProtectedFields `json:"protectedFields,omitempty"`
+}
+
+// ProtectedFields are fields of an exported resource that consumers cannot write.
+type ProtectedFields struct {
+	GroupResource `json:","`
+
+	// paths are the dot separated paths of the protected fields, e.g. spec.replicas.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Paths []string `json:"paths"`
 }
 
 // LocalAPIExportPolicy is a maximal permission policy
@@ -209,7 +362,7 @@ type PermissionClaim struct {
 	IdentityHash string `json:"identityHash,omitempty"`
 }
 
-// +kubebuilder:validation:XValidation:rule="has(self.__namespace__) || has(self.name)",message="at least one field must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.__namespace__) || has(self.name) || has(self.namespaceSelector)",message="at least one field must be set"
 type ResourceSelector struct {
 	// name of an object within a claimed group/resource.
 	// It matches the metadata.name field of the underlying object.
@@ -228,6 +381,14 @@ type ResourceSelector struct {
 	// +kubebuilder:validation:MinLength=1
 	Namespace string `json:"namespace,omitempty"`
 
+	// namespaceSelector selects the namespaces by their labels. For namespaced objects,
+	// it matches the labels of the namespace containing the object. For namespaces
+	// themselves, it matches the labels of the namespace. Other cluster-scoped objects
+	// never match.
+	//
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
 	//
 	// WARNING: If adding new fields, add them to the XValidation check!
 	//
@@ -250,6 +411,18 @@ func (p PermissionClaim) Equal(claim PermissionClaim) bool {
 		p.IdentityHash == claim.IdentityHash
 }
 
+// ObjectCountLimit caps the number of objects of an exported resource per binding workspace.
+type ObjectCountLimit struct {
+	GroupResource `json:","`
+
+	// max is the maximal number of objects of the resource in a binding workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=0
+	Max int64 `json:"max"`
+}
+
 // GroupResource identifies a resource.
 type GroupResource struct {
 	// group is the name of an API group.
@@ -271,11 +444,19 @@ type GroupResource struct {
 // APIExportStatus defines the observed state of APIExport.
 type APIExportStatus struct {
 	// identityHash is the hash of the API identity key of this APIExport. This value
-	// is immutable as soon as it is set.
+	// is immutable as soon as it is set, unless the identity is rotated through the
+	// apis.kcp.dev/rotate-identity annotation.
 	//
 	// +optional
 	IdentityHash string `json:"identityHash,omitempty"`
 
+	// storageIdentityHash is the identity hash under which objects of the bound resources
+	// of this APIExport are stored. It is set to the original identityHash when the identity
+	// is rotated the first time, and never changes afterwards. Empty means identityHash.
+	//
+	// +optional
+	StorageIdentityHash string `json:"storageIdentityHash,omitempty"`
+
 	// conditions is a list of conditions that apply to the APIExport.
 	//
 	// +optional
@@ -284,6 +465,143 @@ type APIExportStatus struct {
 	// virtualWorkspaces contains all APIExport virtual workspace URLs.
 	// +optional
 	VirtualWorkspaces []VirtualWorkspace `json:"virtualWorkspaces,omitempty"`
+
+	// resourceSchemasInUse lists the APIResourceSchemas for the resources of this APIExport
+	// that are bound by at least one APIBinding, including superseded ones. It is only
+	// maintained if spec.schemaRetention is set.
+	//
+	// +optional
+	// +listType=set
+	ResourceSchemasInUse []string `json:"resourceSchemasInUse,omitempty"`
+
+	// schemaRollout shows for each bound resource of this APIExport how many APIBindings have
+	// picked up the APIResourceSchema in spec.latestResourceSchemas.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=resource
+	SchemaRollout []SchemaRolloutStatus `json:"schemaRollout,omitempty"`
+
+	// usage summarizes the consumers of this APIExport: the workspaces that have bound it,
+	// the number of objects of its resources, and when they were last written. It helps
+	// providers to plan the deprecation of resources.
+	//
+	// +optional
+	Usage *APIExportUsage `json:"usage,omitempty"`
+}
+
+// SchemaRolloutStatus describes the rollout of the latest APIResourceSchema of a resource to the APIBindings.
+type SchemaRolloutStatus struct {
+	// group is the API group of the resource.
+	//
+	// +optional
+	// +kubebuilder:default=""
+	Group string `json:"group,omitempty"`
+
+	// resource is the resource name.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Resource string `json:"resource"`
+
+	// updatedBindings is the number of APIBindings that have bound the latest APIResourceSchema.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	UpdatedBindings int32 `json:"updatedBindings"`
+
+	// totalBindings is the number of APIBindings that have bound the resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	TotalBindings int32 `json:"totalBindings"`
+
+	// outdatedBindings lists up to 10 APIBindings that have bound an older APIResourceSchema,
+	// as <workspace>|<name>.
+	//
+	// +optional
+	// +listType=atomic
+	OutdatedBindings []string `json:"outdatedBindings,omitempty"`
+}
+
+// APIExportUsage summarizes the consumers of an APIExport.
+type APIExportUsage struct {
+	// bindings is the number of APIBindings bound to the APIExport.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Bindings int32 `json:"bindings"`
+
+	// resources lists the number of objects of every bound resource across all consumers.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=resource
+	Resources []ResourceUsage `json:"resources,omitempty"`
+
+	// consumers lists up to 100 consuming workspaces, most recently used first.
+	//
+	// +optional
+	// +listType=atomic
+	Consumers []APIExportConsumer `json:"consumers,omitempty"`
+
+	// consumersTruncated is true if there are more consumers than listed in consumers.
+	//
+	// +optional
+	ConsumersTruncated bool `json:"consumersTruncated,omitempty"`
+}
+
+// APIExportConsumer describes the use of an APIExport by a workspace.
+type APIExportConsumer struct {
+	// workspace is the logical cluster name of the consuming workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Workspace string `json:"workspace"`
+
+	// apiBinding is the name of the APIBinding in the workspace bound to the APIExport.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	APIBinding string `json:"apiBinding"`
+
+	// resources lists the number of objects of every bound resource in the workspace.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=resource
+	Resources []ResourceUsage `json:"resources,omitempty"`
+
+	// lastUsedTime is the latest time an object of a bound resource was created or updated
+	// in the workspace, as far as still recorded in the objects. It is unset if there are
+	// no objects.
+	//
+	// +optional
+	LastUsedTime *metav1.Time `json:"lastUsedTime,omitempty"`
+}
+
+// ResourceUsage is the number of objects of a bound resource.
+type ResourceUsage struct {
+	// group is the API group of the resource.
+	//
+	// +optional
+	// +kubebuilder:default=""
+	Group string `json:"group,omitempty"`
+
+	// resource is the resource name.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Resource string `json:"resource"`
+
+	// objects is the number of objects of the resource.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Objects int32 `json:"objects"`
 }
 
 type VirtualWorkspace struct {
@@ -180,6 +180,16 @@ func TestResourceSelectorCELValidation(t *testing.T) {
 				"namespace": "bar",
 			},
 		},
+		{
+			name: "namespaceSelector is set",
+			current: map[string]interface{}{
+				"namespaceSelector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						"team": "payments",
+					},
+				},
+			},
+		},
 	}
 
 	validators := apitest.ValidatorsFromFile(t, "../../../../config/crds/apis.kcp.dev_apiexports.yaml")
@@ -18,6 +18,8 @@ package v1alpha1
 
 import (
 	"encoding/json"
+	"fmt"
+	"time"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -75,6 +77,15 @@ type APIResourceSchemaSpec struct {
 	// +listMapKey=name
 	// +kubebuilder:validation:MinItems=1
 	Versions []APIResourceVersion `json:"versions"`
+
+	// conversion defines conversion settings for the bound CRDs of this schema. Only the
+	// None and Webhook strategies are supported, and webhooks must be referenced by URL
+	// because the bound CRDs do not live in the workspace of the service provider.
+	//
+	// If not set, the None strategy is used.
+	//
+	// +optional
+	Conversion *apiextensionsv1.CustomResourceConversion `json:"conversion,omitempty"`
 }
 
 // APIResourceVersion describes one API version of a resource.
@@ -109,6 +120,13 @@ type APIResourceVersion struct {
 	//
 	// +optional
 	DeprecationWarning *string `json:"deprecationWarning,omitempty"`
+	// sunsetTime is when this version is taken out of service. It is announced in the
+	// deprecation warning. From then on, new APIBindings to an APIExport serving this version
+	// are rejected, while existing ones keep working.
+	// May only be set when `deprecated` is true.
+	//
+	// +optional
+	SunsetTime *metav1.Time `json:"sunsetTime,omitempty"`
 	// schema describes the structural schema used for validation, pruning, and defaulting
 	// of this version of the custom resource.
 	//
@@ -163,3 +181,17 @@ func (v *APIResourceVersion) SetSchema(schema *apiextensionsv1.JSONSchemaProps)
 	v.Schema.Raw = raw
 	return nil
 }
+
+// DeprecationWarningFor returns the warning for clients of this version of the resource with the
+// given group and kind, if deprecated: the deprecationWarning or a default one, followed by the
+// sunset time if set.
+func (v *APIResourceVersion) DeprecationWarningFor(group, kind string) string {
+	warning := fmt.Sprintf("%s/%s %s is deprecated", group, v.Name, kind)
+	if v.DeprecationWarning != nil {
+		warning = *v.DeprecationWarning
+	}
+	if v.SunsetTime != nil {
+		warning = fmt.Sprintf("%s; sunset on %s", warning, v.SunsetTime.UTC().Format(time.RFC3339))
+	}
+	return warning
+}
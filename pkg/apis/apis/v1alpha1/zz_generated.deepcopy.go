@@ -24,6 +24,7 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
@@ -101,6 +102,11 @@ func (in *APIBindingSpec) DeepCopyInto(out *APIBindingSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -145,6 +151,11 @@ func (in *APIBindingStatus) DeepCopyInto(out *APIBindingStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(APIBindingUsage)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -158,6 +169,31 @@ func (in *APIBindingStatus) DeepCopy() *APIBindingStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIBindingUsage) DeepCopyInto(out *APIBindingUsage) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceUsage, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUsedTime != nil {
+		in, out := &in.LastUsedTime, &out.LastUsedTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIBindingUsage.
+func (in *APIBindingUsage) DeepCopy() *APIBindingUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(APIBindingUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIExport) DeepCopyInto(out *APIExport) {
 	*out = *in
@@ -186,6 +222,31 @@ func (in *APIExport) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIExportConsumer) DeepCopyInto(out *APIExportConsumer) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceUsage, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUsedTime != nil {
+		in, out := &in.LastUsedTime, &out.LastUsedTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIExportConsumer.
+func (in *APIExportConsumer) DeepCopy() *APIExportConsumer {
+	if in == nil {
+		return nil
+	}
+	out := new(APIExportConsumer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIExportList) DeepCopyInto(out *APIExportList) {
 	*out = *in
@@ -244,6 +305,21 @@ func (in *APIExportSpec) DeepCopyInto(out *APIExportSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ObjectCountLimits != nil {
+		in, out := &in.ObjectCountLimits, &out.ObjectCountLimits
+		*out = make([]ObjectCountLimit, len(*in))
+		copy(*out, *in)
+	}
+	if in.SchemaRetention != nil {
+		in, out := &in.SchemaRetention, &out.SchemaRetention
+		*out = new(SchemaRetentionPolicy)
+		**out = **in
+	}
+	if in.VirtualWorkspaceAccess != nil {
+		in, out := &in.VirtualWorkspaceAccess, &out.VirtualWorkspaceAccess
+		*out = new(VirtualWorkspaceAccess)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -272,6 +348,23 @@ func (in *APIExportStatus) DeepCopyInto(out *APIExportStatus) {
 		*out = make([]VirtualWorkspace, len(*in))
 		copy(*out, *in)
 	}
+	if in.ResourceSchemasInUse != nil {
+		in, out := &in.ResourceSchemasInUse, &out.ResourceSchemasInUse
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SchemaRollout != nil {
+		in, out := &in.SchemaRollout, &out.SchemaRollout
+		*out = make([]SchemaRolloutStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(APIExportUsage)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -285,6 +378,34 @@ func (in *APIExportStatus) DeepCopy() *APIExportStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIExportUsage) DeepCopyInto(out *APIExportUsage) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourceUsage, len(*in))
+		copy(*out, *in)
+	}
+	if in.Consumers != nil {
+		in, out := &in.Consumers, &out.Consumers
+		*out = make([]APIExportConsumer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIExportUsage.
+func (in *APIExportUsage) DeepCopy() *APIExportUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(APIExportUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIResourceSchema) DeepCopyInto(out *APIResourceSchema) {
 	*out = *in
@@ -356,6 +477,11 @@ func (in *APIResourceSchemaSpec) DeepCopyInto(out *APIResourceSchemaSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Conversion != nil {
+		in, out := &in.Conversion, &out.Conversion
+		*out = new(v1.CustomResourceConversion)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -377,6 +503,10 @@ func (in *APIResourceVersion) DeepCopyInto(out *APIResourceVersion) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.SunsetTime != nil {
+		in, out := &in.SunsetTime, &out.SunsetTime
+		*out = (*in).DeepCopy()
+	}
 	in.Schema.DeepCopyInto(&out.Schema)
 	in.Subresources.DeepCopyInto(&out.Subresources)
 	if in.AdditionalPrinterColumns != nil {
@@ -497,6 +627,11 @@ func (in *Identity) DeepCopyInto(out *Identity) {
 		*out = new(corev1.SecretReference)
 		**out = **in
 	}
+	if in.ProviderRef != nil {
+		in, out := &in.ProviderRef, &out.ProviderRef
+		*out = new(IdentityProviderReference)
+		**out = **in
+	}
 	return
 }
 
@@ -510,6 +645,22 @@ func (in *Identity) DeepCopy() *Identity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentityProviderReference) DeepCopyInto(out *IdentityProviderReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityProviderReference.
+func (in *IdentityProviderReference) DeepCopy() *IdentityProviderReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentityProviderReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalAPIExportPolicy) DeepCopyInto(out *LocalAPIExportPolicy) {
 	*out = *in
@@ -534,6 +685,13 @@ func (in *MaximalPermissionPolicy) DeepCopyInto(out *MaximalPermissionPolicy) {
 		*out = new(LocalAPIExportPolicy)
 		**out = **in
 	}
+	if in.ProtectedFields != nil {
+		in, out := &in.ProtectedFields, &out.ProtectedFields
+		*out = make([]ProtectedFields, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -547,6 +705,23 @@ func (in *MaximalPermissionPolicy) DeepCopy() *MaximalPermissionPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectCountLimit) DeepCopyInto(out *ObjectCountLimit) {
+	*out = *in
+	out.GroupResource = in.GroupResource
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectCountLimit.
+func (in *ObjectCountLimit) DeepCopy() *ObjectCountLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectCountLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PermissionClaim) DeepCopyInto(out *PermissionClaim) {
 	*out = *in
@@ -554,7 +729,9 @@ func (in *PermissionClaim) DeepCopyInto(out *PermissionClaim) {
 	if in.ResourceSelector != nil {
 		in, out := &in.ResourceSelector, &out.ResourceSelector
 		*out = make([]ResourceSelector, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -569,9 +746,36 @@ func (in *PermissionClaim) DeepCopy() *PermissionClaim {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedFields) DeepCopyInto(out *ProtectedFields) {
+	*out = *in
+	out.GroupResource = in.GroupResource
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedFields.
+func (in *ProtectedFields) DeepCopy() *ProtectedFields {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedFields)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSelector) DeepCopyInto(out *ResourceSelector) {
 	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -585,6 +789,60 @@ func (in *ResourceSelector) DeepCopy() *ResourceSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceUsage) DeepCopyInto(out *ResourceUsage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceUsage.
+func (in *ResourceUsage) DeepCopy() *ResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaRetentionPolicy) DeepCopyInto(out *SchemaRetentionPolicy) {
+	*out = *in
+	out.RetentionPeriod = in.RetentionPeriod
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaRetentionPolicy.
+func (in *SchemaRetentionPolicy) DeepCopy() *SchemaRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaRolloutStatus) DeepCopyInto(out *SchemaRolloutStatus) {
+	*out = *in
+	if in.OutdatedBindings != nil {
+		in, out := &in.OutdatedBindings, &out.OutdatedBindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaRolloutStatus.
+func (in *SchemaRolloutStatus) DeepCopy() *SchemaRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualWorkspace) DeepCopyInto(out *VirtualWorkspace) {
 	*out = *in
@@ -601,6 +859,37 @@ func (in *VirtualWorkspace) DeepCopy() *VirtualWorkspace {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VirtualWorkspaceAccess) DeepCopyInto(out *VirtualWorkspaceAccess) {
+	*out = *in
+	if in.SourceCIDRs != nil {
+		in, out := &in.SourceCIDRs, &out.SourceCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VirtualWorkspaceAccess.
+func (in *VirtualWorkspaceAccess) DeepCopy() *VirtualWorkspaceAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(VirtualWorkspaceAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceExportReference) DeepCopyInto(out *WorkspaceExportReference) {
 	*out = *in
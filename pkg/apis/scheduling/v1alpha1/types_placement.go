@@ -82,8 +82,150 @@ type PlacementSpec struct {
 	// +optional
 	// +kubebuilder:validation:Pattern:="^root(:[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$"
 	LocationWorkspace string `json:"locationWorkspace,omitempty"`
+
+	// schedule shifts the placement between locations over time. While a window of the schedule
+	// is active, its locationSelectors are used instead of spec.locationSelectors. The selected
+	// location is only changed on a window transition if it does not match the selectors of the
+	// new window.
+	//
+	// +optional
+	Schedule *PlacementSchedule `json:"schedule,omitempty"`
+
+	// affinity prefers locations matching the given selectors. Among the locations selected by
+	// locationSelectors, those with the highest score are chosen, where the score of a location
+	// is the sum of the weights of the matching affinity terms minus the sum of the weights of
+	// the matching antiAffinity terms.
+	//
+	// +optional
+	Affinity []WeightedLocationSelector `json:"affinity,omitempty"`
+
+	// antiAffinity avoids locations matching the given selectors. See affinity for how
+	// the terms are scored.
+	//
+	// +optional
+	AntiAffinity []WeightedLocationSelector `json:"antiAffinity,omitempty"`
+
+	// spreadConstraints spread this placement and other placements of the workspace
+	// across locations. A location is only chosen if it keeps the difference between
+	// the number of placements selecting it and the least used location within maxSkew.
+	//
+	// +optional
+	SpreadConstraints []PlacementSpreadConstraint `json:"spreadConstraints,omitempty"`
+
+	// mode defines to how many SyncTargets of the selected location the namespaces of this placement
+	// are scheduled. With Single, one SyncTarget is picked. With All, the namespaces are scheduled to
+	// every ready SyncTarget of the location, similar to a DaemonSet, and follow SyncTargets joining
+	// or leaving the location.
+	//
+	// +optional
+	// +kubebuilder:default=Single
+	// +kubebuilder:validation:Enum=Single;All
+	Mode PlacementMode `json:"mode,omitempty"`
 }
 
+// PlacementMode defines to how many SyncTargets of a location a placement schedules.
+type PlacementMode string
+
+const (
+	// PlacementModeSingle schedules to one SyncTarget of the selected location.
+	PlacementModeSingle PlacementMode = "Single"
+
+	// PlacementModeAll schedules to every ready SyncTarget of the selected location.
+	PlacementModeAll PlacementMode = "All"
+)
+
+// WeightedLocationSelector is a location label selector with a weight.
+type WeightedLocationSelector struct {
+	// weight is added to (or subtracted from) the score of the locations matching selector.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+
+	// selector is a label selector for locations.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Selector metav1.LabelSelector `json:"selector"`
+}
+
+// PlacementSpreadConstraint limits how unevenly a group of placements is spread across locations.
+type PlacementSpreadConstraint struct {
+	// maxSkew is the maximum permitted difference between the number of placements of the
+	// group selecting a location and the number selecting the least used valid location.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	MaxSkew int32 `json:"maxSkew"`
+
+	// placementSelector selects the placements of the workspace that form the group. An
+	// empty selector selects all placements of the workspace.
+	//
+	// +optional
+	PlacementSelector metav1.LabelSelector `json:"placementSelector,omitempty"`
+}
+
+// PlacementSchedule is a recurring, time based policy of location selectors.
+type PlacementSchedule struct {
+	// timeZone is the IANA name of the time zone the windows are interpreted in,
+	// e.g. Europe/Berlin. It defaults to UTC.
+	//
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// windows is the list of windows of the schedule. If multiple windows are active at the
+	// same time, the first one in the list wins.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Windows []PlacementScheduleWindow `json:"windows"`
+}
+
+// PlacementScheduleWindow is a daily recurring time window.
+type PlacementScheduleWindow struct {
+	// name is the name of the window. It is recorded in the status when the window becomes active.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// days are the days of the week the window starts on. It defaults to every day.
+	//
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// start is the time of the day the window starts at, in the format HH:MM.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:="^([01][0-9]|2[0-3]):[0-5][0-9]$"
+	Start string `json:"start"`
+
+	// end is the time of the day the window ends at, in the format HH:MM. If end is not
+	// after start, the window ends on the next day.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:="^([01][0-9]|2[0-3]):[0-5][0-9]$"
+	End string `json:"end"`
+
+	// locationSelectors represents a slice of label selector to select a location while the window is active,
+	// these label selectors are logically ORed.
+	LocationSelectors []metav1.LabelSelector `json:"locationSelectors,omitempty"`
+}
+
+// Weekday is a day of the week.
+//
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
 type PlacementStatus struct {
 	// phase is the current phase of the placement
 	//
@@ -95,6 +237,16 @@ type PlacementStatus struct {
 	// +optional
 	SelectedLocation *LocationReference `json:"selectedLocation,omitempty"`
 
+	// activeWindow is the name of the schedule window that is currently active. It is empty
+	// if no window is active.
+	// +optional
+	ActiveWindow string `json:"activeWindow,omitempty"`
+
+	// transitions is the history of the most recent location changes caused by schedule window
+	// transitions, oldest first.
+	// +optional
+	Transitions []PlacementTransition `json:"transitions,omitempty"`
+
 	// Current processing state of the Placement.
 	// +optional
 	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
@@ -118,6 +270,34 @@ type LocationReference struct {
 	LocationName string `json:"locationName"`
 }
 
+// PlacementTransition records a change of the selected location caused by a schedule window transition.
+type PlacementTransition struct {
+	// time is the time of the transition.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Time metav1.Time `json:"time"`
+
+	// window is the name of the window that became active. It is empty if the transition
+	// was caused by the end of a window.
+	//
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// from is the location selected before the transition.
+	//
+	// +optional
+	From *LocationReference `json:"from,omitempty"`
+
+	// to is the location selected after the transition.
+	//
+	// +optional
+	To *LocationReference `json:"to,omitempty"`
+}
+
+// MaxPlacementTransitions is the number of transitions kept in the status of a placement.
+const MaxPlacementTransitions = 10
+
 type PlacementPhase string
 
 const (
@@ -150,6 +330,10 @@ const (
 	// LocationNotMatchReason is a reason for PlacementReady condition that no matched location for
 	// this placement can be found.
 	LocationNotMatchReason = "LocationNoMatch"
+
+	// ScheduleInvalidReason is a reason for PlacementReady condition that the schedule of the
+	// placement cannot be parsed.
+	ScheduleInvalidReason = "ScheduleInvalid"
 )
 
 // PlacementList is a list of locations.
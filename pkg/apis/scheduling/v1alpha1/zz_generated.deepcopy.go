@@ -258,6 +258,57 @@ func (in *PlacementList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementSchedule) DeepCopyInto(out *PlacementSchedule) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]PlacementScheduleWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSchedule.
+func (in *PlacementSchedule) DeepCopy() *PlacementSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementScheduleWindow) DeepCopyInto(out *PlacementScheduleWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+	if in.LocationSelectors != nil {
+		in, out := &in.LocationSelectors, &out.LocationSelectors
+		*out = make([]v1.LabelSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementScheduleWindow.
+func (in *PlacementScheduleWindow) DeepCopy() *PlacementScheduleWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementScheduleWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlacementSpec) DeepCopyInto(out *PlacementSpec) {
 	*out = *in
@@ -274,6 +325,32 @@ func (in *PlacementSpec) DeepCopyInto(out *PlacementSpec) {
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(PlacementSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = make([]WeightedLocationSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AntiAffinity != nil {
+		in, out := &in.AntiAffinity, &out.AntiAffinity
+		*out = make([]WeightedLocationSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SpreadConstraints != nil {
+		in, out := &in.SpreadConstraints, &out.SpreadConstraints
+		*out = make([]PlacementSpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -287,6 +364,23 @@ func (in *PlacementSpec) DeepCopy() *PlacementSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementSpreadConstraint) DeepCopyInto(out *PlacementSpreadConstraint) {
+	*out = *in
+	in.PlacementSelector.DeepCopyInto(&out.PlacementSelector)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementSpreadConstraint.
+func (in *PlacementSpreadConstraint) DeepCopy() *PlacementSpreadConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementSpreadConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlacementStatus) DeepCopyInto(out *PlacementStatus) {
 	*out = *in
@@ -295,6 +389,13 @@ func (in *PlacementStatus) DeepCopyInto(out *PlacementStatus) {
 		*out = new(LocationReference)
 		**out = **in
 	}
+	if in.Transitions != nil {
+		in, out := &in.Transitions, &out.Transitions
+		*out = make([]PlacementTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(conditionsv1alpha1.Conditions, len(*in))
@@ -314,3 +415,47 @@ func (in *PlacementStatus) DeepCopy() *PlacementStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementTransition) DeepCopyInto(out *PlacementTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = new(LocationReference)
+		**out = **in
+	}
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = new(LocationReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementTransition.
+func (in *PlacementTransition) DeepCopy() *PlacementTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeightedLocationSelector) DeepCopyInto(out *WeightedLocationSelector) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeightedLocationSelector.
+func (in *WeightedLocationSelector) DeepCopy() *WeightedLocationSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(WeightedLocationSelector)
+	in.DeepCopyInto(out)
+	return out
+}
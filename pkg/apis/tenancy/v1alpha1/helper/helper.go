@@ -18,6 +18,8 @@ package helper
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/kcp-dev/logicalcluster/v2"
 
@@ -38,6 +40,12 @@ func IsValidCluster(cluster logicalcluster.Name) bool {
 	return cluster.HasPrefix(v1alpha1.RootCluster) || cluster.HasPrefix(logicalcluster.New("system"))
 }
 
+// IsTenantCluster indicates whether a cluster belongs to a tenant, i.e. is
+// neither the root cluster nor rooted at system.
+func IsTenantCluster(cluster logicalcluster.Name) bool {
+	return cluster != v1alpha1.RootCluster && !cluster.HasPrefix(logicalcluster.New("system"))
+}
+
 // QualifiedObjectName builds a fully qualified identifier for an object
 // consisting of its logical cluster, namespace if applicable, and object
 // metadata name.
@@ -53,3 +61,36 @@ func QualifiedObjectName(obj metav1.Object) string {
 func WorkspaceLabelSelector(name string) string {
 	return fmt.Sprintf("%s=%s", v1beta1.WorkspaceNameLabel, name)
 }
+
+// ActiveWorkspaceAliases returns the logical clusters of the aliases of the given workspace
+// that are not expired at the given time.
+func ActiveWorkspaceAliases(ws *v1alpha1.ClusterWorkspace, now time.Time) []logicalcluster.Name {
+	var aliases []logicalcluster.Name
+	for _, alias := range ws.Spec.Aliases {
+		if !alias.IsExpired(now) && alias.Name != ws.Name {
+			aliases = append(aliases, logicalcluster.From(ws).Join(alias.Name))
+		}
+	}
+	return aliases
+}
+
+// ResolveWorkspaceAliases returns the logical cluster the given one refers to, resolving the
+// workspace aliases of every segment of the path from the root down, such that workspaces nested
+// in an aliased workspace can be reached through the alias too. lookupAlias returns the logical
+// cluster of the workspace with the given alias, if the alias resolves.
+func ResolveWorkspaceAliases(cluster logicalcluster.Name, lookupAlias func(alias logicalcluster.Name) (logicalcluster.Name, bool)) logicalcluster.Name {
+	if cluster.Empty() || cluster == logicalcluster.Wildcard {
+		return cluster
+	}
+
+	segments := strings.Split(cluster.String(), ":")
+	resolved := logicalcluster.New(segments[0])
+	for _, segment := range segments[1:] {
+		next := resolved.Join(segment)
+		if target, found := lookupAlias(next); found {
+			next = target
+		}
+		resolved = next
+	}
+	return resolved
+}
@@ -111,3 +111,38 @@ func TestWorkspaceLabelSelector(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveWorkspaceAliases(t *testing.T) {
+	aliases := map[logicalcluster.Name]logicalcluster.Name{
+		logicalcluster.New("root:old-org"):     logicalcluster.New("root:org"),
+		logicalcluster.New("root:org:old-ws"):  logicalcluster.New("root:org:ws"),
+		logicalcluster.New("root:other:alias"): logicalcluster.New("root:other:target"),
+	}
+	lookup := func(alias logicalcluster.Name) (logicalcluster.Name, bool) {
+		target, found := aliases[alias]
+		return target, found
+	}
+
+	tests := []struct {
+		cluster  string
+		resolved string
+	}{
+		{"root", "root"},
+		{"root:org", "root:org"},
+		{"root:org:ws", "root:org:ws"},
+		{"root:old-org", "root:org"},
+		{"root:old-org:ws", "root:org:ws"},
+		{"root:old-org:old-ws", "root:org:ws"},
+		{"root:old-org:old-ws:nested", "root:org:ws:nested"},
+		{"root:org:old-ws", "root:org:ws"},
+		{"root:unknown:old-ws", "root:unknown:old-ws"},
+		{"*", "*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cluster, func(t *testing.T) {
+			if got := ResolveWorkspaceAliases(logicalcluster.New(tt.cluster), lookup); got != logicalcluster.New(tt.resolved) {
+				t.Errorf("ResolveWorkspaceAliases(%s) = %s, want %s", tt.cluster, got, tt.resolved)
+			}
+		})
+	}
+}
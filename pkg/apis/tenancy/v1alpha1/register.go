@@ -51,6 +51,20 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ClusterWorkspaceTypeList{},
 		&ClusterWorkspaceShard{},
 		&ClusterWorkspaceShardList{},
+		&ClusterWorkspaceQuota{},
+		&ClusterWorkspaceQuotaList{},
+		&ClusterWorkspaceTemplate{},
+		&ClusterWorkspaceTemplateList{},
+		&ClusterWorkspaceTombstone{},
+		&ClusterWorkspaceTombstoneList{},
+		&WorkspaceAuthenticationConfiguration{},
+		&WorkspaceAuthenticationConfigurationList{},
+		&WorkspaceMigration{},
+		&WorkspaceMigrationList{},
+		&WorkspaceBackup{},
+		&WorkspaceBackupList{},
+		&WorkspaceRestore{},
+		&WorkspaceRestoreList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
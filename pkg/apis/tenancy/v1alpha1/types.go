@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/kcp-dev/logicalcluster/v2"
 
@@ -112,8 +113,102 @@ type ClusterWorkspaceSpec struct {
 	//
 	// +optional
 	Shard *ShardConstraints `json:"shard,omitempty"`
+
+	// auditPolicy configures auditing of requests targeting this workspace. It
+	// takes precedence over the audit policy of the workspace's type.
+	//
+	// +optional
+	AuditPolicy *AuditPolicy `json:"auditPolicy,omitempty"`
+
+	// aliases are additional names of the workspace in its parent workspace. Requests to
+	// the parent workspace path joined with an alias are served by this workspace, e.g. to
+	// give a workspace a new name while clients still use the old one, or the other way around.
+	// An alias does not resolve if a workspace of that name exists in the parent workspace, or
+	// if several workspaces use the same alias.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Aliases []ClusterWorkspaceAlias `json:"aliases,omitempty"`
+}
+
+// ClusterWorkspaceAlias is an additional name of a workspace in its parent workspace.
+type ClusterWorkspaceAlias struct {
+	// name is the alias. It must be different from the name of the workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// expirationTime is the time after which the alias does not resolve anymore, ending the
+	// transition window of clients moving to another name. Without it, the alias resolves as
+	// long as it is listed.
+	//
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+}
+
+// IsExpired returns true if the alias has an expiration time that is not after now.
+func (a ClusterWorkspaceAlias) IsExpired(now time.Time) bool {
+	return a.ExpirationTime != nil && !a.ExpirationTime.Time.After(now)
 }
 
+// AuditPolicy configures the audit level and stages of requests targeting a workspace. Requests are
+// audited by the server-wide audit policy in any case. If the workspace audit policy asks for a higher
+// level, additional audit events at that level are emitted, annotated with
+// tenancy.kcp.dev/audit-policy.
+type AuditPolicy struct {
+	// level is the audit level of requests targeting the workspace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Level AuditLevel `json:"level"`
+
+	// omitStages is a list of stages for which no audit events are emitted.
+	//
+	// +optional
+	// +listType=set
+	OmitStages []AuditStage `json:"omitStages,omitempty"`
+}
+
+// AuditLevel is the level of an audit policy, see the audit.k8s.io API.
+//
+// +kubebuilder:validation:Enum=None;Metadata;Request;RequestResponse
+type AuditLevel string
+
+const (
+	// AuditLevelNone disables auditing.
+	AuditLevelNone AuditLevel = "None"
+	// AuditLevelMetadata provides the basic level of auditing.
+	AuditLevelMetadata AuditLevel = "Metadata"
+	// AuditLevelRequest provides Metadata level of auditing, and additionally
+	// logs the request object (does not apply for non-resource requests).
+	AuditLevelRequest AuditLevel = "Request"
+	// AuditLevelRequestResponse provides Request level of auditing, and additionally
+	// logs the response object (does not apply for non-resource requests).
+	AuditLevelRequestResponse AuditLevel = "RequestResponse"
+)
+
+// AuditStage is a stage of request handling that audit events are emitted in, see the audit.k8s.io API.
+//
+// +kubebuilder:validation:Enum=RequestReceived;ResponseStarted;ResponseComplete;Panic
+type AuditStage string
+
+const (
+	// AuditStageRequestReceived is the stage for events generated as soon as the handler receives the request.
+	AuditStageRequestReceived AuditStage = "RequestReceived"
+	// AuditStageResponseStarted is the stage for events generated once the response headers are sent, but
+	// before the response body is sent. Only for long-running requests (e.g. watch).
+	AuditStageResponseStarted AuditStage = "ResponseStarted"
+	// AuditStageResponseComplete is the stage for events generated once the response body has been completed.
+	AuditStageResponseComplete AuditStage = "ResponseComplete"
+	// AuditStagePanic is the stage for events generated when a panic occurred.
+	AuditStagePanic AuditStage = "Panic"
+)
+
 type ShardConstraints struct {
 	// name is the name of ClusterWorkspaceShard.
 	//
@@ -252,6 +347,13 @@ type ClusterWorkspaceTypeSpec struct {
 	// +listMapKey=path
 	// +listMapKey=exportName
 	DefaultAPIBindings []APIExportReference `json:"defaultAPIBindings,omitempty"`
+
+	// auditPolicy configures auditing of requests targeting workspaces of this type,
+	// unless the workspace has an audit policy of its own. It is not inherited by
+	// extending types.
+	//
+	// +optional
+	AuditPolicy *AuditPolicy `json:"auditPolicy,omitempty"`
 }
 
 // APIExportReference provides the fields necessary to resolve an APIExport.
@@ -271,6 +373,41 @@ type APIExportReference struct {
 	ExportName string `json:"exportName"`
 }
 
+// ExperimentalCrossWorkspaceOwnersAnnotationKey is the annotation on an object listing owners
+// in the same or other workspaces, as a JSON list of WorkspaceObjectReferences with an optional
+// "uid" field. Once all of these owners are gone, the object is garbage collected.
+const ExperimentalCrossWorkspaceOwnersAnnotationKey = "experimental.tenancy.kcp.dev/owners"
+
+// WorkspaceObjectReference is a reference to a cluster-scoped object in some workspace.
+// It is the standard way for kcp APIs to point at objects in other workspaces.
+type WorkspaceObjectReference struct {
+	// path is an absolute reference to the workspace containing the object, e.g. root:org:ws.
+	// If it is unset, the workspace of the referencing object is used.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern:="^root(:[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$"
+	Path string `json:"path,omitempty"`
+
+	// group is the API group of the object. It is empty for the core group.
+	//
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// resource is the resource of the object, e.g. apiexports.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z][-a-z0-9]*[a-z0-9]$`
+	Resource string `json:"resource"`
+
+	// name is the name of the object.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
 // ClusterWorkspaceTypeSelector describes a set of types.
 type ClusterWorkspaceTypeSelector struct {
 	// none means that no type matches.
@@ -375,6 +512,10 @@ type ClusterWorkspaceInitializer string
 // on a ClusterWorkspaceType to be created.
 const ClusterWorkspaceAPIBindingsInitializer ClusterWorkspaceInitializer = "system:apibindings"
 
+// ClusterWorkspaceTemplatesInitializer is a special-case initializer that instantiates the objects of the
+// ClusterWorkspaceTemplates of a ClusterWorkspaceType.
+const ClusterWorkspaceTemplatesInitializer ClusterWorkspaceInitializer = "system:templates"
+
 // ClusterWorkspacePhaseType is the type of the current phase of the workspace
 type ClusterWorkspacePhaseType string
 
@@ -386,6 +527,10 @@ const (
 
 const ExperimentalClusterWorkspaceOwnerAnnotationKey string = "experimental.tenancy.kcp.dev/owner"
 
+// ExperimentalClusterWorkspaceFeatureGatesAnnotationKey on a ClusterWorkspace overrides feature gates for the
+// workspace and all workspaces below it, e.g. "KCPLocationAPI=true". The override of the closest ancestor wins.
+const ExperimentalClusterWorkspaceFeatureGatesAnnotationKey string = "experimental.tenancy.kcp.dev/feature-gates"
+
 // ClusterWorkspaceStatus communicates the observed state of the ClusterWorkspace.
 type ClusterWorkspaceStatus struct {
 	// Phase of the workspace  (Scheduling / Initializing / Ready)
@@ -439,6 +584,9 @@ const (
 	// WorkspaceShardValidReasonShardNotFound reason in WorkspaceShardValid condition means that the
 	// referenced ClusterWorkspaceShard object got deleted.
 	WorkspaceShardValidReasonShardNotFound = "ShardNotFound"
+	// WorkspaceShardValidReasonShardUnhealthy reason in WorkspaceShardValid condition means that the
+	// referenced ClusterWorkspaceShard is not healthy.
+	WorkspaceShardValidReasonShardUnhealthy = "ShardUnhealthy"
 
 	// WorkspaceDeletionContentSuccess represents the status that all resources in the workspace is deleting
 	WorkspaceDeletionContentSuccess conditionsv1alpha1.ConditionType = "WorkspaceDeletionContentSuccess"
@@ -464,6 +612,16 @@ const (
 	// WorkspaceInitializedAPIBindingErrors is a reason for the APIBindingsInitialized condition that indicates there
 	// were errors trying to initialize APIBindings for the workspace.
 	WorkspaceInitializedAPIBindingErrors = "APIBindingErrors"
+
+	// WorkspaceTemplatesInstantiated represents the status of the objects of the ClusterWorkspaceTemplates
+	// for the workspace.
+	WorkspaceTemplatesInstantiated conditionsv1alpha1.ConditionType = "TemplatesInstantiated"
+	// WorkspaceInitializedTemplateParameterMissing is a reason for the TemplatesInstantiated condition that
+	// indicates a required template parameter has no value.
+	WorkspaceInitializedTemplateParameterMissing = "TemplateParameterMissing"
+	// WorkspaceInitializedTemplateErrors is a reason for the TemplatesInstantiated condition that indicates there
+	// were errors trying to create the objects of the templates in the workspace.
+	WorkspaceInitializedTemplateErrors = "TemplateErrors"
 )
 
 // ClusterWorkspaceLocation specifies workspace placement information, including current, desired (target), and
@@ -474,10 +632,10 @@ type ClusterWorkspaceLocation struct {
 	// +optional
 	Current string `json:"current,omitempty"`
 
-	// Target workspace placement (shard).
+	// Target workspace placement (shard). It is set by a WorkspaceMigration when the data of
+	// the workspace has been copied to the target shard.
 	//
 	// +optional
-	// +kubebuilder:validation:Enum=""
 	Target string `json:"target,omitempty"`
 }
 
@@ -577,8 +735,50 @@ type ClusterWorkspaceShardStatus struct {
 	// Current processing state of the ClusterWorkspaceShard.
 	// +optional
 	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+
+	// load is the load of the shard as last reported by the shard itself. New workspaces
+	// are scheduled to the least loaded healthy shard.
+	//
+	// +optional
+	Load *ClusterWorkspaceShardLoad `json:"load,omitempty"`
 }
 
+// ClusterWorkspaceShardLoad describes the load of a shard.
+type ClusterWorkspaceShardLoad struct {
+	// workspaces is the number of workspaces scheduled to the shard.
+	//
+	// +kubebuilder:validation:Minimum=0
+	Workspaces int64 `json:"workspaces"`
+
+	// requestsPerSecond is the average number of requests per second served by the shard
+	// since the previous report.
+	//
+	// +kubebuilder:validation:Minimum=0
+	RequestsPerSecond int64 `json:"requestsPerSecond"`
+
+	// etcdSizeBytes is the size of the etcd database of the shard in bytes, or 0 if unknown.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	EtcdSizeBytes int64 `json:"etcdSizeBytes,omitempty"`
+
+	// lastReportTime is the time the load was reported by the shard. A shard not reporting
+	// for a while is considered unhealthy.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	LastReportTime metav1.Time `json:"lastReportTime"`
+}
+
+// These are valid conditions of ClusterWorkspaceShard.
+const (
+	// ClusterWorkspaceShardHealthy represents whether the shard reports its load in time.
+	ClusterWorkspaceShardHealthy conditionsv1alpha1.ConditionType = "Healthy"
+	// ClusterWorkspaceShardLoadReportStaleReason reason in ClusterWorkspaceShardHealthy condition means that the
+	// shard has not reported its load for a while.
+	ClusterWorkspaceShardLoadReportStaleReason = "LoadReportStale"
+)
+
 // ClusterWorkspaceShardList is a list of workspace shards
 //
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
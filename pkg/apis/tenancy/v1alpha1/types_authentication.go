@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkspaceAuthenticationConfiguration configures an OIDC issuer whose ID tokens authenticate
+// requests to the workspace it lives in, and to all workspaces below it. An organization can
+// hence configure the identity provider of all its workspaces at once.
+//
+// Alternatively, an organization workspace can forward the tokens to a TokenReview webhook.
+//
+// Tokens are only accepted by these issuers if no server-wide authenticator accepts them. Users
+// and groups of workspace issuers are always prefixed with the logical cluster of the configuration
+// and the issuer, hence cannot be server-wide users or groups, e.g. of the system: prefix.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Issuer",type=string,JSONPath=`.spec.issuerURL`,description="The URL of the OIDC issuer"
+// +kubebuilder:printcolumn:name="Webhook",type=string,JSONPath=`.spec.webhook.url`,description="The URL of the TokenReview webhook"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type WorkspaceAuthenticationConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	// +kubebuilder:validation:Required
+	Spec WorkspaceAuthenticationConfigurationSpec `json:"spec"`
+}
+
+// WorkspaceAuthenticationConfigurationSpec describes an OIDC issuer or a TokenReview webhook, and how
+// the tokens they accept map to users.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.issuerURL) != has(self.webhook)",message="exactly one of issuerURL or webhook must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.issuerURL) == has(self.clientID)",message="clientID must be set with issuerURL, and only then"
+type WorkspaceAuthenticationConfigurationSpec struct {
+	// issuerURL is the URL of the OIDC issuer. It must use the https scheme, and must match the
+	// iss claim of the ID tokens.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern:="^https://"
+	IssuerURL string `json:"issuerURL,omitempty"`
+
+	// clientID is the client ID ID tokens must be issued for, i.e. the expected aud claim.
+	//
+	// +optional
+	// +kubebuilder:validation:MinLength=1
+	ClientID string `json:"clientID,omitempty"`
+
+	// webhook is a TokenReview webhook the tokens are forwarded to, instead of verifying them as
+	// ID tokens of an OIDC issuer. Webhooks are only honored in organization workspaces, i.e. in
+	// the children of the root workspace.
+	//
+	// +optional
+	Webhook *WorkspaceAuthenticationWebhook `json:"webhook,omitempty"`
+
+	// certificateAuthority is a PEM encoded CA bundle used to verify the TLS certificate of the
+	// issuer. If empty, the system roots are used.
+	//
+	// +optional
+	CertificateAuthority string `json:"certificateAuthority,omitempty"`
+
+	// usernameClaim is the claim of the ID token holding the username. Not used with a webhook.
+	//
+	// +optional
+	// +kubebuilder:default:="sub"
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+
+	// usernamePrefix is prepended to the usernames of the issuer or webhook, after the prefix
+	// "<logical cluster>|<issuer or webhook URL>#" that is always applied, such that users of
+	// different workspaces and issuers cannot be confused.
+	//
+	// +optional
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+
+	// groupsClaim is the claim of the ID token holding the groups of the user. If empty, users of
+	// the issuer have no groups apart from system:authenticated. Not used with a webhook.
+	//
+	// +optional
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+
+	// groupsPrefix is prepended to the groups of the issuer or webhook, after the prefix
+	// "<logical cluster>|<issuer or webhook URL>#" that is always applied. It is required if
+	// groups are asserted, i.e. with groupsClaim or a webhook.
+	//
+	// +optional
+	GroupsPrefix string `json:"groupsPrefix,omitempty"`
+}
+
+// WorkspaceAuthenticationWebhook describes a webhook serving authentication.k8s.io/v1 TokenReviews.
+type WorkspaceAuthenticationWebhook struct {
+	// url is the https URL the TokenReviews are POSTed to.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:="^https://"
+	URL string `json:"url"`
+
+	// certificateAuthority is a PEM encoded CA bundle used to verify the TLS certificate of the
+	// webhook. If empty, the system roots are used.
+	//
+	// +optional
+	CertificateAuthority string `json:"certificateAuthority,omitempty"`
+}
+
+// WorkspaceAuthenticationConfigurationList is a list of WorkspaceAuthenticationConfigurations
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WorkspaceAuthenticationConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []WorkspaceAuthenticationConfiguration `json:"items"`
+}
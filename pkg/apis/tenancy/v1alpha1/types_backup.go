@@ -0,0 +1,254 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// WorkspaceBackup writes all objects of a ClusterWorkspace to the backup store of the shards.
+//
+// A backup lives in the parent workspace of the workspace it backs up. The objects are listed
+// resource by resource while the workspace keeps serving requests, i.e. the backup is not a
+// point-in-time snapshot of the workspace.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Workspace",type="string",JSONPath=".spec.workspace"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Objects",type="integer",JSONPath=".status.objectCount"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type WorkspaceBackup struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec WorkspaceBackupSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status WorkspaceBackupStatus `json:"status,omitempty"`
+}
+
+func (in *WorkspaceBackup) SetConditions(c conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = c
+}
+
+func (in *WorkspaceBackup) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+var _ conditions.Getter = &WorkspaceBackup{}
+var _ conditions.Setter = &WorkspaceBackup{}
+
+// WorkspaceBackupSpec holds the desired state of the WorkspaceBackup.
+type WorkspaceBackupSpec struct {
+	// workspace is the name of the ClusterWorkspace to back up. It must be a child of the
+	// workspace the backup lives in.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="workspace is immutable"
+	Workspace string `json:"workspace"`
+}
+
+// WorkspaceBackupPhase is the phase of a WorkspaceBackup or a WorkspaceRestore.
+//
+// +kubebuilder:validation:Enum=Pending;Running;Completed;Failed
+type WorkspaceBackupPhase string
+
+const (
+	// WorkspaceBackupPhasePending means the backup or restore has not started yet.
+	WorkspaceBackupPhasePending WorkspaceBackupPhase = "Pending"
+	// WorkspaceBackupPhaseRunning means the objects are being written to or read from the backup store.
+	WorkspaceBackupPhaseRunning WorkspaceBackupPhase = "Running"
+	// WorkspaceBackupPhaseCompleted means all objects have been written or restored.
+	WorkspaceBackupPhaseCompleted WorkspaceBackupPhase = "Completed"
+	// WorkspaceBackupPhaseFailed means the backup or restore cannot proceed.
+	WorkspaceBackupPhaseFailed WorkspaceBackupPhase = "Failed"
+)
+
+// WorkspaceBackupStatus communicates the observed state of the WorkspaceBackup.
+type WorkspaceBackupStatus struct {
+	// phase of the backup.
+	//
+	// +optional
+	Phase WorkspaceBackupPhase `json:"phase,omitempty"`
+
+	// location is the key of the backup in the backup store.
+	//
+	// +optional
+	Location string `json:"location,omitempty"`
+
+	// objectCount is the number of objects in the backup.
+	//
+	// +optional
+	ObjectCount int64 `json:"objectCount,omitempty"`
+
+	// workspaceUID is the UID of the ClusterWorkspace that was backed up. It is informational,
+	// restores read the UID from the backup itself.
+	//
+	// +optional
+	WorkspaceUID string `json:"workspaceUID,omitempty"`
+
+	// completionTime is the time the backup was completed.
+	//
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Current processing state of the WorkspaceBackup.
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// WorkspaceBackupList is a list of WorkspaceBackups
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WorkspaceBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []WorkspaceBackup `json:"items"`
+}
+
+// WorkspaceRestore recreates the objects of a WorkspaceBackup in a ClusterWorkspace.
+//
+// A restore lives in the parent workspace of the workspace it restores into and has the same
+// name as the corresponding ClusterWorkspace. The WorkspaceBackup must live in the same workspace.
+// Objects that already exist in the workspace are left untouched.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backup"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Objects",type="integer",JSONPath=".status.objectCount"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type WorkspaceRestore struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec WorkspaceRestoreSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status WorkspaceRestoreStatus `json:"status,omitempty"`
+}
+
+func (in *WorkspaceRestore) SetConditions(c conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = c
+}
+
+func (in *WorkspaceRestore) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+var _ conditions.Getter = &WorkspaceRestore{}
+var _ conditions.Setter = &WorkspaceRestore{}
+
+// WorkspaceRestoreSpec holds the desired state of the WorkspaceRestore.
+type WorkspaceRestoreSpec struct {
+	// backup is the name of the completed WorkspaceBackup to restore.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="backup is immutable"
+	Backup string `json:"backup"`
+}
+
+// WorkspaceRestoreStatus communicates the observed state of the WorkspaceRestore.
+type WorkspaceRestoreStatus struct {
+	// phase of the restore.
+	//
+	// +optional
+	Phase WorkspaceBackupPhase `json:"phase,omitempty"`
+
+	// objectCount is the number of objects read from the backup.
+	//
+	// +optional
+	ObjectCount int64 `json:"objectCount,omitempty"`
+
+	// preservedUIDs is true if the objects are restored with the UIDs they have in the backup.
+	// UIDs are preserved if the backed up ClusterWorkspace does not exist anymore. Otherwise,
+	// the restored objects get new UIDs, and owner references are rewritten to them.
+	//
+	// +optional
+	PreservedUIDs bool `json:"preservedUIDs,omitempty"`
+
+	// completionTime is the time the restore was completed.
+	//
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Current processing state of the WorkspaceRestore.
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// These are valid conditions of WorkspaceBackup and WorkspaceRestore.
+const (
+	// WorkspaceBackupCompleted represents the status of the backup.
+	WorkspaceBackupCompleted conditionsv1alpha1.ConditionType = "BackupCompleted"
+	// WorkspaceRestoreCompleted represents the status of the restore.
+	WorkspaceRestoreCompleted conditionsv1alpha1.ConditionType = "RestoreCompleted"
+
+	// WorkspaceBackupReasonInProgress is a reason for the BackupCompleted and RestoreCompleted conditions
+	// that indicates that objects are still being written or restored.
+	WorkspaceBackupReasonInProgress = "InProgress"
+	// WorkspaceBackupReasonWorkspaceNotReady is a reason for the BackupCompleted and RestoreCompleted
+	// conditions that indicates that the ClusterWorkspace does not exist or is not ready.
+	WorkspaceBackupReasonWorkspaceNotReady = "WorkspaceNotReady"
+	// WorkspaceBackupReasonBackupNotCompleted is a reason for the RestoreCompleted condition that indicates
+	// that the WorkspaceBackup does not exist or is not completed.
+	WorkspaceBackupReasonBackupNotCompleted = "BackupNotCompleted"
+	// WorkspaceBackupReasonAPIBindingsNotReady is a reason for the RestoreCompleted condition that indicates
+	// that the restored APIBindings do not bind the resources of the backup with the same identity yet.
+	WorkspaceBackupReasonAPIBindingsNotReady = "APIBindingsNotReady"
+	// WorkspaceBackupReasonFailed is a reason for the BackupCompleted and RestoreCompleted conditions that
+	// indicates that objects could not be written to or read from the backup store.
+	WorkspaceBackupReasonFailed = "Failed"
+)
+
+const (
+	// WorkspaceRestoreUIDAnnotationKey is set on the objects created by a restore that preserves UIDs.
+	// The value is the UID the object has in the backup. The shard creates the object with that UID
+	// and drops the annotation. Only members of system:masters can set it.
+	WorkspaceRestoreUIDAnnotationKey = "experimental.tenancy.kcp.dev/restore-uid"
+)
+
+// WorkspaceRestoreList is a list of WorkspaceRestores
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WorkspaceRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []WorkspaceRestore `json:"items"`
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// WorkspaceMigration moves the data of a ClusterWorkspace from the shard it is currently
+// scheduled to, to another shard.
+//
+// A migration lives in the parent workspace of the workspace it moves and has the same name
+// as the corresponding ClusterWorkspace. While the data is copied, writes to the workspace are
+// rejected. When the copy is complete, status.location.current of the ClusterWorkspace is
+// switched to the target shard.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".status.sourceShard"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.targetShard"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type WorkspaceMigration struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec WorkspaceMigrationSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status WorkspaceMigrationStatus `json:"status,omitempty"`
+}
+
+func (in *WorkspaceMigration) SetConditions(c conditionsv1alpha1.Conditions) {
+	in.Status.Conditions = c
+}
+
+func (in *WorkspaceMigration) GetConditions() conditionsv1alpha1.Conditions {
+	return in.Status.Conditions
+}
+
+var _ conditions.Getter = &WorkspaceMigration{}
+var _ conditions.Setter = &WorkspaceMigration{}
+
+// WorkspaceMigrationSpec holds the desired state of the WorkspaceMigration.
+type WorkspaceMigrationSpec struct {
+	// targetShard is the name of the ClusterWorkspaceShard the workspace is moved to.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	TargetShard string `json:"targetShard"`
+}
+
+// WorkspaceMigrationPhase is the phase of a WorkspaceMigration.
+//
+// +kubebuilder:validation:Enum=Pending;Fencing;Copying;CuttingOver;Completed;Failed
+type WorkspaceMigrationPhase string
+
+const (
+	// WorkspaceMigrationPhasePending means the migration has not started yet.
+	WorkspaceMigrationPhasePending WorkspaceMigrationPhase = "Pending"
+	// WorkspaceMigrationPhaseFencing means writes to the workspace are being stopped.
+	WorkspaceMigrationPhaseFencing WorkspaceMigrationPhase = "Fencing"
+	// WorkspaceMigrationPhaseCopying means the objects of the workspace are being copied to the target shard.
+	WorkspaceMigrationPhaseCopying WorkspaceMigrationPhase = "Copying"
+	// WorkspaceMigrationPhaseCuttingOver means the workspace is being switched to the target shard.
+	WorkspaceMigrationPhaseCuttingOver WorkspaceMigrationPhase = "CuttingOver"
+	// WorkspaceMigrationPhaseCompleted means the workspace is served by the target shard.
+	WorkspaceMigrationPhaseCompleted WorkspaceMigrationPhase = "Completed"
+	// WorkspaceMigrationPhaseFailed means the migration cannot proceed. The workspace stays on the source shard.
+	WorkspaceMigrationPhaseFailed WorkspaceMigrationPhase = "Failed"
+)
+
+// WorkspaceMigrationStatus communicates the observed state of the WorkspaceMigration.
+type WorkspaceMigrationStatus struct {
+	// phase of the migration.
+	//
+	// +optional
+	Phase WorkspaceMigrationPhase `json:"phase,omitempty"`
+
+	// sourceShard is the shard the workspace was scheduled to when the migration started.
+	//
+	// +optional
+	SourceShard string `json:"sourceShard,omitempty"`
+
+	// Current processing state of the WorkspaceMigration.
+	// +optional
+	Conditions conditionsv1alpha1.Conditions `json:"conditions,omitempty"`
+}
+
+// These are valid conditions of WorkspaceMigration.
+const (
+	// WorkspaceMigrationCompleted represents the status of the migration.
+	WorkspaceMigrationCompleted conditionsv1alpha1.ConditionType = "MigrationCompleted"
+	// WorkspaceMigrationReasonInProgress is a reason for the MigrationCompleted condition that indicates that
+	// the migration is still running.
+	WorkspaceMigrationReasonInProgress = "InProgress"
+	// WorkspaceMigrationReasonWorkspaceNotReady is a reason for the MigrationCompleted condition that indicates
+	// that the ClusterWorkspace does not exist or is not ready.
+	WorkspaceMigrationReasonWorkspaceNotReady = "WorkspaceNotReady"
+	// WorkspaceMigrationReasonShardNotFound is a reason for the MigrationCompleted condition that indicates
+	// that the target ClusterWorkspaceShard does not exist.
+	WorkspaceMigrationReasonShardNotFound = "ShardNotFound"
+	// WorkspaceMigrationReasonCopyFailed is a reason for the MigrationCompleted condition that indicates that
+	// objects could not be copied to the target shard.
+	WorkspaceMigrationReasonCopyFailed = "CopyFailed"
+	// WorkspaceMigrationReasonFenceNotEnforceable is a reason for the MigrationCompleted condition that indicates
+	// that the workspace is not on the shard of its parent workspace, which hence cannot reject writes to it.
+	WorkspaceMigrationReasonFenceNotEnforceable = "FenceNotEnforceable"
+	// WorkspaceMigrationReasonCleanupFailed is a reason for the MigrationCompleted condition that indicates that
+	// objects could not be deleted from the source shard after the cut-over.
+	WorkspaceMigrationReasonCleanupFailed = "CleanupFailed"
+
+	// WorkspaceMigrationFenced represents the status of the write fence of the migrated workspace.
+	WorkspaceMigrationFenced conditionsv1alpha1.ConditionType = "WorkspaceFenced"
+	// WorkspaceMigrationReasonFenceLifted is a reason for the WorkspaceFenced condition that indicates that
+	// writes to the workspace are accepted again.
+	WorkspaceMigrationReasonFenceLifted = "FenceLifted"
+
+	// WorkspaceMigrationFenceAnnotationKey is set on a ClusterWorkspace while it is migrated. The value is
+	// the name of the target shard. Writes to a fenced workspace are rejected.
+	WorkspaceMigrationFenceAnnotationKey = "experimental.tenancy.kcp.dev/migrating-to"
+)
+
+// WorkspaceMigrationList is a list of WorkspaceMigrations
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type WorkspaceMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []WorkspaceMigration `json:"items"`
+}
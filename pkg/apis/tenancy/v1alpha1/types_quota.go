@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ResourceStorage is the number of bytes the keys and values of all objects of a workspace
+	// occupy in etcd.
+	ResourceStorage corev1.ResourceName = "tenancy.kcp.dev/storage"
+	// ResourceObjects is the number of objects of all resources of a workspace stored in etcd.
+	ResourceObjects corev1.ResourceName = "tenancy.kcp.dev/objects"
+)
+
+// ClusterWorkspaceQuota limits the aggregate resource consumption of a logical cluster.
+//
+// A quota lives in the parent workspace of the workspace it limits and has the same name
+// as the corresponding ClusterWorkspace. That way, only the owner of the parent workspace can
+// change the quota, not the users of the limited workspace.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type ClusterWorkspaceQuota struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec ClusterWorkspaceQuotaSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status ClusterWorkspaceQuotaStatus `json:"status,omitempty"`
+}
+
+// ClusterWorkspaceQuotaSpec holds the desired limits of the ClusterWorkspaceQuota.
+type ClusterWorkspaceQuotaSpec struct {
+	// hard is the set of enforced hard limits for each named resource. Supported are
+	// object counts of the form "count/<resource>.<group>" (or "count/<resource>" for
+	// the core group), the aggregated compute resources of all pods in the workspace:
+	// "requests.cpu", "requests.memory", "limits.cpu" and "limits.memory", and the etcd
+	// storage of the workspace: "tenancy.kcp.dev/storage" in bytes and "tenancy.kcp.dev/objects".
+	//
+	// +optional
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+}
+
+// ClusterWorkspaceQuotaStatus communicates the observed usage of the limited workspace.
+type ClusterWorkspaceQuotaStatus struct {
+	// hard is the set of enforced hard limits the usage was last computed for.
+	//
+	// +optional
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+
+	// used is the current observed total usage of the resources in the workspace. The etcd
+	// storage of the workspace is always reported, if known, whether it is limited or not.
+	//
+	// +optional
+	Used corev1.ResourceList `json:"used,omitempty"`
+}
+
+// ClusterWorkspaceQuotaList is a list of ClusterWorkspaceQuotas
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterWorkspaceQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterWorkspaceQuota `json:"items"`
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ClusterWorkspaceTemplate describes objects that are created in every new workspace of a
+// ClusterWorkspaceType, e.g. APIBindings, RBAC, namespaces or configuration. It lives in the
+// workspace of the ClusterWorkspaceType it applies to.
+//
+// Templates of the types a ClusterWorkspaceType extends apply too. While the objects of the
+// templates are created, the workspace is initializing with the system:templates initializer.
+// Objects that exist already in the workspace are not changed.
+//
+// String values of the objects can reference parameters as ${NAME}. The value of a parameter
+// is taken from the annotation experimental.template.tenancy.kcp.dev/<NAME> of the
+// ClusterWorkspace, or from the parameter default value.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`,description="Type of the workspaces the template applies to"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type ClusterWorkspaceTemplate struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	// +kubebuilder:validation:Required
+	Spec ClusterWorkspaceTemplateSpec `json:"spec"`
+}
+
+// ClusterWorkspaceTemplateSpec describes the objects to create in new workspaces of a type.
+type ClusterWorkspaceTemplateSpec struct {
+	// type is the name of the ClusterWorkspaceType in the same workspace the template applies to.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Type ClusterWorkspaceTypeName `json:"type"`
+
+	// parameters are the parameters that can be referenced in the objects as ${NAME}.
+	//
+	// The parameters WORKSPACE_NAME, WORKSPACE_PATH and PARENT_WORKSPACE_PATH are always
+	// available, and cannot be overridden.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Parameters []ClusterWorkspaceTemplateParameter `json:"parameters,omitempty"`
+
+	// objects are the objects that are created in new workspaces. Namespaced objects without
+	// namespace are created in the default namespace.
+	//
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:EmbeddedResource
+	Objects []runtime.RawExtension `json:"objects,omitempty"`
+}
+
+// ClusterWorkspaceTemplateParameter is a parameter of a ClusterWorkspaceTemplate.
+type ClusterWorkspaceTemplateParameter struct {
+	// name is the name of the parameter, referenced in the objects as ${NAME}.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:="^[A-Z]([A-Z0-9_]*[A-Z0-9])?$"
+	Name string `json:"name"`
+
+	// description is a human readable description of the parameter.
+	//
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// value is the default value of the parameter, used when the ClusterWorkspace has no
+	// annotation for the parameter.
+	//
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// required means that the ClusterWorkspace must have a non-empty value for the parameter,
+	// either through its annotation or the default value. Otherwise, the workspace stays
+	// initializing.
+	//
+	// +optional
+	Required bool `json:"required,omitempty"`
+}
+
+const (
+	// ClusterWorkspaceTemplateParameterAnnotationPrefix is the prefix of the ClusterWorkspace annotations
+	// that set the value of a parameter of the ClusterWorkspaceTemplates of its type. The suffix is the
+	// name of the parameter.
+	ClusterWorkspaceTemplateParameterAnnotationPrefix = "experimental.template.tenancy.kcp.dev/"
+)
+
+// ClusterWorkspaceTemplateList is a list of ClusterWorkspaceTemplates
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterWorkspaceTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterWorkspaceTemplate `json:"items"`
+}
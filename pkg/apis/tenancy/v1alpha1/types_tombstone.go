@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterWorkspaceTombstone records a deleted ClusterWorkspace whose content is retained for the
+// workspace deletion retention period of the server. It lives next to where the ClusterWorkspace
+// lived, with the same name.
+//
+// While the tombstone exists, the content of the workspace is not accessible, and a ClusterWorkspace
+// of the same name can only be created by users allowed to undelete clusterworkspaces. Such a
+// ClusterWorkspace restores the retained content. When the tombstone expires, the content is deleted.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kcp
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type.name`,description="Type of the deleted workspace"
+// +kubebuilder:printcolumn:name="Shard",type=string,JSONPath=`.spec.shard`,description="Shard the content is retained on"
+// +kubebuilder:printcolumn:name="Expiration",type=date,JSONPath=`.spec.expirationTime`,description="Time the content is deleted"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type ClusterWorkspaceTombstone struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	// +kubebuilder:validation:Required
+	Spec ClusterWorkspaceTombstoneSpec `json:"spec"`
+}
+
+// ClusterWorkspaceTombstoneSpec describes the deleted ClusterWorkspace and when its content expires.
+type ClusterWorkspaceTombstoneSpec struct {
+	// type is the type of the deleted workspace. A restored workspace gets the same type.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Type ClusterWorkspaceTypeReference `json:"type"`
+
+	// shard is the name of the shard the content of the workspace is retained on. A restored
+	// workspace is scheduled to this shard.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Shard string `json:"shard"`
+
+	// deletionTime is the time the workspace was deleted.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	DeletionTime metav1.Time `json:"deletionTime"`
+
+	// expirationTime is the time after which the content of the workspace is deleted, and the
+	// workspace cannot be restored anymore.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	ExpirationTime metav1.Time `json:"expirationTime"`
+}
+
+const (
+	// ClusterWorkspaceRestoredFromAnnotationKey is set on a ClusterWorkspace that restores a deleted workspace.
+	// The value is the UID of the ClusterWorkspaceTombstone. Restored workspaces are not initialized again.
+	ClusterWorkspaceRestoredFromAnnotationKey = "experimental.tenancy.kcp.dev/restored-from"
+)
+
+// ClusterWorkspaceTombstoneList is a list of ClusterWorkspaceTombstones
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterWorkspaceTombstoneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterWorkspaceTombstone `json:"items"`
+}
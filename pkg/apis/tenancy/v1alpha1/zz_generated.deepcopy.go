@@ -45,6 +45,27 @@ func (in *APIExportReference) DeepCopy() *APIExportReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditPolicy) DeepCopyInto(out *AuditPolicy) {
+	*out = *in
+	if in.OmitStages != nil {
+		in, out := &in.OmitStages, &out.OmitStages
+		*out = make([]AuditStage, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditPolicy.
+func (in *AuditPolicy) DeepCopy() *AuditPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterWorkspace) DeepCopyInto(out *ClusterWorkspace) {
 	*out = *in
@@ -73,6 +94,26 @@ func (in *ClusterWorkspace) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceAlias) DeepCopyInto(out *ClusterWorkspaceAlias) {
+	*out = *in
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceAlias.
+func (in *ClusterWorkspaceAlias) DeepCopy() *ClusterWorkspaceAlias {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceAlias)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterWorkspaceList) DeepCopyInto(out *ClusterWorkspaceList) {
 	*out = *in
@@ -122,6 +163,120 @@ func (in *ClusterWorkspaceLocation) DeepCopy() *ClusterWorkspaceLocation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceQuota) DeepCopyInto(out *ClusterWorkspaceQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceQuota.
+func (in *ClusterWorkspaceQuota) DeepCopy() *ClusterWorkspaceQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterWorkspaceQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceQuotaList) DeepCopyInto(out *ClusterWorkspaceQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterWorkspaceQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceQuotaList.
+func (in *ClusterWorkspaceQuotaList) DeepCopy() *ClusterWorkspaceQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterWorkspaceQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceQuotaSpec) DeepCopyInto(out *ClusterWorkspaceQuotaSpec) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceQuotaSpec.
+func (in *ClusterWorkspaceQuotaSpec) DeepCopy() *ClusterWorkspaceQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceQuotaStatus) DeepCopyInto(out *ClusterWorkspaceQuotaStatus) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceQuotaStatus.
+func (in *ClusterWorkspaceQuotaStatus) DeepCopy() *ClusterWorkspaceQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterWorkspaceShard) DeepCopyInto(out *ClusterWorkspaceShard) {
 	*out = *in
@@ -183,6 +338,23 @@ func (in *ClusterWorkspaceShardList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceShardLoad) DeepCopyInto(out *ClusterWorkspaceShardLoad) {
+	*out = *in
+	in.LastReportTime.DeepCopyInto(&out.LastReportTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceShardLoad.
+func (in *ClusterWorkspaceShardLoad) DeepCopy() *ClusterWorkspaceShardLoad {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceShardLoad)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterWorkspaceShardSpec) DeepCopyInto(out *ClusterWorkspaceShardSpec) {
 	*out = *in
@@ -216,6 +388,11 @@ func (in *ClusterWorkspaceShardStatus) DeepCopyInto(out *ClusterWorkspaceShardSt
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Load != nil {
+		in, out := &in.Load, &out.Load
+		*out = new(ClusterWorkspaceShardLoad)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -238,6 +415,18 @@ func (in *ClusterWorkspaceSpec) DeepCopyInto(out *ClusterWorkspaceSpec) {
 		*out = new(ShardConstraints)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AuditPolicy != nil {
+		in, out := &in.AuditPolicy, &out.AuditPolicy
+		*out = new(AuditPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]ClusterWorkspaceAlias, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -281,27 +470,26 @@ func (in *ClusterWorkspaceStatus) DeepCopy() *ClusterWorkspaceStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterWorkspaceType) DeepCopyInto(out *ClusterWorkspaceType) {
+func (in *ClusterWorkspaceTemplate) DeepCopyInto(out *ClusterWorkspaceTemplate) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceType.
-func (in *ClusterWorkspaceType) DeepCopy() *ClusterWorkspaceType {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTemplate.
+func (in *ClusterWorkspaceTemplate) DeepCopy() *ClusterWorkspaceTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterWorkspaceType)
+	out := new(ClusterWorkspaceTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterWorkspaceType) DeepCopyObject() runtime.Object {
+func (in *ClusterWorkspaceTemplate) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -309,34 +497,13 @@ func (in *ClusterWorkspaceType) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterWorkspaceTypeExtension) DeepCopyInto(out *ClusterWorkspaceTypeExtension) {
-	*out = *in
-	if in.With != nil {
-		in, out := &in.With, &out.With
-		*out = make([]ClusterWorkspaceTypeReference, len(*in))
-		copy(*out, *in)
-	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTypeExtension.
-func (in *ClusterWorkspaceTypeExtension) DeepCopy() *ClusterWorkspaceTypeExtension {
-	if in == nil {
-		return nil
-	}
-	out := new(ClusterWorkspaceTypeExtension)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterWorkspaceTypeList) DeepCopyInto(out *ClusterWorkspaceTypeList) {
+func (in *ClusterWorkspaceTemplateList) DeepCopyInto(out *ClusterWorkspaceTemplateList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterWorkspaceType, len(*in))
+		*out = make([]ClusterWorkspaceTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -344,18 +511,18 @@ func (in *ClusterWorkspaceTypeList) DeepCopyInto(out *ClusterWorkspaceTypeList)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTypeList.
-func (in *ClusterWorkspaceTypeList) DeepCopy() *ClusterWorkspaceTypeList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTemplateList.
+func (in *ClusterWorkspaceTemplateList) DeepCopy() *ClusterWorkspaceTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterWorkspaceTypeList)
+	out := new(ClusterWorkspaceTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterWorkspaceTypeList) DeepCopyObject() runtime.Object {
+func (in *ClusterWorkspaceTemplateList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -363,122 +530,332 @@ func (in *ClusterWorkspaceTypeList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterWorkspaceTypeReference) DeepCopyInto(out *ClusterWorkspaceTypeReference) {
+func (in *ClusterWorkspaceTemplateParameter) DeepCopyInto(out *ClusterWorkspaceTemplateParameter) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTypeReference.
-func (in *ClusterWorkspaceTypeReference) DeepCopy() *ClusterWorkspaceTypeReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTemplateParameter.
+func (in *ClusterWorkspaceTemplateParameter) DeepCopy() *ClusterWorkspaceTemplateParameter {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterWorkspaceTypeReference)
+	out := new(ClusterWorkspaceTemplateParameter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterWorkspaceTypeSelector) DeepCopyInto(out *ClusterWorkspaceTypeSelector) {
+func (in *ClusterWorkspaceTemplateSpec) DeepCopyInto(out *ClusterWorkspaceTemplateSpec) {
 	*out = *in
-	if in.Types != nil {
-		in, out := &in.Types, &out.Types
-		*out = make([]ClusterWorkspaceTypeReference, len(*in))
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]ClusterWorkspaceTemplateParameter, len(*in))
 		copy(*out, *in)
 	}
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTypeSelector.
-func (in *ClusterWorkspaceTypeSelector) DeepCopy() *ClusterWorkspaceTypeSelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTemplateSpec.
+func (in *ClusterWorkspaceTemplateSpec) DeepCopy() *ClusterWorkspaceTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterWorkspaceTypeSelector)
+	out := new(ClusterWorkspaceTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterWorkspaceTypeSpec) DeepCopyInto(out *ClusterWorkspaceTypeSpec) {
+func (in *ClusterWorkspaceTombstone) DeepCopyInto(out *ClusterWorkspaceTombstone) {
 	*out = *in
-	in.Extend.DeepCopyInto(&out.Extend)
-	if in.AdditionalWorkspaceLabels != nil {
-		in, out := &in.AdditionalWorkspaceLabels, &out.AdditionalWorkspaceLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.DefaultChildWorkspaceType != nil {
-		in, out := &in.DefaultChildWorkspaceType, &out.DefaultChildWorkspaceType
-		*out = new(ClusterWorkspaceTypeReference)
-		**out = **in
-	}
-	if in.LimitAllowedChildren != nil {
-		in, out := &in.LimitAllowedChildren, &out.LimitAllowedChildren
-		*out = new(ClusterWorkspaceTypeSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.LimitAllowedParents != nil {
-		in, out := &in.LimitAllowedParents, &out.LimitAllowedParents
-		*out = new(ClusterWorkspaceTypeSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.DefaultAPIBindings != nil {
-		in, out := &in.DefaultAPIBindings, &out.DefaultAPIBindings
-		*out = make([]APIExportReference, len(*in))
-		copy(*out, *in)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTypeSpec.
-func (in *ClusterWorkspaceTypeSpec) DeepCopy() *ClusterWorkspaceTypeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTombstone.
+func (in *ClusterWorkspaceTombstone) DeepCopy() *ClusterWorkspaceTombstone {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterWorkspaceTypeSpec)
+	out := new(ClusterWorkspaceTombstone)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterWorkspaceTombstone) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterWorkspaceTypeStatus) DeepCopyInto(out *ClusterWorkspaceTypeStatus) {
+func (in *ClusterWorkspaceTombstoneList) DeepCopyInto(out *ClusterWorkspaceTombstoneList) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make(conditionsv1alpha1.Conditions, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterWorkspaceTombstone, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.VirtualWorkspaces != nil {
-		in, out := &in.VirtualWorkspaces, &out.VirtualWorkspaces
-		*out = make([]VirtualWorkspace, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTypeStatus.
-func (in *ClusterWorkspaceTypeStatus) DeepCopy() *ClusterWorkspaceTypeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTombstoneList.
+func (in *ClusterWorkspaceTombstoneList) DeepCopy() *ClusterWorkspaceTombstoneList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterWorkspaceTypeStatus)
+	out := new(ClusterWorkspaceTombstoneList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterWorkspaceTombstoneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ShardConstraints) DeepCopyInto(out *ShardConstraints) {
+func (in *ClusterWorkspaceTombstoneSpec) DeepCopyInto(out *ClusterWorkspaceTombstoneSpec) {
 	*out = *in
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
+	out.Type = in.Type
+	in.DeletionTime.DeepCopyInto(&out.DeletionTime)
+	in.ExpirationTime.DeepCopyInto(&out.ExpirationTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTombstoneSpec.
+func (in *ClusterWorkspaceTombstoneSpec) DeepCopy() *ClusterWorkspaceTombstoneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceTombstoneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceType) DeepCopyInto(out *ClusterWorkspaceType) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceType.
+func (in *ClusterWorkspaceType) DeepCopy() *ClusterWorkspaceType {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterWorkspaceType) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceTypeExtension) DeepCopyInto(out *ClusterWorkspaceTypeExtension) {
+	*out = *in
+	if in.With != nil {
+		in, out := &in.With, &out.With
+		*out = make([]ClusterWorkspaceTypeReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTypeExtension.
+func (in *ClusterWorkspaceTypeExtension) DeepCopy() *ClusterWorkspaceTypeExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceTypeExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceTypeList) DeepCopyInto(out *ClusterWorkspaceTypeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterWorkspaceType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTypeList.
+func (in *ClusterWorkspaceTypeList) DeepCopy() *ClusterWorkspaceTypeList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceTypeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterWorkspaceTypeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceTypeReference) DeepCopyInto(out *ClusterWorkspaceTypeReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTypeReference.
+func (in *ClusterWorkspaceTypeReference) DeepCopy() *ClusterWorkspaceTypeReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceTypeReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceTypeSelector) DeepCopyInto(out *ClusterWorkspaceTypeSelector) {
+	*out = *in
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]ClusterWorkspaceTypeReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTypeSelector.
+func (in *ClusterWorkspaceTypeSelector) DeepCopy() *ClusterWorkspaceTypeSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceTypeSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceTypeSpec) DeepCopyInto(out *ClusterWorkspaceTypeSpec) {
+	*out = *in
+	in.Extend.DeepCopyInto(&out.Extend)
+	if in.AdditionalWorkspaceLabels != nil {
+		in, out := &in.AdditionalWorkspaceLabels, &out.AdditionalWorkspaceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DefaultChildWorkspaceType != nil {
+		in, out := &in.DefaultChildWorkspaceType, &out.DefaultChildWorkspaceType
+		*out = new(ClusterWorkspaceTypeReference)
+		**out = **in
+	}
+	if in.LimitAllowedChildren != nil {
+		in, out := &in.LimitAllowedChildren, &out.LimitAllowedChildren
+		*out = new(ClusterWorkspaceTypeSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LimitAllowedParents != nil {
+		in, out := &in.LimitAllowedParents, &out.LimitAllowedParents
+		*out = new(ClusterWorkspaceTypeSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultAPIBindings != nil {
+		in, out := &in.DefaultAPIBindings, &out.DefaultAPIBindings
+		*out = make([]APIExportReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.AuditPolicy != nil {
+		in, out := &in.AuditPolicy, &out.AuditPolicy
+		*out = new(AuditPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTypeSpec.
+func (in *ClusterWorkspaceTypeSpec) DeepCopy() *ClusterWorkspaceTypeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceTypeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterWorkspaceTypeStatus) DeepCopyInto(out *ClusterWorkspaceTypeStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(conditionsv1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VirtualWorkspaces != nil {
+		in, out := &in.VirtualWorkspaces, &out.VirtualWorkspaces
+		*out = make([]VirtualWorkspace, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterWorkspaceTypeStatus.
+func (in *ClusterWorkspaceTypeStatus) DeepCopy() *ClusterWorkspaceTypeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWorkspaceTypeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardConstraints) DeepCopyInto(out *ShardConstraints) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -507,3 +884,424 @@ func (in *VirtualWorkspace) DeepCopy() *VirtualWorkspace {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAuthenticationConfiguration) DeepCopyInto(out *WorkspaceAuthenticationConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAuthenticationConfiguration.
+func (in *WorkspaceAuthenticationConfiguration) DeepCopy() *WorkspaceAuthenticationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceAuthenticationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceAuthenticationConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAuthenticationConfigurationList) DeepCopyInto(out *WorkspaceAuthenticationConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspaceAuthenticationConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAuthenticationConfigurationList.
+func (in *WorkspaceAuthenticationConfigurationList) DeepCopy() *WorkspaceAuthenticationConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceAuthenticationConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceAuthenticationConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAuthenticationConfigurationSpec) DeepCopyInto(out *WorkspaceAuthenticationConfigurationSpec) {
+	*out = *in
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WorkspaceAuthenticationWebhook)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAuthenticationConfigurationSpec.
+func (in *WorkspaceAuthenticationConfigurationSpec) DeepCopy() *WorkspaceAuthenticationConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceAuthenticationConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAuthenticationWebhook) DeepCopyInto(out *WorkspaceAuthenticationWebhook) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAuthenticationWebhook.
+func (in *WorkspaceAuthenticationWebhook) DeepCopy() *WorkspaceAuthenticationWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceAuthenticationWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceBackup) DeepCopyInto(out *WorkspaceBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceBackup.
+func (in *WorkspaceBackup) DeepCopy() *WorkspaceBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceBackupList) DeepCopyInto(out *WorkspaceBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspaceBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceBackupList.
+func (in *WorkspaceBackupList) DeepCopy() *WorkspaceBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceBackupSpec) DeepCopyInto(out *WorkspaceBackupSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceBackupSpec.
+func (in *WorkspaceBackupSpec) DeepCopy() *WorkspaceBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceBackupStatus) DeepCopyInto(out *WorkspaceBackupStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(conditionsv1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceBackupStatus.
+func (in *WorkspaceBackupStatus) DeepCopy() *WorkspaceBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceMigration) DeepCopyInto(out *WorkspaceMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceMigration.
+func (in *WorkspaceMigration) DeepCopy() *WorkspaceMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceMigrationList) DeepCopyInto(out *WorkspaceMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspaceMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceMigrationList.
+func (in *WorkspaceMigrationList) DeepCopy() *WorkspaceMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceMigrationSpec) DeepCopyInto(out *WorkspaceMigrationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceMigrationSpec.
+func (in *WorkspaceMigrationSpec) DeepCopy() *WorkspaceMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceMigrationStatus) DeepCopyInto(out *WorkspaceMigrationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(conditionsv1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceMigrationStatus.
+func (in *WorkspaceMigrationStatus) DeepCopy() *WorkspaceMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceObjectReference) DeepCopyInto(out *WorkspaceObjectReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceObjectReference.
+func (in *WorkspaceObjectReference) DeepCopy() *WorkspaceObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceRestore) DeepCopyInto(out *WorkspaceRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceRestore.
+func (in *WorkspaceRestore) DeepCopy() *WorkspaceRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceRestoreList) DeepCopyInto(out *WorkspaceRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspaceRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceRestoreList.
+func (in *WorkspaceRestoreList) DeepCopy() *WorkspaceRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceRestoreSpec) DeepCopyInto(out *WorkspaceRestoreSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceRestoreSpec.
+func (in *WorkspaceRestoreSpec) DeepCopy() *WorkspaceRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceRestoreStatus) DeepCopyInto(out *WorkspaceRestoreStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(conditionsv1alpha1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceRestoreStatus.
+func (in *WorkspaceRestoreStatus) DeepCopy() *WorkspaceRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -19,8 +19,11 @@ package v1alpha1
 import (
 	"crypto/sha256"
 	"math/big"
+	"strings"
 
 	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // ToSyncTargetKey hashes the SyncTarget workspace and the SyncTarget name to a string that is used to identify
@@ -31,8 +34,55 @@ func ToSyncTargetKey(syncTargetWorkspace logicalcluster.Name, syncTargetName str
 	return base62hash
 }
 
+// SyncTargetKeysFromPlacementAnnotation returns the SyncTarget keys stored in the value of the
+// internal.workload.kcp.dev/synctarget annotation of a placement.
+func SyncTargetKeysFromPlacementAnnotation(value string) []string {
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 func toBase62(hash [28]byte) string {
 	var i big.Int
 	i.SetBytes(hash[:])
 	return i.Text(62)
 }
+
+// Matches returns true if the pattern matches the given resource.
+func (p ResourcePattern) Matches(gr schema.GroupResource) bool {
+	if p.Resource != "*" && p.Resource != gr.Resource {
+		return false
+	}
+	switch {
+	case p.Group == "*":
+		return true
+	case strings.HasPrefix(p.Group, "*."):
+		return strings.HasSuffix(gr.Group, p.Group[1:])
+	default:
+		return p.Group == gr.Group
+	}
+}
+
+// IsResourceSynced returns true if the given resource of the supported APIExports is synced to the
+// SyncTarget, i.e. it matches one of the included resources, or no resources are included explicitly,
+// and it matches none of the excluded resources.
+func (in *SyncTargetSpec) IsResourceSynced(gr schema.GroupResource) bool {
+	for _, p := range in.ExcludedResources {
+		if p.Matches(gr) {
+			return false
+		}
+	}
+	if len(in.IncludedResources) == 0 {
+		return true
+	}
+	for _, p := range in.IncludedResources {
+		if p.Matches(gr) {
+			return true
+		}
+	}
+	return false
+}
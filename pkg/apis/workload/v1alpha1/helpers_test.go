@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestIsResourceSynced(t *testing.T) {
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	services := schema.GroupResource{Resource: "services"}
+	ingresses := schema.GroupResource{Group: "networking.k8s.io", Resource: "ingresses"}
+	routes := schema.GroupResource{Group: "route.openshift.io", Resource: "routes"}
+
+	tests := map[string]struct {
+		included, excluded []ResourcePattern
+		want               map[schema.GroupResource]bool
+	}{
+		"no patterns": {
+			want: map[schema.GroupResource]bool{deployments: true, services: true, ingresses: true, routes: true},
+		},
+		"included resources": {
+			included: []ResourcePattern{{Group: "apps", Resource: "deployments"}, {Resource: "services"}},
+			want:     map[schema.GroupResource]bool{deployments: true, services: true, ingresses: false, routes: false},
+		},
+		"included group suffix": {
+			included: []ResourcePattern{{Group: "*.k8s.io", Resource: "*"}},
+			want:     map[schema.GroupResource]bool{deployments: false, services: false, ingresses: true, routes: false},
+		},
+		"core group is not a wildcard": {
+			included: []ResourcePattern{{Resource: "*"}},
+			want:     map[schema.GroupResource]bool{deployments: false, services: true, ingresses: false, routes: false},
+		},
+		"excluded resources": {
+			excluded: []ResourcePattern{{Group: "*.openshift.io", Resource: "*"}, {Group: "*", Resource: "ingresses"}},
+			want:     map[schema.GroupResource]bool{deployments: true, services: true, ingresses: false, routes: false},
+		},
+		"excluded wins over included": {
+			included: []ResourcePattern{{Group: "*", Resource: "*"}},
+			excluded: []ResourcePattern{{Group: "apps", Resource: "*"}},
+			want:     map[schema.GroupResource]bool{deployments: false, services: true, ingresses: true, routes: true},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec := &SyncTargetSpec{IncludedResources: tt.included, ExcludedResources: tt.excluded}
+			for gr, want := range tt.want {
+				if got := spec.IsResourceSynced(gr); got != want {
+					t.Errorf("IsResourceSynced(%s) = %v, want %v", gr, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncTargetKeysFromPlacementAnnotation(t *testing.T) {
+	tests := map[string][]string{
+		"":          nil,
+		"a":         {"a"},
+		"a,b":       {"a", "b"},
+		"a,,b,":     {"a", "b"},
+		",":         nil,
+		"abc,def,g": {"abc", "def", "g"},
+	}
+	for value, want := range tests {
+		if got := SyncTargetKeysFromPlacementAnnotation(value); !reflect.DeepEqual(got, want) {
+			t.Errorf("SyncTargetKeysFromPlacementAnnotation(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -80,6 +80,101 @@ type SyncTargetSpec struct {
 	// they are in the same physical cluster. Each key/value pair in the cells should be added and updated by service providers
 	// (i.e. a network provider updates one key/value, while the storage provider updates another.)
 	Cells map[string]string `json:"cells,omitempty"`
+
+	// Transformations mutate the resources synced to this SyncTarget, in the order given, before they are
+	// served to the syncer. They do not change the resources in kcp.
+	// +optional
+	Transformations []ResourceTransformation `json:"transformations,omitempty"`
+
+	// UpsyncedResources are the resources the syncer of this SyncTarget is allowed to create and update
+	// in kcp through the upsyncer virtual workspace, in addition to persistentvolumes which are always allowed.
+	// The resources must be part of the synced resources of the SyncTarget.
+	// +optional
+	UpsyncedResources []apisv1alpha1.GroupResource `json:"upsyncedResources,omitempty"`
+
+	// IncludedResources restricts the resources of the supported APIExports that are served to the syncer
+	// of this SyncTarget to those matching one of the given patterns. If it is empty, all resources of the
+	// supported APIExports are served.
+	// +optional
+	IncludedResources []ResourcePattern `json:"includedResources,omitempty"`
+
+	// ExcludedResources are resources of the supported APIExports that are not served to the syncer of this
+	// SyncTarget, even if they are included. Use it for resources the physical cluster cannot or should not host.
+	// +optional
+	ExcludedResources []ResourcePattern `json:"excludedResources,omitempty"`
+
+	// NamespaceNaming is the strategy naming the namespaces on the physical cluster which hold the
+	// resources of upstream namespaces. It applies to namespaces created on the physical cluster after
+	// it is set, existing namespaces keep their name.
+	//
+	// - Hash (default): kcp-<hash>, with a hash of the workspace, the namespace and the SyncTarget.
+	// - Passthrough: the name of the upstream namespace. Namespaces of the same name in different
+	//   workspaces collide, and only the first one is synced.
+	// - WorkspacePrefixed: <workspace>-<namespace>, with the colons of the workspace path replaced
+	//   by dashes. Names longer than 63 characters are shortened and suffixed with a hash.
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=Hash;Passthrough;WorkspacePrefixed
+	NamespaceNaming NamespaceNamingStrategy `json:"namespaceNaming,omitempty"`
+
+	// VirtualWorkspaceAccess restricts the clients that can reach the syncer and upsyncer virtual
+	// workspace URLs of this SyncTarget, e.g. to the service account of its syncer. If unset, every
+	// client authorized by the virtual workspaces can reach them.
+	// +optional
+	VirtualWorkspaceAccess *apisv1alpha1.VirtualWorkspaceAccess `json:"virtualWorkspaceAccess,omitempty"`
+}
+
+// NamespaceNamingStrategy determines the names of the namespaces on the physical cluster of a SyncTarget.
+type NamespaceNamingStrategy string
+
+const (
+	// NamespaceNamingHash names namespaces by a hash of the workspace, the namespace and the SyncTarget.
+	NamespaceNamingHash NamespaceNamingStrategy = "Hash"
+	// NamespaceNamingPassthrough names namespaces like the upstream namespace.
+	NamespaceNamingPassthrough NamespaceNamingStrategy = "Passthrough"
+	// NamespaceNamingWorkspacePrefixed names namespaces like the upstream namespace, prefixed with the
+	// workspace path.
+	NamespaceNamingWorkspacePrefixed NamespaceNamingStrategy = "WorkspacePrefixed"
+)
+
+// ResourcePattern matches resources by group and resource name.
+type ResourcePattern struct {
+	// Group is the API group of the resources, the empty string for the core group. "*" matches all
+	// groups, and "*.<suffix>" all groups ending with .<suffix>, e.g. "*.k8s.io".
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Resource is the plural name of the resources, or "*" for all resources of the group.
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+}
+
+// ResourceTransformation describes how the resources synced to a SyncTarget are mutated.
+type ResourceTransformation struct {
+	// Resources selects the resources this transformation applies to. If empty, it applies
+	// to all synced resources.
+	// +optional
+	Resources []apisv1alpha1.GroupResource `json:"resources,omitempty"`
+
+	// RemoveFields are the dot-separated paths of fields removed from the resources,
+	// e.g. "spec.nodeName". Metadata fields cannot be removed.
+	// +optional
+	RemoveFields []string `json:"removeFields,omitempty"`
+
+	// Labels are set on the resources, overriding existing labels with the same key.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are set on the resources, overriding existing annotations with the same key.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Tolerations are added to pods, and to the pod templates of resources having one
+	// in spec.template or spec.jobTemplate.spec.template, e.g. deployments and cronjobs.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 }
 
 // SyncTargetStatus communicates the observed state of the SyncTarget (from the controller).
@@ -16,6 +16,10 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
 type ResourceState string
 
 const (
@@ -96,6 +100,18 @@ const (
 	// and values are overriding field values.
 	InternalSyncerViewAnnotationPrefix = "diff.syncer.internal.kcp.dev/"
 
+	// InternalSyncerFieldConflictsAnnotationPrefix is the prefix of the annotation
+	//
+	//   conflicts.syncer.internal.kcp.dev/<sync-target-key>
+	//
+	// on upstream resources storing the conditions reporting that fields written by the Syncer
+	// for the given SyncTarget could not be promoted to the upstream resource, because they are
+	// owned by another field manager with a different value. The value reported by the Syncer is
+	// then kept in the syncer view annotation instead of silently overwriting the upstream value.
+	//
+	// The format is a JSON array of conditions.
+	InternalSyncerFieldConflictsAnnotationPrefix = "conflicts.syncer.internal.kcp.dev/"
+
 	// InternalClusterStatusAnnotationPrefix is the prefix of the annotation
 	//
 	//   experimental.status.workload.kcp.dev/<sync-target-name>
@@ -150,9 +166,21 @@ const (
 
 	// InternalSyncTargetPlacementAnnotationKey is a internal annotation key on placement API to mark the synctarget scheduled
 	// from this placement. The value is a hash of the SyncTarget workspace + SyncTarget name, generated with the ToSyncTargetKey(..) helper func.
+	// Placements in All mode are scheduled to multiple synctargets, whose hashes are comma-separated. Use the
+	// SyncTargetKeysFromPlacementAnnotation(..) helper func to read the value.
 	InternalSyncTargetPlacementAnnotationKey = "internal.workload.kcp.dev/synctarget"
 
 	// InternalSyncTargetKeyLabel is an internal label set on a SyncTarget resource that contains the full hash of the SyncTargetKey, generated with the ToSyncTargetKey(..)
 	// helper func, this label is used for reverse lookups of a syncTargetKey to SyncTarget.
 	InternalSyncTargetKeyLabel = "internal.workload.kcp.dev/key"
 )
+
+const (
+	// SyncerFieldConflict means that fields written by the Syncer of a SyncTarget
+	// conflict with fields owned by another field manager in the upstream resource.
+	SyncerFieldConflict conditionsv1alpha1.ConditionType = "SyncerFieldConflict"
+
+	// FieldManagedByAnotherManagerReason indicates that a field written by the Syncer
+	// is owned by another field manager with a different value in the upstream resource.
+	FieldManagedByAnotherManagerReason = "FieldManagedByAnotherManager"
+)
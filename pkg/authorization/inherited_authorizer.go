@@ -0,0 +1,389 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	kcpkubernetesinformers "github.com/kcp-dev/client-go/informers"
+	rbacv1listers "github.com/kcp-dev/client-go/listers/rbac/v1"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	kaudit "k8s.io/apiserver/pkg/audit"
+	authserviceaccount "k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	rbaclisters "k8s.io/client-go/listers/rbac/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/genericcontrolplane"
+	"k8s.io/kubernetes/plugin/pkg/auth/authorizer/rbac"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	rbacwrapper "github.com/kcp-dev/kcp/pkg/virtual/framework/wrappers/rbac"
+)
+
+const (
+	// PropagateAnnotationKey marks a ClusterRole or ClusterRoleBinding with the value "true" as
+	// inherited by all descendants of the workspace it is defined in.
+	PropagateAnnotationKey = "authorization.kcp.dev/propagate"
+
+	InheritedAuditPrefix   = "inherited.authorization.kcp.dev/"
+	InheritedAuditDecision = InheritedAuditPrefix + "decision"
+	InheritedAuditReason   = InheritedAuditPrefix + "reason"
+)
+
+// InheritedAuthorizer authorizes requests against the ClusterRoles and ClusterRoleBindings that the
+// ancestor workspaces of the requested workspace propagate, without copying them into every
+// descendant:
+//
+//   - bindings in the requested workspace can reference ClusterRoles propagated by its ancestors;
+//   - ClusterRoleBindings propagated by an ancestor apply in the requested workspace. Their role
+//     reference is resolved in the ancestor defining them. ServiceAccount subjects only match the
+//     ServiceAccounts of the ancestor defining them, not the equally named ones of the descendants.
+//
+// Only ancestors below the root workspace propagate policy. The propagated objects of each
+// workspace are cached, and the cache is invalidated by the RBAC informers.
+type InheritedAuthorizer struct {
+	roleLister               rbacv1listers.RoleClusterLister
+	roleBindingLister        rbacv1listers.RoleBindingClusterLister
+	clusterRoleBindingLister rbacv1listers.ClusterRoleBindingClusterLister
+	clusterRoleLister        rbacv1listers.ClusterRoleClusterLister
+
+	noRoleBindings rbaclisters.RoleBindingLister
+
+	lock       sync.Mutex
+	propagated map[logicalcluster.Name]*propagatedPolicy
+}
+
+// propagatedPolicy holds the ClusterRoles and ClusterRoleBindings propagated by one workspace.
+type propagatedPolicy struct {
+	clusterRoles        rbaclisters.ClusterRoleLister
+	clusterRoleBindings rbaclisters.ClusterRoleBindingLister
+	// userClusterRoleBindings are the clusterRoleBindings without ServiceAccount subjects.
+	userClusterRoleBindings rbaclisters.ClusterRoleBindingLister
+
+	hasClusterRoles        bool
+	hasClusterRoleBindings bool
+}
+
+func NewInheritedAuthorizer(versionedInformers kcpkubernetesinformers.SharedInformerFactory) *InheritedAuthorizer {
+	a := &InheritedAuthorizer{
+		roleLister:               versionedInformers.Rbac().V1().Roles().Lister(),
+		roleBindingLister:        versionedInformers.Rbac().V1().RoleBindings().Lister(),
+		clusterRoleLister:        versionedInformers.Rbac().V1().ClusterRoles().Lister(),
+		clusterRoleBindingLister: versionedInformers.Rbac().V1().ClusterRoleBindings().Lister(),
+
+		noRoleBindings: rbaclisters.NewRoleBindingLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})),
+
+		propagated: map[logicalcluster.Name]*propagatedPolicy{},
+	}
+
+	invalidate := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { a.invalidate(obj) },
+		UpdateFunc: func(_, obj interface{}) { a.invalidate(obj) },
+		DeleteFunc: func(obj interface{}) { a.invalidate(obj) },
+	}
+	versionedInformers.Rbac().V1().ClusterRoles().Informer().AddEventHandler(invalidate)
+	versionedInformers.Rbac().V1().ClusterRoleBindings().Informer().AddEventHandler(invalidate)
+
+	return a
+}
+
+func (a *InheritedAuthorizer) invalidate(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	delete(a.propagated, logicalcluster.From(m))
+}
+
+// propagatedPolicy returns the cached policy propagated by the given workspace. The policy is
+// built while holding the lock, so that an invalidation by a concurrent informer event is not lost.
+func (a *InheritedAuthorizer) propagatedPolicy(clusterName logicalcluster.Name) (*propagatedPolicy, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if p, found := a.propagated[clusterName]; found {
+		return p, nil
+	}
+
+	clusterRoles, err := a.clusterRoleLister.Cluster(clusterName).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	clusterRoleBindings, err := a.clusterRoleBindingLister.Cluster(clusterName).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	clusterRoleIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	clusterRoleBindingIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	userClusterRoleBindingIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	p := &propagatedPolicy{
+		clusterRoles:            rbaclisters.NewClusterRoleLister(clusterRoleIndexer),
+		clusterRoleBindings:     rbaclisters.NewClusterRoleBindingLister(clusterRoleBindingIndexer),
+		userClusterRoleBindings: rbaclisters.NewClusterRoleBindingLister(userClusterRoleBindingIndexer),
+	}
+	for _, r := range clusterRoles {
+		if r.Annotations[PropagateAnnotationKey] != "true" {
+			continue
+		}
+		if err := clusterRoleIndexer.Add(r); err != nil {
+			return nil, err
+		}
+		p.hasClusterRoles = true
+	}
+	for _, b := range clusterRoleBindings {
+		if b.Annotations[PropagateAnnotationKey] != "true" {
+			continue
+		}
+		if err := clusterRoleBindingIndexer.Add(b); err != nil {
+			return nil, err
+		}
+		if err := userClusterRoleBindingIndexer.Add(withoutServiceAccountSubjects(b)); err != nil {
+			return nil, err
+		}
+		p.hasClusterRoleBindings = true
+	}
+
+	a.propagated[clusterName] = p
+	return p, nil
+}
+
+func (a *InheritedAuthorizer) Authorize(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	cluster := genericapirequest.ClusterFrom(ctx)
+	if cluster == nil || cluster.Name.Empty() {
+		kaudit.AddAuditAnnotations(
+			ctx,
+			InheritedAuditDecision, DecisionNoOpinion,
+			InheritedAuditReason, "empty cluster name",
+		)
+		return authorizer.DecisionNoOpinion, "", nil
+	}
+
+	dec, reason, err := a.authorizeIn(ctx, cluster.Name, attr)
+
+	kaudit.AddAuditAnnotations(
+		ctx,
+		InheritedAuditDecision, DecisionString(dec),
+		InheritedAuditReason, fmt.Sprintf("cluster %q inherited policy reason: %v", cluster.Name, reason),
+	)
+
+	return dec, reason, err
+}
+
+// authorizeIn authorizes the attributes against the policy inherited by the given workspace.
+func (a *InheritedAuthorizer) authorizeIn(ctx context.Context, clusterName logicalcluster.Name, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+	ancestors := propagatingAncestors(clusterName)
+	if len(ancestors) == 0 {
+		return authorizer.DecisionNoOpinion, "no ancestors propagating policy", nil
+	}
+	policies := make([]*propagatedPolicy, 0, len(ancestors))
+	for _, ancestor := range ancestors {
+		p, err := a.propagatedPolicy(ancestor)
+		if err != nil {
+			return authorizer.DecisionNoOpinion, "", err
+		}
+		policies = append(policies, p)
+	}
+
+	var errs []error
+
+	// local bindings referencing inherited ClusterRoles, if there are more than the local and bootstrap ones
+	if clusterRoles := a.inheritedClusterRoles(clusterName, policies); len(clusterRoles) > 2 {
+		local := rbac.New(
+			&rbac.RoleGetter{Lister: rbacwrapper.NewMergedRoleLister(
+				a.roleLister.Cluster(clusterName),
+				a.roleLister.Cluster(genericcontrolplane.LocalAdminCluster),
+			)},
+			&rbac.RoleBindingLister{Lister: a.roleBindingLister.Cluster(clusterName)},
+			&rbac.ClusterRoleGetter{Lister: clusterRoles},
+			&rbac.ClusterRoleBindingLister{Lister: a.clusterRoleBindingLister.Cluster(clusterName)},
+		)
+		dec, reason, err := local.Authorize(ctx, attr)
+		if dec == authorizer.DecisionAllow {
+			return dec, fmt.Sprintf("local binding to inherited ClusterRole: %s", reason), nil
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// ClusterRoleBindings propagated by the ancestors, resolved in the ancestor defining them
+	for i, ancestor := range ancestors {
+		if !policies[i].hasClusterRoleBindings {
+			continue
+		}
+		clusterRoleBindings := policies[i].userClusterRoleBindings
+		if isServiceAccountOf(attr.GetUser(), ancestor) {
+			clusterRoleBindings = policies[i].clusterRoleBindings
+		}
+		inherited := rbac.New(
+			&rbac.RoleGetter{Lister: a.roleLister.Cluster(ancestor)},
+			&rbac.RoleBindingLister{Lister: a.noRoleBindings},
+			&rbac.ClusterRoleGetter{Lister: a.inheritedClusterRoles(ancestor, policies[i+1:])},
+			&rbac.ClusterRoleBindingLister{Lister: clusterRoleBindings},
+		)
+		dec, reason, err := inherited.Authorize(ctx, attr)
+		if dec == authorizer.DecisionAllow {
+			return dec, fmt.Sprintf("inherited from workspace %q: %s", ancestor, reason), nil
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return authorizer.DecisionNoOpinion, "", utilerrors.NewAggregate(errs)
+}
+
+// inheritedClusterRoles returns the ClusterRoles visible in the given workspace: its own, those
+// propagated by its ancestors with the given policies, and the bootstrap ClusterRoles.
+func (a *InheritedAuthorizer) inheritedClusterRoles(clusterName logicalcluster.Name, policies []*propagatedPolicy) layeredClusterRoleLister {
+	clusterRoles := layeredClusterRoleLister{a.clusterRoleLister.Cluster(clusterName)}
+	for _, p := range policies {
+		if p.hasClusterRoles {
+			clusterRoles = append(clusterRoles, p.clusterRoles)
+		}
+	}
+	return append(clusterRoles, a.clusterRoleLister.Cluster(genericcontrolplane.LocalAdminCluster))
+}
+
+// withoutServiceAccountSubjects returns the given binding without its ServiceAccount subjects,
+// including User subjects naming a ServiceAccount.
+func withoutServiceAccountSubjects(b *rbacv1.ClusterRoleBinding) *rbacv1.ClusterRoleBinding {
+	subjects := make([]rbacv1.Subject, 0, len(b.Subjects))
+	for _, s := range b.Subjects {
+		if s.Kind == rbacv1.ServiceAccountKind {
+			continue
+		}
+		if _, _, err := authserviceaccount.SplitUsername(s.Name); s.Kind == rbacv1.UserKind && err == nil {
+			continue
+		}
+		subjects = append(subjects, s)
+	}
+	if len(subjects) == len(b.Subjects) {
+		return b
+	}
+	b = b.DeepCopy()
+	b.Subjects = subjects
+	return b
+}
+
+// isServiceAccountOf returns whether the user is a ServiceAccount of the given workspace.
+func isServiceAccountOf(u user.Info, clusterName logicalcluster.Name) bool {
+	if _, _, err := authserviceaccount.SplitUsername(u.GetName()); err != nil {
+		return false
+	}
+	for _, sc := range u.GetExtra()[authserviceaccount.ClusterNameKey] {
+		if sc == clusterName.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// propagatingAncestors returns the ancestors of the given workspace below the root workspace,
+// the parent first.
+func propagatingAncestors(clusterName logicalcluster.Name) []logicalcluster.Name {
+	if !clusterName.HasPrefix(tenancyv1alpha1.RootCluster) {
+		return nil
+	}
+	var ancestors []logicalcluster.Name
+	for {
+		parent, hasParent := clusterName.Parent()
+		if !hasParent || parent == tenancyv1alpha1.RootCluster {
+			return ancestors
+		}
+		ancestors = append(ancestors, parent)
+		clusterName = parent
+	}
+}
+
+var _ rbaclisters.ClusterRoleLister = layeredClusterRoleLister{}
+
+// layeredClusterRoleLister resolves a ClusterRole by name in all of its listers, and returns the
+// union of the rules of the found ClusterRoles. Unlike the merged listers of the rbac wrappers, it
+// never changes the objects of its listers.
+type layeredClusterRoleLister []rbaclisters.ClusterRoleLister
+
+// List returns the ClusterRoles matching the selector in any of the listers, each merged over all
+// listers as by Get.
+func (l layeredClusterRoleLister) List(selector labels.Selector) ([]*rbacv1.ClusterRole, error) {
+	var names []string
+	seen := map[string]bool{}
+	for _, lister := range l {
+		roles, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range roles {
+			if !seen[role.Name] {
+				seen[role.Name] = true
+				names = append(names, role.Name)
+			}
+		}
+	}
+	ret := make([]*rbacv1.ClusterRole, 0, len(names))
+	for _, name := range names {
+		role, err := l.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, role)
+	}
+	return ret, nil
+}
+
+func (l layeredClusterRoleLister) Get(name string) (*rbacv1.ClusterRole, error) {
+	var merged *rbacv1.ClusterRole
+	copied := false
+	for _, lister := range l {
+		role, err := lister.Get(name)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if merged == nil {
+			merged = role
+			continue
+		}
+		if !copied {
+			merged = merged.DeepCopy()
+			copied = true
+		}
+		merged.Rules = append(merged.Rules, role.Rules...)
+	}
+	if merged == nil {
+		return nil, apierrors.NewNotFound(rbacv1.Resource("clusterroles"), name)
+	}
+	return merged, nil
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authorization
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kcpkubernetesinformers "github.com/kcp-dev/client-go/informers"
+	kcpfakeclient "github.com/kcp-dev/client-go/kubernetes/fake"
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	rbaclisters "k8s.io/client-go/listers/rbac/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubernetes/pkg/controller"
+)
+
+func TestPropagatingAncestors(t *testing.T) {
+	tests := []struct {
+		cluster string
+		want    []logicalcluster.Name
+	}{
+		{"root", nil},
+		{"root:org", nil},
+		{"root:org:team", []logicalcluster.Name{logicalcluster.New("root:org")}},
+		{"root:org:team:dev", []logicalcluster.Name{logicalcluster.New("root:org:team"), logicalcluster.New("root:org")}},
+		{"system:admin", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cluster, func(t *testing.T) {
+			require.Equal(t, tt.want, propagatingAncestors(logicalcluster.New(tt.cluster)))
+		})
+	}
+}
+
+func TestInheritedAuthorizer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kubeClient := kcpfakeclient.NewSimpleClientset(
+		clusterRole("root", "root-admin", true, "*"),
+		clusterRoleBinding("root", "dave-root-admin", true, "dave", "root-admin"),
+
+		clusterRole("root:org", "org-admin", false, "*"),
+		clusterRole("root:org", "org-viewer", true, "get"),
+		clusterRoleBinding("root:org", "alice-org-admin", true, "alice", "org-admin"),
+		clusterRoleBinding("root:org", "bob-org-admin", false, "bob", "org-admin"),
+		serviceAccountClusterRoleBinding("root:org", "default-org-admin", true, "default", "default", "org-admin"),
+
+		clusterRoleBinding("root:org:team", "carol-org-viewer", false, "carol", "org-viewer"),
+	)
+	kubeSharedInformerFactory := kcpkubernetesinformers.NewSharedInformerFactory(kubeClient, controller.NoResyncPeriodFunc())
+	a := NewInheritedAuthorizer(kubeSharedInformerFactory)
+	informers := []cache.SharedIndexInformer{
+		kubeSharedInformerFactory.Rbac().V1().Roles().Informer(),
+		kubeSharedInformerFactory.Rbac().V1().RoleBindings().Informer(),
+		kubeSharedInformerFactory.Rbac().V1().ClusterRoles().Informer(),
+		kubeSharedInformerFactory.Rbac().V1().ClusterRoleBindings().Informer(),
+	}
+	var syncs []cache.InformerSynced
+	for i := range informers {
+		go informers[i].Run(ctx.Done())
+		syncs = append(syncs, informers[i].HasSynced)
+	}
+	cache.WaitForCacheSync(ctx.Done(), syncs...)
+
+	authorizeUser := func(cluster string, u user.Info, verb string) authorizer.Decision {
+		ctx := request.WithCluster(ctx, request.Cluster{Name: logicalcluster.New(cluster)})
+		dec, _, _ := a.Authorize(ctx, authorizer.AttributesRecord{
+			User:            u,
+			Verb:            verb,
+			Namespace:       "default",
+			Resource:        "configmaps",
+			Name:            "cm",
+			ResourceRequest: true,
+		})
+		return dec
+	}
+	authorize := func(cluster, user, verb string) authorizer.Decision {
+		return authorizeUser(cluster, newUser(user), verb)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		cluster string
+		user    string
+		verb    string
+		want    authorizer.Decision
+	}{
+		{"propagated binding applies in child", "root:org:team", "alice", "delete", authorizer.DecisionAllow},
+		{"propagated binding applies in grandchild", "root:org:team:dev", "alice", "delete", authorizer.DecisionAllow},
+		{"propagated binding is not evaluated in its own workspace", "root:org", "alice", "delete", authorizer.DecisionNoOpinion},
+		{"binding without annotation is not propagated", "root:org:team", "bob", "get", authorizer.DecisionNoOpinion},
+		{"local binding to propagated role", "root:org:team", "carol", "get", authorizer.DecisionAllow},
+		{"local binding to propagated role is limited to its rules", "root:org:team", "carol", "delete", authorizer.DecisionNoOpinion},
+		{"local binding does not apply in child", "root:org:team:dev", "carol", "get", authorizer.DecisionNoOpinion},
+		{"root does not propagate", "root:org:team", "dave", "get", authorizer.DecisionNoOpinion},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, authorize(tt.cluster, tt.user, tt.verb))
+		})
+	}
+
+	for _, tt := range []struct {
+		name    string
+		cluster string
+		user    user.Info
+		want    authorizer.Decision
+	}{
+		{"propagated binding applies to ServiceAccount of its workspace", "root:org:team", newServiceAccountWithCluster("system:serviceaccount:default:default", "root:org"), authorizer.DecisionAllow},
+		{"propagated binding does not apply to equally named ServiceAccount of child", "root:org:team", newServiceAccountWithCluster("system:serviceaccount:default:default", "root:org:team"), authorizer.DecisionNoOpinion},
+		{"propagated binding does not apply to equally named ServiceAccount of grandchild", "root:org:team:dev", newServiceAccountWithCluster("system:serviceaccount:default:default", "root:org:team:dev"), authorizer.DecisionNoOpinion},
+		{"propagated binding does not apply to ServiceAccount without cluster", "root:org:team", newServiceAccount("system:serviceaccount:default:default"), authorizer.DecisionNoOpinion},
+		{"propagated binding does not apply to user named like a ServiceAccount", "root:org:team", newUser("system:serviceaccount:default:default"), authorizer.DecisionNoOpinion},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, authorizeUser(tt.cluster, tt.user, "delete"))
+		})
+	}
+
+	t.Run("removing the annotation invalidates the cache", func(t *testing.T) {
+		require.Equal(t, authorizer.DecisionAllow, authorize("root:org:team", "alice", "delete"))
+
+		binding := clusterRoleBinding("root:org", "alice-org-admin", false, "alice", "org-admin")
+		_, err := kubeClient.Cluster(logicalcluster.New("root:org")).RbacV1().ClusterRoleBindings().Update(ctx, binding, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			return authorize("root:org:team", "alice", "delete") == authorizer.DecisionNoOpinion
+		}, wait.ForeverTestTimeout, 100*time.Millisecond)
+	})
+}
+
+func clusterRole(cluster, name string, propagate bool, verbs ...string) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: propagatedObjectMeta(cluster, name, propagate),
+		Rules: []rbacv1.PolicyRule{{
+			Verbs:     verbs,
+			APIGroups: []string{""},
+			Resources: []string{"configmaps"},
+		}},
+	}
+}
+
+func clusterRoleBinding(cluster, name string, propagate bool, user, role string) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: propagatedObjectMeta(cluster, name, propagate),
+		Subjects: []rbacv1.Subject{{
+			Kind:     "User",
+			APIGroup: "rbac.authorization.k8s.io",
+			Name:     user,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     role,
+		},
+	}
+}
+
+func serviceAccountClusterRoleBinding(cluster, name string, propagate bool, namespace, serviceAccount, role string) *rbacv1.ClusterRoleBinding {
+	b := clusterRoleBinding(cluster, name, propagate, "", role)
+	b.Subjects = []rbacv1.Subject{{
+		Kind:      "ServiceAccount",
+		Namespace: namespace,
+		Name:      serviceAccount,
+	}}
+	return b
+}
+
+func propagatedObjectMeta(cluster, name string, propagate bool) metav1.ObjectMeta {
+	m := metav1.ObjectMeta{
+		Name: name,
+		Annotations: map[string]string{
+			logicalcluster.AnnotationKey: cluster,
+		},
+	}
+	if propagate {
+		m.Annotations[PropagateAnnotationKey] = "true"
+	}
+	return m
+}
+
+func TestLayeredClusterRoleLister(t *testing.T) {
+	lister := func(roles ...*rbacv1.ClusterRole) rbaclisters.ClusterRoleLister {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		for _, r := range roles {
+			require.NoError(t, indexer.Add(r))
+		}
+		return rbaclisters.NewClusterRoleLister(indexer)
+	}
+	l := layeredClusterRoleLister{
+		lister(clusterRole("root:org:team", "viewer", false, "get")),
+		lister(clusterRole("root:org", "viewer", true, "list"), clusterRole("root:org", "editor", true, "update")),
+	}
+
+	roles, err := l.List(labels.Everything())
+	require.NoError(t, err)
+	require.Len(t, roles, 2)
+	require.Equal(t, "viewer", roles[0].Name)
+	require.Len(t, roles[0].Rules, 2, "rules of all layers are merged")
+	require.Equal(t, "editor", roles[1].Name)
+
+	viewer, err := l.Get("viewer")
+	require.NoError(t, err)
+	require.Equal(t, roles[0], viewer)
+}
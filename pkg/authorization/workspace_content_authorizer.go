@@ -32,6 +32,7 @@ import (
 	authserviceaccount "k8s.io/apiserver/pkg/authentication/serviceaccount"
 	"k8s.io/apiserver/pkg/authentication/user"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/union"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/kubernetes/pkg/genericcontrolplane"
 	"k8s.io/kubernetes/plugin/pkg/auth/authorizer/rbac"
@@ -51,13 +52,14 @@ const (
 	WorkspaceContentAuditReason   = WorkspaceContentAuditPrefix + "reason"
 )
 
-func NewWorkspaceContentAuthorizer(versionedInformers kcpkubernetesinformers.SharedInformerFactory, clusterWorkspaceLister tenancyv1alpha1listers.ClusterWorkspaceClusterLister, delegate authorizer.Authorizer) authorizer.Authorizer {
+func NewWorkspaceContentAuthorizer(versionedInformers kcpkubernetesinformers.SharedInformerFactory, clusterWorkspaceLister tenancyv1alpha1listers.ClusterWorkspaceClusterLister, inherited *InheritedAuthorizer, delegate authorizer.Authorizer) authorizer.Authorizer {
 	return &workspaceContentAuthorizer{
 		roleLister:               versionedInformers.Rbac().V1().Roles().Lister(),
 		roleBindingLister:        versionedInformers.Rbac().V1().RoleBindings().Lister(),
 		clusterRoleLister:        versionedInformers.Rbac().V1().ClusterRoles().Lister(),
 		clusterRoleBindingLister: versionedInformers.Rbac().V1().ClusterRoleBindings().Lister(),
 		clusterWorkspaceLister:   clusterWorkspaceLister,
+		inherited:                inherited,
 
 		delegate: delegate,
 	}
@@ -69,6 +71,8 @@ type workspaceContentAuthorizer struct {
 	clusterRoleBindingLister rbacv1listers.ClusterRoleBindingClusterLister
 	clusterRoleLister        rbacv1listers.ClusterRoleClusterLister
 	clusterWorkspaceLister   tenancyv1alpha1listers.ClusterWorkspaceClusterLister
+	// inherited evaluates the policy the parent inherits from its ancestors, if not nil
+	inherited *InheritedAuthorizer
 
 	// union of local and bootstrap authorizer
 	delegate authorizer.Authorizer
@@ -150,7 +154,7 @@ func (a *workspaceContentAuthorizer) Authorize(ctx context.Context, attr authori
 		return authorizer.DecisionNoOpinion, WorkspaceAccessNotPermittedReason, nil
 	}
 
-	parentAuthorizer := rbac.New(
+	var parentAuthorizer authorizer.Authorizer = rbac.New(
 		&rbac.RoleGetter{Lister: rbacwrapper.NewMergedRoleLister(
 			a.roleLister.Cluster(parentClusterName),
 			a.roleLister.Cluster(genericcontrolplane.LocalAdminCluster),
@@ -165,6 +169,11 @@ func (a *workspaceContentAuthorizer) Authorize(ctx context.Context, attr authori
 			a.clusterRoleBindingLister.Cluster(genericcontrolplane.LocalAdminCluster),
 		)},
 	)
+	if a.inherited != nil {
+		parentAuthorizer = union.New(parentAuthorizer, authorizer.AuthorizerFunc(func(ctx context.Context, attr authorizer.Attributes) (authorizer.Decision, string, error) {
+			return a.inherited.authorizeIn(ctx, parentClusterName, attr)
+		}))
+	}
 
 	extraGroups := sets.NewString()
 
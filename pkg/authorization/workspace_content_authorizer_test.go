@@ -399,7 +399,7 @@ func TestWorkspaceContentAuthorizer(t *testing.T) {
 			lister := tenancyv1alpha1listers.NewClusterWorkspaceClusterLister(indexer)
 
 			recordingAuthorizer := &recordingAuthorizer{}
-			w := NewWorkspaceContentAuthorizer(kubeShareInformerFactory, lister, recordingAuthorizer)
+			w := NewWorkspaceContentAuthorizer(kubeShareInformerFactory, lister, nil, recordingAuthorizer)
 
 			requestedCluster := request.Cluster{
 				Name: logicalcluster.New(tt.requestedWorkspace),
@@ -52,10 +52,38 @@ func (c *TenancyV1alpha1ClusterClient) ClusterWorkspaceTypes() kcptenancyv1alpha
 	return &clusterWorkspaceTypesClusterClient{Fake: c.Fake}
 }
 
+func (c *TenancyV1alpha1ClusterClient) ClusterWorkspaceQuotas() kcptenancyv1alpha1.ClusterWorkspaceQuotaClusterInterface {
+	return &clusterWorkspaceQuotasClusterClient{Fake: c.Fake}
+}
+
+func (c *TenancyV1alpha1ClusterClient) WorkspaceAuthenticationConfigurations() kcptenancyv1alpha1.WorkspaceAuthenticationConfigurationClusterInterface {
+	return &workspaceAuthenticationConfigurationsClusterClient{Fake: c.Fake}
+}
+
+func (c *TenancyV1alpha1ClusterClient) ClusterWorkspaceTemplates() kcptenancyv1alpha1.ClusterWorkspaceTemplateClusterInterface {
+	return &clusterWorkspaceTemplatesClusterClient{Fake: c.Fake}
+}
+
+func (c *TenancyV1alpha1ClusterClient) ClusterWorkspaceTombstones() kcptenancyv1alpha1.ClusterWorkspaceTombstoneClusterInterface {
+	return &clusterWorkspaceTombstonesClusterClient{Fake: c.Fake}
+}
+
 func (c *TenancyV1alpha1ClusterClient) ClusterWorkspaceShards() kcptenancyv1alpha1.ClusterWorkspaceShardClusterInterface {
 	return &clusterWorkspaceShardsClusterClient{Fake: c.Fake}
 }
 
+func (c *TenancyV1alpha1ClusterClient) WorkspaceMigrations() kcptenancyv1alpha1.WorkspaceMigrationClusterInterface {
+	return &workspaceMigrationsClusterClient{Fake: c.Fake}
+}
+
+func (c *TenancyV1alpha1ClusterClient) WorkspaceBackups() kcptenancyv1alpha1.WorkspaceBackupClusterInterface {
+	return &workspaceBackupsClusterClient{Fake: c.Fake}
+}
+
+func (c *TenancyV1alpha1ClusterClient) WorkspaceRestores() kcptenancyv1alpha1.WorkspaceRestoreClusterInterface {
+	return &workspaceRestoresClusterClient{Fake: c.Fake}
+}
+
 var _ tenancyv1alpha1.TenancyV1alpha1Interface = (*TenancyV1alpha1Client)(nil)
 
 type TenancyV1alpha1Client struct {
@@ -76,6 +104,34 @@ func (c *TenancyV1alpha1Client) ClusterWorkspaceTypes() tenancyv1alpha1.ClusterW
 	return &clusterWorkspaceTypesClient{Fake: c.Fake, Cluster: c.Cluster}
 }
 
+func (c *TenancyV1alpha1Client) ClusterWorkspaceQuotas() tenancyv1alpha1.ClusterWorkspaceQuotaInterface {
+	return &clusterWorkspaceQuotasClient{Fake: c.Fake, Cluster: c.Cluster}
+}
+
+func (c *TenancyV1alpha1Client) WorkspaceAuthenticationConfigurations() tenancyv1alpha1.WorkspaceAuthenticationConfigurationInterface {
+	return &workspaceAuthenticationConfigurationsClient{Fake: c.Fake, Cluster: c.Cluster}
+}
+
+func (c *TenancyV1alpha1Client) ClusterWorkspaceTemplates() tenancyv1alpha1.ClusterWorkspaceTemplateInterface {
+	return &clusterWorkspaceTemplatesClient{Fake: c.Fake, Cluster: c.Cluster}
+}
+
+func (c *TenancyV1alpha1Client) ClusterWorkspaceTombstones() tenancyv1alpha1.ClusterWorkspaceTombstoneInterface {
+	return &clusterWorkspaceTombstonesClient{Fake: c.Fake, Cluster: c.Cluster}
+}
+
 func (c *TenancyV1alpha1Client) ClusterWorkspaceShards() tenancyv1alpha1.ClusterWorkspaceShardInterface {
 	return &clusterWorkspaceShardsClient{Fake: c.Fake, Cluster: c.Cluster}
 }
+
+func (c *TenancyV1alpha1Client) WorkspaceMigrations() tenancyv1alpha1.WorkspaceMigrationInterface {
+	return &workspaceMigrationsClient{Fake: c.Fake, Cluster: c.Cluster}
+}
+
+func (c *TenancyV1alpha1Client) WorkspaceBackups() tenancyv1alpha1.WorkspaceBackupInterface {
+	return &workspaceBackupsClient{Fake: c.Fake, Cluster: c.Cluster}
+}
+
+func (c *TenancyV1alpha1Client) WorkspaceRestores() tenancyv1alpha1.WorkspaceRestoreInterface {
+	return &workspaceRestoresClient{Fake: c.Fake, Cluster: c.Cluster}
+}
@@ -36,7 +36,14 @@ type TenancyV1alpha1ClusterInterface interface {
 	TenancyV1alpha1ClusterScoper
 	ClusterWorkspacesClusterGetter
 	ClusterWorkspaceTypesClusterGetter
+	ClusterWorkspaceQuotasClusterGetter
+	WorkspaceAuthenticationConfigurationsClusterGetter
+	ClusterWorkspaceTemplatesClusterGetter
+	ClusterWorkspaceTombstonesClusterGetter
 	ClusterWorkspaceShardsClusterGetter
+	WorkspaceMigrationsClusterGetter
+	WorkspaceBackupsClusterGetter
+	WorkspaceRestoresClusterGetter
 }
 
 type TenancyV1alpha1ClusterScoper interface {
@@ -62,10 +69,38 @@ func (c *TenancyV1alpha1ClusterClient) ClusterWorkspaceTypes() ClusterWorkspaceT
 	return &clusterWorkspaceTypesClusterInterface{clientCache: c.clientCache}
 }
 
+func (c *TenancyV1alpha1ClusterClient) ClusterWorkspaceQuotas() ClusterWorkspaceQuotaClusterInterface {
+	return &clusterWorkspaceQuotasClusterInterface{clientCache: c.clientCache}
+}
+
+func (c *TenancyV1alpha1ClusterClient) WorkspaceAuthenticationConfigurations() WorkspaceAuthenticationConfigurationClusterInterface {
+	return &workspaceAuthenticationConfigurationsClusterInterface{clientCache: c.clientCache}
+}
+
+func (c *TenancyV1alpha1ClusterClient) ClusterWorkspaceTemplates() ClusterWorkspaceTemplateClusterInterface {
+	return &clusterWorkspaceTemplatesClusterInterface{clientCache: c.clientCache}
+}
+
+func (c *TenancyV1alpha1ClusterClient) ClusterWorkspaceTombstones() ClusterWorkspaceTombstoneClusterInterface {
+	return &clusterWorkspaceTombstonesClusterInterface{clientCache: c.clientCache}
+}
+
 func (c *TenancyV1alpha1ClusterClient) ClusterWorkspaceShards() ClusterWorkspaceShardClusterInterface {
 	return &clusterWorkspaceShardsClusterInterface{clientCache: c.clientCache}
 }
 
+func (c *TenancyV1alpha1ClusterClient) WorkspaceMigrations() WorkspaceMigrationClusterInterface {
+	return &workspaceMigrationsClusterInterface{clientCache: c.clientCache}
+}
+
+func (c *TenancyV1alpha1ClusterClient) WorkspaceBackups() WorkspaceBackupClusterInterface {
+	return &workspaceBackupsClusterInterface{clientCache: c.clientCache}
+}
+
+func (c *TenancyV1alpha1ClusterClient) WorkspaceRestores() WorkspaceRestoreClusterInterface {
+	return &workspaceRestoresClusterInterface{clientCache: c.clientCache}
+}
+
 // NewForConfig creates a new TenancyV1alpha1ClusterClient for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
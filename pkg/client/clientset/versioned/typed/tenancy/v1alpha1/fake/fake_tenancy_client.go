@@ -33,6 +33,22 @@ func (c *FakeTenancyV1alpha1) ClusterWorkspaces() v1alpha1.ClusterWorkspaceInter
 	return &FakeClusterWorkspaces{c}
 }
 
+func (c *FakeTenancyV1alpha1) ClusterWorkspaceQuotas() v1alpha1.ClusterWorkspaceQuotaInterface {
+	return &FakeClusterWorkspaceQuotas{c}
+}
+
+func (c *FakeTenancyV1alpha1) WorkspaceAuthenticationConfigurations() v1alpha1.WorkspaceAuthenticationConfigurationInterface {
+	return &FakeWorkspaceAuthenticationConfigurations{c}
+}
+
+func (c *FakeTenancyV1alpha1) ClusterWorkspaceTemplates() v1alpha1.ClusterWorkspaceTemplateInterface {
+	return &FakeClusterWorkspaceTemplates{c}
+}
+
+func (c *FakeTenancyV1alpha1) ClusterWorkspaceTombstones() v1alpha1.ClusterWorkspaceTombstoneInterface {
+	return &FakeClusterWorkspaceTombstones{c}
+}
+
 func (c *FakeTenancyV1alpha1) ClusterWorkspaceShards() v1alpha1.ClusterWorkspaceShardInterface {
 	return &FakeClusterWorkspaceShards{c}
 }
@@ -41,6 +57,18 @@ func (c *FakeTenancyV1alpha1) ClusterWorkspaceTypes() v1alpha1.ClusterWorkspaceT
 	return &FakeClusterWorkspaceTypes{c}
 }
 
+func (c *FakeTenancyV1alpha1) WorkspaceMigrations() v1alpha1.WorkspaceMigrationInterface {
+	return &FakeWorkspaceMigrations{c}
+}
+
+func (c *FakeTenancyV1alpha1) WorkspaceBackups() v1alpha1.WorkspaceBackupInterface {
+	return &FakeWorkspaceBackups{c}
+}
+
+func (c *FakeTenancyV1alpha1) WorkspaceRestores() v1alpha1.WorkspaceRestoreInterface {
+	return &FakeWorkspaceRestores{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeTenancyV1alpha1) RESTClient() rest.Interface {
@@ -20,6 +20,20 @@ package v1alpha1
 
 type ClusterWorkspaceExpansion interface{}
 
+type ClusterWorkspaceQuotaExpansion interface{}
+
+type WorkspaceAuthenticationConfigurationExpansion interface{}
+
+type ClusterWorkspaceTemplateExpansion interface{}
+
+type ClusterWorkspaceTombstoneExpansion interface{}
+
 type ClusterWorkspaceShardExpansion interface{}
 
 type ClusterWorkspaceTypeExpansion interface{}
+
+type WorkspaceMigrationExpansion interface{}
+
+type WorkspaceBackupExpansion interface{}
+
+type WorkspaceRestoreExpansion interface{}
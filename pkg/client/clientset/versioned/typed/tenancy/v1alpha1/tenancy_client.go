@@ -30,8 +30,15 @@ import (
 type TenancyV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	ClusterWorkspacesGetter
+	ClusterWorkspaceQuotasGetter
+	WorkspaceAuthenticationConfigurationsGetter
+	ClusterWorkspaceTemplatesGetter
+	ClusterWorkspaceTombstonesGetter
 	ClusterWorkspaceShardsGetter
 	ClusterWorkspaceTypesGetter
+	WorkspaceMigrationsGetter
+	WorkspaceBackupsGetter
+	WorkspaceRestoresGetter
 }
 
 // TenancyV1alpha1Client is used to interact with features provided by the tenancy.kcp.dev group.
@@ -43,6 +50,22 @@ func (c *TenancyV1alpha1Client) ClusterWorkspaces() ClusterWorkspaceInterface {
 	return newClusterWorkspaces(c)
 }
 
+func (c *TenancyV1alpha1Client) ClusterWorkspaceQuotas() ClusterWorkspaceQuotaInterface {
+	return newClusterWorkspaceQuotas(c)
+}
+
+func (c *TenancyV1alpha1Client) WorkspaceAuthenticationConfigurations() WorkspaceAuthenticationConfigurationInterface {
+	return newWorkspaceAuthenticationConfigurations(c)
+}
+
+func (c *TenancyV1alpha1Client) ClusterWorkspaceTemplates() ClusterWorkspaceTemplateInterface {
+	return newClusterWorkspaceTemplates(c)
+}
+
+func (c *TenancyV1alpha1Client) ClusterWorkspaceTombstones() ClusterWorkspaceTombstoneInterface {
+	return newClusterWorkspaceTombstones(c)
+}
+
 func (c *TenancyV1alpha1Client) ClusterWorkspaceShards() ClusterWorkspaceShardInterface {
 	return newClusterWorkspaceShards(c)
 }
@@ -51,6 +74,18 @@ func (c *TenancyV1alpha1Client) ClusterWorkspaceTypes() ClusterWorkspaceTypeInte
 	return newClusterWorkspaceTypes(c)
 }
 
+func (c *TenancyV1alpha1Client) WorkspaceMigrations() WorkspaceMigrationInterface {
+	return newWorkspaceMigrations(c)
+}
+
+func (c *TenancyV1alpha1Client) WorkspaceBackups() WorkspaceBackupInterface {
+	return newWorkspaceBackups(c)
+}
+
+func (c *TenancyV1alpha1Client) WorkspaceRestores() WorkspaceRestoreInterface {
+	return newWorkspaceRestores(c)
+}
+
 // NewForConfig creates a new TenancyV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusteraware
+
+import (
+	"fmt"
+	"time"
+
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	kcpdynamicinformer "github.com/kcp-dev/client-go/dynamic/dynamicinformer"
+	kcpkubernetesinformers "github.com/kcp-dev/client-go/informers"
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/client-go/rest"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+// Clients are the cluster-aware clients for a kcp endpoint, i.e. a kcp server or a virtual workspace.
+// Use Cluster(clusterName) to scope them to a logical cluster, or logicalcluster.Wildcard to act across
+// all logical clusters visible at the endpoint.
+type Clients struct {
+	Kube    kcpkubernetesclientset.ClusterInterface
+	Kcp     kcpclientset.ClusterInterface
+	Dynamic kcpdynamic.ClusterInterface
+}
+
+// NewClients creates the cluster-aware clients for the given config. The host of the config must not
+// contain a /clusters/<name> suffix.
+func NewClients(config *rest.Config) (*Clients, error) {
+	kubeClusterClient, err := kcpkubernetesclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClusterClient, err := kcpdynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Clients{
+		Kube:    kubeClusterClient,
+		Kcp:     kcpClusterClient,
+		Dynamic: dynamicClusterClient,
+	}, nil
+}
+
+// InformerFactories are the cluster-aware informer factories for a kcp endpoint. Their informers watch
+// across all logical clusters, and their listers can be scoped to a logical cluster with Cluster(clusterName).
+type InformerFactories struct {
+	Kube    kcpkubernetesinformers.SharedInformerFactory
+	Kcp     kcpinformers.SharedInformerFactory
+	Dynamic kcpdynamicinformer.DynamicSharedInformerFactory
+}
+
+// NewInformerFactories creates the cluster-aware informer factories for the given clients.
+func NewInformerFactories(clients *Clients, resync time.Duration) *InformerFactories {
+	return &InformerFactories{
+		Kube:    kcpkubernetesinformers.NewSharedInformerFactory(clients.Kube, resync),
+		Kcp:     kcpinformers.NewSharedInformerFactory(clients.Kcp, resync),
+		Dynamic: kcpdynamicinformer.NewFilteredDynamicSharedInformerFactory(clients.Dynamic, resync, nil),
+	}
+}
+
+// Start starts all informers requested so far.
+func (f *InformerFactories) Start(stopCh <-chan struct{}) {
+	f.Kube.Start(stopCh)
+	f.Kcp.Start(stopCh)
+	f.Dynamic.Start(stopCh)
+}
+
+// WaitForCacheSync waits for the caches of all started informers to sync, and returns false if
+// any did not sync before stopCh was closed.
+func (f *InformerFactories) WaitForCacheSync(stopCh <-chan struct{}) bool {
+	synced := true
+	for _, ok := range f.Kube.WaitForCacheSync(stopCh) {
+		synced = synced && ok
+	}
+	for _, ok := range f.Kcp.WaitForCacheSync(stopCh) {
+		synced = synced && ok
+	}
+	for _, ok := range f.Dynamic.WaitForCacheSync(stopCh) {
+		synced = synced && ok
+	}
+	return synced
+}
+
+// APIExportVirtualWorkspaceConfigs returns a config per virtual workspace URL of the given APIExport,
+// derived from the given config, e.g. the one used to get the APIExport. There is a URL per shard,
+// and a controller has to serve all of them. The URLs are only published once the APIExport is bound.
+func APIExportVirtualWorkspaceConfigs(config *rest.Config, apiExport *apisv1alpha1.APIExport) ([]*rest.Config, error) {
+	if len(apiExport.Status.VirtualWorkspaces) == 0 {
+		return nil, fmt.Errorf("APIExport %s|%s has no virtual workspace URLs yet", logicalcluster.From(apiExport), apiExport.Name)
+	}
+
+	configs := make([]*rest.Config, 0, len(apiExport.Status.VirtualWorkspaces))
+	for _, vw := range apiExport.Status.VirtualWorkspaces {
+		vwConfig := rest.CopyConfig(config)
+		vwConfig.Host = vw.URL
+		configs = append(configs, vwConfig)
+	}
+	return configs, nil
+}
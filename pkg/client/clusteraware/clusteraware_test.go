@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusteraware
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestKey(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "cm",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org:ws"},
+		},
+	}
+
+	key, err := Key(cm)
+	require.NoError(t, err)
+	require.Equal(t, ToKey(logicalcluster.New("root:org:ws"), "default", "cm"), key)
+
+	tombstoneKey, err := Key(cache.DeletedFinalStateUnknown{Key: key, Obj: cm})
+	require.NoError(t, err)
+	require.Equal(t, key, tombstoneKey)
+
+	clusterName, namespace, name, err := SplitKey(key)
+	require.NoError(t, err)
+	require.Equal(t, logicalcluster.New("root:org:ws"), clusterName)
+	require.Equal(t, "default", namespace)
+	require.Equal(t, "cm", name)
+}
+
+func TestAPIExportVirtualWorkspaceConfigs(t *testing.T) {
+	config := &rest.Config{Host: "https://kcp.example.com/clusters/root:org:ws", BearerToken: "token"}
+	apiExport := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "widgets",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org:ws"},
+		},
+	}
+
+	_, err := APIExportVirtualWorkspaceConfigs(config, apiExport)
+	require.EqualError(t, err, "APIExport root:org:ws|widgets has no virtual workspace URLs yet")
+
+	apiExport.Status.VirtualWorkspaces = []apisv1alpha1.VirtualWorkspace{
+		{URL: "https://shard-1.example.com/services/apiexport/root:org:ws/widgets"},
+		{URL: "https://shard-2.example.com/services/apiexport/root:org:ws/widgets"},
+	}
+	configs, err := APIExportVirtualWorkspaceConfigs(config, apiExport)
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+	require.Equal(t, "https://shard-1.example.com/services/apiexport/root:org:ws/widgets", configs[0].Host)
+	require.Equal(t, "https://shard-2.example.com/services/apiexport/root:org:ws/widgets", configs[1].Host)
+	require.Equal(t, "token", configs[1].BearerToken)
+	require.Equal(t, "https://kcp.example.com/clusters/root:org:ws", config.Host, "the given config must not be modified")
+}
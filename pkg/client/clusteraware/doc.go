@@ -0,0 +1,40 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusteraware provides the building blocks for kcp-aware controllers outside of this repository,
+// e.g. controllers of service providers running against the virtual workspace of their APIExport: cluster-aware
+// clients and informer factories built on top of the generated kcp clientset and kcp-dev/client-go, and the
+// workqueue key helpers of kcp-dev/apimachinery.
+//
+// A typical controller looks like this:
+//
+//	configs, err := clusteraware.APIExportVirtualWorkspaceConfigs(cfg, apiExport)
+//	...
+//	clients, err := clusteraware.NewClients(configs[0])
+//	...
+//	informers := clusteraware.NewInformerFactories(clients, 10*time.Minute)
+//	informers.Dynamic.ForResource(widgetsGVR).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+//		AddFunc: func(obj interface{}) {
+//			key, err := clusteraware.Key(obj)
+//			...
+//			queue.Add(key)
+//		},
+//	})
+//	informers.Start(ctx.Done())
+//	...
+//	clusterName, namespace, name, err := clusteraware.SplitKey(key)
+//	widget, err := clients.Dynamic.Cluster(clusterName).Resource(widgetsGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+package clusteraware
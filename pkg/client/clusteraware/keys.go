@@ -0,0 +1,39 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusteraware
+
+import (
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+)
+
+// Key returns the workqueue key of a cluster-aware object, i.e. <cluster>|<namespace>/<name> for namespaced
+// and <cluster>|<name> for cluster-scoped objects. Tombstones of deleted objects are handled.
+func Key(obj interface{}) (string, error) {
+	return kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+}
+
+// SplitKey returns the logical cluster, namespace and name of a key returned by Key.
+func SplitKey(key string) (clusterName logicalcluster.Name, namespace, name string, err error) {
+	return kcpcache.SplitMetaClusterNamespaceKey(key)
+}
+
+// ToKey returns the key of the object with the given logical cluster, namespace and name, e.g. to look it
+// up in an informer's indexer with GetByKey.
+func ToKey(clusterName logicalcluster.Name, namespace, name string) string {
+	return kcpcache.ToClusterAwareKey(clusterName.String(), namespace, name)
+}
@@ -113,8 +113,22 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().ClusterWorkspaces().Informer()}, nil
 	case tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspacetypes"):
 		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().ClusterWorkspaceTypes().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspacequotas"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().ClusterWorkspaceQuotas().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspaceauthenticationconfigurations"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().WorkspaceAuthenticationConfigurations().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspacetemplates"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().ClusterWorkspaceTemplates().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspacetombstones"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().ClusterWorkspaceTombstones().Informer()}, nil
 	case tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspaceshards"):
 		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().ClusterWorkspaceShards().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacemigrations"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().WorkspaceMigrations().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacebackups"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().WorkspaceBackups().Informer()}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacerestores"):
+		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1alpha1().WorkspaceRestores().Informer()}, nil
 	// Group=tenancy.kcp.dev, Version=V1beta1
 	case tenancyv1beta1.SchemeGroupVersion.WithResource("workspaces"):
 		return &genericClusterInformer{resource: resource.GroupResource(), informer: f.Tenancy().V1beta1().Workspaces().Informer()}, nil
@@ -161,9 +175,30 @@ func (f *sharedScopedInformerFactory) ForResource(resource schema.GroupVersionRe
 	case tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspacetypes"):
 		informer := f.Tenancy().V1alpha1().ClusterWorkspaceTypes().Informer()
 		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspacequotas"):
+		informer := f.Tenancy().V1alpha1().ClusterWorkspaceQuotas().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspaceauthenticationconfigurations"):
+		informer := f.Tenancy().V1alpha1().WorkspaceAuthenticationConfigurations().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspacetemplates"):
+		informer := f.Tenancy().V1alpha1().ClusterWorkspaceTemplates().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspacetombstones"):
+		informer := f.Tenancy().V1alpha1().ClusterWorkspaceTombstones().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
 	case tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspaceshards"):
 		informer := f.Tenancy().V1alpha1().ClusterWorkspaceShards().Informer()
 		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacemigrations"):
+		informer := f.Tenancy().V1alpha1().WorkspaceMigrations().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacebackups"):
+		informer := f.Tenancy().V1alpha1().WorkspaceBackups().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
+	case tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacerestores"):
+		informer := f.Tenancy().V1alpha1().WorkspaceRestores().Informer()
+		return &genericInformer{lister: cache.NewGenericLister(informer.GetIndexer(), resource.GroupResource()), informer: informer}, nil
 	// Group=tenancy.kcp.dev, Version=V1beta1
 	case tenancyv1beta1.SchemeGroupVersion.WithResource("workspaces"):
 		informer := f.Tenancy().V1beta1().Workspaces().Informer()
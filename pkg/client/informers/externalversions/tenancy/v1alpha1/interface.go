@@ -30,8 +30,22 @@ type ClusterInterface interface {
 	ClusterWorkspaces() ClusterWorkspaceClusterInformer
 	// ClusterWorkspaceTypes returns a ClusterWorkspaceTypeClusterInformer
 	ClusterWorkspaceTypes() ClusterWorkspaceTypeClusterInformer
+	// ClusterWorkspaceQuotas returns a ClusterWorkspaceQuotaClusterInformer
+	ClusterWorkspaceQuotas() ClusterWorkspaceQuotaClusterInformer
+	// WorkspaceAuthenticationConfigurations returns a WorkspaceAuthenticationConfigurationClusterInformer
+	WorkspaceAuthenticationConfigurations() WorkspaceAuthenticationConfigurationClusterInformer
+	// ClusterWorkspaceTemplates returns a ClusterWorkspaceTemplateClusterInformer
+	ClusterWorkspaceTemplates() ClusterWorkspaceTemplateClusterInformer
+	// ClusterWorkspaceTombstones returns a ClusterWorkspaceTombstoneClusterInformer
+	ClusterWorkspaceTombstones() ClusterWorkspaceTombstoneClusterInformer
 	// ClusterWorkspaceShards returns a ClusterWorkspaceShardClusterInformer
 	ClusterWorkspaceShards() ClusterWorkspaceShardClusterInformer
+	// WorkspaceMigrations returns a WorkspaceMigrationClusterInformer
+	WorkspaceMigrations() WorkspaceMigrationClusterInformer
+	// WorkspaceBackups returns a WorkspaceBackupClusterInformer
+	WorkspaceBackups() WorkspaceBackupClusterInformer
+	// WorkspaceRestores returns a WorkspaceRestoreClusterInformer
+	WorkspaceRestores() WorkspaceRestoreClusterInformer
 }
 
 type version struct {
@@ -54,18 +68,67 @@ func (v *version) ClusterWorkspaceTypes() ClusterWorkspaceTypeClusterInformer {
 	return &clusterWorkspaceTypeClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// ClusterWorkspaceQuotas returns a ClusterWorkspaceQuotaClusterInformer
+func (v *version) ClusterWorkspaceQuotas() ClusterWorkspaceQuotaClusterInformer {
+	return &clusterWorkspaceQuotaClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// WorkspaceAuthenticationConfigurations returns a WorkspaceAuthenticationConfigurationClusterInformer
+func (v *version) WorkspaceAuthenticationConfigurations() WorkspaceAuthenticationConfigurationClusterInformer {
+	return &workspaceAuthenticationConfigurationClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ClusterWorkspaceTemplates returns a ClusterWorkspaceTemplateClusterInformer
+func (v *version) ClusterWorkspaceTemplates() ClusterWorkspaceTemplateClusterInformer {
+	return &clusterWorkspaceTemplateClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ClusterWorkspaceTombstones returns a ClusterWorkspaceTombstoneClusterInformer
+func (v *version) ClusterWorkspaceTombstones() ClusterWorkspaceTombstoneClusterInformer {
+	return &clusterWorkspaceTombstoneClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ClusterWorkspaceShards returns a ClusterWorkspaceShardClusterInformer
 func (v *version) ClusterWorkspaceShards() ClusterWorkspaceShardClusterInformer {
 	return &clusterWorkspaceShardClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// WorkspaceMigrations returns a WorkspaceMigrationClusterInformer
+func (v *version) WorkspaceMigrations() WorkspaceMigrationClusterInformer {
+	return &workspaceMigrationClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// WorkspaceBackups returns a WorkspaceBackupClusterInformer
+func (v *version) WorkspaceBackups() WorkspaceBackupClusterInformer {
+	return &workspaceBackupClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// WorkspaceRestores returns a WorkspaceRestoreClusterInformer
+func (v *version) WorkspaceRestores() WorkspaceRestoreClusterInformer {
+	return &workspaceRestoreClusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 type Interface interface {
 	// ClusterWorkspaces returns a ClusterWorkspaceInformer
 	ClusterWorkspaces() ClusterWorkspaceInformer
 	// ClusterWorkspaceTypes returns a ClusterWorkspaceTypeInformer
 	ClusterWorkspaceTypes() ClusterWorkspaceTypeInformer
+	// ClusterWorkspaceQuotas returns a ClusterWorkspaceQuotaInformer
+	ClusterWorkspaceQuotas() ClusterWorkspaceQuotaInformer
+	// WorkspaceAuthenticationConfigurations returns a WorkspaceAuthenticationConfigurationInformer
+	WorkspaceAuthenticationConfigurations() WorkspaceAuthenticationConfigurationInformer
+	// ClusterWorkspaceTemplates returns a ClusterWorkspaceTemplateInformer
+	ClusterWorkspaceTemplates() ClusterWorkspaceTemplateInformer
+	// ClusterWorkspaceTombstones returns a ClusterWorkspaceTombstoneInformer
+	ClusterWorkspaceTombstones() ClusterWorkspaceTombstoneInformer
 	// ClusterWorkspaceShards returns a ClusterWorkspaceShardInformer
 	ClusterWorkspaceShards() ClusterWorkspaceShardInformer
+	// WorkspaceMigrations returns a WorkspaceMigrationInformer
+	WorkspaceMigrations() WorkspaceMigrationInformer
+	// WorkspaceBackups returns a WorkspaceBackupInformer
+	WorkspaceBackups() WorkspaceBackupInformer
+	// WorkspaceRestores returns a WorkspaceRestoreInformer
+	WorkspaceRestores() WorkspaceRestoreInformer
 }
 
 type scopedVersion struct {
@@ -89,7 +152,42 @@ func (v *scopedVersion) ClusterWorkspaceTypes() ClusterWorkspaceTypeInformer {
 	return &clusterWorkspaceTypeScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// ClusterWorkspaceQuotas returns a ClusterWorkspaceQuotaInformer
+func (v *scopedVersion) ClusterWorkspaceQuotas() ClusterWorkspaceQuotaInformer {
+	return &clusterWorkspaceQuotaScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// WorkspaceAuthenticationConfigurations returns a WorkspaceAuthenticationConfigurationInformer
+func (v *scopedVersion) WorkspaceAuthenticationConfigurations() WorkspaceAuthenticationConfigurationInformer {
+	return &workspaceAuthenticationConfigurationScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ClusterWorkspaceTemplates returns a ClusterWorkspaceTemplateInformer
+func (v *scopedVersion) ClusterWorkspaceTemplates() ClusterWorkspaceTemplateInformer {
+	return &clusterWorkspaceTemplateScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ClusterWorkspaceTombstones returns a ClusterWorkspaceTombstoneInformer
+func (v *scopedVersion) ClusterWorkspaceTombstones() ClusterWorkspaceTombstoneInformer {
+	return &clusterWorkspaceTombstoneScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ClusterWorkspaceShards returns a ClusterWorkspaceShardInformer
 func (v *scopedVersion) ClusterWorkspaceShards() ClusterWorkspaceShardInformer {
 	return &clusterWorkspaceShardScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// WorkspaceMigrations returns a WorkspaceMigrationInformer
+func (v *scopedVersion) WorkspaceMigrations() WorkspaceMigrationInformer {
+	return &workspaceMigrationScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// WorkspaceBackups returns a WorkspaceBackupInformer
+func (v *scopedVersion) WorkspaceBackups() WorkspaceBackupInformer {
+	return &workspaceBackupScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// WorkspaceRestores returns a WorkspaceRestoreInformer
+func (v *scopedVersion) WorkspaceRestores() WorkspaceRestoreInformer {
+	return &workspaceRestoreScopedInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
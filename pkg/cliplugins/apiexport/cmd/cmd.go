@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/kcp-dev/kcp/pkg/cliplugins/apiexport/plugin"
+)
+
+var (
+	rotateIdentityExample = `
+	# Rotate the identity of an APIExport. Bound resources keep working with the new identity.
+	%[1]s apiexport rotate-identity <apiexport-name>
+`
+)
+
+// New provides a cobra command for APIExport operations.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Aliases:          []string{"apiexports"},
+		Use:              "apiexport",
+		Short:            "Manages APIExports",
+		SilenceUsage:     true,
+		TraverseChildren: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	rotateIdentityOpts := plugin.NewRotateIdentityOptions(streams)
+	rotateIdentityCmd := &cobra.Command{
+		Use:          "rotate-identity <apiexport-name>",
+		Short:        "Replace the identity of an APIExport by a newly generated one",
+		Example:      fmt.Sprintf(rotateIdentityExample, "kubectl kcp"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return c.Help()
+			}
+
+			if err := rotateIdentityOpts.Complete(args); err != nil {
+				return err
+			}
+
+			if err := rotateIdentityOpts.Validate(); err != nil {
+				return err
+			}
+
+			return rotateIdentityOpts.Run(c.Context())
+		},
+	}
+
+	rotateIdentityOpts.BindFlags(rotateIdentityCmd)
+	cmd.AddCommand(rotateIdentityCmd)
+
+	return cmd
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
+	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
+	"github.com/kcp-dev/kcp/pkg/crypto"
+)
+
+// RotateIdentityOptions contains options for rotating the identity of an APIExport.
+type RotateIdentityOptions struct {
+	*base.Options
+
+	// APIExport is the name of the APIExport to rotate the identity of.
+	APIExport string
+}
+
+// NewRotateIdentityOptions returns a new RotateIdentityOptions.
+func NewRotateIdentityOptions(streams genericclioptions.IOStreams) *RotateIdentityOptions {
+	return &RotateIdentityOptions{
+		Options: base.NewOptions(streams),
+	}
+}
+
+// Complete ensures all dynamically populated fields are initialized.
+func (o *RotateIdentityOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.APIExport = args[0]
+	}
+
+	return nil
+}
+
+// Validate validates the RotateIdentityOptions are complete and usable.
+func (o *RotateIdentityOptions) Validate() error {
+	if o.APIExport == "" {
+		return errors.New("APIExport name is required")
+	}
+
+	return o.Options.Validate()
+}
+
+// Run creates a new identity secret for the APIExport and points the APIExport to it. The APIExport
+// controller accepts the new identity because of the rotation annotation, and keeps the objects of
+// the bound resources stored under the previous identity.
+func (o *RotateIdentityOptions) Run(ctx context.Context) error {
+	config, err := o.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+
+	kcpClient, err := kcpclient.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create kcp client: %w", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create kube client: %w", err)
+	}
+
+	apiExport, err := kcpClient.ApisV1alpha1().APIExports().Get(ctx, o.APIExport, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get APIExport %s: %w", o.APIExport, err)
+	}
+	if apiExport.Spec.Identity == nil || apiExport.Spec.Identity.SecretRef == nil {
+		return fmt.Errorf("APIExport %s does not reference an identity secret, rotate the key in its identity provider and set the %s annotation to the new hash", o.APIExport, apisv1alpha1.AnnotationRotateIdentityKey)
+	}
+	if apiExport.Status.IdentityHash == "" {
+		return fmt.Errorf("APIExport %s has no identity yet", o.APIExport)
+	}
+	previous := apiExport.Spec.Identity.SecretRef
+
+	key := crypto.Random256BitsString()
+	secret, err := kubeClient.CoreV1().Secrets(previous.Namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: o.APIExport + "-",
+		},
+		StringData: map[string]string{
+			apisv1alpha1.SecretKeyAPIExportIdentity: key,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create identity secret: %w", err)
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(key)))
+
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": apiExport.ResourceVersion,
+			"annotations": map[string]interface{}{
+				apisv1alpha1.AnnotationRotateIdentityKey: hash,
+			},
+		},
+		"spec": map[string]interface{}{
+			"identity": map[string]interface{}{
+				"secretRef": map[string]interface{}{
+					"namespace": secret.Namespace,
+					"name":      secret.Name,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := kcpClient.ApisV1alpha1().APIExports().Patch(ctx, o.APIExport, types.MergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to update APIExport %s: %w", o.APIExport, err)
+	}
+
+	fmt.Fprintf(o.Out, "APIExport %s rotated to identity %s.\n", o.APIExport, hash)
+	fmt.Fprintf(o.Out, "The previous identity secret %s/%s can be deleted when status.identityHash shows the new identity.\n", previous.Namespace, previous.Name)
+	return nil
+}
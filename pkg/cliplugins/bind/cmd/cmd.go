@@ -30,6 +30,12 @@ var (
 	bindExampleUses = `
 	# Create an APIBinding named "my-binding" that binds to the APIExport "my-export" in the "root:my-service" workspace.
 	%[1]s bind apiexport root:my-service:my-export --name my-binding
+
+	# Create an APIBinding to "my-export", accepting its permission claims for configmaps and widgets.example.io.
+	%[1]s bind apiexport root:my-service:my-export --accept-permission-claim configmaps,widgets.example.io
+
+	# Choose from the APIExports you are allowed to bind, and whether to accept their permission claims.
+	%[1]s bind apiexport
 	`
 
 	bindComputeExampleUses = `
@@ -57,7 +63,7 @@ func New(streams genericclioptions.IOStreams) *cobra.Command {
 
 	bindOpts := plugin.NewBindOptions(streams)
 	bindCmd := &cobra.Command{
-		Use:          "apiexport <workspace_path:apiexport-name>",
+		Use:          "apiexport [<workspace_path:apiexport-name>]",
 		Short:        "Bind to an APIExport",
 		Example:      fmt.Sprintf(bindExampleUses, "kubectl kcp"),
 		SilenceUsage: true,
@@ -17,22 +17,33 @@ limitations under the License.
 package plugin
 
 import (
+	"bufio"
 	"context"
-	"errors"
 	"fmt"
+	"io"
 	"net/url"
+	"path"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/kcp-dev/logicalcluster/v2"
 	"github.com/spf13/cobra"
 
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
 	pluginhelpers "github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
@@ -43,11 +54,21 @@ type BindOptions struct {
 	*base.Options
 	// APIExportRef is the argument accepted by the command. It contains the
 	// reference to where APIExport exists. For ex: <absolute_ref_to_workspace>:<apiexport>.
+	// If empty, the APIExports the user may bind are listed to choose from.
 	APIExportRef string
 	// Name of the APIBinding.
 	APIBindingName string
+	// AcceptedPermissionClaims are the permission claims of the APIExport to accept, in the
+	// form <resource>.<group>, or <resource> for core resources.
+	AcceptedPermissionClaims []string
 	// BindWaitTimeout is how long to wait for the APIBinding to be created and successful.
 	BindWaitTimeout time.Duration
+
+	kcpClusterClient kcpclientset.ClusterInterface
+	in               *bufio.Reader
+
+	// for testing
+	canBind func(ctx context.Context, clusterName logicalcluster.Name, apiExportName string) (bool, error)
 }
 
 // NewBindOptions returns new BindOptions.
@@ -62,6 +83,7 @@ func (b *BindOptions) BindFlags(cmd *cobra.Command) {
 	b.Options.BindFlags(cmd)
 
 	cmd.Flags().StringVar(&b.APIBindingName, "name", b.APIBindingName, "Name of the APIBinding to create.")
+	cmd.Flags().StringSliceVar(&b.AcceptedPermissionClaims, "accept-permission-claim", b.AcceptedPermissionClaims, "Permission claim of the APIExport to accept, e.g. configmaps or widgets.example.io. Can be repeated.")
 	cmd.Flags().DurationVar(&b.BindWaitTimeout, "timeout", time.Second*30, "Duration to wait for APIBinding to be created successfully.")
 }
 
@@ -74,29 +96,60 @@ func (b *BindOptions) Complete(args []string) error {
 	if len(args) > 0 {
 		b.APIExportRef = args[0]
 	}
+
+	config, err := b.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := newKCPClusterClient(config)
+	if err != nil {
+		return err
+	}
+	b.kcpClusterClient = kcpClusterClient
+	b.canBind = func(ctx context.Context, clusterName logicalcluster.Name, apiExportName string) (bool, error) {
+		return canBind(ctx, config, clusterName, apiExportName)
+	}
+
 	return nil
 }
 
 // Validate validates the BindOptions are complete and usable.
 func (b *BindOptions) Validate() error {
-	if b.APIExportRef == "" {
-		return errors.New("`root:ws:apiexport_object` reference to bind is required as an argument")
-	}
-
-	if !strings.HasPrefix(b.APIExportRef, "root") || !logicalcluster.New(b.APIExportRef).IsValid() {
+	if b.APIExportRef != "" && (!strings.HasPrefix(b.APIExportRef, "root") || !logicalcluster.New(b.APIExportRef).IsValid()) {
 		return fmt.Errorf("fully qualified reference to workspace where APIExport exists is required. The format is `root:<ws>:<apiexport>`")
 	}
 
 	return b.Options.Validate()
 }
 
-// Run creates an apibinding for the user.
+// Run creates an apibinding for the user. Without APIExport reference, it first lets the user
+// choose one of the APIExports they may bind.
 func (b *BindOptions) Run(ctx context.Context) error {
 	config, err := b.ClientConfig.ClientConfig()
 	if err != nil {
 		return err
 	}
 
+	_, currentClusterName, err := pluginhelpers.ParseClusterURL(config.Host)
+	if err != nil {
+		return fmt.Errorf("current URL %q does not point to cluster workspace", config.Host)
+	}
+
+	interactive := b.APIExportRef == ""
+	var export *apisv1alpha1.APIExport
+	if interactive {
+		candidates, err := b.discoverAPIExports(ctx)
+		if err != nil {
+			return err
+		}
+		selected, err := b.selectAPIExport(candidates)
+		if err != nil {
+			return err
+		}
+		b.APIExportRef = selected.clusterName.Join(selected.export.Name).String()
+		export = selected.export
+	}
+
 	workspacePath, apiExportName := logicalcluster.New(b.APIExportRef).Split()
 
 	// if apibindingName is not provided, default it to <apiExportname>.
@@ -105,9 +158,17 @@ func (b *BindOptions) Run(ctx context.Context) error {
 		apiBindingName = apiExportName
 	}
 
-	_, currentClusterName, err := pluginhelpers.ParseClusterURL(config.Host)
-	if err != nil {
-		return fmt.Errorf("current URL %q does not point to cluster workspace", config.Host)
+	if export == nil && len(b.AcceptedPermissionClaims) > 0 {
+		export, err = b.kcpClusterClient.Cluster(workspacePath).ApisV1alpha1().APIExports().Get(ctx, apiExportName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("error getting the permission claims of APIExport %s: %w", b.APIExportRef, err)
+		}
+	}
+	var claims []apisv1alpha1.AcceptablePermissionClaim
+	if export != nil {
+		if claims, err = b.permissionClaims(export, interactive); err != nil {
+			return err
+		}
 	}
 
 	binding := &apisv1alpha1.APIBinding{
@@ -121,15 +182,11 @@ func (b *BindOptions) Run(ctx context.Context) error {
 					ExportName: apiExportName,
 				},
 			},
+			PermissionClaims: claims,
 		},
 	}
 
-	kcpclient, err := newKCPClusterClient(config)
-	if err != nil {
-		return err
-	}
-
-	createdBinding, err := kcpclient.Cluster(currentClusterName).ApisV1alpha1().APIBindings().Create(ctx, binding, metav1.CreateOptions{})
+	createdBinding, err := b.kcpClusterClient.Cluster(currentClusterName).ApisV1alpha1().APIBindings().Create(ctx, binding, metav1.CreateOptions{})
 	if err != nil {
 		return err
 	}
@@ -138,17 +195,14 @@ func (b *BindOptions) Run(ctx context.Context) error {
 		return err
 	}
 
-	// wait for phase to be bound
-	if createdBinding.Status.Phase != apisv1alpha1.APIBindingPhaseBound {
+	// wait for the initial binding to complete, i.e. for the APIs to be served.
+	if !conditions.IsTrue(createdBinding, apisv1alpha1.InitialBindingCompleted) {
 		if err := wait.PollImmediate(time.Millisecond*500, b.BindWaitTimeout, func() (done bool, err error) {
-			createdBinding, err := kcpclient.Cluster(currentClusterName).ApisV1alpha1().APIBindings().Get(ctx, binding.Name, metav1.GetOptions{})
+			createdBinding, err := b.kcpClusterClient.Cluster(currentClusterName).ApisV1alpha1().APIBindings().Get(ctx, binding.Name, metav1.GetOptions{})
 			if err != nil {
 				return false, err
 			}
-			if createdBinding.Status.Phase == apisv1alpha1.APIBindingPhaseBound {
-				return true, nil
-			}
-			return false, nil
+			return conditions.IsTrue(createdBinding, apisv1alpha1.InitialBindingCompleted), nil
 		}); err != nil {
 			return fmt.Errorf("could not bind %s: %w", binding.Name, err)
 		}
@@ -161,6 +215,176 @@ func (b *BindOptions) Run(ctx context.Context) error {
 	return nil
 }
 
+// apiExportCandidate is an APIExport the user may bind.
+type apiExportCandidate struct {
+	clusterName logicalcluster.Name
+	export      *apisv1alpha1.APIExport
+}
+
+// discoverAPIExports walks the workspaces below root the user has access to, and returns the
+// APIExports in them the user is allowed to bind, ordered by workspace and name.
+func (b *BindOptions) discoverAPIExports(ctx context.Context) ([]apiExportCandidate, error) {
+	var candidates []apiExportCandidate
+	queue := []logicalcluster.Name{tenancyv1alpha1.RootCluster}
+	for len(queue) > 0 {
+		clusterName := queue[0]
+		queue = queue[1:]
+
+		exports, err := b.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIExports().List(ctx, metav1.ListOptions{})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+			return nil, fmt.Errorf("error listing APIExports in workspace %s: %w", clusterName, err)
+		}
+		if err == nil {
+			for i := range exports.Items {
+				export := &exports.Items[i]
+				allowed, err := b.canBind(ctx, clusterName, export.Name)
+				if err != nil {
+					return nil, fmt.Errorf("error checking bind permission for APIExport %s: %w", clusterName.Join(export.Name), err)
+				}
+				if allowed {
+					candidates = append(candidates, apiExportCandidate{clusterName: clusterName, export: export})
+				}
+			}
+		}
+
+		workspaces, err := b.kcpClusterClient.Cluster(clusterName).TenancyV1beta1().Workspaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+				continue
+			}
+			return nil, fmt.Errorf("error listing workspaces in %s: %w", clusterName, err)
+		}
+		for _, ws := range workspaces.Items {
+			queue = append(queue, clusterName.Join(ws.Name))
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].clusterName != candidates[j].clusterName {
+			return candidates[i].clusterName.String() < candidates[j].clusterName.String()
+		}
+		return candidates[i].export.Name < candidates[j].export.Name
+	})
+	return candidates, nil
+}
+
+// selectAPIExport lists the candidates and asks the user to choose one of them.
+func (b *BindOptions) selectAPIExport(candidates []apiExportCandidate) (*apiExportCandidate, error) {
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no APIExport found that you are allowed to bind")
+	}
+
+	out := printers.GetNewTabWriter(b.Out)
+	if _, err := fmt.Fprintf(out, "\tWORKSPACE\tAPIEXPORT\tRESOURCE SCHEMAS\n"); err != nil {
+		return nil, err
+	}
+	for i, c := range candidates {
+		if _, err := fmt.Fprintf(out, "%d\t%s\t%s\t%s\n", i+1, c.clusterName, c.export.Name, strings.Join(c.export.Spec.LatestResourceSchemas, ",")); err != nil {
+			return nil, err
+		}
+	}
+	if err := out.Flush(); err != nil {
+		return nil, err
+	}
+
+	answer, err := b.prompt(fmt.Sprintf("Select the APIExport to bind [1-%d]: ", len(candidates)))
+	if err != nil {
+		return nil, err
+	}
+	i, err := strconv.Atoi(answer)
+	if err != nil || i < 1 || i > len(candidates) {
+		return nil, fmt.Errorf("invalid selection %q, expected a number between 1 and %d", answer, len(candidates))
+	}
+	return &candidates[i-1], nil
+}
+
+// permissionClaims returns the claims of the export accepted or rejected by the user. If interactive,
+// the user is asked for every claim. Otherwise, the claims given by --accept-permission-claim are accepted
+// and the others are left open.
+func (b *BindOptions) permissionClaims(export *apisv1alpha1.APIExport, interactive bool) ([]apisv1alpha1.AcceptablePermissionClaim, error) {
+	accepted := sets.NewString(b.AcceptedPermissionClaims...)
+	found := sets.NewString()
+
+	var claims []apisv1alpha1.AcceptablePermissionClaim
+	for _, claim := range export.Spec.PermissionClaims {
+		name := claimName(claim)
+		switch {
+		case accepted.Has(name):
+			found.Insert(name)
+			claims = append(claims, apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: claim, State: apisv1alpha1.ClaimAccepted})
+		case interactive:
+			answer, err := b.prompt(fmt.Sprintf("APIExport %s claims access to %s. Accept? [y/N]: ", export.Name, name))
+			if err != nil {
+				return nil, err
+			}
+			state := apisv1alpha1.ClaimRejected
+			if strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes") {
+				state = apisv1alpha1.ClaimAccepted
+			}
+			claims = append(claims, apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: claim, State: state})
+		}
+	}
+
+	if unknown := accepted.Difference(found); unknown.Len() > 0 {
+		return nil, fmt.Errorf("APIExport %s does not claim %s", export.Name, strings.Join(unknown.List(), ", "))
+	}
+	return claims, nil
+}
+
+// claimName returns the group resource of the claim as <resource>.<group>, or <resource> for
+// core resources.
+func claimName(claim apisv1alpha1.PermissionClaim) string {
+	if claim.Group == "" {
+		return claim.Resource
+	}
+	return claim.Resource + "." + claim.Group
+}
+
+// prompt writes the question to the output and returns the trimmed line answered on the input.
+func (b *BindOptions) prompt(question string) (string, error) {
+	if b.in == nil {
+		b.in = bufio.NewReader(b.In)
+	}
+	if _, err := fmt.Fprint(b.Out, question); err != nil {
+		return "", err
+	}
+	line, err := b.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("error reading answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// canBind returns whether the user of config may bind the given APIExport.
+func canBind(ctx context.Context, config *rest.Config, clusterName logicalcluster.Name, apiExportName string) (bool, error) {
+	clusterConfig := rest.CopyConfig(config)
+	u, _, err := pluginhelpers.ParseClusterURL(config.Host)
+	if err != nil {
+		return false, err
+	}
+	u.Path = path.Join(u.Path, clusterName.Path())
+	clusterConfig.Host = u.String()
+
+	kubeClient, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		return false, err
+	}
+	review, err := kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:     "bind",
+				Group:    apisv1alpha1.SchemeGroupVersion.Group,
+				Resource: "apiexports",
+				Name:     apiExportName,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
 func newKCPClusterClient(config *rest.Config) (kcpclientset.ClusterInterface, error) {
 	clusterConfig := rest.CopyConfig(config)
 	u, err := url.Parse(config.Host)
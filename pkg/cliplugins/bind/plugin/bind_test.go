@@ -0,0 +1,147 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kcptesting "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/testing"
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1beta1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1beta1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	kcpfakeclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster/fake"
+)
+
+func TestBind(t *testing.T) {
+	configMaps := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true}
+	widgets := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"}, All: true, IdentityHash: "abc"}
+
+	newExport := func(cluster, name string, claims ...apisv1alpha1.PermissionClaim) *apisv1alpha1.APIExport {
+		return &apisv1alpha1.APIExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+			},
+			Spec: apisv1alpha1.APIExportSpec{PermissionClaims: claims},
+		}
+	}
+	objects := []runtime.Object{
+		newExport("root", "shared"),
+		newExport("root:services", "secret"),
+		newExport("root:services", "widgets", configMaps, widgets),
+		&tenancyv1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "services",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "root"},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		apiExportRef   string
+		acceptedClaims []string
+		input          string
+
+		wantErr       bool
+		wantReference apisv1alpha1.WorkspaceExportReference
+		wantClaims    []apisv1alpha1.AcceptablePermissionClaim
+	}{
+		"reference without claims": {
+			apiExportRef:  "root:services:widgets",
+			wantReference: apisv1alpha1.WorkspaceExportReference{Path: "root:services", ExportName: "widgets"},
+		},
+		"reference with accepted claim": {
+			apiExportRef:   "root:services:widgets",
+			acceptedClaims: []string{"widgets.example.io"},
+			wantReference:  apisv1alpha1.WorkspaceExportReference{Path: "root:services", ExportName: "widgets"},
+			wantClaims: []apisv1alpha1.AcceptablePermissionClaim{
+				{PermissionClaim: widgets, State: apisv1alpha1.ClaimAccepted},
+			},
+		},
+		"reference with unknown claim": {
+			apiExportRef:   "root:services:widgets",
+			acceptedClaims: []string{"secrets"},
+			wantErr:        true,
+		},
+		"interactive": {
+			input:         "2\ny\nn\n",
+			wantReference: apisv1alpha1.WorkspaceExportReference{Path: "root:services", ExportName: "widgets"},
+			wantClaims: []apisv1alpha1.AcceptablePermissionClaim{
+				{PermissionClaim: configMaps, State: apisv1alpha1.ClaimAccepted},
+				{PermissionClaim: widgets, State: apisv1alpha1.ClaimRejected},
+			},
+		},
+		"interactive, invalid selection": {
+			input:   "3\n",
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := kcpfakeclient.NewSimpleClientset(objects...)
+			client.PrependReactor("create", "apibindings", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				binding := action.(kcptesting.CreateAction).GetObject().(*apisv1alpha1.APIBinding)
+				conditions.MarkTrue(binding, apisv1alpha1.InitialBindingCompleted)
+				return false, nil, nil
+			})
+
+			config := clientcmdapi.Config{CurrentContext: "test",
+				Contexts:  map[string]*clientcmdapi.Context{"test": {Cluster: "test", AuthInfo: "test"}},
+				Clusters:  map[string]*clientcmdapi.Cluster{"test": {Server: "https://test/clusters/root:consumer"}},
+				AuthInfos: map[string]*clientcmdapi.AuthInfo{"test": {Token: "test"}},
+			}
+
+			streams, stdin, stdout, _ := genericclioptions.NewTestIOStreams()
+			stdin.WriteString(tc.input)
+
+			opts := NewBindOptions(streams)
+			opts.APIExportRef = tc.apiExportRef
+			opts.AcceptedPermissionClaims = tc.acceptedClaims
+			opts.BindWaitTimeout = time.Second
+			opts.ClientConfig = clientcmd.NewDefaultClientConfig(config, nil)
+			opts.kcpClusterClient = client
+			opts.canBind = func(ctx context.Context, clusterName logicalcluster.Name, apiExportName string) (bool, error) {
+				return apiExportName != "secret", nil
+			}
+
+			err := opts.Run(context.Background())
+			t.Logf("stdout:\n%s", stdout.String())
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			binding, err := client.Cluster(logicalcluster.New("root:consumer")).ApisV1alpha1().APIBindings().Get(context.Background(), tc.wantReference.ExportName, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tc.wantReference, *binding.Spec.Reference.Workspace)
+			require.Equal(t, tc.wantClaims, binding.Spec.PermissionClaims)
+			require.NotContains(t, stdout.String(), "secret")
+		})
+	}
+}
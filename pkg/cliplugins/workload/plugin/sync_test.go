@@ -67,6 +67,12 @@ rules:
   - "get"
   - "watch"
   - "list"
+- apiGroups:
+  - ""
+  resources:
+  - nodes
+  verbs:
+  - "list"
 - apiGroups:
   - ""
   resources:
@@ -296,6 +302,12 @@ rules:
   - "get"
   - "watch"
   - "list"
+- apiGroups:
+  - ""
+  resources:
+  - nodes
+  verbs:
+  - "list"
 - apiGroups:
   - ""
   resources:
@@ -181,9 +181,16 @@ func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
 
 	treeCmdOpts := plugin.NewTreeOptions(streams)
 	treeCmd := &cobra.Command{
-		Use:          "tree",
-		Short:        "Print the current workspace tree.",
-		Example:      "kcp workspace tree",
+		Use:   "tree [--full] [--show-bindings] [--use <query>]",
+		Short: "Print the current workspace tree.",
+		Example: `  # print the workspaces below the current workspace with their type, phase and shard
+  kcp workspace tree
+
+  # also print the APIBindings of every workspace
+  kcp workspace tree --show-bindings
+
+  # switch to the workspace below the current one matching "tm:dev", e.g. root:team:dev
+  kcp workspace tree --use tm:dev`,
 		SilenceUsage: true,
 		RunE: func(c *cobra.Command, args []string) error {
 			if len(args) != 0 {
@@ -0,0 +1,285 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/spf13/cobra"
+	"github.com/xlab/treeprint"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1beta1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1beta1"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
+	pluginhelpers "github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
+)
+
+// TreeOptions contains options for displaying the workspace tree
+type TreeOptions struct {
+	*base.Options
+
+	// Full shows the full workspace paths instead of the names.
+	Full bool
+	// ShowBindings shows the APIBindings of every workspace.
+	ShowBindings bool
+	// Use is a query for a workspace in the tree to switch to. It matches fuzzily against the
+	// workspace paths.
+	Use string
+
+	kcpClusterClient kcpclientset.ClusterInterface
+	useOptions       *UseWorkspaceOptions
+}
+
+// NewTreeOptions returns a new TreeOptions.
+func NewTreeOptions(streams genericclioptions.IOStreams) *TreeOptions {
+	return &TreeOptions{
+		Options:    base.NewOptions(streams),
+		useOptions: NewUseWorkspaceOptions(streams),
+	}
+}
+
+// BindFlags binds fields to cmd's flagset.
+func (o *TreeOptions) BindFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	cmd.Flags().BoolVarP(&o.Full, "full", "f", o.Full, "Show full workspaces names")
+	cmd.Flags().BoolVar(&o.ShowBindings, "show-bindings", o.ShowBindings, "Show the APIBindings of every workspace")
+	cmd.Flags().StringVar(&o.Use, "use", o.Use, "Switch to the workspace in the tree best matching the given query, e.g. 'tm:dev' for root:team:dev")
+}
+
+// Complete ensures all dynamically populated fields are initialized.
+func (o *TreeOptions) Complete() error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	kcpClusterClient, err := newKCPClusterClient(o.ClientConfig)
+	if err != nil {
+		return err
+	}
+	o.kcpClusterClient = kcpClusterClient
+
+	if o.Use != "" {
+		o.useOptions.Options = o.Options
+		if err := o.useOptions.Complete(nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run outputs the workspace tree below the current workspace, or switches to the workspace matching
+// the --use query.
+func (o *TreeOptions) Run(ctx context.Context) error {
+	config, err := o.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	_, currentClusterName, err := pluginhelpers.ParseClusterURL(config.Host)
+	if err != nil {
+		return fmt.Errorf("current config context URL %q does not point to workspace", config.Host)
+	}
+
+	root := &workspaceNode{clusterName: currentClusterName}
+	if err := o.populate(ctx, root); err != nil {
+		return err
+	}
+
+	if o.Use != "" {
+		clusterName, err := findWorkspace(root, o.Use)
+		if err != nil {
+			return err
+		}
+		o.useOptions.Name = clusterName.String()
+		return o.useOptions.Run(ctx)
+	}
+
+	tree := treeprint.New()
+	o.render(tree.AddBranch(o.label(root)), root)
+	_, err = fmt.Fprintln(o.Out, tree.String())
+	return err
+}
+
+// workspaceNode is a workspace in the tree.
+type workspaceNode struct {
+	clusterName logicalcluster.Name
+	// workspace is nil for the root of the tree.
+	workspace *tenancyv1beta1.Workspace
+	// shard is the shard the workspace is scheduled to, if visible to the user.
+	shard    string
+	bindings []apisv1alpha1.APIBinding
+	children []*workspaceNode
+}
+
+// populate recursively adds the child workspaces, and optionally the APIBindings, to node.
+func (o *TreeOptions) populate(ctx context.Context, node *workspaceNode) error {
+	if o.ShowBindings {
+		bindings, err := o.kcpClusterClient.Cluster(node.clusterName).ApisV1alpha1().APIBindings().List(ctx, metav1.ListOptions{})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+			return err
+		}
+		if err == nil {
+			node.bindings = bindings.Items
+			sort.Slice(node.bindings, func(i, j int) bool {
+				return node.bindings[i].Name < node.bindings[j].Name
+			})
+		}
+	}
+
+	workspaces, err := o.kcpClusterClient.Cluster(node.clusterName).TenancyV1beta1().Workspaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+			return nil
+		}
+		return err
+	}
+
+	// The shard is only part of the ClusterWorkspaces, which not every user may list.
+	shards := map[string]string{}
+	if clusterWorkspaces, err := o.kcpClusterClient.Cluster(node.clusterName).TenancyV1alpha1().ClusterWorkspaces().List(ctx, metav1.ListOptions{}); err == nil {
+		for _, cws := range clusterWorkspaces.Items {
+			shards[cws.Name] = cws.Status.Location.Current
+		}
+	}
+
+	sort.Slice(workspaces.Items, func(i, j int) bool {
+		return workspaces.Items[i].Name < workspaces.Items[j].Name
+	})
+	for i := range workspaces.Items {
+		ws := &workspaces.Items[i]
+		child := &workspaceNode{
+			clusterName: node.clusterName.Join(ws.Name),
+			workspace:   ws,
+			shard:       shards[ws.Name],
+		}
+		if err := o.populate(ctx, child); err != nil {
+			return err
+		}
+		node.children = append(node.children, child)
+	}
+	return nil
+}
+
+func (o *TreeOptions) render(tree treeprint.Tree, node *workspaceNode) {
+	for _, binding := range node.bindings {
+		export := "<unknown>"
+		if ref := binding.Spec.Reference.Workspace; ref != nil {
+			export = ref.Path + ":" + ref.ExportName
+			if ref.Path == "" {
+				export = ref.ExportName
+			}
+		}
+		tree.AddMetaNode("APIBinding", fmt.Sprintf("%s -> %s (%s)", binding.Name, export, binding.Status.Phase))
+	}
+	for _, child := range node.children {
+		o.render(tree.AddBranch(o.label(child)), child)
+	}
+}
+
+// label returns the name of the workspace of node, with its type, phase and shard, e.g.
+// "dev (root:universal, Ready, shard root)".
+func (o *TreeOptions) label(node *workspaceNode) string {
+	name := node.clusterName.Base()
+	if o.Full {
+		name = node.clusterName.String()
+	}
+	if node.workspace == nil {
+		return name
+	}
+
+	details := []string{node.workspace.Spec.Type.String(), string(node.workspace.Status.Phase)}
+	if node.shard != "" {
+		details = append(details, "shard "+node.shard)
+	}
+	return fmt.Sprintf("%s (%s)", name, strings.Join(details, ", "))
+}
+
+// findWorkspace returns the workspace below root best matching query. Matches are ranked by
+// workspaceMatch. It fails if no workspace matches, or if several match equally well.
+func findWorkspace(root *workspaceNode, query string) (logicalcluster.Name, error) {
+	best := -1
+	var matches []logicalcluster.Name
+	var visit func(node *workspaceNode)
+	visit = func(node *workspaceNode) {
+		for _, child := range node.children {
+			if rank, ok := workspaceMatch(child.clusterName, query); ok {
+				switch {
+				case best == -1 || rank < best:
+					best, matches = rank, []logicalcluster.Name{child.clusterName}
+				case rank == best:
+					matches = append(matches, child.clusterName)
+				}
+			}
+			visit(child)
+		}
+	}
+	visit(root)
+
+	switch len(matches) {
+	case 0:
+		return logicalcluster.Name{}, fmt.Errorf("no workspace below %s matches %q", root.clusterName, query)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, 0, len(matches))
+		for _, m := range matches {
+			names = append(names, m.String())
+		}
+		return logicalcluster.Name{}, fmt.Errorf("%q matches multiple workspaces: %s", query, strings.Join(names, ", "))
+	}
+}
+
+// workspaceMatch returns whether query matches the workspace path, and how well, lower being better:
+//
+//  0. the workspace name equals query,
+//  1. the path ends with query, e.g. "team:dev" for root:team:dev,
+//  2. the workspace name starts with query,
+//  3. the path contains query,
+//  4. the characters of query appear in order in the path, e.g. "tm:dv" for root:team:dev.
+func workspaceMatch(clusterName logicalcluster.Name, query string) (int, bool) {
+	path, name := clusterName.String(), clusterName.Base()
+	switch {
+	case name == query:
+		return 0, true
+	case strings.HasSuffix(path, ":"+query):
+		return 1, true
+	case strings.HasPrefix(name, query):
+		return 2, true
+	case strings.Contains(path, query):
+		return 3, true
+	}
+
+	rest := query
+	for _, r := range path {
+		if rest == "" {
+			break
+		}
+		if strings.HasPrefix(rest, string(r)) {
+			rest = rest[len(string(r)):]
+		}
+	}
+	return 4, rest == ""
+}
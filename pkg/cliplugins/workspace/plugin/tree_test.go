@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancyv1beta1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1beta1"
+	kcpfakeclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster/fake"
+)
+
+func TestTree(t *testing.T) {
+	newWorkspace := func(cluster, name string, phase tenancyv1alpha1.ClusterWorkspacePhaseType) *tenancyv1beta1.Workspace {
+		return &tenancyv1beta1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+			},
+			Spec: tenancyv1beta1.WorkspaceSpec{
+				Type: tenancyv1alpha1.ClusterWorkspaceTypeReference{Name: "universal", Path: "root"},
+			},
+			Status: tenancyv1beta1.WorkspaceStatus{Phase: phase},
+		}
+	}
+	objects := []runtime.Object{
+		newWorkspace("root:team", "dev", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+		newWorkspace("root:team", "prod", tenancyv1alpha1.ClusterWorkspacePhaseInitializing),
+		newWorkspace("root:team:dev", "frontend", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+		&tenancyv1alpha1.ClusterWorkspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "dev",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "root:team"},
+			},
+			Status: tenancyv1alpha1.ClusterWorkspaceStatus{Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "shard-1"}},
+		},
+		&apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "kubernetes",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "root:team:dev"},
+			},
+			Spec: apisv1alpha1.APIBindingSpec{
+				Reference: apisv1alpha1.ExportReference{
+					Workspace: &apisv1alpha1.WorkspaceExportReference{Path: "root:compute", ExportName: "kubernetes"},
+				},
+			},
+			Status: apisv1alpha1.APIBindingStatus{Phase: apisv1alpha1.APIBindingPhaseBound},
+		},
+	}
+
+	config := clientcmdapi.Config{CurrentContext: "workspace.kcp.dev/current",
+		Contexts:  map[string]*clientcmdapi.Context{"workspace.kcp.dev/current": {Cluster: "workspace.kcp.dev/current", AuthInfo: "test"}},
+		Clusters:  map[string]*clientcmdapi.Cluster{"workspace.kcp.dev/current": {Server: "https://test/clusters/root:team"}},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{"test": {Token: "test"}},
+	}
+
+	streams, _, stdout, _ := genericclioptions.NewTestIOStreams()
+	opts := NewTreeOptions(streams)
+	opts.ShowBindings = true
+	opts.kcpClusterClient = kcpfakeclient.NewSimpleClientset(objects...)
+	opts.ClientConfig = clientcmd.NewDefaultClientConfig(config, nil)
+
+	err := opts.Run(context.Background())
+	require.NoError(t, err)
+	t.Logf("stdout:\n%s", stdout.String())
+
+	require.Contains(t, stdout.String(), "team\n")
+	require.Contains(t, stdout.String(), "dev (root:universal, Ready, shard shard-1)")
+	require.Contains(t, stdout.String(), "prod (root:universal, Initializing)")
+	require.Contains(t, stdout.String(), "frontend (root:universal, Ready)")
+	require.Contains(t, stdout.String(), "kubernetes -> root:compute:kubernetes (Bound)")
+}
+
+func TestFindWorkspace(t *testing.T) {
+	node := func(path string, children ...*workspaceNode) *workspaceNode {
+		return &workspaceNode{clusterName: logicalcluster.New(path), children: children}
+	}
+	root := node("root",
+		node("root:team",
+			node("root:team:dev",
+				node("root:team:dev:frontend"),
+			),
+			node("root:team:prod"),
+		),
+		node("root:other",
+			node("root:other:dev"),
+		),
+	)
+
+	tests := map[string]struct {
+		query   string
+		want    string
+		wantErr bool
+	}{
+		"name":                {query: "prod", want: "root:team:prod"},
+		"ambiguous name":      {query: "dev", wantErr: true},
+		"path suffix":         {query: "team:dev", want: "root:team:dev"},
+		"name prefix":         {query: "front", want: "root:team:dev:frontend"},
+		"path substring":      {query: "her:d", want: "root:other:dev"},
+		"characters in order": {query: "tm:prd", want: "root:team:prod"},
+		"no match":            {query: "staging", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := findWorkspace(root, tc.query)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got.String())
+		})
+	}
+}
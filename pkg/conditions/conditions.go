@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conditions is the condition library used by kcp reconcilers. It offers the API of the third_party
+// conditions utilities, and records transition metrics for conditions of successfully updated objects.
+package conditions
+
+import (
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+type (
+	Getter        = conditions.Getter
+	Setter        = conditions.Setter
+	MergeOption   = conditions.MergeOption
+	MirrorOptions = conditions.MirrorOptions
+	Patch         = conditions.Patch
+	ApplyOption   = conditions.ApplyOption
+)
+
+var (
+	Get                   = conditions.Get
+	Has                   = conditions.Has
+	IsTrue                = conditions.IsTrue
+	IsFalse               = conditions.IsFalse
+	IsUnknown             = conditions.IsUnknown
+	GetReason             = conditions.GetReason
+	GetMessage            = conditions.GetMessage
+	GetSeverity           = conditions.GetSeverity
+	GetLastTransitionTime = conditions.GetLastTransitionTime
+
+	TrueCondition    = conditions.TrueCondition
+	FalseCondition   = conditions.FalseCondition
+	UnknownCondition = conditions.UnknownCondition
+
+	WithConditions        = conditions.WithConditions
+	WithStepCounter       = conditions.WithStepCounter
+	WithStepCounterIf     = conditions.WithStepCounterIf
+	WithStepCounterIfOnly = conditions.WithStepCounterIfOnly
+	AddSourceRef          = conditions.AddSourceRef
+	WithFallbackValue     = conditions.WithFallbackValue
+	NewPatch              = conditions.NewPatch
+	WithOwnedConditions   = conditions.WithOwnedConditions
+	WithForceOverwrite    = conditions.WithForceOverwrite
+
+	Set          = conditions.Set
+	MarkTrue     = conditions.MarkTrue
+	MarkUnknown  = conditions.MarkUnknown
+	MarkFalse    = conditions.MarkFalse
+	SetSummary   = conditions.SetSummary
+	SetMirror    = conditions.SetMirror
+	SetAggregate = conditions.SetAggregate
+	Delete       = conditions.Delete
+)
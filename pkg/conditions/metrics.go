@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"reflect"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	conditionsapi "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+var (
+	// conditionTransitions counts status changes of conditions, e.g. to alert on SyncTargets flapping
+	// between Ready=True and Ready=False.
+	conditionTransitions = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "kcp_condition_transitions_total",
+			Help:           "Number of condition status transitions by resource kind, condition type and new status.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"resource", "condition", "status"},
+	)
+
+	// conditionTimeToTrue observes how long a condition took to become True, measured from its previous
+	// transition, or from the creation of the object if the condition did not exist before. E.g. Ready of
+	// ClusterWorkspace is the time until a workspace is ready, Ready of SyncTarget the time until a
+	// SyncTarget is ready, and InitialBindingCompleted of APIBinding the time until an APIBinding is bound.
+	conditionTimeToTrue = compbasemetrics.NewHistogramVec(
+		&compbasemetrics.HistogramOpts{
+			Name:           "kcp_condition_time_to_true_seconds",
+			Help:           "Time in seconds for a condition to become True by resource kind and condition type.",
+			Buckets:        []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800, 3600},
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"resource", "condition"},
+	)
+)
+
+var registerMetrics sync.Once
+
+// Register metrics.
+func Register() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(conditionTransitions)
+		legacyregistry.MustRegister(conditionTimeToTrue)
+	})
+}
+
+func init() {
+	Register()
+}
+
+// RecordTransitions records metrics for the conditions whose status differs between before and after.
+// It must only be called with objects as they were stored before and after a successful update, so that
+// conflicting or failing updates, which the reconciler retries, are not counted.
+func RecordTransitions(before, after Getter) {
+	if isNil(before) || isNil(after) {
+		return
+	}
+	resource := resourceName(after)
+	conditions := after.GetConditions()
+	for i := range conditions {
+		recordTransition(resource, after, Get(before, conditions[i].Type), &conditions[i])
+	}
+}
+
+func recordTransition(resource string, obj Getter, before, after *conditionsapi.Condition) {
+	if before != nil && before.Status == after.Status {
+		return
+	}
+
+	conditionTransitions.WithLabelValues(resource, string(after.Type), string(after.Status)).Inc()
+
+	if after.Status != corev1.ConditionTrue {
+		return
+	}
+	var since metav1.Time
+	if before != nil {
+		since = before.LastTransitionTime
+	} else if o, ok := obj.(metav1.Object); ok {
+		since = o.GetCreationTimestamp()
+	}
+	if since.IsZero() || after.LastTransitionTime.Before(&since) {
+		return
+	}
+	conditionTimeToTrue.WithLabelValues(resource, string(after.Type)).Observe(after.LastTransitionTime.Sub(since.Time).Seconds())
+}
+
+func isNil(obj interface{}) bool {
+	if obj == nil {
+		return true
+	}
+	v := reflect.ValueOf(obj)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// resourceName returns the kind of the given object, derived from its Go type as the TypeMeta is
+// usually not set on objects from informers.
+func resourceName(obj interface{}) string {
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
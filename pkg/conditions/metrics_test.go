@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/metrics/testutil"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	conditionsapi "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+func TestRecordTransitions(t *testing.T) {
+	conditionTransitions.Reset()
+	conditionTimeToTrue.Reset()
+
+	transitions := func(status string) float64 {
+		v, err := testutil.GetCounterMetricValue(conditionTransitions.WithLabelValues("APIBinding", string(apisv1alpha1.InitialBindingCompleted), status))
+		require.NoError(t, err)
+		return v
+	}
+	timeToTrue := func() float64 {
+		v, err := testutil.GetHistogramMetricValue(conditionTimeToTrue.WithLabelValues("APIBinding", string(apisv1alpha1.InitialBindingCompleted)))
+		require.NoError(t, err)
+		return v
+	}
+
+	stored := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute))},
+	}
+	update := func(mutate func(binding *apisv1alpha1.APIBinding)) {
+		updated := stored.DeepCopy()
+		mutate(updated)
+		RecordTransitions(stored, updated)
+		stored = updated
+	}
+
+	// setting a condition alone is not recorded, only a successful update
+	MarkFalse(stored.DeepCopy(), apisv1alpha1.InitialBindingCompleted, "Waiting", conditionsapi.ConditionSeverityInfo, "waiting")
+	require.Equal(t, float64(0), transitions("False"))
+
+	update(func(binding *apisv1alpha1.APIBinding) {
+		MarkFalse(binding, apisv1alpha1.InitialBindingCompleted, "Waiting", conditionsapi.ConditionSeverityInfo, "waiting")
+	})
+	require.Equal(t, float64(1), transitions("False"))
+
+	// same status, no transition
+	update(func(binding *apisv1alpha1.APIBinding) {
+		MarkFalse(binding, apisv1alpha1.InitialBindingCompleted, "StillWaiting", conditionsapi.ConditionSeverityInfo, "still waiting")
+	})
+	require.Equal(t, float64(1), transitions("False"))
+	require.Equal(t, float64(0), timeToTrue())
+
+	// move the previous transition into the past to observe a time to true
+	Get(stored, apisv1alpha1.InitialBindingCompleted).LastTransitionTime = metav1.NewTime(time.Now().Add(-30 * time.Second))
+	update(func(binding *apisv1alpha1.APIBinding) {
+		MarkTrue(binding, apisv1alpha1.InitialBindingCompleted)
+	})
+	require.Equal(t, float64(1), transitions("True"))
+	require.InDelta(t, 30, timeToTrue(), 2)
+
+	update(func(binding *apisv1alpha1.APIBinding) {
+		MarkTrue(binding, apisv1alpha1.InitialBindingCompleted)
+	})
+	require.Equal(t, float64(1), transitions("True"))
+}
+
+func TestRecordTransitionsFromCreation(t *testing.T) {
+	conditionTimeToTrue.Reset()
+
+	before := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute))},
+	}
+	after := before.DeepCopy()
+	MarkTrue(after, apisv1alpha1.InitialBindingCompleted)
+	RecordTransitions(before, after)
+
+	v, err := testutil.GetHistogramMetricValue(conditionTimeToTrue.WithLabelValues("APIBinding", string(apisv1alpha1.InitialBindingCompleted)))
+	require.NoError(t, err)
+	require.InDelta(t, 60, v, 2)
+}
+
+func TestRecordTransitionsNil(t *testing.T) {
+	conditionTransitions.Reset()
+
+	var after *apisv1alpha1.APIBinding
+	RecordTransitions(&apisv1alpha1.APIBinding{}, after)
+
+	v, err := testutil.GetCounterMetricValue(conditionTransitions.WithLabelValues("APIBinding", string(apisv1alpha1.InitialBindingCompleted), "True"))
+	require.NoError(t, err)
+	require.Equal(t, float64(0), v)
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package envtest starts an in-process kcp server for integration tests of API providers and controllers
+// that are developed outside of the kcp repository. It does not require a kcp binary.
+//
+// A typical test looks like this:
+//
+//	func TestMyController(t *testing.T) {
+//		server := envtest.NewServer(t, envtest.WithVirtualWorkspaces(false))
+//		workspace := server.NewWorkspace(t, tenancyv1alpha1.RootCluster)
+//
+//		_, err := server.Clients().Kcp.Cluster(workspace).ApisV1alpha1().APIExports().Create(ctx, export, metav1.CreateOptions{})
+//		require.NoError(t, err)
+//		...
+//	}
+//
+// Every server has its own root directory, embedded etcd and ports, so tests using this package can run
+// in parallel.
+package envtest
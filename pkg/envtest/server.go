@@ -0,0 +1,349 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kcp-dev/kcp/pkg/client/clusteraware"
+	"github.com/kcp-dev/kcp/pkg/embeddedetcd"
+	"github.com/kcp-dev/kcp/pkg/server"
+	"github.com/kcp-dev/kcp/pkg/server/options"
+)
+
+// Option configures a Server before it is started.
+type Option func(o *serverOptions)
+
+type serverOptions struct {
+	rootDirectory     string
+	virtualWorkspaces bool
+	readyTimeout      time.Duration
+	args              []string
+}
+
+// WithRootDirectory sets the directory the server keeps its data, certificates and admin.kubeconfig in.
+// By default, a temporary directory is used.
+func WithRootDirectory(dir string) Option {
+	return func(o *serverOptions) {
+		o.rootDirectory = dir
+	}
+}
+
+// WithVirtualWorkspaces enables or disables the virtual workspaces served by the server. They are
+// enabled by default.
+func WithVirtualWorkspaces(enabled bool) Option {
+	return func(o *serverOptions) {
+		o.virtualWorkspaces = enabled
+	}
+}
+
+// WithReadyTimeout sets how long to wait for the server to become ready. The default is one minute.
+func WithReadyTimeout(timeout time.Duration) Option {
+	return func(o *serverOptions) {
+		o.readyTimeout = timeout
+	}
+}
+
+// WithArgs adds "kcp start" flags, e.g. "--feature-gates=...". They are applied after the flags set by
+// this package and hence take precedence.
+func WithArgs(args ...string) Option {
+	return func(o *serverOptions) {
+		o.args = append(o.args, args...)
+	}
+}
+
+// Server is a kcp server running in the current process.
+type Server struct {
+	rootDirectory  string
+	kubeconfigPath string
+
+	config  *rest.Config
+	clients *clusteraware.Clients
+
+	cancel  context.CancelFunc
+	stopped chan struct{}
+
+	lock sync.Mutex
+	err  error
+}
+
+// NewServer starts a kcp server and waits for it to be ready. The server is stopped when the test
+// and all its subtests have completed.
+func NewServer(t testing.TB, opts ...Option) *Server {
+	t.Helper()
+
+	o := []Option{WithRootDirectory(t.TempDir())}
+	s, err := StartServer(context.Background(), append(o, opts...)...)
+	if err != nil {
+		t.Fatalf("failed to start kcp: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := s.Stop(); err != nil {
+			t.Errorf("kcp failed: %v", err)
+		}
+	})
+
+	return s
+}
+
+// StartServer starts a kcp server and waits for it to be ready. The server runs until ctx is done or
+// Stop is called. Without WithRootDirectory, the root directory is a temporary directory that is removed
+// on Stop.
+func StartServer(ctx context.Context, opts ...Option) (*Server, error) {
+	o := &serverOptions{
+		virtualWorkspaces: true,
+		readyTimeout:      time.Minute,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	removeRootDirectory := false
+	if o.rootDirectory == "" {
+		dir, err := os.MkdirTemp("", "kcp-envtest-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create root directory: %w", err)
+		}
+		o.rootDirectory = dir
+		removeRootDirectory = true
+	}
+
+	args, err := serverArgs(o)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Server{
+		rootDirectory:  o.rootDirectory,
+		kubeconfigPath: filepath.Join(o.rootDirectory, "admin.kubeconfig"),
+		cancel:         cancel,
+		stopped:        make(chan struct{}),
+	}
+	stopped := func() {
+		if removeRootDirectory {
+			os.RemoveAll(o.rootDirectory) //nolint:errcheck
+		}
+		close(s.stopped)
+	}
+
+	if err := s.run(ctx, o.rootDirectory, args, stopped); err != nil {
+		cancel()
+		stopped()
+		return nil, err
+	}
+
+	if err := s.waitForReady(ctx, o.readyTimeout); err != nil {
+		s.Stop() //nolint:errcheck
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// serverArgs returns the "kcp start" flags for the given options. Each server gets its own free ports
+// for serving and embedded etcd.
+func serverArgs(o *serverOptions) ([]string, error) {
+	var ports []string
+	for i := 0; i < 3; i++ {
+		port, err := freePort()
+		if err != nil {
+			return nil, err
+		}
+		ports = append(ports, port)
+	}
+
+	args := []string{
+		"--root-directory=" + o.rootDirectory,
+		"--secure-port=" + ports[0],
+		"--embedded-etcd-client-port=" + ports[1],
+		"--embedded-etcd-peer-port=" + ports[2],
+		"--embedded-etcd-wal-size-bytes=" + strconv.Itoa(5*1000), // 5KB
+		"--kubeconfig-path=" + filepath.Join(o.rootDirectory, "admin.kubeconfig"),
+		"--run-virtual-workspaces=" + strconv.FormatBool(o.virtualWorkspaces),
+	}
+	return append(args, o.args...), nil
+}
+
+// freePort asks the kernel for a free port. There is a small window in which another process can take
+// the port before the server binds it.
+func freePort() (string, error) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return "", fmt.Errorf("could not listen on free port: %w", err)
+	}
+	defer l.Close()
+	return strconv.Itoa(l.Addr().(*net.TCPAddr).Port), nil
+}
+
+func (s *Server) run(ctx context.Context, rootDir string, args []string, stopped func()) error {
+	serverOptions := options.NewOptions(rootDir)
+	all := pflag.NewFlagSet("kcp", pflag.ContinueOnError)
+	for _, fs := range serverOptions.Flags().FlagSets {
+		all.AddFlagSet(fs)
+	}
+	if err := all.Parse(args); err != nil {
+		return err
+	}
+
+	completed, err := serverOptions.Complete()
+	if err != nil {
+		return err
+	}
+	if errs := completed.Validate(); len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+
+	config, err := server.NewConfig(completed)
+	if err != nil {
+		return err
+	}
+	completedConfig, err := config.Complete()
+	if err != nil {
+		return err
+	}
+
+	// the etcd server must be up before NewServer because storage decorators access it right away
+	if completedConfig.EmbeddedEtcd.Config != nil {
+		if err := embeddedetcd.NewServer(completedConfig.EmbeddedEtcd).Run(ctx); err != nil {
+			return err
+		}
+	}
+
+	kcpServer, err := server.NewServer(completedConfig)
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer stopped()
+
+		if err := kcpServer.Run(ctx); err != nil && ctx.Err() == nil {
+			s.lock.Lock()
+			s.err = err
+			s.lock.Unlock()
+		}
+	}()
+
+	return nil
+}
+
+// waitForReady waits for the admin.kubeconfig to be written and for /livez and /readyz to succeed.
+func (s *Server) waitForReady(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastError error
+	if err := wait.PollImmediateUntilWithContext(ctx, 100*time.Millisecond, func(ctx context.Context) (bool, error) {
+		if err := s.Err(); err != nil {
+			return false, err
+		}
+		// the server writes the admin.kubeconfig during startup
+		rawConfig, err := clientcmd.LoadFromFile(s.kubeconfigPath)
+		if err != nil {
+			lastError = err
+			return false, nil
+		}
+		config, err := clientcmd.NewNonInteractiveClientConfig(*rawConfig, "base", nil, nil).ClientConfig()
+		if err != nil {
+			lastError = err
+			return false, nil
+		}
+		config.QPS = -1
+		s.config = config
+		return true, nil
+	}); err != nil {
+		return fmt.Errorf("failed to load admin kubeconfig %s: %w", s.kubeconfigPath, utilerrors.NewAggregate([]error{err, lastError}))
+	}
+
+	healthConfig := rest.CopyConfig(s.config)
+	healthConfig.NegotiatedSerializer = kubernetesscheme.Codecs.WithoutConversion()
+	client, err := rest.UnversionedRESTClientFor(healthConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create unversioned client: %w", err)
+	}
+	for _, endpoint := range []string{"/livez", "/readyz"} {
+		if err := wait.PollImmediateUntilWithContext(ctx, 100*time.Millisecond, func(ctx context.Context) (bool, error) {
+			if err := s.Err(); err != nil {
+				return false, err
+			}
+			_, err := client.Get().RequestURI(endpoint).Do(ctx).Raw()
+			lastError = err
+			return err == nil, nil
+		}); err != nil {
+			return fmt.Errorf("failed waiting for %s: %w", endpoint, utilerrors.NewAggregate([]error{err, lastError}))
+		}
+	}
+
+	clients, err := clusteraware.NewClients(s.config)
+	if err != nil {
+		return err
+	}
+	s.clients = clients
+
+	return nil
+}
+
+// Stop stops the server and waits for it to shut down. It returns the error the server failed with, if any.
+func (s *Server) Stop() error {
+	s.cancel()
+	<-s.stopped
+	return s.Err()
+}
+
+// Err returns the error the server failed with while running, if any.
+func (s *Server) Err() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.err
+}
+
+// RootDirectory returns the root directory of the server.
+func (s *Server) RootDirectory() string {
+	return s.rootDirectory
+}
+
+// KubeconfigPath returns the path of the admin.kubeconfig written by the server.
+func (s *Server) KubeconfigPath() string {
+	return s.kubeconfigPath
+}
+
+// Config returns a copy of the admin client config of the server, without a /clusters/<name> suffix.
+// Use it with cluster-aware clients. Client-side throttling is disabled (QPS=-1).
+func (s *Server) Config() *rest.Config {
+	return rest.CopyConfig(s.config)
+}
+
+// Clients returns the cluster-aware admin clients of the server.
+func (s *Server) Clients() *clusteraware.Clients {
+	return s.clients
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		want    []string
+	}{
+		{
+			name: "defaults",
+			want: []string{
+				"--root-directory=/tmp/kcp",
+				"--kubeconfig-path=/tmp/kcp/admin.kubeconfig",
+				"--run-virtual-workspaces=true",
+			},
+		},
+		{
+			name:    "virtual workspaces disabled",
+			options: []Option{WithVirtualWorkspaces(false)},
+			want:    []string{"--run-virtual-workspaces=false"},
+		},
+		{
+			name:    "extra args come last",
+			options: []Option{WithArgs("--run-virtual-workspaces=true", "-v=4")},
+			want:    []string{"--run-virtual-workspaces=true", "-v=4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &serverOptions{rootDirectory: "/tmp/kcp", virtualWorkspaces: true}
+			for _, opt := range tt.options {
+				opt(o)
+			}
+
+			args, err := serverArgs(o)
+			require.NoError(t, err)
+			require.Subset(t, args, tt.want)
+			if len(o.args) > 0 {
+				require.Equal(t, o.args, args[len(args)-len(o.args):])
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+// fakeSyncerHeartbeatInterval is the interval the fake syncer heartbeats in. It is well below the
+// default --sync-target-heartbeat-threshold of one minute.
+const fakeSyncerHeartbeatInterval = 5 * time.Second
+
+// FakeSyncer pretends to be the syncer of a SyncTarget by heartbeating, such that the SyncTarget
+// becomes ready and workloads can be scheduled onto it. It does not sync any resources to a physical
+// cluster, so tests can only observe the upstream side of scheduling.
+type FakeSyncer struct {
+	server         *Server
+	clusterName    logicalcluster.Name
+	syncTargetName string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartFakeSyncer creates a SyncTarget with the given name in the given workspace, unless it already
+// exists, and heartbeats for it until ctx is done or Stop is called.
+func (s *Server) StartFakeSyncer(ctx context.Context, clusterName logicalcluster.Name, syncTargetName string) (*FakeSyncer, error) {
+	syncTargets := s.clients.Kcp.Cluster(clusterName).WorkloadV1alpha1().SyncTargets()
+	syncTarget, err := syncTargets.Create(ctx, &workloadv1alpha1.SyncTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: syncTargetName,
+		},
+	}, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		syncTarget, err = syncTargets.Get(ctx, syncTargetName, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SyncTarget %s|%s: %w", clusterName, syncTargetName, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	f := &FakeSyncer{
+		server:         s,
+		clusterName:    clusterName,
+		syncTargetName: syncTargetName,
+		cancel:         cancel,
+		done:           make(chan struct{}),
+	}
+
+	go func() {
+		defer close(f.done)
+
+		// like the real syncer, the heartbeat fails if the SyncTarget has been recreated
+		wait.UntilWithContext(ctx, func(ctx context.Context) {
+			patchBytes := []byte(fmt.Sprintf(`[{"op":"test","path":"/metadata/uid","value":%q},{"op":"replace","path":"/status/lastSyncerHeartbeatTime","value":%q}]`, syncTarget.UID, time.Now().Format(time.RFC3339)))
+			syncTargets.Patch(ctx, syncTargetName, types.JSONPatchType, patchBytes, metav1.PatchOptions{}, "status") //nolint:errcheck
+		}, fakeSyncerHeartbeatInterval)
+	}()
+
+	return f, nil
+}
+
+// NewFakeSyncer starts a fake syncer for a new SyncTarget in the given workspace and waits for the
+// SyncTarget to be ready. The fake syncer is stopped when the test and all its subtests have completed.
+func (s *Server) NewFakeSyncer(t testing.TB, clusterName logicalcluster.Name, syncTargetName string) *FakeSyncer {
+	t.Helper()
+
+	f, err := s.StartFakeSyncer(context.Background(), clusterName, syncTargetName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(f.Stop)
+
+	ctx, cancel := context.WithTimeout(context.Background(), wait.ForeverTestTimeout)
+	defer cancel()
+	if err := f.WaitForReady(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+// SyncTargetName returns the name of the SyncTarget of the fake syncer.
+func (f *FakeSyncer) SyncTargetName() string {
+	return f.syncTargetName
+}
+
+// ClusterName returns the logical cluster of the SyncTarget of the fake syncer.
+func (f *FakeSyncer) ClusterName() logicalcluster.Name {
+	return f.clusterName
+}
+
+// WaitForReady waits for the SyncTarget to have the Ready condition.
+func (f *FakeSyncer) WaitForReady(ctx context.Context) error {
+	syncTargets := f.server.clients.Kcp.Cluster(f.clusterName).WorkloadV1alpha1().SyncTargets()
+	if err := wait.PollImmediateUntilWithContext(ctx, 100*time.Millisecond, func(ctx context.Context) (bool, error) {
+		syncTarget, err := syncTargets.Get(ctx, f.syncTargetName, metav1.GetOptions{})
+		if err != nil {
+			return false, nil //nolint:nilerr
+		}
+		return conditions.IsTrue(syncTarget, conditionsv1alpha1.ReadyCondition), nil
+	}); err != nil {
+		return fmt.Errorf("failed to wait for SyncTarget %s|%s to become ready: %w", f.clusterName, f.syncTargetName, err)
+	}
+	return nil
+}
+
+// Stop stops heartbeating. The SyncTarget is not deleted and becomes not ready after the heartbeat threshold.
+func (f *FakeSyncer) Stop() {
+	f.cancel()
+	<-f.done
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// WorkspaceOption configures a ClusterWorkspace before it is created.
+type WorkspaceOption func(ws *tenancyv1alpha1.ClusterWorkspace)
+
+// WithWorkspaceName sets the name of the workspace. By default, a name is generated.
+func WithWorkspaceName(name string) WorkspaceOption {
+	return func(ws *tenancyv1alpha1.ClusterWorkspace) {
+		ws.Name = name
+		ws.GenerateName = ""
+	}
+}
+
+// WithWorkspaceType sets the type of the workspace. By default, root:universal is used.
+func WithWorkspaceType(path logicalcluster.Name, name tenancyv1alpha1.ClusterWorkspaceTypeName) WorkspaceOption {
+	return func(ws *tenancyv1alpha1.ClusterWorkspace) {
+		ws.Spec.Type = tenancyv1alpha1.ClusterWorkspaceTypeReference{
+			Name: name,
+			Path: path.String(),
+		}
+	}
+}
+
+// CreateWorkspace creates a workspace in the parent workspace and waits for it to be ready. It returns
+// the logical cluster name of the new workspace.
+func (s *Server) CreateWorkspace(ctx context.Context, parent logicalcluster.Name, opts ...WorkspaceOption) (logicalcluster.Name, error) {
+	tmpl := &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "envtest-",
+		},
+		Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+			Type: tenancyv1alpha1.ClusterWorkspaceTypeReference{
+				Name: "universal",
+				Path: "root",
+			},
+		},
+	}
+	for _, opt := range opts {
+		opt(tmpl)
+	}
+
+	workspaces := s.clients.Kcp.Cluster(parent).TenancyV1alpha1().ClusterWorkspaces()
+
+	// the admission plugin of the workspace type might not have seen a type that has just been
+	// created, so creation is retried.
+	var ws *tenancyv1alpha1.ClusterWorkspace
+	var lastError error
+	if err := wait.PollImmediateWithContext(ctx, 100*time.Millisecond, wait.ForeverTestTimeout, func(ctx context.Context) (bool, error) {
+		ws, lastError = workspaces.Create(ctx, tmpl, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(lastError) {
+			return false, lastError
+		}
+		return lastError == nil, nil
+	}); err != nil {
+		if lastError != nil {
+			err = lastError
+		}
+		return "", fmt.Errorf("failed to create workspace in %s: %w", parent, err)
+	}
+
+	clusterName := parent.Join(ws.Name)
+	if err := wait.PollImmediateWithContext(ctx, 100*time.Millisecond, wait.ForeverTestTimeout, func(ctx context.Context) (bool, error) {
+		ws, lastError = workspaces.Get(ctx, clusterName.Base(), metav1.GetOptions{})
+		if apierrors.IsNotFound(lastError) {
+			return false, lastError
+		}
+		return lastError == nil && ws.Status.Phase == tenancyv1alpha1.ClusterWorkspacePhaseReady, nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to wait for workspace %s to become ready: %w", clusterName, err)
+	}
+
+	return clusterName, nil
+}
+
+// NewWorkspace creates a workspace in the parent workspace and waits for it to be ready. The workspace
+// is deleted when the test and all its subtests have completed.
+func (s *Server) NewWorkspace(t testing.TB, parent logicalcluster.Name, opts ...WorkspaceOption) logicalcluster.Name {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clusterName, err := s.CreateWorkspace(ctx, parent, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err := s.clients.Kcp.Cluster(parent).TenancyV1alpha1().ClusterWorkspaces().Delete(ctx, clusterName.Base(), metav1.DeleteOptions{})
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+			return // the parent has probably been deleted already
+		}
+		if err != nil {
+			t.Errorf("failed to delete workspace %s: %v", clusterName, err)
+		}
+	})
+
+	return clusterName
+}
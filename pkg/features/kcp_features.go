@@ -48,6 +48,29 @@ const (
 	//
 	// Enable reverse tunnels to the downstream clusters through the syncers.
 	SyncerTunnel featuregate.Feature = "KCPSyncerTunnel"
+
+	// owner: @agent
+	// alpha: v0.10
+	//
+	// Mark bound CRDs in the shadow workspace as established and their names as accepted right
+	// after creation, instead of waiting for the naming and establishing controllers. Bound CRD
+	// names are derived from APIResourceSchema UIDs and conflicts are already checked by the
+	// APIBinding controller, so these controllers have nothing to decide for them.
+	BoundCRDFastEstablishment featuregate.Feature = "KCPBoundCRDFastEstablishment"
+
+	// owner: @agent
+	// alpha: v0.10
+	//
+	// Throttle requests by the FlowSchemas and PriorityLevelConfigurations of the workspace they target,
+	// in addition to the server-wide API priority and fairness.
+	WorkspacePriorityAndFairness featuregate.Feature = "KCPWorkspacePriorityAndFairness"
+
+	// owner: @agent
+	// alpha: v0.10
+	//
+	// Authenticate bearer tokens with the OIDC issuers configured through WorkspaceAuthenticationConfigurations
+	// in the workspace of the request or its ancestors, if no server-wide authenticator accepts them.
+	WorkspaceAuthentication featuregate.Feature = "KCPWorkspaceAuthentication"
 )
 
 // DefaultFeatureGate exposes the upstream feature gate, but with our gate setting applied.
@@ -99,6 +122,12 @@ var defaultGenericControlPlaneFeatureGates = map[featuregate.Feature]featuregate
 	LocationAPI:  {Default: true, PreRelease: featuregate.Alpha},
 	SyncerTunnel: {Default: false, PreRelease: featuregate.Alpha},
 
+	BoundCRDFastEstablishment: {Default: false, PreRelease: featuregate.Alpha},
+
+	WorkspacePriorityAndFairness: {Default: false, PreRelease: featuregate.Alpha},
+
+	WorkspaceAuthentication: {Default: false, PreRelease: featuregate.Alpha},
+
 	// inherited features from generic apiserver, relisted here to get a conflict if it is changed
 	// unintentionally on either side:
 	genericfeatures.AdvancedAuditing:                    {Default: true, PreRelease: featuregate.GA},
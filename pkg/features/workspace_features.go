@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/component-base/featuregate"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// workspaceFeatures are the features that can be overridden per workspace through the
+// experimental.tenancy.kcp.dev/feature-gates annotation of a ClusterWorkspace.
+var workspaceFeatures = map[featuregate.Feature]bool{
+	LocationAPI: true,
+}
+
+// DefaultWorkspaceFeatureGate answers feature gate queries for workspaces. It falls back to DefaultFeatureGate
+// for workspaces without override, and for everything before SetClusterWorkspaceGetter is called.
+var DefaultWorkspaceFeatureGate = &WorkspaceFeatureGate{defaults: DefaultFeatureGate}
+
+// WorkspaceFeatureGate resolves feature gates per workspace. An override in the feature gates annotation of a
+// ClusterWorkspace applies to the workspace and all workspaces below it, unless one of them overrides it again.
+type WorkspaceFeatureGate struct {
+	defaults featuregate.FeatureGate
+
+	lock                sync.RWMutex
+	getClusterWorkspace func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+}
+
+// SetClusterWorkspaceGetter sets the function used to look up the ClusterWorkspaces of a workspace and its ancestors.
+func (g *WorkspaceFeatureGate) SetClusterWorkspaceGetter(getClusterWorkspace func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.getClusterWorkspace = getClusterWorkspace
+}
+
+// Enabled returns whether the feature is enabled for the given logical cluster.
+func (g *WorkspaceFeatureGate) Enabled(feature featuregate.Feature, clusterName logicalcluster.Name) bool {
+	g.lock.RLock()
+	getClusterWorkspace := g.getClusterWorkspace
+	g.lock.RUnlock()
+
+	if !workspaceFeatures[feature] || getClusterWorkspace == nil {
+		return g.defaults.Enabled(feature)
+	}
+
+	for current := clusterName; ; {
+		parent, hasParent := current.Parent()
+		if !hasParent {
+			break
+		}
+		if ws, err := getClusterWorkspace(parent, current.Base()); err == nil {
+			// invalid values are rejected by admission, so ignore errors here
+			if overrides, _ := ParseWorkspaceFeatureGates(ws.Annotations[tenancyv1alpha1.ExperimentalClusterWorkspaceFeatureGatesAnnotationKey]); overrides != nil {
+				if enabled, found := overrides[feature]; found {
+					return enabled
+				}
+			}
+		}
+		current = parent
+	}
+
+	return g.defaults.Enabled(feature)
+}
+
+// ParseWorkspaceFeatureGates parses the value of the feature gates annotation of a ClusterWorkspace, a comma
+// separated list of <feature>=<bool> pairs. Only features that can be overridden per workspace are accepted.
+func ParseWorkspaceFeatureGates(value string) (map[featuregate.Feature]bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	ret := map[featuregate.Feature]bool{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		k, v, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("missing bool value for %q", pair)
+		}
+		feature := featuregate.Feature(strings.TrimSpace(k))
+		if !workspaceFeatures[feature] {
+			return nil, fmt.Errorf("feature %q cannot be set per workspace, supported are: %s", feature, strings.Join(KnownWorkspaceFeatures(), ", "))
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of %s=%s, err: %w", feature, v, err)
+		}
+		ret[feature] = enabled
+	}
+	return ret, nil
+}
+
+// KnownWorkspaceFeatures returns the features that can be overridden per workspace.
+func KnownWorkspaceFeatures() []string {
+	var features []string
+	for k := range workspaceFeatures {
+		features = append(features, string(k))
+	}
+	sort.Strings(features)
+	return features
+}
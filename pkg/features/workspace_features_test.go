@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/featuregate"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestWorkspaceFeatureGate(t *testing.T) {
+	workspaces := map[string]string{
+		"root|org":         "KCPLocationAPI=false",
+		"root:org|team":    "",
+		"root:org|other":   "KCPLocationAPI=true",
+		"root:org:other|a": "",
+		"root|invalid":     "KCPLocationAPI=maybe",
+	}
+	getClusterWorkspace := func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		value, found := workspaces[clusterName.String()+"|"+name]
+		if !found {
+			return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+		}
+		ws := &tenancyv1alpha1.ClusterWorkspace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if value != "" {
+			ws.Annotations = map[string]string{tenancyv1alpha1.ExperimentalClusterWorkspaceFeatureGatesAnnotationKey: value}
+		}
+		return ws, nil
+	}
+
+	defaults := featuregate.NewFeatureGate()
+	require.NoError(t, defaults.Add(defaultGenericControlPlaneFeatureGates))
+
+	tests := map[string]struct {
+		feature     featuregate.Feature
+		clusterName string
+		want        bool
+	}{
+		"root uses default":                          {feature: LocationAPI, clusterName: "root", want: true},
+		"override on workspace":                      {feature: LocationAPI, clusterName: "root:org", want: false},
+		"override inherited":                         {feature: LocationAPI, clusterName: "root:org:team", want: false},
+		"override of closest ancestor wins":          {feature: LocationAPI, clusterName: "root:org:other:a", want: true},
+		"invalid override ignored":                   {feature: LocationAPI, clusterName: "root:invalid", want: true},
+		"unknown workspace uses default":             {feature: LocationAPI, clusterName: "root:unknown", want: true},
+		"feature not settable per workspace":         {feature: SyncerTunnel, clusterName: "root:org:other", want: false},
+		"system workspaces without ClusterWorkspace": {feature: LocationAPI, clusterName: "system:admin", want: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := &WorkspaceFeatureGate{defaults: defaults}
+			g.SetClusterWorkspaceGetter(getClusterWorkspace)
+			require.Equal(t, tt.want, g.Enabled(tt.feature, logicalcluster.New(tt.clusterName)))
+		})
+	}
+}
+
+func TestParseWorkspaceFeatureGates(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    map[featuregate.Feature]bool
+		wantErr bool
+	}{
+		"empty":         {value: "", want: nil},
+		"enabled":       {value: "KCPLocationAPI=true", want: map[featuregate.Feature]bool{LocationAPI: true}},
+		"with spaces":   {value: " KCPLocationAPI = false ", want: map[featuregate.Feature]bool{LocationAPI: false}},
+		"missing value": {value: "KCPLocationAPI", wantErr: true},
+		"invalid value": {value: "KCPLocationAPI=yes please", wantErr: true},
+		"not settable":  {value: "KCPSyncerTunnel=true", wantErr: true},
+		"unknown":       {value: "Foo=true", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseWorkspaceFeatureGates(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
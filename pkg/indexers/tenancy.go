@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package indexers
+
+import (
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+const (
+	// ClusterWorkspacesByAliasPath is the name for the index that indexes ClusterWorkspaces by the
+	// logical clusters of their aliases, expired or not.
+	ClusterWorkspacesByAliasPath = "ClusterWorkspacesByAliasPath"
+)
+
+// IndexClusterWorkspacesByAliasPath indexes ClusterWorkspaces by the logical clusters of their aliases.
+func IndexClusterWorkspacesByAliasPath(obj interface{}) ([]string, error) {
+	ws, ok := obj.(*tenancyv1alpha1.ClusterWorkspace)
+	if !ok {
+		return []string{}, fmt.Errorf("obj is supposed to be a tenancyv1alpha1.ClusterWorkspace, but is %T", obj)
+	}
+
+	var ret []string
+	for _, alias := range ws.Spec.Aliases {
+		if alias.Name == ws.Name {
+			continue
+		}
+		ret = append(ret, logicalcluster.From(ws).Join(alias.Name).String())
+	}
+	return ret, nil
+}
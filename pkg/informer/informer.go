@@ -45,8 +45,10 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog/v2"
 
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	metadataclient "github.com/kcp-dev/kcp/pkg/metadata"
 	"github.com/kcp-dev/kcp/pkg/projection"
@@ -56,6 +58,12 @@ const (
 	resyncPeriod = 10 * time.Hour
 
 	byGroupVersionResourceIndex = "byGroupVersionResource"
+
+	// deferredInformerStartQPS and deferredInformerStartBurst limit the rate at which informers for bound CRDs
+	// are started. On startup of a shard with many APIBindings, this avoids listing all bound resources of all
+	// logical clusters at once. Informers for built-in types and system CRDs are not rate limited.
+	deferredInformerStartQPS   = 10
+	deferredInformerStartBurst = 20
 )
 
 // DynamicDiscoverySharedInformerFactory is a SharedInformerFactory that
@@ -82,6 +90,11 @@ type DynamicDiscoverySharedInformerFactory struct {
 	discoveryData    discoveryData
 	restMapper       restMapper
 
+	// deferredInformers are the informers for resources only served by bound CRDs. They are started
+	// through deferredStartLimiter after all other informers.
+	deferredInformers    map[schema.GroupVersionResource]bool
+	deferredStartLimiter flowcontrol.RateLimiter
+
 	// Support subscribers (e.g. quota) that want to know when informers/discovery have changed.
 	subscribersLock sync.Mutex
 	subscribers     map[string]chan<- struct{}
@@ -117,6 +130,9 @@ func NewDynamicDiscoverySharedInformerFactory(
 		startedInformers: make(map[schema.GroupVersionResource]bool),
 		informerStops:    make(map[schema.GroupVersionResource]chan struct{}),
 
+		deferredInformers:    make(map[schema.GroupVersionResource]bool),
+		deferredStartLimiter: flowcontrol.NewTokenBucketRateLimiter(deferredInformerStartQPS, deferredInformerStartBurst),
+
 		subscribers: make(map[string]chan<- struct{}),
 	}
 
@@ -389,7 +405,7 @@ func (d *DynamicDiscoverySharedInformerFactory) StartWorker(ctx context.Context)
 	}
 
 	// Now that the CRD informer has synced, do an initial update
-	d.updateInformers()
+	d.updateInformers(ctx)
 
 	// Use UntilWithContext here so that we only check updateCh at most once every second. Because a flurry of several
 	// watch events for CRDs can come in quickly, this effectively "batches" them, so we aren't recalculating the
@@ -403,7 +419,7 @@ func (d *DynamicDiscoverySharedInformerFactory) StartWorker(ctx context.Context)
 		}
 
 		klog.V(5).InfoS("Notification received")
-		d.updateInformers()
+		d.updateInformers(ctx)
 	}, time.Second)
 }
 
@@ -453,10 +469,11 @@ func builtInInformableTypes() map[schema.GroupVersionResource]gvrPartialMetadata
 	}
 }
 
-func (d *DynamicDiscoverySharedInformerFactory) updateInformers() {
+func (d *DynamicDiscoverySharedInformerFactory) updateInformers(ctx context.Context) {
 	klog.V(5).InfoS("Determining dynamic informer additions and removals")
 
 	latest := builtInInformableTypes()
+	deferred := map[schema.GroupVersionResource]bool{}
 
 	// Get the unique set of Group(Version)Resources (version doesn't matter because we're expecting a wildcard
 	// partial metadata client, but we need a version in the request, so we need it here) and add them to latest.
@@ -494,8 +511,49 @@ func (d *DynamicDiscoverySharedInformerFactory) updateInformers() {
 				Singular: crd.Spec.Names.Singular,
 			},
 		}
+		if onlyBoundCRDs(obj) {
+			deferred[gvr] = true
+		}
 	}
 
+	toStart := d.updateInformersFor(latest, deferred)
+
+	// Start the informers for system resources right away, and those only served by bound CRDs rate limited
+	// afterwards, in the background in order to not block informer recalculation.
+	var toDefer []schema.GroupVersionResource
+	for _, gvr := range toStart {
+		if deferred[gvr] {
+			toDefer = append(toDefer, gvr)
+			continue
+		}
+		d.runInformer(gvr)
+	}
+	if len(toDefer) > 0 {
+		klog.V(2).InfoS("Deferring start of dynamic informers for bound resources", "count", len(toDefer))
+		go func() {
+			for _, gvr := range toDefer {
+				if err := d.deferredStartLimiter.Wait(ctx); err != nil {
+					return // context closed
+				}
+				d.runInformer(gvr)
+			}
+		}()
+	}
+}
+
+// onlyBoundCRDs returns true if all the given CRDs are bound CRDs created for APIBindings.
+func onlyBoundCRDs(crds []*apiextensionsv1.CustomResourceDefinition) bool {
+	for _, crd := range crds {
+		if _, bound := crd.Annotations[apisv1alpha1.AnnotationBoundCRDKey]; !bound {
+			return false
+		}
+	}
+	return true
+}
+
+// updateInformersFor adds and removes informers such that they match latest, and updates discovery. It returns
+// the informers added, which have not been started yet.
+func (d *DynamicDiscoverySharedInformerFactory) updateInformersFor(latest map[schema.GroupVersionResource]gvrPartialMetadata, deferred map[schema.GroupVersionResource]bool) []schema.GroupVersionResource {
 	// Grab a read lock to compare against d.informers to see if we need to start or stop any informers
 	d.informersLock.RLock()
 	informersToAdd, informersToRemove := d.calculateInformersLockHeld(latest)
@@ -503,7 +561,7 @@ func (d *DynamicDiscoverySharedInformerFactory) updateInformers() {
 
 	if len(informersToAdd) == 0 && len(informersToRemove) == 0 {
 		klog.V(5).InfoS("No changes")
-		return
+		return nil
 	}
 
 	// We have to add/remove, so we need the write lock
@@ -515,7 +573,7 @@ func (d *DynamicDiscoverySharedInformerFactory) updateInformers() {
 	informersToAdd, informersToRemove = d.calculateInformersLockHeld(latest)
 	if len(informersToAdd) == 0 && len(informersToRemove) == 0 {
 		klog.V(5).InfoS("No changes")
-		return
+		return nil
 	}
 
 	// Now we definitely need to do this work
@@ -523,15 +581,14 @@ func (d *DynamicDiscoverySharedInformerFactory) updateInformers() {
 		gvr := informersToAdd[i]
 
 		// We have the write lock, so call the LH variant
-		inf := d.informerForResourceLockHeld(gvr)
+		d.informerForResourceLockHeld(gvr)
 
-		// Set up a stop channel for this specific informer
-		stop := make(chan struct{})
-		go inf.Informer().Run(stop)
-
-		// And store it
-		d.informerStops[gvr] = stop
+		// Set up a stop channel for this specific informer. The informer is started by the caller.
+		d.informerStops[gvr] = make(chan struct{})
 		d.startedInformers[gvr] = true
+		if deferred[gvr] {
+			d.deferredInformers[gvr] = true
+		}
 	}
 
 	for i := range informersToRemove {
@@ -549,6 +606,7 @@ func (d *DynamicDiscoverySharedInformerFactory) updateInformers() {
 		delete(d.informers, gvr)
 		delete(d.informerStops, gvr)
 		delete(d.startedInformers, gvr)
+		delete(d.deferredInformers, gvr)
 	}
 
 	d.discoveryData = gvrsToDiscoveryData(latest)
@@ -568,6 +626,56 @@ func (d *DynamicDiscoverySharedInformerFactory) updateInformers() {
 			klog.V(4).InfoS("Unable to notify discovery subscriber - channel full", "id", id)
 		}
 	}
+
+	return informersToAdd
+}
+
+// runInformer runs the informer for the given resource until its stop channel is closed. It is a noop if
+// the informer has been removed in the meantime.
+func (d *DynamicDiscoverySharedInformerFactory) runInformer(gvr schema.GroupVersionResource) {
+	d.informersLock.RLock()
+	defer d.informersLock.RUnlock()
+
+	inf, found := d.informers[gvr]
+	if !found {
+		return
+	}
+	go inf.Informer().Run(d.informerStops[gvr])
+}
+
+// InformerProgress describes how far the dynamic informers have synced.
+type InformerProgress struct {
+	// Total is the number of dynamic informers.
+	Total int
+	// Synced is the number of dynamic informers that have synced.
+	Synced int
+	// NotSynced lists the informers for built-in types and system CRDs which have not synced yet.
+	NotSynced []schema.GroupVersionResource
+	// DeferredNotSynced is the number of informers for resources only served by bound CRDs which have not synced yet.
+	DeferredNotSynced int
+}
+
+// Progress returns the sync progress of the dynamic informers.
+func (d *DynamicDiscoverySharedInformerFactory) Progress() InformerProgress {
+	d.informersLock.RLock()
+	defer d.informersLock.RUnlock()
+
+	progress := InformerProgress{Total: len(d.informers)}
+	for gvr, inf := range d.informers {
+		switch {
+		case inf.Informer().HasSynced():
+			progress.Synced++
+		case d.deferredInformers[gvr]:
+			progress.DeferredNotSynced++
+		default:
+			progress.NotSynced = append(progress.NotSynced, gvr)
+		}
+	}
+	sort.Slice(progress.NotSynced, func(i, j int) bool {
+		return progress.NotSynced[i].String() < progress.NotSynced[j].String()
+	})
+
+	return progress
 }
 
 // gvrsToDiscoveryData returns discovery data for all the resources covered by the factory. It only
@@ -31,6 +31,8 @@ import (
 	"k8s.io/client-go/restmapper"
 	"k8s.io/kubernetes/pkg/api/genericcontrolplanescheme"
 	_ "k8s.io/kubernetes/pkg/genericcontrolplane/apis/install"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 )
 
 // TestBuiltInInformableTypes tests that there is no drift between actual built-in types and the list that is hard-coded
@@ -294,3 +296,13 @@ func TestGVRsToDiscoveryData(t *testing.T) {
 
 	require.Empty(t, cmp.Diff(expected, actual, cmp.AllowUnexported(discoveryData{})))
 }
+
+func TestOnlyBoundCRDs(t *testing.T) {
+	bound := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{apisv1alpha1.AnnotationBoundCRDKey: ""}}}
+	system := &apiextensionsv1.CustomResourceDefinition{}
+
+	require.True(t, onlyBoundCRDs([]*apiextensionsv1.CustomResourceDefinition{bound}))
+	require.True(t, onlyBoundCRDs([]*apiextensionsv1.CustomResourceDefinition{bound, bound}))
+	require.False(t, onlyBoundCRDs([]*apiextensionsv1.CustomResourceDefinition{system}))
+	require.False(t, onlyBoundCRDs([]*apiextensionsv1.CustomResourceDefinition{bound, system}), "resources also served by a non-bound CRD must not be deferred")
+}
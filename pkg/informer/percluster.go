@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	kubernetesinformers "k8s.io/client-go/informers"
+	"k8s.io/klog/v2"
+)
+
+// ScopedSharedInformerFactory is the common interface of the single-cluster shared informer factories.
+type ScopedSharedInformerFactory interface {
+	Start(stopCh <-chan struct{})
+	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
+}
+
+// PerClusterSharedInformerFactory manages one shared informer factory per logical cluster. Every factory
+// lists and watches a single logical cluster only, and can be started and stopped independently. This is
+// meant for controllers serving a few workspaces only, for which wildcard informers holding all objects
+// of all logical clusters in memory are too costly.
+type PerClusterSharedInformerFactory[F ScopedSharedInformerFactory] struct {
+	newFactory func(cluster logicalcluster.Name) F
+
+	lock      sync.Mutex
+	factories map[logicalcluster.Name]*perClusterFactory[F]
+}
+
+type perClusterFactory[F ScopedSharedInformerFactory] struct {
+	factory F
+	stopCh  chan struct{}
+}
+
+// NewPerClusterSharedInformerFactory returns a PerClusterSharedInformerFactory creating factories with newFactory.
+func NewPerClusterSharedInformerFactory[F ScopedSharedInformerFactory](newFactory func(cluster logicalcluster.Name) F) *PerClusterSharedInformerFactory[F] {
+	return &PerClusterSharedInformerFactory[F]{
+		newFactory: newFactory,
+		factories:  map[logicalcluster.Name]*perClusterFactory[F]{},
+	}
+}
+
+// NewPerClusterKubeSharedInformerFactory returns a PerClusterSharedInformerFactory for kube informers.
+func NewPerClusterKubeSharedInformerFactory(client kcpkubernetesclientset.ClusterInterface, defaultResync time.Duration) *PerClusterSharedInformerFactory[kubernetesinformers.SharedInformerFactory] {
+	return NewPerClusterSharedInformerFactory(func(cluster logicalcluster.Name) kubernetesinformers.SharedInformerFactory {
+		return kubernetesinformers.NewSharedInformerFactory(client.Cluster(cluster), defaultResync)
+	})
+}
+
+// Cluster returns the factory for the given logical cluster, creating it if needed. Informers
+// requested from the factory are only running after calling Start for the cluster.
+func (f *PerClusterSharedInformerFactory[F]) Cluster(cluster logicalcluster.Name) F {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.clusterLockHeld(cluster).factory
+}
+
+func (f *PerClusterSharedInformerFactory[F]) clusterLockHeld(cluster logicalcluster.Name) *perClusterFactory[F] {
+	if pcf, found := f.factories[cluster]; found {
+		return pcf
+	}
+
+	pcf := &perClusterFactory[F]{
+		factory: f.newFactory(cluster),
+		stopCh:  make(chan struct{}),
+	}
+	f.factories[cluster] = pcf
+	return pcf
+}
+
+// Start starts all informers of the given logical cluster that have been requested so far. It
+// can be called multiple times, e.g. after requesting more informers. It is non-blocking.
+func (f *PerClusterSharedInformerFactory[F]) Start(cluster logicalcluster.Name) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	klog.V(4).InfoS("Starting informers for logical cluster", "cluster", cluster)
+	pcf := f.clusterLockHeld(cluster)
+	pcf.factory.Start(pcf.stopCh)
+}
+
+// WaitForCacheSync waits for the started informers of the given logical cluster to sync, or stopCh to be closed.
+func (f *PerClusterSharedInformerFactory[F]) WaitForCacheSync(cluster logicalcluster.Name, stopCh <-chan struct{}) map[reflect.Type]bool {
+	f.lock.Lock()
+	pcf, found := f.factories[cluster]
+	f.lock.Unlock()
+
+	if !found {
+		return nil
+	}
+	return pcf.factory.WaitForCacheSync(stopCh)
+}
+
+// Stop stops all informers of the given logical cluster and forgets about its factory. Informers
+// requested for the cluster before calling Stop must not be used afterwards.
+func (f *PerClusterSharedInformerFactory[F]) Stop(cluster logicalcluster.Name) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	pcf, found := f.factories[cluster]
+	if !found {
+		return
+	}
+
+	klog.V(4).InfoS("Stopping informers for logical cluster", "cluster", cluster)
+	close(pcf.stopCh)
+	delete(f.factories, cluster)
+}
+
+// Shutdown stops the informers of all logical clusters.
+func (f *PerClusterSharedInformerFactory[F]) Shutdown() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for cluster, pcf := range f.factories {
+		close(pcf.stopCh)
+		delete(f.factories, cluster)
+	}
+}
+
+// Clusters returns the logical clusters with a factory, sorted by name.
+func (f *PerClusterSharedInformerFactory[F]) Clusters() []logicalcluster.Name {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	clusters := make([]logicalcluster.Name, 0, len(f.factories))
+	for cluster := range f.factories {
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].String() < clusters[j].String()
+	})
+	return clusters
+}
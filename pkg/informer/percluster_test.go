@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeScopedFactory struct {
+	cluster logicalcluster.Name
+	stopChs []<-chan struct{}
+	synced  int
+}
+
+func (f *fakeScopedFactory) Start(stopCh <-chan struct{}) {
+	f.stopChs = append(f.stopChs, stopCh)
+}
+
+func (f *fakeScopedFactory) WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool {
+	f.synced++
+	return map[reflect.Type]bool{}
+}
+
+func stopped(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestPerClusterSharedInformerFactory(t *testing.T) {
+	created := map[logicalcluster.Name]int{}
+	f := NewPerClusterSharedInformerFactory(func(cluster logicalcluster.Name) *fakeScopedFactory {
+		created[cluster]++
+		return &fakeScopedFactory{cluster: cluster}
+	})
+
+	foo, bar := logicalcluster.New("root:foo"), logicalcluster.New("root:bar")
+
+	fooFactory := f.Cluster(foo)
+	require.Equal(t, foo, fooFactory.cluster)
+	require.Same(t, fooFactory, f.Cluster(foo), "factory of a cluster must be reused")
+	require.Equal(t, 1, created[foo])
+
+	require.Nil(t, f.WaitForCacheSync(bar, nil), "no factory of bar yet")
+
+	f.Start(foo)
+	f.Start(foo)
+	f.Start(bar)
+	barFactory := f.Cluster(bar)
+	require.Equal(t, []logicalcluster.Name{bar, foo}, f.Clusters())
+
+	require.NotNil(t, f.WaitForCacheSync(foo, nil))
+	require.Equal(t, 1, fooFactory.synced)
+
+	require.Len(t, fooFactory.stopChs, 2)
+	require.Equal(t, fooFactory.stopChs[0], fooFactory.stopChs[1], "informers of a cluster must share one stop channel")
+
+	f.Stop(foo)
+	require.True(t, stopped(fooFactory.stopChs[0]))
+	require.False(t, stopped(barFactory.stopChs[0]), "stopping foo must not stop bar")
+	require.Equal(t, []logicalcluster.Name{bar}, f.Clusters())
+	f.Stop(foo) // idempotent
+
+	// a stopped cluster gets a new factory when requested again
+	require.NotSame(t, fooFactory, f.Cluster(foo))
+	require.Equal(t, 2, created[foo])
+
+	f.Shutdown()
+	require.True(t, stopped(barFactory.stopChs[0]))
+	require.Empty(t, f.Clusters())
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+)
+
+// IdleCache is a size bounded cache whose entries expire after a TTL, and which can evict entries
+// that have not been used for a while. Register its Evict method to have it evicted under memory
+// pressure.
+type IdleCache[K comparable, V any] struct {
+	maxSize int
+	ttl     time.Duration
+	now     func() time.Time
+
+	lock    sync.Mutex
+	entries map[K]*list.Element
+	// byUse orders the entries from the most to the least recently used.
+	byUse *list.List
+}
+
+type idleCacheEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	expires  time.Time
+	lastUsed time.Time
+}
+
+// NewIdleCache returns an IdleCache holding at most maxSize entries, each for at most ttl.
+func NewIdleCache[K comparable, V any](maxSize int, ttl time.Duration) *IdleCache[K, V] {
+	return &IdleCache[K, V]{
+		maxSize: maxSize,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[K]*list.Element{},
+		byUse:   list.New(),
+	}
+}
+
+// Get returns the value of key, and marks it as used.
+func (c *IdleCache[K, V]) Get(key K) (V, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var zero V
+	elem, found := c.entries[key]
+	if !found {
+		return zero, false
+	}
+	entry := elem.Value.(*idleCacheEntry[K, V])
+	now := c.now()
+	if now.After(entry.expires) {
+		c.removeLocked(elem)
+		return zero, false
+	}
+	entry.lastUsed = now
+	c.byUse.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Add adds or replaces the value of key. The least recently used entry is dropped if the cache is full.
+func (c *IdleCache[K, V]) Add(key K, value V) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	if elem, found := c.entries[key]; found {
+		c.removeLocked(elem)
+	}
+	c.entries[key] = c.byUse.PushFront(&idleCacheEntry[K, V]{key: key, value: value, expires: now.Add(c.ttl), lastUsed: now})
+
+	for c.byUse.Len() > c.maxSize {
+		c.removeLocked(c.byUse.Back())
+	}
+}
+
+// Remove removes key from the cache.
+func (c *IdleCache[K, V]) Remove(key K) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, found := c.entries[key]; found {
+		c.removeLocked(elem)
+	}
+}
+
+// Len returns the number of entries, including expired ones not looked up since.
+func (c *IdleCache[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.byUse.Len()
+}
+
+// Evict drops the entries not used for at least idleFor, most idle first, while more than keep
+// entries are left. It is an EvictFunc.
+func (c *IdleCache[K, V]) Evict(idleFor time.Duration, keep int) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	idleSince := c.now().Add(-idleFor)
+	evicted := 0
+	for c.byUse.Len() > keep {
+		elem := c.byUse.Back()
+		if elem.Value.(*idleCacheEntry[K, V]).lastUsed.After(idleSince) {
+			break // all others were used more recently
+		}
+		c.removeLocked(elem)
+		evicted++
+	}
+	return evicted
+}
+
+func (c *IdleCache[K, V]) removeLocked(elem *list.Element) {
+	delete(c.entries, elem.Value.(*idleCacheEntry[K, V]).key)
+	c.byUse.Remove(elem)
+}
+
+// IdleTracker records when the entries of a cache managed elsewhere were last used, for the cache
+// to evict idle entries.
+type IdleTracker[K comparable] struct {
+	now func() time.Time
+
+	lock     sync.Mutex
+	lastUsed map[K]time.Time
+}
+
+// NewIdleTracker returns an empty IdleTracker.
+func NewIdleTracker[K comparable]() *IdleTracker[K] {
+	return &IdleTracker[K]{
+		now:      time.Now,
+		lastUsed: map[K]time.Time{},
+	}
+}
+
+// Touch marks key as used now.
+func (t *IdleTracker[K]) Touch(key K) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.lastUsed[key] = t.now()
+}
+
+// Forget stops tracking key, e.g. when it was removed from the cache.
+func (t *IdleTracker[K]) Forget(key K) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.lastUsed, key)
+}
+
+// Idle returns the keys not used for at least idleFor, most idle first, leaving out the keep most
+// recently used keys. The returned keys are not tracked anymore.
+func (t *IdleTracker[K]) Idle(idleFor time.Duration, keep int) []K {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if len(t.lastUsed) <= keep {
+		return nil
+	}
+
+	type used struct {
+		key K
+		at  time.Time
+	}
+	idleSince := t.now().Add(-idleFor)
+	candidates := make([]used, 0, len(t.lastUsed))
+	for key, at := range t.lastUsed {
+		if !at.After(idleSince) {
+			candidates = append(candidates, used{key: key, at: at})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].at.Before(candidates[j].at)
+	})
+
+	n := len(t.lastUsed) - keep
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+	ret := make([]K, 0, n)
+	for _, c := range candidates[:n] {
+		ret = append(ret, c.key)
+		delete(t.lastUsed, c.key)
+	}
+	return ret
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdleCache(t *testing.T) {
+	now := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+	c := NewIdleCache[string, int](3, time.Hour)
+	c.now = func() time.Time { return now }
+
+	c.Add("a", 1)
+	now = now.Add(time.Minute)
+	c.Add("b", 2)
+	now = now.Add(time.Minute)
+	c.Add("c", 3)
+	now = now.Add(time.Minute)
+
+	// using a makes b the most idle entry
+	v, found := c.Get("a")
+	require.True(t, found)
+	require.Equal(t, 1, v)
+
+	// the least recently used entry is dropped when full
+	c.Add("d", 4)
+	_, found = c.Get("b")
+	require.False(t, found)
+	require.Equal(t, 3, c.Len())
+
+	// only entries idle for long enough are evicted, even if above keep
+	now = now.Add(3 * time.Minute)
+	c.Add("e", 5) // drops c, the least recently used
+	require.Equal(t, 0, c.Evict(10*time.Minute, 0))
+	require.Equal(t, 2, c.Evict(2*time.Minute, 0), "expected a and d to be evicted, they were used 3 minutes ago")
+	_, found = c.Get("e")
+	require.True(t, found)
+
+	// keep is respected
+	now = now.Add(time.Hour - time.Second)
+	c.Add("f", 6)
+	require.Equal(t, 0, c.Evict(time.Minute, 2))
+	require.Equal(t, 1, c.Evict(time.Minute, 1))
+	_, found = c.Get("f")
+	require.True(t, found)
+
+	// entries expire
+	now = now.Add(time.Hour + time.Second)
+	_, found = c.Get("f")
+	require.False(t, found)
+	require.Equal(t, 0, c.Len())
+}
+
+func TestIdleTracker(t *testing.T) {
+	now := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+	tracker := NewIdleTracker[string]()
+	tracker.now = func() time.Time { return now }
+
+	tracker.Touch("a")
+	now = now.Add(time.Minute)
+	tracker.Touch("b")
+	now = now.Add(time.Minute)
+	tracker.Touch("c")
+	tracker.Touch("gone")
+	tracker.Forget("gone")
+	now = now.Add(time.Minute)
+
+	require.Empty(t, tracker.Idle(10*time.Minute, 0))
+	require.Empty(t, tracker.Idle(time.Minute, 3))
+	require.Equal(t, []string{"a"}, tracker.Idle(2*time.Minute, 2))
+	require.Equal(t, []string{"b"}, tracker.Idle(time.Minute, 1))
+	require.Equal(t, []string{"c"}, tracker.Idle(time.Minute, 0))
+	require.Empty(t, tracker.Idle(0, 0))
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"os"
+	runtimemetrics "runtime/metrics"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// EvictFunc drops the entries of a cache that have not been used for at least idleFor, most idle
+// first, while more than keep entries are left. It returns the number of evicted entries.
+type EvictFunc func(idleFor time.Duration, keep int) int
+
+var (
+	evictorsLock sync.RWMutex
+	evictors     = map[string]EvictFunc{}
+)
+
+// Register registers a cache to be evicted under memory pressure. Registering a name again
+// replaces the former EvictFunc.
+func Register(name string, evict EvictFunc) {
+	evictorsLock.Lock()
+	defer evictorsLock.Unlock()
+
+	evictors[name] = evict
+}
+
+// Unregister removes a cache registered with Register.
+func Unregister(name string) {
+	evictorsLock.Lock()
+	defer evictorsLock.Unlock()
+
+	delete(evictors, name)
+}
+
+// Governor watches the heap size and evicts the registered caches when it gets close
+// to the memory limit, instead of letting the process run out of memory.
+type Governor struct {
+	limit       uint64
+	threshold   float64
+	floor       int
+	idleTimeout time.Duration
+	interval    time.Duration
+	heapInUse   func() uint64
+	evictorsFor func() map[string]EvictFunc
+}
+
+// NewGovernor returns a Governor for the given options. It returns nil if there is no
+// memory limit, neither configured nor via GOMEMLIMIT.
+func NewGovernor(o *Options) (*Governor, error) {
+	limit := uint64(o.Limit.Value())
+	if limit == 0 {
+		var err error
+		if limit, err = limitFromEnv(os.Getenv("GOMEMLIMIT")); err != nil {
+			return nil, err
+		}
+	}
+	if limit == 0 {
+		return nil, nil
+	}
+
+	return &Governor{
+		limit:       limit,
+		threshold:   o.PressureThreshold,
+		floor:       o.CacheFloor,
+		idleTimeout: o.IdleTimeout,
+		interval:    o.CheckInterval,
+		heapInUse:   heapInUse,
+		evictorsFor: func() map[string]EvictFunc {
+			evictorsLock.RLock()
+			defer evictorsLock.RUnlock()
+
+			ret := make(map[string]EvictFunc, len(evictors))
+			for name, evict := range evictors {
+				ret[name] = evict
+			}
+			return ret
+		},
+	}, nil
+}
+
+// Start checks the heap size periodically until the context is done.
+func (g *Governor) Start(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithValues("component", "memory-governor")
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting memory governor", "limit", g.limit, "threshold", g.threshold, "floor", g.floor, "idleTimeout", g.idleTimeout)
+	defer logger.Info("Shutting down memory governor")
+
+	limitBytes.Set(float64(g.limit))
+	wait.UntilWithContext(ctx, g.check, g.interval)
+}
+
+func (g *Governor) check(ctx context.Context) {
+	heap := g.heapInUse()
+	heapBytes.Set(float64(heap))
+
+	if float64(heap) < float64(g.limit)*g.threshold {
+		underPressure.Set(0)
+		return
+	}
+	underPressure.Set(1)
+
+	logger := klog.FromContext(ctx)
+	evictors := g.evictorsFor()
+	names := make([]string, 0, len(evictors))
+	for name := range evictors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	total := 0
+	for _, name := range names {
+		n := evictors[name](g.idleTimeout, g.floor)
+		if n > 0 {
+			evictions.WithLabelValues(name).Add(float64(n))
+			total += n
+		}
+	}
+	logger.Info("memory pressure, evicted idle cache entries", "heap", heap, "limit", g.limit, "evicted", total)
+}
+
+func heapInUse() uint64 {
+	samples := []runtimemetrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	runtimemetrics.Read(samples)
+	if samples[0].Value.Kind() != runtimemetrics.KindUint64 {
+		return 0
+	}
+	return samples[0].Value.Uint64()
+}
+
+// limitFromEnv parses the value of GOMEMLIMIT, e.g. "2GiB", "512MiB" or "1000000". "off"
+// and the empty string mean no limit.
+func limitFromEnv(value string) (uint64, error) {
+	if value == "" || value == "off" {
+		return 0, nil
+	}
+	q, err := resource.ParseQuantity(strings.TrimSuffix(value, "B"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse GOMEMLIMIT=%q: %w", value, err)
+	}
+	if q.Sign() < 0 {
+		return 0, fmt.Errorf("invalid GOMEMLIMIT=%q: must not be negative", value)
+	}
+	return uint64(q.Value()), nil
+}
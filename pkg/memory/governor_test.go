@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitFromEnv(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    uint64
+		wantErr bool
+	}{
+		"unset":  {value: "", want: 0},
+		"off":    {value: "off", want: 0},
+		"bytes":  {value: "1000", want: 1000},
+		"B":      {value: "1000B", want: 1000},
+		"MiB":    {value: "512MiB", want: 512 << 20},
+		"GiB":    {value: "2GiB", want: 2 << 30},
+		"broken": {value: "lots", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := limitFromEnv(tc.value)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var kept []int
+	var idle []time.Duration
+	g := &Governor{
+		limit:       1000,
+		threshold:   0.8,
+		floor:       5,
+		idleTimeout: time.Minute,
+		evictorsFor: func() map[string]EvictFunc {
+			return map[string]EvictFunc{
+				"test": func(idleFor time.Duration, keep int) int {
+					idle = append(idle, idleFor)
+					kept = append(kept, keep)
+					return 3
+				},
+			}
+		},
+	}
+
+	g.heapInUse = func() uint64 { return 799 }
+	g.check(context.Background())
+	require.Empty(t, kept, "no eviction expected below the threshold")
+
+	g.heapInUse = func() uint64 { return 800 }
+	g.check(context.Background())
+	require.Equal(t, []int{5}, kept)
+	require.Equal(t, []time.Duration{time.Minute}, idle)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const subsystem = "memory_governor"
+
+var (
+	heapBytes = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      subsystem,
+			Name:           "heap_bytes",
+			Help:           "Bytes of heap objects at the last check of the memory governor.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	limitBytes = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      subsystem,
+			Name:           "limit_bytes",
+			Help:           "Memory limit the memory governor evicts caches for.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	underPressure = metrics.NewGauge(
+		&metrics.GaugeOpts{
+			Subsystem:      subsystem,
+			Name:           "under_pressure",
+			Help:           "1 if the heap exceeded the pressure threshold at the last check, 0 otherwise.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	evictions = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      subsystem,
+			Name:           "evictions_total",
+			Help:           "Number of cache entries evicted under memory pressure, by cache.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"cache"},
+	)
+)
+
+var registerMetrics sync.Once
+
+func init() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(heapBytes)
+		legacyregistry.MustRegister(limitBytes)
+		legacyregistry.MustRegister(underPressure)
+		legacyregistry.MustRegister(evictions)
+	})
+}
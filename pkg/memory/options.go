@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func DefaultOptions() *Options {
+	return &Options{
+		PressureThreshold: 0.8,
+		CheckInterval:     10 * time.Second,
+		CacheFloor:        100,
+		IdleTimeout:       5 * time.Minute,
+	}
+}
+
+func BindOptions(o *Options, fs *pflag.FlagSet) *Options {
+	fs.Var(&o.Limit, "memory-governor-limit", "Memory limit the memory governor evicts caches for, e.g. 4Gi. Defaults to GOMEMLIMIT. If neither is set, the memory governor is disabled.")
+	fs.Float64Var(&o.PressureThreshold, "memory-governor-pressure-threshold", o.PressureThreshold, "Fraction of the memory limit the heap must exceed for idle caches to be evicted.")
+	fs.DurationVar(&o.CheckInterval, "memory-governor-check-interval", o.CheckInterval, "Interval at which the memory governor checks the heap size.")
+	fs.IntVar(&o.CacheFloor, "memory-governor-cache-floor", o.CacheFloor, "Number of entries every cache keeps when evicted under memory pressure.")
+	fs.DurationVar(&o.IdleTimeout, "memory-governor-idle-timeout", o.IdleTimeout, "Time cache entries must not have been used for to be evicted under memory pressure.")
+	return o
+}
+
+type Options struct {
+	Limit             resource.Quantity
+	PressureThreshold float64
+	CheckInterval     time.Duration
+	CacheFloor        int
+	IdleTimeout       time.Duration
+}
+
+func (o *Options) Validate() error {
+	if o.Limit.Sign() < 0 {
+		return fmt.Errorf("--memory-governor-limit must be >=0 (%s)", o.Limit.String())
+	}
+	if o.PressureThreshold <= 0 || o.PressureThreshold > 1 {
+		return fmt.Errorf("--memory-governor-pressure-threshold must be in (0,1] (%v)", o.PressureThreshold)
+	}
+	if o.CheckInterval <= 0 {
+		return fmt.Errorf("--memory-governor-check-interval must be >0 (%s)", o.CheckInterval)
+	}
+	if o.CacheFloor < 0 {
+		return fmt.Errorf("--memory-governor-cache-floor must be >=0 (%d)", o.CacheFloor)
+	}
+	if o.IdleTimeout < 0 {
+		return fmt.Errorf("--memory-governor-idle-timeout must be >=0 (%s)", o.IdleTimeout)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Recorder collects the latencies and errors of operations.
+type Recorder struct {
+	lock      sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		latencies: map[string][]time.Duration{},
+		errors:    map[string]int{},
+	}
+}
+
+// Observe records the latency of a successful operation, or an error.
+func (r *Recorder) Observe(operation string, latency time.Duration, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err != nil {
+		r.errors[operation]++
+		return
+	}
+	r.latencies[operation] = append(r.latencies[operation], latency)
+}
+
+// Summary are the latency percentiles of one operation.
+type Summary struct {
+	Operation string
+	Count     int
+	Errors    int
+	P50       time.Duration
+	P90       time.Duration
+	P99       time.Duration
+	Max       time.Duration
+}
+
+// Summaries returns the summaries of all operations, sorted by operation.
+func (r *Recorder) Summaries() []Summary {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	operations := map[string]bool{}
+	for op := range r.latencies {
+		operations[op] = true
+	}
+	for op := range r.errors {
+		operations[op] = true
+	}
+
+	summaries := make([]Summary, 0, len(operations))
+	for op := range operations {
+		latencies := append([]time.Duration(nil), r.latencies[op]...)
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		s := Summary{Operation: op, Count: len(latencies), Errors: r.errors[op]}
+		if len(latencies) > 0 {
+			s.P50 = percentile(latencies, 0.50)
+			s.P90 = percentile(latencies, 0.90)
+			s.P99 = percentile(latencies, 0.99)
+			s.Max = latencies[len(latencies)-1]
+		}
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Operation < summaries[j].Operation })
+
+	return summaries
+}
+
+// percentile returns the nearest-rank percentile p of the sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// Report writes a table of the summaries of all operations.
+func (r *Recorder) Report(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "OPERATION\tCOUNT\tERRORS\tP50\tP90\tP99\tMAX") //nolint:errcheck
+	for _, s := range r.Summaries() {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\n", s.Operation, s.Count, s.Errors, //nolint:errcheck
+			s.P50.Round(time.Millisecond), s.P90.Round(time.Millisecond), s.P99.Round(time.Millisecond), s.Max.Round(time.Millisecond))
+	}
+	return w.Flush()
+}
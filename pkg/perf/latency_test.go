@@ -0,0 +1,50 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummaries(t *testing.T) {
+	r := NewRecorder()
+	for i := 100; i >= 1; i-- {
+		r.Observe("create", time.Duration(i)*time.Millisecond, nil)
+	}
+	r.Observe("create", 0, errors.New("boom"))
+	r.Observe("delete", 0, errors.New("boom"))
+
+	require.Equal(t, []Summary{
+		{
+			Operation: "create",
+			Count:     100,
+			Errors:    1,
+			P50:       50 * time.Millisecond,
+			P90:       90 * time.Millisecond,
+			P99:       99 * time.Millisecond,
+			Max:       100 * time.Millisecond,
+		},
+		{
+			Operation: "delete",
+			Errors:    1,
+		},
+	}, r.Summaries())
+}
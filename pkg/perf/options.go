@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/spf13/pflag"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// Options are the options of a load test run.
+type Options struct {
+	// Kubeconfig is the kubeconfig of the kcp installation under test. It must point to the base URL of
+	// kcp, without a /clusters/<name> suffix, and hold credentials allowed to create workspaces in Parent.
+	Kubeconfig string
+	// Context is the kubeconfig context to use. Defaults to the current context.
+	Context string
+
+	// Parent is the workspace the load test workspace is created in.
+	Parent string
+	// Workspaces is the number of workspaces created.
+	Workspaces int
+	// Bindings is the number of workspaces binding the load test APIExport. At most Workspaces.
+	Bindings int
+	// ObjectsPerWorkspace is the number of objects churned in every bound workspace.
+	ObjectsPerWorkspace int
+	// Duration is how long objects are churned.
+	Duration time.Duration
+	// QPS is the sustained rate of object operations during churn, across all workspaces.
+	QPS float64
+	// Concurrency is the number of parallel requests.
+	Concurrency int
+	// ReadyTimeout is how long to wait for workspaces and bindings to get ready.
+	ReadyTimeout time.Duration
+	// Cleanup deletes the load test workspace after the run.
+	Cleanup bool
+}
+
+// NewOptions returns the default options.
+func NewOptions() *Options {
+	return &Options{
+		Parent:              tenancyv1alpha1.RootCluster.String(),
+		Workspaces:          10,
+		Bindings:            10,
+		ObjectsPerWorkspace: 10,
+		Duration:            time.Minute,
+		QPS:                 20,
+		Concurrency:         10,
+		ReadyTimeout:        5 * time.Minute,
+		Cleanup:             true,
+	}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Kubeconfig of the kcp installation under test. Defaults to the in-cluster config or $KUBECONFIG.")
+	fs.StringVar(&o.Context, "context", o.Context, "Kubeconfig context to use.")
+	fs.StringVar(&o.Parent, "parent", o.Parent, "Workspace to create the load test workspace in.")
+	fs.IntVar(&o.Workspaces, "workspaces", o.Workspaces, "Number of workspaces to create.")
+	fs.IntVar(&o.Bindings, "bindings", o.Bindings, "Number of workspaces binding the load test APIExport. At most --workspaces.")
+	fs.IntVar(&o.ObjectsPerWorkspace, "objects-per-workspace", o.ObjectsPerWorkspace, "Number of objects churned in every bound workspace.")
+	fs.DurationVar(&o.Duration, "duration", o.Duration, "How long to churn objects.")
+	fs.Float64Var(&o.QPS, "qps", o.QPS, "Rate of object operations during churn, across all workspaces.")
+	fs.IntVar(&o.Concurrency, "concurrency", o.Concurrency, "Number of parallel requests.")
+	fs.DurationVar(&o.ReadyTimeout, "ready-timeout", o.ReadyTimeout, "How long to wait for workspaces and bindings to get ready.")
+	fs.BoolVar(&o.Cleanup, "cleanup", o.Cleanup, "Delete the load test workspace after the run.")
+}
+
+func (o *Options) Validate() []error {
+	var errs []error
+
+	if parent := logicalcluster.New(o.Parent); !parent.IsValid() || parent == logicalcluster.Wildcard {
+		errs = append(errs, fmt.Errorf("--parent must be a valid workspace path"))
+	}
+	if o.Workspaces < 1 {
+		errs = append(errs, fmt.Errorf("--workspaces must be >0"))
+	}
+	if o.Bindings < 0 || o.Bindings > o.Workspaces {
+		errs = append(errs, fmt.Errorf("--bindings must be between 0 and --workspaces"))
+	}
+	if o.ObjectsPerWorkspace < 1 {
+		errs = append(errs, fmt.Errorf("--objects-per-workspace must be >0"))
+	}
+	if o.Duration < 0 {
+		errs = append(errs, fmt.Errorf("--duration must be >=0"))
+	}
+	if o.QPS <= 0 {
+		errs = append(errs, fmt.Errorf("--qps must be >0"))
+	}
+	if o.Concurrency < 1 {
+		errs = append(errs, fmt.Errorf("--concurrency must be >0"))
+	}
+	if o.ReadyTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("--ready-timeout must be >0"))
+	}
+
+	return errs
+}
@@ -0,0 +1,395 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+)
+
+const (
+	group       = "perf.kcp.dev"
+	exportName  = group
+	bindingName = "perf"
+)
+
+var widgetsGVR = schema.GroupVersionResource{Group: group, Version: "v1", Resource: "widgets"}
+
+// Run executes a load test against the kcp installation of the given options, and writes a
+// latency report to out. It creates a load test workspace with Workspaces child workspaces,
+// binds an APIExport in Bindings of them, and churns objects of the exported API in the bound
+// workspaces at the configured rate.
+func Run(ctx context.Context, o *Options, out io.Writer) error {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.Kubeconfig
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: o.Context}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	return RunWithConfig(ctx, cfg, o, out)
+}
+
+// RunWithConfig is like Run, but uses the given client config.
+func RunWithConfig(ctx context.Context, cfg *rest.Config, o *Options, out io.Writer) error {
+	logger := klog.FromContext(ctx)
+
+	// client-side throttling would distort the latencies, the load is limited by QPS instead.
+	cfg = rest.CopyConfig(cfg)
+	cfg.QPS = -1
+	cfg.RateLimiter = nil
+	cfg = rest.AddUserAgent(cfg, "kcp-perf")
+
+	kcpClusterClient, err := kcpclientset.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	dynamicClusterClient, err := kcpdynamic.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	r := &runner{
+		options:              o,
+		kcpClusterClient:     kcpClusterClient,
+		dynamicClusterClient: dynamicClusterClient,
+		recorder:             NewRecorder(),
+	}
+
+	parent := logicalcluster.New(o.Parent)
+	start := time.Now()
+	testWorkspace, err := r.createWorkspace(ctx, parent, "perf-")
+	if err != nil {
+		return fmt.Errorf("failed to create load test workspace in %s: %w", parent, err)
+	}
+	logger.Info("created load test workspace", "workspace", testWorkspace, "duration", time.Since(start))
+
+	if o.Cleanup {
+		defer func() {
+			// the parent context might be cancelled already
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			if err := kcpClusterClient.Cluster(parent).TenancyV1alpha1().ClusterWorkspaces().Delete(ctx, testWorkspace.Base(), metav1.DeleteOptions{}); err != nil {
+				logger.Error(err, "failed to delete load test workspace", "workspace", testWorkspace)
+			}
+		}()
+	}
+
+	if err := r.createExport(ctx, testWorkspace); err != nil {
+		return fmt.Errorf("failed to create APIExport in %s: %w", testWorkspace, err)
+	}
+
+	workspaces := make([]logicalcluster.Name, o.Workspaces)
+	start = time.Now()
+	if err := parallel(ctx, o.Workspaces, o.Concurrency, func(i int) error {
+		ws, err := r.createWorkspace(ctx, testWorkspace, fmt.Sprintf("ws-%d-", i))
+		workspaces[i] = ws
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to create workspaces: %w", err)
+	}
+	logger.Info("created workspaces", "count", o.Workspaces, "duration", time.Since(start))
+
+	bound := workspaces[:o.Bindings]
+	start = time.Now()
+	if err := parallel(ctx, len(bound), o.Concurrency, func(i int) error {
+		return r.bind(ctx, bound[i], testWorkspace)
+	}); err != nil {
+		return fmt.Errorf("failed to bind APIExport: %w", err)
+	}
+	logger.Info("created bindings", "count", len(bound), "duration", time.Since(start))
+
+	if len(bound) > 0 && o.Duration > 0 {
+		logger.Info("churning objects", "workspaces", len(bound), "objectsPerWorkspace", o.ObjectsPerWorkspace, "duration", o.Duration, "qps", o.QPS)
+		r.churn(ctx, bound)
+	}
+
+	return r.recorder.Report(out)
+}
+
+type runner struct {
+	options              *Options
+	kcpClusterClient     kcpclientset.ClusterInterface
+	dynamicClusterClient kcpdynamic.ClusterInterface
+	recorder             *Recorder
+}
+
+// createWorkspace creates a universal workspace in parent and waits for it to be ready.
+func (r *runner) createWorkspace(ctx context.Context, parent logicalcluster.Name, generateName string) (logicalcluster.Name, error) {
+	start := time.Now()
+	ws, err := r.kcpClusterClient.Cluster(parent).TenancyV1alpha1().ClusterWorkspaces().Create(ctx, &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: generateName,
+		},
+		Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+			Type: tenancyv1alpha1.ClusterWorkspaceTypeReference{
+				Name: "universal",
+				Path: tenancyv1alpha1.RootCluster.String(),
+			},
+		},
+	}, metav1.CreateOptions{})
+	r.recorder.Observe("workspace-create", time.Since(start), err)
+	if err != nil {
+		return logicalcluster.Name{}, err
+	}
+
+	err = wait.PollImmediateWithContext(ctx, 100*time.Millisecond, r.options.ReadyTimeout, func(ctx context.Context) (bool, error) {
+		ws, err = r.kcpClusterClient.Cluster(parent).TenancyV1alpha1().ClusterWorkspaces().Get(ctx, ws.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return ws.Status.Phase == tenancyv1alpha1.ClusterWorkspacePhaseReady, nil
+	})
+	r.recorder.Observe("workspace-ready", time.Since(start), err)
+	if err != nil {
+		return logicalcluster.Name{}, err
+	}
+
+	return parent.Join(ws.Name), nil
+}
+
+// createExport creates the APIResourceSchema and APIExport of the churned widgets resource.
+func (r *runner) createExport(ctx context.Context, clusterName logicalcluster.Name) error {
+	raw, err := json.Marshal(&apiextensionsv1.JSONSchemaProps{
+		Type:                   "object",
+		XPreserveUnknownFields: boolPtr(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	apiResourceSchema := &apisv1alpha1.APIResourceSchema{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "v1.widgets." + group,
+		},
+		Spec: apisv1alpha1.APIResourceSchemaSpec{
+			Group: group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   "widgets",
+				Singular: "widget",
+				Kind:     "Widget",
+				ListKind: "WidgetList",
+			},
+			Scope: apiextensionsv1.ClusterScoped,
+			Versions: []apisv1alpha1.APIResourceVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema:  runtime.RawExtension{Raw: raw},
+			}},
+		},
+	}
+	if _, err := r.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIResourceSchemas().Create(ctx, apiResourceSchema, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	_, err = r.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIExports().Create(ctx, &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: exportName,
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{apiResourceSchema.Name},
+		},
+	}, metav1.CreateOptions{})
+	return err
+}
+
+// bind binds the load test APIExport in clusterName and waits for the binding to be bound.
+func (r *runner) bind(ctx context.Context, clusterName, exportClusterName logicalcluster.Name) error {
+	start := time.Now()
+	_, err := r.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIBindings().Create(ctx, &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: bindingName,
+		},
+		Spec: apisv1alpha1.APIBindingSpec{
+			Reference: apisv1alpha1.ExportReference{
+				Workspace: &apisv1alpha1.WorkspaceExportReference{
+					Path:       exportClusterName.String(),
+					ExportName: exportName,
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+	r.recorder.Observe("binding-create", time.Since(start), err)
+	if err != nil {
+		return err
+	}
+
+	err = wait.PollImmediateWithContext(ctx, 100*time.Millisecond, r.options.ReadyTimeout, func(ctx context.Context) (bool, error) {
+		binding, err := r.kcpClusterClient.Cluster(clusterName).ApisV1alpha1().APIBindings().Get(ctx, bindingName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return binding.Status.Phase == apisv1alpha1.APIBindingPhaseBound, nil
+	})
+	r.recorder.Observe("binding-bound", time.Since(start), err)
+	if err != nil {
+		return err
+	}
+
+	// bound APIs are served asynchronously, wait until the first request succeeds.
+	err = wait.PollImmediateWithContext(ctx, 100*time.Millisecond, r.options.ReadyTimeout, func(ctx context.Context) (bool, error) {
+		_, err := r.dynamicClusterClient.Cluster(clusterName).Resource(widgetsGVR).List(ctx, metav1.ListOptions{})
+		return err == nil, nil
+	})
+	r.recorder.Observe("binding-served", time.Since(start), err)
+	return err
+}
+
+// churn creates, updates and deletes widgets in the given workspaces at the configured rate until
+// the duration is over. Every object is owned by a single worker to avoid conflicts.
+func (r *runner) churn(ctx context.Context, workspaces []logicalcluster.Name) {
+	ctx, cancel := context.WithTimeout(ctx, r.options.Duration)
+	defer cancel()
+
+	limiter := flowcontrol.NewTokenBucketRateLimiter(float32(r.options.QPS), r.options.Concurrency)
+	defer limiter.Stop()
+
+	type object struct {
+		cluster    logicalcluster.Name
+		name       string
+		exists     bool
+		generation int
+	}
+
+	objects := make([][]*object, r.options.Concurrency)
+	i := 0
+	for _, ws := range workspaces {
+		for j := 0; j < r.options.ObjectsPerWorkspace; j++ {
+			worker := i % r.options.Concurrency
+			objects[worker] = append(objects[worker], &object{cluster: ws, name: fmt.Sprintf("widget-%d", j)})
+			i++
+		}
+	}
+
+	var wg sync.WaitGroup
+	for worker := range objects {
+		owned := objects[worker]
+		if len(owned) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed)) //nolint:gosec
+
+			for next := 0; ; next = (next + 1) % len(owned) {
+				if err := limiter.Wait(ctx); err != nil {
+					return
+				}
+
+				obj := owned[next]
+				client := r.dynamicClusterClient.Cluster(obj.cluster).Resource(widgetsGVR)
+				start := time.Now()
+				switch {
+				case !obj.exists:
+					_, err := client.Create(ctx, &unstructured.Unstructured{Object: map[string]interface{}{
+						"apiVersion": widgetsGVR.GroupVersion().String(),
+						"kind":       "Widget",
+						"metadata":   map[string]interface{}{"name": obj.name},
+					}}, metav1.CreateOptions{})
+					if ctx.Err() != nil {
+						return
+					}
+					r.recorder.Observe("object-create", time.Since(start), err)
+					obj.exists = err == nil
+				case rnd.Intn(3) == 0:
+					err := client.Delete(ctx, obj.name, metav1.DeleteOptions{})
+					if ctx.Err() != nil {
+						return
+					}
+					r.recorder.Observe("object-delete", time.Since(start), err)
+					obj.exists = err != nil && !apierrors.IsNotFound(err)
+				default:
+					obj.generation++
+					patch := fmt.Sprintf(`{"metadata":{"labels":{"perf.kcp.dev/generation":"%d"}}}`, obj.generation)
+					_, err := client.Patch(ctx, obj.name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+					if ctx.Err() != nil {
+						return
+					}
+					r.recorder.Observe("object-update", time.Since(start), err)
+				}
+			}
+		}(int64(worker))
+	}
+	wg.Wait()
+}
+
+// parallel calls fn for 0..n-1 with at most concurrency calls at a time, and returns the first error.
+func parallel(ctx context.Context, n, concurrency int, fn func(i int) error) error {
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(i); err != nil {
+				lock.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				lock.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -20,8 +20,13 @@ import (
 	"context"
 	"fmt"
 
+	kcpcorev1informers "github.com/kcp-dev/client-go/informers/core/v1"
 	"github.com/kcp-dev/logicalcluster/v2"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 
@@ -37,10 +42,11 @@ import (
 type Labeler struct {
 	listAPIBindingsAcceptingClaimedGroupResource func(clusterName logicalcluster.Name, groupResource schema.GroupResource) ([]*apisv1alpha1.APIBinding, error)
 	getAPIBinding                                func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIBinding, error)
+	getNamespace                                 func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error)
 }
 
 // NewLabeler returns a new Labeler.
-func NewLabeler(apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer) *Labeler {
+func NewLabeler(apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer, namespaceInformer kcpcorev1informers.NamespaceClusterInformer) *Labeler {
 	return &Labeler{
 		listAPIBindingsAcceptingClaimedGroupResource: func(clusterName logicalcluster.Name, groupResource schema.GroupResource) ([]*apisv1alpha1.APIBinding, error) {
 			indexKey := indexers.ClusterAndGroupResourceValue(clusterName, groupResource)
@@ -50,13 +56,18 @@ func NewLabeler(apiBindingInformer apisv1alpha1informers.APIBindingClusterInform
 		getAPIBinding: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIBinding, error) {
 			return apiBindingInformer.Lister().Cluster(clusterName).Get(name)
 		},
+
+		getNamespace: func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error) {
+			return namespaceInformer.Lister().Cluster(clusterName).Get(name)
+		},
 	}
 }
 
 // LabelsFor returns all the applicable labels for the cluster-group-resource relating to permission claims. This is
 // the intersection of (1) all APIBindings in the cluster that have accepted claims for the group-resource with (2)
-// associated APIExports that are claiming group-resource.
-func (l *Labeler) LabelsFor(ctx context.Context, cluster logicalcluster.Name, groupResource schema.GroupResource, resourceName string) (map[string]string, error) {
+// associated APIExports that are claiming group-resource, restricted to the claims whose resource selectors select
+// the object with the given name, namespace and labels.
+func (l *Labeler) LabelsFor(ctx context.Context, cluster logicalcluster.Name, groupResource schema.GroupResource, resourceName, resourceNamespace string, resourceLabels map[string]string) (map[string]string, error) {
 	labels := map[string]string{}
 
 	bindings, err := l.listAPIBindingsAcceptingClaimedGroupResource(cluster, groupResource)
@@ -80,6 +91,17 @@ func (l *Labeler) LabelsFor(ctx context.Context, cluster logicalcluster.Name, gr
 				continue
 			}
 
+			selected, err := Selects(claim.PermissionClaim, groupResource, resourceName, resourceNamespace, resourceLabels, func(name string) (*corev1.Namespace, error) {
+				return l.getNamespace(cluster, name)
+			})
+			if err != nil {
+				logger.Error(err, "error evaluating permission claim resource selectors", "claim", claim.String())
+				continue
+			}
+			if !selected {
+				continue
+			}
+
 			k, v, err := permissionclaims.ToLabelKeyAndValue(logicalcluster.New(boundAPIExportWorkspace.Path), boundAPIExportWorkspace.ExportName, claim.PermissionClaim)
 			if err != nil {
 				// extremely unlikely to get an error here - it means the json marshaling failed
@@ -111,3 +133,53 @@ func (l *Labeler) LabelsFor(ctx context.Context, cluster logicalcluster.Name, gr
 
 	return labels, nil
 }
+
+// Selects returns whether the claim selects the object with the given name, namespace and labels. A claim
+// with "all" selects every object, otherwise at least one of its resource selectors must match. A namespace
+// is considered to be in itself, i.e. namespace and namespaceSelector of a resource selector are matched
+// against the namespace itself. Objects in namespaces that do not exist are never matched by a namespaceSelector.
+func Selects(claim apisv1alpha1.PermissionClaim, groupResource schema.GroupResource, name, namespace string, objLabels map[string]string, getNamespace func(name string) (*corev1.Namespace, error)) (bool, error) {
+	if claim.All {
+		return true, nil
+	}
+
+	isNamespace := groupResource == corev1.Resource("namespaces")
+	if isNamespace {
+		namespace = name
+	}
+
+	for _, selector := range claim.ResourceSelector {
+		if selector.Name != "" && selector.Name != name {
+			continue
+		}
+		if selector.Namespace != "" && selector.Namespace != namespace {
+			continue
+		}
+		if selector.NamespaceSelector != nil {
+			if namespace == "" {
+				continue
+			}
+			namespaceSelector, err := metav1.LabelSelectorAsSelector(selector.NamespaceSelector)
+			if err != nil {
+				return false, fmt.Errorf("invalid namespaceSelector: %w", err)
+			}
+			namespaceLabels := objLabels
+			if !isNamespace {
+				ns, err := getNamespace(namespace)
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				if err != nil {
+					return false, err
+				}
+				namespaceLabels = ns.Labels
+			}
+			if !namespaceSelector.Matches(labels.Set(namespaceLabels)) {
+				continue
+			}
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
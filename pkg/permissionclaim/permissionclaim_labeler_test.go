@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package permissionclaim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestSelects(t *testing.T) {
+	namespaces := map[string]*corev1.Namespace{
+		"payments": {ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"team": "payments"}}},
+		"cert":     {ObjectMeta: metav1.ObjectMeta{Name: "cert", Labels: map[string]string{"provider": "cert-manager"}}},
+	}
+	getNamespace := func(name string) (*corev1.Namespace, error) {
+		if ns, ok := namespaces[name]; ok {
+			return ns, nil
+		}
+		return nil, apierrors.NewNotFound(corev1.Resource("namespaces"), name)
+	}
+
+	configMaps := corev1.Resource("configmaps")
+	ns := corev1.Resource("namespaces")
+	teamPayments := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}}
+	certManager := &metav1.LabelSelector{MatchLabels: map[string]string{"provider": "cert-manager"}}
+
+	tests := map[string]struct {
+		selectors     []apisv1alpha1.ResourceSelector
+		all           bool
+		groupResource schema.GroupResource
+		name          string
+		namespace     string
+		labels        map[string]string
+		want          bool
+		wantErr       bool
+	}{
+		"all": {
+			all:           true,
+			groupResource: configMaps,
+			name:          "foo",
+			namespace:     "default",
+			want:          true,
+		},
+		"name matches": {
+			selectors:     []apisv1alpha1.ResourceSelector{{Name: "foo"}},
+			groupResource: configMaps,
+			name:          "foo",
+			namespace:     "default",
+			want:          true,
+		},
+		"name does not match": {
+			selectors:     []apisv1alpha1.ResourceSelector{{Name: "bar"}},
+			groupResource: configMaps,
+			name:          "foo",
+			namespace:     "default",
+		},
+		"namespace matches": {
+			selectors:     []apisv1alpha1.ResourceSelector{{Namespace: "default"}},
+			groupResource: configMaps,
+			name:          "foo",
+			namespace:     "default",
+			want:          true,
+		},
+		"name and namespace, namespace does not match": {
+			selectors:     []apisv1alpha1.ResourceSelector{{Name: "foo", Namespace: "other"}},
+			groupResource: configMaps,
+			name:          "foo",
+			namespace:     "default",
+		},
+		"namespace selector matches namespace of object": {
+			selectors:     []apisv1alpha1.ResourceSelector{{NamespaceSelector: certManager}},
+			groupResource: configMaps,
+			name:          "foo",
+			namespace:     "cert",
+			want:          true,
+		},
+		"namespace selector does not match namespace of object": {
+			selectors:     []apisv1alpha1.ResourceSelector{{NamespaceSelector: certManager}},
+			groupResource: configMaps,
+			name:          "foo",
+			namespace:     "payments",
+		},
+		"namespace selector, namespace of object does not exist": {
+			selectors:     []apisv1alpha1.ResourceSelector{{NamespaceSelector: certManager}},
+			groupResource: configMaps,
+			name:          "foo",
+			namespace:     "missing",
+		},
+		"namespace selector, cluster-scoped object": {
+			selectors:     []apisv1alpha1.ResourceSelector{{NamespaceSelector: certManager}},
+			groupResource: schema.GroupResource{Group: "rbac.authorization.k8s.io", Resource: "clusterroles"},
+			name:          "foo",
+		},
+		"namespace selector matches namespace itself": {
+			selectors:     []apisv1alpha1.ResourceSelector{{NamespaceSelector: teamPayments}},
+			groupResource: ns,
+			name:          "new",
+			labels:        map[string]string{"team": "payments"},
+			want:          true,
+		},
+		"namespace selector does not match namespace itself": {
+			selectors:     []apisv1alpha1.ResourceSelector{{NamespaceSelector: teamPayments}},
+			groupResource: ns,
+			name:          "payments",
+			labels:        map[string]string{"team": "billing"},
+		},
+		"namespace name matches namespace itself": {
+			selectors:     []apisv1alpha1.ResourceSelector{{Namespace: "payments"}},
+			groupResource: ns,
+			name:          "payments",
+			want:          true,
+		},
+		"second selector matches": {
+			selectors:     []apisv1alpha1.ResourceSelector{{Namespace: "other"}, {NamespaceSelector: teamPayments}},
+			groupResource: configMaps,
+			name:          "foo",
+			namespace:     "payments",
+			want:          true,
+		},
+		"invalid namespace selector": {
+			selectors: []apisv1alpha1.ResourceSelector{{NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Foo"}},
+			}}},
+			groupResource: configMaps,
+			name:          "foo",
+			namespace:     "payments",
+			wantErr:       true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			claim := apisv1alpha1.PermissionClaim{
+				GroupResource:    apisv1alpha1.GroupResource{Group: tt.groupResource.Group, Resource: tt.groupResource.Resource},
+				All:              tt.all,
+				ResourceSelector: tt.selectors,
+			}
+			got, err := Selects(claim, tt.groupResource, tt.name, tt.namespace, tt.labels, getNamespace)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/kcp-dev/logicalcluster/v2"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apiserver/pkg/endpoints/filters"
 	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
 	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
@@ -33,7 +34,7 @@ import (
 	"github.com/kcp-dev/kcp/pkg/proxy/index"
 )
 
-func shardHandler(index index.Index, proxy http.Handler) http.HandlerFunc {
+func shardHandler(index index.Index, proxy http.Handler, wildcard http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		var cs = strings.SplitN(strings.TrimLeft(req.URL.Path, "/"), "/", 3)
 		if len(cs) != 3 || cs[0] != "clusters" {
@@ -50,6 +51,11 @@ func shardHandler(index index.Index, proxy http.Handler) http.HandlerFunc {
 		}
 
 		clusterName := logicalcluster.New(cs[1])
+		if clusterName == logicalcluster.Wildcard && req.Method == http.MethodGet {
+			// lists and watches across all logical clusters are served by all shards
+			wildcard.ServeHTTP(w, req)
+			return
+		}
 		if !tenancyhelper.IsValidCluster(clusterName) {
 			// this includes wildcards
 			logger.WithValues("path", req.URL.Path).V(4).Info("Invalid cluster name")
@@ -57,6 +63,14 @@ func shardHandler(index index.Index, proxy http.Handler) http.HandlerFunc {
 			return
 		}
 
+		if resolved := index.Resolve(clusterName); resolved != clusterName {
+			// workspace aliases are rewritten to the logical cluster they stand for
+			logger.WithValues("alias", clusterName, "clusterName", resolved).V(4).Info("Resolved workspace alias")
+			req.URL.Path = "/clusters/" + resolved.String() + "/" + cs[2]
+			req.URL.RawPath = ""
+			clusterName = resolved
+		}
+
 		shardURLString, found := index.Lookup(clusterName)
 		if !found {
 			logger.WithValues("clusterName", clusterName).V(4).Info("Unknown cluster")
@@ -69,6 +83,13 @@ func shardHandler(index index.Index, proxy http.Handler) http.HandlerFunc {
 			return
 		}
 
+		if shardName, found := shardNameForURL(index, shardURLString); found {
+			if err := translateResourceVersions(req, shardName); err != nil {
+				responsewriters.ErrorNegotiated(err, kubernetesscheme.Codecs, schema.GroupVersion{}, w, req)
+				return
+			}
+		}
+
 		logger.WithValues("from", req.URL.Path, "to", shardURL).V(4).Info("Redirecting")
 
 		ctx = WithShardURL(ctx, shardURL)
@@ -25,6 +25,7 @@ import (
 	"github.com/kcp-dev/logicalcluster/v2"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
@@ -32,6 +33,7 @@ import (
 	"k8s.io/klog/v2"
 
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancyhelper "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1/helper"
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	tenancyv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
 	tenancyv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
@@ -45,7 +47,13 @@ const (
 
 // Index implements a mapping from logical cluster to (shard) URL.
 type Index interface {
+	// Lookup returns the base URL of the shard of the given logical cluster, resolving
+	// workspace aliases.
 	Lookup(logicalCluster logicalcluster.Name) (string, bool)
+	// Resolve returns the logical cluster with all workspace aliases in its path resolved.
+	Resolve(logicalCluster logicalcluster.Name) logicalcluster.Name
+	// Shards returns the base URLs of all shards by shard name.
+	Shards() map[string]string
 }
 
 type ClusterWorkspaceClientGetter func(shard *tenancyv1alpha1.ClusterWorkspaceShard) (kcpclientset.ClusterInterface, error)
@@ -66,12 +74,16 @@ func NewController(
 
 		clusterWorkspaceShardIndexer: clusterWorkspaceShardInformer.Informer().GetIndexer(),
 		clusterWorkspaceShardLister:  clusterWorkspaceShardInformer.Lister(),
+		clusterWorkspaceShardsSynced: clusterWorkspaceShardInformer.Informer().HasSynced,
 
 		shardClusterWorkspaceInformers: map[string]cache.SharedIndexInformer{},
 		shardClusterWorkspaceStopCh:    map[string]chan struct{}{},
 
 		workspaceShardNames: map[logicalcluster.Name]string{},
 		shardBaseURLs:       map[string]string{},
+		workspaceAliases:    map[logicalcluster.Name]map[logicalcluster.Name]*metav1.Time{},
+		aliasesByWorkspace:  map[logicalcluster.Name][]logicalcluster.Name{},
+		now:                 time.Now,
 	}
 
 	c.clusterWorkspaceHandler = cache.ResourceEventHandlerFuncs{
@@ -83,9 +95,11 @@ func NewController(
 
 			if expected := ws.Status.Location.Current; got != expected {
 				c.lock.Lock()
-				defer c.lock.Unlock()
 				c.workspaceShardNames[logicalcluster.From(ws).Join(ws.Name)] = expected
+				c.lock.Unlock()
 			}
+
+			c.updateAliases(ws)
 		},
 		UpdateFunc: func(old, obj interface{}) {
 			ws := obj.(*tenancyv1alpha1.ClusterWorkspace)
@@ -96,9 +110,11 @@ func NewController(
 
 			if expected := ws.Status.Location.Current; got != expected {
 				c.lock.Lock()
-				defer c.lock.Unlock()
 				c.workspaceShardNames[logicalcluster.From(ws).Join(ws.Name)] = expected
+				c.lock.Unlock()
 			}
+
+			c.updateAliases(ws)
 		},
 		DeleteFunc: func(obj interface{}) {
 			if final, ok := obj.(cache.DeletedFinalStateUnknown); ok {
@@ -109,6 +125,7 @@ func NewController(
 			c.lock.Lock()
 			defer c.lock.Unlock()
 			delete(c.workspaceShardNames, logicalcluster.From(ws).Join(ws.Name))
+			c.setAliasesLocked(logicalcluster.From(ws).Join(ws.Name), nil)
 		},
 	}
 
@@ -169,6 +186,7 @@ type Controller struct {
 
 	clusterWorkspaceShardIndexer cache.Indexer
 	clusterWorkspaceShardLister  tenancyv1alpha1listers.ClusterWorkspaceShardLister
+	clusterWorkspaceShardsSynced cache.InformerSynced
 
 	clusterWorkspaceHandler cache.ResourceEventHandler
 
@@ -179,6 +197,12 @@ type Controller struct {
 	lock                sync.RWMutex
 	workspaceShardNames map[logicalcluster.Name]string
 	shardBaseURLs       map[string]string
+	// workspaceAliases maps alias logical clusters to the workspaces claiming them, with the
+	// expiration time of the alias.
+	workspaceAliases   map[logicalcluster.Name]map[logicalcluster.Name]*metav1.Time
+	aliasesByWorkspace map[logicalcluster.Name][]logicalcluster.Name
+
+	now func() time.Time
 }
 
 // Start the controller. It does not really do anything, but to keep the shape of a normal
@@ -201,6 +225,13 @@ func (c *Controller) Start(ctx context.Context, numThreads int) {
 		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
 	}
 
+	// entries loaded from a snapshot might point to shards deleted in the meantime.
+	if cache.WaitForCacheSync(ctx.Done(), c.clusterWorkspaceShardsSynced) {
+		if err := c.pruneDeletedShards(ctx); err != nil {
+			runtime.HandleError(fmt.Errorf("%q controller failed to prune deleted shards: %w", controllerName, err))
+		}
+	}
+
 	<-ctx.Done()
 }
 
@@ -255,8 +286,13 @@ func (c *Controller) process(ctx context.Context, key string) error {
 			c.shardInformersLock.Lock()
 			defer c.shardInformersLock.Unlock()
 
-			delete(c.shardClusterWorkspaceInformers, shard.Name)
-			delete(c.shardClusterWorkspaceStopCh, shard.Name)
+			// stop watching the ClusterWorkspaces of the deleted shard. Workspaces moved to other shards
+			// are re-routed by the informers of those shards.
+			if stopCh, found := c.shardClusterWorkspaceStopCh[name]; found {
+				close(stopCh)
+			}
+			delete(c.shardClusterWorkspaceInformers, name)
+			delete(c.shardClusterWorkspaceStopCh, name)
 
 			return nil
 		}
@@ -281,11 +317,30 @@ func (c *Controller) process(ctx context.Context, key string) error {
 		go informer.Run(stopCh)
 
 		// no need to wait. We only care about events and they arrive when they arrive.
+		// But entries loaded from a snapshot have to go once we know the truth.
+		go c.pruneStaleWorkspaces(ctx, shard.Name, informer, stopCh)
 	}
 
 	return nil
 }
 
+// Shards returns the base URLs of all shards by shard name. The root shard is always included.
+func (c *Controller) Shards() map[string]string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	shards := make(map[string]string, len(c.shardBaseURLs)+1)
+	hasRoot := false
+	for name, url := range c.shardBaseURLs {
+		shards[name] = url
+		hasRoot = hasRoot || url == c.rootHost
+	}
+	if !hasRoot {
+		shards[tenancyv1alpha1.RootShard] = c.rootHost
+	}
+	return shards
+}
+
 func (c *Controller) Lookup(logicalCluster logicalcluster.Name) (string, bool) {
 	if logicalCluster == tenancyv1alpha1.RootCluster {
 		return c.rootHost, true
@@ -294,6 +349,7 @@ func (c *Controller) Lookup(logicalCluster logicalcluster.Name) (string, bool) {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
 
+	logicalCluster = tenancyhelper.ResolveWorkspaceAliases(logicalCluster, c.lookupAliasLocked)
 	shardName, found := c.workspaceShardNames[logicalCluster]
 	if !found {
 		return "", false
@@ -301,3 +357,67 @@ func (c *Controller) Lookup(logicalCluster logicalcluster.Name) (string, bool) {
 	url, found := c.shardBaseURLs[shardName]
 	return url, found
 }
+
+// Resolve returns the logical cluster with all workspace aliases in its path resolved.
+func (c *Controller) Resolve(logicalCluster logicalcluster.Name) logicalcluster.Name {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return tenancyhelper.ResolveWorkspaceAliases(logicalCluster, c.lookupAliasLocked)
+}
+
+// lookupAliasLocked returns the workspace with the given alias. A workspace with the name of the
+// alias always wins, and aliases claimed by more than one workspace do not resolve at all.
+func (c *Controller) lookupAliasLocked(alias logicalcluster.Name) (logicalcluster.Name, bool) {
+	if _, found := c.workspaceShardNames[alias]; found {
+		return logicalcluster.Name{}, false
+	}
+
+	now := c.now()
+	var target logicalcluster.Name
+	active := 0
+	for ws, expirationTime := range c.workspaceAliases[alias] {
+		if expirationTime != nil && !expirationTime.Time.After(now) {
+			continue
+		}
+		target = ws
+		active++
+	}
+	if active != 1 {
+		return logicalcluster.Name{}, false
+	}
+	return target, true
+}
+
+func (c *Controller) updateAliases(ws *tenancyv1alpha1.ClusterWorkspace) {
+	aliases := make(map[logicalcluster.Name]*metav1.Time, len(ws.Spec.Aliases))
+	for _, alias := range ws.Spec.Aliases {
+		if alias.Name == ws.Name {
+			continue
+		}
+		aliases[logicalcluster.From(ws).Join(alias.Name)] = alias.ExpirationTime
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.setAliasesLocked(logicalcluster.From(ws).Join(ws.Name), aliases)
+}
+
+// setAliasesLocked replaces the aliases of the given workspace.
+func (c *Controller) setAliasesLocked(ws logicalcluster.Name, aliases map[logicalcluster.Name]*metav1.Time) {
+	for _, alias := range c.aliasesByWorkspace[ws] {
+		delete(c.workspaceAliases[alias], ws)
+		if len(c.workspaceAliases[alias]) == 0 {
+			delete(c.workspaceAliases, alias)
+		}
+	}
+	delete(c.aliasesByWorkspace, ws)
+
+	for alias, expirationTime := range aliases {
+		if c.workspaceAliases[alias] == nil {
+			c.workspaceAliases[alias] = map[logicalcluster.Name]*metav1.Time{}
+		}
+		c.workspaceAliases[alias][ws] = expirationTime
+		c.aliasesByWorkspace[ws] = append(c.aliasesByWorkspace[ws], alias)
+	}
+}
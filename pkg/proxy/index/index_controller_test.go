@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package index
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func workspace(parent, name string, aliases ...tenancyv1alpha1.ClusterWorkspaceAlias) *tenancyv1alpha1.ClusterWorkspace {
+	return &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: parent,
+			},
+		},
+		Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+			Aliases: aliases,
+		},
+	}
+}
+
+func TestLookupAliases(t *testing.T) {
+	now := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+	past := metav1.NewTime(now.Add(-time.Hour))
+	future := metav1.NewTime(now.Add(time.Hour))
+
+	c := newTestController()
+	c.now = func() time.Time { return now }
+	c.shardBaseURLs["alpha"] = "https://alpha"
+	c.shardBaseURLs["beta"] = "https://beta"
+	c.workspaceShardNames[logicalcluster.New("root:org")] = "alpha"
+	c.workspaceShardNames[logicalcluster.New("root:org:team")] = "beta"
+	c.workspaceShardNames[logicalcluster.New("root:org:taken")] = "alpha"
+	c.workspaceShardNames[logicalcluster.New("root:other")] = "alpha"
+
+	c.updateAliases(workspace("root", "org",
+		tenancyv1alpha1.ClusterWorkspaceAlias{Name: "old-org", ExpirationTime: &future},
+	))
+	c.updateAliases(workspace("root:org", "team",
+		tenancyv1alpha1.ClusterWorkspaceAlias{Name: "old-team"},
+		tenancyv1alpha1.ClusterWorkspaceAlias{Name: "expired-team", ExpirationTime: &past},
+		tenancyv1alpha1.ClusterWorkspaceAlias{Name: "taken"},
+		tenancyv1alpha1.ClusterWorkspaceAlias{Name: "shared"},
+	))
+	c.updateAliases(workspace("root:org", "taken",
+		tenancyv1alpha1.ClusterWorkspaceAlias{Name: "shared"},
+	))
+
+	tests := []struct {
+		cluster  string
+		resolved string
+		url      string
+	}{
+		{"root:org:team", "root:org:team", "https://beta"},
+		{"root:org:old-team", "root:org:team", "https://beta"},
+		{"root:old-org", "root:org", "https://alpha"},
+		{"root:old-org:old-team", "root:org:team", "https://beta"},
+		{"root:org:expired-team", "root:org:expired-team", ""},
+		{"root:org:taken", "root:org:taken", "https://alpha"},
+		{"root:org:shared", "root:org:shared", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cluster, func(t *testing.T) {
+			require.Equal(t, logicalcluster.New(tt.resolved), c.Resolve(logicalcluster.New(tt.cluster)))
+			url, found := c.Lookup(logicalcluster.New(tt.cluster))
+			require.Equal(t, tt.url != "", found)
+			require.Equal(t, tt.url, url)
+		})
+	}
+
+	// aliases dropped from the spec are removed from the index
+	c.updateAliases(workspace("root:org", "team"))
+	require.Equal(t, logicalcluster.New("root:org:old-team"), c.Resolve(logicalcluster.New("root:org:old-team")))
+	require.Equal(t, logicalcluster.New("root:org:taken"), c.Resolve(logicalcluster.New("root:org:shared")))
+	require.NotContains(t, c.aliasesByWorkspace, logicalcluster.New("root:org:team"))
+}
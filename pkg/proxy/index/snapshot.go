@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// snapshotVersion is bumped whenever the on-disk format changes incompatibly.
+const snapshotVersion = 1
+
+// snapshot is the on-disk format of the index. It is loaded on startup to serve
+// lookups before the ClusterWorkspace informers of all shards have synced.
+type snapshot struct {
+	Version             int               `json:"version"`
+	WorkspaceShardNames map[string]string `json:"workspaceShardNames"`
+	ShardBaseURLs       map[string]string `json:"shardBaseURLs"`
+}
+
+// LoadSnapshot seeds the index from a snapshot file written by SaveSnapshot. Entries
+// already known to the index take precedence. A missing file is not an error.
+func (c *Controller) LoadSnapshot(path string) error {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var s snapshot
+	if err := json.Unmarshal(bs, &s); err != nil {
+		return fmt.Errorf("failed to decode index snapshot %q: %w", path, err)
+	}
+	if s.Version != snapshotVersion {
+		return fmt.Errorf("unsupported index snapshot version %d in %q", s.Version, path)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for ws, shardName := range s.WorkspaceShardNames {
+		name := logicalcluster.New(ws)
+		if _, found := c.workspaceShardNames[name]; !found {
+			c.workspaceShardNames[name] = shardName
+		}
+	}
+	for shardName, baseURL := range s.ShardBaseURLs {
+		if _, found := c.shardBaseURLs[shardName]; !found {
+			c.shardBaseURLs[shardName] = baseURL
+		}
+	}
+
+	return nil
+}
+
+// SaveSnapshot atomically writes the current index to path.
+func (c *Controller) SaveSnapshot(path string) error {
+	s := snapshot{
+		Version:             snapshotVersion,
+		WorkspaceShardNames: map[string]string{},
+		ShardBaseURLs:       map[string]string{},
+	}
+
+	c.lock.RLock()
+	for ws, shardName := range c.workspaceShardNames {
+		s.WorkspaceShardNames[ws.String()] = shardName
+	}
+	for shardName, baseURL := range c.shardBaseURLs {
+		s.ShardBaseURLs[shardName] = baseURL
+	}
+	c.lock.RUnlock()
+
+	bs, err := json.Marshal(&s)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	if _, err := tmp.Write(bs); err != nil {
+		tmp.Close() //nolint:errcheck
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// StartSnapshotting periodically writes the index to path until the context is done,
+// and once more on shutdown.
+func (c *Controller) StartSnapshotting(ctx context.Context, path string, interval time.Duration) {
+	logger := klog.FromContext(ctx).WithValues("controller", controllerName, "path", path)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.SaveSnapshot(path); err != nil {
+			logger.Error(err, "failed to write index snapshot")
+		}
+	}, interval)
+
+	if err := c.SaveSnapshot(path); err != nil {
+		logger.Error(err, "failed to write index snapshot on shutdown")
+	}
+}
+
+// pruneStaleWorkspaces removes the index entries pointing to the given shard that are not
+// backed by a ClusterWorkspace in the synced informer. These can only stem from a snapshot,
+// and would never be removed by a delete event otherwise.
+func (c *Controller) pruneStaleWorkspaces(ctx context.Context, shardName string, informer cache.SharedIndexInformer, stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, informer.HasSynced) {
+		return
+	}
+
+	// list under the lock, such that entries added by the event handlers in the meantime are
+	// known to the store and are not pruned.
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	existing := map[logicalcluster.Name]bool{}
+	for _, obj := range informer.GetStore().List() {
+		ws, ok := obj.(*tenancyv1alpha1.ClusterWorkspace)
+		if !ok {
+			continue
+		}
+		existing[logicalcluster.From(ws).Join(ws.Name)] = true
+	}
+
+	pruned := 0
+	for ws, name := range c.workspaceShardNames {
+		if name == shardName && !existing[ws] {
+			delete(c.workspaceShardNames, ws)
+			pruned++
+		}
+	}
+
+	if pruned > 0 {
+		klog.FromContext(ctx).WithValues("controller", controllerName, "shard", shardName, "pruned", pruned).V(2).Info("pruned stale index entries")
+	}
+}
+
+// pruneDeletedShards removes the index entries pointing to shards that do not exist anymore.
+// Shards deleted while the proxy was down are only known from a snapshot, and neither get an
+// informer nor a delete event that would remove their entries.
+func (c *Controller) pruneDeletedShards(ctx context.Context) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	shards, err := c.clusterWorkspaceShardLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	live := sets.NewString()
+	for _, shard := range shards {
+		live.Insert(shard.Name)
+	}
+
+	pruned := 0
+	for ws, name := range c.workspaceShardNames {
+		if !live.Has(name) {
+			delete(c.workspaceShardNames, ws)
+			pruned++
+		}
+	}
+	for name := range c.shardBaseURLs {
+		if !live.Has(name) {
+			delete(c.shardBaseURLs, name)
+		}
+	}
+
+	if pruned > 0 {
+		klog.FromContext(ctx).WithValues("controller", controllerName, "pruned", pruned).V(2).Info("pruned index entries of deleted shards")
+	}
+	return nil
+}
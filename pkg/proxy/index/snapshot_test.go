@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package index
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancyv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+)
+
+func newTestController() *Controller {
+	return &Controller{
+		rootHost:            "https://root",
+		workspaceShardNames: map[logicalcluster.Name]string{},
+		shardBaseURLs:       map[string]string{},
+		workspaceAliases:    map[logicalcluster.Name]map[logicalcluster.Name]*metav1.Time{},
+		aliasesByWorkspace:  map[logicalcluster.Name][]logicalcluster.Name{},
+		now:                 time.Now,
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	saved := newTestController()
+	saved.workspaceShardNames[logicalcluster.New("root:org")] = "alpha"
+	saved.workspaceShardNames[logicalcluster.New("root:org:team")] = "beta"
+	saved.shardBaseURLs["alpha"] = "https://alpha"
+	saved.shardBaseURLs["beta"] = "https://beta"
+	require.NoError(t, saved.SaveSnapshot(path))
+
+	loaded := newTestController()
+	loaded.workspaceShardNames[logicalcluster.New("root:org")] = "gamma"
+	loaded.shardBaseURLs["gamma"] = "https://gamma"
+	require.NoError(t, loaded.LoadSnapshot(path))
+
+	url, found := loaded.Lookup(logicalcluster.New("root:org:team"))
+	require.True(t, found)
+	require.Equal(t, "https://beta", url)
+
+	// live entries win over the snapshot
+	url, found = loaded.Lookup(logicalcluster.New("root:org"))
+	require.True(t, found)
+	require.Equal(t, "https://gamma", url)
+}
+
+func TestLoadSnapshotMissingFile(t *testing.T) {
+	c := newTestController()
+	require.NoError(t, c.LoadSnapshot(filepath.Join(t.TempDir(), "does-not-exist.json")))
+	require.Empty(t, c.workspaceShardNames)
+}
+
+func TestPruneStaleWorkspaces(t *testing.T) {
+	c := newTestController()
+	c.workspaceShardNames[logicalcluster.New("root:org")] = "alpha"
+	c.workspaceShardNames[logicalcluster.New("root:org:gone")] = "alpha"
+	c.workspaceShardNames[logicalcluster.New("root:other")] = "beta"
+
+	informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return &tenancyv1alpha1.ClusterWorkspaceList{Items: []tenancyv1alpha1.ClusterWorkspace{*workspace("root", "org")}}, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return watch.NewFake(), nil
+		},
+	}, &tenancyv1alpha1.ClusterWorkspace{}, 0, cache.Indexers{})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go informer.Run(stopCh)
+
+	c.pruneStaleWorkspaces(context.Background(), "alpha", informer, stopCh)
+
+	require.Equal(t, map[logicalcluster.Name]string{
+		logicalcluster.New("root:org"):   "alpha",
+		logicalcluster.New("root:other"): "beta",
+	}, c.workspaceShardNames)
+}
+
+func TestPruneDeletedShards(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(&tenancyv1alpha1.ClusterWorkspaceShard{ObjectMeta: metav1.ObjectMeta{Name: "alpha"}}))
+
+	c := newTestController()
+	c.clusterWorkspaceShardLister = tenancyv1alpha1listers.NewClusterWorkspaceShardLister(indexer)
+	c.shardBaseURLs["alpha"] = "https://alpha"
+	c.shardBaseURLs["deleted"] = "https://deleted"
+	c.workspaceShardNames[logicalcluster.New("root:org")] = "alpha"
+	c.workspaceShardNames[logicalcluster.New("root:org:team")] = "deleted"
+
+	require.NoError(t, c.pruneDeletedShards(context.Background()))
+
+	require.Equal(t, map[string]string{"alpha": "https://alpha"}, c.shardBaseURLs)
+	require.Equal(t, map[logicalcluster.Name]string{logicalcluster.New("root:org"): "alpha"}, c.workspaceShardNames)
+	_, found := c.Lookup(logicalcluster.New("root:org:team"))
+	require.False(t, found)
+}
@@ -91,7 +91,7 @@ func NewHandler(ctx context.Context, o *proxyoptions.Options, index index.Index)
 		if m.Path == "/clusters/" {
 			clusterProxy := newShardReverseProxy()
 			clusterProxy.Transport = transport
-			handler = shardHandler(index, clusterProxy)
+			handler = shardHandler(index, clusterProxy, newWildcardHandler(index, transport))
 		} else {
 			// TODO: handle virtual workspace apiservers per shard
 			proxy := httputil.NewSingleHostReverseProxy(u)
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/pflag"
 
@@ -33,6 +34,9 @@ type Options struct {
 	RootDirectory   string
 	RootKubeconfig  string
 	ProfilerAddress string
+
+	IndexSnapshotFile     string
+	IndexSnapshotInterval time.Duration
 }
 
 func NewOptions() *Options {
@@ -41,6 +45,8 @@ func NewOptions() *Options {
 		Authentication: *NewAuthentication(),
 		RootKubeconfig: "",
 		RootDirectory:  ".kcp",
+
+		IndexSnapshotInterval: time.Minute,
 	}
 
 	// override all the things
@@ -57,6 +63,8 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.RootDirectory, "root-directory", o.RootDirectory, "Root directory.")
 	fs.StringVar(&o.RootKubeconfig, "root-kubeconfig", o.RootKubeconfig, "The path to the kubeconfig of the root shard.")
 	fs.StringVar(&o.ProfilerAddress, "profiler-address", "", "[Address]:port to bind the profiler to")
+	fs.StringVar(&o.IndexSnapshotFile, "index-snapshot-file", o.IndexSnapshotFile, "File to persist the workspace to shard index to, and to load it from on startup before the shard informers have synced. Relative to --root-directory if not absolute. Empty disables snapshots.")
+	fs.DurationVar(&o.IndexSnapshotInterval, "index-snapshot-interval", o.IndexSnapshotInterval, "Interval in which the workspace to shard index is written to --index-snapshot-file.")
 }
 
 func (o *Options) Complete() error {
@@ -68,6 +76,10 @@ func (o *Options) Complete() error {
 		o.RootDirectory = filepath.Join(pwd, o.RootDirectory)
 	}
 
+	if o.IndexSnapshotFile != "" && !filepath.IsAbs(o.IndexSnapshotFile) {
+		o.IndexSnapshotFile = filepath.Join(o.RootDirectory, o.IndexSnapshotFile)
+	}
+
 	if len(o.SecureServing.ServerCert.CertDirectory) == 0 {
 		o.SecureServing.ServerCert.CertDirectory = o.RootDirectory
 	}
@@ -89,6 +101,10 @@ func (o *Options) Validate() []error {
 		errs = append(errs, fmt.Errorf("--mapping-file is required"))
 	}
 
+	if o.IndexSnapshotFile != "" && o.IndexSnapshotInterval <= 0 {
+		errs = append(errs, fmt.Errorf("--index-snapshot-interval must be positive"))
+	}
+
 	errs = append(errs, o.SecureServing.Validate()...)
 	errs = append(errs, o.Authentication.Validate()...)
 
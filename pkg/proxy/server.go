@@ -101,6 +101,15 @@ func (s preparedServer) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to get or create identities: %w", err)
 	}
 
+	// seed the index from the last snapshot, such that requests can be routed before
+	// the ClusterWorkspace informers of the shards have synced.
+	if snapshotFile := s.CompletedConfig.Options.IndexSnapshotFile; snapshotFile != "" {
+		if err := s.IndexController.LoadSnapshot(snapshotFile); err != nil {
+			logger.Error(err, "failed to load index snapshot, starting with an empty index")
+		}
+		go s.IndexController.StartSnapshotting(ctx, snapshotFile, s.CompletedConfig.Options.IndexSnapshotInterval)
+	}
+
 	// start index
 	go s.IndexController.Start(ctx, 2)
 
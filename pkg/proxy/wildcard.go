@@ -0,0 +1,497 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/proxy/index"
+)
+
+// compositeResourceVersionPrefix marks a resourceVersion handed out by the front-proxy for a wildcard
+// list or watch. It is followed by the base64 encoded JSON map of shard names to the resourceVersions
+// of those shards.
+const compositeResourceVersionPrefix = "kcp."
+
+// compositeResourceVersion is the position of a wildcard list or watch across all shards. Every shard
+// has its own resourceVersion space, and the logical clusters on a shard share it. Hence, the
+// resourceVersions of all shards together are a consistent resume point for a watch across all
+// logical clusters.
+type compositeResourceVersion map[string]string
+
+// Encode returns the opaque string representation of the composite resourceVersion.
+func (rv compositeResourceVersion) Encode() string {
+	bs, err := json.Marshal(map[string]string(rv))
+	if err != nil {
+		// cannot happen for a map of strings
+		panic(err)
+	}
+	return compositeResourceVersionPrefix + base64.RawURLEncoding.EncodeToString(bs)
+}
+
+// decodeCompositeResourceVersion parses a composite resourceVersion. It returns false if the given
+// resourceVersion is not a composite one.
+func decodeCompositeResourceVersion(s string) (compositeResourceVersion, bool, error) {
+	if !strings.HasPrefix(s, compositeResourceVersionPrefix) {
+		return nil, false, nil
+	}
+	bs, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(s, compositeResourceVersionPrefix))
+	if err != nil {
+		return nil, true, fmt.Errorf("invalid resourceVersion %q: %w", s, err)
+	}
+	rv := compositeResourceVersion{}
+	if err := json.Unmarshal(bs, &rv); err != nil {
+		return nil, true, fmt.Errorf("invalid resourceVersion %q: %w", s, err)
+	}
+	return rv, true, nil
+}
+
+// with returns a copy of the composite resourceVersion with the given shard at the given resourceVersion.
+func (rv compositeResourceVersion) with(shard, resourceVersion string) compositeResourceVersion {
+	ret := make(compositeResourceVersion, len(rv)+1)
+	for k, v := range rv {
+		ret[k] = v
+	}
+	ret[shard] = resourceVersion
+	return ret
+}
+
+// wildcardHandler serves list and watch requests across all logical clusters, i.e. for the
+// wildcard cluster "*", by fanning them out to all shards.
+//
+// The resourceVersions of the returned lists, list items and watch events are composite, and
+// encode the resourceVersions of all shards. A watch started from such a resourceVersion resumes
+// every shard at its own position, without relisting. Shards added after the resourceVersion was
+// handed out are watched from the beginning. Shard bookmarks are forwarded with the composite
+// resourceVersion, such that clients can resume from them too.
+//
+// A watch from a resourceVersion that is neither composite, nor empty, nor "0" is answered as
+// expired, because it cannot be mapped to the shards.
+type wildcardHandler struct {
+	index  index.Index
+	client *http.Client
+}
+
+func newWildcardHandler(index index.Index, transport http.RoundTripper) *wildcardHandler {
+	return &wildcardHandler{
+		index:  index,
+		client: &http.Client{Transport: transport},
+	}
+}
+
+func isWildcardWatch(req *http.Request) bool {
+	if w := req.URL.Query().Get("watch"); w == "true" || w == "1" {
+		return true
+	}
+	return strings.Contains(req.URL.Path, "/watch/")
+}
+
+func (h *wildcardHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	shards := h.index.Shards()
+	from := compositeResourceVersion{}
+	if rv := req.URL.Query().Get("resourceVersion"); rv != "" && rv != "0" {
+		composite, ok, err := decodeCompositeResourceVersion(rv)
+		if err != nil {
+			responsewriters.ErrorNegotiated(apierrors.NewBadRequest(err.Error()), kubernetesscheme.Codecs, schema.GroupVersion{}, w, req)
+			return
+		}
+		if !ok {
+			responsewriters.ErrorNegotiated(apierrors.NewResourceExpired(fmt.Sprintf("resourceVersion %q cannot be resumed across shards", rv)), kubernetesscheme.Codecs, schema.GroupVersion{}, w, req)
+			return
+		}
+		for shard := range shards {
+			// watch shards unknown to the client from the beginning
+			if shardRV, found := composite[shard]; found {
+				from[shard] = shardRV
+			} else {
+				from[shard] = ""
+			}
+		}
+	}
+
+	if isWildcardWatch(req) {
+		h.watch(ctx, w, req, shards, from)
+		return
+	}
+	h.list(ctx, w, req, shards, from)
+}
+
+// shardRequest returns a copy of the request against the given shard, starting from the given resourceVersion.
+func shardRequest(ctx context.Context, req *http.Request, shardURL, resourceVersion string, resourceVersionGiven bool) (*http.Request, error) {
+	u, err := url.Parse(shardURL)
+	if err != nil {
+		return nil, err
+	}
+
+	query := req.URL.Query()
+	// pagination continue tokens are per shard and cannot be merged
+	query.Del("limit")
+	query.Del("continue")
+	if resourceVersionGiven {
+		query.Set("resourceVersion", resourceVersion)
+		if resourceVersion == "" {
+			query.Del("resourceVersion")
+		}
+	}
+
+	shardReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	shardReq.URL.Scheme = u.Scheme
+	shardReq.URL.Host = u.Host
+	shardReq.URL.RawQuery = query.Encode()
+	shardReq.Header = req.Header.Clone()
+	shardReq.Header.Set("Accept", "application/json")
+	shardReq.Header.Del("Accept-Encoding")
+	return shardReq, nil
+}
+
+func (h *wildcardHandler) list(ctx context.Context, w http.ResponseWriter, req *http.Request, shards map[string]string, from compositeResourceVersion) {
+	logger := klog.FromContext(ctx)
+
+	type shardList struct {
+		shard string
+		list  *unstructured.UnstructuredList
+		err   error
+	}
+	results := make(chan shardList, len(shards))
+	for shard, shardURL := range shards {
+		go func(shard, shardURL string) {
+			list, err := h.listShard(ctx, req, shardURL, from[shard], len(from) > 0)
+			results <- shardList{shard: shard, list: list, err: err}
+		}(shard, shardURL)
+	}
+
+	var merged *unstructured.UnstructuredList
+	composite := compositeResourceVersion{}
+	lists := map[string]*unstructured.UnstructuredList{}
+	for range shards {
+		result := <-results
+		if result.err != nil {
+			logger.WithValues("shard", result.shard).Error(result.err, "failed to list wildcard request on shard")
+			responsewriters.ErrorNegotiated(result.err, kubernetesscheme.Codecs, schema.GroupVersion{}, w, req)
+			return
+		}
+		lists[result.shard] = result.list
+		composite[result.shard] = result.list.GetResourceVersion()
+	}
+
+	// merge in a stable order of shards
+	names := make([]string, 0, len(lists))
+	for shard := range lists {
+		names = append(names, shard)
+	}
+	sort.Strings(names)
+	for _, shard := range names {
+		list := lists[shard]
+		if merged == nil {
+			merged = &unstructured.UnstructuredList{Object: list.Object}
+			merged.SetContinue("")
+		}
+		for i := range list.Items {
+			item := list.Items[i]
+			item.SetResourceVersion(composite.with(shard, item.GetResourceVersion()).Encode())
+			merged.Items = append(merged.Items, item)
+		}
+	}
+	if merged == nil {
+		responsewriters.ErrorNegotiated(apierrors.NewServiceUnavailable("no shards available"), kubernetesscheme.Codecs, schema.GroupVersion{}, w, req)
+		return
+	}
+	merged.SetResourceVersion(composite.Encode())
+
+	bs, err := merged.MarshalJSON()
+	if err != nil {
+		responsewriters.InternalError(w, req, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(bs) //nolint:errcheck
+}
+
+func (h *wildcardHandler) listShard(ctx context.Context, req *http.Request, shardURL, resourceVersion string, resourceVersionGiven bool) (*unstructured.UnstructuredList, error) {
+	shardReq, err := shardRequest(ctx, req, shardURL, resourceVersion, resourceVersionGiven)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.client.Do(shardReq)
+	if err != nil {
+		return nil, apierrors.NewServiceUnavailable(err.Error())
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, apierrors.NewServiceUnavailable(err.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, bs)
+	}
+
+	list := &unstructured.UnstructuredList{}
+	if err := list.UnmarshalJSON(bs); err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	return list, nil
+}
+
+// statusError turns a non-success response of a shard into an API error.
+func statusError(code int, body []byte) error {
+	status := &apierrors.StatusError{}
+	if err := json.Unmarshal(body, &status.ErrStatus); err == nil && status.ErrStatus.Code != 0 {
+		return status
+	}
+	return apierrors.NewGenericServerResponse(code, "", schema.GroupResource{}, "", string(body), 0, true)
+}
+
+// watchEvent is a watch event as it is streamed by the shards.
+type watchEvent struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+func (h *wildcardHandler) watch(ctx context.Context, w http.ResponseWriter, req *http.Request, shards map[string]string, from compositeResourceVersion) {
+	logger := klog.FromContext(ctx)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		responsewriters.InternalError(w, req, fmt.Errorf("unable to start watch - can't get http.Flusher: %#v", w))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// open all shard watches before committing to a response, such that errors are reported as such
+	bodies := map[string]io.ReadCloser{}
+	defer func() {
+		for _, body := range bodies {
+			body.Close()
+		}
+	}()
+	for shard, shardURL := range shards {
+		shardReq, err := shardRequest(ctx, req, shardURL, from[shard], len(from) > 0)
+		if err != nil {
+			responsewriters.InternalError(w, req, err)
+			return
+		}
+		resp, err := h.client.Do(shardReq)
+		if err != nil {
+			logger.WithValues("shard", shard).Error(err, "failed to watch wildcard request on shard")
+			responsewriters.ErrorNegotiated(apierrors.NewServiceUnavailable(err.Error()), kubernetesscheme.Codecs, schema.GroupVersion{}, w, req)
+			return
+		}
+		if resp.StatusCode != http.StatusOK {
+			bs, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			responsewriters.ErrorNegotiated(statusError(resp.StatusCode, bs), kubernetesscheme.Codecs, schema.GroupVersion{}, w, req)
+			return
+		}
+		bodies[shard] = resp.Body
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	var lock sync.Mutex
+	composite := make(compositeResourceVersion, len(from))
+	for shard, rv := range from {
+		composite[shard] = rv
+	}
+	encoder := json.NewEncoder(w)
+
+	// send writes the event of the given shard with the composite resourceVersion advanced to the event.
+	// It returns false if the watch is over.
+	send := func(shard string, event *watchEvent) bool {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if ctx.Err() != nil {
+			return false
+		}
+		if event.Type != "ERROR" {
+			obj := &unstructured.Unstructured{}
+			if err := obj.UnmarshalJSON(event.Object); err != nil {
+				logger.WithValues("shard", shard).Error(err, "failed to decode watch event")
+				return false
+			}
+			composite[shard] = obj.GetResourceVersion()
+			obj.SetResourceVersion(composite.Encode())
+			bs, err := obj.MarshalJSON()
+			if err != nil {
+				logger.WithValues("shard", shard).Error(err, "failed to encode watch event")
+				return false
+			}
+			event.Object = bs
+		}
+		if err := encoder.Encode(event); err != nil {
+			return false
+		}
+		flusher.Flush()
+
+		// an error like an expired resourceVersion ends the watch on the shard, and hence the whole watch
+		return event.Type != "ERROR"
+	}
+
+	var wg sync.WaitGroup
+	for shard, body := range bodies {
+		wg.Add(1)
+		go func(shard string, body io.Reader) {
+			defer wg.Done()
+			// any shard ending its watch ends the whole watch. The client resumes from the last
+			// composite resourceVersion it has seen.
+			defer cancel()
+
+			decoder := json.NewDecoder(body)
+			for {
+				event := &watchEvent{}
+				if err := decoder.Decode(event); err != nil {
+					if err != io.EOF && ctx.Err() == nil {
+						logger.WithValues("shard", shard).V(4).Info("wildcard watch on shard ended", "err", err)
+					}
+					return
+				}
+				if !send(shard, event) {
+					return
+				}
+			}
+		}(shard, body)
+	}
+
+	// shard requests are aborted with the context
+	<-ctx.Done()
+	wg.Wait()
+}
+
+// shardNameForURL returns the name of the shard with the given base URL.
+func shardNameForURL(index index.Index, shardURL string) (string, bool) {
+	for name, u := range index.Shards() {
+		if u == shardURL {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// translateResourceVersions replaces composite resourceVersions in a request against a single logical
+// cluster, e.g. of objects read through a wildcard informer, with the resourceVersion of the given shard.
+// It covers the resourceVersion query parameter and the resourceVersion of the object or the delete
+// preconditions in JSON bodies.
+func translateResourceVersions(req *http.Request, shard string) error {
+	query := req.URL.Query()
+	if rv := query.Get("resourceVersion"); rv != "" {
+		composite, ok, err := decodeCompositeResourceVersion(rv)
+		if err != nil {
+			return apierrors.NewBadRequest(err.Error())
+		}
+		if ok {
+			if shardRV := composite[shard]; shardRV != "" {
+				query.Set("resourceVersion", shardRV)
+			} else {
+				query.Del("resourceVersion")
+			}
+			req.URL.RawQuery = query.Encode()
+		}
+	}
+
+	if req.Body == nil || req.ContentLength == 0 {
+		return nil
+	}
+	switch req.Method {
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return nil //nolint:nilerr // leave it to the shard to reject
+	}
+	switch mediaType {
+	case "application/json", "application/merge-patch+json", "application/strategic-merge-patch+json":
+	default:
+		return nil
+	}
+
+	bs, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return apierrors.NewBadRequest(err.Error())
+	}
+	req.Body = io.NopCloser(bytes.NewReader(bs))
+	if !bytes.Contains(bs, []byte(compositeResourceVersionPrefix)) {
+		return nil
+	}
+
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(bs, &obj); err != nil {
+		return nil //nolint:nilerr // leave it to the shard to reject
+	}
+	changed := false
+	for _, fields := range [][]string{{"metadata", "resourceVersion"}, {"preconditions", "resourceVersion"}} {
+		rv, found, err := unstructured.NestedString(obj, fields...)
+		if err != nil || !found {
+			continue
+		}
+		composite, ok, err := decodeCompositeResourceVersion(rv)
+		if err != nil {
+			return apierrors.NewBadRequest(err.Error())
+		}
+		if !ok {
+			continue
+		}
+		if err := unstructured.SetNestedField(obj, composite[shard], fields...); err != nil {
+			return apierrors.NewBadRequest(err.Error())
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	bs, err = json.Marshal(obj)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(bs))
+	req.ContentLength = int64(len(bs))
+	req.Header.Set("Content-Length", strconv.Itoa(len(bs)))
+	return nil
+}
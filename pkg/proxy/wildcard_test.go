@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type fakeIndex map[string]string
+
+func (f fakeIndex) Lookup(logicalCluster logicalcluster.Name) (string, bool) {
+	return "", false
+}
+
+func (f fakeIndex) Resolve(logicalCluster logicalcluster.Name) logicalcluster.Name {
+	return logicalCluster
+}
+
+func (f fakeIndex) Shards() map[string]string {
+	return f
+}
+
+func TestCompositeResourceVersion(t *testing.T) {
+	rv := compositeResourceVersion{"root": "42", "beta": "7"}
+
+	decoded, ok, err := decodeCompositeResourceVersion(rv.Encode())
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, rv, decoded)
+
+	_, ok, err = decodeCompositeResourceVersion("42")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, ok, err = decodeCompositeResourceVersion(compositeResourceVersionPrefix + "!")
+	require.Error(t, err)
+	require.True(t, ok)
+}
+
+func TestWildcardList(t *testing.T) {
+	shard := func(name, listRV string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			require.Empty(t, req.URL.Query().Get("limit"))
+			if rv := req.URL.Query().Get("resourceVersion"); rv != "" {
+				// resumed lists ask every shard from its own resourceVersion
+				require.Equal(t, listRV, rv)
+			}
+			fmt.Fprintf(w, `{"apiVersion":"v1","kind":"ConfigMapList","metadata":{"resourceVersion":%q},"items":[{"metadata":{"name":%q,"resourceVersion":"1"}}]}`, listRV, name)
+		}))
+	}
+	alpha := shard("alpha", "10")
+	defer alpha.Close()
+	beta := shard("beta", "20")
+	defer beta.Close()
+
+	handler := newWildcardHandler(fakeIndex{"alpha": alpha.URL, "beta": beta.URL}, http.DefaultTransport)
+
+	list := func(resourceVersion string) *unstructured.UnstructuredList {
+		req := httptest.NewRequest(http.MethodGet, "/clusters/*/api/v1/configmaps?limit=500&resourceVersion="+resourceVersion, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		list := &unstructured.UnstructuredList{}
+		require.NoError(t, list.UnmarshalJSON(rec.Body.Bytes()))
+		return list
+	}
+
+	got := list("")
+	require.Len(t, got.Items, 2)
+	rv, ok, err := decodeCompositeResourceVersion(got.GetResourceVersion())
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, compositeResourceVersion{"alpha": "10", "beta": "20"}, rv)
+
+	// items carry the resourceVersion of their own shard
+	itemRV, _, err := decodeCompositeResourceVersion(got.Items[1].GetResourceVersion())
+	require.NoError(t, err)
+	require.Equal(t, "1", itemRV["beta"])
+
+	require.Len(t, list(got.GetResourceVersion()).Items, 2)
+
+	// plain resourceVersions cannot be resumed across shards
+	req := httptest.NewRequest(http.MethodGet, "/clusters/*/api/v1/configmaps?resourceVersion=10", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusGone, rec.Code)
+}
+
+func TestWildcardWatch(t *testing.T) {
+	alpha := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "10", req.URL.Query().Get("resourceVersion"))
+		fmt.Fprintln(w, `{"type":"ADDED","object":{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"a","resourceVersion":"11"}}}`)
+		fmt.Fprintln(w, `{"type":"BOOKMARK","object":{"apiVersion":"v1","kind":"ConfigMap","metadata":{"resourceVersion":"12"}}}`)
+	}))
+	defer alpha.Close()
+	beta := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// beta is unknown to the client, and hence watched from the beginning
+		require.Empty(t, req.URL.Query().Get("resourceVersion"))
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-req.Context().Done()
+	}))
+	defer beta.Close()
+
+	handler := newWildcardHandler(fakeIndex{"alpha": alpha.URL, "beta": beta.URL}, http.DefaultTransport)
+
+	from := compositeResourceVersion{"alpha": "10"}.Encode()
+	req := httptest.NewRequest(http.MethodGet, "/clusters/*/api/v1/configmaps?watch=true&resourceVersion="+from, nil)
+	rec := httptest.NewRecorder()
+	// the watch ends when the stream of alpha ends
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var types []string
+	var rvs []compositeResourceVersion
+	decoder := json.NewDecoder(rec.Body)
+	for {
+		event := &watchEvent{}
+		err := decoder.Decode(event)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		obj := &unstructured.Unstructured{}
+		require.NoError(t, obj.UnmarshalJSON(event.Object))
+		rv, ok, err := decodeCompositeResourceVersion(obj.GetResourceVersion())
+		require.NoError(t, err)
+		require.True(t, ok)
+		types = append(types, event.Type)
+		rvs = append(rvs, rv)
+	}
+
+	require.Equal(t, []string{"ADDED", "BOOKMARK"}, types)
+	require.Equal(t, []compositeResourceVersion{{"alpha": "11", "beta": ""}, {"alpha": "12", "beta": ""}}, rvs)
+}
+
+func TestTranslateResourceVersions(t *testing.T) {
+	rv := compositeResourceVersion{"alpha": "10", "beta": "20"}.Encode()
+
+	req := httptest.NewRequest(http.MethodPut, "/clusters/root:org/api/v1/namespaces/default/configmaps/cm", strings.NewReader(`{"metadata":{"name":"cm","resourceVersion":"`+rv+`"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	require.NoError(t, translateResourceVersions(req, "beta"))
+
+	bs, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	obj := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(bs, &obj))
+	got, _, err := unstructured.NestedString(obj, "metadata", "resourceVersion")
+	require.NoError(t, err)
+	require.Equal(t, "20", got)
+	require.Equal(t, int64(len(bs)), req.ContentLength)
+
+	req = httptest.NewRequest(http.MethodGet, "/clusters/root:org/api/v1/configmaps?watch=true&resourceVersion="+rv, nil)
+	require.NoError(t, translateResourceVersions(req, "alpha"))
+	require.Equal(t, "10", req.URL.Query().Get("resourceVersion"))
+}
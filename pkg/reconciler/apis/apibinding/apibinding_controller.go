@@ -53,6 +53,11 @@ import (
 
 const (
 	ControllerName = "kcp-apibinding"
+
+	// relatedObjectBatchPeriod is the time APIBindings are enqueued after because of changes to related objects
+	// like APIExports, APIResourceSchemas and CRDs. Those changes tend to come in bursts, e.g. when many bound CRDs
+	// get established. Delaying the enqueue coalesces them into one reconcile, and hence one status patch.
+	relatedObjectBatchPeriod = 200 * time.Millisecond
 )
 
 var (
@@ -60,6 +65,10 @@ var (
 )
 
 // NewController returns a new controller for APIBindings.
+//
+// APIExports and APIResourceSchemas are looked up on the local shard first, then on the root shard, and finally
+// in the cache server if cacheApiExportInformer and cacheApiResourceSchemaInformer are non-nil. The latter makes
+// APIBindings to APIExports living on any other shard work, as the cache server replicates them from all shards.
 func NewController(
 	crdClusterClient kcpapiextensionsclientset.ClusterInterface,
 	kcpClusterClient kcpclientset.ClusterInterface,
@@ -70,8 +79,25 @@ func NewController(
 	apiResourceSchemaInformer apisv1alpha1informers.APIResourceSchemaClusterInformer,
 	temporaryRemoteShardApiExportInformer apisv1alpha1informers.APIExportClusterInformer, /*TODO(p0lyn0mial): replace with multi-shard informers*/
 	temporaryRemoteShardApiResourceSchemaInformer apisv1alpha1informers.APIResourceSchemaClusterInformer, /*TODO(p0lyn0mial): replace with multi-shard informers*/
+	cacheApiExportInformer apisv1alpha1informers.APIExportClusterInformer,
+	cacheApiResourceSchemaInformer apisv1alpha1informers.APIResourceSchemaClusterInformer,
 	crdInformer kcpapiextensionsv1informers.CustomResourceDefinitionClusterInformer,
 ) (*controller, error) {
+	apiExportListers := []apisv1alpha1listers.APIExportClusterLister{
+		apiExportInformer.Lister(),
+		temporaryRemoteShardApiExportInformer.Lister(),
+	}
+	apiResourceSchemaListers := []apisv1alpha1listers.APIResourceSchemaClusterLister{
+		apiResourceSchemaInformer.Lister(),
+		temporaryRemoteShardApiResourceSchemaInformer.Lister(),
+	}
+	var cacheApiExportsIndexer cache.Indexer
+	if cacheApiExportInformer != nil && cacheApiResourceSchemaInformer != nil {
+		apiExportListers = append(apiExportListers, cacheApiExportInformer.Lister())
+		apiResourceSchemaListers = append(apiResourceSchemaListers, cacheApiResourceSchemaInformer.Lister())
+		cacheApiExportsIndexer = cacheApiExportInformer.Informer().GetIndexer()
+	}
+
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
 
 	c := &controller{
@@ -103,26 +129,25 @@ func NewController(
 		apiBindingsIndexer: apiBindingInformer.Informer().GetIndexer(),
 
 		getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
-			apiExport, err := apiExportInformer.Lister().Cluster(clusterName).Get(name)
-			if errors.IsNotFound(err) {
-				return temporaryRemoteShardApiExportInformer.Lister().Cluster(clusterName).Get(name)
-			}
-			return apiExport, err
+			return getAPIExportFromListers(apiExportListers, clusterName, name)
 		},
 		apiExportsIndexer:                     apiExportInformer.Informer().GetIndexer(),
 		temporaryRemoteShardApiExportsIndexer: temporaryRemoteShardApiExportInformer.Informer().GetIndexer(),
+		cacheApiExportsIndexer:                cacheApiExportsIndexer,
 
 		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
-			apiResourceSchema, err := apiResourceSchemaInformer.Lister().Cluster(clusterName).Get(name)
-			if errors.IsNotFound(err) {
-				return temporaryRemoteShardApiResourceSchemaInformer.Lister().Cluster(clusterName).Get(name)
-			}
-			return apiResourceSchema, err
+			return getAPIResourceSchemaFromListers(apiResourceSchemaListers, clusterName, name)
+		},
+		listAPIResourceSchemas: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIResourceSchema, error) {
+			return listAPIResourceSchemasFromListers(apiResourceSchemaListers, clusterName)
 		},
 
 		createCRD: func(ctx context.Context, clusterName logicalcluster.Name, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
 			return crdClusterClient.Cluster(clusterName).ApiextensionsV1().CustomResourceDefinitions().Create(ctx, crd, metav1.CreateOptions{})
 		},
+		updateCRDStatus: func(ctx context.Context, clusterName logicalcluster.Name, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crdClusterClient.Cluster(clusterName).ApiextensionsV1().CustomResourceDefinitions().UpdateStatus(ctx, crd, metav1.UpdateOptions{})
+		},
 		getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 			return crdInformer.Lister().Cluster(clusterName).Get(name)
 		},
@@ -135,9 +160,9 @@ func NewController(
 
 	logger := logging.WithReconciler(klog.Background(), ControllerName)
 	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    func(obj interface{}) { c.enqueueAPIBinding(obj, logger, "") },
-		UpdateFunc: func(_, obj interface{}) { c.enqueueAPIBinding(obj, logger, "") },
-		DeleteFunc: func(obj interface{}) { c.enqueueAPIBinding(obj, logger, "") },
+		AddFunc:    func(obj interface{}) { c.enqueueAPIBinding(obj, logger, "", false) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueAPIBinding(obj, logger, "", false) },
+		DeleteFunc: func(obj interface{}) { c.enqueueAPIBinding(obj, logger, "", false) },
 	})
 
 	if err := apiBindingInformer.Informer().AddIndexers(cache.Indexers{
@@ -175,6 +200,22 @@ func NewController(
 		},
 	})
 
+	crdInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+			if !ok {
+				return false
+			}
+
+			return logicalcluster.From(crd) != ShadowWorkspaceName
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueueLocalCRD(obj, logger) },
+			UpdateFunc: func(_, obj interface{}) { c.enqueueLocalCRD(obj, logger) },
+			DeleteFunc: func(obj interface{}) { c.enqueueLocalCRD(obj, logger) },
+		},
+	})
+
 	apiResourceSchemaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    func(obj interface{}) { c.enqueueAPIResourceSchema(obj, logger, "") },
 		UpdateFunc: func(_, obj interface{}) { c.enqueueAPIResourceSchema(obj, logger, "") },
@@ -197,6 +238,24 @@ func NewController(
 		DeleteFunc: func(obj interface{}) { c.enqueueAPIResourceSchema(obj, logger, "") },
 	})
 
+	if cacheApiExportsIndexer != nil {
+		cacheApiExportInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueueAPIExport(obj, logger, "") },
+			UpdateFunc: func(_, obj interface{}) { c.enqueueAPIExport(obj, logger, "") },
+			DeleteFunc: func(obj interface{}) { c.enqueueAPIExport(obj, logger, "") },
+		})
+		cacheApiResourceSchemaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueueAPIResourceSchema(obj, logger, "") },
+			UpdateFunc: func(_, obj interface{}) { c.enqueueAPIResourceSchema(obj, logger, "") },
+			DeleteFunc: func(obj interface{}) { c.enqueueAPIResourceSchema(obj, logger, "") },
+		})
+		if err := cacheApiExportsIndexer.AddIndexers(cache.Indexers{
+			indexAPIExportsByAPIResourceSchema: indexAPIExportsByAPIResourceSchemasFunc,
+		}); err != nil {
+			return nil, fmt.Errorf("error adding APIExport indexes for the cache server: %w", err)
+		}
+	}
+
 	if err := c.apiExportsIndexer.AddIndexers(cache.Indexers{
 		indexAPIExportsByAPIResourceSchema: indexAPIExportsByAPIResourceSchemasFunc,
 	}); err != nil {
@@ -236,19 +295,24 @@ type controller struct {
 	getAPIExport                          func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error)
 	apiExportsIndexer                     cache.Indexer
 	temporaryRemoteShardApiExportsIndexer cache.Indexer
+	// cacheApiExportsIndexer is nil if the cache server is not enabled.
+	cacheApiExportsIndexer cache.Indexer
 
-	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	getAPIResourceSchema   func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	listAPIResourceSchemas func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIResourceSchema, error)
 
-	createCRD func(ctx context.Context, clusterName logicalcluster.Name, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error)
-	getCRD    func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
-	listCRDs  func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error)
+	createCRD       func(ctx context.Context, clusterName logicalcluster.Name, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error)
+	updateCRDStatus func(ctx context.Context, clusterName logicalcluster.Name, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error)
+	getCRD          func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
+	listCRDs        func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error)
 
 	deletedCRDTracker *lockedStringSet
 	commit            CommitFunc
 }
 
-// enqueueAPIBinding enqueues an APIBinding .
-func (c *controller) enqueueAPIBinding(obj interface{}, logger logr.Logger, logSuffix string) {
+// enqueueAPIBinding enqueues an APIBinding. If batched is true, the APIBinding is enqueued after
+// relatedObjectBatchPeriod, such that bursts of changes to related objects are reconciled at once.
+func (c *controller) enqueueAPIBinding(obj interface{}, logger logr.Logger, logSuffix string, batched bool) {
 	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
 	if err != nil {
 		runtime.HandleError(err)
@@ -256,7 +320,11 @@ func (c *controller) enqueueAPIBinding(obj interface{}, logger logr.Logger, logS
 	}
 
 	logging.WithQueueKey(logger, key).V(2).Info(fmt.Sprintf("queueing APIBinding%s", logSuffix))
-	c.queue.Add(key)
+	if !batched {
+		c.queue.Add(key)
+		return
+	}
+	c.queue.AddAfter(key, relatedObjectBatchPeriod)
 }
 
 // enqueueAPIExport enqueues maps an APIExport to APIBindings for enqueuing.
@@ -274,7 +342,7 @@ func (c *controller) enqueueAPIExport(obj interface{}, logger logr.Logger, logSu
 	}
 
 	for _, binding := range bindingsForExport {
-		c.enqueueAPIBinding(binding, logging.WithObject(logger, obj.(*apisv1alpha1.APIExport)), fmt.Sprintf(" because of APIExport%s", logSuffix))
+		c.enqueueAPIBinding(binding, logging.WithObject(logger, obj.(*apisv1alpha1.APIExport)), fmt.Sprintf(" because of APIExport%s", logSuffix), true)
 	}
 }
 
@@ -309,6 +377,31 @@ func (c *controller) enqueueCRD(obj interface{}, logger logr.Logger) {
 	c.enqueueAPIResourceSchema(apiResourceSchema, logger, " because of CRD")
 }
 
+// enqueueLocalCRD maps a CRD of a workspace to the APIBindings of that workspace it might shadow.
+func (c *controller) enqueueLocalCRD(obj interface{}, logger logr.Logger) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("obj is supposed to be a CustomResourceDefinition, but is %T", obj))
+		return
+	}
+
+	bindings, err := c.listAPIBindings(logicalcluster.From(crd))
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	for _, binding := range bindings {
+		if !ShadowedByLocalCRDs(binding) {
+			continue
+		}
+		c.enqueueAPIBinding(binding, logging.WithObject(logger, crd), " because of local CRD", true)
+	}
+}
+
 // enqueueAPIResourceSchema maps an APIResourceSchema to APIExports for enqueuing.
 func (c *controller) enqueueAPIResourceSchema(obj interface{}, logger logr.Logger, logSuffix string) {
 	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
@@ -329,6 +422,13 @@ func (c *controller) enqueueAPIResourceSchema(obj interface{}, logger logr.Logge
 			return
 		}
 	}
+	if len(apiExports) == 0 && c.cacheApiExportsIndexer != nil {
+		apiExports, err = c.cacheApiExportsIndexer.ByIndex(indexAPIExportsByAPIResourceSchema, key)
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+	}
 
 	for _, export := range apiExports {
 		c.enqueueAPIExport(export, logging.WithObject(logger, obj.(*apisv1alpha1.APIResourceSchema)), fmt.Sprintf(" because of APIResourceSchema%s", logSuffix))
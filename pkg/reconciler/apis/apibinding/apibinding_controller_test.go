@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"context"
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	apisv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/conditions"
+)
+
+func TestEnqueueAPIBinding(t *testing.T) {
+	c := &controller{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+	}
+	defer c.queue.ShutDown()
+
+	c.enqueueAPIBinding(binding.Build(), klog.Background(), "", false)
+	require.Equal(t, 1, c.queue.Len(), "APIBinding events must be enqueued immediately")
+	key, _ := c.queue.Get()
+	c.queue.Done(key)
+	c.queue.Forget(key)
+
+	for i := 0; i < 3; i++ {
+		c.enqueueAPIBinding(binding.Build(), klog.Background(), " because of local CRD", true)
+	}
+	require.Equal(t, 0, c.queue.Len(), "events of related objects must be batched")
+	require.Eventually(t, func() bool {
+		return c.queue.Len() == 1
+	}, wait.ForeverTestTimeout, relatedObjectBatchPeriod/4, "batched events must be enqueued once")
+}
+
+func TestProcessUpdatesWaitingMessages(t *testing.T) {
+	b := binding.Build()
+	conditions.MarkFalse(b, apisv1alpha1.InitialBindingCompleted, apisv1alpha1.WaitingForEstablishedReason, conditionsv1alpha1.ConditionSeverityInfo,
+		"Waiting for API(s) to be established: another.widgets.kcp.dev, today.widgets.kcp.dev")
+
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(b))
+	key, err := kcpcache.MetaClusterNamespaceKeyFunc(b)
+	require.NoError(t, err)
+
+	var committed *Resource
+	c := &controller{
+		apiBindingsLister: apisv1alpha1listers.NewAPIBindingClusterLister(indexer),
+		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			return nil, nil
+		},
+		getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+			return &apisv1alpha1.APIExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{logicalcluster.AnnotationKey: "org:some-workspace"},
+					Name:        name,
+				},
+				Spec:   apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{"today.widgets.kcp.dev"}},
+				Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+			}, nil
+		},
+		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+			if name != todayWidgetsAPIResourceSchema.Name {
+				return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiresourceschemas"), name)
+			}
+			return todayWidgetsAPIResourceSchema, nil
+		},
+		listAPIResourceSchemas: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIResourceSchema, error) {
+			return nil, nil
+		},
+		getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			// exists, but is not established yet
+			return &apiextensionsv1.CustomResourceDefinition{}, nil
+		},
+		listCRDs: func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+			return nil, nil
+		},
+		deletedCRDTracker: &lockedStringSet{},
+		commit: func(ctx context.Context, old, new *Resource) error {
+			committed = new
+			return nil
+		},
+	}
+
+	require.NoError(t, c.process(context.Background(), key))
+	require.NotNil(t, committed)
+
+	updated := &apisv1alpha1.APIBinding{Status: *committed.Status}
+	condition := conditions.Get(updated, apisv1alpha1.InitialBindingCompleted)
+	require.NotNil(t, condition)
+	require.Equal(t, "Waiting for API(s) to be established: today.widgets.kcp.dev", condition.Message, "waiting message must be recomputed")
+}
@@ -23,6 +23,7 @@ import (
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/client"
+	"github.com/kcp-dev/kcp/pkg/reference"
 )
 
 const indexAPIBindingsByWorkspaceExport = "apiBindingsByWorkspaceExport"
@@ -35,13 +36,8 @@ func indexAPIBindingsByWorkspaceExportFunc(obj interface{}) ([]string, error) {
 		return []string{}, fmt.Errorf("obj is supposed to be an APIBinding, but is %T", obj)
 	}
 
-	if apiBinding.Spec.Reference.Workspace != nil {
-		apiExportClusterName := logicalcluster.New(apiBinding.Spec.Reference.Workspace.Path)
-		if !ok {
-			// this will never happen due to validation
-			return []string{}, fmt.Errorf("invalid export reference")
-		}
-		key := client.ToClusterAwareKey(apiExportClusterName, apiBinding.Spec.Reference.Workspace.ExportName)
+	if ref, apiExportClusterName, ok := reference.APIExportOf(apiBinding); ok {
+		key := client.ToClusterAwareKey(apiExportClusterName, ref.Name)
 		return []string{key}, nil
 	}
 
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	apisv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+)
+
+// getAPIExportFromListers returns the APIExport from the first lister that knows it. Listers are consulted in order,
+// usually local shard, root shard and cache server. Errors other than NotFound are returned immediately.
+func getAPIExportFromListers(listers []apisv1alpha1listers.APIExportClusterLister, clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+	var err error
+	for _, lister := range listers {
+		var apiExport *apisv1alpha1.APIExport
+		apiExport, err = lister.Cluster(clusterName).Get(name)
+		if !errors.IsNotFound(err) {
+			return apiExport, err
+		}
+	}
+	return nil, err
+}
+
+// getAPIResourceSchemaFromListers returns the APIResourceSchema from the first lister that knows it. Listers are
+// consulted in order. Errors other than NotFound are returned immediately.
+func getAPIResourceSchemaFromListers(listers []apisv1alpha1listers.APIResourceSchemaClusterLister, clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+	var err error
+	for _, lister := range listers {
+		var apiResourceSchema *apisv1alpha1.APIResourceSchema
+		apiResourceSchema, err = lister.Cluster(clusterName).Get(name)
+		if !errors.IsNotFound(err) {
+			return apiResourceSchema, err
+		}
+	}
+	return nil, err
+}
+
+// listAPIResourceSchemasFromListers returns the APIResourceSchemas of the given logical cluster from the first
+// lister that has any of them. A logical cluster lives on exactly one shard, hence there is no need to merge.
+func listAPIResourceSchemasFromListers(listers []apisv1alpha1listers.APIResourceSchemaClusterLister, clusterName logicalcluster.Name) ([]*apisv1alpha1.APIResourceSchema, error) {
+	for _, lister := range listers {
+		apiResourceSchemas, err := lister.Cluster(clusterName).List(labels.Everything())
+		if err != nil || len(apiResourceSchemas) > 0 {
+			return apiResourceSchemas, err
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	apisv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+)
+
+func newSchemaLister(t *testing.T, schemas ...*apisv1alpha1.APIResourceSchema) apisv1alpha1listers.APIResourceSchemaClusterLister {
+	t.Helper()
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{})
+	for _, s := range schemas {
+		require.NoError(t, indexer.Add(s))
+	}
+	return apisv1alpha1listers.NewAPIResourceSchemaClusterLister(indexer)
+}
+
+func newSchema(cluster, name string) *apisv1alpha1.APIResourceSchema {
+	return &apisv1alpha1.APIResourceSchema{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+		},
+	}
+}
+
+func TestAPIResourceSchemaLookupFallsBackToCacheServer(t *testing.T) {
+	local := newSchemaLister(t, newSchema("root:local", "today.widgets"))
+	rootShard := newSchemaLister(t, newSchema("root:org", "today.gadgets"))
+	cacheServer := newSchemaLister(t,
+		newSchema("root:org", "today.gadgets"),
+		newSchema("root:remote", "today.sprockets"),
+		newSchema("root:remote", "today.cogs"),
+	)
+	listers := []apisv1alpha1listers.APIResourceSchemaClusterLister{local, rootShard, cacheServer}
+
+	schema, err := getAPIResourceSchemaFromListers(listers, logicalcluster.New("root:local"), "today.widgets")
+	require.NoError(t, err)
+	require.Equal(t, "today.widgets", schema.Name)
+
+	schema, err = getAPIResourceSchemaFromListers(listers, logicalcluster.New("root:remote"), "today.sprockets")
+	require.NoError(t, err, "schema on another shard should be found in the cache server")
+	require.Equal(t, "today.sprockets", schema.Name)
+
+	_, err = getAPIResourceSchemaFromListers(listers, logicalcluster.New("root:remote"), "today.missing")
+	require.True(t, errors.IsNotFound(err), "expected NotFound, got %v", err)
+
+	schemas, err := listAPIResourceSchemasFromListers(listers, logicalcluster.New("root:remote"))
+	require.NoError(t, err)
+	require.Len(t, schemas, 2)
+
+	schemas, err = listAPIResourceSchemasFromListers(listers, logicalcluster.New("root:org"))
+	require.NoError(t, err)
+	require.Len(t, schemas, 1)
+
+	schemas, err = listAPIResourceSchemasFromListers(listers[:2], logicalcluster.New("root:remote"))
+	require.NoError(t, err)
+	require.Empty(t, schemas, "without the cache server, remote schemas are not visible")
+}
@@ -35,8 +35,10 @@ import (
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/conditions"
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reference"
 )
 
 func (c *controller) reconcile(ctx context.Context, apiBinding *apisv1alpha1.APIBinding) error {
@@ -149,12 +151,26 @@ func (c *controller) reconcileBinding(ctx context.Context, apiBinding *apisv1alp
 		return nil
 	}
 
+	availableSchemas, err := c.listAPIResourceSchemas(apiExportClusterName)
+	if err != nil {
+		return err
+	}
+
 	var needToWaitForRequeueWhenEstablished []string
+	var deprecationWarnings []string
+	var outdatedSchemaWarnings []string
+	var schemaRevisionDrifts []string
 
 	// Process all APIResourceSchemas
-	for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
+	for _, latestSchemaName := range apiExport.Spec.LatestResourceSchemas {
 		bindingClusterName := logicalcluster.From(apiBinding)
 
+		// Honor the schema revision the binding might be pinned to
+		schemaName, drift := boundSchemaNameFor(apiBinding, latestSchemaName, availableSchemas)
+		if drift != "" {
+			schemaRevisionDrifts = append(schemaRevisionDrifts, drift)
+		}
+
 		// Get the schema
 		schema, err := c.getAPIResourceSchema(apiExportClusterName, schemaName)
 		if err != nil {
@@ -177,6 +193,8 @@ func (c *controller) reconcileBinding(ctx context.Context, apiBinding *apisv1alp
 
 		logger = logging.WithObject(logger, schema)
 
+		outdatedSchemaWarnings = append(outdatedSchemaWarnings, OutdatedSchemaWarnings(schema, availableSchemas)...)
+
 		// Check for conflicts
 		checker := &conflictChecker{
 			listAPIBindings:      c.listAPIBindings,
@@ -271,7 +289,8 @@ func (c *controller) reconcileBinding(ctx context.Context, apiBinding *apisv1alp
 
 			// Create bound CRD
 			logger.V(2).Info("creating CRD")
-			if _, err := c.createCRD(ctx, ShadowWorkspaceName, crd); err != nil {
+			createdCRD, err := c.createCRD(ctx, ShadowWorkspaceName, crd)
+			if err != nil {
 				schemaClusterName := logicalcluster.From(schema)
 				if apierrors.IsInvalid(err) {
 					status := apierrors.APIStatus(nil)
@@ -323,6 +342,14 @@ func (c *controller) reconcileBinding(ctx context.Context, apiBinding *apisv1alp
 
 			c.deletedCRDTracker.Remove(crd.Name)
 
+			// With fast establishment, the status update of the established CRD requeues the APIBinding, which then
+			// binds the CRD like any other established one. If the update fails, the naming and establishing
+			// controllers take over.
+			if kcpfeatures.DefaultFeatureGate.Enabled(kcpfeatures.BoundCRDFastEstablishment) {
+				if _, err := c.updateCRDStatus(ctx, ShadowWorkspaceName, establishBoundCRD(createdCRD)); err != nil {
+					logger.V(2).Info("failed to fast-path establish CRD, waiting for it to be established", "err", err)
+				}
+			}
 			needToWaitForRequeueWhenEstablished = append(needToWaitForRequeueWhenEstablished, schemaName)
 			continue
 		}
@@ -342,15 +369,17 @@ func (c *controller) reconcileBinding(ctx context.Context, apiBinding *apisv1alp
 
 		sortedStorageVersions := storageVersions.List()
 		sort.Strings(sortedStorageVersions)
+		deprecationWarnings = append(deprecationWarnings, storedVersionDeprecationWarnings(schema, storageVersions)...)
 
 		// Upsert the BoundAPIResource for this APIResourceSchema
 		newBoundResource := apisv1alpha1.BoundAPIResource{
 			Group:    schema.Spec.Group,
 			Resource: schema.Spec.Names.Plural,
 			Schema: apisv1alpha1.BoundAPIResourceSchema{
-				Name:         schema.Name,
-				UID:          string(schema.UID),
-				IdentityHash: apiExport.Status.IdentityHash,
+				Name:                schema.Name,
+				UID:                 string(schema.UID),
+				IdentityHash:        apiExport.Status.IdentityHash,
+				StorageIdentityHash: apiExport.Status.StorageIdentityHash,
 			},
 			StorageVersions: sortedStorageVersions,
 		}
@@ -370,6 +399,69 @@ func (c *controller) reconcileBinding(ctx context.Context, apiBinding *apisv1alp
 
 	conditions.MarkTrue(apiBinding, apisv1alpha1.APIExportValid)
 
+	if len(deprecationWarnings) > 0 {
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.BoundAPIVersionsUpToDate,
+			apisv1alpha1.DeprecatedAPIVersionsInUseReason,
+			conditionsv1alpha1.ConditionSeverityWarning,
+			"Objects are stored in deprecated API versions: %s", strings.Join(deprecationWarnings, "; "),
+		)
+	} else {
+		conditions.MarkTrue(apiBinding, apisv1alpha1.BoundAPIVersionsUpToDate)
+	}
+
+	if len(outdatedSchemaWarnings) > 0 {
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.APIResourceSchemasCurrent,
+			apisv1alpha1.OutdatedAPIResourceSchemasReason,
+			conditionsv1alpha1.ConditionSeverityInfo,
+			"APIExport %s|%s provides outdated APIs: %s", apiExportClusterName, workspaceRef.ExportName, strings.Join(outdatedSchemaWarnings, "; "),
+		)
+	} else {
+		conditions.MarkTrue(apiBinding, apisv1alpha1.APIResourceSchemasCurrent)
+	}
+
+	if len(schemaRevisionDrifts) > 0 {
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.APIResourceSchemasLatest,
+			apisv1alpha1.SchemaRevisionPinnedReason,
+			conditionsv1alpha1.ConditionSeverityInfo,
+			"Bound APIs drifted from APIExport %s|%s: %s", apiExportClusterName, workspaceRef.ExportName, strings.Join(schemaRevisionDrifts, "; "),
+		)
+	} else {
+		conditions.MarkTrue(apiBinding, apisv1alpha1.APIResourceSchemasLatest)
+	}
+
+	var shadowedResources []string
+	if ShadowedByLocalCRDs(apiBinding) {
+		for _, r := range apiBinding.Status.BoundResources {
+			if r.Group == "" {
+				continue
+			}
+			_, err := c.getCRD(logicalcluster.From(apiBinding), r.Resource+"."+r.Group)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			if err == nil {
+				shadowedResources = append(shadowedResources, r.Resource+"."+r.Group)
+			}
+		}
+	}
+	if len(shadowedResources) > 0 {
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.BoundResourcesServed,
+			apisv1alpha1.ShadowedByLocalCRDsReason,
+			conditionsv1alpha1.ConditionSeverityInfo,
+			"Bound resources are served by CustomResourceDefinitions of the workspace: %s", strings.Join(shadowedResources, ", "),
+		)
+	} else {
+		conditions.MarkTrue(apiBinding, apisv1alpha1.BoundResourcesServed)
+	}
+
 	if len(needToWaitForRequeueWhenEstablished) > 0 {
 		sort.Strings(needToWaitForRequeueWhenEstablished)
 
@@ -400,6 +492,41 @@ func (c *controller) reconcileBinding(ctx context.Context, apiBinding *apisv1alp
 	return nil
 }
 
+// storedVersionDeprecationWarnings returns the deprecation warnings of the versions of the schema that
+// objects have been stored in.
+func storedVersionDeprecationWarnings(schema *apisv1alpha1.APIResourceSchema, storageVersions sets.String) []string {
+	var warnings []string
+	for _, version := range schema.Spec.Versions {
+		if !version.Deprecated || !storageVersions.Has(version.Name) {
+			continue
+		}
+		warning := version.DeprecationWarningFor(schema.Spec.Group, schema.Spec.Names.Kind)
+		warnings = append(warnings, fmt.Sprintf("%s.%s %s: %s", schema.Spec.Names.Plural, schema.Spec.Group, version.Name, warning))
+	}
+	return warnings
+}
+
+// establishBoundCRD accepts the names of a freshly created bound CRD and marks it as established. Bound CRDs
+// are named by the UID of their APIResourceSchema and naming conflicts are checked before creation, so
+// there is nothing left to decide for the naming and establishing controllers.
+func establishBoundCRD(crd *apiextensionsv1.CustomResourceDefinition) *apiextensionsv1.CustomResourceDefinition {
+	crd = crd.DeepCopy()
+	crd.Status.AcceptedNames = crd.Spec.Names
+	apihelpers.SetCRDCondition(crd, apiextensionsv1.CustomResourceDefinitionCondition{
+		Type:    apiextensionsv1.NamesAccepted,
+		Status:  apiextensionsv1.ConditionTrue,
+		Reason:  "NoConflicts",
+		Message: "no conflicts found",
+	})
+	apihelpers.SetCRDCondition(crd, apiextensionsv1.CustomResourceDefinitionCondition{
+		Type:    apiextensionsv1.Established,
+		Status:  apiextensionsv1.ConditionTrue,
+		Reason:  "InitialNamesAccepted",
+		Message: "the initial names have been accepted",
+	})
+	return crd
+}
+
 func boundCRDName(schema *apisv1alpha1.APIResourceSchema) string {
 	return string(schema.UID)
 }
@@ -416,9 +543,10 @@ func generateCRD(schema *apisv1alpha1.APIResourceSchema) (*apiextensionsv1.Custo
 			},
 		},
 		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
-			Group: schema.Spec.Group,
-			Names: schema.Spec.Names,
-			Scope: schema.Spec.Scope,
+			Group:      schema.Spec.Group,
+			Names:      schema.Spec.Names,
+			Scope:      schema.Spec.Scope,
+			Conversion: schema.Spec.Conversion.DeepCopy(),
 		},
 	}
 
@@ -441,6 +569,11 @@ func generateCRD(schema *apisv1alpha1.APIResourceSchema) (*apiextensionsv1.Custo
 			Subresources:             &version.Subresources,
 			AdditionalPrinterColumns: version.AdditionalPrinterColumns,
 		}
+		if version.Deprecated && version.SunsetTime != nil {
+			// announce the sunset in the warning headers of requests to the version
+			warning := version.DeprecationWarningFor(schema.Spec.Group, schema.Spec.Names.Kind)
+			crdVersion.DeprecationWarning = &warning
+		}
 
 		var validation apiextensionsv1.CustomResourceValidation
 		if err := json.Unmarshal(version.Schema.Raw, &validation.OpenAPIV3Schema); err != nil {
@@ -455,10 +588,11 @@ func generateCRD(schema *apisv1alpha1.APIResourceSchema) (*apiextensionsv1.Custo
 }
 
 func getAPIExportClusterName(apiBinding *apisv1alpha1.APIBinding) (logicalcluster.Name, error) {
-	if apiBinding.Spec.Reference.Workspace == nil {
+	_, clusterName, ok := reference.APIExportOf(apiBinding)
+	if !ok {
 		// cannot happen due to APIBinding validation
 		return logicalcluster.Name{}, fmt.Errorf("APIBinding does not specify an APIExport")
 	}
 
-	return logicalcluster.New(apiBinding.Spec.Reference.Workspace.Path), nil
+	return clusterName, nil
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,11 +33,14 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/conditions"
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 )
 
 // requireConditionMatches looks for a condition matching c in g. Only fields that are set in c are compared (Type is
@@ -82,6 +86,8 @@ var (
 
 	invalidSchema = binding.DeepCopy().WithWorkspaceReference("org:some-workspace", "invalid-schema")
 
+	deprecatedBinding = binding.DeepCopy().WithWorkspaceReference("org:some-workspace", "deprecated")
+
 	bound = unbound.DeepCopy().
 		WithPhase(apisv1alpha1.APIBindingPhaseBound).
 		WithBoundResources(
@@ -136,6 +142,46 @@ var (
 		},
 	}
 
+	deprecatedWidgetsAPIResourceSchema = &apisv1alpha1.APIResourceSchema{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "some-workspace",
+			},
+			Name: "deprecated.widgets.kcp.dev",
+			UID:  "deprecatedwidgetsuid",
+		},
+		Spec: apisv1alpha1.APIResourceSchemaSpec{
+			Group: "kcp.dev",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   "widgets",
+				Singular: "widget",
+				Kind:     "Widget",
+				ListKind: "WidgetList",
+			},
+			Scope: "Namespace",
+			Versions: []apisv1alpha1.APIResourceVersion{
+				{
+					Name:               "v1",
+					Served:             true,
+					Storage:            false,
+					Deprecated:         true,
+					DeprecationWarning: pointer.StringPtr("kcp.dev/v1 Widget is deprecated; use kcp.dev/v2 Widget"),
+					Schema: runtime.RawExtension{
+						Raw: []byte(`{"description":"foo","type":"object"}`),
+					},
+				},
+				{
+					Name:    "v2",
+					Served:  true,
+					Storage: true,
+					Schema: runtime.RawExtension{
+						Raw: []byte(`{"description":"foo","type":"object"}`),
+					},
+				},
+			},
+		},
+	}
+
 	someOtherWidgetsAPIResourceSchema = &apisv1alpha1.APIResourceSchema{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "another.widgets.kcp.dev",
@@ -185,6 +231,9 @@ func TestReconcileBinding(t *testing.T) {
 		getCRDError                             error
 		wantCreateCRD                           bool
 		createCRDError                          error
+		fastEstablishment                       bool
+		wantEstablishCRD                        bool
+		establishCRDError                       error
 		wantUpdateCRD                           bool
 		updateCRDError                          error
 		deletedCRDs                             []string
@@ -204,6 +253,9 @@ func TestReconcileBinding(t *testing.T) {
 		wantNamingConflict                      bool
 		crdEstablished                          bool
 		crdStorageVersions                      []string
+		wantDeprecationWarning                  string
+		exportWorkspaceSchemas                  []*apisv1alpha1.APIResourceSchema
+		wantOutdatedSchemas                     string
 	}{
 		"Update to nil workspace ref reports invalid APIExport": {
 			apiBinding:           binding.DeepCopy().WithoutWorkspaceReference().Build(),
@@ -269,6 +321,27 @@ func TestReconcileBinding(t *testing.T) {
 			wantBoundAPIExport:        true,
 			wantBoundResources:        nil, // not yet established
 		},
+		"create CRD - fast establishment": {
+			apiBinding:                binding.Build(),
+			fastEstablishment:         true,
+			wantCreateCRD:             true,
+			wantEstablishCRD:          true,
+			wantWaitingForEstablished: true,
+			wantAPIExportValid:        true,
+			wantBoundAPIExport:        true,
+			wantBoundResources:        nil, // bound when requeued by the CRD update
+		},
+		"create CRD - fast establishment fails": {
+			apiBinding:                binding.Build(),
+			fastEstablishment:         true,
+			wantCreateCRD:             true,
+			wantEstablishCRD:          true,
+			establishCRDError:         errors.New("foo"),
+			wantWaitingForEstablished: true,
+			wantAPIExportValid:        true,
+			wantBoundAPIExport:        true,
+			wantBoundResources:        nil, // left to the naming and establishing controllers
+		},
 		"create CRD - other bindings - no conflicts": {
 			apiBinding: binding.Build(),
 			existingAPIBindings: []*apisv1alpha1.APIBinding{
@@ -355,11 +428,76 @@ func TestReconcileBinding(t *testing.T) {
 			wantPhaseBound:             true,
 			wantInitialBindingComplete: true,
 		},
+		"Objects stored in deprecated version": {
+			apiBinding:         deprecatedBinding.Build(),
+			crdExists:          true,
+			crdEstablished:     true,
+			crdStorageVersions: []string{"v1"},
+			wantAPIExportValid: true,
+			wantReady:          true,
+			wantBoundAPIExport: true,
+			wantBoundResources: []apisv1alpha1.BoundAPIResource{
+				{
+					Group:    "kcp.dev",
+					Resource: "widgets",
+					Schema: apisv1alpha1.BoundAPIResourceSchema{
+						Name:         "deprecated.widgets.kcp.dev",
+						UID:          "deprecatedwidgetsuid",
+						IdentityHash: "hash4",
+					},
+					StorageVersions: []string{"v1"},
+				},
+			},
+			wantPhaseBound:             true,
+			wantInitialBindingComplete: true,
+			wantDeprecationWarning:     "widgets.kcp.dev v1: kcp.dev/v1 Widget is deprecated; use kcp.dev/v2 Widget",
+			wantOutdatedSchemas:        "APIExport org:some-workspace|deprecated provides outdated APIs: APIResourceSchema deprecated.widgets.kcp.dev: kcp.dev/v1 Widget is deprecated; use kcp.dev/v2 Widget",
+		},
+		"Schema superseded by a newer schema in the export workspace": {
+			apiBinding:         binding.Build(),
+			crdExists:          true,
+			crdEstablished:     true,
+			crdStorageVersions: []string{"v1"},
+			exportWorkspaceSchemas: []*apisv1alpha1.APIResourceSchema{
+				todayWidgetsAPIResourceSchema,
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              "tomorrow.widgets.kcp.dev",
+						CreationTimestamp: metav1.Now(),
+					},
+					Spec: apisv1alpha1.APIResourceSchemaSpec{
+						Group: "kcp.dev",
+						Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets"},
+					},
+				},
+			},
+			wantAPIExportValid: true,
+			wantReady:          true,
+			wantBoundAPIExport: true,
+			wantBoundResources: []apisv1alpha1.BoundAPIResource{
+				{
+					Group:    "kcp.dev",
+					Resource: "widgets",
+					Schema: apisv1alpha1.BoundAPIResourceSchema{
+						Name:         "today.widgets.kcp.dev",
+						UID:          "todaywidgetsuid",
+						IdentityHash: "hash1",
+					},
+					StorageVersions: []string{"v1"},
+				},
+			},
+			wantPhaseBound:             true,
+			wantInitialBindingComplete: true,
+			wantOutdatedSchemas:        "APIExport org:some-workspace|some-export provides outdated APIs: APIResourceSchema today.widgets.kcp.dev is superseded by the newer APIResourceSchema tomorrow.widgets.kcp.dev",
+		},
 	}
 
 	for testName, tc := range tests {
 		t.Run(testName, func(t *testing.T) {
+			defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, kcpfeatures.BoundCRDFastEstablishment, tc.fastEstablishment)()
+
 			createCRDCalled := false
+			var establishedCRD *apiextensionsv1.CustomResourceDefinition
 
 			apiExports := map[string]*apisv1alpha1.APIExport{
 				"some-export": {
@@ -398,6 +536,18 @@ func TestReconcileBinding(t *testing.T) {
 					},
 					Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash3"},
 				},
+				"deprecated": {
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							logicalcluster.AnnotationKey: "some-workspace",
+						},
+						Name: "deprecated",
+					},
+					Spec: apisv1alpha1.APIExportSpec{
+						LatestResourceSchemas: []string{"deprecated.widgets.kcp.dev"},
+					},
+					Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash4"},
+				},
 				"no-identity-hash": {
 					ObjectMeta: metav1.ObjectMeta{
 						Annotations: map[string]string{
@@ -429,8 +579,9 @@ func TestReconcileBinding(t *testing.T) {
 						},
 					},
 				},
-				"today.widgets.kcp.dev":   todayWidgetsAPIResourceSchema,
-				"another.widgets.kcp.dev": someOtherWidgetsAPIResourceSchema,
+				"today.widgets.kcp.dev":      todayWidgetsAPIResourceSchema,
+				"another.widgets.kcp.dev":    someOtherWidgetsAPIResourceSchema,
+				"deprecated.widgets.kcp.dev": deprecatedWidgetsAPIResourceSchema,
 			}
 
 			c := &controller{
@@ -455,6 +606,10 @@ func TestReconcileBinding(t *testing.T) {
 
 					return schema, nil
 				},
+				listAPIResourceSchemas: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIResourceSchema, error) {
+					require.Equal(t, "org:some-workspace", clusterName.String())
+					return tc.exportWorkspaceSchemas, nil
+				},
 				getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 					require.Equal(t, ShadowWorkspaceName, clusterName)
 
@@ -499,6 +654,11 @@ func TestReconcileBinding(t *testing.T) {
 					createCRDCalled = true
 					return crd, tc.createCRDError
 				},
+				updateCRDStatus: func(ctx context.Context, clusterName logicalcluster.Name, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+					require.Equal(t, ShadowWorkspaceName, clusterName)
+					establishedCRD = crd
+					return crd, tc.establishCRDError
+				},
 				deletedCRDTracker: &lockedStringSet{},
 			}
 
@@ -511,6 +671,12 @@ func TestReconcileBinding(t *testing.T) {
 			}
 
 			require.Equal(t, tc.wantCreateCRD, createCRDCalled, "mismatch on CRD creation expectation")
+			require.Equal(t, tc.wantEstablishCRD, establishedCRD != nil, "mismatch on CRD establishment expectation")
+			if establishedCRD != nil {
+				require.True(t, apihelpers.IsCRDConditionTrue(establishedCRD, apiextensionsv1.NamesAccepted), "names not accepted")
+				require.True(t, apihelpers.IsCRDConditionTrue(establishedCRD, apiextensionsv1.Established), "not established")
+				require.Equal(t, establishedCRD.Spec.Names, establishedCRD.Status.AcceptedNames)
+			}
 
 			if tc.wantInvalidReference {
 				requireConditionMatches(t, tc.apiBinding, &conditionsv1alpha1.Condition{
@@ -605,6 +771,30 @@ func TestReconcileBinding(t *testing.T) {
 				})
 			}
 
+			if tc.wantDeprecationWarning != "" {
+				requireConditionMatches(t, tc.apiBinding, &conditionsv1alpha1.Condition{
+					Type:     apisv1alpha1.BoundAPIVersionsUpToDate,
+					Status:   corev1.ConditionFalse,
+					Severity: conditionsv1alpha1.ConditionSeverityWarning,
+					Reason:   apisv1alpha1.DeprecatedAPIVersionsInUseReason,
+					Message:  tc.wantDeprecationWarning,
+				})
+			} else if tc.wantPhaseBound {
+				requireConditionMatches(t, tc.apiBinding, conditions.TrueCondition(apisv1alpha1.BoundAPIVersionsUpToDate))
+			}
+
+			if tc.wantOutdatedSchemas != "" {
+				requireConditionMatches(t, tc.apiBinding, &conditionsv1alpha1.Condition{
+					Type:     apisv1alpha1.APIResourceSchemasCurrent,
+					Status:   corev1.ConditionFalse,
+					Severity: conditionsv1alpha1.ConditionSeverityInfo,
+					Reason:   apisv1alpha1.OutdatedAPIResourceSchemasReason,
+					Message:  tc.wantOutdatedSchemas,
+				})
+			} else if tc.wantPhaseBound {
+				requireConditionMatches(t, tc.apiBinding, conditions.TrueCondition(apisv1alpha1.APIResourceSchemasCurrent))
+			}
+
 			if tc.wantInitialBindingCompleteSchemaInvalid {
 				requireConditionMatches(t, tc.apiBinding, &conditionsv1alpha1.Condition{
 					Type:     apisv1alpha1.InitialBindingCompleted,
@@ -802,6 +992,170 @@ func TestCRDFromAPIResourceSchema(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		"validation rules": {
+			schema: &apisv1alpha1.APIResourceSchema{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey: "my-cluster",
+					},
+					Name: "my-name",
+					UID:  types.UID("my-uuid"),
+				},
+				Spec: apisv1alpha1.APIResourceSchemaSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
+						Plural:   "widgets",
+						Singular: "widget",
+						Kind:     "Widget",
+						ListKind: "WidgetList",
+					},
+					Scope: apiextensionsv1.ClusterScoped,
+					Versions: []apisv1alpha1.APIResourceVersion{
+						{
+							Name:    "v1",
+							Served:  true,
+							Storage: true,
+							Schema: runtime.RawExtension{
+								Raw: []byte(`
+{
+	"type": "object",
+	"x-kubernetes-validations": [
+		{
+			"rule": "self.spec.min <= self.spec.max",
+			"message": "min must not be greater than max"
+		}
+	]
+}
+								`),
+							},
+						},
+					},
+				},
+			},
+			want: &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-uuid",
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey:            ShadowWorkspaceName.String(),
+						apisv1alpha1.AnnotationBoundCRDKey:      "",
+						apisv1alpha1.AnnotationSchemaClusterKey: "my-cluster",
+						apisv1alpha1.AnnotationSchemaNameKey:    "my-name",
+					},
+				},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
+						Plural:   "widgets",
+						Singular: "widget",
+						Kind:     "Widget",
+						ListKind: "WidgetList",
+					},
+					Scope: apiextensionsv1.ClusterScoped,
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+						{
+							Name:    "v1",
+							Served:  true,
+							Storage: true,
+							Schema: &apiextensionsv1.CustomResourceValidation{
+								OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+									Type: "object",
+									XValidations: apiextensionsv1.ValidationRules{
+										{
+											Rule:    "self.spec.min <= self.spec.max",
+											Message: "min must not be greater than max",
+										},
+									},
+								},
+							},
+							Subresources: &apiextensionsv1.CustomResourceSubresources{},
+						},
+					},
+				},
+			},
+		},
+		"webhook conversion": {
+			schema: &apisv1alpha1.APIResourceSchema{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey: "my-cluster",
+					},
+					Name: "my-name",
+					UID:  types.UID("my-uuid"),
+				},
+				Spec: apisv1alpha1.APIResourceSchemaSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
+						Plural:   "widgets",
+						Singular: "widget",
+						Kind:     "Widget",
+						ListKind: "WidgetList",
+					},
+					Scope: apiextensionsv1.ClusterScoped,
+					Versions: []apisv1alpha1.APIResourceVersion{
+						{
+							Name:    "v1",
+							Served:  true,
+							Storage: true,
+							Schema: runtime.RawExtension{
+								Raw: []byte(`{"type": "object"}`),
+							},
+						},
+					},
+					Conversion: &apiextensionsv1.CustomResourceConversion{
+						Strategy: apiextensionsv1.WebhookConverter,
+						Webhook: &apiextensionsv1.WebhookConversion{
+							ClientConfig: &apiextensionsv1.WebhookClientConfig{
+								URL: pointer.StringPtr("https://widgets.example.com/convert"),
+							},
+							ConversionReviewVersions: []string{"v1"},
+						},
+					},
+				},
+			},
+			want: &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-uuid",
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey:            ShadowWorkspaceName.String(),
+						apisv1alpha1.AnnotationBoundCRDKey:      "",
+						apisv1alpha1.AnnotationSchemaClusterKey: "my-cluster",
+						apisv1alpha1.AnnotationSchemaNameKey:    "my-name",
+					},
+				},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
+						Plural:   "widgets",
+						Singular: "widget",
+						Kind:     "Widget",
+						ListKind: "WidgetList",
+					},
+					Scope: apiextensionsv1.ClusterScoped,
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+						{
+							Name:    "v1",
+							Served:  true,
+							Storage: true,
+							Schema: &apiextensionsv1.CustomResourceValidation{
+								OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+									Type: "object",
+								},
+							},
+							Subresources: &apiextensionsv1.CustomResourceSubresources{},
+						},
+					},
+					Conversion: &apiextensionsv1.CustomResourceConversion{
+						Strategy: apiextensionsv1.WebhookConverter,
+						Webhook: &apiextensionsv1.WebhookConversion{
+							ClientConfig: &apiextensionsv1.WebhookClientConfig{
+								URL: pointer.StringPtr("https://widgets.example.com/convert"),
+							},
+							ConversionReviewVersions: []string{"v1"},
+						},
+					},
+				},
+			},
+		},
 		"error when schema is invalid": {
 			schema: &apisv1alpha1.APIResourceSchema{
 				Spec: apisv1alpha1.APIResourceSchemaSpec{
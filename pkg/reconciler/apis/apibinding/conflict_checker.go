@@ -46,6 +46,33 @@ type conflictChecker struct {
 	crdToBinding map[string]*apisv1alpha1.APIBinding
 }
 
+// ConflictChecker checks whether an APIResourceSchema can be bound in the workspace of an APIBinding
+// without conflicting with the APIs bound or defined through CRDs in that workspace already.
+type ConflictChecker interface {
+	CheckForConflicts(schema *apisv1alpha1.APIResourceSchema, apiBinding *apisv1alpha1.APIBinding) error
+}
+
+// NewConflictChecker returns a ConflictChecker for a single check.
+func NewConflictChecker(
+	listAPIBindings func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error),
+	getAPIExport func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error),
+	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error),
+	getCRD func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error),
+	listCRDs func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error),
+) ConflictChecker {
+	return &conflictChecker{
+		listAPIBindings:      listAPIBindings,
+		getAPIExport:         getAPIExport,
+		getAPIResourceSchema: getAPIResourceSchema,
+		getCRD:               getCRD,
+		listCRDs:             listCRDs,
+	}
+}
+
+func (ncc *conflictChecker) CheckForConflicts(schema *apisv1alpha1.APIResourceSchema, apiBinding *apisv1alpha1.APIBinding) error {
+	return ncc.checkForConflicts(schema, apiBinding)
+}
+
 func (ncc *conflictChecker) getBoundCRDs(apiBindingToExclude *apisv1alpha1.APIBinding) error {
 	clusterName := logicalcluster.From(apiBindingToExclude)
 
@@ -108,6 +135,10 @@ func (ncc *conflictChecker) checkForConflicts(schema *apisv1alpha1.APIResourceSc
 	for _, boundCRD := range ncc.boundCRDs {
 		if foundConflict, details := namesConflict(boundCRD, schema); foundConflict {
 			conflict := ncc.crdToBinding[boundCRD.Name]
+			if sameGroupResource(boundCRD, schema) && toleratesConflicts(apiBinding) && toleratesConflicts(conflict) {
+				// resolved when serving, see SortByConflictPrecedence
+				continue
+			}
 			return fmt.Errorf("naming conflict with a bound API %s, %s", conflict.Name, details)
 		}
 	}
@@ -116,6 +147,11 @@ func (ncc *conflictChecker) checkForConflicts(schema *apisv1alpha1.APIResourceSc
 }
 
 func (ncc *conflictChecker) gvrConflict(schema *apisv1alpha1.APIResourceSchema, apiBinding *apisv1alpha1.APIBinding) error {
+	if ShadowedByLocalCRDs(apiBinding) {
+		// resolved when serving, the CRDs of the workspace take precedence
+		return nil
+	}
+
 	bindingClusterName := logicalcluster.From(apiBinding)
 	bindingClusterCRDs, err := ncc.listCRDs(bindingClusterName)
 	if err != nil {
@@ -130,6 +166,10 @@ func (ncc *conflictChecker) gvrConflict(schema *apisv1alpha1.APIResourceSchema,
 	return nil
 }
 
+func sameGroupResource(existing *apiextensionsv1.CustomResourceDefinition, incoming *apisv1alpha1.APIResourceSchema) bool {
+	return existing.Spec.Group == incoming.Spec.Group && existing.Spec.Names.Plural == incoming.Spec.Names.Plural
+}
+
 func namesConflict(existing *apiextensionsv1.CustomResourceDefinition, incoming *apisv1alpha1.APIResourceSchema) (bool, string) {
 	if existing.Spec.Group != incoming.Spec.Group {
 		return false, ""
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"sort"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+// toleratesConflicts returns true if the APIBinding allows other APIBindings in its workspace to
+// bind the same group resources.
+func toleratesConflicts(apiBinding *apisv1alpha1.APIBinding) bool {
+	switch apiBinding.Spec.ConflictPolicy {
+	case apisv1alpha1.APIBindingConflictPolicyPriorityOrder, apisv1alpha1.APIBindingConflictPolicyFirstWins:
+		return true
+	default:
+		return false
+	}
+}
+
+func conflictPriority(apiBinding *apisv1alpha1.APIBinding) int32 {
+	if apiBinding.Spec.ConflictPolicy == apisv1alpha1.APIBindingConflictPolicyPriorityOrder {
+		return apiBinding.Spec.Priority
+	}
+	return 0
+}
+
+// SortByConflictPrecedence sorts the APIBindings of a workspace such that, for a group resource bound
+// by several of them, the first one serves the resource: highest priority first, then the oldest, then
+// by name.
+func SortByConflictPrecedence(apiBindings []*apisv1alpha1.APIBinding) {
+	sort.SliceStable(apiBindings, func(i, j int) bool {
+		a, b := apiBindings[i], apiBindings[j]
+		if pa, pb := conflictPriority(a), conflictPriority(b); pa != pb {
+			return pa > pb
+		}
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return a.Name < b.Name
+	})
+}
+
+// ShadowedByLocalCRDs returns true if CRDs in the workspace of the APIBinding take precedence over
+// the resources bound by it.
+func ShadowedByLocalCRDs(apiBinding *apisv1alpha1.APIBinding) bool {
+	return apiBinding.Spec.LocalCRDPolicy == apisv1alpha1.APIBindingLocalCRDPolicyShadow
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestSortByConflictPrecedence(t *testing.T) {
+	now := time.Now()
+	binding := func(name string, policy apisv1alpha1.APIBindingConflictPolicy, priority int32, age time.Duration) *apisv1alpha1.APIBinding {
+		return &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Spec:       apisv1alpha1.APIBindingSpec{ConflictPolicy: policy, Priority: priority},
+		}
+	}
+
+	bindings := []*apisv1alpha1.APIBinding{
+		binding("young-first-wins", apisv1alpha1.APIBindingConflictPolicyFirstWins, 0, time.Minute),
+		binding("low-priority", apisv1alpha1.APIBindingConflictPolicyPriorityOrder, -1, time.Hour),
+		binding("ignored-priority", apisv1alpha1.APIBindingConflictPolicyFirstWins, 100, time.Second),
+		binding("high-priority", apisv1alpha1.APIBindingConflictPolicyPriorityOrder, 10, time.Second),
+		binding("old-first-wins-b", apisv1alpha1.APIBindingConflictPolicyFirstWins, 0, time.Hour),
+		binding("old-first-wins-a", apisv1alpha1.APIBindingConflictPolicyFirstWins, 0, time.Hour),
+	}
+	SortByConflictPrecedence(bindings)
+
+	names := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		names = append(names, b.Name)
+	}
+	require.Equal(t, []string{"high-priority", "old-first-wins-a", "old-first-wins-b", "young-first-wins", "ignored-priority", "low-priority"}, names)
+}
+
+func TestCheckForConflictsWithPolicy(t *testing.T) {
+	schema := &apisv1alpha1.APIResourceSchema{
+		Spec: apisv1alpha1.APIResourceSchemaSpec{
+			Group: "acme.dev",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget", ListKind: "WidgetList"},
+		},
+	}
+	existingCRD := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing-uid"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "acme.dev",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets"},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			AcceptedNames: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget", ListKind: "WidgetList"},
+		},
+	}
+	otherResourceCRD := existingCRD.DeepCopy()
+	otherResourceCRD.Spec.Names.Plural = "gadgets"
+	otherResourceCRD.Status.AcceptedNames.Plural = "gadgets"
+
+	tests := map[string]struct {
+		existingPolicy apisv1alpha1.APIBindingConflictPolicy
+		newPolicy      apisv1alpha1.APIBindingConflictPolicy
+		existingCRD    *apiextensionsv1.CustomResourceDefinition
+		wantErr        bool
+	}{
+		"default rejects":                    {existingCRD: existingCRD, wantErr: true},
+		"existing rejects":                   {existingPolicy: apisv1alpha1.APIBindingConflictPolicyReject, newPolicy: apisv1alpha1.APIBindingConflictPolicyFirstWins, existingCRD: existingCRD, wantErr: true},
+		"new rejects":                        {existingPolicy: apisv1alpha1.APIBindingConflictPolicyPriorityOrder, existingCRD: existingCRD, wantErr: true},
+		"both tolerate":                      {existingPolicy: apisv1alpha1.APIBindingConflictPolicyPriorityOrder, newPolicy: apisv1alpha1.APIBindingConflictPolicyFirstWins, existingCRD: existingCRD},
+		"names of another resource conflict": {existingPolicy: apisv1alpha1.APIBindingConflictPolicyFirstWins, newPolicy: apisv1alpha1.APIBindingConflictPolicyFirstWins, existingCRD: otherResourceCRD, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			existingBinding := new(bindingBuilder).
+				WithClusterName("root:org:ws").
+				WithName("existing").
+				WithWorkspaceReference("root:org:exportWS", "existing").
+				WithBoundResources(new(boundAPIResourceBuilder).WithSchema("existing-schema", "existing-uid").BoundAPIResource).
+				Build()
+			existingBinding.Spec.ConflictPolicy = tt.existingPolicy
+			newBinding := new(bindingBuilder).
+				WithClusterName("root:org:ws").
+				WithName("new").
+				WithWorkspaceReference("root:org:exportWS", "new").
+				Build()
+			newBinding.Spec.ConflictPolicy = tt.newPolicy
+
+			ncc := &conflictChecker{
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					return []*apisv1alpha1.APIBinding{existingBinding, newBinding}, nil
+				},
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					return &apisv1alpha1.APIExport{Spec: apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{"existing-schema"}}}, nil
+				},
+				getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+					return &apisv1alpha1.APIResourceSchema{ObjectMeta: metav1.ObjectMeta{UID: "existing-uid"}}, nil
+				},
+				getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+					return tt.existingCRD, nil
+				},
+				listCRDs: func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+					return nil, nil
+				},
+			}
+
+			err := ncc.checkForConflicts(schema, newBinding)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"fmt"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+// OutdatedSchemaWarnings returns warnings for an APIResourceSchema of an APIExport that is outdated: either a
+// served version is deprecated, or the export workspace holds a newer APIResourceSchema for the same group and
+// resource that the APIExport does not reference yet, i.e. the provider has published a revision the export
+// has not caught up with.
+//
+// The available schemas are all APIResourceSchemas of the export workspace.
+func OutdatedSchemaWarnings(schema *apisv1alpha1.APIResourceSchema, available []*apisv1alpha1.APIResourceSchema) []string {
+	var warnings []string
+
+	for _, version := range schema.Spec.Versions {
+		if !version.Served || !version.Deprecated {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("APIResourceSchema %s: %s", schema.Name, version.DeprecationWarningFor(schema.Spec.Group, schema.Spec.Names.Kind)))
+	}
+
+	var newest *apisv1alpha1.APIResourceSchema
+	for _, other := range available {
+		if other.Name == schema.Name || other.Spec.Group != schema.Spec.Group || other.Spec.Names.Plural != schema.Spec.Names.Plural {
+			continue
+		}
+		if !schema.CreationTimestamp.Before(&other.CreationTimestamp) {
+			continue
+		}
+		if newest == nil || newest.CreationTimestamp.Before(&other.CreationTimestamp) {
+			newest = other
+		}
+	}
+	if newest != nil {
+		warnings = append(warnings, fmt.Sprintf("APIResourceSchema %s is superseded by the newer APIResourceSchema %s", schema.Name, newest.Name))
+	}
+
+	return warnings
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"fmt"
+	"strings"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+// boundSchemaNameFor returns the name of the APIResourceSchema to bind for the given latest APIResourceSchema
+// of the APIExport, honoring spec.schemaRevision of the APIBinding, and a description of the drift from the
+// latest APIResourceSchema, if any.
+//
+// APIResourceSchemas are named <revision>.<resource>.<group>. If the pinned revision does not exist for the
+// resource, the currently bound APIResourceSchema is held, or the latest one is bound for new resources.
+//
+// The available schemas are all APIResourceSchemas of the export workspace.
+func boundSchemaNameFor(apiBinding *apisv1alpha1.APIBinding, latestSchemaName string, available []*apisv1alpha1.APIResourceSchema) (string, string) {
+	revision := apiBinding.Spec.SchemaRevision
+	if revision == "" || revision == apisv1alpha1.LatestSchemaRevision {
+		return latestSchemaName, ""
+	}
+
+	_, resourceAndGroup, found := strings.Cut(latestSchemaName, ".")
+	if !found {
+		return latestSchemaName, ""
+	}
+
+	pinnedSchemaName := revision + "." + resourceAndGroup
+	if pinnedSchemaName == latestSchemaName {
+		return latestSchemaName, ""
+	}
+	for _, schema := range available {
+		if schema.Name == pinnedSchemaName {
+			return pinnedSchemaName, fmt.Sprintf("%s is pinned to APIResourceSchema %s, latest is %s", resourceAndGroup, pinnedSchemaName, latestSchemaName)
+		}
+	}
+
+	for _, r := range apiBinding.Status.BoundResources {
+		group := r.Group
+		if group == "" {
+			group = "core"
+		}
+		if r.Resource+"."+group != resourceAndGroup || r.Schema.Name == latestSchemaName {
+			continue
+		}
+		return r.Schema.Name, fmt.Sprintf("%s is held at APIResourceSchema %s because revision %q does not exist, latest is %s", resourceAndGroup, r.Schema.Name, revision, latestSchemaName)
+	}
+
+	return latestSchemaName, fmt.Sprintf("%s is bound to the latest APIResourceSchema %s because revision %q does not exist", resourceAndGroup, latestSchemaName, revision)
+}
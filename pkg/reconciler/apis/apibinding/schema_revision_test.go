@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestBoundSchemaNameFor(t *testing.T) {
+	available := []*apisv1alpha1.APIResourceSchema{
+		{ObjectMeta: metav1.ObjectMeta{Name: "rev1.widgets.example.io"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "rev2.widgets.example.io"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "rev1.configmaps.core"}},
+	}
+
+	tests := map[string]struct {
+		revision       string
+		boundResources []apisv1alpha1.BoundAPIResource
+		latest         string
+		wantName       string
+		wantDrift      bool
+	}{
+		"no revision follows latest": {
+			latest:   "rev2.widgets.example.io",
+			wantName: "rev2.widgets.example.io",
+		},
+		"latest revision follows latest": {
+			revision: apisv1alpha1.LatestSchemaRevision,
+			latest:   "rev2.widgets.example.io",
+			wantName: "rev2.widgets.example.io",
+		},
+		"pinned to the latest revision": {
+			revision: "rev2",
+			latest:   "rev2.widgets.example.io",
+			wantName: "rev2.widgets.example.io",
+		},
+		"pinned to an older revision": {
+			revision:  "rev1",
+			latest:    "rev2.widgets.example.io",
+			wantName:  "rev1.widgets.example.io",
+			wantDrift: true,
+		},
+		"pinned core resource": {
+			revision:  "rev1",
+			latest:    "rev2.configmaps.core",
+			wantName:  "rev1.configmaps.core",
+			wantDrift: true,
+		},
+		"missing revision holds the bound schema": {
+			revision: "rev0",
+			boundResources: []apisv1alpha1.BoundAPIResource{
+				{Group: "example.io", Resource: "widgets", Schema: apisv1alpha1.BoundAPIResourceSchema{Name: "rev1.widgets.example.io"}},
+			},
+			latest:    "rev2.widgets.example.io",
+			wantName:  "rev1.widgets.example.io",
+			wantDrift: true,
+		},
+		"missing revision of an unbound resource binds latest": {
+			revision: "rev0",
+			boundResources: []apisv1alpha1.BoundAPIResource{
+				{Group: "example.io", Resource: "gadgets", Schema: apisv1alpha1.BoundAPIResourceSchema{Name: "rev1.gadgets.example.io"}},
+			},
+			latest:    "rev2.widgets.example.io",
+			wantName:  "rev2.widgets.example.io",
+			wantDrift: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apiBinding := &apisv1alpha1.APIBinding{
+				Spec:   apisv1alpha1.APIBindingSpec{SchemaRevision: tc.revision},
+				Status: apisv1alpha1.APIBindingStatus{BoundResources: tc.boundResources},
+			}
+			schemaName, drift := boundSchemaNameFor(apiBinding, tc.latest, available)
+			require.Equal(t, tc.wantName, schemaName)
+			if tc.wantDrift {
+				require.NotEmpty(t, drift)
+			} else {
+				require.Empty(t, drift)
+			}
+		})
+	}
+}
@@ -40,12 +40,12 @@ import (
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	apisv1alpha1client "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/typed/apis/v1alpha1"
 	apisv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
 	tenancyv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
 	apisv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
@@ -66,6 +66,7 @@ func NewController(
 	namespaceInformer kcpcorev1informers.NamespaceClusterInformer,
 	secretInformer kcpcorev1informers.SecretClusterInformer,
 	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
+	identityProviders map[string]IdentityProvider,
 ) (*controller, error) {
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
 
@@ -82,8 +83,9 @@ func NewController(
 			_, err := kubeClusterClient.Cluster(clusterName).CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
 			return err
 		},
-		secretLister:    secretInformer.Lister(),
-		secretNamespace: DefaultIdentitySecretNamespace,
+		secretLister:      secretInformer.Lister(),
+		secretNamespace:   DefaultIdentitySecretNamespace,
+		identityProviders: identityProviders,
 		createSecret: func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error {
 			_, err := kubeClusterClient.Cluster(clusterName).CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
 			return err
@@ -177,7 +179,8 @@ type Patcher = apisv1alpha1client.APIExportInterface
 type Resource = committer.Resource[*APIExportSpec, *APIExportStatus]
 type CommitFunc = func(context.Context, *Resource, *Resource) error
 
-// controller reconciles APIExports. It ensures an export's identity secret exists and is valid.
+// controller reconciles APIExports. It ensures an export's identity secret exists, or the identity can be
+// fetched from its identity provider, and is valid.
 type controller struct {
 	queue workqueue.RateLimitingInterface
 
@@ -196,6 +199,8 @@ type controller struct {
 	getSecret    func(ctx context.Context, clusterName logicalcluster.Name, ns, name string) (*corev1.Secret, error)
 	createSecret func(ctx context.Context, clusterName logicalcluster.Name, secret *corev1.Secret) error
 
+	identityProviders map[string]IdentityProvider
+
 	getAPIBindingsForAPIExport func(clustername logicalcluster.Name, name string) ([]interface{}, error)
 
 	listClusterWorkspaceShards func() ([]*tenancyv1alpha1.ClusterWorkspaceShard, error)
@@ -33,7 +33,7 @@ import (
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 )
 
 func TestReconcile(t *testing.T) {
@@ -46,7 +46,11 @@ func TestReconcile(t *testing.T) {
 		apiExportHasExpectedHash             bool
 		apiExportHasSomeOtherHash            bool
 		hasPreexistingVerifyFailure          bool
+		rotateIdentity                       bool
 		listClusterWorkspaceShardsError      error
+		providerRefSet                       bool
+		providerUnknown                      bool
+		providerError                        error
 
 		apiBindings []interface{}
 
@@ -58,6 +62,7 @@ func TestReconcile(t *testing.T) {
 		wantStatusHashSet             bool
 		wantVerifyFailure             bool
 		wantIdentityValid             bool
+		wantRotated                   bool
 		wantVirtualWorkspaceURLsError bool
 		wantVirtualWorkspaceURLsReady bool
 	}{
@@ -102,6 +107,16 @@ func TestReconcile(t *testing.T) {
 
 			wantVerifyFailure: true,
 		},
+		"identity rotated when the annotation names the hash of the new secret": {
+			secretRefSet:                         true,
+			secretExists:                         true,
+			apiExportHasExpectedHash:             true,
+			secretHashDoesntMatchAPIExportStatus: true,
+			rotateIdentity:                       true,
+
+			wantRotated:       true,
+			wantIdentityValid: true,
+		},
 		"able to fix identity verification by returning to secret with correct key/hash": {
 			secretRefSet:                true,
 			secretExists:                true,
@@ -110,6 +125,31 @@ func TestReconcile(t *testing.T) {
 
 			wantIdentityValid: true,
 		},
+		"status hash updated from identity provider": {
+			providerRefSet: true,
+
+			wantStatusHashSet: true,
+			wantIdentityValid: true,
+		},
+		"identity verification fails when identity provider is not configured": {
+			providerRefSet:  true,
+			providerUnknown: true,
+
+			wantVerifyFailure: true,
+		},
+		"identity verification fails when identity provider fails": {
+			providerRefSet: true,
+			providerError:  errors.New("permission denied"),
+
+			wantVerifyFailure: true,
+		},
+		"identity verification fails when hash from identity provider differs with APIExport's hash": {
+			providerRefSet:                       true,
+			apiExportHasExpectedHash:             true,
+			secretHashDoesntMatchAPIExportStatus: true,
+
+			wantVerifyFailure: true,
+		},
 		"error listing clusterworkspaceshards": {
 			secretRefSet: true,
 			secretExists: true,
@@ -183,6 +223,18 @@ func TestReconcile(t *testing.T) {
 
 					return make([]interface{}, 0), nil
 				},
+				identityProviders: map[string]IdentityProvider{
+					"vault": identityProviderFunc(func(ctx context.Context, clusterName logicalcluster.Name, key string) ([]byte, error) {
+						require.Equal(t, "my-export-identity", key)
+						if tc.providerError != nil {
+							return nil, tc.providerError
+						}
+						if tc.secretHashDoesntMatchAPIExportStatus {
+							return []byte(someOtherKey), nil
+						}
+						return []byte(expectedKey), nil
+					}),
+				},
 				listClusterWorkspaceShards: func() ([]*tenancyv1alpha1.ClusterWorkspaceShard, error) {
 					if tc.listClusterWorkspaceShardsError != nil {
 						return nil, tc.listClusterWorkspaceShardsError
@@ -233,6 +285,19 @@ func TestReconcile(t *testing.T) {
 				}
 			}
 
+			if tc.providerRefSet {
+				provider := "vault"
+				if tc.providerUnknown {
+					provider = "kms"
+				}
+				apiExport.Spec.Identity = &apisv1alpha1.Identity{
+					ProviderRef: &apisv1alpha1.IdentityProviderReference{
+						Provider: provider,
+						Key:      "my-export-identity",
+					},
+				}
+			}
+
 			if tc.apiExportHasSomeOtherHash {
 				apiExport.Status.IdentityHash = "asdfasdfasdfasdf"
 			}
@@ -240,6 +305,10 @@ func TestReconcile(t *testing.T) {
 				apiExport.Status.IdentityHash = expectedHash
 			}
 
+			if tc.rotateIdentity {
+				apiExport.Annotations[apisv1alpha1.AnnotationRotateIdentityKey] = fmt.Sprintf("%x", sha256.Sum256([]byte(someOtherKey)))
+			}
+
 			if tc.hasPreexistingVerifyFailure {
 				conditions.MarkFalse(apiExport, apisv1alpha1.APIExportIdentityValid, apisv1alpha1.IdentityVerificationFailedReason, conditionsv1alpha1.ConditionSeverityError, "")
 			}
@@ -253,7 +322,9 @@ func TestReconcile(t *testing.T) {
 
 			require.Equal(t, tc.wantCreateSecretCalled, createSecretCalled, "expected to try to create secret")
 
-			if !tc.wantUnsetIdentity {
+			if tc.providerRefSet {
+				require.Nil(t, apiExport.Spec.Identity.SecretRef, "expected no secret to be referenced")
+			} else if !tc.wantUnsetIdentity {
 				if tc.wantDefaultSecretRef {
 					require.Equal(t, "default-ns", apiExport.Spec.Identity.SecretRef.Namespace)
 					require.Equal(t, apiExport.Name, apiExport.Spec.Identity.SecretRef.Name)
@@ -269,6 +340,13 @@ func TestReconcile(t *testing.T) {
 				require.Equal(t, hash, apiExport.Status.IdentityHash)
 			}
 
+			if tc.wantRotated {
+				require.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte(someOtherKey))), apiExport.Status.IdentityHash)
+				require.Equal(t, expectedHash, apiExport.Status.StorageIdentityHash)
+			} else {
+				require.Empty(t, apiExport.Status.StorageIdentityHash)
+			}
+
 			if tc.wantGenerationFailed {
 				requireConditionMatches(t, apiExport,
 					conditions.FalseCondition(
@@ -313,6 +391,90 @@ func TestReconcile(t *testing.T) {
 	}
 }
 
+type identityProviderFunc func(ctx context.Context, clusterName logicalcluster.Name, key string) ([]byte, error)
+
+func (f identityProviderFunc) GetIdentity(ctx context.Context, clusterName logicalcluster.Name, key string) ([]byte, error) {
+	return f(ctx, clusterName, key)
+}
+
+func TestUpdateBoundSchemasConsistent(t *testing.T) {
+	newBinding := func(schemaNames ...string) *apisv1alpha1.APIBinding {
+		b := &apisv1alpha1.APIBinding{}
+		for _, name := range schemaNames {
+			b.Status.BoundResources = append(b.Status.BoundResources, apisv1alpha1.BoundAPIResource{
+				Group:    "example.io",
+				Resource: "widgets",
+				Schema:   apisv1alpha1.BoundAPIResourceSchema{Name: name, IdentityHash: "id"},
+			})
+		}
+		return b
+	}
+
+	tests := map[string]struct {
+		apiBindings []interface{}
+		want        *conditionsv1alpha1.Condition
+	}{
+		"no bindings": {
+			want: conditions.TrueCondition(apisv1alpha1.APIExportBoundSchemasConsistent),
+		},
+		"same schema": {
+			apiBindings: []interface{}{newBinding("v1.widgets.example.io"), newBinding("v1.widgets.example.io")},
+			want:        conditions.TrueCondition(apisv1alpha1.APIExportBoundSchemasConsistent),
+		},
+		"skewed schemas": {
+			apiBindings: []interface{}{newBinding("v1.widgets.example.io"), newBinding("v2.widgets.example.io")},
+			want: conditions.FalseCondition(
+				apisv1alpha1.APIExportBoundSchemasConsistent,
+				apisv1alpha1.BoundSchemasSkewedReason,
+				conditionsv1alpha1.ConditionSeverityInfo,
+				"widgets.example.io (v1.widgets.example.io, v2.widgets.example.io)",
+			),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apiExport := &apisv1alpha1.APIExport{Status: apisv1alpha1.APIExportStatus{IdentityHash: "id"}}
+			updateBoundSchemasConsistent(apiExport, tc.apiBindings)
+			requireConditionMatches(t, apiExport, tc.want)
+		})
+	}
+}
+
+func TestUpdateSchemaRollout(t *testing.T) {
+	newBinding := func(cluster, name string, resources map[string]string) *apisv1alpha1.APIBinding {
+		b := &apisv1alpha1.APIBinding{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+		}}
+		for resource, schemaName := range resources {
+			b.Status.BoundResources = append(b.Status.BoundResources, apisv1alpha1.BoundAPIResource{
+				Group:    "example.io",
+				Resource: resource,
+				Schema:   apisv1alpha1.BoundAPIResourceSchema{Name: schemaName, IdentityHash: "id"},
+			})
+		}
+		return b
+	}
+
+	apiExport := &apisv1alpha1.APIExport{
+		Spec:   apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{"v2.widgets.example.io", "v1.gadgets.example.io"}},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "id"},
+	}
+	updateSchemaRollout(apiExport, []interface{}{
+		newBinding("root:a", "widgets", map[string]string{"widgets": "v2.widgets.example.io", "gadgets": "v1.gadgets.example.io"}),
+		newBinding("root:c", "widgets", map[string]string{"widgets": "v1.widgets.example.io"}),
+		newBinding("root:b", "widgets", map[string]string{"widgets": "v1.widgets.example.io"}),
+		&apisv1alpha1.APIBinding{Status: apisv1alpha1.APIBindingStatus{BoundResources: []apisv1alpha1.BoundAPIResource{
+			{Group: "other.io", Resource: "things", Schema: apisv1alpha1.BoundAPIResourceSchema{Name: "v1.things.other.io", IdentityHash: "other"}},
+		}}},
+	})
+
+	require.Equal(t, []apisv1alpha1.SchemaRolloutStatus{
+		{Group: "example.io", Resource: "gadgets", UpdatedBindings: 1, TotalBindings: 1},
+		{Group: "example.io", Resource: "widgets", UpdatedBindings: 1, TotalBindings: 3, OutdatedBindings: []string{"root:b|widgets", "root:c|widgets"}},
+	}, apiExport.Status.SchemaRollout)
+}
+
 // requireConditionMatches looks for a condition matching c in g. Only fields that are set in c are compared (Type is
 // required, though). If c.Message is set, the test performed is contains rather than an exact match.
 func requireConditionMatches(t *testing.T, g conditions.Getter, c *conditionsv1alpha1.Condition) {
@@ -21,19 +21,22 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"sort"
+	"strings"
 
 	"github.com/kcp-dev/logicalcluster/v2"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	virtualworkspacesoptions "github.com/kcp-dev/kcp/cmd/virtual-workspaces/options"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	apiexportbuilder "github.com/kcp-dev/kcp/pkg/virtual/apiexport/builder"
 )
@@ -46,7 +49,7 @@ func (c *controller) reconcile(ctx context.Context, apiExport *apisv1alpha1.APIE
 
 	clusterName := logicalcluster.From(apiExport)
 
-	if identity.SecretRef == nil {
+	if identity.SecretRef == nil && identity.ProviderRef == nil {
 		c.ensureSecretNamespaceExists(ctx, clusterName)
 
 		// See if the generated secret already exists (for whatever reason)
@@ -85,7 +88,7 @@ func (c *controller) reconcile(ctx context.Context, apiExport *apisv1alpha1.APIE
 	}
 
 	// Ref exists - make sure it's valid
-	if err := c.updateOrVerifyIdentitySecretHash(ctx, clusterName, apiExport); err != nil {
+	if err := c.updateOrVerifyIdentityHash(ctx, clusterName, apiExport); err != nil {
 		conditions.MarkFalse(
 			apiExport,
 			apisv1alpha1.APIExportIdentityValid,
@@ -101,6 +104,9 @@ func (c *controller) reconcile(ctx context.Context, apiExport *apisv1alpha1.APIE
 		return fmt.Errorf("error checking for APIBindings with APIExport %s|%s: %w", clusterName, apiExport.Name, err)
 	}
 
+	updateBoundSchemasConsistent(apiExport, apiBindings)
+	updateSchemaRollout(apiExport, apiBindings)
+
 	// If there are no bindings, then we can't create a URL yet.
 	if len(apiBindings) == 0 {
 		return nil
@@ -154,21 +160,39 @@ func (c *controller) createIdentitySecret(ctx context.Context, clusterName logic
 	return nil
 }
 
-func (c *controller) updateOrVerifyIdentitySecretHash(ctx context.Context, clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) error {
-	secret, err := c.getSecret(ctx, clusterName, apiExport.Spec.Identity.SecretRef.Namespace, apiExport.Spec.Identity.SecretRef.Name)
-	if err != nil {
-		return err
-	}
+func (c *controller) updateOrVerifyIdentityHash(ctx context.Context, clusterName logicalcluster.Name, apiExport *apisv1alpha1.APIExport) error {
+	var hash string
+	if ref := apiExport.Spec.Identity.ProviderRef; ref != nil {
+		provider, ok := c.identityProviders[ref.Provider]
+		if !ok {
+			return fmt.Errorf("identity provider %q is not configured", ref.Provider)
+		}
+		identity, err := provider.GetIdentity(ctx, clusterName, ref.Key)
+		if err != nil {
+			return fmt.Errorf("error fetching identity from provider %q: %w", ref.Provider, err)
+		}
+		hash = identityKeyHash(identity)
+	} else {
+		secret, err := c.getSecret(ctx, clusterName, apiExport.Spec.Identity.SecretRef.Namespace, apiExport.Spec.Identity.SecretRef.Name)
+		if err != nil {
+			return err
+		}
 
-	hash, err := IdentityHash(secret)
-	if err != nil {
-		return err
+		hash, err = IdentityHash(secret)
+		if err != nil {
+			return err
+		}
 	}
 
 	if apiExport.Status.IdentityHash == "" {
 		apiExport.Status.IdentityHash = hash
 	}
 
+	if apiExport.Status.IdentityHash != hash && apiExport.Annotations[apisv1alpha1.AnnotationRotateIdentityKey] == hash {
+		klog.FromContext(ctx).Info("rotating APIExport identity", "from", apiExport.Status.IdentityHash, "to", hash)
+		rotateIdentity(apiExport, hash)
+	}
+
 	if apiExport.Status.IdentityHash != hash {
 		return fmt.Errorf("hash mismatch: identity secret hash %q must match status.identityHash %q", hash, apiExport.Status.IdentityHash)
 	}
@@ -178,6 +202,15 @@ func (c *controller) updateOrVerifyIdentitySecretHash(ctx context.Context, clust
 	return nil
 }
 
+// rotateIdentity replaces the identity hash of the APIExport. The objects of the bound resources
+// stay stored under the original identity.
+func rotateIdentity(apiExport *apisv1alpha1.APIExport, hash string) {
+	if apiExport.Status.StorageIdentityHash == "" {
+		apiExport.Status.StorageIdentityHash = apiExport.Status.IdentityHash
+	}
+	apiExport.Status.IdentityHash = hash
+}
+
 func (c *controller) updateVirtualWorkspaceURLs(ctx context.Context, apiExport *apisv1alpha1.APIExport) error {
 	logger := klog.FromContext(ctx)
 	clusterWorkspaceShards, err := c.listClusterWorkspaceShards()
@@ -226,3 +259,90 @@ func (c *controller) updateVirtualWorkspaceURLs(ctx context.Context, apiExport *
 
 	return nil
 }
+
+// updateBoundSchemasConsistent marks whether the APIBindings to apiExport have bound the same APIResourceSchema
+// for each group and resource, e.g. after the APIExport has moved to a new schema not all bindings have picked up yet.
+func updateBoundSchemasConsistent(apiExport *apisv1alpha1.APIExport, apiBindings []interface{}) {
+	schemas := map[schema.GroupResource]sets.String{}
+	for _, obj := range apiBindings {
+		apiBinding := obj.(*apisv1alpha1.APIBinding)
+		for _, r := range apiBinding.Status.BoundResources {
+			if r.Schema.IdentityHash != apiExport.Status.IdentityHash {
+				continue
+			}
+			gr := schema.GroupResource{Group: r.Group, Resource: r.Resource}
+			if schemas[gr] == nil {
+				schemas[gr] = sets.NewString()
+			}
+			schemas[gr].Insert(r.Schema.Name)
+		}
+	}
+
+	var skewed []string
+	for gr, names := range schemas {
+		if names.Len() > 1 {
+			skewed = append(skewed, fmt.Sprintf("%s (%s)", gr, strings.Join(names.List(), ", ")))
+		}
+	}
+	if len(skewed) == 0 {
+		conditions.MarkTrue(apiExport, apisv1alpha1.APIExportBoundSchemasConsistent)
+		return
+	}
+	sort.Strings(skewed)
+
+	conditions.MarkFalse(
+		apiExport,
+		apisv1alpha1.APIExportBoundSchemasConsistent,
+		apisv1alpha1.BoundSchemasSkewedReason,
+		conditionsv1alpha1.ConditionSeverityInfo,
+		"APIBindings have bound different APIResourceSchemas for %s",
+		strings.Join(skewed, "; "),
+	)
+}
+
+// maxOutdatedBindings is the maximal number of outdated APIBindings listed per resource in status.schemaRollout.
+const maxOutdatedBindings = 10
+
+// updateSchemaRollout sets status.schemaRollout of apiExport, showing for each resource bound by the APIBindings
+// how many of them have bound one of the spec.latestResourceSchemas.
+func updateSchemaRollout(apiExport *apisv1alpha1.APIExport, apiBindings []interface{}) {
+	latest := sets.NewString(apiExport.Spec.LatestResourceSchemas...)
+
+	rollouts := map[schema.GroupResource]*apisv1alpha1.SchemaRolloutStatus{}
+	for _, obj := range apiBindings {
+		apiBinding := obj.(*apisv1alpha1.APIBinding)
+		for _, r := range apiBinding.Status.BoundResources {
+			if r.Schema.IdentityHash != apiExport.Status.IdentityHash {
+				continue
+			}
+			gr := schema.GroupResource{Group: r.Group, Resource: r.Resource}
+			rollout, found := rollouts[gr]
+			if !found {
+				rollout = &apisv1alpha1.SchemaRolloutStatus{Group: r.Group, Resource: r.Resource}
+				rollouts[gr] = rollout
+			}
+			rollout.TotalBindings++
+			if latest.Has(r.Schema.Name) {
+				rollout.UpdatedBindings++
+			} else {
+				rollout.OutdatedBindings = append(rollout.OutdatedBindings, fmt.Sprintf("%s|%s", logicalcluster.From(apiBinding), apiBinding.Name))
+			}
+		}
+	}
+
+	apiExport.Status.SchemaRollout = nil
+	for _, rollout := range rollouts {
+		sort.Strings(rollout.OutdatedBindings)
+		if len(rollout.OutdatedBindings) > maxOutdatedBindings {
+			rollout.OutdatedBindings = rollout.OutdatedBindings[:maxOutdatedBindings]
+		}
+		apiExport.Status.SchemaRollout = append(apiExport.Status.SchemaRollout, *rollout)
+	}
+	sort.Slice(apiExport.Status.SchemaRollout, func(i, j int) bool {
+		a, b := apiExport.Status.SchemaRollout[i], apiExport.Status.SchemaRollout[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		return a.Resource < b.Resource
+	})
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexport
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/pflag"
+)
+
+func DefaultOptions() *Options {
+	return &Options{}
+}
+
+func BindOptions(o *Options, fs *pflag.FlagSet) *Options {
+	fs.StringToStringVar(&o.IdentityProviderDirs, "apiexport-identity-provider-dirs", o.IdentityProviderDirs, "Identity providers for APIExports referencing their identity via spec.identity.providerRef, as <provider>=<directory> pairs. The identity of a reference is read from the file in the directory named like the key of the reference, e.g. as projected by an external secret manager.")
+	return o
+}
+
+type Options struct {
+	// IdentityProviderDirs maps identity provider names to directories holding identities, one file per key.
+	IdentityProviderDirs map[string]string
+}
+
+func (o *Options) Validate() error {
+	names := make([]string, 0, len(o.IdentityProviderDirs))
+	for name := range o.IdentityProviderDirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		dir := o.IdentityProviderDirs[name]
+		if name == "" {
+			return fmt.Errorf("--apiexport-identity-provider-dirs must not contain an empty provider name")
+		}
+		if info, err := os.Stat(dir); err != nil {
+			return fmt.Errorf("--apiexport-identity-provider-dirs directory %q of provider %q: %w", dir, name, err)
+		} else if !info.IsDir() {
+			return fmt.Errorf("--apiexport-identity-provider-dirs path %q of provider %q is not a directory", dir, name)
+		}
+	}
+	return nil
+}
+
+// IdentityProviders returns the identity providers configured in the options.
+func (o *Options) IdentityProviders() map[string]IdentityProvider {
+	providers := make(map[string]IdentityProvider, len(o.IdentityProviderDirs))
+	for name, dir := range o.IdentityProviderDirs {
+		providers[name] = NewFileIdentityProvider(dir)
+	}
+	return providers
+}
@@ -58,7 +58,10 @@ func IdentityHash(secret *corev1.Secret) (string, error) {
 		return "", fmt.Errorf("secret is missing data.%s", apisv1alpha1.SecretKeyAPIExportIdentity)
 	}
 
+	return identityKeyHash(key), nil
+}
+
+func identityKeyHash(key []byte) string {
 	hashBytes := sha256.Sum256(key)
-	hash := fmt.Sprintf("%x", hashBytes)
-	return hash, nil
+	return fmt.Sprintf("%x", hashBytes)
 }
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+)
+
+// IdentityProvider fetches APIExport identities that are stored outside of kcp, e.g. in a KMS or an
+// external secret manager, and are referenced by an APIExport's spec.identity.providerRef.
+//
+// The identity is fetched on every reconciliation of the APIExport. A provider may hence rotate how it
+// stores or encrypts the identity at any time, as long as the identity itself stays the same: a changed
+// identity does not match the APIExport's status.identityHash anymore and fails verification.
+type IdentityProvider interface {
+	// GetIdentity returns the identity stored under the given key for an APIExport in the given logical cluster.
+	GetIdentity(ctx context.Context, clusterName logicalcluster.Name, key string) ([]byte, error)
+}
+
+// NewFileIdentityProvider returns an IdentityProvider reading identities from files in the given directory,
+// the file name being the key of the reference. This fits secret managers and KMS plugins that
+// project decrypted secrets into the filesystem of the kcp server, e.g. the secrets store CSI driver or
+// the Vault agent.
+func NewFileIdentityProvider(dir string) IdentityProvider {
+	return &fileIdentityProvider{dir: dir}
+}
+
+type fileIdentityProvider struct {
+	dir string
+}
+
+func (p *fileIdentityProvider) GetIdentity(_ context.Context, _ logicalcluster.Name, key string) ([]byte, error) {
+	if key != filepath.Base(key) || strings.HasPrefix(key, ".") {
+		return nil, fmt.Errorf("invalid identity key %q: must be a file name", key)
+	}
+
+	identity, err := os.ReadFile(filepath.Join(p.dir, key))
+	if err != nil {
+		return nil, fmt.Errorf("error reading identity %q: %w", key, err)
+	}
+	identity = []byte(strings.TrimSpace(string(identity)))
+	if len(identity) == 0 {
+		return nil, fmt.Errorf("identity %q is empty", key)
+	}
+
+	return identity, nil
+}
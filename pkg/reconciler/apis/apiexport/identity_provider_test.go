@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexport
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileIdentityProvider(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "my-export"), []byte("abc\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "empty"), nil, 0600))
+
+	tests := map[string]struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		"identity read from file":  {key: "my-export", want: "abc"},
+		"missing file":             {key: "other-export", wantErr: true},
+		"empty file":               {key: "empty", wantErr: true},
+		"path outside directory":   {key: "../my-export", wantErr: true},
+		"hidden file":              {key: "..data", wantErr: true},
+		"subdirectory not allowed": {key: "sub/my-export", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			identity, err := NewFileIdentityProvider(dir).GetIdentity(context.Background(), logicalcluster.New("root:org"), tt.key)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(identity))
+		})
+	}
+}
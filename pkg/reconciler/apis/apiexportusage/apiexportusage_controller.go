@@ -0,0 +1,364 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportusage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	apisv1alpha1client "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/typed/apis/v1alpha1"
+	apisv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	apisv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/indexers"
+	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+)
+
+const (
+	ControllerName = "kcp-apiexport-usage"
+
+	// resyncPeriod is how often the usage of an APIBinding is recomputed, and the usage of an
+	// APIExport is aggregated. Objects of the bound resources do not trigger a reconciliation,
+	// as they are far too many.
+	resyncPeriod = 5 * time.Minute
+)
+
+var (
+	apiExportsResource  = apisv1alpha1.SchemeGroupVersion.WithResource("apiexports")
+	apiBindingsResource = apisv1alpha1.SchemeGroupVersion.WithResource("apibindings")
+)
+
+// NewController returns a new controller maintaining the usage of APIExports by their consumers.
+// Every shard records the usage of the bound resources in the status of its APIBindings, and
+// aggregates it into the status of its APIExports. With the cache server, the APIBindings of all
+// shards are aggregated, otherwise only those of the shard of the APIExport.
+func NewController(
+	kcpClusterClient kcpclientset.ClusterInterface,
+	apiExportInformer apisv1alpha1informers.APIExportClusterInformer,
+	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
+	cacheAPIBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
+	ddsif *informer.DynamicDiscoverySharedInformerFactory,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+
+	// the cache server has the APIBindings of all shards, including this one.
+	exportBindingInformer := apiBindingInformer
+	if cacheAPIBindingInformer != nil {
+		exportBindingInformer = cacheAPIBindingInformer
+	}
+
+	c := &controller{
+		queue: queue,
+
+		apiExportLister:  apiExportInformer.Lister(),
+		apiBindingLister: apiBindingInformer.Lister(),
+		getAPIBindingsForAPIExport: func(clusterName logicalcluster.Name, name string) ([]*apisv1alpha1.APIBinding, error) {
+			return indexers.ByIndex[*apisv1alpha1.APIBinding](exportBindingInformer.Informer().GetIndexer(), indexers.APIBindingsByAPIExport, indexers.ClusterPathAndAPIExportName(clusterName.String(), name))
+		},
+		listObjects: func(clusterName logicalcluster.Name, gr schema.GroupResource) ([]apiruntime.Object, error) {
+			listers, notSynced := ddsif.Listers()
+			for gvr, lister := range listers {
+				if gvr.GroupResource() == gr {
+					return lister.ByCluster(clusterName).List(labels.Everything())
+				}
+			}
+			for _, gvr := range notSynced {
+				if gvr.GroupResource() == gr {
+					return nil, fmt.Errorf("informer for %s not synced yet", gvr)
+				}
+			}
+			return nil, nil
+		},
+
+		commitAPIExport:  committer.NewCommitter[*APIExport, Patcher, *APIExportSpec, *APIExportStatus](kcpClusterClient.ApisV1alpha1().APIExports()),
+		commitAPIBinding: committer.NewCommitter[*APIBinding, APIBindingPatcher, *APIBindingSpec, *APIBindingStatus](kcpClusterClient.ApisV1alpha1().APIBindings()),
+	}
+
+	indexers.AddIfNotPresentOrDie(
+		exportBindingInformer.Informer().GetIndexer(),
+		cache.Indexers{
+			indexers.APIBindingsByAPIExport: indexers.IndexAPIBindingByAPIExport,
+		},
+	)
+
+	apiExportInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueAPIExport(obj)
+		},
+	})
+
+	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueAPIBinding(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldBinding, ok := oldObj.(*apisv1alpha1.APIBinding)
+			if !ok {
+				return
+			}
+			newBinding, ok := newObj.(*apisv1alpha1.APIBinding)
+			if !ok {
+				return
+			}
+			// updates of the usage itself must not trigger a recomputation
+			if !equality.Semantic.DeepEqual(oldBinding.Status.BoundResources, newBinding.Status.BoundResources) {
+				c.enqueueAPIBinding(newObj)
+			}
+		},
+	})
+
+	exportBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueAPIExportFromAPIBinding(obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueAPIExportFromAPIBinding(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueAPIExportFromAPIBinding(obj)
+		},
+	})
+
+	return c, nil
+}
+
+type APIExport = apisv1alpha1.APIExport
+type APIExportSpec = apisv1alpha1.APIExportSpec
+type APIExportStatus = apisv1alpha1.APIExportStatus
+type Patcher = apisv1alpha1client.APIExportInterface
+type Resource = committer.Resource[*APIExportSpec, *APIExportStatus]
+type CommitFunc = func(context.Context, *Resource, *Resource) error
+
+type APIBinding = apisv1alpha1.APIBinding
+type APIBindingSpec = apisv1alpha1.APIBindingSpec
+type APIBindingStatus = apisv1alpha1.APIBindingStatus
+type APIBindingPatcher = apisv1alpha1client.APIBindingInterface
+type APIBindingResource = committer.Resource[*APIBindingSpec, *APIBindingStatus]
+type CommitAPIBindingFunc = func(context.Context, *APIBindingResource, *APIBindingResource) error
+
+// controller records in the status of every APIBinding how many objects of the bound resources
+// its workspace has, and when these were last written, and reports in the status of every
+// APIExport which workspaces have bound it, aggregated from the status of their APIBindings.
+//
+// The queue keys are prefixed with the resource, i.e. apiexports::<key> or apibindings::<key>.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	apiExportLister  apisv1alpha1listers.APIExportClusterLister
+	apiBindingLister apisv1alpha1listers.APIBindingClusterLister
+
+	getAPIBindingsForAPIExport func(clusterName logicalcluster.Name, name string) ([]*apisv1alpha1.APIBinding, error)
+	listObjects                func(clusterName logicalcluster.Name, gr schema.GroupResource) ([]apiruntime.Object, error)
+
+	commitAPIExport  CommitFunc
+	commitAPIBinding CommitAPIBindingFunc
+}
+
+func (c *controller) enqueueAPIExport(obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing APIExport")
+	c.queue.Add(queueKey(apiExportsResource, key))
+}
+
+func (c *controller) enqueueAPIBinding(obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing APIBinding")
+	c.queue.Add(queueKey(apiBindingsResource, key))
+}
+
+func (c *controller) enqueueAPIExportFromAPIBinding(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	binding, ok := obj.(*apisv1alpha1.APIBinding)
+	if !ok || binding.Spec.Reference.Workspace == nil {
+		return
+	}
+
+	logger := logging.WithObject(logging.WithReconciler(klog.Background(), ControllerName), binding)
+	key := kcpcache.ToClusterAwareKey(binding.Spec.Reference.Workspace.Path, "", binding.Spec.Reference.Workspace.ExportName)
+	logging.WithQueueKey(logger, key).V(4).Info("queueing APIExport via APIBinding")
+	c.queue.Add(queueKey(apiExportsResource, key))
+}
+
+func queueKey(gvr schema.GroupVersionResource, key string) string {
+	return gvr.Resource + "::" + key
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	resource, objKey, found := strings.Cut(key, "::")
+	if !found {
+		runtime.HandleError(fmt.Errorf("incorrect key: %v, expected resource::key", key))
+		return nil
+	}
+	clusterName, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(objKey)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil
+	}
+
+	switch resource {
+	case apiExportsResource.Resource:
+		return c.processAPIExport(ctx, key, clusterName, name)
+	case apiBindingsResource.Resource:
+		return c.processAPIBinding(ctx, key, clusterName, name)
+	default:
+		runtime.HandleError(fmt.Errorf("unsupported resource %v", resource))
+		return nil
+	}
+}
+
+func (c *controller) processAPIExport(ctx context.Context, key string, clusterName logicalcluster.Name, name string) error {
+	obj, err := c.apiExportLister.Cluster(clusterName).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil // object deleted before we handled it, or on another shard
+		}
+		return err
+	}
+
+	old := obj
+	obj = obj.DeepCopy()
+
+	logger := logging.WithObject(klog.FromContext(ctx), obj)
+	ctx = klog.NewContext(ctx, logger)
+
+	var errs []error
+	if err := c.reconcile(ctx, obj); err != nil {
+		errs = append(errs, err)
+	} else {
+		c.queue.AddAfter(key, resyncPeriod)
+	}
+
+	oldResource := &Resource{ObjectMeta: old.ObjectMeta, Spec: &old.Spec, Status: &old.Status}
+	newResource := &Resource{ObjectMeta: obj.ObjectMeta, Spec: &obj.Spec, Status: &obj.Status}
+	if err := c.commitAPIExport(ctx, oldResource, newResource); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func (c *controller) processAPIBinding(ctx context.Context, key string, clusterName logicalcluster.Name, name string) error {
+	obj, err := c.apiBindingLister.Cluster(clusterName).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil // object deleted before we handled it
+		}
+		return err
+	}
+
+	old := obj
+	obj = obj.DeepCopy()
+
+	logger := logging.WithObject(klog.FromContext(ctx), obj)
+	ctx = klog.NewContext(ctx, logger)
+
+	var errs []error
+	if err := c.reconcileAPIBinding(ctx, obj); err != nil {
+		errs = append(errs, err)
+	} else {
+		c.queue.AddAfter(key, resyncPeriod)
+	}
+
+	oldResource := &APIBindingResource{ObjectMeta: old.ObjectMeta, Spec: &old.Spec, Status: &old.Status}
+	newResource := &APIBindingResource{ObjectMeta: obj.ObjectMeta, Spec: &obj.Spec, Status: &obj.Status}
+	if err := c.commitAPIBinding(ctx, oldResource, newResource); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportusage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+// maxConsumers is the maximal number of consumers listed in the usage of an APIExport.
+const maxConsumers = 100
+
+// reconcile aggregates the usage of the given APIExport from the APIBindings binding it.
+func (c *controller) reconcile(ctx context.Context, apiExport *apisv1alpha1.APIExport) error {
+	bindings, err := c.getAPIBindingsForAPIExport(logicalcluster.From(apiExport), apiExport.Name)
+	if err != nil {
+		return err
+	}
+
+	usage := &apisv1alpha1.APIExportUsage{Bindings: int32(len(bindings))}
+	totals := map[schema.GroupResource]int32{}
+	consumers := make([]apisv1alpha1.APIExportConsumer, 0, len(bindings))
+	for _, binding := range bindings {
+		consumer := apisv1alpha1.APIExportConsumer{
+			Workspace:  logicalcluster.From(binding).String(),
+			APIBinding: binding.Name,
+		}
+		if u := binding.Status.Usage; u != nil {
+			consumer.Resources = append(consumer.Resources, u.Resources...)
+			consumer.LastUsedTime = u.LastUsedTime
+			for _, r := range u.Resources {
+				totals[schema.GroupResource{Group: r.Group, Resource: r.Resource}] += r.Objects
+			}
+		}
+		consumers = append(consumers, consumer)
+	}
+
+	// most recently used first, consumers without objects last
+	sort.Slice(consumers, func(i, j int) bool {
+		ti, tj := consumers[i].LastUsedTime, consumers[j].LastUsedTime
+		switch {
+		case ti != nil && tj != nil && !ti.Equal(tj):
+			return tj.Before(ti)
+		case (ti == nil) != (tj == nil):
+			return ti != nil
+		case consumers[i].Workspace != consumers[j].Workspace:
+			return consumers[i].Workspace < consumers[j].Workspace
+		default:
+			return consumers[i].APIBinding < consumers[j].APIBinding
+		}
+	})
+	if len(consumers) > maxConsumers {
+		consumers = consumers[:maxConsumers]
+		usage.ConsumersTruncated = true
+	}
+	if len(consumers) > 0 {
+		usage.Consumers = consumers
+	}
+
+	for gr, count := range totals {
+		usage.Resources = append(usage.Resources, apisv1alpha1.ResourceUsage{Group: gr.Group, Resource: gr.Resource, Objects: count})
+	}
+	sortResources(usage.Resources)
+
+	apiExport.Status.Usage = usage
+	return nil
+}
+
+// reconcileAPIBinding computes the usage of the resources bound by the given APIBinding in its
+// workspace.
+func (c *controller) reconcileAPIBinding(ctx context.Context, binding *apisv1alpha1.APIBinding) error {
+	clusterName := logicalcluster.From(binding)
+	usage := &apisv1alpha1.APIBindingUsage{}
+	for _, r := range binding.Status.BoundResources {
+		objs, err := c.listObjects(clusterName, schema.GroupResource{Group: r.Group, Resource: r.Resource})
+		if err != nil {
+			return err
+		}
+		usage.Resources = append(usage.Resources, apisv1alpha1.ResourceUsage{Group: r.Group, Resource: r.Resource, Objects: int32(len(objs))})
+		for _, obj := range objs {
+			if t := lastWritten(obj); t != nil && (usage.LastUsedTime == nil || usage.LastUsedTime.Before(t)) {
+				usage.LastUsedTime = t
+			}
+		}
+	}
+	sortResources(usage.Resources)
+
+	binding.Status.Usage = usage
+	return nil
+}
+
+// lastWritten returns the latest creation or update time recorded in the metadata of obj.
+func lastWritten(obj runtime.Object) *metav1.Time {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil
+	}
+
+	latest := accessor.GetCreationTimestamp()
+	for _, entry := range accessor.GetManagedFields() {
+		if entry.Time != nil && latest.Before(entry.Time) {
+			latest = *entry.Time
+		}
+	}
+	if latest.IsZero() {
+		return nil
+	}
+	return &latest
+}
+
+func sortResources(resources []apisv1alpha1.ResourceUsage) {
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Group != resources[j].Group {
+			return resources[i].Group < resources[j].Group
+		}
+		return resources[i].Resource < resources[j].Resource
+	})
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiexportusage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	newBinding := func(cluster, name string, lastUsed time.Duration, objects ...int32) *apisv1alpha1.APIBinding {
+		binding := &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+			},
+		}
+		if objects == nil {
+			return binding
+		}
+		binding.Status.Usage = &apisv1alpha1.APIBindingUsage{
+			Resources: []apisv1alpha1.ResourceUsage{
+				{Group: "example.com", Resource: "gadgets", Objects: objects[0]},
+				{Group: "example.com", Resource: "widgets", Objects: objects[1]},
+			},
+		}
+		if lastUsed > 0 {
+			binding.Status.Usage.LastUsedTime = &metav1.Time{Time: now.Add(-lastUsed)}
+		}
+		return binding
+	}
+	timeAgo := func(d time.Duration) *metav1.Time {
+		return &metav1.Time{Time: now.Add(-d)}
+	}
+	manyBindings := make([]*apisv1alpha1.APIBinding, 0, maxConsumers+1)
+	for i := 0; i < maxConsumers+1; i++ {
+		manyBindings = append(manyBindings, newBinding(fmt.Sprintf("root:%03d", i), "widgets", 0))
+	}
+
+	tests := map[string]struct {
+		bindings []*apisv1alpha1.APIBinding
+
+		wantUsage *apisv1alpha1.APIExportUsage
+	}{
+		"no bindings": {
+			wantUsage: &apisv1alpha1.APIExportUsage{},
+		},
+		"bindings without usage yet": {
+			bindings: []*apisv1alpha1.APIBinding{newBinding("root:b", "widgets", 0), newBinding("root:a", "widgets", 0)},
+			wantUsage: &apisv1alpha1.APIExportUsage{
+				Bindings: 2,
+				Consumers: []apisv1alpha1.APIExportConsumer{
+					{Workspace: "root:a", APIBinding: "widgets"},
+					{Workspace: "root:b", APIBinding: "widgets"},
+				},
+			},
+		},
+		"usage aggregated and consumers ordered by last use": {
+			bindings: []*apisv1alpha1.APIBinding{
+				newBinding("root:a", "widgets", 4*time.Hour, 0, 2),
+				newBinding("root:b", "widgets", time.Hour, 1, 1),
+				newBinding("root:c", "widgets", 0, 0, 0),
+			},
+			wantUsage: &apisv1alpha1.APIExportUsage{
+				Bindings: 3,
+				Resources: []apisv1alpha1.ResourceUsage{
+					{Group: "example.com", Resource: "gadgets", Objects: 1},
+					{Group: "example.com", Resource: "widgets", Objects: 3},
+				},
+				Consumers: []apisv1alpha1.APIExportConsumer{
+					{
+						Workspace: "root:b", APIBinding: "widgets", LastUsedTime: timeAgo(time.Hour),
+						Resources: []apisv1alpha1.ResourceUsage{{Group: "example.com", Resource: "gadgets", Objects: 1}, {Group: "example.com", Resource: "widgets", Objects: 1}},
+					},
+					{
+						Workspace: "root:a", APIBinding: "widgets", LastUsedTime: timeAgo(4 * time.Hour),
+						Resources: []apisv1alpha1.ResourceUsage{{Group: "example.com", Resource: "gadgets"}, {Group: "example.com", Resource: "widgets", Objects: 2}},
+					},
+					{
+						Workspace: "root:c", APIBinding: "widgets",
+						Resources: []apisv1alpha1.ResourceUsage{{Group: "example.com", Resource: "gadgets"}, {Group: "example.com", Resource: "widgets"}},
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &controller{
+				getAPIBindingsForAPIExport: func(clusterName logicalcluster.Name, name string) ([]*apisv1alpha1.APIBinding, error) {
+					require.Equal(t, "root:provider", clusterName.String())
+					require.Equal(t, "widgets", name)
+					return tc.bindings, nil
+				},
+			}
+
+			export := &apisv1alpha1.APIExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "widgets",
+					Annotations: map[string]string{logicalcluster.AnnotationKey: "root:provider"},
+				},
+			}
+			err := c.reconcile(context.Background(), export)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantUsage, export.Status.Usage)
+		})
+	}
+
+	t.Run("consumers truncated", func(t *testing.T) {
+		c := &controller{
+			getAPIBindingsForAPIExport: func(clusterName logicalcluster.Name, name string) ([]*apisv1alpha1.APIBinding, error) {
+				return manyBindings, nil
+			},
+		}
+		export := &apisv1alpha1.APIExport{ObjectMeta: metav1.ObjectMeta{Name: "widgets"}}
+		require.NoError(t, c.reconcile(context.Background(), export))
+		require.Equal(t, int32(maxConsumers+1), export.Status.Usage.Bindings)
+		require.Len(t, export.Status.Usage.Consumers, maxConsumers)
+		require.True(t, export.Status.Usage.ConsumersTruncated)
+		require.Equal(t, "root:099", export.Status.Usage.Consumers[maxConsumers-1].Workspace)
+	})
+}
+
+func TestReconcileAPIBinding(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	newBinding := func(resources ...string) *apisv1alpha1.APIBinding {
+		binding := &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "widgets",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "root:consumer"},
+			},
+		}
+		for _, r := range resources {
+			binding.Status.BoundResources = append(binding.Status.BoundResources, apisv1alpha1.BoundAPIResource{Group: "example.com", Resource: r})
+		}
+		return binding
+	}
+	newObject := func(created, updated time.Duration) runtime.Object {
+		obj := &metav1.PartialObjectMetadata{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-created))},
+		}
+		if updated > 0 {
+			obj.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "test", Time: &metav1.Time{Time: now.Add(-updated)}}}
+		}
+		return obj
+	}
+
+	tests := map[string]struct {
+		binding *apisv1alpha1.APIBinding
+		objects map[string][]runtime.Object
+		listErr error
+
+		wantUsage *apisv1alpha1.APIBindingUsage
+		wantErr   bool
+	}{
+		"not bound yet": {
+			binding:   newBinding(),
+			wantUsage: &apisv1alpha1.APIBindingUsage{},
+		},
+		"without objects": {
+			binding: newBinding("widgets"),
+			wantUsage: &apisv1alpha1.APIBindingUsage{
+				Resources: []apisv1alpha1.ResourceUsage{{Group: "example.com", Resource: "widgets"}},
+			},
+		},
+		"objects counted": {
+			binding: newBinding("widgets", "gadgets"),
+			objects: map[string][]runtime.Object{
+				"widgets": {newObject(5*time.Hour, 0), newObject(4*time.Hour, 0)},
+				"gadgets": {newObject(5*time.Hour, time.Hour)},
+			},
+			wantUsage: &apisv1alpha1.APIBindingUsage{
+				Resources: []apisv1alpha1.ResourceUsage{
+					{Group: "example.com", Resource: "gadgets", Objects: 1},
+					{Group: "example.com", Resource: "widgets", Objects: 2},
+				},
+				LastUsedTime: &metav1.Time{Time: now.Add(-time.Hour)},
+			},
+		},
+		"informer not synced": {
+			binding: newBinding("widgets"),
+			listErr: fmt.Errorf("informer for example.com/v1, Resource=widgets not synced yet"),
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &controller{
+				listObjects: func(clusterName logicalcluster.Name, gr schema.GroupResource) ([]runtime.Object, error) {
+					require.Equal(t, "root:consumer", clusterName.String())
+					if tc.listErr != nil {
+						return nil, tc.listErr
+					}
+					return tc.objects[gr.Resource], nil
+				},
+			}
+
+			err := c.reconcileAPIBinding(context.Background(), tc.binding)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantUsage, tc.binding.Status.Usage)
+		})
+	}
+}
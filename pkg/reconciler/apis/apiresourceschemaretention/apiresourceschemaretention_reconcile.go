@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresourceschemaretention
+
+import (
+	"context"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+// reconcile applies the schema retention policy of the given APIExport. It returns the duration after which
+// the next superseded APIResourceSchema reaches the end of its retention period, if any.
+func (c *controller) reconcile(ctx context.Context, apiExport *apisv1alpha1.APIExport) (time.Duration, error) {
+	policy := apiExport.Spec.SchemaRetention
+	if policy == nil {
+		apiExport.Status.ResourceSchemasInUse = nil
+		return 0, nil
+	}
+
+	clusterName := logicalcluster.From(apiExport)
+	schemas, err := c.listAPIResourceSchemas(clusterName)
+	if err != nil {
+		return 0, err
+	}
+	schemasByName := make(map[string]*apisv1alpha1.APIResourceSchema, len(schemas))
+	for _, s := range schemas {
+		schemasByName[s.Name] = s
+	}
+
+	// the latest schemas of this export, by resource, are the ones superseding older schemas
+	latest := map[schema.GroupResource]*apisv1alpha1.APIResourceSchema{}
+	for _, name := range apiExport.Spec.LatestResourceSchemas {
+		if s, ok := schemasByName[name]; ok {
+			latest[schema.GroupResource{Group: s.Spec.Group, Resource: s.Spec.Names.Plural}] = s
+		}
+	}
+
+	bindings, err := c.getAPIBindingsForAPIExport(clusterName, apiExport.Name)
+	if err != nil {
+		return 0, err
+	}
+	inUse := sets.NewString()
+	for _, binding := range bindings {
+		for _, r := range binding.Status.BoundResources {
+			if _, ok := latest[schema.GroupResource{Group: r.Group, Resource: r.Resource}]; ok {
+				inUse.Insert(r.Schema.Name)
+			}
+		}
+	}
+	apiExport.Status.ResourceSchemasInUse = inUse.List()
+
+	// schemas referenced by any APIExport of the workspace cannot be deleted and are not superseded
+	apiExports, err := c.listAPIExports(clusterName)
+	if err != nil {
+		return 0, err
+	}
+	referenced := sets.NewString()
+	for _, export := range apiExports {
+		referenced.Insert(export.Spec.LatestResourceSchemas...)
+	}
+
+	logger := klog.FromContext(ctx)
+	now := c.now()
+	var requeueAfter time.Duration
+	var errs []error
+	for _, s := range schemas {
+		if referenced.Has(s.Name) || inUse.Has(s.Name) {
+			continue
+		}
+		successor, ok := latest[schema.GroupResource{Group: s.Spec.Group, Resource: s.Spec.Names.Plural}]
+		if !ok || !s.CreationTimestamp.Before(&successor.CreationTimestamp) {
+			continue
+		}
+		if policy.Action != apisv1alpha1.SchemaRetentionActionDelete && s.Labels[apisv1alpha1.APIResourceSchemaArchivedLabel] == "true" {
+			continue
+		}
+
+		expiry := successor.CreationTimestamp.Add(policy.RetentionPeriod.Duration)
+		if now.Before(expiry) {
+			if d := expiry.Sub(now); requeueAfter == 0 || d < requeueAfter {
+				requeueAfter = d
+			}
+			continue
+		}
+
+		if policy.Action == apisv1alpha1.SchemaRetentionActionDelete {
+			logger.V(2).Info("deleting superseded APIResourceSchema", "schema", s.Name, "successor", successor.Name)
+			if err := c.deleteAPIResourceSchema(ctx, clusterName, s.Name); err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, err)
+			}
+		} else {
+			logger.V(2).Info("archiving superseded APIResourceSchema", "schema", s.Name, "successor", successor.Name)
+			if err := c.archiveAPIResourceSchema(ctx, clusterName, s.Name); err != nil && !errors.IsNotFound(err) {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return requeueAfter, utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresourceschemaretention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	newSchema := func(name, resource string, age time.Duration, labels map[string]string) *apisv1alpha1.APIResourceSchema {
+		return &apisv1alpha1.APIResourceSchema{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Annotations:       map[string]string{logicalcluster.AnnotationKey: "root:org:provider"},
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+				Labels:            labels,
+			},
+			Spec: apisv1alpha1.APIResourceSchemaSpec{
+				Group: "example.com",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: resource},
+			},
+		}
+	}
+	newBinding := func(schemaName string) *apisv1alpha1.APIBinding {
+		return &apisv1alpha1.APIBinding{
+			Status: apisv1alpha1.APIBindingStatus{
+				BoundResources: []apisv1alpha1.BoundAPIResource{
+					{Group: "example.com", Resource: "widgets", Schema: apisv1alpha1.BoundAPIResourceSchema{Name: schemaName}},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		policy        *apisv1alpha1.SchemaRetentionPolicy
+		schemas       []*apisv1alpha1.APIResourceSchema
+		bindings      []*apisv1alpha1.APIBinding
+		otherExports  []string
+		wantInUse     []string
+		wantArchived  []string
+		wantDeleted   []string
+		wantRequeue   time.Duration
+		initialStatus []string
+	}{
+		"no policy clears the status": {
+			schemas:       []*apisv1alpha1.APIResourceSchema{newSchema("v1.widgets.example.com", "widgets", 3*time.Hour, nil), newSchema("v2.widgets.example.com", "widgets", 2*time.Hour, nil)},
+			initialStatus: []string{"v1.widgets.example.com"},
+		},
+		"superseded schema archived after the retention period": {
+			policy:       &apisv1alpha1.SchemaRetentionPolicy{RetentionPeriod: metav1.Duration{Duration: time.Hour}, Action: apisv1alpha1.SchemaRetentionActionArchive},
+			schemas:      []*apisv1alpha1.APIResourceSchema{newSchema("v1.widgets.example.com", "widgets", 3*time.Hour, nil), newSchema("v2.widgets.example.com", "widgets", 2*time.Hour, nil)},
+			wantArchived: []string{"v1.widgets.example.com"},
+		},
+		"superseded schema deleted after the retention period": {
+			policy:      &apisv1alpha1.SchemaRetentionPolicy{RetentionPeriod: metav1.Duration{Duration: time.Hour}, Action: apisv1alpha1.SchemaRetentionActionDelete},
+			schemas:     []*apisv1alpha1.APIResourceSchema{newSchema("v1.widgets.example.com", "widgets", 3*time.Hour, nil), newSchema("v2.widgets.example.com", "widgets", 2*time.Hour, nil)},
+			wantDeleted: []string{"v1.widgets.example.com"},
+		},
+		"superseded schema retained during the retention period": {
+			policy:      &apisv1alpha1.SchemaRetentionPolicy{RetentionPeriod: metav1.Duration{Duration: 3 * time.Hour}, Action: apisv1alpha1.SchemaRetentionActionDelete},
+			schemas:     []*apisv1alpha1.APIResourceSchema{newSchema("v1.widgets.example.com", "widgets", 3*time.Hour, nil), newSchema("v2.widgets.example.com", "widgets", 2*time.Hour, nil)},
+			wantRequeue: time.Hour,
+		},
+		"superseded schema in use is retained": {
+			policy:    &apisv1alpha1.SchemaRetentionPolicy{RetentionPeriod: metav1.Duration{Duration: time.Hour}, Action: apisv1alpha1.SchemaRetentionActionDelete},
+			schemas:   []*apisv1alpha1.APIResourceSchema{newSchema("v1.widgets.example.com", "widgets", 3*time.Hour, nil), newSchema("v2.widgets.example.com", "widgets", 2*time.Hour, nil)},
+			bindings:  []*apisv1alpha1.APIBinding{newBinding("v1.widgets.example.com"), newBinding("v2.widgets.example.com")},
+			wantInUse: []string{"v1.widgets.example.com", "v2.widgets.example.com"},
+		},
+		"schema referenced by another APIExport is retained": {
+			policy:       &apisv1alpha1.SchemaRetentionPolicy{RetentionPeriod: metav1.Duration{Duration: time.Hour}, Action: apisv1alpha1.SchemaRetentionActionDelete},
+			schemas:      []*apisv1alpha1.APIResourceSchema{newSchema("v1.widgets.example.com", "widgets", 3*time.Hour, nil), newSchema("v2.widgets.example.com", "widgets", 2*time.Hour, nil)},
+			otherExports: []string{"v1.widgets.example.com"},
+		},
+		"newer unreferenced schema is not superseded": {
+			policy:  &apisv1alpha1.SchemaRetentionPolicy{RetentionPeriod: metav1.Duration{Duration: time.Hour}, Action: apisv1alpha1.SchemaRetentionActionDelete},
+			schemas: []*apisv1alpha1.APIResourceSchema{newSchema("v3.widgets.example.com", "widgets", time.Minute, nil), newSchema("v2.widgets.example.com", "widgets", 2*time.Hour, nil)},
+		},
+		"schema of other resource is not superseded": {
+			policy:  &apisv1alpha1.SchemaRetentionPolicy{RetentionPeriod: metav1.Duration{Duration: time.Hour}, Action: apisv1alpha1.SchemaRetentionActionDelete},
+			schemas: []*apisv1alpha1.APIResourceSchema{newSchema("v1.gadgets.example.com", "gadgets", 3*time.Hour, nil), newSchema("v2.widgets.example.com", "widgets", 2*time.Hour, nil)},
+		},
+		"archived schema is not archived again": {
+			policy:  &apisv1alpha1.SchemaRetentionPolicy{RetentionPeriod: metav1.Duration{Duration: time.Hour}, Action: apisv1alpha1.SchemaRetentionActionArchive},
+			schemas: []*apisv1alpha1.APIResourceSchema{newSchema("v1.widgets.example.com", "widgets", 3*time.Hour, map[string]string{apisv1alpha1.APIResourceSchemaArchivedLabel: "true"}), newSchema("v2.widgets.example.com", "widgets", 2*time.Hour, nil)},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var archived, deleted []string
+			apiExport := &apisv1alpha1.APIExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "widgets",
+					Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org:provider"},
+				},
+				Spec: apisv1alpha1.APIExportSpec{
+					LatestResourceSchemas: []string{"v2.widgets.example.com"},
+					SchemaRetention:       tt.policy,
+				},
+				Status: apisv1alpha1.APIExportStatus{
+					ResourceSchemasInUse: tt.initialStatus,
+				},
+			}
+			otherExport := &apisv1alpha1.APIExport{
+				Spec: apisv1alpha1.APIExportSpec{LatestResourceSchemas: tt.otherExports},
+			}
+
+			c := &controller{
+				listAPIExports: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIExport, error) {
+					return []*apisv1alpha1.APIExport{apiExport, otherExport}, nil
+				},
+				listAPIResourceSchemas: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIResourceSchema, error) {
+					require.Equal(t, "root:org:provider", clusterName.String())
+					return tt.schemas, nil
+				},
+				getAPIBindingsForAPIExport: func(clusterName logicalcluster.Name, name string) ([]*apisv1alpha1.APIBinding, error) {
+					require.Equal(t, "widgets", name)
+					return tt.bindings, nil
+				},
+				archiveAPIResourceSchema: func(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+					archived = append(archived, name)
+					return nil
+				},
+				deleteAPIResourceSchema: func(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+					deleted = append(deleted, name)
+					return nil
+				},
+				now: func() time.Time { return now },
+			}
+
+			requeue, err := c.reconcile(context.Background(), apiExport)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRequeue, requeue)
+			require.Equal(t, tt.wantArchived, archived)
+			require.Equal(t, tt.wantDeleted, deleted)
+			if len(tt.wantInUse) == 0 {
+				require.Empty(t, apiExport.Status.ResourceSchemasInUse)
+			} else {
+				require.Equal(t, tt.wantInUse, apiExport.Status.ResourceSchemasInUse)
+			}
+		})
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"time"
 
 	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
-	kcpcorev1informers "github.com/kcp-dev/client-go/informers/core/v1"
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	"github.com/kcp-dev/logicalcluster/v2"
 
@@ -31,11 +30,11 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
-	configshard "github.com/kcp-dev/kcp/config/shard"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	apisv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
@@ -56,10 +55,13 @@ const (
 //
 // The config map is meant to be used by clients/informers to inject the identities
 // for the given GRs when making requests to the server.
+//
+// The configMapInformer must be scoped to the system:shard logical cluster, such that
+// the config maps of all other logical clusters are not held in memory.
 func NewApiExportIdentityProviderController(
 	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
 	remoteShardApiExportInformer apisv1alpha1informers.APIExportClusterInformer,
-	configMapInformer kcpcorev1informers.ConfigMapClusterInformer,
+	configMapInformer corev1informers.ConfigMapInformer,
 ) (*controller, error) {
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
 
@@ -68,8 +70,8 @@ func NewApiExportIdentityProviderController(
 		createConfigMap: func(ctx context.Context, cluster logicalcluster.Name, namespace string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
 			return kubeClusterClient.Cluster(cluster).CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
 		},
-		getConfigMap: func(cluster logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error) {
-			return configMapInformer.Lister().Cluster(cluster).ConfigMaps(namespace).Get(name)
+		getConfigMap: func(namespace, name string) (*corev1.ConfigMap, error) {
+			return configMapInformer.Lister().ConfigMaps(namespace).Get(name)
 		},
 		updateConfigMap: func(ctx context.Context, cluster logicalcluster.Name, namespace string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
 			return kubeClusterClient.Cluster(cluster).CoreV1().ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{})
@@ -102,18 +104,8 @@ func NewApiExportIdentityProviderController(
 
 	configMapInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: func(obj interface{}) bool {
-			key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
-			if err != nil {
-				runtime.HandleError(err)
-				return false
-			}
-			clusterName, _, _, err := kcpcache.SplitMetaClusterNamespaceKey(key)
-			if err != nil {
-				runtime.HandleError(err)
-				return false
-			}
-			if clusterName != configshard.SystemShardCluster {
-				return false
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
 			}
 			switch t := obj.(type) {
 			case *corev1.ConfigMap:
@@ -172,7 +164,7 @@ func (c *controller) processNextWorkItem(ctx context.Context) bool {
 type controller struct {
 	queue                         workqueue.RateLimitingInterface
 	createConfigMap               func(ctx context.Context, cluster logicalcluster.Name, namespace string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error)
-	getConfigMap                  func(cluster logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error)
+	getConfigMap                  func(namespace, name string) (*corev1.ConfigMap, error)
 	updateConfigMap               func(ctx context.Context, cluster logicalcluster.Name, namespace string, configMap *corev1.ConfigMap) (*corev1.ConfigMap, error)
 	listAPIExportsFromRemoteShard func(logicalcluster.Name) ([]*apisv1alpha1.APIExport, error)
 }
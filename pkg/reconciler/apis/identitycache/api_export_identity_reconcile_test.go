@@ -123,7 +123,7 @@ func TestReconcile(t *testing.T) {
 					},
 				},
 				getConfigMap: getConfigMapRecord{
-					defaulted: func(cluster logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error) {
+					defaulted: func(namespace, name string) (*corev1.ConfigMap, error) {
 						if scenario.initialConfigMap == nil {
 							return nil, errors.NewNotFound(corev1.Resource("configmaps"), name)
 						}
@@ -183,16 +183,16 @@ func (r *createConfigMapRecord) call(ctx context.Context, cluster logicalcluster
 
 type getConfigMapRecord struct {
 	called              bool
-	delegate, defaulted func(cluster logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error)
+	delegate, defaulted func(namespace, name string) (*corev1.ConfigMap, error)
 }
 
-func (r *getConfigMapRecord) call(cluster logicalcluster.Name, namespace, name string) (*corev1.ConfigMap, error) {
+func (r *getConfigMapRecord) call(namespace, name string) (*corev1.ConfigMap, error) {
 	r.called = true
 	delegate := r.delegate
 	if delegate == nil {
 		delegate = r.defaulted
 	}
-	return delegate(cluster, namespace, name)
+	return delegate(namespace, name)
 }
 
 type updateConfigMapRecord struct {
@@ -36,8 +36,9 @@ import (
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reference"
 )
 
 // reconcilePermissionClaims determines the resources that need to be labeled for access by a permission claim.
@@ -50,13 +51,13 @@ func (c *controller) reconcile(ctx context.Context, apiBinding *apisv1alpha1.API
 
 	clusterName := logicalcluster.From(apiBinding)
 
-	if apiBinding.Spec.Reference.Workspace == nil {
+	exportRef, exportClusterName, ok := reference.APIExportOf(apiBinding)
+	if !ok {
 		return nil
 	}
 
-	exportClusterName := apiBinding.Spec.Reference.Workspace.Path
-	exportName := apiBinding.Spec.Reference.Workspace.ExportName
-	apiExport, err := c.getAPIExport(logicalcluster.New(exportClusterName), exportName)
+	exportName := exportRef.Name
+	apiExport, err := c.getAPIExport(exportClusterName, exportName)
 	if err != nil {
 		logger.Error(err, "error getting APIExport", "apiExportWorkspace", exportClusterName, "apiExportName", exportName)
 		return nil // nothing we can do
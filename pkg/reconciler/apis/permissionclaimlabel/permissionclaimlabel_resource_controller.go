@@ -26,8 +26,14 @@ import (
 	"github.com/go-logr/logr"
 	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
 	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	kcpcorev1informers "github.com/kcp-dev/client-go/informers/core/v1"
+	"github.com/kcp-dev/logicalcluster/v2"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -35,6 +41,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	apisv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/indexers"
@@ -53,6 +60,7 @@ func NewResourceController(
 	dynamicClusterClient kcpdynamic.ClusterInterface,
 	dynamicDiscoverySharedInformerFactory *informer.DynamicDiscoverySharedInformerFactory,
 	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
+	namespaceInformer kcpcorev1informers.NamespaceClusterInformer,
 ) (*resourceController, error) {
 	if err := apiBindingInformer.Informer().GetIndexer().AddIndexers(
 		cache.Indexers{
@@ -67,13 +75,26 @@ func NewResourceController(
 		kcpClusterClient:       kcpClusterClient,
 		dynamicClusterClient:   dynamicClusterClient,
 		ddsif:                  dynamicDiscoverySharedInformerFactory,
-		permissionClaimLabeler: permissionclaim.NewLabeler(apiBindingInformer),
+		permissionClaimLabeler: permissionclaim.NewLabeler(apiBindingInformer, namespaceInformer),
+		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			return apiBindingInformer.Lister().Cluster(clusterName).List(labels.Everything())
+		},
 	}
 
 	logger := logging.WithReconciler(klog.Background(), ControllerName)
 	c.ddsif.AddEventHandler(informer.GVREventHandlerFuncs{
-		AddFunc:    func(gvr schema.GroupVersionResource, obj interface{}) { c.enqueueForResource(logger, gvr, obj) },
-		UpdateFunc: func(gvr schema.GroupVersionResource, _, obj interface{}) { c.enqueueForResource(logger, gvr, obj) },
+		AddFunc: func(gvr schema.GroupVersionResource, obj interface{}) {
+			c.enqueueForResource(logger, gvr, obj)
+			if gvr.GroupResource() == corev1.Resource("namespaces") {
+				c.enqueueForNamespace(logger, obj)
+			}
+		},
+		UpdateFunc: func(gvr schema.GroupVersionResource, oldObj, obj interface{}) {
+			c.enqueueForResource(logger, gvr, obj)
+			if gvr.GroupResource() == corev1.Resource("namespaces") && namespaceLabelsChanged(oldObj, obj) {
+				c.enqueueForNamespace(logger, obj)
+			}
+		},
 		DeleteFunc: nil, // Nothing to do.
 	})
 
@@ -89,6 +110,7 @@ type resourceController struct {
 	dynamicClusterClient   kcpdynamic.ClusterInterface
 	ddsif                  *informer.DynamicDiscoverySharedInformerFactory
 	permissionClaimLabeler *permissionclaim.Labeler
+	listAPIBindings        func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
 }
 
 // enqueueForResource adds the resource (gvr + obj) to the queue.
@@ -106,6 +128,67 @@ func (c *resourceController) enqueueForResource(logger logr.Logger, gvr schema.G
 	c.queue.Add(queueKey)
 }
 
+// enqueueForNamespace adds all objects in the namespace to the queue whose group resource is claimed
+// with a namespace selector by an accepted permission claim, because the namespace labels decide
+// whether these objects are claimed.
+func (c *resourceController) enqueueForNamespace(logger logr.Logger, obj interface{}) {
+	ns, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	clusterName := logicalcluster.From(ns)
+
+	bindings, err := c.listAPIBindings(clusterName)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	groupResources := map[schema.GroupResource]bool{}
+	for _, binding := range bindings {
+		for _, claim := range binding.Spec.PermissionClaims {
+			if claim.State != apisv1alpha1.ClaimAccepted {
+				continue
+			}
+			for _, selector := range claim.ResourceSelector {
+				if selector.NamespaceSelector != nil {
+					groupResources[schema.GroupResource{Group: claim.Group, Resource: claim.Resource}] = true
+				}
+			}
+		}
+	}
+	if len(groupResources) == 0 {
+		return
+	}
+
+	listers, _ := c.ddsif.Listers()
+	for gvr, lister := range listers {
+		if !groupResources[gvr.GroupResource()] || gvr.GroupResource() == corev1.Resource("namespaces") {
+			continue
+		}
+		objs, err := lister.ByCluster(clusterName).ByNamespace(ns.GetName()).List(labels.Everything())
+		if err != nil {
+			utilruntime.HandleError(err)
+			continue
+		}
+		for _, obj := range objs {
+			c.enqueueForResource(logger, gvr, obj)
+		}
+	}
+}
+
+func namespaceLabelsChanged(oldObj, newObj interface{}) bool {
+	oldNs, ok := oldObj.(metav1.Object)
+	if !ok {
+		return true
+	}
+	newNs, ok := newObj.(metav1.Object)
+	if !ok {
+		return true
+	}
+	return !equality.Semantic.DeepEqual(oldNs.GetLabels(), newNs.GetLabels())
+}
+
 // Start starts the controller, which stops when ctx.Done() is closed.
 func (c *resourceController) Start(ctx context.Context, numThreads int) {
 	defer utilruntime.HandleCrash()
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package systemcrds
+
+import (
+	"context"
+	"fmt"
+
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/pager"
+)
+
+// migrateObjects rewrites the objects of the given resource in all logical clusters of the shard, page by
+// page. A no-op update stores an object in the storage version of its CRD. It returns the number of objects
+// rewritten.
+func migrateObjects(ctx context.Context, client kcpdynamic.ClusterInterface, gvr schema.GroupVersionResource) (int, error) {
+	var count int
+	p := pager.New(pager.SimplePageFunc(func(opts metav1.ListOptions) (runtime.Object, error) {
+		return client.Resource(gvr).List(ctx, opts)
+	}))
+	err := p.EachListItem(ctx, metav1.ListOptions{}, func(o runtime.Object) error {
+		obj, ok := o.(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected object type %T", o)
+		}
+		_, err := client.Cluster(logicalcluster.From(obj)).Resource(gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
+		if errors.IsNotFound(err) || errors.IsConflict(err) {
+			return nil // deleted or written since listed, i.e. stored in the storage version already
+		}
+		if err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
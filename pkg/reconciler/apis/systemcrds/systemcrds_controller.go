@@ -0,0 +1,337 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package systemcrds
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	crdhelpers "k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kcpapiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/kcp/clientset/versioned"
+	kcpapiextensionsv1informers "k8s.io/apiextensions-apiserver/pkg/client/kcp/informers/externalversions/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	configsystemcrds "github.com/kcp-dev/kcp/config/system-crds"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	"github.com/kcp-dev/kcp/pkg/logging"
+)
+
+const (
+	ControllerName = "kcp-systemcrds"
+
+	// ackPollInterval is the interval in which the shards are checked for having acknowledged a pending
+	// system CRD schema.
+	ackPollInterval = 30 * time.Second
+)
+
+// NewController returns a controller rolling out the given system CRDs, shipped with this binary, to the
+// system CRD logical cluster of the shard.
+//
+// A system CRD whose schema differs from the shipped one keeps being served with its current schema until
+// every ClusterWorkspaceShard acknowledged the shipped schema through the configsystemcrds.ShardSchemasAnnotationKey
+// annotation, i.e. until every shard runs a binary that can serve it. Then the CRD is updated, and once it is
+// established again, the existing objects are migrated to the storage version and status.storedVersions is
+// trimmed to it.
+func NewController(
+	systemCRDCluster logicalcluster.Name,
+	crds []*apiextensionsv1.CustomResourceDefinition,
+	crdClusterClient kcpapiextensionsclientset.ClusterInterface,
+	crdInformer kcpapiextensionsv1informers.CustomResourceDefinitionClusterInformer,
+	rootKcpClient kcpclientset.ClusterInterface,
+	dynamicClusterClient kcpdynamic.ClusterInterface,
+) (*Controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+
+	c := &Controller{
+		queue: queue,
+		crds:  map[string]*apiextensionsv1.CustomResourceDefinition{},
+		getCRD: func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crdInformer.Lister().Cluster(systemCRDCluster).Get(name)
+		},
+		createCRD: func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crdClusterClient.ApiextensionsV1().CustomResourceDefinitions().Cluster(systemCRDCluster).Create(ctx, crd, metav1.CreateOptions{})
+		},
+		updateCRD: func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crdClusterClient.ApiextensionsV1().CustomResourceDefinitions().Cluster(systemCRDCluster).Update(ctx, crd, metav1.UpdateOptions{})
+		},
+		updateCRDStatus: func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crdClusterClient.ApiextensionsV1().CustomResourceDefinitions().Cluster(systemCRDCluster).UpdateStatus(ctx, crd, metav1.UpdateOptions{})
+		},
+		listShards: func(ctx context.Context) ([]tenancyv1alpha1.ClusterWorkspaceShard, error) {
+			shards, err := rootKcpClient.Cluster(tenancyv1alpha1.RootCluster).TenancyV1alpha1().ClusterWorkspaceShards().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return shards.Items, nil
+		},
+		migrateObjects: func(ctx context.Context, gvr schema.GroupVersionResource) (int, error) {
+			return migrateObjects(ctx, dynamicClusterClient, gvr)
+		},
+	}
+	for _, crd := range crds {
+		c.crds[crd.Name] = crd
+		c.names = append(c.names, crd.Name)
+	}
+	sort.Strings(c.names)
+
+	crdInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+			if !ok {
+				return false
+			}
+			_, found := c.crds[crd.Name]
+			return found && logicalcluster.From(crd) == systemCRDCluster
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { c.enqueueCRD(obj) },
+			UpdateFunc: func(_, obj interface{}) { c.enqueueCRD(obj) },
+			DeleteFunc: func(obj interface{}) { c.enqueueCRD(obj) },
+		},
+	})
+
+	return c, nil
+}
+
+// Controller rolls out the system CRDs shipped with this binary. It is also a readiness check, passing
+// when all system CRDs are established.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	// crds are the system CRDs shipped with this binary by name, annotated with the hash of their schema.
+	crds  map[string]*apiextensionsv1.CustomResourceDefinition
+	names []string
+
+	getCRD          func(name string) (*apiextensionsv1.CustomResourceDefinition, error)
+	createCRD       func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error)
+	updateCRD       func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error)
+	updateCRDStatus func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error)
+	listShards      func(ctx context.Context) ([]tenancyv1alpha1.ClusterWorkspaceShard, error)
+	migrateObjects  func(ctx context.Context, gvr schema.GroupVersionResource) (int, error)
+}
+
+// enqueueCRD enqueues a system CRD by name.
+func (c *Controller) enqueueCRD(obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	_, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), name)
+	logger.V(4).Info("queueing system CRD")
+	c.queue.Add(name)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	// system CRDs without events, e.g. those waiting for shards, are checked once on start.
+	for _, name := range c.names {
+		c.queue.Add(name)
+	}
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) process(ctx context.Context, name string) error {
+	logger := klog.FromContext(ctx)
+
+	shipped, found := c.crds[name]
+	if !found {
+		return nil // not a system CRD of this binary
+	}
+
+	crd, err := c.getCRD(name)
+	if errors.IsNotFound(err) {
+		logger.Info("creating system CRD")
+		if _, err := c.createCRD(ctx, shipped.DeepCopy()); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	logger = logging.WithObject(logger, crd)
+	ctx = klog.NewContext(ctx, logger)
+
+	if crd.Annotations[configsystemcrds.SchemaAnnotationKey] != shipped.Annotations[configsystemcrds.SchemaAnnotationKey] {
+		return c.rollOut(ctx, crd, shipped)
+	}
+
+	// objects are only migrated once the shipped schema is served.
+	if !crdhelpers.IsCRDConditionTrue(crd, apiextensionsv1.Established) {
+		logger.V(2).Info("waiting for system CRD to become established")
+		return nil // requeued on the next CRD event
+	}
+
+	return c.migrate(ctx, crd)
+}
+
+// rollOut updates the system CRD to the shipped schema if all shards acknowledged it, and requeues
+// the CRD otherwise.
+func (c *Controller) rollOut(ctx context.Context, crd, shipped *apiextensionsv1.CustomResourceDefinition) error {
+	logger := klog.FromContext(ctx)
+	schemaHash := shipped.Annotations[configsystemcrds.SchemaAnnotationKey]
+
+	shards, err := c.listShards(ctx)
+	if err != nil {
+		return err
+	}
+	var pending []string
+	for _, shard := range shards {
+		if configsystemcrds.ParseShardSchemas(shard.Annotations[configsystemcrds.ShardSchemasAnnotationKey])[crd.Name] != schemaHash {
+			pending = append(pending, shard.Name)
+		}
+	}
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		logger.V(2).Info("serving the current schema until all shards acknowledged the shipped one", "schema", schemaHash, "pending", strings.Join(pending, ","))
+		c.queue.AddAfter(crd.Name, ackPollInterval)
+		return nil
+	}
+
+	updated := shipped.DeepCopy()
+	updated.ResourceVersion = crd.ResourceVersion
+	for k, v := range crd.Annotations {
+		if _, found := updated.Annotations[k]; !found {
+			updated.Annotations[k] = v
+		}
+	}
+	logger.Info("rolling out system CRD schema", "schema", schemaHash, "previousSchema", crd.Annotations[configsystemcrds.SchemaAnnotationKey])
+	_, err = c.updateCRD(ctx, updated)
+	return err
+}
+
+// migrate rewrites all objects of the system CRD in its storage version, and then trims
+// status.storedVersions to it, such that later schemas can drop the other versions.
+func (c *Controller) migrate(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) error {
+	logger := klog.FromContext(ctx)
+
+	storageVersion, err := crdhelpers.GetCRDStorageVersion(crd)
+	if err != nil {
+		return err
+	}
+	if len(crd.Status.StoredVersions) == 0 || (len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion) {
+		return nil
+	}
+
+	gvr := schema.GroupVersionResource{Group: crd.Spec.Group, Version: storageVersion, Resource: crd.Spec.Names.Plural}
+	logger.Info("migrating objects to the storage version", "storedVersions", crd.Status.StoredVersions, "storageVersion", storageVersion)
+	count, err := c.migrateObjects(ctx, gvr)
+	if err != nil {
+		return fmt.Errorf("failed to migrate %s: %w", gvr, err)
+	}
+
+	crd = crd.DeepCopy()
+	crd.Status.StoredVersions = []string{storageVersion}
+	if _, err := c.updateCRDStatus(ctx, crd); err != nil {
+		return err
+	}
+	logger.Info("migrated objects to the storage version", "storageVersion", storageVersion, "count", count)
+	return nil
+}
+
+func (c *Controller) Name() string {
+	return "kcp-system-crds"
+}
+
+// Check fails while a system CRD does not exist or is not established, e.g. right after a new
+// schema has been rolled out.
+func (c *Controller) Check(_ *http.Request) error {
+	var notEstablished []string
+	for _, name := range c.names {
+		crd, err := c.getCRD(name)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err != nil || !crdhelpers.IsCRDConditionTrue(crd, apiextensionsv1.Established) {
+			notEstablished = append(notEstablished, name)
+		}
+	}
+	if len(notEstablished) > 0 {
+		return fmt.Errorf("system CRDs not established: %s", strings.Join(notEstablished, ", "))
+	}
+	return nil
+}
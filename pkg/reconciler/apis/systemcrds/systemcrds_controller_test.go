@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package systemcrds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
+
+	configsystemcrds "github.com/kcp-dev/kcp/config/system-crds"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+const crdName = "widgets.apis.kcp.dev"
+
+func TestReconcile(t *testing.T) {
+	tests := map[string]struct {
+		existing *apiextensionsv1.CustomResourceDefinition
+		shards   []tenancyv1alpha1.ClusterWorkspaceShard
+
+		wantCreated       bool
+		wantUpdatedSchema string
+		wantMigrated      *schema.GroupVersionResource
+		wantStoredVersion []string
+	}{
+		"missing CRD is created": {
+			wantCreated: true,
+		},
+		"current schema, nothing to do": {
+			existing: systemCRD("new", true, "v1"),
+		},
+		"new schema, shard pending": {
+			existing: systemCRD("old", true, "v1"),
+			shards:   []tenancyv1alpha1.ClusterWorkspaceShard{shard("root", "new"), shard("beta", "old"), shard("gamma", "")},
+		},
+		"new schema, all shards acknowledged": {
+			existing:          systemCRD("old", true, "v1"),
+			shards:            []tenancyv1alpha1.ClusterWorkspaceShard{shard("root", "new"), shard("beta", "new")},
+			wantUpdatedSchema: "new",
+		},
+		"rolled out, not established yet": {
+			existing: systemCRD("new", false, "v1alpha1", "v1"),
+		},
+		"rolled out and established, objects are migrated": {
+			existing:          systemCRD("new", true, "v1alpha1", "v1"),
+			wantMigrated:      &schema.GroupVersionResource{Group: "apis.kcp.dev", Version: "v1", Resource: "widgets"},
+			wantStoredVersion: []string{"v1"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created, updated, statusUpdated *apiextensionsv1.CustomResourceDefinition
+			var migrated *schema.GroupVersionResource
+
+			shipped := systemCRD("new", false)
+			delete(shipped.Annotations, "other")
+
+			queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+			defer queue.ShutDown()
+			c := &Controller{
+				queue: queue,
+				crds:  map[string]*apiextensionsv1.CustomResourceDefinition{crdName: shipped},
+				names: []string{crdName},
+				getCRD: func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+					if tt.existing == nil {
+						return nil, apierrors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
+					}
+					return tt.existing, nil
+				},
+				createCRD: func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+					created = crd
+					return crd, nil
+				},
+				updateCRD: func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+					updated = crd
+					return crd, nil
+				},
+				updateCRDStatus: func(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+					statusUpdated = crd
+					return crd, nil
+				},
+				listShards: func(ctx context.Context) ([]tenancyv1alpha1.ClusterWorkspaceShard, error) {
+					return tt.shards, nil
+				},
+				migrateObjects: func(ctx context.Context, gvr schema.GroupVersionResource) (int, error) {
+					migrated = &gvr
+					return 1, nil
+				},
+			}
+
+			err := c.process(context.Background(), crdName)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.wantCreated, created != nil, "created")
+			if tt.wantUpdatedSchema == "" {
+				require.Nil(t, updated, "updated")
+			} else {
+				require.NotNil(t, updated, "updated")
+				require.Equal(t, tt.wantUpdatedSchema, updated.Annotations[configsystemcrds.SchemaAnnotationKey])
+				require.Equal(t, tt.existing.ResourceVersion, updated.ResourceVersion)
+				require.Equal(t, "keep", updated.Annotations["other"], "foreign annotations must be kept")
+			}
+			require.Equal(t, tt.wantMigrated, migrated, "migrated")
+			if tt.wantStoredVersion == nil {
+				require.Nil(t, statusUpdated, "status updated")
+			} else {
+				require.NotNil(t, statusUpdated, "status updated")
+				require.Equal(t, tt.wantStoredVersion, statusUpdated.Status.StoredVersions)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	crds := map[string]*apiextensionsv1.CustomResourceDefinition{}
+	c := &Controller{
+		names: []string{crdName},
+		getCRD: func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			crd, found := crds[name]
+			if !found {
+				return nil, apierrors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
+			}
+			return crd, nil
+		},
+	}
+
+	require.Error(t, c.Check(nil), "missing CRD must fail readiness")
+
+	crds[crdName] = systemCRD("new", false, "v1")
+	require.Error(t, c.Check(nil), "CRD not established must fail readiness")
+
+	crds[crdName] = systemCRD("new", true, "v1")
+	require.NoError(t, c.Check(nil))
+}
+
+func systemCRD(schemaHash string, established bool, storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            crdName,
+			ResourceVersion: "42",
+			Annotations: map[string]string{
+				configsystemcrds.SchemaAnnotationKey: schemaHash,
+				"other":                              "keep",
+			},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "apis.kcp.dev",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+			Scope: apiextensionsv1.ClusterScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1", Served: true, Storage: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			StoredVersions: storedVersions,
+		},
+	}
+	if established {
+		crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue}}
+	}
+	return crd
+}
+
+func shard(name, schemaHash string) tenancyv1alpha1.ClusterWorkspaceShard {
+	s := tenancyv1alpha1.ClusterWorkspaceShard{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if schemaHash != "" {
+		s.Annotations = map[string]string{
+			configsystemcrds.ShardSchemasAnnotationKey: "other.apis.kcp.dev=abc," + crdName + "=" + schemaHash,
+		}
+	}
+	return s
+}
@@ -65,8 +65,10 @@ func NewController(
 		dynamicLocalClient:            dynamicLocalClient,
 		localApiExportLister:          localKcpInformers.Apis().V1alpha1().APIExports().Lister(),
 		localApiResourceSchemaLister:  localKcpInformers.Apis().V1alpha1().APIResourceSchemas().Lister(),
+		localApiBindingLister:         localKcpInformers.Apis().V1alpha1().APIBindings().Lister(),
 		cacheApiExportsIndexer:        cacheKcpInformers.Apis().V1alpha1().APIExports().Informer().GetIndexer(),
 		cacheApiResourceSchemaIndexer: cacheKcpInformers.Apis().V1alpha1().APIResourceSchemas().Informer().GetIndexer(),
+		cacheApiBindingsIndexer:       cacheKcpInformers.Apis().V1alpha1().APIBindings().Informer().GetIndexer(),
 	}
 
 	if err := cacheKcpInformers.Apis().V1alpha1().APIExports().Informer().AddIndexers(cache.Indexers{
@@ -79,11 +81,18 @@ func NewController(
 	}); err != nil {
 		return nil, err
 	}
+	if err := cacheKcpInformers.Apis().V1alpha1().APIBindings().Informer().AddIndexers(cache.Indexers{
+		ByShardAndLogicalClusterAndNamespaceAndName: IndexByShardAndLogicalClusterAndNamespace,
+	}); err != nil {
+		return nil, err
+	}
 
 	localKcpInformers.Apis().V1alpha1().APIExports().Informer().AddEventHandler(c.apiExportInformerEventHandler())
 	localKcpInformers.Apis().V1alpha1().APIResourceSchemas().Informer().AddEventHandler(c.apiResourceSchemaInformerEventHandler())
 	cacheKcpInformers.Apis().V1alpha1().APIExports().Informer().AddEventHandler(c.apiExportInformerEventHandler())
 	cacheKcpInformers.Apis().V1alpha1().APIResourceSchemas().Informer().AddEventHandler(c.apiResourceSchemaInformerEventHandler())
+	localKcpInformers.Apis().V1alpha1().APIBindings().Informer().AddEventHandler(c.apiBindingInformerEventHandler())
+	cacheKcpInformers.Apis().V1alpha1().APIBindings().Informer().AddEventHandler(c.apiBindingInformerEventHandler())
 	return c, nil
 }
 
@@ -95,6 +104,10 @@ func (c *controller) enqueueAPIResourceSchema(obj interface{}) {
 	c.enqueueObject(obj, apisv1alpha1.SchemeGroupVersion.WithResource("apiresourceschemas"))
 }
 
+func (c *controller) enqueueAPIBinding(obj interface{}) {
+	c.enqueueObject(obj, apisv1alpha1.SchemeGroupVersion.WithResource("apibindings"))
+}
+
 func (c *controller) enqueueObject(obj interface{}, gvr schema.GroupVersionResource) {
 	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
 	if err != nil {
@@ -155,6 +168,10 @@ func (c *controller) apiResourceSchemaInformerEventHandler() cache.ResourceEvent
 	return objectInformerEventHandler(c.enqueueAPIResourceSchema)
 }
 
+func (c *controller) apiBindingInformerEventHandler() cache.ResourceEventHandler {
+	return objectInformerEventHandler(c.enqueueAPIBinding)
+}
+
 func objectInformerEventHandler(enqueueObject func(obj interface{})) cache.ResourceEventHandler {
 	return cache.ResourceEventHandlerFuncs{
 		AddFunc:    func(obj interface{}) { enqueueObject(obj) },
@@ -172,7 +189,9 @@ type controller struct {
 
 	localApiExportLister         apisv1alpha1listers.APIExportClusterLister
 	localApiResourceSchemaLister apisv1alpha1listers.APIResourceSchemaClusterLister
+	localApiBindingLister        apisv1alpha1listers.APIBindingClusterLister
 
 	cacheApiExportsIndexer        cache.Indexer
 	cacheApiResourceSchemaIndexer cache.Indexer
+	cacheApiBindingsIndexer       cache.Indexer
 }
@@ -63,6 +63,17 @@ func (c *controller) reconcile(ctx context.Context, gvrKey string) error {
 			func(cluster logicalcluster.Name, _, name string) (interface{}, error) {
 				return c.localApiResourceSchemaLister.Cluster(cluster).Get(name)
 			})
+	case apisv1alpha1.SchemeGroupVersion.WithResource("apibindings").String():
+		return c.reconcileObject(ctx,
+			keyParts[1],
+			apisv1alpha1.SchemeGroupVersion.WithResource("apibindings"),
+			apisv1alpha1.SchemeGroupVersion.WithKind("APIBinding"),
+			func(gvr schema.GroupVersionResource, cluster logicalcluster.Name, namespace, name string) (interface{}, error) {
+				return retrieveCacheObject(&gvr, c.cacheApiBindingsIndexer, c.shardName, cluster, namespace, name)
+			},
+			func(cluster logicalcluster.Name, _, name string) (interface{}, error) {
+				return c.localApiBindingLister.Cluster(cluster).Get(name)
+			})
 	default:
 		return fmt.Errorf("unsupported resource %v", keyParts[0])
 	}
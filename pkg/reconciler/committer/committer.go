@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/google/go-cmp/cmp"
@@ -30,6 +31,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/conditions"
 )
 
 // Resource is a generic wrapper around resources so we can generate patches
@@ -117,11 +120,39 @@ func NewCommitter[R runtime.Object, P Patcher[R], Sp any, St any](patcher Cluste
 		}
 
 		logger.V(2).Info(fmt.Sprintf("patching %s", focusType), "patch", string(patchBytes))
-		_, err = patcher.Cluster(clusterName).Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, subresources...)
+		patched, err := patcher.Cluster(clusterName).Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, subresources...)
 		if err != nil {
 			return fmt.Errorf("failed to patch %s %s|%s: %w", focusType, clusterName, name, err)
 		}
 
+		if statusChanged {
+			recordConditionTransitions(old, patched)
+		}
+
 		return nil
 	}
 }
+
+// recordConditionTransitions records the condition transition metrics of a successful status patch,
+// if R has conditions.
+func recordConditionTransitions[R runtime.Object, Sp any, St any](old *Resource[Sp, St], patched R) {
+	after, ok := any(patched).(conditions.Getter)
+	if !ok {
+		return
+	}
+	t := reflect.TypeOf(patched)
+	if t.Kind() != reflect.Ptr || reflect.ValueOf(patched).IsNil() {
+		return
+	}
+
+	// decode the old status into an R to compare the conditions
+	data, err := json.Marshal(old)
+	if err != nil {
+		return
+	}
+	before, ok := reflect.New(t.Elem()).Interface().(conditions.Getter)
+	if !ok || json.Unmarshal(data, before) != nil {
+		return
+	}
+	conditions.RecordTransitions(before, after)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventttl
+
+import (
+	"context"
+	"time"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1/helper"
+	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/logging"
+)
+
+const (
+	ControllerName = "kcp-event-ttl"
+)
+
+var eventsGVR = corev1.SchemeGroupVersion.WithResource("events")
+
+// Controller deletes the events of tenant workspaces once they are older than the tenant event TTL.
+// The storage layer only knows a single TTL for all events, which is too long for the volume of
+// events many tenant workspaces produce together.
+type Controller struct {
+	kubeClusterClient                     kcpkubernetesclientset.ClusterInterface
+	dynamicDiscoverySharedInformerFactory *informer.DynamicDiscoverySharedInformerFactory
+
+	ttl time.Duration
+	now func() time.Time
+
+	deleteEvent func(ctx context.Context, cluster logicalcluster.Name, namespace, name string, uid types.UID) error
+}
+
+// NewController returns a new Controller deleting tenant events older than ttl.
+func NewController(
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
+	dynamicDiscoverySharedInformerFactory *informer.DynamicDiscoverySharedInformerFactory,
+	ttl time.Duration,
+) *Controller {
+	return &Controller{
+		kubeClusterClient:                     kubeClusterClient,
+		dynamicDiscoverySharedInformerFactory: dynamicDiscoverySharedInformerFactory,
+
+		ttl: ttl,
+		now: time.Now,
+
+		deleteEvent: func(ctx context.Context, cluster logicalcluster.Name, namespace, name string, uid types.UID) error {
+			return kubeClusterClient.Cluster(cluster).CoreV1().Events(namespace).Delete(ctx, name, metav1.DeleteOptions{
+				Preconditions: &metav1.Preconditions{UID: &uid},
+			})
+		},
+	}
+}
+
+// Start sweeps expired tenant events periodically until the context is done.
+func (c *Controller) Start(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	// sweeping at a tenth of the TTL keeps events around at most 10% longer than configured.
+	period := c.ttl / 10
+	if period < time.Minute {
+		period = time.Minute
+	}
+	wait.UntilWithContext(ctx, c.sweep, period)
+}
+
+func (c *Controller) sweep(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+
+	genericInformer, err := c.dynamicDiscoverySharedInformerFactory.ForResource(eventsGVR)
+	if err != nil {
+		logger.Error(err, "failed to get events informer")
+		return
+	}
+	if !genericInformer.Informer().HasSynced() {
+		logger.V(4).Info("events informer not synced yet")
+		return
+	}
+
+	objs, err := genericInformer.Lister().List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "failed to list events")
+		return
+	}
+
+	deleted := c.deleteExpired(ctx, objs)
+	if deleted > 0 {
+		logger.V(2).Info("deleted expired tenant events", "count", deleted)
+	}
+}
+
+// deleteExpired deletes the given events that belong to tenant workspaces and are older than the TTL.
+func (c *Controller) deleteExpired(ctx context.Context, objs []runtime.Object) int {
+	logger := klog.FromContext(ctx)
+	expiry := c.now().Add(-c.ttl)
+
+	deleted := 0
+	for _, obj := range objs {
+		event, err := meta.Accessor(obj)
+		if err != nil {
+			utilruntime.HandleError(err)
+			continue
+		}
+		cluster := logicalcluster.From(event)
+		if !helper.IsTenantCluster(cluster) || !event.GetCreationTimestamp().Time.Before(expiry) {
+			continue
+		}
+
+		if err := c.deleteEvent(ctx, cluster, event.GetNamespace(), event.GetName(), event.GetUID()); err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+			logger.Error(err, "failed to delete expired event", "cluster", cluster, "namespace", event.GetNamespace(), "name", event.GetName())
+			continue
+		}
+		deleted++
+	}
+	return deleted
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventttl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func event(cluster, name string, created time.Time) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations:       map[string]string{logicalcluster.AnnotationKey: cluster},
+			Namespace:         "default",
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+}
+
+func TestDeleteExpired(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	var deleted []string
+	c := &Controller{
+		ttl: 10 * time.Minute,
+		now: func() time.Time { return now },
+		deleteEvent: func(ctx context.Context, cluster logicalcluster.Name, namespace, name string, uid types.UID) error {
+			deleted = append(deleted, cluster.String()+"|"+name)
+			return nil
+		},
+	}
+
+	count := c.deleteExpired(context.Background(), []runtime.Object{
+		event("root:org:ws", "expired", now.Add(-time.Hour)),
+		event("root:org:ws", "fresh", now.Add(-time.Minute)),
+		event("root", "root-expired", now.Add(-time.Hour)),
+		event("system:admin", "system-expired", now.Add(-time.Hour)),
+	})
+	require.Equal(t, 1, count)
+	require.Equal(t, []string{"root:org:ws|expired"}, deleted)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventttl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func DefaultOptions() *Options {
+	return &Options{}
+}
+
+func BindOptions(o *Options, fs *pflag.FlagSet) *Options {
+	fs.DurationVar(&o.TenantEventTTL, "tenant-event-ttl", o.TenantEventTTL, "Amount of time to retain events of tenant workspaces. Must be shorter than --event-ttl to have an effect. 0 means events of tenant workspaces are retained as long as all other events.")
+	return o
+}
+
+type Options struct {
+	TenantEventTTL time.Duration
+}
+
+func (o *Options) Validate() error {
+	if o.TenantEventTTL < 0 {
+		return fmt.Errorf("--tenant-event-ttl must be >=0 (%s)", o.TenantEventTTL)
+	}
+	if o.TenantEventTTL > 0 && o.TenantEventTTL < time.Minute {
+		return fmt.Errorf("--tenant-event-ttl must be at least 1m (%s)", o.TenantEventTTL)
+	}
+	return nil
+}
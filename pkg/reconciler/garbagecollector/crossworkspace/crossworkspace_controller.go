@@ -0,0 +1,379 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossworkspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	kcpmetadataclient "github.com/kcp-dev/client-go/metadata"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancyv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/logging"
+)
+
+const (
+	ControllerName = "kcp-cross-workspace-garbage-collector"
+
+	// unknownOwnerRecheckPeriod is how often dependents are rechecked whose owners cannot be
+	// looked up on this shard.
+	unknownOwnerRecheckPeriod = 10 * time.Minute
+)
+
+// Controller deletes objects whose owners, listed in the experimental.tenancy.kcp.dev/owners
+// annotation, are all gone. Contrary to owner references, these owners can live in other workspaces,
+// which the per-workspace garbage collectors do not see.
+//
+// Owners are only considered gone if their workspace is scheduled to this shard and a live lookup
+// confirms they do not exist (anymore), or if their workspace was deleted. Dependents with owners on
+// other shards are kept.
+//
+// All lookups go through the listers of the partial metadata informers of the dynamic discovery
+// informer factory, i.e. no full objects are held, and no informers are created for resources
+// the factory does not inform on.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	ddsif     *informer.DynamicDiscoverySharedInformerFactory
+	shardName string
+
+	getClusterWorkspace     func(parent logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	getLiveClusterWorkspace func(ctx context.Context, parent logicalcluster.Name, name string) (metav1.Object, error)
+	gvrFor                  func(gr schema.GroupResource) (schema.GroupVersionResource, bool)
+	getDependent            func(gvr schema.GroupVersionResource, cluster logicalcluster.Name, namespace, name string) (runtime.Object, error)
+	getOwner                func(gvr schema.GroupVersionResource, cluster logicalcluster.Name, name string) (metav1.Object, error)
+	getLiveOwner            func(ctx context.Context, gvr schema.GroupVersionResource, cluster logicalcluster.Name, name string) (metav1.Object, error)
+	deleteDependent         func(ctx context.Context, gvr schema.GroupVersionResource, cluster logicalcluster.Name, namespace, name string, uid types.UID) error
+
+	// lock guards the fields in this group
+	lock sync.Mutex
+	// dependents maps owner keys to the queue keys of their dependents.
+	dependents map[string]sets.String
+	// owners maps queue keys of dependents to their owner keys.
+	owners map[string][]string
+}
+
+// NewController returns a new Controller.
+func NewController(
+	shardName string,
+	metadataClient kcpmetadataclient.ClusterInterface,
+	dynamicDiscoverySharedInformerFactory *informer.DynamicDiscoverySharedInformerFactory,
+	clusterWorkspaceInformer tenancyv1alpha1informers.ClusterWorkspaceClusterInformer,
+) *Controller {
+	listerFor := func(gvr schema.GroupVersionResource) (kcpcache.GenericClusterLister, bool) {
+		listers, _ := dynamicDiscoverySharedInformerFactory.Listers()
+		lister, found := listers[gvr]
+		return lister, found
+	}
+
+	c := &Controller{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+
+		ddsif:     dynamicDiscoverySharedInformerFactory,
+		shardName: shardName,
+
+		getClusterWorkspace: func(parent logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			return clusterWorkspaceInformer.Lister().Cluster(parent).Get(name)
+		},
+		getLiveClusterWorkspace: func(ctx context.Context, parent logicalcluster.Name, name string) (metav1.Object, error) {
+			return metadataClient.Cluster(parent).Resource(tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspaces")).Get(ctx, name, metav1.GetOptions{})
+		},
+		gvrFor: func(gr schema.GroupResource) (schema.GroupVersionResource, bool) {
+			listers, _ := dynamicDiscoverySharedInformerFactory.Listers()
+			for gvr := range listers {
+				if gvr.GroupResource() == gr {
+					return gvr, true
+				}
+			}
+			return schema.GroupVersionResource{}, false
+		},
+		getDependent: func(gvr schema.GroupVersionResource, cluster logicalcluster.Name, namespace, name string) (runtime.Object, error) {
+			lister, found := listerFor(gvr)
+			if !found {
+				return nil, apierrors.NewNotFound(gvr.GroupResource(), name)
+			}
+			if namespace != "" {
+				return lister.ByCluster(cluster).ByNamespace(namespace).Get(name)
+			}
+			return lister.ByCluster(cluster).Get(name)
+		},
+		getOwner: func(gvr schema.GroupVersionResource, cluster logicalcluster.Name, name string) (metav1.Object, error) {
+			lister, found := listerFor(gvr)
+			if !found {
+				return nil, apierrors.NewNotFound(gvr.GroupResource(), name)
+			}
+			obj, err := lister.ByCluster(cluster).Get(name)
+			if err != nil {
+				return nil, err
+			}
+			return meta.Accessor(obj)
+		},
+		getLiveOwner: func(ctx context.Context, gvr schema.GroupVersionResource, cluster logicalcluster.Name, name string) (metav1.Object, error) {
+			return metadataClient.Cluster(cluster).Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+		},
+		deleteDependent: func(ctx context.Context, gvr schema.GroupVersionResource, cluster logicalcluster.Name, namespace, name string, uid types.UID) error {
+			background := metav1.DeletePropagationBackground
+			return metadataClient.Cluster(cluster).Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{
+				Preconditions:     &metav1.Preconditions{UID: &uid},
+				PropagationPolicy: &background,
+			})
+		},
+
+		dependents: map[string]sets.String{},
+		owners:     map[string][]string{},
+	}
+
+	logger := logging.WithReconciler(klog.Background(), ControllerName)
+	c.ddsif.AddEventHandler(informer.GVREventHandlerFuncs{
+		AddFunc: func(gvr schema.GroupVersionResource, obj interface{}) {
+			c.enqueueDependent(logger, gvr, obj)
+			c.enqueueDependentsOf(logger, gvr, obj)
+		},
+		UpdateFunc: func(gvr schema.GroupVersionResource, oldObj, obj interface{}) {
+			c.enqueueDependent(logger, gvr, obj)
+		},
+		DeleteFunc: func(gvr schema.GroupVersionResource, obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			c.forgetDependent(gvr, obj)
+			c.enqueueDependentsOf(logger, gvr, obj)
+		},
+	})
+
+	return c
+}
+
+func queueKeyFor(gvr schema.GroupVersionResource, obj interface{}) (string, error) {
+	key, err := kcpcache.MetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{gvr.Resource, gvr.Version, gvr.Group}, ".") + "::" + key, nil
+}
+
+func ownerKeyFor(cluster logicalcluster.Name, gr schema.GroupResource, name string) string {
+	return strings.Join([]string{cluster.String(), gr.Group, gr.Resource, name}, "|")
+}
+
+// enqueueDependent indexes the owners of an object with owners annotation and adds it to the queue.
+func (c *Controller) enqueueDependent(logger logr.Logger, gvr schema.GroupVersionResource, obj interface{}) {
+	dependent, err := meta.Accessor(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	if _, found := dependent.GetAnnotations()[tenancyv1alpha1.ExperimentalCrossWorkspaceOwnersAnnotationKey]; !found {
+		// the annotation might have been removed
+		c.forgetDependent(gvr, obj)
+		return
+	}
+
+	key, err := queueKeyFor(gvr, obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	owners, err := ownersOf(dependent)
+	if err != nil {
+		logging.WithQueueKey(logger, key).Error(err, "invalid owners annotation")
+		c.forgetDependent(gvr, obj)
+		return
+	}
+
+	cluster := logicalcluster.From(dependent)
+	ownerKeys := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		ownerKeys = append(ownerKeys, ownerKeyFor(owner.clusterFor(cluster), owner.groupResource(), owner.Name))
+	}
+	c.index(key, ownerKeys)
+
+	logging.WithQueueKey(logger, key).V(4).Info("queueing dependent")
+	c.queue.Add(key)
+}
+
+// enqueueDependentsOf adds the dependents of a cluster-scoped object to the queue.
+func (c *Controller) enqueueDependentsOf(logger logr.Logger, gvr schema.GroupVersionResource, obj interface{}) {
+	owner, err := meta.Accessor(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	if owner.GetNamespace() != "" {
+		return
+	}
+	ownerKey := ownerKeyFor(logicalcluster.From(owner), gvr.GroupResource(), owner.GetName())
+
+	c.lock.Lock()
+	keys := c.dependents[ownerKey].List()
+	c.lock.Unlock()
+
+	for _, key := range keys {
+		logging.WithQueueKey(logger, key).V(4).Info("queueing dependent because of owner event", "owner", ownerKey)
+		c.queue.Add(key)
+	}
+}
+
+func (c *Controller) forgetDependent(gvr schema.GroupVersionResource, obj interface{}) {
+	key, err := queueKeyFor(gvr, obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.index(key, nil)
+}
+
+// index replaces the owners of the dependent with the given queue key.
+func (c *Controller) index(key string, ownerKeys []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, ownerKey := range c.owners[key] {
+		c.dependents[ownerKey].Delete(key)
+		if c.dependents[ownerKey].Len() == 0 {
+			delete(c.dependents, ownerKey)
+		}
+	}
+	delete(c.owners, key)
+
+	if len(ownerKeys) == 0 {
+		return
+	}
+	c.owners[key] = ownerKeys
+	for _, ownerKey := range ownerKeys {
+		if _, found := c.dependents[ownerKey]; !found {
+			c.dependents[ownerKey] = sets.NewString()
+		}
+		c.dependents[ownerKey].Insert(key)
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(1).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	requeue, err := c.process(ctx, key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	if requeue {
+		c.queue.AddAfter(key, unknownOwnerRecheckPeriod)
+	}
+	return true
+}
+
+func (c *Controller) process(ctx context.Context, key string) (bool, error) {
+	logger := klog.FromContext(ctx)
+
+	parts := strings.SplitN(key, "::", 2)
+	if len(parts) != 2 {
+		logger.Error(errors.New("unexpected key format"), "skipping key")
+		return false, nil
+	}
+
+	gvr, _ := schema.ParseResourceArg(parts[0])
+	if gvr == nil {
+		logger.Error(errors.New("unable to parse gvr string"), "skipping key", "gvr", parts[0])
+		return false, nil
+	}
+
+	cluster, namespace, name, err := kcpcache.SplitMetaClusterNamespaceKey(parts[1])
+	if err != nil {
+		logger.Error(err, "skipping key")
+		return false, nil
+	}
+
+	obj, err := c.getDependent(*gvr, cluster, namespace, name)
+	if apierrors.IsNotFound(err) {
+		logger.V(4).Info("dependent not found")
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	dependent, ok := obj.(logging.Object)
+	if !ok {
+		logger.Error(nil, "got unexpected type", "type", fmt.Sprintf("%T", obj))
+		return false, nil // retrying won't help
+	}
+
+	logger = logging.WithObject(logger, dependent)
+	ctx = klog.NewContext(ctx, logger)
+
+	return c.reconcile(ctx, *gvr, dependent)
+}
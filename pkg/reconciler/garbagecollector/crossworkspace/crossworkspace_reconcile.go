@@ -0,0 +1,218 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossworkspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reference"
+)
+
+// ownerReference is an entry of the owners annotation.
+type ownerReference struct {
+	tenancyv1alpha1.WorkspaceObjectReference `json:",inline"`
+
+	// uid is the UID of the owner. If set, an object with the same name but
+	// a different UID is not considered the owner.
+	UID types.UID `json:"uid,omitempty"`
+}
+
+func (r ownerReference) clusterFor(from logicalcluster.Name) logicalcluster.Name {
+	return reference.ClusterFor(from, r.WorkspaceObjectReference)
+}
+
+func (r ownerReference) groupResource() schema.GroupResource {
+	return schema.GroupResource{Group: r.Group, Resource: r.Resource}
+}
+
+// ownersOf parses and validates the owners annotation of the given object.
+func ownersOf(obj metav1.Object) ([]ownerReference, error) {
+	value, found := obj.GetAnnotations()[tenancyv1alpha1.ExperimentalCrossWorkspaceOwnersAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+
+	var owners []ownerReference
+	if err := json.Unmarshal([]byte(value), &owners); err != nil {
+		return nil, fmt.Errorf("failed to decode %s annotation: %w", tenancyv1alpha1.ExperimentalCrossWorkspaceOwnersAnnotationKey, err)
+	}
+
+	fldPath := field.NewPath("metadata", "annotations").Key(tenancyv1alpha1.ExperimentalCrossWorkspaceOwnersAnnotationKey)
+	var errs field.ErrorList
+	for i, owner := range owners {
+		errs = append(errs, reference.ValidateWorkspaceObjectReference(owner.WorkspaceObjectReference, fldPath.Index(i))...)
+	}
+	if len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+
+	return owners, nil
+}
+
+type ownerState int
+
+const (
+	ownerPresent ownerState = iota
+	ownerAbsent
+	// ownerUnknown means that the owner cannot be looked up on this shard.
+	ownerUnknown
+)
+
+// reconcile deletes the dependent if all of its owners are absent. It returns whether the
+// dependent has to be rechecked later because some owners could not be looked up.
+func (c *Controller) reconcile(ctx context.Context, gvr schema.GroupVersionResource, dependent metav1.Object) (bool, error) {
+	logger := klog.FromContext(ctx)
+
+	if dependent.GetDeletionTimestamp() != nil {
+		return false, nil
+	}
+
+	owners, err := ownersOf(dependent)
+	if err != nil {
+		logger.Error(err, "invalid owners annotation")
+		return false, nil // retrying won't help
+	}
+	if len(owners) == 0 {
+		return false, nil
+	}
+
+	cluster := logicalcluster.From(dependent)
+	unknown := false
+	for _, owner := range owners {
+		state, err := c.ownerStateOf(ctx, cluster, owner)
+		if err != nil {
+			return false, err
+		}
+		switch state {
+		case ownerPresent:
+			return false, nil
+		case ownerUnknown:
+			unknown = true
+		}
+	}
+	if unknown {
+		logger.V(4).Info("some owners cannot be looked up on this shard")
+		return true, nil
+	}
+
+	logger.V(2).Info("deleting dependent because all of its owners are gone")
+	if err := c.deleteDependent(ctx, gvr, cluster, dependent.GetNamespace(), dependent.GetName(), dependent.GetUID()); err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		return false, err
+	}
+	return false, nil
+}
+
+// ownerStateOf looks up the owner, first in the informers, and if not found there, confirms
+// its absence with a live lookup to not act on stale caches.
+func (c *Controller) ownerStateOf(ctx context.Context, from logicalcluster.Name, owner ownerReference) (ownerState, error) {
+	cluster := owner.clusterFor(from)
+	state, err := c.clusterStateOf(ctx, cluster)
+	if err != nil {
+		return ownerUnknown, err
+	}
+	switch state {
+	case clusterGone:
+		return ownerAbsent, nil
+	case clusterRemote:
+		return ownerUnknown, nil
+	}
+
+	gvr, found := c.gvrFor(owner.groupResource())
+	if !found {
+		return ownerUnknown, nil
+	}
+
+	obj, err := c.getOwner(gvr, cluster, owner.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ownerUnknown, err
+	}
+	if err == nil && (owner.UID == "" || obj.GetUID() == owner.UID) {
+		return ownerPresent, nil
+	}
+
+	obj, err = c.getLiveOwner(ctx, gvr, cluster, owner.Name)
+	if apierrors.IsNotFound(err) {
+		return ownerAbsent, nil
+	}
+	if err != nil {
+		return ownerUnknown, err
+	}
+	if owner.UID != "" && obj.GetUID() != owner.UID {
+		return ownerAbsent, nil
+	}
+	return ownerPresent, nil
+}
+
+type clusterState int
+
+const (
+	clusterLocal clusterState = iota
+	// clusterRemote means that the logical cluster is scheduled to another shard, or that this
+	// cannot be determined on this shard.
+	clusterRemote
+	// clusterGone means that the workspace of the logical cluster was deleted.
+	clusterGone
+)
+
+// clusterStateOf returns whether the logical cluster is scheduled to this shard. A workspace is
+// only considered deleted if its parent is on this shard and a live lookup confirms it does not
+// exist (anymore), or if its parent was deleted.
+func (c *Controller) clusterStateOf(ctx context.Context, cluster logicalcluster.Name) (clusterState, error) {
+	if cluster == tenancyv1alpha1.RootCluster {
+		if c.shardName == tenancyv1alpha1.RootShard {
+			return clusterLocal, nil
+		}
+		return clusterRemote, nil
+	}
+
+	parent, name := cluster.Split()
+	ws, err := c.getClusterWorkspace(parent, name)
+	if err == nil {
+		if ws.Status.Location.Current == c.shardName {
+			return clusterLocal, nil
+		}
+		return clusterRemote, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return clusterRemote, err
+	}
+
+	parentState, err := c.clusterStateOf(ctx, parent)
+	if err != nil || parentState != clusterLocal {
+		return parentState, err
+	}
+
+	if _, err := c.getLiveClusterWorkspace(ctx, parent, name); apierrors.IsNotFound(err) {
+		return clusterGone, nil
+	} else if err != nil {
+		return clusterRemote, err
+	}
+	// the informer is stale, recheck later
+	return clusterRemote, nil
+}
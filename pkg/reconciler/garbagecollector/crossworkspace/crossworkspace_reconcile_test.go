@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crossworkspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func object(cluster, name string, uid types.UID, owners string) *metav1.PartialObjectMetadata {
+	obj := &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+			Name:        name,
+			UID:         uid,
+		},
+	}
+	if owners != "" {
+		obj.Annotations[tenancyv1alpha1.ExperimentalCrossWorkspaceOwnersAnnotationKey] = owners
+	}
+	return obj
+}
+
+func TestReconcile(t *testing.T) {
+	exportsGVR := schema.GroupVersionResource{Group: "apis.kcp.dev", Version: "v1alpha1", Resource: "apiexports"}
+	workspaces := map[string]*tenancyv1alpha1.ClusterWorkspace{
+		"root|org":        {Status: tenancyv1alpha1.ClusterWorkspaceStatus{Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "root"}}},
+		"root:org|local":  {Status: tenancyv1alpha1.ClusterWorkspaceStatus{Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "root"}}},
+		"root:org|remote": {Status: tenancyv1alpha1.ClusterWorkspaceStatus{Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "other"}}},
+	}
+
+	tests := map[string]struct {
+		owners         string
+		cached         []*metav1.PartialObjectMetadata
+		live           []*metav1.PartialObjectMetadata
+		liveWorkspaces []string
+		wantDeleted    bool
+		wantRequeue    bool
+	}{
+		"owner in other local workspace exists": {
+			owners: `[{"path":"root:org:local","group":"apis.kcp.dev","resource":"apiexports","name":"export"}]`,
+			cached: []*metav1.PartialObjectMetadata{object("root:org:local", "export", "1", "")},
+		},
+		"owner in other local workspace is gone": {
+			owners:      `[{"path":"root:org:local","group":"apis.kcp.dev","resource":"apiexports","name":"export"}]`,
+			wantDeleted: true,
+		},
+		"owner in same workspace is gone": {
+			owners:      `[{"group":"apis.kcp.dev","resource":"apiexports","name":"export"}]`,
+			wantDeleted: true,
+		},
+		"owner is missing in stale cache, but exists": {
+			owners: `[{"path":"root:org:local","group":"apis.kcp.dev","resource":"apiexports","name":"export"}]`,
+			live:   []*metav1.PartialObjectMetadata{object("root:org:local", "export", "1", "")},
+		},
+		"owner was recreated with other uid": {
+			owners:      `[{"path":"root:org:local","group":"apis.kcp.dev","resource":"apiexports","name":"export","uid":"1"}]`,
+			cached:      []*metav1.PartialObjectMetadata{object("root:org:local", "export", "2", "")},
+			live:        []*metav1.PartialObjectMetadata{object("root:org:local", "export", "2", "")},
+			wantDeleted: true,
+		},
+		"one of two owners is gone": {
+			owners: `[{"path":"root:org:local","group":"apis.kcp.dev","resource":"apiexports","name":"gone"},{"path":"root:org:local","group":"apis.kcp.dev","resource":"apiexports","name":"export"}]`,
+			cached: []*metav1.PartialObjectMetadata{object("root:org:local", "export", "1", "")},
+		},
+		"owner on other shard": {
+			owners:      `[{"path":"root:org:remote","group":"apis.kcp.dev","resource":"apiexports","name":"export"}]`,
+			wantRequeue: true,
+		},
+		"owner workspace was deleted": {
+			owners:      `[{"path":"root:org:deleted","group":"apis.kcp.dev","resource":"apiexports","name":"export"}]`,
+			wantDeleted: true,
+		},
+		"parent of owner workspace was deleted": {
+			owners:      `[{"path":"root:org:deleted:child","group":"apis.kcp.dev","resource":"apiexports","name":"export"}]`,
+			wantDeleted: true,
+		},
+		"owner workspace is missing in stale cache, but exists": {
+			owners:         `[{"path":"root:org:new","group":"apis.kcp.dev","resource":"apiexports","name":"export"}]`,
+			liveWorkspaces: []string{"root:org|new"},
+			wantRequeue:    true,
+		},
+		"owner workspace below workspace on other shard": {
+			owners:      `[{"path":"root:org:remote:child","group":"apis.kcp.dev","resource":"apiexports","name":"export"}]`,
+			wantRequeue: true,
+		},
+		"owner resource not served": {
+			owners:      `[{"path":"root:org:local","group":"example.com","resource":"widgets","name":"export"}]`,
+			wantRequeue: true,
+		},
+		"invalid annotation": {
+			owners: `[{"path":"root:org:local","resource":"apiexports"}]`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			lookup := func(objs []*metav1.PartialObjectMetadata, gvr schema.GroupVersionResource, cluster logicalcluster.Name, name string) (metav1.Object, error) {
+				for _, obj := range objs {
+					if logicalcluster.From(obj) == cluster && obj.Name == name {
+						return obj, nil
+					}
+				}
+				return nil, apierrors.NewNotFound(gvr.GroupResource(), name)
+			}
+
+			deleted := false
+			c := &Controller{
+				shardName: "root",
+				getClusterWorkspace: func(parent logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					if ws, found := workspaces[parent.String()+"|"+name]; found {
+						return ws, nil
+					}
+					return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+				},
+				getLiveClusterWorkspace: func(ctx context.Context, parent logicalcluster.Name, name string) (metav1.Object, error) {
+					require.NotEqual(t, "root:org:remote", parent.String(), "workspaces of other shards must not be looked up")
+					for _, key := range tt.liveWorkspaces {
+						if key == parent.String()+"|"+name {
+							return &metav1.ObjectMeta{Name: name}, nil
+						}
+					}
+					return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+				},
+				gvrFor: func(gr schema.GroupResource) (schema.GroupVersionResource, bool) {
+					return exportsGVR, gr == exportsGVR.GroupResource()
+				},
+				getOwner: func(gvr schema.GroupVersionResource, cluster logicalcluster.Name, name string) (metav1.Object, error) {
+					return lookup(tt.cached, gvr, cluster, name)
+				},
+				getLiveOwner: func(ctx context.Context, gvr schema.GroupVersionResource, cluster logicalcluster.Name, name string) (metav1.Object, error) {
+					return lookup(tt.live, gvr, cluster, name)
+				},
+				deleteDependent: func(ctx context.Context, gvr schema.GroupVersionResource, cluster logicalcluster.Name, namespace, name string, uid types.UID) error {
+					require.Equal(t, logicalcluster.New("root:org:local"), cluster)
+					require.Equal(t, "dependent", name)
+					deleted = true
+					return nil
+				},
+			}
+
+			dependent := object("root:org:local", "dependent", "d", tt.owners)
+			requeue, err := c.reconcile(context.Background(), schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, dependent)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRequeue, requeue, "requeue")
+			require.Equal(t, tt.wantDeleted, deleted, "deleted")
+		})
+	}
+}
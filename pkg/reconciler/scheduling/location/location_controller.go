@@ -45,6 +45,7 @@ import (
 	workloadv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/workload/v1alpha1"
 	schedulingv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/scheduling/v1alpha1"
 	workloadv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/workload/v1alpha1"
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	"github.com/kcp-dev/kcp/pkg/logging"
 )
 
@@ -214,6 +215,10 @@ func (c *controller) process(ctx context.Context, key string) error {
 		return nil
 	}
 
+	if !kcpfeatures.DefaultWorkspaceFeatureGate.Enabled(kcpfeatures.LocationAPI, clusterName) {
+		return nil // the scheduling APIs are disabled for this workspace
+	}
+
 	obj, err := c.locationLister.Cluster(clusterName).Get(name)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -47,6 +47,7 @@ import (
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	schedulingv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/scheduling/v1alpha1"
 	schedulingv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/scheduling/v1alpha1"
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	"github.com/kcp-dev/kcp/pkg/logging"
 )
 
@@ -164,6 +165,18 @@ func (c *controller) enqueuePlacement(obj interface{}) {
 	c.queue.Add(key)
 }
 
+func (c *controller) enqueuePlacementAfter(placement *schedulingv1alpha1.Placement, duration time.Duration) {
+	key, err := kcpcache.MetaClusterNamespaceKeyFunc(placement)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing Placement for next schedule window transition", "after", duration)
+	c.queue.AddAfter(key, duration)
+}
+
 // enqueueNamespace enqueues all placements for the namespace.
 func (c *controller) enqueueNamespace(obj interface{}) {
 	logger := logging.WithReconciler(klog.Background(), ControllerName)
@@ -275,6 +288,10 @@ func (c *controller) process(ctx context.Context, key string) error {
 		return nil
 	}
 
+	if !kcpfeatures.DefaultWorkspaceFeatureGate.Enabled(kcpfeatures.LocationAPI, clusterName) {
+		return nil // the scheduling APIs are disabled for this workspace
+	}
+
 	obj, err := c.placementLister.Cluster(clusterName).Get(name)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -22,6 +22,7 @@ import (
 	"github.com/kcp-dev/logicalcluster/v2"
 
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/scheduling/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reference"
 )
 
 func indexByLocationWorkspace(obj interface{}) ([]string, error) {
@@ -30,9 +31,5 @@ func indexByLocationWorkspace(obj interface{}) ([]string, error) {
 		return []string{}, fmt.Errorf("obj is supposed to be a Placement, but is %T", obj)
 	}
 
-	if len(placement.Spec.LocationWorkspace) == 0 {
-		return []string{logicalcluster.From(placement).String()}, nil
-	}
-
-	return []string{placement.Spec.LocationWorkspace}, nil
+	return []string{reference.ClusterForPath(logicalcluster.From(placement), placement.Spec.LocationWorkspace).String()}, nil
 }
@@ -18,6 +18,7 @@ package placement
 
 import (
 	"context"
+	"time"
 
 	"github.com/kcp-dev/logicalcluster/v2"
 
@@ -42,7 +43,10 @@ type reconciler interface {
 func (c *controller) reconcile(ctx context.Context, placement *schedulingv1alpha1.Placement) error {
 	reconcilers := []reconciler{
 		&placementReconciler{
-			listLocations: c.listLocations,
+			listLocations:  c.listLocations,
+			listPlacements: c.listPlacements,
+			now:            time.Now,
+			enqueueAfter:   c.enqueuePlacementAfter,
 		},
 		&placementNamespaceReconciler{
 			listNamespacesWithAnnotation: c.listNamespacesWithAnnotation,
@@ -70,6 +74,10 @@ func (c *controller) listLocations(clusterName logicalcluster.Name) ([]*scheduli
 	return c.locationLister.Cluster(clusterName).List(labels.Everything())
 }
 
+func (c *controller) listPlacements(clusterName logicalcluster.Name) ([]*schedulingv1alpha1.Placement, error) {
+	return c.placementLister.Cluster(clusterName).List(labels.Everything())
+}
+
 func (c *controller) listNamespacesWithAnnotation(clusterName logicalcluster.Name) ([]*corev1.Namespace, error) {
 	items, err := c.namespaceLister.Cluster(clusterName).List(labels.Everything())
 	if err != nil {
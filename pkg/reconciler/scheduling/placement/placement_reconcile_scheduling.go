@@ -19,34 +19,59 @@ package placement
 import (
 	"context"
 	"math/rand"
+	"time"
 
 	"github.com/kcp-dev/logicalcluster/v2"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
 	"k8s.io/kube-openapi/pkg/util/sets"
 
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/scheduling/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/conditions"
+	"github.com/kcp-dev/kcp/pkg/reference"
 )
 
 // placementReconciler watches namespaces within a cluster workspace and assigns those to location from
 // the location domain of the cluster workspace.
 type placementReconciler struct {
-	listLocations func(clusterName logicalcluster.Name) ([]*schedulingv1alpha1.Location, error)
+	listLocations  func(clusterName logicalcluster.Name) ([]*schedulingv1alpha1.Location, error)
+	listPlacements func(clusterName logicalcluster.Name) ([]*schedulingv1alpha1.Placement, error)
+
+	// now and enqueueAfter are only used for placements with a schedule.
+	now          func() time.Time
+	enqueueAfter func(placement *schedulingv1alpha1.Placement, duration time.Duration)
 }
 
 func (r *placementReconciler) reconcile(ctx context.Context, placement *schedulingv1alpha1.Placement) (reconcileStatus, *schedulingv1alpha1.Placement, error) {
 	// get location workspace at first
-	var locationWorkspace logicalcluster.Name
-	if len(placement.Spec.LocationWorkspace) > 0 {
-		locationWorkspace = logicalcluster.New(placement.Spec.LocationWorkspace)
-	} else {
-		locationWorkspace = logicalcluster.From(placement)
+	locationWorkspace := reference.ClusterForPath(logicalcluster.From(placement), placement.Spec.LocationWorkspace)
+
+	locationSelectors := placement.Spec.LocationSelectors
+	activeWindow := ""
+	if placement.Spec.Schedule != nil {
+		schedule, err := parseSchedule(placement.Spec.Schedule)
+		if err != nil {
+			conditions.MarkFalse(placement, schedulingv1alpha1.PlacementReady, schedulingv1alpha1.ScheduleInvalidReason, conditionsv1alpha1.ConditionSeverityError, err.Error())
+			return reconcileStatusContinue, placement, nil // retrying won't help
+		}
+
+		now := r.now()
+		if window := schedule.activeWindow(now); window != nil {
+			locationSelectors = window.LocationSelectors
+			activeWindow = window.Name
+		}
+		if next, found := schedule.nextTransition(now); found {
+			r.enqueueAfter(placement, next.Sub(now))
+		}
 	}
+	windowChanged := activeWindow != placement.Status.ActiveWindow
+	placement.Status.ActiveWindow = activeWindow
 
-	validLocationNames, err := r.validLocationNames(placement, locationWorkspace)
+	validLocationNames, err := r.validLocationNames(placement, locationSelectors, locationWorkspace)
 	if err != nil {
 		conditions.MarkFalse(placement, schedulingv1alpha1.PlacementReady, schedulingv1alpha1.LocationNotFoundReason, conditionsv1alpha1.ConditionSeverityError, err.Error())
 		return reconcileStatusContinue, placement, err
@@ -54,6 +79,21 @@ func (r *placementReconciler) reconcile(ctx context.Context, placement *scheduli
 
 	switch placement.Status.Phase {
 	case schedulingv1alpha1.PlacementBound:
+		// on a schedule window transition, move the bound namespaces to a location of the new window. The
+		// namespaces are rescheduled, giving the workloads the usual grace period on the old sync targets.
+		if windowChanged && validLocationNames.Len() > 0 && !isValidLocationSelected(placement, locationWorkspace, validLocationNames) {
+			locationName, err := r.chooseLocation(placement, locationWorkspace, validLocationNames)
+			if err != nil {
+				return reconcileStatusContinue, placement, err
+			}
+			r.transition(ctx, placement, activeWindow, &schedulingv1alpha1.LocationReference{
+				Path:         locationWorkspace.String(),
+				LocationName: locationName,
+			})
+			conditions.MarkTrue(placement, schedulingv1alpha1.PlacementReady)
+			return reconcileStatusContinue, placement, nil
+		}
+
 		// if selected location becomes invalid when placement is in bound state, set PlacementReady
 		// to false.
 		if !isValidLocationSelected(placement, locationWorkspace, validLocationNames) {
@@ -90,17 +130,18 @@ func (r *placementReconciler) reconcile(ctx context.Context, placement *scheduli
 		return reconcileStatusContinue, placement, nil
 	}
 
-	candidates := make([]string, 0, validLocationNames.Len())
-	for loc := range validLocationNames {
-		candidates = append(candidates, loc)
+	locationName, err := r.chooseLocation(placement, locationWorkspace, validLocationNames)
+	if err != nil {
+		return reconcileStatusContinue, placement, err
 	}
-
-	// TODO(qiujian16): two placements could select the same location. We should
-	// consider whether placements in a workspace should always select different locations.
-	chosenLocation := candidates[rand.Intn(len(candidates))]
-	placement.Status.SelectedLocation = &schedulingv1alpha1.LocationReference{
+	selectedLocation := &schedulingv1alpha1.LocationReference{
 		Path:         locationWorkspace.String(),
-		LocationName: chosenLocation,
+		LocationName: locationName,
+	}
+	if windowChanged {
+		r.transition(ctx, placement, activeWindow, selectedLocation)
+	} else {
+		placement.Status.SelectedLocation = selectedLocation
 	}
 	placement.Status.Phase = schedulingv1alpha1.PlacementUnbound
 	conditions.MarkTrue(placement, schedulingv1alpha1.PlacementReady)
@@ -108,7 +149,147 @@ func (r *placementReconciler) reconcile(ctx context.Context, placement *scheduli
 	return reconcileStatusContinue, placement, nil
 }
 
-func (r *placementReconciler) validLocationNames(placement *schedulingv1alpha1.Placement, locationWorkspace logicalcluster.Name) (sets.String, error) {
+// transition selects the given location because of a schedule window transition, and records
+// the transition in the status.
+func (r *placementReconciler) transition(ctx context.Context, placement *schedulingv1alpha1.Placement, window string, to *schedulingv1alpha1.LocationReference) {
+	from := placement.Status.SelectedLocation
+	placement.Status.SelectedLocation = to
+	if equality.Semantic.DeepEqual(from, to) {
+		return
+	}
+
+	klog.FromContext(ctx).V(2).Info("moving placement to another location because of a schedule window transition", "window", window, "from", from, "to", to)
+	placement.Status.Transitions = append(placement.Status.Transitions, schedulingv1alpha1.PlacementTransition{
+		Time:   metav1.NewTime(r.now()),
+		Window: window,
+		From:   from,
+		To:     to,
+	})
+	if n := len(placement.Status.Transitions); n > schedulingv1alpha1.MaxPlacementTransitions {
+		placement.Status.Transitions = placement.Status.Transitions[n-schedulingv1alpha1.MaxPlacementTransitions:]
+	}
+}
+
+// chooseLocation picks one of the valid locations. The candidates are first narrowed down by the
+// spread constraints of the placement, then to those with the highest affinity score. Ties are
+// broken randomly.
+func (r *placementReconciler) chooseLocation(placement *schedulingv1alpha1.Placement, locationWorkspace logicalcluster.Name, validLocationNames sets.String) (string, error) {
+	candidates := validLocationNames.List()
+
+	if len(placement.Spec.SpreadConstraints) > 0 {
+		placements, err := r.listPlacements(logicalcluster.From(placement))
+		if err != nil {
+			return "", err
+		}
+		candidates = spreadCandidates(placement, locationWorkspace, candidates, placements)
+	}
+
+	if len(placement.Spec.Affinity) > 0 || len(placement.Spec.AntiAffinity) > 0 {
+		locations, err := r.listLocations(locationWorkspace)
+		if err != nil {
+			return "", err
+		}
+		locationLabels := make(map[string]labels.Set, len(locations))
+		for _, loc := range locations {
+			locationLabels[loc.Name] = loc.Labels
+		}
+		candidates = highestScoredCandidates(placement, candidates, locationLabels)
+	}
+
+	return candidates[rand.Intn(len(candidates))], nil
+}
+
+// spreadCandidates returns the candidates that keep every spread constraint of the placement
+// satisfied, i.e. selecting the location does not make it exceed the least used candidate by more
+// than maxSkew placements. The least used candidate always qualifies, so the result is never empty.
+func spreadCandidates(placement *schedulingv1alpha1.Placement, locationWorkspace logicalcluster.Name, candidates []string, placements []*schedulingv1alpha1.Placement) []string {
+	for _, constraint := range placement.Spec.SpreadConstraints {
+		selector, err := metav1.LabelSelectorAsSelector(&constraint.PlacementSelector)
+		if err != nil {
+			// skip this constraint
+			continue
+		}
+
+		counts := make(map[string]int, len(candidates))
+		for _, name := range candidates {
+			counts[name] = 0
+		}
+		for _, other := range placements {
+			if other.Name == placement.Name || !selector.Matches(labels.Set(other.Labels)) {
+				continue
+			}
+			selected := other.Status.SelectedLocation
+			if selected == nil || selected.Path != locationWorkspace.String() {
+				continue
+			}
+			if _, found := counts[selected.LocationName]; found {
+				counts[selected.LocationName]++
+			}
+		}
+
+		minCount := -1
+		for _, count := range counts {
+			if minCount < 0 || count < minCount {
+				minCount = count
+			}
+		}
+
+		filtered := make([]string, 0, len(candidates))
+		for _, name := range candidates {
+			if counts[name]+1-minCount <= int(constraint.MaxSkew) {
+				filtered = append(filtered, name)
+			}
+		}
+		candidates = filtered
+	}
+
+	return candidates
+}
+
+// highestScoredCandidates returns the candidates with the highest affinity score. The score of a
+// location is the sum of the weights of the matching affinity terms minus the sum of the weights of
+// the matching anti-affinity terms.
+func highestScoredCandidates(placement *schedulingv1alpha1.Placement, candidates []string, locationLabels map[string]labels.Set) []string {
+	score := func(name string) int {
+		total := 0
+		for _, term := range placement.Spec.Affinity {
+			if matchesLocation(term, locationLabels[name]) {
+				total += int(term.Weight)
+			}
+		}
+		for _, term := range placement.Spec.AntiAffinity {
+			if matchesLocation(term, locationLabels[name]) {
+				total -= int(term.Weight)
+			}
+		}
+		return total
+	}
+
+	var best []string
+	bestScore := 0
+	for _, name := range candidates {
+		s := score(name)
+		switch {
+		case len(best) == 0 || s > bestScore:
+			best = []string{name}
+			bestScore = s
+		case s == bestScore:
+			best = append(best, name)
+		}
+	}
+
+	return best
+}
+
+func matchesLocation(term schedulingv1alpha1.WeightedLocationSelector, locationLabels labels.Set) bool {
+	selector, err := metav1.LabelSelectorAsSelector(&term.Selector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(locationLabels)
+}
+
+func (r *placementReconciler) validLocationNames(placement *schedulingv1alpha1.Placement, locationSelectors []metav1.LabelSelector, locationWorkspace logicalcluster.Name) (sets.String, error) {
 	selectedLocations := sets.NewString()
 
 	locations, err := r.listLocations(locationWorkspace)
@@ -121,7 +302,7 @@ func (r *placementReconciler) validLocationNames(placement *schedulingv1alpha1.P
 			continue
 		}
 
-		for _, s := range placement.Spec.LocationSelectors {
+		for _, s := range locationSelectors {
 			selector, err := metav1.LabelSelectorAsSelector(&s)
 			if err != nil {
 				// skip this selector
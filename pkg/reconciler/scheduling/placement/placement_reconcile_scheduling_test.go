@@ -20,15 +20,17 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/kcp-dev/logicalcluster/v2"
 	"github.com/stretchr/testify/require"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kube-openapi/pkg/util/sets"
 
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/scheduling/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 )
 
 func TestPlacementScheduling(t *testing.T) {
@@ -224,6 +226,233 @@ func TestPlacementScheduling(t *testing.T) {
 	}
 }
 
+func TestPlacementScheduledTransition(t *testing.T) {
+	schedule := &schedulingv1alpha1.PlacementSchedule{
+		Windows: []schedulingv1alpha1.PlacementScheduleWindow{{
+			Name:              "night",
+			Start:             "22:00",
+			End:               "06:00",
+			LocationSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"cloud": "gcp"}}},
+		}},
+	}
+	locations := []*schedulingv1alpha1.Location{
+		newLocation("aws", map[string]string{"cloud": "aws"}),
+		newLocation("gcp", map[string]string{"cloud": "gcp"}),
+	}
+	aws := &schedulingv1alpha1.LocationReference{LocationName: "aws"}
+	gcp := &schedulingv1alpha1.LocationReference{LocationName: "gcp"}
+
+	testCases := []struct {
+		name             string
+		now              time.Time
+		activeWindow     string
+		selectedLocation *schedulingv1alpha1.LocationReference
+
+		wantActiveWindow     string
+		wantSelectedLocation *schedulingv1alpha1.LocationReference
+		wantTransition       bool
+		wantRequeueAfter     time.Duration
+	}{
+		{
+			name:                 "window starts",
+			now:                  time.Date(2022, 10, 10, 22, 0, 0, 0, time.UTC),
+			selectedLocation:     aws,
+			wantActiveWindow:     "night",
+			wantSelectedLocation: gcp,
+			wantTransition:       true,
+			wantRequeueAfter:     8 * time.Hour,
+		},
+		{
+			name:                 "window stays active",
+			now:                  time.Date(2022, 10, 11, 2, 0, 0, 0, time.UTC),
+			activeWindow:         "night",
+			selectedLocation:     gcp,
+			wantActiveWindow:     "night",
+			wantSelectedLocation: gcp,
+			wantRequeueAfter:     4 * time.Hour,
+		},
+		{
+			name:                 "window ends",
+			now:                  time.Date(2022, 10, 11, 6, 30, 0, 0, time.UTC),
+			activeWindow:         "night",
+			selectedLocation:     gcp,
+			wantSelectedLocation: aws,
+			wantTransition:       true,
+			wantRequeueAfter:     15*time.Hour + 30*time.Minute,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testPlacement := &schedulingv1alpha1.Placement{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-placement",
+				},
+				Spec: schedulingv1alpha1.PlacementSpec{
+					LocationSelectors: []metav1.LabelSelector{{MatchLabels: map[string]string{"cloud": "aws"}}},
+					Schedule:          schedule,
+				},
+				Status: schedulingv1alpha1.PlacementStatus{
+					Phase:            schedulingv1alpha1.PlacementBound,
+					SelectedLocation: testCase.selectedLocation,
+					ActiveWindow:     testCase.activeWindow,
+				},
+			}
+
+			var requeueAfter time.Duration
+			reconciler := &placementReconciler{
+				listLocations: func(clusterName logicalcluster.Name) ([]*schedulingv1alpha1.Location, error) {
+					return locations, nil
+				},
+				now: func() time.Time { return testCase.now },
+				enqueueAfter: func(_ *schedulingv1alpha1.Placement, duration time.Duration) {
+					requeueAfter = duration
+				},
+			}
+			_, updated, err := reconciler.reconcile(context.TODO(), testPlacement)
+			require.NoError(t, err)
+
+			require.Equal(t, schedulingv1alpha1.PlacementPhase(schedulingv1alpha1.PlacementBound), updated.Status.Phase)
+			require.True(t, conditions.IsTrue(updated, schedulingv1alpha1.PlacementReady))
+			require.Equal(t, testCase.wantActiveWindow, updated.Status.ActiveWindow)
+			require.Equal(t, testCase.wantSelectedLocation, updated.Status.SelectedLocation)
+			require.Equal(t, testCase.wantRequeueAfter, requeueAfter)
+			if testCase.wantTransition {
+				require.Equal(t, []schedulingv1alpha1.PlacementTransition{{
+					Time:   metav1.NewTime(testCase.now),
+					Window: testCase.wantActiveWindow,
+					From:   testCase.selectedLocation,
+					To:     testCase.wantSelectedLocation,
+				}}, updated.Status.Transitions)
+			} else {
+				require.Empty(t, updated.Status.Transitions)
+			}
+		})
+	}
+}
+
+func TestChooseLocation(t *testing.T) {
+	locations := []*schedulingv1alpha1.Location{
+		newLocation("eu-1", map[string]string{"region": "eu", "tier": "gold"}),
+		newLocation("eu-2", map[string]string{"region": "eu"}),
+		newLocation("us-1", map[string]string{"region": "us", "tier": "gold"}),
+	}
+	selected := func(name string, labels map[string]string, location string) *schedulingv1alpha1.Placement {
+		return &schedulingv1alpha1.Placement{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Status: schedulingv1alpha1.PlacementStatus{
+				SelectedLocation: &schedulingv1alpha1.LocationReference{Path: "root:org", LocationName: location},
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		spec       schedulingv1alpha1.PlacementSpec
+		placements []*schedulingv1alpha1.Placement
+		want       []string
+	}{
+		"no policy": {
+			want: []string{"eu-1", "eu-2", "us-1"},
+		},
+		"affinity": {
+			spec: schedulingv1alpha1.PlacementSpec{
+				Affinity: []schedulingv1alpha1.WeightedLocationSelector{
+					{Weight: 10, Selector: metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu"}}},
+					{Weight: 5, Selector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "gold"}}},
+				},
+			},
+			want: []string{"eu-1"},
+		},
+		"anti-affinity": {
+			spec: schedulingv1alpha1.PlacementSpec{
+				AntiAffinity: []schedulingv1alpha1.WeightedLocationSelector{
+					{Weight: 1, Selector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "gold"}}},
+				},
+			},
+			want: []string{"eu-2"},
+		},
+		"affinity outweighed by anti-affinity": {
+			spec: schedulingv1alpha1.PlacementSpec{
+				Affinity: []schedulingv1alpha1.WeightedLocationSelector{
+					{Weight: 10, Selector: metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu"}}},
+				},
+				AntiAffinity: []schedulingv1alpha1.WeightedLocationSelector{
+					{Weight: 20, Selector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "gold"}}},
+				},
+			},
+			want: []string{"eu-2"},
+		},
+		"spread over used locations": {
+			spec: schedulingv1alpha1.PlacementSpec{
+				SpreadConstraints: []schedulingv1alpha1.PlacementSpreadConstraint{{MaxSkew: 1}},
+			},
+			placements: []*schedulingv1alpha1.Placement{
+				selected("a", nil, "eu-1"),
+				selected("b", nil, "eu-2"),
+			},
+			want: []string{"us-1"},
+		},
+		"spread ignores placements not matching the selector": {
+			spec: schedulingv1alpha1.PlacementSpec{
+				SpreadConstraints: []schedulingv1alpha1.PlacementSpreadConstraint{{
+					MaxSkew:           1,
+					PlacementSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+				}},
+			},
+			placements: []*schedulingv1alpha1.Placement{
+				selected("a", map[string]string{"app": "db"}, "eu-1"),
+				selected("b", map[string]string{"app": "web"}, "eu-2"),
+			},
+			want: []string{"eu-2", "us-1"},
+		},
+		"spread within max skew": {
+			spec: schedulingv1alpha1.PlacementSpec{
+				SpreadConstraints: []schedulingv1alpha1.PlacementSpreadConstraint{{MaxSkew: 2}},
+			},
+			placements: []*schedulingv1alpha1.Placement{
+				selected("a", nil, "eu-1"),
+				selected("b", nil, "eu-1"),
+			},
+			want: []string{"eu-2", "us-1"},
+		},
+		"spread before affinity": {
+			spec: schedulingv1alpha1.PlacementSpec{
+				SpreadConstraints: []schedulingv1alpha1.PlacementSpreadConstraint{{MaxSkew: 1}},
+				Affinity: []schedulingv1alpha1.WeightedLocationSelector{
+					{Weight: 10, Selector: metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu"}}},
+				},
+			},
+			placements: []*schedulingv1alpha1.Placement{
+				selected("a", nil, "eu-1"),
+			},
+			want: []string{"eu-2"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			placement := &schedulingv1alpha1.Placement{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-placement"},
+				Spec:       testCase.spec,
+			}
+			reconciler := &placementReconciler{
+				listLocations: func(clusterName logicalcluster.Name) ([]*schedulingv1alpha1.Location, error) {
+					return locations, nil
+				},
+				listPlacements: func(clusterName logicalcluster.Name) ([]*schedulingv1alpha1.Placement, error) {
+					return append(testCase.placements, placement), nil
+				},
+			}
+
+			for i := 0; i < 20; i++ {
+				got, err := reconciler.chooseLocation(placement, logicalcluster.New("root:org"), sets.NewString("eu-1", "eu-2", "us-1"))
+				require.NoError(t, err)
+				require.Contains(t, testCase.want, got)
+			}
+		})
+	}
+}
+
 func newLocation(name string, labels map[string]string) *schedulingv1alpha1.Location {
 	return &schedulingv1alpha1.Location{
 		ObjectMeta: metav1.ObjectMeta{
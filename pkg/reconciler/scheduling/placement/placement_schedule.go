@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placement
+
+import (
+	"fmt"
+	"time"
+
+	schedulingv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/scheduling/v1alpha1"
+)
+
+// scheduleWindow is a parsed schedule window with its start and end as offsets into the day.
+type scheduleWindow struct {
+	*schedulingv1alpha1.PlacementScheduleWindow
+
+	days     map[time.Weekday]bool // nil means every day
+	start    time.Duration
+	duration time.Duration
+}
+
+// parsedSchedule is a schedule with its windows parsed in the time zone of the schedule.
+type parsedSchedule struct {
+	location *time.Location
+	windows  []scheduleWindow
+}
+
+var weekdays = map[schedulingv1alpha1.Weekday]time.Weekday{
+	"Sunday":    time.Sunday,
+	"Monday":    time.Monday,
+	"Tuesday":   time.Tuesday,
+	"Wednesday": time.Wednesday,
+	"Thursday":  time.Thursday,
+	"Friday":    time.Friday,
+	"Saturday":  time.Saturday,
+}
+
+func parseSchedule(schedule *schedulingv1alpha1.PlacementSchedule) (*parsedSchedule, error) {
+	location := time.UTC
+	if schedule.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(schedule.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", schedule.TimeZone, err)
+		}
+	}
+
+	parsed := &parsedSchedule{location: location}
+	for i := range schedule.Windows {
+		w := &schedule.Windows[i]
+		start, err := parseTimeOfDay(w.Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start of window %q: %w", w.Name, err)
+		}
+		end, err := parseTimeOfDay(w.End)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end of window %q: %w", w.Name, err)
+		}
+		duration := end - start
+		if duration <= 0 {
+			duration += 24 * time.Hour
+		}
+
+		var days map[time.Weekday]bool
+		if len(w.Days) > 0 {
+			days = map[time.Weekday]bool{}
+			for _, d := range w.Days {
+				wd, found := weekdays[d]
+				if !found {
+					return nil, fmt.Errorf("invalid day %q of window %q", d, w.Name)
+				}
+				days[wd] = true
+			}
+		}
+
+		parsed.windows = append(parsed.windows, scheduleWindow{
+			PlacementScheduleWindow: w,
+			days:                    days,
+			start:                   start,
+			duration:                duration,
+		})
+	}
+
+	return parsed, nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// occurrences calls fn with the start and end of each occurrence of the window starting
+// from the day before now until a week after now.
+func (s *parsedSchedule) occurrences(w scheduleWindow, now time.Time, fn func(start, end time.Time)) {
+	now = now.In(s.location)
+	for i := -1; i <= 7; i++ {
+		// use the wall clock to be robust against daylight saving time changes
+		start := time.Date(now.Year(), now.Month(), now.Day()+i, 0, int(w.start/time.Minute), 0, 0, s.location)
+		if w.days != nil && !w.days[start.Weekday()] {
+			continue
+		}
+		fn(start, start.Add(w.duration))
+	}
+}
+
+// activeWindow returns the first window active at the given time, or nil if none is.
+func (s *parsedSchedule) activeWindow(now time.Time) *schedulingv1alpha1.PlacementScheduleWindow {
+	for _, w := range s.windows {
+		active := false
+		s.occurrences(w, now, func(start, end time.Time) {
+			if !now.Before(start) && now.Before(end) {
+				active = true
+			}
+		})
+		if active {
+			return w.PlacementScheduleWindow
+		}
+	}
+	return nil
+}
+
+// nextTransition returns the next time after now a window starts or ends. It returns
+// false if there is none within the next week.
+func (s *parsedSchedule) nextTransition(now time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, w := range s.windows {
+		s.occurrences(w, now, func(start, end time.Time) {
+			for _, t := range []time.Time{start, end} {
+				if t.After(now) && (next.IsZero() || t.Before(next)) {
+					next = t
+				}
+			}
+		})
+	}
+	return next, !next.IsZero()
+}
@@ -33,13 +33,17 @@ import (
 
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 	"github.com/kcp-dev/kcp/pkg/logging"
 )
 
 type schedulingReconciler struct {
 	getShard   func(name string) (*tenancyv1alpha1.ClusterWorkspaceShard, error)
 	listShards func(selector labels.Selector) ([]*tenancyv1alpha1.ClusterWorkspaceShard, error)
+
+	// pickShard chooses the shard to schedule a new workspace to from a non-empty list of valid
+	// shards. It defaults to leastLoadedShard.
+	pickShard func(shards []*tenancyv1alpha1.ClusterWorkspaceShard) *tenancyv1alpha1.ClusterWorkspaceShard
 }
 
 func (r *schedulingReconciler) reconcile(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace) (reconcileStatus, error) {
@@ -137,7 +141,11 @@ func (r *schedulingReconciler) reconcile(ctx context.Context, workspace *tenancy
 			}
 
 			if len(validShards) > 0 {
-				targetShard := validShards[rand.Intn(len(validShards))]
+				pickShard := r.pickShard
+				if pickShard == nil {
+					pickShard = leastLoadedShard
+				}
+				targetShard := pickShard(validShards)
 
 				u, err := url.Parse(targetShard.Spec.ExternalURL)
 				if err != nil {
@@ -173,15 +181,25 @@ func (r *schedulingReconciler) reconcile(ctx context.Context, workspace *tenancy
 			break
 		}
 
-		_, err := r.getShard(target)
+		targetShard, err := r.getShard(target)
 		if apierrors.IsNotFound(err) {
 			logger.Info("cannot move to nonexistent shard", "ClusterWorkspaceShard", target)
+			workspace.Status.Location.Target = ""
+			break
 		} else if err != nil {
 			return reconcileStatusStopAndRequeue, err
 		}
 
-		logger.Info("moving workspace to shard", "ClusterWorkspaceShard", workspace.Status.Location.Target)
-		workspace.Status.Location.Current = workspace.Status.Location.Target
+		u, err := url.Parse(targetShard.Spec.ExternalURL)
+		if err != nil {
+			conditions.MarkFalse(workspace, tenancyv1alpha1.WorkspaceScheduled, tenancyv1alpha1.WorkspaceReasonReasonUnknown, conditionsv1alpha1.ConditionSeverityError, "Invalid connection information on target ClusterWorkspaceShard: %v.", err)
+			return reconcileStatusStopAndRequeue, err // requeue
+		}
+		u.Path = path.Join(u.Path, workspaceClusterName.Join(workspace.Name).Path())
+
+		logger.Info("moving workspace to shard", "ClusterWorkspaceShard", target)
+		workspace.Status.BaseURL = u.String()
+		workspace.Status.Location.Current = target
 		workspace.Status.Location.Target = ""
 	}
 
@@ -212,8 +230,11 @@ func (r *schedulingReconciler) reconcile(ctx context.Context, workspace *tenancy
 			} else if shardName := workspace.Spec.Shard.Name; shardName != "" && shardName != workspace.Status.Location.Current {
 				needsRescheduling = true
 			}
-			if needsRescheduling {
-				conditions.MarkFalse(workspace, tenancyv1alpha1.WorkspaceScheduled, tenancyv1alpha1.WorkspaceReasonUnreschedulable, conditionsv1alpha1.ConditionSeverityError, "Needs rescheduling, but movement is not supported yet")
+			if needsRescheduling && workspace.Spec.Shard.Selector == nil {
+				// the workspacemigration controller moves workspaces with a shard name constraint
+				conditions.MarkFalse(workspace, tenancyv1alpha1.WorkspaceScheduled, tenancyv1alpha1.WorkspaceReasonUnreschedulable, conditionsv1alpha1.ConditionSeverityInfo, "Needs rescheduling, waiting for WorkspaceMigration to shard %q", workspace.Spec.Shard.Name)
+			} else if needsRescheduling {
+				conditions.MarkFalse(workspace, tenancyv1alpha1.WorkspaceScheduled, tenancyv1alpha1.WorkspaceReasonUnreschedulable, conditionsv1alpha1.ConditionSeverityError, "Needs rescheduling, but movement is only supported by shard name")
 			} else {
 				conditions.MarkTrue(workspace, tenancyv1alpha1.WorkspaceScheduled)
 			}
@@ -226,5 +247,40 @@ func (r *schedulingReconciler) reconcile(ctx context.Context, workspace *tenancy
 }
 
 func isValidShard(shard *tenancyv1alpha1.ClusterWorkspaceShard) (valid bool, reason, message string) {
+	if conditions.IsFalse(shard, tenancyv1alpha1.ClusterWorkspaceShardHealthy) {
+		return false, tenancyv1alpha1.WorkspaceShardValidReasonShardUnhealthy, fmt.Sprintf("ClusterWorkspaceShard %q is not healthy: %s", shard.Name, conditions.GetMessage(shard, tenancyv1alpha1.ClusterWorkspaceShardHealthy))
+	}
 	return true, "", ""
 }
+
+// leastLoadedShard returns the shard with the fewest workspaces, then the fewest requests per second,
+// then the smallest etcd database, as reported in their load status. Shards not reporting their load
+// are only picked if no shard does. Ties are broken randomly.
+func leastLoadedShard(shards []*tenancyv1alpha1.ClusterWorkspaceShard) *tenancyv1alpha1.ClusterWorkspaceShard {
+	less := func(a, b *tenancyv1alpha1.ClusterWorkspaceShard) bool {
+		if (a.Status.Load == nil) != (b.Status.Load == nil) {
+			return a.Status.Load != nil
+		}
+		if a.Status.Load == nil {
+			return false
+		}
+		if a.Status.Load.Workspaces != b.Status.Load.Workspaces {
+			return a.Status.Load.Workspaces < b.Status.Load.Workspaces
+		}
+		if a.Status.Load.RequestsPerSecond != b.Status.Load.RequestsPerSecond {
+			return a.Status.Load.RequestsPerSecond < b.Status.Load.RequestsPerSecond
+		}
+		return a.Status.Load.EtcdSizeBytes < b.Status.Load.EtcdSizeBytes
+	}
+
+	var best []*tenancyv1alpha1.ClusterWorkspaceShard
+	for _, shard := range shards {
+		switch {
+		case len(best) == 0 || less(shard, best[0]):
+			best = []*tenancyv1alpha1.ClusterWorkspaceShard{shard}
+		case !less(best[0], shard):
+			best = append(best, shard)
+		}
+	}
+	return best[rand.Intn(len(best))]
+}
@@ -168,6 +168,55 @@ func TestSchedulingReconciler(t *testing.T) {
 			),
 			wantStatus: reconcileStatusContinue,
 		},
+		{
+			name: "spec shard selector, least loaded healthy shard",
+			workspace: phase(tenancyv1alpha1.ClusterWorkspacePhaseScheduling,
+				constrained(tenancyv1alpha1.ShardConstraints{Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"a": "1"}},
+				}, workspace())),
+			shards: []*tenancyv1alpha1.ClusterWorkspaceShard{
+				withLoad(10, withLabels(map[string]string{"a": "1"}, withURLs("https://foo", "https://front-proxy", shard("foo")))),
+				withLoad(5, withLabels(map[string]string{"a": "1"}, withURLs("https://bar", "https://front-proxy", shard("bar")))),
+				unhealthy(withLoad(1, withLabels(map[string]string{"a": "1"}, withURLs("https://baz", "https://front-proxy", shard("baz"))))),
+			},
+			want: withConditions(phase(tenancyv1alpha1.ClusterWorkspacePhaseScheduling,
+				scheduled("bar", "https://front-proxy/clusters/workspace",
+					constrained(tenancyv1alpha1.ShardConstraints{Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"a": "1"}},
+					}, workspace()))),
+				conditionsapi.Condition{
+					Type:   tenancyv1alpha1.WorkspaceScheduled,
+					Status: corev1.ConditionTrue,
+				},
+				conditionsapi.Condition{
+					Type:   tenancyv1alpha1.WorkspaceShardValid,
+					Status: corev1.ConditionTrue,
+				},
+			),
+			wantStatus: reconcileStatusContinue,
+		},
+		{
+			name: "scheduled to unhealthy shard",
+			workspace: phase(tenancyv1alpha1.ClusterWorkspacePhaseReady,
+				scheduled("root", "https://front-proxy/clusters/workspace", workspace())),
+			shards: []*tenancyv1alpha1.ClusterWorkspaceShard{
+				unhealthy(withURLs("https://root", "https://front-proxy", shard("root"))),
+			},
+			want: withConditions(phase(tenancyv1alpha1.ClusterWorkspacePhaseReady,
+				scheduled("root", "https://front-proxy/clusters/workspace", workspace())),
+				conditionsapi.Condition{
+					Type:   tenancyv1alpha1.WorkspaceScheduled,
+					Status: corev1.ConditionTrue,
+				},
+				conditionsapi.Condition{
+					Type:     tenancyv1alpha1.WorkspaceShardValid,
+					Severity: conditionsapi.ConditionSeverityError,
+					Status:   corev1.ConditionFalse,
+					Reason:   tenancyv1alpha1.WorkspaceShardValidReasonShardUnhealthy,
+				},
+			),
+			wantStatus: reconcileStatusContinue,
+		},
 		{
 			name: "invalid spec shard name",
 			workspace: phase(tenancyv1alpha1.ClusterWorkspacePhaseScheduling,
@@ -236,6 +285,72 @@ func TestSchedulingReconciler(t *testing.T) {
 			),
 			wantStatus: reconcileStatusContinue,
 		},
+		{
+			name: "move to target shard",
+			workspace: phase(tenancyv1alpha1.ClusterWorkspacePhaseReady,
+				moving("foo", scheduled("root", "https://front-proxy/clusters/workspace", workspace()))),
+			shards: []*tenancyv1alpha1.ClusterWorkspaceShard{
+				withURLs("https://root", "https://front-proxy", shard("root")),
+				withURLs("https://foo", "https://foo-proxy", shard("foo")),
+			},
+			want: withConditions(phase(tenancyv1alpha1.ClusterWorkspacePhaseReady,
+				scheduled("foo", "https://foo-proxy/clusters/workspace", workspace())),
+				conditionsapi.Condition{
+					Type:   tenancyv1alpha1.WorkspaceScheduled,
+					Status: corev1.ConditionTrue,
+				},
+				conditionsapi.Condition{
+					Type:   tenancyv1alpha1.WorkspaceShardValid,
+					Status: corev1.ConditionTrue,
+				},
+			),
+			wantStatus: reconcileStatusContinue,
+		},
+		{
+			name: "move to nonexistent target shard",
+			workspace: phase(tenancyv1alpha1.ClusterWorkspacePhaseReady,
+				moving("foo", scheduled("root", "https://front-proxy/clusters/workspace", workspace()))),
+			shards: []*tenancyv1alpha1.ClusterWorkspaceShard{
+				withURLs("https://root", "https://front-proxy", shard("root")),
+			},
+			want: withConditions(phase(tenancyv1alpha1.ClusterWorkspacePhaseReady,
+				scheduled("root", "https://front-proxy/clusters/workspace", workspace())),
+				conditionsapi.Condition{
+					Type:   tenancyv1alpha1.WorkspaceScheduled,
+					Status: corev1.ConditionTrue,
+				},
+				conditionsapi.Condition{
+					Type:   tenancyv1alpha1.WorkspaceShardValid,
+					Status: corev1.ConditionTrue,
+				},
+			),
+			wantStatus: reconcileStatusContinue,
+		},
+		{
+			name: "spec shard name differs from current shard",
+			workspace: phase(tenancyv1alpha1.ClusterWorkspacePhaseReady,
+				scheduled("root", "https://front-proxy/clusters/workspace",
+					constrained(tenancyv1alpha1.ShardConstraints{Name: "foo"}, workspace()))),
+			shards: []*tenancyv1alpha1.ClusterWorkspaceShard{
+				withURLs("https://root", "https://front-proxy", shard("root")),
+				withURLs("https://foo", "https://front-proxy", shard("foo")),
+			},
+			want: withConditions(phase(tenancyv1alpha1.ClusterWorkspacePhaseReady,
+				scheduled("root", "https://front-proxy/clusters/workspace",
+					constrained(tenancyv1alpha1.ShardConstraints{Name: "foo"}, workspace()))),
+				conditionsapi.Condition{
+					Type:     tenancyv1alpha1.WorkspaceScheduled,
+					Severity: conditionsapi.ConditionSeverityInfo,
+					Status:   corev1.ConditionFalse,
+					Reason:   tenancyv1alpha1.WorkspaceReasonUnreschedulable,
+				},
+				conditionsapi.Condition{
+					Type:   tenancyv1alpha1.WorkspaceShardValid,
+					Status: corev1.ConditionTrue,
+				},
+			),
+			wantStatus: reconcileStatusContinue,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -300,6 +415,11 @@ func scheduled(shard string, baseURL string, ws *tenancyv1alpha1.ClusterWorkspac
 	return ws
 }
 
+func moving(target string, ws *tenancyv1alpha1.ClusterWorkspace) *tenancyv1alpha1.ClusterWorkspace {
+	ws.Status.Location.Target = target
+	return ws
+}
+
 func constrained(constraints tenancyv1alpha1.ShardConstraints, ws *tenancyv1alpha1.ClusterWorkspace) *tenancyv1alpha1.ClusterWorkspace {
 	ws.Spec.Shard = &constraints
 	return ws
@@ -328,3 +448,54 @@ func withLabels(labels map[string]string, shard *tenancyv1alpha1.ClusterWorkspac
 	shard.Labels = labels
 	return shard
 }
+
+func withLoad(workspaces int64, shard *tenancyv1alpha1.ClusterWorkspaceShard) *tenancyv1alpha1.ClusterWorkspaceShard {
+	shard.Status.Load = &tenancyv1alpha1.ClusterWorkspaceShardLoad{Workspaces: workspaces}
+	return shard
+}
+
+func unhealthy(shard *tenancyv1alpha1.ClusterWorkspaceShard) *tenancyv1alpha1.ClusterWorkspaceShard {
+	shard.Status.Conditions = append(shard.Status.Conditions, conditionsapi.Condition{
+		Type:   tenancyv1alpha1.ClusterWorkspaceShardHealthy,
+		Status: corev1.ConditionFalse,
+		Reason: tenancyv1alpha1.ClusterWorkspaceShardLoadReportStaleReason,
+	})
+	return shard
+}
+
+func TestLeastLoadedShard(t *testing.T) {
+	loaded := func(name string, workspaces, rps int64) *tenancyv1alpha1.ClusterWorkspaceShard {
+		s := withLoad(workspaces, shard(name))
+		s.Status.Load.RequestsPerSecond = rps
+		return s
+	}
+
+	tests := map[string]struct {
+		shards []*tenancyv1alpha1.ClusterWorkspaceShard
+		want   string
+	}{
+		"fewest workspaces": {
+			shards: []*tenancyv1alpha1.ClusterWorkspaceShard{loaded("a", 3, 0), loaded("b", 1, 100), loaded("c", 2, 0)},
+			want:   "b",
+		},
+		"requests per second break ties": {
+			shards: []*tenancyv1alpha1.ClusterWorkspaceShard{loaded("a", 1, 50), loaded("b", 1, 10)},
+			want:   "b",
+		},
+		"reporting shards win": {
+			shards: []*tenancyv1alpha1.ClusterWorkspaceShard{shard("a"), loaded("b", 100, 100)},
+			want:   "b",
+		},
+		"single shard without report": {
+			shards: []*tenancyv1alpha1.ClusterWorkspaceShard{shard("a")},
+			want:   "a",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := leastLoadedShard(tt.shards); got.Name != tt.want {
+				t.Errorf("leastLoadedShard() = %q, want %q", got.Name, tt.want)
+			}
+		})
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -62,7 +63,10 @@ func NewController(
 	kcpClusterClient kcpclientset.ClusterInterface,
 	metadataClusterClient kcpmetadata.ClusterInterface,
 	workspaceInformer tenancyv1alpha1informers.ClusterWorkspaceClusterInformer,
+	tombstoneInformer tenancyv1alpha1informers.ClusterWorkspaceTombstoneClusterInformer,
 	discoverResourcesFn func(clusterName logicalcluster.Name) ([]*metav1.APIResourceList, error),
+	countObjectsFn func(clusterName logicalcluster.Name) (map[schema.GroupVersionResource]int, error),
+	retention time.Duration,
 ) *Controller {
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
 
@@ -72,7 +76,9 @@ func NewController(
 		kcpClusterClient:      kcpClusterClient,
 		metadataClusterClient: metadataClusterClient,
 		workspaceLister:       workspaceInformer.Lister(),
-		deleter:               deletion.NewWorkspacedResourcesDeleter(metadataClusterClient, discoverResourcesFn),
+		tombstoneLister:       tombstoneInformer.Lister(),
+		deleter:               deletion.NewWorkspacedResourcesDeleter(metadataClusterClient, discoverResourcesFn, countObjectsFn),
+		retention:             retention,
 	}
 
 	workspaceInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
@@ -101,7 +107,12 @@ type Controller struct {
 	metadataClusterClient kcpmetadata.ClusterInterface
 
 	workspaceLister tenancyv1alpha1listers.ClusterWorkspaceClusterLister
+	tombstoneLister tenancyv1alpha1listers.ClusterWorkspaceTombstoneClusterLister
 	deleter         deletion.WorkspaceResourcesDeleterInterface
+
+	// retention is the amount of time the content of deleted workspaces is retained. If it is zero,
+	// the content is deleted right away.
+	retention time.Duration
 }
 
 func (c *Controller) enqueue(obj interface{}) {
@@ -203,6 +214,19 @@ func (c *Controller) process(ctx context.Context, key string) error {
 
 	workspaceCopy := workspace.DeepCopy()
 
+	if c.retention > 0 && workspace.Status.Location.Current != "" {
+		// workspaces in the retained content of a deleted workspace are deleted with it. Their
+		// tombstone could neither be created behind the fence, nor outlive the content it lives in.
+		retained, err := c.isRetained(parent)
+		if err != nil {
+			return err
+		}
+		if !retained {
+			return c.retainWorkspace(ctx, workspaceCopy)
+		}
+		logger.V(2).Info("ClusterWorkspace is part of the retained content of a deleted workspace, deleting it right away")
+	}
+
 	logger.V(2).Info("deleting ClusterWorkspace")
 	startTime := time.Now()
 	deleteErr = c.deleter.Delete(ctx, workspaceCopy)
@@ -256,6 +280,75 @@ func (c *Controller) patchCondition(ctx context.Context, old, new *tenancyv1alph
 	return err
 }
 
+// retainWorkspace records the deleted workspace in a ClusterWorkspaceTombstone and removes the finalizer
+// without deleting the content. The content is deleted when the tombstone expires.
+func (c *Controller) retainWorkspace(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace) error {
+	logger := klog.FromContext(ctx)
+
+	finalizers := make([]string, 0, len(workspace.Finalizers))
+	for _, f := range workspace.Finalizers {
+		if f != deletion.WorkspaceFinalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	if len(finalizers) == len(workspace.Finalizers) {
+		return nil
+	}
+
+	clusterName := logicalcluster.From(workspace)
+	tombstone := &tenancyv1alpha1.ClusterWorkspaceTombstone{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: workspace.Name,
+		},
+		Spec: tenancyv1alpha1.ClusterWorkspaceTombstoneSpec{
+			Type:           workspace.Spec.Type,
+			Shard:          workspace.Status.Location.Current,
+			DeletionTime:   *workspace.DeletionTimestamp,
+			ExpirationTime: metav1.NewTime(workspace.DeletionTimestamp.Add(c.retention)),
+		},
+	}
+	tombstones := c.kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().ClusterWorkspaceTombstones()
+	_, err := tombstones.Create(ctx, tombstone, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		// a tombstone of a former workspace of the same name that was restored, and not cleaned up yet
+		existing, err := tombstones.Get(ctx, tombstone.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		existing.Spec = tombstone.Spec
+		_, err = tombstones.Update(ctx, existing, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+	} else if err != nil {
+		return fmt.Errorf("could not create tombstone for workspace %s|%s: %w", clusterName, workspace.Name, err)
+	}
+
+	logger.V(2).Info("retaining content of ClusterWorkspace and removing finalizer", "expiration", tombstone.Spec.ExpirationTime)
+	workspace.Finalizers = finalizers
+	_, err = c.kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().ClusterWorkspaces().Update(ctx, workspace, metav1.UpdateOptions{})
+	return err
+}
+
+// isRetained returns true if the given logical cluster, or any of its ancestors, is the retained
+// content of a deleted workspace.
+func (c *Controller) isRetained(clusterName logicalcluster.Name) (bool, error) {
+	for {
+		parent, hasParent := clusterName.Parent()
+		if !hasParent {
+			return false, nil
+		}
+		_, err := c.tombstoneLister.Cluster(parent).Get(clusterName.Base())
+		if err == nil {
+			return true, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		clusterName = parent
+	}
+}
+
 // finalizeNamespace removes the specified finalizer and finalizes the workspace
 func (c *Controller) finalizeWorkspace(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace) error {
 	logger := klog.FromContext(ctx)
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacedeletion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	kcpfakekubernetesclient "github.com/kcp-dev/client-go/kubernetes/fake"
+	kcptesting "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/testing"
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpfakeclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster/fake"
+	tenancyv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacedeletion/deletion"
+)
+
+type fakeDeleter struct {
+	deleted []logicalcluster.Name
+}
+
+func (d *fakeDeleter) Delete(ctx context.Context, ws *tenancyv1alpha1.ClusterWorkspace) error {
+	d.deleted = append(d.deleted, logicalcluster.From(ws).Join(ws.Name))
+	return nil
+}
+
+func TestProcessRetention(t *testing.T) {
+	deletionTime := metav1.NewTime(time.Date(2022, 11, 20, 0, 0, 0, 0, time.UTC))
+	deletedWorkspace := func(clusterName logicalcluster.Name, name string) *tenancyv1alpha1.ClusterWorkspace {
+		return &tenancyv1alpha1.ClusterWorkspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Annotations:       map[string]string{logicalcluster.AnnotationKey: clusterName.String()},
+				DeletionTimestamp: &deletionTime,
+				Finalizers:        []string{deletion.WorkspaceFinalizer},
+			},
+			Status: tenancyv1alpha1.ClusterWorkspaceStatus{
+				Location: tenancyv1alpha1.ClusterWorkspaceLocation{Current: "alpha"},
+			},
+		}
+	}
+	expiredTombstone := &tenancyv1alpha1.ClusterWorkspaceTombstone{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ws",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+		Spec: tenancyv1alpha1.ClusterWorkspaceTombstoneSpec{
+			Shard:          "alpha",
+			DeletionTime:   deletionTime,
+			ExpirationTime: deletionTime,
+		},
+	}
+
+	tests := map[string]struct {
+		workspace     *tenancyv1alpha1.ClusterWorkspace
+		retention     time.Duration
+		wantDeleted   bool
+		wantTombstone bool
+	}{
+		"without retention": {
+			workspace:   deletedWorkspace(logicalcluster.New("root:org"), "other"),
+			wantDeleted: true,
+		},
+		"retained": {
+			workspace:     deletedWorkspace(logicalcluster.New("root:org"), "other"),
+			retention:     time.Hour,
+			wantTombstone: true,
+		},
+		"nested in a deleted workspace": {
+			workspace:   deletedWorkspace(logicalcluster.New("root:org:ws"), "child"),
+			retention:   time.Hour,
+			wantDeleted: true,
+		},
+		"nested deeper in a deleted workspace": {
+			workspace:   deletedWorkspace(logicalcluster.New("root:org:ws:child"), "grandchild"),
+			retention:   time.Hour,
+			wantDeleted: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			workspaceIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+			require.NoError(t, workspaceIndexer.Add(tt.workspace))
+			tombstoneIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+			require.NoError(t, tombstoneIndexer.Add(expiredTombstone))
+
+			clusterName := logicalcluster.From(tt.workspace)
+			kcpClient := kcpfakeclient.NewSimpleClientset()
+			require.NoError(t, kcpClient.Tracker().Cluster(clusterName).Add(tt.workspace.DeepCopy()))
+			kubeClient := kcpfakekubernetesclient.NewSimpleClientset()
+			kubeClient.AddReactor("delete-collection", "*", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				return true, nil, nil
+			})
+			deleter := &fakeDeleter{}
+
+			c := &Controller{
+				kubeClusterClient: kubeClient,
+				kcpClusterClient:  kcpClient,
+				workspaceLister:   tenancyv1alpha1listers.NewClusterWorkspaceClusterLister(workspaceIndexer),
+				tombstoneLister:   tenancyv1alpha1listers.NewClusterWorkspaceTombstoneClusterLister(tombstoneIndexer),
+				deleter:           deleter,
+				retention:         tt.retention,
+			}
+
+			key, err := kcpcache.MetaClusterNamespaceKeyFunc(tt.workspace)
+			require.NoError(t, err)
+			require.NoError(t, c.process(context.Background(), key))
+
+			if tt.wantDeleted {
+				require.Equal(t, []logicalcluster.Name{clusterName.Join(tt.workspace.Name)}, deleter.deleted)
+			} else {
+				require.Empty(t, deleter.deleted)
+			}
+
+			_, err = kcpClient.Cluster(clusterName).TenancyV1alpha1().ClusterWorkspaceTombstones().Get(context.Background(), tt.workspace.Name, metav1.GetOptions{})
+			if tt.wantTombstone {
+				require.NoError(t, err)
+			} else {
+				require.True(t, apierrors.IsNotFound(err), "expected no tombstone, got %v", err)
+			}
+
+			ws, err := kcpClient.Cluster(clusterName).TenancyV1alpha1().ClusterWorkspaces().Get(context.Background(), tt.workspace.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.NotContains(t, ws.Finalizers, deletion.WorkspaceFinalizer)
+		})
+	}
+}
@@ -51,7 +51,7 @@ import (
 
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 	"github.com/kcp-dev/kcp/pkg/projection"
 )
 
@@ -68,13 +68,17 @@ type WorkspaceResourcesDeleterInterface interface {
 	Delete(ctx context.Context, ws *tenancyv1alpha1.ClusterWorkspace) error
 }
 
-// NewWorkspacedResourcesDeleter returns a new NamespacedResourcesDeleter.
+// NewWorkspacedResourcesDeleter returns a new NamespacedResourcesDeleter. countObjectsFn, if not nil, counts the objects
+// of every resource remaining in a logical cluster, e.g. from the wildcard partial metadata informers, to make sure
+// nothing is left behind when the workspace is finalized.
 func NewWorkspacedResourcesDeleter(
 	metadataClusterClient kcpmetadata.ClusterInterface,
-	discoverResourcesFn func(clusterName logicalcluster.Name) ([]*metav1.APIResourceList, error)) WorkspaceResourcesDeleterInterface {
+	discoverResourcesFn func(clusterName logicalcluster.Name) ([]*metav1.APIResourceList, error),
+	countObjectsFn func(clusterName logicalcluster.Name) (map[schema.GroupVersionResource]int, error)) WorkspaceResourcesDeleterInterface {
 	d := &workspacedResourcesDeleter{
 		metadataClusterClient: metadataClusterClient,
 		discoverResourcesFn:   discoverResourcesFn,
+		countObjectsFn:        countObjectsFn,
 	}
 	return d
 }
@@ -87,6 +91,7 @@ type workspacedResourcesDeleter struct {
 	metadataClusterClient kcpmetadata.ClusterInterface
 
 	discoverResourcesFn func(clusterName logicalcluster.Name) ([]*metav1.APIResourceList, error)
+	countObjectsFn      func(clusterName logicalcluster.Name) (map[schema.GroupVersionResource]int, error)
 }
 
 // Delete deletes all resources in the given workspace.
@@ -384,24 +389,59 @@ func (d *workspacedResourcesDeleter) deleteAllContent(ctx context.Context, ws *t
 		finalizersToNumRemaining: map[string]int{},
 	}
 	deleteContentErrs := []error{}
+	deleteAll := func(groupVersionResources map[schema.GroupVersionResource]sets.String) {
+		for gvr, verbs := range groupVersionResources {
+			gvrDeletionMetadata, err := d.deleteAllContentForGroupVersionResource(ctx, wsClusterName, gvr, verbs, workspaceDeletedAt)
+			if err != nil {
+				// If there is an error, hold on to it but proceed with all the remaining
+				// groupVersionResources.
+				deleteContentErrs = append(deleteContentErrs, err)
+			}
+			if gvrDeletionMetadata.finalizerEstimateSeconds > estimate {
+				estimate = gvrDeletionMetadata.finalizerEstimateSeconds
+			}
+			if gvrDeletionMetadata.numRemaining > 0 {
+				numRemainingTotals.gvrToNumRemaining[gvr] = gvrDeletionMetadata.numRemaining
+				for finalizer, numRemaining := range gvrDeletionMetadata.finalizersToNumRemaining {
+					if numRemaining == 0 {
+						continue
+					}
+					numRemainingTotals.finalizersToNumRemaining[finalizer] += numRemaining
+				}
+			}
+		}
+	}
+
+	// Resources providing APIs are deleted only when all other content is gone. Otherwise, objects still waiting
+	// for their finalizers would lose their API, and with it the controllers removing the finalizers.
+	apiProviders := map[schema.GroupVersionResource]sets.String{}
 	for gvr, verbs := range groupVersionResources {
-		gvrDeletionMetadata, err := d.deleteAllContentForGroupVersionResource(ctx, wsClusterName, gvr, verbs, workspaceDeletedAt)
+		if apiProviderResources[gvr.GroupResource()] {
+			apiProviders[gvr] = verbs
+			delete(groupVersionResources, gvr)
+		}
+	}
+	deleteAll(groupVersionResources)
+	if len(deleteContentErrs) == 0 && len(numRemainingTotals.gvrToNumRemaining) == 0 {
+		deleteAll(apiProviders)
+	} else {
+		logger.V(5).Info("waiting for content to be deleted before deleting APIs", "apis", len(apiProviders))
+		estimate = finalizerEstimateSeconds
+	}
+
+	// Finally, make sure nothing is left that discovery did not show, e.g. namespaced objects outside of namespaces
+	// or of resources not supporting delete.
+	if len(deleteContentErrs) == 0 && len(numRemainingTotals.gvrToNumRemaining) == 0 && d.countObjectsFn != nil {
+		remaining, err := d.countObjectsFn(wsClusterName)
 		if err != nil {
-			// If there is an error, hold on to it but proceed with all the remaining
-			// groupVersionResources.
 			deleteContentErrs = append(deleteContentErrs, err)
 		}
-		if gvrDeletionMetadata.finalizerEstimateSeconds > estimate {
-			estimate = gvrDeletionMetadata.finalizerEstimateSeconds
-		}
-		if gvrDeletionMetadata.numRemaining > 0 {
-			numRemainingTotals.gvrToNumRemaining[gvr] = gvrDeletionMetadata.numRemaining
-			for finalizer, numRemaining := range gvrDeletionMetadata.finalizersToNumRemaining {
-				if numRemaining == 0 {
-					continue
-				}
-				numRemainingTotals.finalizersToNumRemaining[finalizer] += numRemaining
+		for gvr, numRemaining := range remaining {
+			if numRemaining == 0 || projection.Includes(gvr) {
+				continue
 			}
+			numRemainingTotals.gvrToNumRemaining[gvr] = numRemaining
+			estimate = finalizerEstimateSeconds
 		}
 	}
 
@@ -493,6 +533,12 @@ func groupVersionResources(rls []*metav1.APIResourceList) (map[schema.GroupVersi
 	return gvrs, nil
 }
 
+// apiProviderResources are the resources whose objects provide the APIs of other content in a workspace.
+var apiProviderResources = map[schema.GroupResource]bool{
+	{Group: "apis.kcp.dev", Resource: "apibindings"}:                       true,
+	{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}: true,
+}
+
 type isNotVirtualResource struct{}
 
 // Match checks if a resource contains all the given verbs.
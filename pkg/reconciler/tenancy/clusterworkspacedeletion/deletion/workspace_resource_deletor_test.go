@@ -22,17 +22,19 @@ import (
 	"testing"
 
 	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
 
 	kcpfakemetadata "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/metadata/fake"
 	kcptesting "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/testing"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 )
 
 var scheme *runtime.Scheme
@@ -133,7 +135,7 @@ func TestWorkspaceTerminating(t *testing.T) {
 				return resources, tt.gvrError
 			}
 			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, tt.existingObject...)
-			d := NewWorkspacedResourcesDeleter(mockMetadataClient, fn)
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, fn, nil)
 
 			err := d.Delete(context.TODO(), ws)
 			if !matchErrors(err, tt.expectErrorOnDelete) {
@@ -163,6 +165,70 @@ func TestWorkspaceTerminating(t *testing.T) {
 	}
 }
 
+func TestWorkspaceDeletionDeletesAPIsLast(t *testing.T) {
+	now := metav1.Now()
+	newWorkspace := func() *tenancyv1alpha1.ClusterWorkspace {
+		return &tenancyv1alpha1.ClusterWorkspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "test",
+				DeletionTimestamp: &now,
+				Finalizers:        []string{WorkspaceFinalizer},
+				Annotations:       map[string]string{logicalcluster.AnnotationKey: "root"},
+			},
+		}
+	}
+	resources := append(testResources(), &metav1.APIResourceList{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{
+				Name:       "namespaces",
+				Namespaced: false,
+				Kind:       "Namespace",
+				Verbs:      []string{"get", "list", "delete", "deletecollection", "create", "update"},
+			},
+		},
+	})
+	discover := func(clusterName logicalcluster.Name) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	}
+
+	t.Run("CRDs are kept while namespaces remain", func(t *testing.T) {
+		mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+			newPartialObject("v1", "Namespace", "ns1", ""),
+			newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+		)
+		// pretend the namespace is still terminating
+		mockMetadataClient.PrependReactor("delete-collection", "namespaces", func(action kcptesting.Action) (bool, runtime.Object, error) {
+			return true, nil, nil
+		})
+
+		ws := newWorkspace()
+		err := NewWorkspacedResourcesDeleter(mockMetadataClient, discover, nil).Delete(context.TODO(), ws)
+		require.Error(t, err)
+		for _, action := range mockMetadataClient.Actions() {
+			require.NotEqual(t, "customresourcedefinitions", action.GetResource().Resource, "unexpected action %v", action)
+		}
+		require.True(t, conditions.IsFalse(ws, tenancyv1alpha1.WorkspaceContentDeleted))
+	})
+
+	t.Run("objects not found via discovery are waited for", func(t *testing.T) {
+		mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+		countObjects := func(clusterName logicalcluster.Name) (map[schema.GroupVersionResource]int, error) {
+			require.Equal(t, "root:test", clusterName.String())
+			return map[schema.GroupVersionResource]int{
+				{Group: "example.io", Version: "v1", Resource: "widgets"}:              2,
+				{Group: "example.io", Version: "v1", Resource: "gadgets"}:              0,
+				{Group: "tenancy.kcp.dev", Version: "v1beta1", Resource: "workspaces"}: 1,
+			}, nil
+		}
+
+		ws := newWorkspace()
+		err := NewWorkspacedResourcesDeleter(mockMetadataClient, discover, countObjects).Delete(context.TODO(), ws)
+		require.Equal(t, &ResourcesRemainingError{15, "Some resources are remaining: widgets.example.io has 2 resource instances"}, err)
+		require.True(t, conditions.IsFalse(ws, tenancyv1alpha1.WorkspaceContentDeleted))
+	})
+}
+
 type metaAction struct {
 	resource string
 	verb     string
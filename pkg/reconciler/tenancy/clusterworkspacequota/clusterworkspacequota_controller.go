@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacequota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	quota "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	tenancyv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/logging"
+)
+
+const (
+	ControllerName = "kcp-clusterworkspacequota"
+)
+
+// NewController returns a controller that keeps the usage in the status of ClusterWorkspaceQuotas
+// up-to-date. The usage is recomputed whenever a quota changes and every resyncPeriod.
+func NewController(
+	kcpClusterClient kcpclientset.ClusterInterface,
+	clusterWorkspaceQuotaInformer tenancyinformers.ClusterWorkspaceQuotaClusterInformer,
+	countObjects CountObjectsFunc,
+	listObjects ListObjectsFunc,
+	storageUsage StorageUsageFunc,
+	resyncPeriod time.Duration,
+) (*Controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+
+	c := &Controller{
+		queue:                       queue,
+		kcpClusterClient:            kcpClusterClient,
+		clusterWorkspaceQuotaLister: clusterWorkspaceQuotaInformer.Lister(),
+		countObjects:                countObjects,
+		listObjects:                 listObjects,
+		storageUsage:                storageUsage,
+		resyncPeriod:                resyncPeriod,
+	}
+
+	clusterWorkspaceQuotaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueue(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueue(obj) },
+	})
+
+	return c, nil
+}
+
+// Controller computes the usage of the workspaces limited by ClusterWorkspaceQuotas.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	kcpClusterClient kcpclientset.ClusterInterface
+
+	clusterWorkspaceQuotaLister tenancyv1alpha1listers.ClusterWorkspaceQuotaClusterLister
+	countObjects                CountObjectsFunc
+	listObjects                 ListObjectsFunc
+	storageUsage                StorageUsageFunc
+
+	resyncPeriod time.Duration
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := kcpcache.MetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(2).Info("queueing ClusterWorkspaceQuota")
+	c.queue.Add(key)
+}
+
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.Until(func() { c.startWorker(ctx) }, time.Second, ctx.Done())
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(1).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	clusterName, namespace, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		logger.Error(err, "invalid key")
+		return nil
+	}
+	if namespace != "" {
+		logger.Error(errors.New("namespace found in key for cluster-wide ClusterWorkspaceQuota object"), "invalid key")
+		return nil
+	}
+
+	obj, err := c.clusterWorkspaceQuotaLister.Cluster(clusterName).Get(name)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil // object deleted before we handled it
+		}
+		return err
+	}
+	previous := obj
+	obj = obj.DeepCopy()
+
+	logger = logging.WithObject(logger, obj)
+	ctx = klog.NewContext(ctx, logger)
+
+	if err := c.reconcile(ctx, obj); err != nil {
+		return err
+	}
+
+	// usage changes without the quota changing, hence recompute periodically.
+	c.queue.AddAfter(key, c.resyncPeriod)
+
+	// If the object being reconciled changed as a result, update it.
+	if !equality.Semantic.DeepEqual(previous.Status, obj.Status) {
+		oldData, err := json.Marshal(tenancyv1alpha1.ClusterWorkspaceQuota{
+			Status: previous.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal old data for workspace quota %s|%s: %w", clusterName, name, err)
+		}
+
+		newData, err := json.Marshal(tenancyv1alpha1.ClusterWorkspaceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:             previous.UID,
+				ResourceVersion: previous.ResourceVersion,
+			}, // to ensure they appear in the patch as preconditions
+			Status: obj.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to Marshal new data for workspace quota %s|%s: %w", clusterName, name, err)
+		}
+
+		patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+		if err != nil {
+			return fmt.Errorf("failed to create patch for workspace quota %s|%s: %w", clusterName, name, err)
+		}
+		_, uerr := c.kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().ClusterWorkspaceQuotas().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status")
+		return uerr
+	}
+
+	logger.V(6).Info("processed ClusterWorkspaceQuota")
+	return nil
+}
+
+func (c *Controller) reconcile(ctx context.Context, workspaceQuota *tenancyv1alpha1.ClusterWorkspaceQuota) error {
+	// the quota limits the child workspace of the same name
+	limitedClusterName := logicalcluster.From(workspaceQuota).Join(workspaceQuota.Name)
+
+	used, err := Usage(limitedClusterName, workspaceQuota.Spec.Hard, c.countObjects, c.listObjects, c.storageUsage)
+	if err != nil {
+		return err
+	}
+	// the etcd storage is reported even if it is not limited.
+	if storage, ok := c.storageUsage(limitedClusterName); ok {
+		for name, quantity := range storage {
+			used[name] = quantity
+		}
+	}
+
+	workspaceQuota.Status.Hard = quota.Mask(workspaceQuota.Spec.Hard, quota.ResourceNames(used))
+	workspaceQuota.Status.Used = used
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacequota
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func DefaultOptions() *Options {
+	return &Options{
+		StorageScanInterval: 5 * time.Minute,
+	}
+}
+
+func BindOptions(o *Options, fs *pflag.FlagSet) *Options {
+	fs.DurationVar(&o.StorageScanInterval, "workspace-storage-scan-interval", o.StorageScanInterval, "Interval in which the etcd keys of the shard are scanned to account the storage of each workspace. 0 disables the accounting, and with it the tenancy.kcp.dev/storage and tenancy.kcp.dev/objects limits of ClusterWorkspaceQuotas.")
+	return o
+}
+
+type Options struct {
+	StorageScanInterval time.Duration
+}
+
+func (o *Options) Validate() error {
+	if o.StorageScanInterval < 0 {
+		return fmt.Errorf("--workspace-storage-scan-interval must be >=0 (%s)", o.StorageScanInterval)
+	}
+	if o.StorageScanInterval > 0 && o.StorageScanInterval < time.Minute {
+		return fmt.Errorf("--workspace-storage-scan-interval must be at least 1m (%s)", o.StorageScanInterval)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacequota
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/informer"
+)
+
+// CountObjectsFunc returns the number of objects of the given resource in the given logical cluster.
+type CountObjectsFunc func(clusterName logicalcluster.Name, gr schema.GroupResource) (int64, error)
+
+// ObjectCounts indexes the objects of all resources served on the shard by logical cluster, from the
+// events of the dynamic discovery informers. Counting the objects of a resource in a logical cluster
+// hence does not list them.
+//
+// The keys of the objects are indexed instead of counters, such that replayed events of relisting
+// informers do not count objects twice.
+type ObjectCounts struct {
+	ddsif    *informer.DynamicDiscoverySharedInformerFactory
+	informed func() (map[schema.GroupVersionResource]kcpcache.GenericClusterLister, []schema.GroupVersionResource)
+
+	lock sync.RWMutex
+	keys map[schema.GroupVersionResource]map[logicalcluster.Name]sets.String
+}
+
+// NewObjectCounts returns ObjectCounts indexing the objects of the given informer factory. Run must
+// be called to drop the objects of resources that are not informed on anymore.
+func NewObjectCounts(ddsif *informer.DynamicDiscoverySharedInformerFactory) *ObjectCounts {
+	c := &ObjectCounts{
+		ddsif:    ddsif,
+		informed: ddsif.Listers,
+		keys:     map[schema.GroupVersionResource]map[logicalcluster.Name]sets.String{},
+	}
+
+	ddsif.AddEventHandler(informer.GVREventHandlerFuncs{
+		AddFunc: func(gvr schema.GroupVersionResource, obj interface{}) {
+			c.add(gvr, obj)
+		},
+		UpdateFunc: func(gvr schema.GroupVersionResource, _, obj interface{}) {
+			c.add(gvr, obj)
+		},
+		DeleteFunc: func(gvr schema.GroupVersionResource, obj interface{}) {
+			c.delete(gvr, obj)
+		},
+	})
+
+	return c
+}
+
+// Count returns the number of objects of the given resource in the given logical cluster. Resources
+// that are not served have no objects.
+func (c *ObjectCounts) Count(clusterName logicalcluster.Name, gr schema.GroupResource) (int64, error) {
+	listers, notSynced := c.informed()
+	for gvr := range listers {
+		if gvr.GroupResource() != gr {
+			continue
+		}
+
+		c.lock.RLock()
+		defer c.lock.RUnlock()
+		return int64(c.keys[gvr][clusterName].Len()), nil
+	}
+	for _, gvr := range notSynced {
+		if gvr.GroupResource() == gr {
+			return 0, fmt.Errorf("informer for %s not synced yet", gvr)
+		}
+	}
+	return 0, nil
+}
+
+// Run drops the objects of resources whose informers were removed, until ctx is done.
+func (c *ObjectCounts) Run(ctx context.Context) {
+	const subscriberID = "clusterworkspacequota-object-counts"
+	changed := c.ddsif.Subscribe(subscriberID)
+	defer c.ddsif.Unsubscribe(subscriberID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+			listers, notSynced := c.informed()
+			informed := make(map[schema.GroupVersionResource]bool, len(listers)+len(notSynced))
+			for gvr := range listers {
+				informed[gvr] = true
+			}
+			for _, gvr := range notSynced {
+				informed[gvr] = true
+			}
+			if dropped := c.prune(informed); len(dropped) > 0 {
+				klog.FromContext(ctx).V(2).Info("dropped object counts of resources not informed on anymore", "resources", dropped)
+			}
+		}
+	}
+}
+
+// prune drops the objects of all resources not in informed, and returns those resources.
+func (c *ObjectCounts) prune(informed map[schema.GroupVersionResource]bool) []schema.GroupVersionResource {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var dropped []schema.GroupVersionResource
+	for gvr := range c.keys {
+		if !informed[gvr] {
+			delete(c.keys, gvr)
+			dropped = append(dropped, gvr)
+		}
+	}
+	return dropped
+}
+
+func (c *ObjectCounts) add(gvr schema.GroupVersionResource, obj interface{}) {
+	key, err := kcpcache.MetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	clusterName, _, _, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	clusters, found := c.keys[gvr]
+	if !found {
+		clusters = map[logicalcluster.Name]sets.String{}
+		c.keys[gvr] = clusters
+	}
+	if _, found := clusters[clusterName]; !found {
+		clusters[clusterName] = sets.NewString()
+	}
+	clusters[clusterName].Insert(key)
+}
+
+func (c *ObjectCounts) delete(gvr schema.GroupVersionResource, obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	clusterName, _, _, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	keys := c.keys[gvr][clusterName]
+	keys.Delete(key)
+	if keys.Len() == 0 {
+		delete(c.keys[gvr], clusterName)
+	}
+}
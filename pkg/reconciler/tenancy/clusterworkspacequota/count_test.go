@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacequota
+
+import (
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestObjectCounts(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	gadgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "gadgets"}
+	object := func(cluster, namespace, name string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+				Namespace:   namespace,
+				Name:        name,
+			},
+		}
+	}
+
+	synced := map[schema.GroupVersionResource]kcpcache.GenericClusterLister{widgets: nil}
+	var notSynced []schema.GroupVersionResource
+	c := &ObjectCounts{
+		informed: func() (map[schema.GroupVersionResource]kcpcache.GenericClusterLister, []schema.GroupVersionResource) {
+			return synced, notSynced
+		},
+		keys: map[schema.GroupVersionResource]map[logicalcluster.Name]sets.String{},
+	}
+	ws, other := logicalcluster.New("root:org:ws"), logicalcluster.New("root:org:other")
+
+	c.add(widgets, object("root:org:ws", "default", "a"))
+	c.add(widgets, object("root:org:ws", "other", "a"))
+	c.add(widgets, object("root:org:ws", "default", "b"))
+	c.add(widgets, object("root:org:ws", "default", "b")) // replayed on relist
+	c.add(widgets, object("root:org:other", "default", "a"))
+
+	count, err := c.Count(ws, widgets.GroupResource())
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+	count, err = c.Count(other, widgets.GroupResource())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	c.delete(widgets, object("root:org:ws", "default", "b"))
+	c.delete(widgets, cache.DeletedFinalStateUnknown{Key: "root:org:ws|other/a", Obj: object("root:org:ws", "other", "a")})
+	count, err = c.Count(ws, widgets.GroupResource())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	count, err = c.Count(ws, gadgets.GroupResource())
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count, "resources not served have no objects")
+
+	notSynced = []schema.GroupVersionResource{gadgets}
+	_, err = c.Count(ws, gadgets.GroupResource())
+	require.Error(t, err, "objects of resources whose informers did not sync cannot be counted")
+
+	require.Equal(t, []schema.GroupVersionResource{widgets}, c.prune(map[schema.GroupVersionResource]bool{gadgets: true}))
+	count, err = c.Count(ws, widgets.GroupResource())
+	require.NoError(t, err)
+	require.Equal(t, int64(0), count, "objects of resources not informed on anymore must be dropped")
+}
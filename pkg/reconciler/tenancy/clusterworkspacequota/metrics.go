@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacequota
+
+import (
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	// workspaceStorageBytes is the etcd storage of each workspace of the shard, as of the last scan.
+	workspaceStorageBytes = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Name:           "kcp_workspace_storage_bytes",
+			Help:           "Number of bytes the keys and values of the objects of a workspace occupy in etcd.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"workspace"},
+	)
+
+	// workspaceStorageObjects is the number of objects of each workspace of the shard, as of the last scan.
+	workspaceStorageObjects = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Name:           "kcp_workspace_storage_objects",
+			Help:           "Number of objects of a workspace stored in etcd.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"workspace"},
+	)
+)
+
+var registerMetrics sync.Once
+
+// Register metrics.
+func Register() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(workspaceStorageBytes)
+		legacyregistry.MustRegister(workspaceStorageObjects)
+	})
+}
+
+func init() {
+	Register()
+}
+
+// recordStorage updates the storage metrics to usage, and drops those of workspaces only in previous.
+func recordStorage(previous, usage map[logicalcluster.Name]clusterStorage) {
+	for clusterName := range previous {
+		if _, found := usage[clusterName]; !found {
+			workspaceStorageBytes.DeleteLabelValues(clusterName.String())
+			workspaceStorageObjects.DeleteLabelValues(clusterName.String())
+		}
+	}
+	for clusterName, u := range usage {
+		workspaceStorageBytes.WithLabelValues(clusterName.String()).Set(float64(u.bytes))
+		workspaceStorageObjects.WithLabelValues(clusterName.String()).Set(float64(u.objects))
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacequota
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// scanPageSize is the number of keys read from etcd per request.
+const scanPageSize = 500
+
+// storageResources are the resources of the etcd storage of a workspace.
+var storageResources = []corev1.ResourceName{
+	tenancyv1alpha1.ResourceStorage,
+	tenancyv1alpha1.ResourceObjects,
+}
+
+// StorageUsageFunc returns the etcd storage of the given logical cluster, and false if it is not known.
+type StorageUsageFunc func(clusterName logicalcluster.Name) (corev1.ResourceList, bool)
+
+// clusterStorage is the etcd storage of one logical cluster.
+type clusterStorage struct {
+	bytes   int64
+	objects int64
+}
+
+// StorageUsage holds the etcd storage of the logical clusters of a shard, as found by the last
+// scan of the etcd keys of the shard.
+type StorageUsage struct {
+	lock    sync.RWMutex
+	scanned bool
+	usage   map[logicalcluster.Name]clusterStorage
+}
+
+// NewStorageUsage returns a StorageUsage that knows nothing until Run scanned etcd once.
+func NewStorageUsage() *StorageUsage {
+	return &StorageUsage{}
+}
+
+// Get returns the etcd storage of the given logical cluster as tenancy.kcp.dev/storage and
+// tenancy.kcp.dev/objects, and false if etcd has not been scanned yet.
+func (s *StorageUsage) Get(clusterName logicalcluster.Name) (corev1.ResourceList, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if !s.scanned {
+		return nil, false
+	}
+	usage := s.usage[clusterName]
+	return corev1.ResourceList{
+		tenancyv1alpha1.ResourceStorage: *resource.NewQuantity(usage.bytes, resource.BinarySI),
+		tenancyv1alpha1.ResourceObjects: *resource.NewQuantity(usage.objects, resource.DecimalSI),
+	}, true
+}
+
+// Run scans the keys below etcdPrefix every period until ctx is done. A scan reads all objects of
+// the shard, hence period should be generous.
+func (s *StorageUsage) Run(ctx context.Context, kv clientv3.KV, etcdPrefix string, period time.Duration) {
+	logger := klog.FromContext(ctx)
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		start := time.Now()
+		usage, err := scanStorage(ctx, kv, etcdPrefix)
+		if err != nil {
+			logger.Error(err, "failed to scan etcd")
+			return
+		}
+		s.set(usage)
+		logger.V(2).Info("scanned etcd", "clusters", len(usage), "duration", time.Since(start))
+	}, period)
+}
+
+func (s *StorageUsage) set(usage map[logicalcluster.Name]clusterStorage) {
+	s.lock.Lock()
+	previous := s.usage
+	s.usage = usage
+	s.scanned = true
+	s.lock.Unlock()
+
+	recordStorage(previous, usage)
+}
+
+// scanStorage returns the etcd storage of all logical clusters below the given etcd prefix. Keys not
+// belonging to a logical cluster are skipped.
+func scanStorage(ctx context.Context, kv clientv3.KV, etcdPrefix string) (map[logicalcluster.Name]clusterStorage, error) {
+	prefix := strings.TrimSuffix(etcdPrefix, "/") + "/"
+	rangeEnd := clientv3.GetPrefixRangeEnd(prefix)
+
+	usage := map[logicalcluster.Name]clusterStorage{}
+	key := prefix
+	for {
+		resp, err := kv.Get(ctx, key, clientv3.WithRange(rangeEnd), clientv3.WithLimit(scanPageSize), clientv3.WithSerializable())
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Kvs {
+			clusterName, ok := ClusterFromKey(etcdPrefix, string(item.Key))
+			if !ok {
+				continue
+			}
+			u := usage[clusterName]
+			u.bytes += int64(len(item.Key) + len(item.Value))
+			u.objects++
+			usage[clusterName] = u
+		}
+		if !resp.More || len(resp.Kvs) == 0 {
+			break
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+	return usage, nil
+}
+
+// ClusterFromKey returns the logical cluster of the etcd key of an object below the given etcd prefix,
+// or false if the key does not belong to a logical cluster.
+//
+// Depending on the resource, the key is <prefix>/<resource>/<cluster>/..., <prefix>/<group>/<resource>/<cluster>/...
+// or <prefix>/<group>/<resource>/<identity>/<cluster>/..., followed by the namespace, if any, and the name. The
+// logical cluster is the first segment that is "root" or a logical cluster name with a colon, as no group,
+// resource or identity is.
+func ClusterFromKey(etcdPrefix, key string) (logicalcluster.Name, bool) {
+	prefix := strings.TrimSuffix(etcdPrefix, "/") + "/"
+	if !strings.HasPrefix(key, prefix) {
+		return logicalcluster.Name{}, false
+	}
+	segments := strings.Split(strings.TrimPrefix(key, prefix), "/")
+	// the last segment is the name of the object
+	for _, segment := range segments[:len(segments)-1] {
+		if segment == tenancyv1alpha1.RootCluster.String() {
+			return tenancyv1alpha1.RootCluster, true
+		}
+		if !strings.Contains(segment, ":") {
+			continue
+		}
+		if clusterName := logicalcluster.New(segment); clusterName.IsValid() {
+			return clusterName, true
+		}
+		return logicalcluster.Name{}, false
+	}
+	return logicalcluster.Name{}, false
+}
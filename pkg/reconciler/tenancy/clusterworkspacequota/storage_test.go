@@ -0,0 +1,109 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacequota
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	quota "k8s.io/apiserver/pkg/quota/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestClusterFromKey(t *testing.T) {
+	identity := strings.Repeat("a", 64)
+	tests := map[string]struct {
+		key    string
+		want   logicalcluster.Name
+		wantOK bool
+	}{
+		"core resource": {
+			key:    "/registry/configmaps/root:org/default/foo",
+			want:   logicalcluster.New("root:org"),
+			wantOK: true,
+		},
+		"grouped resource": {
+			key:    "/registry/apps/deployments/root:org:team/default/foo",
+			want:   logicalcluster.New("root:org:team"),
+			wantOK: true,
+		},
+		"bound resource": {
+			key:    "/registry/example.io/widgets/" + identity + "/root:org/default/foo",
+			want:   logicalcluster.New("root:org"),
+			wantOK: true,
+		},
+		"custom resource": {
+			key:    "/registry/example.io/widgets/customresources/system:admin/foo",
+			want:   logicalcluster.New("system:admin"),
+			wantOK: true,
+		},
+		"root": {
+			key:    "/registry/tenancy.kcp.dev/clusterworkspaces/root/org",
+			want:   tenancyv1alpha1.RootCluster,
+			wantOK: true,
+		},
+		"object named like a logical cluster": {
+			key: "/registry/masterleases/root",
+		},
+		"invalid logical cluster": {
+			key: "/registry/configmaps/Root:Org/default/foo",
+		},
+		"other prefix": {
+			key: "/other/configmaps/root:org/default/foo",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := ClusterFromKey("/registry/", tt.key)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStorageUsage(t *testing.T) {
+	s := NewStorageUsage()
+
+	_, ok := s.Get(logicalcluster.New("root:org"))
+	require.False(t, ok, "storage must be unknown before the first scan")
+
+	s.set(map[logicalcluster.Name]clusterStorage{
+		logicalcluster.New("root:org"): {bytes: 2048, objects: 3},
+	})
+
+	got, ok := s.Get(logicalcluster.New("root:org"))
+	require.True(t, ok)
+	expected := corev1.ResourceList{
+		tenancyv1alpha1.ResourceStorage: resource.MustParse("2Ki"),
+		tenancyv1alpha1.ResourceObjects: resource.MustParse("3"),
+	}
+	require.True(t, quota.Equals(expected, got), "expected %v, got %v", expected, got)
+
+	got, ok = s.Get(logicalcluster.New("root:empty"))
+	require.True(t, ok)
+	expected = corev1.ResourceList{
+		tenancyv1alpha1.ResourceStorage: resource.MustParse("0"),
+		tenancyv1alpha1.ResourceObjects: resource.MustParse("0"),
+	}
+	require.True(t, quota.Equals(expected, got), "expected %v, got %v", expected, got)
+}
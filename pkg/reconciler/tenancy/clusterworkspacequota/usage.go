@@ -0,0 +1,197 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacequota
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	quota "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/apiserver/pkg/quota/v1/generic"
+	"k8s.io/kubernetes/pkg/quota/v1/evaluator/core"
+	"k8s.io/utils/clock"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/informer"
+)
+
+const countResourcePrefix = "count/"
+
+var podsResource = schema.GroupResource{Resource: "pods"}
+
+// computeResources are the pod compute resources a ClusterWorkspaceQuota aggregates over
+// all pods of a workspace.
+var computeResources = []corev1.ResourceName{
+	corev1.ResourceRequestsCPU,
+	corev1.ResourceRequestsMemory,
+	corev1.ResourceLimitsCPU,
+	corev1.ResourceLimitsMemory,
+}
+
+// ListObjectsFunc lists the objects of the given resource in the given logical cluster.
+type ListObjectsFunc func(clusterName logicalcluster.Name, gr schema.GroupResource) ([]runtime.Object, error)
+
+// NewListObjectsFunc returns a ListObjectsFunc backed by the dynamic discovery informers.
+// Resources that are not served have no objects.
+func NewListObjectsFunc(ddsif *informer.DynamicDiscoverySharedInformerFactory) ListObjectsFunc {
+	return func(clusterName logicalcluster.Name, gr schema.GroupResource) ([]runtime.Object, error) {
+		listers, notSynced := ddsif.Listers()
+		for gvr, lister := range listers {
+			if gvr.GroupResource() == gr {
+				return lister.ByCluster(clusterName).List(labels.Everything())
+			}
+		}
+		for _, gvr := range notSynced {
+			if gvr.GroupResource() == gr {
+				return nil, fmt.Errorf("informer for %s not synced yet", gvr)
+			}
+		}
+		return nil, nil
+	}
+}
+
+// CountedResource returns the resource whose objects are counted by the given quota resource
+// name of the form "count/<resource>.<group>", and false if the name is no object count.
+func CountedResource(name corev1.ResourceName) (schema.GroupResource, bool) {
+	if !strings.HasPrefix(string(name), countResourcePrefix) {
+		return schema.GroupResource{}, false
+	}
+	gr := schema.ParseGroupResource(strings.TrimPrefix(string(name), countResourcePrefix))
+	if gr.Resource == "" {
+		return schema.GroupResource{}, false
+	}
+	return gr, true
+}
+
+// IsSupportedResource returns true if the given resource name can be limited by a ClusterWorkspaceQuota.
+func IsSupportedResource(name corev1.ResourceName) bool {
+	if _, ok := CountedResource(name); ok {
+		return true
+	}
+	return quota.Contains(computeResources, name) || quota.Contains(storageResources, name)
+}
+
+// ObjectUsage returns the usage the given object of the given resource adds to a workspace.
+func ObjectUsage(gr schema.GroupResource, obj runtime.Object) (corev1.ResourceList, error) {
+	storage, err := ObjectStorage(obj)
+	if err != nil {
+		return nil, err
+	}
+	usage := quota.Add(storage, corev1.ResourceList{
+		generic.ObjectCountQuotaResourceNameFor(gr): *resource.NewQuantity(1, resource.DecimalSI),
+	})
+	if gr != podsResource {
+		return usage, nil
+	}
+
+	pod, err := toPod(obj)
+	if err != nil {
+		return nil, err
+	}
+	podUsage, err := core.PodUsageFunc(pod, clock.RealClock{})
+	if err != nil {
+		return nil, err
+	}
+	return quota.Add(usage, quota.Mask(podUsage, computeResources)), nil
+}
+
+// ObjectStorage returns the etcd storage the given object occupies, estimated by the size of its JSON
+// encoding. Objects of built-in resources are stored as protobuf, and usually occupy less.
+func ObjectStorage(obj runtime.Object) (corev1.ResourceList, error) {
+	var data interface{} = obj
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		data = u.Object
+	}
+	bs, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return corev1.ResourceList{
+		tenancyv1alpha1.ResourceStorage: *resource.NewQuantity(int64(len(bs)), resource.BinarySI),
+		tenancyv1alpha1.ResourceObjects: *resource.NewQuantity(1, resource.DecimalSI),
+	}, nil
+}
+
+// Usage computes the current usage of the resources limited by hard in the given logical cluster.
+// Objects are counted with countObjects, and pods are only listed to sum up their compute resources.
+// Unsupported resource names, and the etcd storage if it is not known yet, are ignored.
+func Usage(clusterName logicalcluster.Name, hard corev1.ResourceList, countObjects CountObjectsFunc, listObjects ListObjectsFunc, storageUsage StorageUsageFunc) (corev1.ResourceList, error) {
+	used := corev1.ResourceList{}
+	if storage := quota.Intersection(quota.ResourceNames(hard), storageResources); len(storage) > 0 {
+		if u, ok := storageUsage(clusterName); ok {
+			used = quota.Mask(u, storage)
+		}
+	}
+
+	var compute []corev1.ResourceName
+	for name := range hard {
+		if gr, ok := CountedResource(name); ok {
+			count, err := countObjects(clusterName, gr)
+			if err != nil {
+				return nil, err
+			}
+			used[name] = *resource.NewQuantity(count, resource.DecimalSI)
+		} else if quota.Contains(computeResources, name) {
+			compute = append(compute, name)
+			used[name] = resource.MustParse("0")
+		}
+	}
+	if len(compute) == 0 {
+		return used, nil
+	}
+
+	pods, err := listObjects(clusterName, podsResource)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range pods {
+		pod, err := toPod(obj)
+		if err != nil {
+			return nil, err
+		}
+		podUsage, err := core.PodUsageFunc(pod, clock.RealClock{})
+		if err != nil {
+			return nil, err
+		}
+		used = quota.Add(used, quota.Mask(podUsage, compute))
+	}
+	return used, nil
+}
+
+func toPod(obj runtime.Object) (*corev1.Pod, error) {
+	switch t := obj.(type) {
+	case *corev1.Pod:
+		return t, nil
+	case *unstructured.Unstructured:
+		pod := &corev1.Pod{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(t.Object, pod); err != nil {
+			return nil, err
+		}
+		return pod, nil
+	default:
+		return nil, fmt.Errorf("unexpected pod object of type %T", obj)
+	}
+}
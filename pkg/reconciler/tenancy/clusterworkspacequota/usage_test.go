@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacequota
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	quota "k8s.io/apiserver/pkg/quota/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestCountedResource(t *testing.T) {
+	tests := map[corev1.ResourceName]struct {
+		want   schema.GroupResource
+		wantOK bool
+	}{
+		"count/pods":                {want: schema.GroupResource{Resource: "pods"}, wantOK: true},
+		"count/deployments.apps":    {want: schema.GroupResource{Group: "apps", Resource: "deployments"}, wantOK: true},
+		"count/widgets.example.com": {want: schema.GroupResource{Group: "example.com", Resource: "widgets"}, wantOK: true},
+		"count/":                    {},
+		"requests.cpu":              {},
+		"pods":                      {},
+	}
+	for name, tt := range tests {
+		t.Run(string(name), func(t *testing.T) {
+			got, ok := CountedResource(name)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUsage(t *testing.T) {
+	pod := func(phase corev1.PodPhase, cpu, memory string) runtime.Object {
+		return &corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+					},
+				}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	objects := map[schema.GroupResource][]runtime.Object{
+		{Resource: "pods"}: {
+			pod(corev1.PodRunning, "100m", "1Gi"),
+			pod(corev1.PodPending, "200m", "2Gi"),
+			pod(corev1.PodSucceeded, "400m", "4Gi"),
+		},
+		{Group: "apps", Resource: "deployments"}: {nil, nil},
+	}
+	listObjects := func(clusterName logicalcluster.Name, gr schema.GroupResource) ([]runtime.Object, error) {
+		require.Equal(t, logicalcluster.New("root:org:ws"), clusterName)
+		return objects[gr], nil
+	}
+	countObjects := func(clusterName logicalcluster.Name, gr schema.GroupResource) (int64, error) {
+		require.Equal(t, logicalcluster.New("root:org:ws"), clusterName)
+		return int64(len(objects[gr])), nil
+	}
+	storageUsage := func(clusterName logicalcluster.Name) (corev1.ResourceList, bool) {
+		require.Equal(t, logicalcluster.New("root:org:ws"), clusterName)
+		return corev1.ResourceList{
+			tenancyv1alpha1.ResourceStorage: resource.MustParse("4Ki"),
+			tenancyv1alpha1.ResourceObjects: resource.MustParse("5"),
+		}, true
+	}
+
+	used, err := Usage(logicalcluster.New("root:org:ws"), corev1.ResourceList{
+		"count/pods":                       resource.MustParse("10"),
+		"count/deployments.apps":           resource.MustParse("10"),
+		"count/configmaps":                 resource.MustParse("10"),
+		corev1.ResourceRequestsCPU:         resource.MustParse("1"),
+		corev1.ResourceLimitsMemory:        resource.MustParse("10Gi"),
+		corev1.ResourceName("unsupported"): resource.MustParse("1"),
+		tenancyv1alpha1.ResourceStorage:    resource.MustParse("1Mi"),
+	}, countObjects, listObjects, storageUsage)
+	require.NoError(t, err)
+
+	expected := corev1.ResourceList{
+		"count/pods":                    resource.MustParse("3"),
+		"count/deployments.apps":        resource.MustParse("2"),
+		"count/configmaps":              resource.MustParse("0"),
+		corev1.ResourceRequestsCPU:      resource.MustParse("300m"),
+		corev1.ResourceLimitsMemory:     resource.MustParse("3Gi"),
+		tenancyv1alpha1.ResourceStorage: resource.MustParse("4Ki"),
+	}
+	require.True(t, quota.Equals(expected, used), "expected %v, got %v", expected, used)
+
+	t.Run("unknown storage is ignored", func(t *testing.T) {
+		used, err := Usage(logicalcluster.New("root:org:ws"), corev1.ResourceList{
+			"count/pods":                    resource.MustParse("10"),
+			tenancyv1alpha1.ResourceStorage: resource.MustParse("1Mi"),
+		}, countObjects, listObjects, func(logicalcluster.Name) (corev1.ResourceList, bool) { return nil, false })
+		require.NoError(t, err)
+
+		expected := corev1.ResourceList{"count/pods": resource.MustParse("3")}
+		require.True(t, quota.Equals(expected, used), "expected %v, got %v", expected, used)
+	})
+}
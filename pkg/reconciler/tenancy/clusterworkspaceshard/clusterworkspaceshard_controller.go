@@ -37,14 +37,20 @@ import (
 	"k8s.io/klog/v2"
 
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
 	tenancyv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 	"github.com/kcp-dev/kcp/pkg/logging"
 )
 
 const (
 	ControllerName = "kcp-clusterworkspaceshard"
+
+	// LoadReportStaleThreshold is the time after which a shard that has not reported its load
+	// is considered unhealthy.
+	LoadReportStaleThreshold = 2 * time.Minute
 )
 
 func NewController(
@@ -164,6 +170,10 @@ func (c *Controller) process(ctx context.Context, key string) error {
 	if err := c.reconcile(ctx, obj); err != nil {
 		return err
 	}
+	if obj.Status.Load != nil {
+		// check again when the load report goes stale
+		c.queue.AddAfter(key, LoadReportStaleThreshold)
+	}
 
 	// If the object being reconciled changed as a result, update it.
 	if !equality.Semantic.DeepEqual(previous.Status, obj.Status) {
@@ -198,5 +208,23 @@ func (c *Controller) process(ctx context.Context, key string) error {
 }
 
 func (c *Controller) reconcile(ctx context.Context, workspaceShard *tenancyv1alpha1.ClusterWorkspaceShard) error {
+	// shards not reporting their load are not judged
+	if workspaceShard.Status.Load == nil {
+		return nil
+	}
+
+	if age := time.Since(workspaceShard.Status.Load.LastReportTime.Time); age > LoadReportStaleThreshold {
+		conditions.MarkFalse(
+			workspaceShard,
+			tenancyv1alpha1.ClusterWorkspaceShardHealthy,
+			tenancyv1alpha1.ClusterWorkspaceShardLoadReportStaleReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"The shard has not reported its load for %s.",
+			age.Round(time.Second),
+		)
+	} else {
+		conditions.MarkTrue(workspaceShard, tenancyv1alpha1.ClusterWorkspaceShardHealthy)
+	}
+
 	return nil
 }
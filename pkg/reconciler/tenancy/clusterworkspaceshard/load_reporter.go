@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspaceshard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/logging"
+)
+
+const (
+	LoadReporterName = "kcp-clusterworkspaceshard-load-reporter"
+
+	// loadReportInterval is how often a shard reports its load. It must be well below LoadReportStaleThreshold.
+	loadReportInterval = 30 * time.Second
+
+	requestsMetricName = "apiserver_request_total"
+	etcdSizeMetricName = "etcd_db_total_size_in_bytes"
+)
+
+// NewLoadReporter returns a reporter that periodically writes the load of the given shard
+// into the status of its ClusterWorkspaceShard in the root workspace. The load is derived
+// from the ClusterWorkspaces scheduled to the shard and from the request and etcd metrics
+// of the shard's server.
+func NewLoadReporter(
+	shardName string,
+	rootKcpClient kcpclientset.ClusterInterface,
+	clusterWorkspaceInformer tenancyinformers.ClusterWorkspaceClusterInformer,
+	gatherer metrics.Gatherer,
+) *LoadReporter {
+	return &LoadReporter{
+		shardName: shardName,
+		listClusterWorkspaces: func() ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+			return clusterWorkspaceInformer.Lister().List(labels.Everything())
+		},
+		gatherer: gatherer,
+		patchShardStatus: func(ctx context.Context, name string, patch []byte) error {
+			_, err := rootKcpClient.Cluster(tenancyv1alpha1.RootCluster).TenancyV1alpha1().ClusterWorkspaceShards().Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+			return err
+		},
+		now: time.Now,
+	}
+}
+
+// LoadReporter reports the load of a shard into its ClusterWorkspaceShard.
+type LoadReporter struct {
+	shardName string
+
+	listClusterWorkspaces func() ([]*tenancyv1alpha1.ClusterWorkspace, error)
+	gatherer              metrics.Gatherer
+	patchShardStatus      func(ctx context.Context, name string, patch []byte) error
+	now                   func() time.Time
+
+	lastReportTime time.Time
+	lastRequests   float64
+}
+
+// Start reports the load until the context is done.
+func (r *LoadReporter) Start(ctx context.Context) {
+	defer runtime.HandleCrash()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), LoadReporterName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting reporter")
+	defer logger.Info("Shutting down reporter")
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := r.report(ctx); err != nil {
+			runtime.HandleError(fmt.Errorf("%q failed to report the load of shard %q: %w", LoadReporterName, r.shardName, err))
+		}
+	}, loadReportInterval)
+}
+
+func (r *LoadReporter) report(ctx context.Context) error {
+	load, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"load": load,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.FromContext(ctx).V(4).Info("reporting shard load", "shard", r.shardName, "workspaces", load.Workspaces, "requestsPerSecond", load.RequestsPerSecond, "etcdSizeBytes", load.EtcdSizeBytes)
+	return r.patchShardStatus(ctx, r.shardName, patch)
+}
+
+func (r *LoadReporter) load() (*tenancyv1alpha1.ClusterWorkspaceShardLoad, error) {
+	now := r.now()
+
+	workspaces, err := r.listClusterWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+	var scheduled int64
+	for _, ws := range workspaces {
+		if ws.Status.Location.Current == r.shardName {
+			scheduled++
+		}
+	}
+
+	requests, etcdSize, err := gatherLoadMetrics(r.gatherer)
+	if err != nil {
+		return nil, err
+	}
+
+	// the request counter is monotonic, but starts from zero on restart
+	var rps int64
+	if !r.lastReportTime.IsZero() && requests >= r.lastRequests {
+		if elapsed := now.Sub(r.lastReportTime).Seconds(); elapsed > 0 {
+			rps = int64((requests - r.lastRequests) / elapsed)
+		}
+	}
+	r.lastReportTime = now
+	r.lastRequests = requests
+
+	return &tenancyv1alpha1.ClusterWorkspaceShardLoad{
+		Workspaces:        scheduled,
+		RequestsPerSecond: rps,
+		EtcdSizeBytes:     etcdSize,
+		LastReportTime:    metav1.NewTime(now),
+	}, nil
+}
+
+// gatherLoadMetrics returns the total number of requests served, and the largest etcd database
+// size of all etcd endpoints, or 0 if etcd does not report it.
+func gatherLoadMetrics(gatherer metrics.Gatherer) (requests float64, etcdSize int64, err error) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, family := range families {
+		switch family.GetName() {
+		case requestsMetricName:
+			for _, m := range family.GetMetric() {
+				requests += m.GetCounter().GetValue()
+			}
+		case etcdSizeMetricName:
+			for _, m := range family.GetMetric() {
+				if size := int64(m.GetGauge().GetValue()); size > etcdSize {
+					etcdSize = size
+				}
+			}
+		}
+	}
+	return requests, etcdSize, nil
+}
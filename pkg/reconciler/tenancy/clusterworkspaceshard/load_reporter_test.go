@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspaceshard
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/component-base/metrics"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestLoadReporter(t *testing.T) {
+	registry := metrics.NewKubeRegistry()
+	requests := metrics.NewCounterVec(&metrics.CounterOpts{Name: requestsMetricName}, []string{"verb"})
+	etcdSize := metrics.NewGaugeVec(&metrics.GaugeOpts{Name: etcdSizeMetricName}, []string{"endpoint"})
+	registry.MustRegister(requests, etcdSize)
+
+	scheduled := func(shard string) *tenancyv1alpha1.ClusterWorkspace {
+		ws := &tenancyv1alpha1.ClusterWorkspace{}
+		ws.Status.Location.Current = shard
+		return ws
+	}
+
+	now := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	var patches []map[string]map[string]tenancyv1alpha1.ClusterWorkspaceShardLoad
+	r := &LoadReporter{
+		shardName: "alpha",
+		listClusterWorkspaces: func() ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+			return []*tenancyv1alpha1.ClusterWorkspace{scheduled("alpha"), scheduled("beta"), scheduled("alpha")}, nil
+		},
+		gatherer: registry,
+		patchShardStatus: func(ctx context.Context, name string, patch []byte) error {
+			require.Equal(t, "alpha", name)
+			var p map[string]map[string]tenancyv1alpha1.ClusterWorkspaceShardLoad
+			require.NoError(t, json.Unmarshal(patch, &p))
+			patches = append(patches, p)
+			return nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	requests.WithLabelValues("get").Add(100)
+	etcdSize.WithLabelValues("a").Set(1000)
+	etcdSize.WithLabelValues("b").Set(3000)
+	require.NoError(t, r.report(context.Background()))
+
+	now = now.Add(10 * time.Second)
+	requests.WithLabelValues("list").Add(50)
+	require.NoError(t, r.report(context.Background()))
+
+	require.Len(t, patches, 2)
+	first, second := patches[0]["status"]["load"], patches[1]["status"]["load"]
+	require.Equal(t, int64(2), first.Workspaces)
+	require.Equal(t, int64(0), first.RequestsPerSecond, "no rate without a previous report")
+	require.Equal(t, int64(3000), first.EtcdSizeBytes)
+	require.Equal(t, int64(5), second.RequestsPerSecond)
+	require.True(t, second.LastReportTime.Time.Equal(now))
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacetemplate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	admission "github.com/kcp-dev/kcp/pkg/admission/clusterworkspacetypeexists"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	tenancyv1alpha1client "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/typed/tenancy/v1alpha1"
+	tenancyv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+)
+
+const (
+	ControllerName = "kcp-clusterworkspacetemplate-initializer"
+)
+
+// WorkspaceClientsFunc returns a dynamic client and a RESTMapper for the given initializing workspace.
+type WorkspaceClientsFunc func(clusterName logicalcluster.Name) (dynamic.Interface, meta.RESTMapper, error)
+
+// NewController returns a new controller which creates the objects of the ClusterWorkspaceTemplates
+// of the type of new ClusterWorkspaces.
+func NewController(
+	kcpClusterClient kcpclientset.ClusterInterface,
+	workspaceClients WorkspaceClientsFunc,
+	clusterWorkspaceInformer tenancyv1alpha1informers.ClusterWorkspaceClusterInformer,
+	clusterWorkspaceTypeInformer tenancyv1alpha1informers.ClusterWorkspaceTypeClusterInformer,
+	clusterWorkspaceTemplateInformer tenancyv1alpha1informers.ClusterWorkspaceTemplateClusterInformer,
+) (*controller, error) {
+	c := &controller{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+
+		getClusterWorkspace: func(clusterName logicalcluster.Name) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			parent, workspace := clusterName.Split()
+			return clusterWorkspaceInformer.Lister().Cluster(parent).Get(workspace)
+		},
+		getClusterWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+			return clusterWorkspaceTypeInformer.Lister().Cluster(clusterName).Get(name)
+		},
+		listClusterWorkspaces: func() ([]*tenancyv1alpha1.ClusterWorkspace, error) {
+			return clusterWorkspaceInformer.Lister().List(labels.Everything())
+		},
+		listClusterWorkspaceTemplates: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.ClusterWorkspaceTemplate, error) {
+			return clusterWorkspaceTemplateInformer.Lister().Cluster(clusterName).List(labels.Everything())
+		},
+
+		createObject: func(ctx context.Context, clusterName logicalcluster.Name, obj *unstructured.Unstructured) error {
+			dynamicClient, mapper, err := workspaceClients(clusterName)
+			if err != nil {
+				return err
+			}
+			gvk := obj.GroupVersionKind()
+			mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			if err != nil {
+				return err
+			}
+			if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+				_, err = dynamicClient.Resource(mapping.Resource).Create(ctx, obj, metav1.CreateOptions{})
+				return err
+			}
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace(metav1.NamespaceDefault)
+			}
+			_, err = dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+			return err
+		},
+
+		commit: committer.NewCommitter[*tenancyv1alpha1.ClusterWorkspace, tenancyv1alpha1client.ClusterWorkspaceInterface, *tenancyv1alpha1.ClusterWorkspaceSpec, *tenancyv1alpha1.ClusterWorkspaceStatus](kcpClusterClient.TenancyV1alpha1().ClusterWorkspaces()),
+	}
+
+	c.transitiveTypeResolver = admission.NewTransitiveTypeResolver(c.getClusterWorkspaceType)
+
+	logger := logging.WithReconciler(klog.Background(), ControllerName)
+
+	clusterWorkspaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueClusterWorkspace(obj, logger)
+		},
+		// parameter annotations might be added while the workspace is initializing
+		UpdateFunc: func(_, obj interface{}) {
+			c.enqueueClusterWorkspace(obj, logger)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueClusterWorkspace(obj, logger)
+		},
+	})
+
+	clusterWorkspaceTemplateInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueAllClusterWorkspaces(logger)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			c.enqueueAllClusterWorkspaces(logger)
+		},
+	})
+
+	return c, nil
+}
+
+type clusterWorkspaceResource = committer.Resource[*tenancyv1alpha1.ClusterWorkspaceSpec, *tenancyv1alpha1.ClusterWorkspaceStatus]
+
+// controller creates the objects of the ClusterWorkspaceTemplates of the type of new ClusterWorkspaces,
+// and removes the system:templates initializer when they all exist.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	getClusterWorkspace           func(clusterName logicalcluster.Name) (*tenancyv1alpha1.ClusterWorkspace, error)
+	getClusterWorkspaceType       func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error)
+	listClusterWorkspaces         func() ([]*tenancyv1alpha1.ClusterWorkspace, error)
+	listClusterWorkspaceTemplates func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.ClusterWorkspaceTemplate, error)
+
+	createObject func(ctx context.Context, clusterName logicalcluster.Name, obj *unstructured.Unstructured) error
+
+	transitiveTypeResolver transitiveTypeResolver
+
+	// commit creates a patch and submits it, if needed.
+	commit func(ctx context.Context, new, old *clusterWorkspaceResource) error
+}
+
+type transitiveTypeResolver interface {
+	Resolve(t *tenancyv1alpha1.ClusterWorkspaceType) ([]*tenancyv1alpha1.ClusterWorkspaceType, error)
+}
+
+func (c *controller) enqueueClusterWorkspace(obj interface{}, logger logr.Logger) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logging.WithQueueKey(logger, key).V(2).Info("queueing ClusterWorkspace")
+	c.queue.Add(key)
+}
+
+// enqueueAllClusterWorkspaces enqueues all clusterworkspaces (which are only those that are initializing, because of
+// how the informer is supposed to be configured) whenever a template changes. If a template had a mistake, there
+// is a chance the requeuing here would pick up a fix.
+func (c *controller) enqueueAllClusterWorkspaces(logger logr.Logger) {
+	list, err := c.listClusterWorkspaces()
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("error listing clusterworkspaces: %w", err))
+		return
+	}
+
+	for _, ws := range list {
+		logger := logging.WithObject(logger, ws)
+		c.enqueueClusterWorkspace(ws, logger)
+	}
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *controller) ShutDown() {
+	c.queue.ShutDown()
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(1).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%s: failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+
+	parent, _, workspace, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		logger.Error(err, "unable to decode key")
+		return nil
+	}
+
+	clusterName := parent.Join(workspace)
+
+	clusterWorkspace, err := c.getClusterWorkspace(clusterName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "failed to get ClusterWorkspace from lister", "parentCluster", parent, "clusterWorkspace", workspace)
+		}
+
+		return nil // nothing we can do here
+	}
+
+	old := clusterWorkspace
+	clusterWorkspace = clusterWorkspace.DeepCopy()
+
+	logger = logging.WithObject(logger, clusterWorkspace)
+	ctx = klog.NewContext(ctx, logger)
+
+	var errs []error
+	err = c.reconcile(ctx, clusterWorkspace)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	// If the object being reconciled changed as a result, update it.
+	oldResource := &clusterWorkspaceResource{ObjectMeta: old.ObjectMeta, Spec: &old.Spec, Status: &old.Status}
+	newResource := &clusterWorkspaceResource{ObjectMeta: clusterWorkspace.ObjectMeta, Spec: &clusterWorkspace.Spec, Status: &clusterWorkspace.Status}
+	if err := c.commit(ctx, oldResource, newResource); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacetemplate
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/apis/tenancy/initialization"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/conditions"
+)
+
+const (
+	// WorkspaceNameParameter is the name of the workspace.
+	WorkspaceNameParameter = "WORKSPACE_NAME"
+	// WorkspacePathParameter is the logical cluster of the workspace, e.g. root:org:ws.
+	WorkspacePathParameter = "WORKSPACE_PATH"
+	// ParentWorkspacePathParameter is the logical cluster of the parent of the workspace, e.g. root:org.
+	ParentWorkspacePathParameter = "PARENT_WORKSPACE_PATH"
+)
+
+// parameterReference matches a reference of a parameter in a string value, e.g. ${WORKSPACE_NAME}.
+var parameterReference = regexp.MustCompile(`\$\{([A-Z]([A-Z0-9_]*[A-Z0-9])?)\}`)
+
+func (c *controller) reconcile(ctx context.Context, clusterWorkspace *tenancyv1alpha1.ClusterWorkspace) error {
+	logger := klog.FromContext(ctx).WithValues(
+		"clusterWorkspaceType.path", clusterWorkspace.Spec.Type.Path,
+		"clusterWorkspaceType.name", clusterWorkspace.Spec.Type.Name,
+	)
+
+	if !initialization.InitializerPresent(tenancyv1alpha1.ClusterWorkspaceTemplatesInitializer, clusterWorkspace.Status.Initializers) {
+		return nil
+	}
+
+	clusterName := logicalcluster.From(clusterWorkspace).Join(clusterWorkspace.Name)
+	logger.V(2).Info("instantiating templates for workspace")
+
+	leafCWT, err := c.getClusterWorkspaceType(logicalcluster.New(clusterWorkspace.Spec.Type.Path), string(clusterWorkspace.Spec.Type.Name))
+	if err != nil {
+		logger.Error(err, "error getting ClusterWorkspaceType")
+
+		conditions.MarkFalse(
+			clusterWorkspace,
+			tenancyv1alpha1.WorkspaceTemplatesInstantiated,
+			tenancyv1alpha1.WorkspaceInitializedClusterWorkspaceTypeInvalid,
+			conditionsv1alpha1.ConditionSeverityError,
+			"error getting ClusterWorkspaceType %s|%s: %v",
+			clusterWorkspace.Spec.Type.Path, clusterWorkspace.Spec.Type.Name,
+			err,
+		)
+
+		return nil
+	}
+
+	cwts, err := c.transitiveTypeResolver.Resolve(leafCWT)
+	if err != nil {
+		logger.Error(err, "error resolving transitive types")
+
+		conditions.MarkFalse(
+			clusterWorkspace,
+			tenancyv1alpha1.WorkspaceTemplatesInstantiated,
+			tenancyv1alpha1.WorkspaceInitializedClusterWorkspaceTypeInvalid,
+			conditionsv1alpha1.ConditionSeverityError,
+			"error resolving transitive set of cluster workspace types: %v",
+			err,
+		)
+
+		return nil
+	}
+
+	templates, err := c.templatesFor(cwts)
+	if err != nil {
+		return err
+	}
+
+	builtins := map[string]string{
+		WorkspaceNameParameter:       clusterWorkspace.Name,
+		WorkspacePathParameter:       clusterName.String(),
+		ParentWorkspacePathParameter: logicalcluster.From(clusterWorkspace).String(),
+	}
+
+	var missing []string
+	var objects []*unstructured.Unstructured
+	var errors []error
+	for _, template := range templates {
+		values, missingParameters := parameterValues(template, clusterWorkspace.Annotations, builtins)
+		for _, name := range missingParameters {
+			missing = append(missing, fmt.Sprintf("%s|%s %s", logicalcluster.From(template), template.Name, name))
+		}
+
+		for i, raw := range template.Spec.Objects {
+			obj := &unstructured.Unstructured{}
+			if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+				errors = append(errors, fmt.Errorf("invalid object %d of ClusterWorkspaceTemplate %s|%s: %w", i, logicalcluster.From(template), template.Name, err))
+				continue
+			}
+			obj.Object = substitute(obj.Object, values).(map[string]interface{})
+			objects = append(objects, obj)
+		}
+	}
+
+	if len(missing) > 0 {
+		conditions.MarkFalse(
+			clusterWorkspace,
+			tenancyv1alpha1.WorkspaceTemplatesInstantiated,
+			tenancyv1alpha1.WorkspaceInitializedTemplateParameterMissing,
+			conditionsv1alpha1.ConditionSeverityError,
+			"required template parameters have no value: %s",
+			strings.Join(missing, ", "),
+		)
+
+		return nil
+	}
+
+	for _, obj := range objects {
+		logger := logger.WithValues("object.kind", obj.GetKind(), "object.namespace", obj.GetNamespace(), "object.name", obj.GetName())
+
+		logger.V(2).Info("trying to create object")
+		if err := c.createObject(ctx, clusterName, obj); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				logger.V(4).Info("object already exists - skipping creation")
+				continue
+			}
+
+			errors = append(errors, fmt.Errorf("failed to create %s %s: %w", obj.GetKind(), obj.GetName(), err))
+			continue
+		}
+
+		logger.V(2).Info("created object")
+	}
+
+	if len(errors) > 0 {
+		logger.Error(utilerrors.NewAggregate(errors), "error instantiating templates")
+
+		conditions.MarkFalse(
+			clusterWorkspace,
+			tenancyv1alpha1.WorkspaceTemplatesInstantiated,
+			tenancyv1alpha1.WorkspaceInitializedTemplateErrors,
+			conditionsv1alpha1.ConditionSeverityError,
+			"encountered errors: %v",
+			utilerrors.NewAggregate(errors),
+		)
+
+		// Retry, as the resources of the objects might not be served yet, e.g. until the
+		// default APIBindings of the type are bound.
+		return utilerrors.NewAggregate(errors)
+	}
+
+	conditions.MarkTrue(clusterWorkspace, tenancyv1alpha1.WorkspaceTemplatesInstantiated)
+	clusterWorkspace.Status.Initializers = initialization.EnsureInitializerAbsent(tenancyv1alpha1.ClusterWorkspaceTemplatesInitializer, clusterWorkspace.Status.Initializers)
+
+	return nil
+}
+
+// templatesFor returns the templates of the given types, in the order of the types, and sorted by name per type.
+func (c *controller) templatesFor(cwts []*tenancyv1alpha1.ClusterWorkspaceType) ([]*tenancyv1alpha1.ClusterWorkspaceTemplate, error) {
+	var ret []*tenancyv1alpha1.ClusterWorkspaceTemplate
+	for _, cwt := range cwts {
+		templates, err := c.listClusterWorkspaceTemplates(logicalcluster.From(cwt))
+		if err != nil {
+			return nil, err
+		}
+
+		var forType []*tenancyv1alpha1.ClusterWorkspaceTemplate
+		for _, template := range templates {
+			if template.Spec.Type == tenancyv1alpha1.ClusterWorkspaceTypeName(cwt.Name) {
+				forType = append(forType, template)
+			}
+		}
+		sort.Slice(forType, func(i, j int) bool {
+			return forType[i].Name < forType[j].Name
+		})
+		ret = append(ret, forType...)
+	}
+	return ret, nil
+}
+
+// parameterValues returns the values of the parameters of the template, from the annotations of the
+// workspace or the parameter default values, and the built-in parameters. It also returns the names of
+// the required parameters without value.
+func parameterValues(template *tenancyv1alpha1.ClusterWorkspaceTemplate, annotations, builtins map[string]string) (map[string]string, []string) {
+	values := make(map[string]string, len(builtins)+len(template.Spec.Parameters))
+	var missing []string
+	for _, p := range template.Spec.Parameters {
+		if _, found := builtins[p.Name]; found {
+			continue
+		}
+		value := annotations[tenancyv1alpha1.ClusterWorkspaceTemplateParameterAnnotationPrefix+p.Name]
+		if value == "" {
+			value = p.Value
+		}
+		if value == "" && p.Required {
+			missing = append(missing, p.Name)
+		}
+		values[p.Name] = value
+	}
+	for name, value := range builtins {
+		values[name] = value
+	}
+	return values, missing
+}
+
+// substitute replaces the references of the given parameters in all string values of obj. References of
+// unknown parameters are kept as they are.
+func substitute(obj interface{}, values map[string]string) interface{} {
+	switch obj := obj.(type) {
+	case map[string]interface{}:
+		for k, v := range obj {
+			obj[k] = substitute(v, values)
+		}
+		return obj
+	case []interface{}:
+		for i, v := range obj {
+			obj[i] = substitute(v, values)
+		}
+		return obj
+	case string:
+		return parameterReference.ReplaceAllStringFunc(obj, func(ref string) string {
+			if value, found := values[parameterReference.FindStringSubmatch(ref)[1]]; found {
+				return value
+			}
+			return ref
+		})
+	default:
+		return obj
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacetemplate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/conditions"
+)
+
+type fakeTransitiveTypeResolver map[string][]*tenancyv1alpha1.ClusterWorkspaceType
+
+func (r fakeTransitiveTypeResolver) Resolve(t *tenancyv1alpha1.ClusterWorkspaceType) ([]*tenancyv1alpha1.ClusterWorkspaceType, error) {
+	return append([]*tenancyv1alpha1.ClusterWorkspaceType{t}, r[t.Name]...), nil
+}
+
+func newTemplate(cluster, name, typeName string, parameters []tenancyv1alpha1.ClusterWorkspaceTemplateParameter, objects ...string) *tenancyv1alpha1.ClusterWorkspaceTemplate {
+	template := &tenancyv1alpha1.ClusterWorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+		},
+		Spec: tenancyv1alpha1.ClusterWorkspaceTemplateSpec{
+			Type:       tenancyv1alpha1.ClusterWorkspaceTypeName(typeName),
+			Parameters: parameters,
+		},
+	}
+	for _, obj := range objects {
+		template.Spec.Objects = append(template.Spec.Objects, runtime.RawExtension{Raw: []byte(obj)})
+	}
+	return template
+}
+
+func TestReconcile(t *testing.T) {
+	universal := &tenancyv1alpha1.ClusterWorkspaceType{
+		ObjectMeta: metav1.ObjectMeta{Name: "universal", Annotations: map[string]string{logicalcluster.AnnotationKey: "root"}},
+	}
+	team := &tenancyv1alpha1.ClusterWorkspaceType{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"}},
+	}
+
+	owner := []tenancyv1alpha1.ClusterWorkspaceTemplateParameter{{Name: "OWNER", Required: true}}
+	tier := []tenancyv1alpha1.ClusterWorkspaceTemplateParameter{{Name: "TIER", Value: "bronze"}}
+	templates := map[logicalcluster.Name][]*tenancyv1alpha1.ClusterWorkspaceTemplate{
+		logicalcluster.New("root"): {
+			newTemplate("root", "namespaces", "universal", nil, `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"${WORKSPACE_NAME}-system"}}`),
+			newTemplate("root", "other", "other", nil, `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"other"}}`),
+		},
+		logicalcluster.New("root:org"): {
+			newTemplate("root:org", "rbac", "team", owner, `{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRoleBinding","metadata":{"name":"owner"},"subjects":[{"kind":"User","name":"${OWNER}"}],"roleRef":{"kind":"ClusterRole","name":"admin"}}`),
+			newTemplate("root:org", "config", "team", tier, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config"},"data":{"tier":"${TIER}","path":"${WORKSPACE_PATH}","parent":"${PARENT_WORKSPACE_PATH}","unknown":"${UNKNOWN}"}}`),
+		},
+	}
+
+	tests := map[string]struct {
+		annotations map[string]string
+		createErr   error
+
+		wantCreated     []string
+		wantObjects     map[string]string
+		wantReason      string
+		wantError       bool
+		wantInitialized bool
+	}{
+		"objects of the templates of the type and the types it extends are created": {
+			annotations: map[string]string{tenancyv1alpha1.ClusterWorkspaceTemplateParameterAnnotationPrefix + "OWNER": "alice"},
+			wantCreated: []string{"ConfigMap default/config", "ClusterRoleBinding owner", "Namespace ws-system"},
+			wantObjects: map[string]string{
+				"ConfigMap default/config": `{"apiVersion":"v1","data":{"parent":"root:org","path":"root:org:ws","tier":"bronze","unknown":"${UNKNOWN}"},"kind":"ConfigMap","metadata":{"name":"config","namespace":"default"}}`,
+				"ClusterRoleBinding owner": `{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRoleBinding","metadata":{"name":"owner"},"roleRef":{"kind":"ClusterRole","name":"admin"},"subjects":[{"kind":"User","name":"alice"}]}`,
+			},
+			wantInitialized: true,
+		},
+		"annotations override default values": {
+			annotations: map[string]string{
+				tenancyv1alpha1.ClusterWorkspaceTemplateParameterAnnotationPrefix + "OWNER": "alice",
+				tenancyv1alpha1.ClusterWorkspaceTemplateParameterAnnotationPrefix + "TIER":  "gold",
+			},
+			wantCreated: []string{"ConfigMap default/config", "ClusterRoleBinding owner", "Namespace ws-system"},
+			wantObjects: map[string]string{
+				"ConfigMap default/config": `{"apiVersion":"v1","data":{"parent":"root:org","path":"root:org:ws","tier":"gold","unknown":"${UNKNOWN}"},"kind":"ConfigMap","metadata":{"name":"config","namespace":"default"}}`,
+			},
+			wantInitialized: true,
+		},
+		"missing required parameter": {
+			wantReason: tenancyv1alpha1.WorkspaceInitializedTemplateParameterMissing,
+		},
+		"existing objects are not changed": {
+			annotations:     map[string]string{tenancyv1alpha1.ClusterWorkspaceTemplateParameterAnnotationPrefix + "OWNER": "alice"},
+			createErr:       apierrors.NewAlreadyExists(schema.GroupResource{Resource: "objects"}, "object"),
+			wantInitialized: true,
+		},
+		"errors are retried": {
+			annotations: map[string]string{tenancyv1alpha1.ClusterWorkspaceTemplateParameterAnnotationPrefix + "OWNER": "alice"},
+			createErr:   errors.New("no matches for kind"),
+			wantReason:  tenancyv1alpha1.WorkspaceInitializedTemplateErrors,
+			wantError:   true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created []string
+			objects := map[string]string{}
+			c := &controller{
+				getClusterWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+					return team, nil
+				},
+				listClusterWorkspaceTemplates: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.ClusterWorkspaceTemplate, error) {
+					return templates[clusterName], nil
+				},
+				createObject: func(ctx context.Context, clusterName logicalcluster.Name, obj *unstructured.Unstructured) error {
+					require.Equal(t, "root:org:ws", clusterName.String())
+					if tc.createErr != nil {
+						return tc.createErr
+					}
+					if obj.GetKind() == "ConfigMap" {
+						obj.SetNamespace(metav1.NamespaceDefault)
+					}
+					key := obj.GetKind() + " " + obj.GetName()
+					if obj.GetNamespace() != "" {
+						key = obj.GetKind() + " " + obj.GetNamespace() + "/" + obj.GetName()
+					}
+					created = append(created, key)
+					raw, err := obj.MarshalJSON()
+					require.NoError(t, err)
+					objects[key] = string(raw)
+					return nil
+				},
+				transitiveTypeResolver: fakeTransitiveTypeResolver{"team": {universal}},
+			}
+
+			clusterWorkspace := &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ws",
+					Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+					Type: tenancyv1alpha1.ClusterWorkspaceTypeReference{Path: "root:org", Name: "team"},
+				},
+				Status: tenancyv1alpha1.ClusterWorkspaceStatus{
+					Initializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{tenancyv1alpha1.ClusterWorkspaceTemplatesInitializer},
+				},
+			}
+			for k, v := range tc.annotations {
+				clusterWorkspace.Annotations[k] = v
+			}
+
+			err := c.reconcile(context.Background(), clusterWorkspace)
+			require.Equal(t, tc.wantError, err != nil, "unexpected error: %v", err)
+			require.Equal(t, tc.wantCreated, created)
+			for key, obj := range tc.wantObjects {
+				require.JSONEq(t, obj, objects[key], "object %s", key)
+			}
+			if tc.wantInitialized {
+				require.Empty(t, clusterWorkspace.Status.Initializers)
+				require.True(t, conditions.IsTrue(clusterWorkspace, tenancyv1alpha1.WorkspaceTemplatesInstantiated))
+			} else {
+				require.NotEmpty(t, clusterWorkspace.Status.Initializers)
+				require.Equal(t, tc.wantReason, conditions.GetReason(clusterWorkspace, tenancyv1alpha1.WorkspaceTemplatesInstantiated))
+			}
+		})
+	}
+}
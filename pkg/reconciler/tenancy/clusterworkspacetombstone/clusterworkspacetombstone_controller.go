@@ -0,0 +1,261 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterworkspacetombstone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1/helper"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	tenancyv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	tenancyv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacedeletion/deletion"
+)
+
+const (
+	ControllerName = "kcp-clusterworkspacetombstone"
+)
+
+var (
+	background         = metav1.DeletePropagationBackground
+	backgroundDeletion = metav1.DeleteOptions{PropagationPolicy: &background}
+)
+
+// NewController returns a controller that deletes the retained content of deleted workspaces when their
+// ClusterWorkspaceTombstone expires, and removes the tombstones of restored workspaces.
+func NewController(
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
+	kcpClusterClient kcpclientset.ClusterInterface,
+	tombstoneInformer tenancyv1alpha1informers.ClusterWorkspaceTombstoneClusterInformer,
+	workspaceInformer tenancyv1alpha1informers.ClusterWorkspaceClusterInformer,
+	deleter deletion.WorkspaceResourcesDeleterInterface,
+) *Controller {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+
+	c := &Controller{
+		queue:             queue,
+		kubeClusterClient: kubeClusterClient,
+		kcpClusterClient:  kcpClusterClient,
+		tombstoneLister:   tombstoneInformer.Lister(),
+		workspaceLister:   workspaceInformer.Lister(),
+		deleter:           deleter,
+		now:               time.Now,
+	}
+
+	tombstoneInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueue(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueue(obj) },
+	})
+
+	// a ClusterWorkspace of the same name restores the workspace of a tombstone.
+	workspaceInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			switch obj := obj.(type) {
+			case *tenancyv1alpha1.ClusterWorkspace:
+				return obj.DeletionTimestamp.IsZero()
+			default:
+				return false
+			}
+		},
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { c.enqueue(obj) },
+		},
+	})
+
+	return c
+}
+
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface
+	kcpClusterClient  kcpclientset.ClusterInterface
+
+	tombstoneLister tenancyv1alpha1listers.ClusterWorkspaceTombstoneClusterLister
+	workspaceLister tenancyv1alpha1listers.ClusterWorkspaceClusterLister
+	deleter         deletion.WorkspaceResourcesDeleterInterface
+
+	now func() time.Time
+}
+
+// enqueue adds the key of a tombstone, or of the tombstone of the same name as a ClusterWorkspace.
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := kcpcache.MetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing ClusterWorkspaceTombstone")
+	c.queue.Add(key)
+}
+
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.Until(func() { c.startWorker(ctx) }, time.Second, ctx.Done())
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.process(ctx, key)
+	if err == nil {
+		c.queue.Forget(key)
+		if requeueAfter > 0 {
+			c.queue.AddAfter(key, requeueAfter)
+		}
+		return true
+	}
+
+	var estimate *deletion.ResourcesRemainingError
+	if errors.As(err, &estimate) {
+		duration := time.Duration(estimate.Estimate/2+1) * time.Second
+		logger.V(2).Error(err, "content remaining in expired workspace after a wait, waiting more to continue", "waiting", duration)
+		c.queue.AddAfter(key, duration)
+	} else {
+		c.queue.AddRateLimited(key)
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+	}
+
+	return true
+}
+
+// process removes the tombstone of a restored workspace, or deletes the content of the workspace and the
+// tombstone when it has expired. Otherwise, it returns the time until expiration.
+func (c *Controller) process(ctx context.Context, key string) (time.Duration, error) {
+	logger := klog.FromContext(ctx)
+	clusterName, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return 0, nil
+	}
+	tombstone, err := c.tombstoneLister.Cluster(clusterName).Get(name)
+	if apierrors.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	logger = logging.WithObject(logger, tombstone)
+	ctx = klog.NewContext(ctx, logger)
+
+	workspace, err := c.workspaceLister.Cluster(clusterName).Get(name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	}
+	if workspace != nil && workspace.DeletionTimestamp.IsZero() {
+		logger.V(2).Info("workspace has been restored, removing ClusterWorkspaceTombstone")
+		return 0, c.deleteTombstone(ctx, tombstone)
+	}
+
+	if remaining := tombstone.Spec.ExpirationTime.Sub(c.now()); remaining > 0 {
+		return remaining, nil
+	}
+
+	logger.V(2).Info("ClusterWorkspaceTombstone expired, deleting workspace content")
+	if err := c.deleter.Delete(ctx, deletedWorkspace(clusterName, tombstone)); err != nil {
+		return 0, err
+	}
+
+	// the ClusterRoles and ClusterRoleBindings of the workspace are kept while the content is retained, such
+	// that a restored workspace is accessible like before.
+	listOpts := metav1.ListOptions{
+		LabelSelector: helper.WorkspaceLabelSelector(tombstone.Name),
+	}
+	if err := c.kubeClusterClient.Cluster(clusterName).RbacV1().ClusterRoles().DeleteCollection(ctx, backgroundDeletion, listOpts); err != nil && !apierrors.IsNotFound(err) {
+		return 0, fmt.Errorf("could not delete clusterroles for workspace %s|%s: %w", clusterName, tombstone.Name, err)
+	}
+	if err := c.kubeClusterClient.Cluster(clusterName).RbacV1().ClusterRoleBindings().DeleteCollection(ctx, backgroundDeletion, listOpts); err != nil && !apierrors.IsNotFound(err) {
+		return 0, fmt.Errorf("could not delete clusterrolebindings for workspace %s|%s: %w", clusterName, tombstone.Name, err)
+	}
+
+	logger.V(2).Info("finished deleting content of expired workspace, removing ClusterWorkspaceTombstone")
+	return 0, c.deleteTombstone(ctx, tombstone)
+}
+
+func (c *Controller) deleteTombstone(ctx context.Context, tombstone *tenancyv1alpha1.ClusterWorkspaceTombstone) error {
+	err := c.kcpClusterClient.Cluster(logicalcluster.From(tombstone)).TenancyV1alpha1().ClusterWorkspaceTombstones().Delete(ctx, tombstone.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &tombstone.UID, ResourceVersion: &tombstone.ResourceVersion},
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// deletedWorkspace returns the deleted ClusterWorkspace of a tombstone, as expected by the workspace
+// resources deleter.
+func deletedWorkspace(clusterName logicalcluster.Name, tombstone *tenancyv1alpha1.ClusterWorkspaceTombstone) *tenancyv1alpha1.ClusterWorkspace {
+	deletionTime := tombstone.Spec.DeletionTime
+	return &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: tombstone.Name,
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: clusterName.String(),
+			},
+			DeletionTimestamp: &deletionTime,
+			Finalizers:        []string{deletion.WorkspaceFinalizer},
+		},
+	}
+}
@@ -36,7 +36,7 @@ import (
 	"github.com/kcp-dev/kcp/pkg/apis/tenancy/initialization"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 	"github.com/kcp-dev/kcp/pkg/logging"
 )
 
@@ -121,6 +121,13 @@ func (b *APIBinder) reconcile(ctx context.Context, clusterWorkspace *tenancyv1al
 			logger := logger.WithValues("apiExport.path", exportRef.Path, "apiExport.name", exportRef.ExportName)
 			ctx := klog.NewContext(ctx, logger)
 
+			// The export might already be bound, e.g. because a type further up the chain declares it
+			// too, or because the workspace creator bound it under a different name.
+			if existing, found := exportToBinding[apisv1alpha1.WorkspaceExportReference{Path: exportRef.Path, ExportName: exportRef.ExportName}]; found {
+				logger.V(4).Info("APIExport already bound - skipping creation", "apiBinding", existing.Name)
+				continue
+			}
+
 			apiBindingName := generateAPIBindingName(clusterName, exportRef.Path, exportRef.ExportName)
 			logger = logger.WithValues("apiBindingName", apiBindingName)
 
@@ -17,12 +17,20 @@ limitations under the License.
 package initialization
 
 import (
+	"context"
 	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/kcp-dev/logicalcluster/v2"
 	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 )
 
 func TestGenerateAPIBindingName(t *testing.T) {
@@ -83,6 +91,126 @@ func TestGenerateAPIBindingNameWithMultipleSimilarLongNames(t *testing.T) {
 	require.NotEqual(t, generated1, generated2, "expected different generated names")
 }
 
+type fakeTransitiveTypeResolver map[string][]*tenancyv1alpha1.ClusterWorkspaceType
+
+func (r fakeTransitiveTypeResolver) Resolve(t *tenancyv1alpha1.ClusterWorkspaceType) ([]*tenancyv1alpha1.ClusterWorkspaceType, error) {
+	return append([]*tenancyv1alpha1.ClusterWorkspaceType{t}, r[t.Name]...), nil
+}
+
 func TestReconcile(t *testing.T) {
+	universal := &tenancyv1alpha1.ClusterWorkspaceType{
+		ObjectMeta: metav1.ObjectMeta{Name: "universal"},
+		Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+			DefaultAPIBindings: []tenancyv1alpha1.APIExportReference{{Path: "root", ExportName: "tenancy.kcp.dev"}},
+		},
+	}
+	team := &tenancyv1alpha1.ClusterWorkspaceType{
+		ObjectMeta: metav1.ObjectMeta{Name: "team"},
+		Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{
+			DefaultAPIBindings: []tenancyv1alpha1.APIExportReference{
+				{Path: "root:org", ExportName: "widgets"},
+				{Path: "root", ExportName: "tenancy.kcp.dev"},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		existingBindings []*apisv1alpha1.APIBinding
+		wantCreated      []apisv1alpha1.WorkspaceExportReference
+		wantReason       string
+		wantInitialized  bool
+	}{
+		"bindings of the type and the types it extends are created once": {
+			wantCreated: []apisv1alpha1.WorkspaceExportReference{
+				{Path: "root:org", ExportName: "widgets"},
+				{Path: "root", ExportName: "tenancy.kcp.dev"},
+			},
+			wantReason: tenancyv1alpha1.WorkspaceInitializedWaitingOnAPIBindings,
+		},
+		"exports bound under a different name are not bound again": {
+			existingBindings: []*apisv1alpha1.APIBinding{
+				newAPIBinding("my-widgets", "root:org", "widgets", true),
+				newAPIBinding("tenancy", "root", "tenancy.kcp.dev", true),
+			},
+			wantInitialized: true,
+		},
+		"waits for existing bindings to be bound": {
+			existingBindings: []*apisv1alpha1.APIBinding{
+				newAPIBinding("my-widgets", "root:org", "widgets", false),
+				newAPIBinding("tenancy", "root", "tenancy.kcp.dev", true),
+			},
+			wantReason: tenancyv1alpha1.WorkspaceInitializedWaitingOnAPIBindings,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created []apisv1alpha1.WorkspaceExportReference
+			b := &APIBinder{
+				getClusterWorkspaceType: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+					return team, nil
+				},
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					return tc.existingBindings, nil
+				},
+				getAPIBinding: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIBinding, error) {
+					for _, binding := range tc.existingBindings {
+						if binding.Name == name {
+							return binding, nil
+						}
+					}
+					return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apibindings"), name)
+				},
+				createAPIBinding: func(ctx context.Context, clusterName logicalcluster.Name, binding *apisv1alpha1.APIBinding) (*apisv1alpha1.APIBinding, error) {
+					for _, ref := range created {
+						if ref == *binding.Spec.Reference.Workspace {
+							return nil, apierrors.NewAlreadyExists(apisv1alpha1.Resource("apibindings"), binding.Name)
+						}
+					}
+					created = append(created, *binding.Spec.Reference.Workspace)
+					return binding, nil
+				},
+				getAPIExport: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIExport, error) {
+					return &apisv1alpha1.APIExport{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+				},
+				transitiveTypeResolver: fakeTransitiveTypeResolver{"team": {universal}},
+			}
+
+			clusterWorkspace := &tenancyv1alpha1.ClusterWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ws",
+					Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+				},
+				Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+					Type: tenancyv1alpha1.ClusterWorkspaceTypeReference{Path: "root:org", Name: "team"},
+				},
+				Status: tenancyv1alpha1.ClusterWorkspaceStatus{
+					Initializers: []tenancyv1alpha1.ClusterWorkspaceInitializer{tenancyv1alpha1.ClusterWorkspaceAPIBindingsInitializer},
+				},
+			}
+
+			err := b.reconcile(context.Background(), clusterWorkspace)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantCreated, created)
+			if tc.wantInitialized {
+				require.Empty(t, clusterWorkspace.Status.Initializers)
+			} else {
+				require.Equal(t, tc.wantReason, conditions.GetReason(clusterWorkspace, tenancyv1alpha1.WorkspaceAPIBindingsInitialized))
+			}
+		})
+	}
+}
 
+func newAPIBinding(name, exportPath, exportName string, bound bool) *apisv1alpha1.APIBinding {
+	binding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: apisv1alpha1.APIBindingSpec{
+			Reference: apisv1alpha1.ExportReference{
+				Workspace: &apisv1alpha1.WorkspaceExportReference{Path: exportPath, ExportName: exportName},
+			},
+		},
+	}
+	if bound {
+		conditions.MarkTrue(binding, apisv1alpha1.InitialBindingCompleted)
+	}
+	return binding
 }
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacebackup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// archiveVersion is bumped whenever the format of the archive changes incompatibly.
+const archiveVersion = 1
+
+// An archive is a gzip compressed stream of JSON documents: a header, followed by one
+// entry per object. Objects of the same resource are consecutive, and the resources
+// other objects depend on come first.
+type archiveHeader struct {
+	Version int    `json:"version"`
+	Cluster string `json:"cluster"`
+	// WorkspaceUID is the UID of the backed up ClusterWorkspace.
+	WorkspaceUID types.UID `json:"workspaceUID,omitempty"`
+}
+
+// archiveResource describes the resource of an object in the archive.
+type archiveResource struct {
+	Group      string `json:"group,omitempty"`
+	Version    string `json:"version"`
+	Resource   string `json:"resource"`
+	Namespaced bool   `json:"namespaced,omitempty"`
+	HasStatus  bool   `json:"hasStatus,omitempty"`
+	// IdentityHash is the identity of the APIExport the resource is bound from, if any.
+	// Objects of bound resources can only be restored into a workspace binding the
+	// resource from an APIExport with the same identity.
+	IdentityHash string `json:"identityHash,omitempty"`
+}
+
+func (r archiveResource) gvr() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+}
+
+type archiveEntry struct {
+	Resource archiveResource        `json:"resource"`
+	Object   map[string]interface{} `json:"object"`
+}
+
+type archiveWriter struct {
+	gz  *gzip.Writer
+	enc *json.Encoder
+}
+
+// newArchiveWriter writes the header of an archive of the given logical cluster, backing up the
+// ClusterWorkspace with the given UID, to w.
+func newArchiveWriter(w io.Writer, clusterName logicalcluster.Name, workspaceUID types.UID) (*archiveWriter, error) {
+	gz := gzip.NewWriter(w)
+	aw := &archiveWriter{gz: gz, enc: json.NewEncoder(gz)}
+	if err := aw.enc.Encode(&archiveHeader{Version: archiveVersion, Cluster: clusterName.String(), WorkspaceUID: workspaceUID}); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+func (w *archiveWriter) write(resource archiveResource, obj map[string]interface{}) error {
+	return w.enc.Encode(&archiveEntry{Resource: resource, Object: obj})
+}
+
+// close flushes the archive. It does not close the underlying writer.
+func (w *archiveWriter) close() error {
+	return w.gz.Close()
+}
+
+type archiveReader struct {
+	dec    *json.Decoder
+	header archiveHeader
+}
+
+// newArchiveReader reads the header of the archive in r.
+func newArchiveReader(r io.Reader) (*archiveReader, error) {
+	gz, err := gzip.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup: %w", err)
+	}
+	ar := &archiveReader{dec: json.NewDecoder(gz)}
+	if err := ar.dec.Decode(&ar.header); err != nil {
+		return nil, fmt.Errorf("invalid backup header: %w", err)
+	}
+	if ar.header.Version != archiveVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", ar.header.Version)
+	}
+	return ar, nil
+}
+
+// next returns the next entry of the archive, or io.EOF after the last one.
+func (r *archiveReader) next() (*archiveEntry, error) {
+	var entry archiveEntry
+	if err := r.dec.Decode(&entry); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("invalid backup entry: %w", err)
+	}
+	return &entry, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacebackup
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewStore("file://" + t.TempDir())
+	require.NoError(t, err)
+
+	namespaces := archiveResource{Version: "v1", Resource: "namespaces"}
+	widgets := archiveResource{Group: "example.com", Version: "v1", Resource: "widgets", Namespaced: true, HasStatus: true, IdentityHash: "abc"}
+	objects := []archiveEntry{
+		{Resource: namespaces, Object: map[string]interface{}{"apiVersion": "v1", "kind": "Namespace", "metadata": map[string]interface{}{"name": "default"}}},
+		{Resource: widgets, Object: map[string]interface{}{"apiVersion": "example.com/v1", "kind": "Widget", "metadata": map[string]interface{}{"name": "a", "namespace": "default"}}},
+	}
+
+	sw, err := store.NewWriter(ctx, "root:org/backup-uid.json.gz")
+	require.NoError(t, err)
+	w, err := newArchiveWriter(sw, logicalcluster.New("root:org:ws"), "ws-uid")
+	require.NoError(t, err)
+	for _, o := range objects {
+		require.NoError(t, w.write(o.Resource, o.Object))
+	}
+	require.NoError(t, w.close())
+
+	_, err = store.NewReader(ctx, "root:org/backup-uid.json.gz")
+	require.Error(t, err, "backup must not be visible before the writer is closed")
+	require.NoError(t, sw.Close())
+
+	rc, err := store.NewReader(ctx, "root:org/backup-uid.json.gz")
+	require.NoError(t, err)
+	defer rc.Close()
+	r, err := newArchiveReader(rc)
+	require.NoError(t, err)
+	require.Equal(t, "root:org:ws", r.header.Cluster)
+	require.Equal(t, types.UID("ws-uid"), r.header.WorkspaceUID)
+
+	var got []archiveEntry
+	for {
+		entry, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, *entry)
+	}
+	require.Equal(t, objects, got)
+}
+
+func TestFileStoreAbort(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewStore("file://" + t.TempDir())
+	require.NoError(t, err)
+
+	sw, err := store.NewWriter(ctx, "root:org/backup-uid.json.gz")
+	require.NoError(t, err)
+	_, err = sw.Write([]byte("partial"))
+	require.NoError(t, err)
+	require.NoError(t, sw.Abort())
+
+	_, err = store.NewReader(ctx, "root:org/backup-uid.json.gz")
+	require.Error(t, err)
+}
+
+func TestNewStore(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "access")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	for _, tt := range []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "file:///var/lib/kcp/backups"},
+		{url: "file://relative/path", wantErr: true},
+		{url: "s3://bucket/backups"},
+		{url: "s3://bucket?region=eu-west-1&endpoint=https://minio.example.com:9000"},
+		{url: "s3:///backups", wantErr: true},
+		{url: "s3://bucket/backups?endpoint=minio.example.com", wantErr: true},
+		{url: "gs://bucket/backups", wantErr: true},
+	} {
+		_, err := NewStore(tt.url)
+		require.Equal(t, tt.wantErr, err != nil, "url %q: %v", tt.url, err)
+	}
+
+	store, err := NewStore("file:///var/lib/kcp/backups")
+	require.NoError(t, err)
+	_, err = store.NewReader(context.Background(), "../../etc/passwd")
+	require.Error(t, err)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacebackup
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/pager"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacecontent"
+)
+
+var apiBindingsGVR = apisv1alpha1.SchemeGroupVersion.WithResource("apibindings")
+
+// notBackedUp are resources that are not worth restoring, in addition to the resources that
+// are derived from other objects.
+var notBackedUp = sets.NewString(
+	"events",
+	"events.events.k8s.io",
+)
+
+// resourcesToBackUp returns the persisted resources of the given discovery information, with
+// the resources that other objects depend on first. Bound resources carry the identity of
+// the APIExport they are bound from.
+func resourcesToBackUp(lists []*metav1.APIResourceList, identities map[schema.GroupResource]string) ([]archiveResource, error) {
+	resources, err := workspacecontent.Resources(lists, notBackedUp)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]archiveResource, 0, len(resources))
+	for _, r := range resources {
+		ret = append(ret, archiveResource{
+			Group:        r.GVR.Group,
+			Version:      r.GVR.Version,
+			Resource:     r.GVR.Resource,
+			Namespaced:   r.Namespaced,
+			HasStatus:    r.HasStatus,
+			IdentityHash: identities[r.GVR.GroupResource()],
+		})
+	}
+	return ret, nil
+}
+
+// boundResourceIdentities returns the identities of the resources bound by the APIBindings
+// of the logical cluster of the given client.
+func boundResourceIdentities(ctx context.Context, client dynamic.Interface) (map[schema.GroupResource]string, error) {
+	list, err := client.Resource(apiBindingsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list APIBindings: %w", err)
+	}
+	identities := map[schema.GroupResource]string{}
+	for i := range list.Items {
+		binding := &apisv1alpha1.APIBinding{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[i].Object, binding); err != nil {
+			return nil, fmt.Errorf("failed to convert APIBinding %s: %w", list.Items[i].GetName(), err)
+		}
+		for _, r := range binding.Status.BoundResources {
+			identities[schema.GroupResource{Group: r.Group, Resource: r.Resource}] = r.Schema.IdentityHash
+		}
+	}
+	return identities, nil
+}
+
+// writeObjects writes the objects of the given resources to the archive, page by page. It
+// returns the number of objects written.
+func writeObjects(ctx context.Context, resources []archiveResource, client dynamic.Interface, w *archiveWriter) (int64, error) {
+	logger := klog.FromContext(ctx)
+
+	var count int64
+	for _, r := range resources {
+		p := pager.New(pager.SimplePageFunc(func(opts metav1.ListOptions) (runtime.Object, error) {
+			return client.Resource(r.gvr()).List(ctx, opts)
+		}))
+		before := count
+		err := p.EachListItem(ctx, metav1.ListOptions{}, func(o runtime.Object) error {
+			obj, ok := o.(*unstructured.Unstructured)
+			if !ok {
+				return fmt.Errorf("unexpected object type %T", o)
+			}
+			if !backedUp(obj) {
+				return nil
+			}
+			count++
+			return w.write(r, obj.Object)
+		})
+		if err != nil {
+			return count, fmt.Errorf("failed to back up %s: %w", r.gvr(), err)
+		}
+		logger.V(4).Info("backed up objects", "resource", r.gvr().String(), "count", count-before)
+	}
+	return count, nil
+}
+
+// backedUp returns whether the object is written to a backup. Objects being deleted and
+// service account tokens, which are recreated by the token controller, are not.
+func backedUp(obj *unstructured.Unstructured) bool {
+	if obj.GetDeletionTimestamp() != nil {
+		return false
+	}
+	if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Secret" {
+		if t, _, _ := unstructured.NestedString(obj.Object, "type"); t == "kubernetes.io/service-account-token" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacebackup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacecontent"
+)
+
+// errAPIBindingsNotReady is returned when objects of a bound resource are restored before the
+// restored APIBindings bind the resource with the identity it had in the backup.
+var errAPIBindingsNotReady = errors.New("APIBindings not ready")
+
+// statusNotRestored are resources whose status is computed by kcp for the restored object.
+var statusNotRestored = sets.NewString(
+	"customresourcedefinitions.apiextensions.k8s.io",
+	"apibindings.apis.kcp.dev",
+	"namespaces",
+)
+
+// restoreObjects creates the objects of the archive. Objects that already exist are not changed,
+// hence a failed restore can be retried. It returns the number of objects in the archive.
+//
+// If preserveUIDs is true, the objects are created with the UIDs they have in the backup.
+// Otherwise, the API server assigns new UIDs. Owner references are rewritten to the UIDs of
+// the restored objects after all objects are restored. Owner references to objects that are
+// not in the backup are kept as they are.
+func restoreObjects(ctx context.Context, r *archiveReader, client dynamic.Interface, preserveUIDs bool) (int64, error) {
+	type restored struct {
+		gvr    schema.GroupVersionResource
+		refs   []metav1.OwnerReference
+		object *unstructured.Unstructured
+	}
+	var withOwners []restored
+	uids := map[types.UID]types.UID{}
+
+	var identities map[schema.GroupResource]string
+	var count int64
+	for {
+		entry, err := r.next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return count, err
+		}
+		count++
+
+		gvr := entry.Resource.gvr()
+		if hash := entry.Resource.IdentityHash; hash != "" && identities[gvr.GroupResource()] != hash {
+			if identities, err = boundResourceIdentities(ctx, client); err != nil {
+				return count, err
+			}
+			if identities[gvr.GroupResource()] != hash {
+				return count, fmt.Errorf("%w: %s is not bound from an APIExport with identity %s", errAPIBindingsNotReady, gvr.GroupResource(), hash)
+			}
+		}
+
+		obj := &unstructured.Unstructured{Object: entry.Object}
+		resourceClient := client.Resource(gvr).Namespace(obj.GetNamespace())
+		toCreate := workspacecontent.PrepareForCreate(obj)
+		if preserveUIDs && obj.GetUID() != "" {
+			annotations := toCreate.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[tenancyv1alpha1.WorkspaceRestoreUIDAnnotationKey] = string(obj.GetUID())
+			toCreate.SetAnnotations(annotations)
+		}
+		created, err := resourceClient.Create(ctx, toCreate, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			created, err = resourceClient.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		} else if err == nil && entry.Resource.HasStatus && !statusNotRestored.Has(gvr.GroupResource().String()) {
+			if status, found := obj.Object["status"]; found {
+				created.Object["status"] = status
+				created, err = resourceClient.UpdateStatus(ctx, created, metav1.UpdateOptions{})
+			}
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed to restore %s %s: %w", gvr, workspacecontent.QualifiedName(obj), err)
+		}
+
+		uids[obj.GetUID()] = created.GetUID()
+		if len(obj.GetOwnerReferences()) > 0 {
+			withOwners = append(withOwners, restored{gvr: gvr, refs: obj.GetOwnerReferences(), object: created})
+		}
+	}
+
+	for _, o := range withOwners {
+		refs := make([]metav1.OwnerReference, 0, len(o.refs))
+		for _, ref := range o.refs {
+			if uid, found := uids[ref.UID]; found {
+				ref.UID = uid
+			}
+			refs = append(refs, ref)
+		}
+		if workspacecontent.EqualOwnerReferences(refs, o.object.GetOwnerReferences()) {
+			continue
+		}
+		o.object.SetOwnerReferences(refs)
+		if _, err := client.Resource(o.gvr).Namespace(o.object.GetNamespace()).Update(ctx, o.object, metav1.UpdateOptions{}); err != nil {
+			return count, fmt.Errorf("failed to restore owner references of %s %s: %w", o.gvr, workspacecontent.QualifiedName(o.object), err)
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacebackup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Store is an object store holding backups by key.
+type Store interface {
+	// NewWriter returns a writer for the backup with the given key. The backup becomes
+	// visible to readers only when the writer is closed without error. Abort discards it.
+	NewWriter(ctx context.Context, key string) (StoreWriter, error)
+	// NewReader returns a reader for the backup with the given key.
+	NewReader(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// StoreWriter writes a backup to a Store.
+type StoreWriter interface {
+	io.WriteCloser
+	// Abort discards the data written so far.
+	Abort() error
+}
+
+// NewStore returns the Store for the given URL. Supported are
+//
+//   - file:// URLs with the absolute path of a directory, e.g. file:///var/lib/kcp/backups, and
+//   - s3:// URLs with a bucket and an optional key prefix of an S3 compatible object store, e.g.
+//     s3://bucket/backups?region=eu-west-1 or s3://bucket/backups?endpoint=https://minio:9000.
+//     The credentials are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+//     AWS_SESSION_TOKEN environment variables.
+func NewStore(rawURL string) (Store, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup store URL %q: %w", rawURL, err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" || !filepath.IsAbs(u.Path) {
+			return nil, fmt.Errorf("invalid backup store URL %q: expected an absolute path like file:///var/lib/kcp/backups", rawURL)
+		}
+		return &fileStore{dir: u.Path}, nil
+	case "s3":
+		return newS3Store(u)
+	default:
+		return nil, fmt.Errorf("unsupported backup store URL scheme %q", u.Scheme)
+	}
+}
+
+// fileStore stores backups as files in a directory.
+type fileStore struct {
+	dir string
+}
+
+func (s *fileStore) path(key string) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, filepath.Clean(s.dir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid backup key %q", key)
+	}
+	return path, nil
+}
+
+func (s *fileStore) NewWriter(_ context.Context, key string) (StoreWriter, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return nil, err
+	}
+	return &fileWriter{File: tmp, path: path}, nil
+}
+
+func (s *fileStore) NewReader(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// fileWriter writes to a temporary file that is renamed to its final path on Close.
+type fileWriter struct {
+	*os.File
+	path string
+}
+
+func (w *fileWriter) Close() error {
+	if err := w.File.Close(); err != nil {
+		os.Remove(w.File.Name()) //nolint:errcheck
+		return err
+	}
+	return os.Rename(w.File.Name(), w.path)
+}
+
+func (w *fileWriter) Abort() error {
+	w.File.Close() //nolint:errcheck
+	return os.Remove(w.File.Name())
+}
@@ -0,0 +1,426 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacebackup
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+
+	// emptyPayloadHash is the SHA-256 of an empty request body.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	// defaultS3PartSize is the size of the parts of multipart uploads. Backups up to this size
+	// are uploaded with a single request.
+	defaultS3PartSize = 64 << 20
+	// s3MaxParts is the maximum number of parts of a multipart upload.
+	s3MaxParts = 10000
+	// s3MaxObjectSize is the maximum size of an object.
+	s3MaxObjectSize = 5 << 40
+)
+
+// s3Store stores backups as objects in a bucket of an S3 compatible object store. Objects
+// are addressed path-style, i.e. <endpoint>/<bucket>/<prefix>/<key>, which both AWS and
+// the common self-hosted implementations support. Requests are signed with AWS Signature
+// Version 4. Backups larger than partSize are uploaded in parts.
+type s3Store struct {
+	endpoint *url.URL
+	bucket   string
+	prefix   string
+	region   string
+
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+
+	partSize int64
+
+	client *http.Client
+	now    func() time.Time
+}
+
+func newS3Store(u *url.URL) (*s3Store, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid backup store URL %q: expected a bucket like s3://bucket/backups", u.String())
+	}
+	s := &s3Store{
+		bucket:          u.Host,
+		prefix:          strings.Trim(u.Path, "/"),
+		region:          u.Query().Get("region"),
+		accessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		partSize:        defaultS3PartSize,
+		client:          http.DefaultClient,
+		now:             time.Now,
+	}
+	if s.region == "" {
+		s.region = defaultS3Region
+	}
+
+	endpoint := u.Query().Get("endpoint")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", s.region)
+	}
+	var err error
+	if s.endpoint, err = url.Parse(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid endpoint of backup store URL %q: %w", u.String(), err)
+	}
+	if (s.endpoint.Scheme != "https" && s.endpoint.Scheme != "http") || s.endpoint.Host == "" || strings.Trim(s.endpoint.Path, "/") != "" {
+		return nil, fmt.Errorf("invalid endpoint of backup store URL %q: expected a URL like https://minio:9000", u.String())
+	}
+
+	if s.accessKeyID == "" || s.secretAccessKey == "" {
+		return nil, fmt.Errorf("backup store URL %q requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY to be set", u.String())
+	}
+	return s, nil
+}
+
+// objectURL returns the URL of the object of the given key, with the given query.
+func (s *s3Store) objectURL(key string, query url.Values) (*url.URL, error) {
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return nil, fmt.Errorf("invalid backup key %q", key)
+		}
+	}
+	path := "/" + s.bucket + "/" + key
+	if s.prefix != "" {
+		path = "/" + s.bucket + "/" + s.prefix + "/" + key
+	}
+	return &url.URL{
+		Scheme:   s.endpoint.Scheme,
+		Host:     s.endpoint.Host,
+		Path:     path,
+		RawPath:  s3Escape(path, false),
+		RawQuery: s3CanonicalQuery(query),
+	}, nil
+}
+
+func (s *s3Store) NewWriter(ctx context.Context, key string) (StoreWriter, error) {
+	if _, err := s.objectURL(key, nil); err != nil {
+		return nil, err
+	}
+	// objects are uploaded on Close, which requires the length and the hash of the content upfront.
+	tmp, err := os.CreateTemp("", "kcp-backup-")
+	if err != nil {
+		return nil, err
+	}
+	return &s3Writer{ctx: ctx, store: s, key: key, file: tmp}, nil
+}
+
+func (s *s3Store) NewReader(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, 0, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// do sends a signed request for the object of the given key, and returns the response if it
+// succeeded. The body of the response must be closed by the caller.
+func (s *s3Store) do(ctx context.Context, method, key string, query url.Values, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	u, err := s.objectURL(key, query)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil && size == 0 {
+		// an empty body of unknown type would be sent chunked
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	s.sign(req, payloadHash)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, s3Error(resp, key)
+	}
+	return resp, nil
+}
+
+// upload uploads the given content of the given size as the object of the given key, with a single
+// request if it fits in one part, and as a multipart upload otherwise.
+func (s *s3Store) upload(ctx context.Context, key string, content io.ReaderAt, size int64) error {
+	if size > s3MaxObjectSize {
+		return fmt.Errorf("backup %q of %d bytes exceeds the maximum object size of %d bytes", key, size, int64(s3MaxObjectSize))
+	}
+	if size <= s.partSize {
+		_, err := s.put(ctx, key, nil, io.NewSectionReader(content, 0, size), size)
+		return err
+	}
+
+	partSize := s.partSize
+	if partSize*s3MaxParts < size {
+		partSize = (size + s3MaxParts - 1) / s3MaxParts
+	}
+
+	uploadID, err := s.createMultipartUpload(ctx, key)
+	if err != nil {
+		return err
+	}
+	err = func() error {
+		var parts []s3CompletedPart
+		for offset := int64(0); offset < size; offset += partSize {
+			n := partSize
+			if offset+n > size {
+				n = size - offset
+			}
+			partNumber := len(parts) + 1
+			query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}
+			etag, err := s.put(ctx, key, query, io.NewSectionReader(content, offset, n), n)
+			if err != nil {
+				return err
+			}
+			parts = append(parts, s3CompletedPart{PartNumber: partNumber, ETag: etag})
+		}
+		return s.completeMultipartUpload(ctx, key, uploadID, parts)
+	}()
+	if err != nil {
+		// abort, such that the store drops the uploaded parts
+		resp, abortErr := s.do(ctx, http.MethodDelete, key, url.Values{"uploadId": {uploadID}}, nil, 0, emptyPayloadHash)
+		if abortErr == nil {
+			resp.Body.Close()
+		}
+		return utilerrors.NewAggregate([]error{err, abortErr})
+	}
+	return nil
+}
+
+// put uploads the given content as the object of the given key, or as a part of it if the query
+// names an upload. It returns the ETag of the uploaded content.
+func (s *s3Store) put(ctx context.Context, key string, query url.Values, content *io.SectionReader, size int64) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, key, query, content, size, hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	return resp.Header.Get("ETag"), nil
+}
+
+type s3InitiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type s3CompleteMultipartUpload struct {
+	XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+	Parts   []s3CompletedPart `xml:"Part"`
+}
+
+func (s *s3Store) createMultipartUpload(ctx context.Context, key string) (string, error) {
+	resp, err := s.do(ctx, http.MethodPost, key, url.Values{"uploads": {""}}, nil, 0, emptyPayloadHash)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result s3InitiateMultipartUploadResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("invalid response to the multipart upload of backup %q: %w", key, err)
+	}
+	if result.UploadID == "" {
+		return "", fmt.Errorf("invalid response to the multipart upload of backup %q: no upload ID", key)
+	}
+	return result.UploadID, nil
+}
+
+func (s *s3Store) completeMultipartUpload(ctx context.Context, key, uploadID string, parts []s3CompletedPart) error {
+	body, err := xml.Marshal(&s3CompleteMultipartUpload{Parts: parts})
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(body)
+	resp, err := s.do(ctx, http.MethodPost, key, url.Values{"uploadId": {uploadID}}, bytes.NewReader(body), int64(len(body)), hex.EncodeToString(hash[:]))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// the completion can fail after the response status has been sent, which is reported by an error document
+	var result struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("invalid response to the completion of the multipart upload of backup %q: %w", key, err)
+	}
+	if result.XMLName.Local == "Error" {
+		return fmt.Errorf("backup store failed to complete the multipart upload of backup %q: %s: %s", key, result.Code, result.Message)
+	}
+	return nil
+}
+
+// sign adds the AWS Signature Version 4 headers for the given payload hash to the request. Besides
+// the host and the x-amz-* headers, the content type and range headers are signed if present.
+func (s *s3Store) sign(req *http.Request, payloadHash string) {
+	amzDate := s.now().UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	req.Header.Set("x-amz-date", amzDate)
+	if s.sessionToken != "" {
+		req.Header.Set("x-amz-security-token", s.sessionToken)
+	}
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "content-type" || name == "content-md5" || name == "range" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3CanonicalQuery returns the query sorted by name and value, with names and values escaped as
+// required by the canonical query string of AWS Signature Version 4.
+func s3CanonicalQuery(query url.Values) string {
+	params := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, s3Escape(name, true)+"="+s3Escape(value, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// s3Escape escapes all characters of s but the unreserved ones, and slashes unless escapeSlash is
+// true, as required by the canonical URI and query string of AWS Signature Version 4.
+func s3Escape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c == '/' && !escapeSlash) || c == '-' || c == '_' || c == '.' || c == '~' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func s3Error(resp *http.Response, key string) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("backup %q not found: %s", key, strings.TrimSpace(string(body)))
+	}
+	return fmt.Errorf("backup store request for %q failed with %s: %s", key, resp.Status, strings.TrimSpace(string(body)))
+}
+
+// s3Writer buffers the backup in a temporary file and uploads it on Close.
+type s3Writer struct {
+	ctx   context.Context
+	store *s3Store
+	key   string
+	file  *os.File
+	size  int64
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	if w.size+int64(len(p)) > s3MaxObjectSize {
+		return 0, fmt.Errorf("backup %q exceeds the maximum object size of %d bytes", w.key, int64(s3MaxObjectSize))
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *s3Writer) Close() error {
+	defer os.Remove(w.file.Name()) //nolint:errcheck
+	defer w.file.Close()           //nolint:errcheck
+
+	return w.store.upload(w.ctx, w.key, w.file, w.size)
+}
+
+func (w *s3Writer) Abort() error {
+	w.file.Close() //nolint:errcheck
+	return os.Remove(w.file.Name())
+}
@@ -0,0 +1,470 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacebackup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	tenancyv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/logging"
+)
+
+const (
+	ControllerName = "kcp-workspacebackup"
+
+	// backupsKeyPrefix and restoresKeyPrefix distinguish the two kinds in the shared queue.
+	backupsKeyPrefix  = "workspacebackups::"
+	restoresKeyPrefix = "workspacerestores::"
+)
+
+// ShardClientsFunc returns clients for the given logical cluster on the given shard.
+type ShardClientsFunc func(shard *tenancyv1alpha1.ClusterWorkspaceShard, clusterName logicalcluster.Name) (dynamic.Interface, discovery.DiscoveryInterface, error)
+
+// NewController returns a controller that writes the objects of ClusterWorkspaces to the given
+// store as requested by WorkspaceBackups, and recreates them as requested by WorkspaceRestores.
+func NewController(
+	kcpClusterClient kcpclientset.ClusterInterface,
+	workspaceBackupInformer tenancyinformers.WorkspaceBackupClusterInformer,
+	workspaceRestoreInformer tenancyinformers.WorkspaceRestoreClusterInformer,
+	clusterWorkspaceInformer tenancyinformers.ClusterWorkspaceClusterInformer,
+	clusterWorkspaceShardInformer tenancyinformers.ClusterWorkspaceShardClusterInformer,
+	shardClients ShardClientsFunc,
+	store Store,
+) (*Controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+
+	c := &Controller{
+		queue:                  queue,
+		kcpClusterClient:       kcpClusterClient,
+		workspaceBackupLister:  workspaceBackupInformer.Lister(),
+		workspaceRestoreLister: workspaceRestoreInformer.Lister(),
+		clusterWorkspaceLister: clusterWorkspaceInformer.Lister(),
+		getShard: func(name string) (*tenancyv1alpha1.ClusterWorkspaceShard, error) {
+			return clusterWorkspaceShardInformer.Lister().Cluster(tenancyv1alpha1.RootCluster).Get(name)
+		},
+		shardClients: shardClients,
+		store:        store,
+	}
+	c.reconciler = &reconciler{
+		getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			return c.clusterWorkspaceLister.Cluster(clusterName).Get(name)
+		},
+		getBackup: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.WorkspaceBackup, error) {
+			return c.workspaceBackupLister.Cluster(clusterName).Get(name)
+		},
+		listRestores: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceRestore, error) {
+			return c.workspaceRestoreLister.Cluster(clusterName).List(labels.Everything())
+		},
+		backupWorkspace:      c.backupWorkspace,
+		backedUpWorkspaceUID: c.backedUpWorkspaceUID,
+		restoreWorkspace:     c.restoreWorkspace,
+		now:                  time.Now,
+	}
+
+	workspaceBackupInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueBackup(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueBackup(obj) },
+	})
+	workspaceRestoreInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueue(restoresKeyPrefix, obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueue(restoresKeyPrefix, obj) },
+	})
+	clusterWorkspaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueClusterWorkspace(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueClusterWorkspace(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueueClusterWorkspace(obj) },
+	})
+
+	return c, nil
+}
+
+// Controller backs up and restores workspaces.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	kcpClusterClient kcpclientset.ClusterInterface
+
+	workspaceBackupLister  tenancyv1alpha1listers.WorkspaceBackupClusterLister
+	workspaceRestoreLister tenancyv1alpha1listers.WorkspaceRestoreClusterLister
+	clusterWorkspaceLister tenancyv1alpha1listers.ClusterWorkspaceClusterLister
+	getShard               func(name string) (*tenancyv1alpha1.ClusterWorkspaceShard, error)
+	shardClients           ShardClientsFunc
+	store                  Store
+
+	reconciler *reconciler
+}
+
+func (c *Controller) enqueue(prefix string, obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), prefix+key)
+	logger.V(2).Info("queueing", "kind", strings.TrimSuffix(prefix, "::"))
+	c.queue.Add(prefix + key)
+}
+
+// enqueueBackup enqueues the backup and the restores waiting for it.
+func (c *Controller) enqueueBackup(obj interface{}) {
+	c.enqueue(backupsKeyPrefix, obj)
+
+	backup, ok := obj.(*tenancyv1alpha1.WorkspaceBackup)
+	if !ok {
+		return
+	}
+	restores, err := c.workspaceRestoreLister.Cluster(logicalcluster.From(backup)).List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, restore := range restores {
+		if restore.Spec.Backup == backup.Name {
+			c.enqueue(restoresKeyPrefix, restore)
+		}
+	}
+}
+
+// enqueueClusterWorkspace enqueues the backups of the workspace and the restore into it.
+func (c *Controller) enqueueClusterWorkspace(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	workspace, ok := obj.(*tenancyv1alpha1.ClusterWorkspace)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object type %T", obj))
+		return
+	}
+	clusterName := logicalcluster.From(workspace)
+
+	if restore, err := c.workspaceRestoreLister.Cluster(clusterName).Get(workspace.Name); err == nil {
+		c.enqueue(restoresKeyPrefix, restore)
+	} else if !kerrors.IsNotFound(err) {
+		runtime.HandleError(err)
+	}
+
+	backups, err := c.workspaceBackupLister.Cluster(clusterName).List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, backup := range backups {
+		if backup.Spec.Workspace == workspace.Name {
+			c.enqueue(backupsKeyPrefix, backup)
+		}
+	}
+}
+
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.Until(func() { c.startWorker(ctx) }, time.Second, ctx.Done())
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(1).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+
+	var prefix string
+	switch {
+	case strings.HasPrefix(key, backupsKeyPrefix):
+		prefix = backupsKeyPrefix
+	case strings.HasPrefix(key, restoresKeyPrefix):
+		prefix = restoresKeyPrefix
+	default:
+		logger.Error(errors.New("unknown key prefix"), "invalid key")
+		return nil
+	}
+	clusterName, namespace, name, err := kcpcache.SplitMetaClusterNamespaceKey(strings.TrimPrefix(key, prefix))
+	if err != nil {
+		logger.Error(err, "invalid key")
+		return nil
+	}
+	if namespace != "" {
+		logger.Error(errors.New("namespace found in key for cluster-wide object"), "invalid key")
+		return nil
+	}
+
+	if prefix == backupsKeyPrefix {
+		return c.processBackup(ctx, key, clusterName, name)
+	}
+	return c.processRestore(ctx, key, clusterName, name)
+}
+
+func (c *Controller) processBackup(ctx context.Context, key string, clusterName logicalcluster.Name, name string) error {
+	obj, err := c.workspaceBackupLister.Cluster(clusterName).Get(name)
+	if kerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	previous := obj
+	obj = obj.DeepCopy()
+
+	logger := logging.WithObject(klog.FromContext(ctx), obj)
+	ctx = klog.NewContext(ctx, logger)
+
+	requeueAfter, reconcileErr := c.reconciler.reconcileBackup(ctx, obj)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+
+	// If the object being reconciled changed as a result, update it.
+	var updateErr error
+	if !equality.Semantic.DeepEqual(previous.Status, obj.Status) {
+		updateErr = c.patchBackupStatus(ctx, previous, obj)
+	}
+
+	logger.V(6).Info("processed WorkspaceBackup")
+	return utilerrors.NewAggregate([]error{reconcileErr, updateErr})
+}
+
+func (c *Controller) processRestore(ctx context.Context, key string, clusterName logicalcluster.Name, name string) error {
+	obj, err := c.workspaceRestoreLister.Cluster(clusterName).Get(name)
+	if kerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	previous := obj
+	obj = obj.DeepCopy()
+
+	logger := logging.WithObject(klog.FromContext(ctx), obj)
+	ctx = klog.NewContext(ctx, logger)
+
+	requeueAfter, reconcileErr := c.reconciler.reconcileRestore(ctx, obj)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+
+	// If the object being reconciled changed as a result, update it.
+	var updateErr error
+	if !equality.Semantic.DeepEqual(previous.Status, obj.Status) {
+		updateErr = c.patchRestoreStatus(ctx, previous, obj)
+	}
+
+	logger.V(6).Info("processed WorkspaceRestore")
+	return utilerrors.NewAggregate([]error{reconcileErr, updateErr})
+}
+
+func (c *Controller) patchBackupStatus(ctx context.Context, previous, obj *tenancyv1alpha1.WorkspaceBackup) error {
+	clusterName := logicalcluster.From(obj)
+
+	oldData, err := json.Marshal(tenancyv1alpha1.WorkspaceBackup{
+		Status: previous.Status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to Marshal old data for workspace backup %s|%s: %w", clusterName, obj.Name, err)
+	}
+
+	newData, err := json.Marshal(tenancyv1alpha1.WorkspaceBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:             previous.UID,
+			ResourceVersion: previous.ResourceVersion,
+		}, // to ensure they appear in the patch as preconditions
+		Status: obj.Status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to Marshal new data for workspace backup %s|%s: %w", clusterName, obj.Name, err)
+	}
+
+	patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+	if err != nil {
+		return fmt.Errorf("failed to create patch for workspace backup %s|%s: %w", clusterName, obj.Name, err)
+	}
+	_, err = c.kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().WorkspaceBackups().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status")
+	return err
+}
+
+func (c *Controller) patchRestoreStatus(ctx context.Context, previous, obj *tenancyv1alpha1.WorkspaceRestore) error {
+	clusterName := logicalcluster.From(obj)
+
+	oldData, err := json.Marshal(tenancyv1alpha1.WorkspaceRestore{
+		Status: previous.Status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to Marshal old data for workspace restore %s|%s: %w", clusterName, obj.Name, err)
+	}
+
+	newData, err := json.Marshal(tenancyv1alpha1.WorkspaceRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:             previous.UID,
+			ResourceVersion: previous.ResourceVersion,
+		}, // to ensure they appear in the patch as preconditions
+		Status: obj.Status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to Marshal new data for workspace restore %s|%s: %w", clusterName, obj.Name, err)
+	}
+
+	patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+	if err != nil {
+		return fmt.Errorf("failed to create patch for workspace restore %s|%s: %w", clusterName, obj.Name, err)
+	}
+	_, err = c.kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().WorkspaceRestores().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status")
+	return err
+}
+
+// workspaceClients returns the clients for the logical cluster of the given workspace on its current shard.
+func (c *Controller) workspaceClients(workspace *tenancyv1alpha1.ClusterWorkspace) (dynamic.Interface, discovery.DiscoveryInterface, error) {
+	shard, err := c.getShard(workspace.Status.Location.Current)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get ClusterWorkspaceShard %q: %w", workspace.Status.Location.Current, err)
+	}
+	return c.shardClients(shard, logicalcluster.From(workspace).Join(workspace.Name))
+}
+
+func (c *Controller) backupWorkspace(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, key string) (int64, error) {
+	client, discoveryClient, err := c.workspaceClients(workspace)
+	if err != nil {
+		return 0, err
+	}
+	clusterName := logicalcluster.From(workspace).Join(workspace.Name)
+
+	lists, err := discoveryClient.ServerPreferredResources()
+	if err != nil {
+		return 0, fmt.Errorf("failed to discover resources of %s: %w", clusterName, err)
+	}
+	identities, err := boundResourceIdentities(ctx, client)
+	if err != nil {
+		return 0, err
+	}
+	resources, err := resourcesToBackUp(lists, identities)
+	if err != nil {
+		return 0, err
+	}
+
+	sw, err := c.store.NewWriter(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	count, err := func() (int64, error) {
+		w, err := newArchiveWriter(sw, clusterName, workspace.UID)
+		if err != nil {
+			return 0, err
+		}
+		count, err := writeObjects(ctx, resources, client, w)
+		if err != nil {
+			return count, err
+		}
+		return count, w.close()
+	}()
+	if err != nil {
+		return count, utilerrors.NewAggregate([]error{err, sw.Abort()})
+	}
+	return count, sw.Close()
+}
+
+func (c *Controller) backedUpWorkspaceUID(ctx context.Context, key string) (types.UID, error) {
+	rc, err := c.store.NewReader(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	r, err := newArchiveReader(rc)
+	if err != nil {
+		return "", err
+	}
+	return r.header.WorkspaceUID, nil
+}
+
+func (c *Controller) restoreWorkspace(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, key string, preserveUIDs bool) (int64, error) {
+	client, _, err := c.workspaceClients(workspace)
+	if err != nil {
+		return 0, err
+	}
+
+	rc, err := c.store.NewReader(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	r, err := newArchiveReader(rc)
+	if err != nil {
+		return 0, err
+	}
+	return restoreObjects(ctx, r, client, preserveUIDs)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacebackup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/conditions"
+)
+
+// apiBindingsNotReadyRequeue is the time to wait for restored APIBindings to bind their resources.
+const apiBindingsNotReadyRequeue = 5 * time.Second
+
+type reconciler struct {
+	getClusterWorkspace func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	getBackup           func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.WorkspaceBackup, error)
+	listRestores        func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceRestore, error)
+	// backupWorkspace writes the objects of the workspace to the store under the given key.
+	backupWorkspace func(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, key string) (int64, error)
+	// backedUpWorkspaceUID returns the UID of the ClusterWorkspace backed up under the given key,
+	// as recorded in the backup itself.
+	backedUpWorkspaceUID func(ctx context.Context, key string) (types.UID, error)
+	// restoreWorkspace creates the objects stored under the given key in the workspace, with the
+	// UIDs they have in the backup if preserveUIDs is true.
+	restoreWorkspace func(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, key string, preserveUIDs bool) (int64, error)
+
+	now func() time.Time
+}
+
+// backupKey returns the key of the backup in the store. The UID keeps the archives of
+// recreated WorkspaceBackups with the same name apart.
+func backupKey(backup *tenancyv1alpha1.WorkspaceBackup) string {
+	return fmt.Sprintf("%s/%s-%s.json.gz", logicalcluster.From(backup).String(), backup.Name, backup.UID)
+}
+
+// reconcileBackup advances the backup by at most one phase.
+func (r *reconciler) reconcileBackup(ctx context.Context, backup *tenancyv1alpha1.WorkspaceBackup) (time.Duration, error) {
+	logger := klog.FromContext(ctx)
+	clusterName := logicalcluster.From(backup)
+
+	if backup.Status.Phase == tenancyv1alpha1.WorkspaceBackupPhaseCompleted || backup.Status.Phase == tenancyv1alpha1.WorkspaceBackupPhaseFailed {
+		return 0, nil
+	}
+
+	workspace, err := r.getClusterWorkspace(clusterName, backup.Spec.Workspace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	}
+	if workspace == nil || workspace.Status.Phase != tenancyv1alpha1.ClusterWorkspacePhaseReady {
+		if backup.Status.Phase == tenancyv1alpha1.WorkspaceBackupPhaseRunning {
+			backup.Status.Phase = tenancyv1alpha1.WorkspaceBackupPhaseFailed
+			conditions.MarkFalse(backup, tenancyv1alpha1.WorkspaceBackupCompleted, tenancyv1alpha1.WorkspaceBackupReasonWorkspaceNotReady, conditionsv1alpha1.ConditionSeverityError, "ClusterWorkspace %s|%s is gone or not ready anymore.", clusterName, backup.Spec.Workspace)
+			return 0, nil
+		}
+		backup.Status.Phase = tenancyv1alpha1.WorkspaceBackupPhasePending
+		conditions.MarkFalse(backup, tenancyv1alpha1.WorkspaceBackupCompleted, tenancyv1alpha1.WorkspaceBackupReasonWorkspaceNotReady, conditionsv1alpha1.ConditionSeverityInfo, "ClusterWorkspace %s|%s does not exist or is not ready.", clusterName, backup.Spec.Workspace)
+		return 0, nil
+	}
+
+	switch backup.Status.Phase {
+	case "", tenancyv1alpha1.WorkspaceBackupPhasePending:
+		logger.Info("starting backup", "workspace", backup.Spec.Workspace)
+		backup.Status.Phase = tenancyv1alpha1.WorkspaceBackupPhaseRunning
+		conditions.MarkFalse(backup, tenancyv1alpha1.WorkspaceBackupCompleted, tenancyv1alpha1.WorkspaceBackupReasonInProgress, conditionsv1alpha1.ConditionSeverityInfo, "Backing up objects.")
+		return 0, nil
+
+	case tenancyv1alpha1.WorkspaceBackupPhaseRunning:
+		key := backupKey(backup)
+		count, err := r.backupWorkspace(ctx, workspace, key)
+		if err != nil {
+			conditions.MarkFalse(backup, tenancyv1alpha1.WorkspaceBackupCompleted, tenancyv1alpha1.WorkspaceBackupReasonFailed, conditionsv1alpha1.ConditionSeverityWarning, "Failed to back up objects: %v.", err)
+			return 0, err
+		}
+
+		logger.Info("backup completed", "location", key, "objects", count)
+		now := metav1.NewTime(r.now())
+		backup.Status.Phase = tenancyv1alpha1.WorkspaceBackupPhaseCompleted
+		backup.Status.Location = key
+		backup.Status.ObjectCount = count
+		backup.Status.WorkspaceUID = string(workspace.UID)
+		backup.Status.CompletionTime = &now
+		conditions.MarkTrue(backup, tenancyv1alpha1.WorkspaceBackupCompleted)
+	}
+
+	return 0, nil
+}
+
+// reconcileRestore advances the restore by at most one phase.
+func (r *reconciler) reconcileRestore(ctx context.Context, restore *tenancyv1alpha1.WorkspaceRestore) (time.Duration, error) {
+	logger := klog.FromContext(ctx)
+	clusterName := logicalcluster.From(restore)
+
+	if restore.Status.Phase == tenancyv1alpha1.WorkspaceBackupPhaseCompleted || restore.Status.Phase == tenancyv1alpha1.WorkspaceBackupPhaseFailed {
+		return 0, nil
+	}
+
+	backup, err := r.getBackup(clusterName, restore.Spec.Backup)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	}
+	switch {
+	case backup != nil && backup.Status.Phase == tenancyv1alpha1.WorkspaceBackupPhaseFailed:
+		restore.Status.Phase = tenancyv1alpha1.WorkspaceBackupPhaseFailed
+		conditions.MarkFalse(restore, tenancyv1alpha1.WorkspaceRestoreCompleted, tenancyv1alpha1.WorkspaceBackupReasonBackupNotCompleted, conditionsv1alpha1.ConditionSeverityError, "WorkspaceBackup %s|%s failed.", clusterName, restore.Spec.Backup)
+		return 0, nil
+	case backup == nil || backup.Status.Phase != tenancyv1alpha1.WorkspaceBackupPhaseCompleted:
+		if restore.Status.Phase == tenancyv1alpha1.WorkspaceBackupPhaseRunning {
+			restore.Status.Phase = tenancyv1alpha1.WorkspaceBackupPhaseFailed
+			conditions.MarkFalse(restore, tenancyv1alpha1.WorkspaceRestoreCompleted, tenancyv1alpha1.WorkspaceBackupReasonBackupNotCompleted, conditionsv1alpha1.ConditionSeverityError, "WorkspaceBackup %s|%s got deleted.", clusterName, restore.Spec.Backup)
+			return 0, nil
+		}
+		restore.Status.Phase = tenancyv1alpha1.WorkspaceBackupPhasePending
+		conditions.MarkFalse(restore, tenancyv1alpha1.WorkspaceRestoreCompleted, tenancyv1alpha1.WorkspaceBackupReasonBackupNotCompleted, conditionsv1alpha1.ConditionSeverityInfo, "WorkspaceBackup %s|%s does not exist or is not completed.", clusterName, restore.Spec.Backup)
+		return 0, nil
+	}
+
+	workspace, err := r.getClusterWorkspace(clusterName, restore.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	}
+	if workspace == nil || workspace.Status.Phase != tenancyv1alpha1.ClusterWorkspacePhaseReady {
+		if restore.Status.Phase == tenancyv1alpha1.WorkspaceBackupPhaseRunning {
+			restore.Status.Phase = tenancyv1alpha1.WorkspaceBackupPhaseFailed
+			conditions.MarkFalse(restore, tenancyv1alpha1.WorkspaceRestoreCompleted, tenancyv1alpha1.WorkspaceBackupReasonWorkspaceNotReady, conditionsv1alpha1.ConditionSeverityError, "ClusterWorkspace %s|%s is gone or not ready anymore.", clusterName, restore.Name)
+			return 0, nil
+		}
+		restore.Status.Phase = tenancyv1alpha1.WorkspaceBackupPhasePending
+		conditions.MarkFalse(restore, tenancyv1alpha1.WorkspaceRestoreCompleted, tenancyv1alpha1.WorkspaceBackupReasonWorkspaceNotReady, conditionsv1alpha1.ConditionSeverityInfo, "ClusterWorkspace %s|%s does not exist or is not ready.", clusterName, restore.Name)
+		return 0, nil
+	}
+
+	switch restore.Status.Phase {
+	case "", tenancyv1alpha1.WorkspaceBackupPhasePending:
+		preserveUIDs, err := r.canPreserveUIDs(ctx, clusterName, backup, restore)
+		if err != nil {
+			return 0, err
+		}
+		logger.Info("starting restore", "backup", restore.Spec.Backup, "preserveUIDs", preserveUIDs)
+		restore.Status.Phase = tenancyv1alpha1.WorkspaceBackupPhaseRunning
+		restore.Status.PreservedUIDs = preserveUIDs
+		conditions.MarkFalse(restore, tenancyv1alpha1.WorkspaceRestoreCompleted, tenancyv1alpha1.WorkspaceBackupReasonInProgress, conditionsv1alpha1.ConditionSeverityInfo, "Restoring objects.")
+		return 0, nil
+
+	case tenancyv1alpha1.WorkspaceBackupPhaseRunning:
+		// the key is derived again instead of taken from the status of the backup, which users can write
+		count, err := r.restoreWorkspace(ctx, workspace, backupKey(backup), restore.Status.PreservedUIDs)
+		if errors.Is(err, errAPIBindingsNotReady) {
+			conditions.MarkFalse(restore, tenancyv1alpha1.WorkspaceRestoreCompleted, tenancyv1alpha1.WorkspaceBackupReasonAPIBindingsNotReady, conditionsv1alpha1.ConditionSeverityInfo, "Waiting for restored APIBindings: %v.", err)
+			return apiBindingsNotReadyRequeue, nil
+		} else if err != nil {
+			conditions.MarkFalse(restore, tenancyv1alpha1.WorkspaceRestoreCompleted, tenancyv1alpha1.WorkspaceBackupReasonFailed, conditionsv1alpha1.ConditionSeverityWarning, "Failed to restore objects: %v.", err)
+			return 0, err
+		}
+
+		logger.Info("restore completed", "backup", restore.Spec.Backup, "objects", count)
+		now := metav1.NewTime(r.now())
+		restore.Status.Phase = tenancyv1alpha1.WorkspaceBackupPhaseCompleted
+		restore.Status.ObjectCount = count
+		restore.Status.CompletionTime = &now
+		conditions.MarkTrue(restore, tenancyv1alpha1.WorkspaceRestoreCompleted)
+	}
+
+	return 0, nil
+}
+
+// canPreserveUIDs returns whether the objects of the backup can be restored with the UIDs they have in
+// the backup. This is the case if the backed up ClusterWorkspace is gone, i.e. the original objects do
+// not exist anymore, and no other restore of the backup preserves the UIDs already. The UID of the backed
+// up ClusterWorkspace is read from the backup, not from the status of the WorkspaceBackup, which users
+// can write.
+func (r *reconciler) canPreserveUIDs(ctx context.Context, clusterName logicalcluster.Name, backup *tenancyv1alpha1.WorkspaceBackup, restore *tenancyv1alpha1.WorkspaceRestore) (bool, error) {
+	workspaceUID, err := r.backedUpWorkspaceUID(ctx, backupKey(backup))
+	if err != nil {
+		return false, err
+	}
+	if workspaceUID == "" {
+		return false, nil
+	}
+
+	source, err := r.getClusterWorkspace(clusterName, backup.Spec.Workspace)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	if source != nil && source.UID == workspaceUID {
+		return false, nil
+	}
+
+	restores, err := r.listRestores(clusterName)
+	if err != nil {
+		return false, err
+	}
+	for _, other := range restores {
+		if other.Name != restore.Name && other.Spec.Backup == backup.Name && other.Status.PreservedUIDs {
+			return false, nil
+		}
+	}
+	return true, nil
+}
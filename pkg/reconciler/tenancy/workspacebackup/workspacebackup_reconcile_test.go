@@ -0,0 +1,348 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacebackup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestReconcileBackup(t *testing.T) {
+	tests := map[string]struct {
+		phase     tenancyv1alpha1.WorkspaceBackupPhase
+		workspace *tenancyv1alpha1.ClusterWorkspace
+		backupErr error
+
+		wantPhase    tenancyv1alpha1.WorkspaceBackupPhase
+		wantErr      bool
+		wantBackedUp bool
+		wantLocation string
+	}{
+		"pending without workspace": {
+			wantPhase: tenancyv1alpha1.WorkspaceBackupPhasePending,
+		},
+		"pending with unready workspace": {
+			workspace: workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseInitializing),
+			wantPhase: tenancyv1alpha1.WorkspaceBackupPhasePending,
+		},
+		"pending with ready workspace": {
+			workspace: workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase: tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+		},
+		"running": {
+			phase:        tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			workspace:    workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase:    tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			wantBackedUp: true,
+			wantLocation: "root:org/backup-uid.json.gz",
+		},
+		"running fails": {
+			phase:        tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			workspace:    workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			backupErr:    errors.New("boom"),
+			wantPhase:    tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			wantErr:      true,
+			wantBackedUp: true,
+		},
+		"workspace deleted while running": {
+			phase:     tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			wantPhase: tenancyv1alpha1.WorkspaceBackupPhaseFailed,
+		},
+		"completed": {
+			phase:     tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			workspace: workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase: tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var backedUp bool
+			r := &reconciler{
+				getClusterWorkspace: getClusterWorkspace(tt.workspace),
+				backupWorkspace: func(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, key string) (int64, error) {
+					require.Equal(t, "ws", workspace.Name)
+					backedUp = true
+					return 42, tt.backupErr
+				},
+				now: time.Now,
+			}
+
+			backup := &tenancyv1alpha1.WorkspaceBackup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "backup",
+					UID:  "uid",
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey: "root:org",
+					},
+				},
+				Spec: tenancyv1alpha1.WorkspaceBackupSpec{
+					Workspace: "ws",
+				},
+				Status: tenancyv1alpha1.WorkspaceBackupStatus{
+					Phase: tt.phase,
+				},
+			}
+			_, err := r.reconcileBackup(context.Background(), backup)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantPhase, backup.Status.Phase)
+			require.Equal(t, tt.wantBackedUp, backedUp)
+			require.Equal(t, tt.wantLocation, backup.Status.Location)
+			if tt.wantLocation != "" {
+				require.Equal(t, int64(42), backup.Status.ObjectCount)
+				require.Equal(t, "ws-uid", backup.Status.WorkspaceUID)
+				require.NotNil(t, backup.Status.CompletionTime)
+			}
+		})
+	}
+}
+
+func TestReconcileRestore(t *testing.T) {
+	tests := map[string]struct {
+		phase              tenancyv1alpha1.WorkspaceBackupPhase
+		preservedUIDs      bool
+		backupPhase        tenancyv1alpha1.WorkspaceBackupPhase
+		backupWorkspaceUID string
+		// archivedWorkspaceUID is the UID of the backed up ClusterWorkspace in the archive
+		archivedWorkspaceUID types.UID
+		otherRestores        []*tenancyv1alpha1.WorkspaceRestore
+		workspace            *tenancyv1alpha1.ClusterWorkspace
+		restoreErr           error
+
+		wantPhase         tenancyv1alpha1.WorkspaceBackupPhase
+		wantPreservedUIDs bool
+		wantRequeue       time.Duration
+		wantErr           bool
+		wantRestored      bool
+	}{
+		"pending without backup": {
+			workspace: workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase: tenancyv1alpha1.WorkspaceBackupPhasePending,
+		},
+		"pending with running backup": {
+			backupPhase: tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			workspace:   workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase:   tenancyv1alpha1.WorkspaceBackupPhasePending,
+		},
+		"pending with failed backup": {
+			backupPhase: tenancyv1alpha1.WorkspaceBackupPhaseFailed,
+			workspace:   workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase:   tenancyv1alpha1.WorkspaceBackupPhaseFailed,
+		},
+		"pending without workspace": {
+			backupPhase: tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			wantPhase:   tenancyv1alpha1.WorkspaceBackupPhasePending,
+		},
+		"pending": {
+			backupPhase: tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			workspace:   workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase:   tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+		},
+		"pending with backed up workspace": {
+			backupPhase:          tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			backupWorkspaceUID:   "ws-uid",
+			archivedWorkspaceUID: "ws-uid",
+			workspace:            workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase:            tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+		},
+		"pending with backed up workspace and forged status": {
+			backupPhase:          tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			backupWorkspaceUID:   "deleted-uid",
+			archivedWorkspaceUID: "ws-uid",
+			workspace:            workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase:            tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+		},
+		"pending with deleted workspace": {
+			backupPhase:          tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			backupWorkspaceUID:   "deleted-uid",
+			archivedWorkspaceUID: "deleted-uid",
+			workspace:            workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase:            tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			wantPreservedUIDs:    true,
+		},
+		"pending with deleted workspace restored already": {
+			backupPhase:          tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			backupWorkspaceUID:   "deleted-uid",
+			archivedWorkspaceUID: "deleted-uid",
+			otherRestores: []*tenancyv1alpha1.WorkspaceRestore{{
+				ObjectMeta: metav1.ObjectMeta{Name: "other"},
+				Spec:       tenancyv1alpha1.WorkspaceRestoreSpec{Backup: "backup"},
+				Status:     tenancyv1alpha1.WorkspaceRestoreStatus{PreservedUIDs: true},
+			}},
+			workspace: workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase: tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+		},
+		"running": {
+			phase:        tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			backupPhase:  tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			workspace:    workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase:    tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			wantRestored: true,
+		},
+		"running with preserved UIDs": {
+			phase:             tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			preservedUIDs:     true,
+			backupPhase:       tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			workspace:         workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase:         tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			wantPreservedUIDs: true,
+			wantRestored:      true,
+		},
+		"running with unbound resources": {
+			phase:        tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			backupPhase:  tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			workspace:    workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			restoreErr:   fmt.Errorf("%w: widgets.example.com", errAPIBindingsNotReady),
+			wantPhase:    tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			wantRequeue:  apiBindingsNotReadyRequeue,
+			wantRestored: true,
+		},
+		"running fails": {
+			phase:        tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			backupPhase:  tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			workspace:    workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			restoreErr:   errors.New("boom"),
+			wantPhase:    tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			wantErr:      true,
+			wantRestored: true,
+		},
+		"backup deleted while running": {
+			phase:     tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			workspace: workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady),
+			wantPhase: tenancyv1alpha1.WorkspaceBackupPhaseFailed,
+		},
+		"workspace deleted while running": {
+			phase:       tenancyv1alpha1.WorkspaceBackupPhaseRunning,
+			backupPhase: tenancyv1alpha1.WorkspaceBackupPhaseCompleted,
+			wantPhase:   tenancyv1alpha1.WorkspaceBackupPhaseFailed,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var restored bool
+			r := &reconciler{
+				getClusterWorkspace: getClusterWorkspace(tt.workspace),
+				getBackup: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.WorkspaceBackup, error) {
+					if tt.backupPhase == "" || name != "backup" {
+						return nil, apierrors.NewNotFound(tenancyv1alpha1.SchemeGroupVersion.WithResource("workspacebackups").GroupResource(), name)
+					}
+					return &tenancyv1alpha1.WorkspaceBackup{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: name,
+							UID:  "uid",
+							Annotations: map[string]string{
+								logicalcluster.AnnotationKey: "root:org",
+							},
+						},
+						Spec: tenancyv1alpha1.WorkspaceBackupSpec{
+							Workspace: "ws",
+						},
+						Status: tenancyv1alpha1.WorkspaceBackupStatus{
+							Phase: tt.backupPhase,
+							// the status is writable by users, and must not be used to locate the backup
+							Location:     "root:victim/backup-uid.json.gz",
+							WorkspaceUID: tt.backupWorkspaceUID,
+						},
+					}, nil
+				},
+				listRestores: func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceRestore, error) {
+					return tt.otherRestores, nil
+				},
+				backedUpWorkspaceUID: func(ctx context.Context, key string) (types.UID, error) {
+					require.Equal(t, "root:org/backup-uid.json.gz", key)
+					return tt.archivedWorkspaceUID, nil
+				},
+				restoreWorkspace: func(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, key string, preserveUIDs bool) (int64, error) {
+					require.Equal(t, "ws", workspace.Name)
+					require.Equal(t, "root:org/backup-uid.json.gz", key)
+					require.Equal(t, tt.wantPreservedUIDs, preserveUIDs)
+					restored = true
+					return 42, tt.restoreErr
+				},
+				now: time.Now,
+			}
+
+			restore := &tenancyv1alpha1.WorkspaceRestore{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ws",
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey: "root:org",
+					},
+				},
+				Spec: tenancyv1alpha1.WorkspaceRestoreSpec{
+					Backup: "backup",
+				},
+				Status: tenancyv1alpha1.WorkspaceRestoreStatus{
+					Phase:         tt.phase,
+					PreservedUIDs: tt.preservedUIDs,
+				},
+			}
+			requeue, err := r.reconcileRestore(context.Background(), restore)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantPhase, restore.Status.Phase)
+			require.Equal(t, tt.wantPreservedUIDs, restore.Status.PreservedUIDs)
+			require.Equal(t, tt.wantRequeue, requeue)
+			require.Equal(t, tt.wantRestored, restored)
+		})
+	}
+}
+
+func getClusterWorkspace(ws *tenancyv1alpha1.ClusterWorkspace) func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+	return func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		if ws == nil || ws.Name != name {
+			return nil, apierrors.NewNotFound(tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspaces").GroupResource(), name)
+		}
+		return ws, nil
+	}
+}
+
+func workspace(name string, phase tenancyv1alpha1.ClusterWorkspacePhaseType) *tenancyv1alpha1.ClusterWorkspace {
+	return &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			UID:  types.UID(name + "-uid"),
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "root:org",
+			},
+		},
+		Status: tenancyv1alpha1.ClusterWorkspaceStatus{
+			Phase: phase,
+			Location: tenancyv1alpha1.ClusterWorkspaceLocation{
+				Current: "alpha",
+			},
+		},
+	}
+}
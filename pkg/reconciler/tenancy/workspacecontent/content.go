@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package workspacecontent holds the helpers shared by the controllers that copy the objects
+// of a logical cluster, i.e. workspace migration and workspace backup and restore.
+package workspacecontent
+
+import (
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Resource is a persisted resource of a logical cluster.
+type Resource struct {
+	GVR        schema.GroupVersionResource
+	Namespaced bool
+	HasStatus  bool
+}
+
+// derived are resources that are listable and creatable, but are derived from other objects.
+var derived = sets.NewString(
+	// projection of clusterworkspaces
+	"workspaces.tenancy.kcp.dev",
+)
+
+// dependenciesFirst are the resources that other objects depend on, in the order they are copied.
+var dependenciesFirst = []string{
+	"customresourcedefinitions.apiextensions.k8s.io",
+	"apibindings.apis.kcp.dev",
+	"namespaces",
+}
+
+// Resources returns the persisted resources of the given discovery information, with the
+// resources that other objects depend on first. Derived resources and the given excluded
+// group resources are skipped.
+func Resources(lists []*metav1.APIResourceList, excluded sets.String) ([]Resource, error) {
+	var resources []Resource
+	withStatus := sets.NewString()
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range list.APIResources {
+			if strings.HasSuffix(r.Name, "/status") {
+				withStatus.Insert(gv.WithResource(strings.TrimSuffix(r.Name, "/status")).GroupResource().String())
+			}
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			if verbs := sets.NewString(r.Verbs...); !verbs.HasAll("list", "create") {
+				continue
+			}
+			gr := gv.WithResource(r.Name).GroupResource().String()
+			if derived.Has(gr) || excluded.Has(gr) {
+				continue
+			}
+			resources = append(resources, Resource{GVR: gv.WithResource(r.Name), Namespaced: r.Namespaced})
+		}
+	}
+	for i := range resources {
+		resources[i].HasStatus = withStatus.Has(resources[i].GVR.GroupResource().String())
+	}
+
+	priority := func(r Resource) int {
+		for i, gr := range dependenciesFirst {
+			if r.GVR.GroupResource().String() == gr {
+				return i
+			}
+		}
+		return len(dependenciesFirst)
+	}
+	sort.SliceStable(resources, func(i, j int) bool {
+		return priority(resources[i]) < priority(resources[j])
+	})
+	return resources, nil
+}
+
+// PrepareForCreate returns a copy of the object without the fields set by the server it was read from.
+// Owner references are dropped, as the owners might not exist yet and might get new UIDs.
+func PrepareForCreate(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	obj.SetSelfLink("")
+	obj.SetGeneration(0)
+	obj.SetManagedFields(nil)
+	obj.SetOwnerReferences(nil)
+	obj.SetCreationTimestamp(metav1.Time{})
+	return obj
+}
+
+// EqualOwnerReferences returns whether both lists reference the same owners in the same order.
+func EqualOwnerReferences(a, b []metav1.OwnerReference) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].UID != b[i].UID || a[i].Name != b[i].Name || a[i].Kind != b[i].Kind || a[i].APIVersion != b[i].APIVersion {
+			return false
+		}
+	}
+	return true
+}
+
+// QualifiedName returns namespace/name of namespaced objects and the name of cluster-scoped objects.
+func QualifiedName(obj metav1.Object) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
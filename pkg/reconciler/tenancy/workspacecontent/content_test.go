@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacecontent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestResources(t *testing.T) {
+	verbs := metav1.Verbs{"create", "delete", "get", "list", "update", "watch"}
+	lists := []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Namespaced: true, Verbs: verbs},
+				{Name: "events", Namespaced: true, Verbs: verbs},
+				{Name: "namespaces", Verbs: verbs},
+				{Name: "namespaces/status", Verbs: metav1.Verbs{"get", "update"}},
+				{Name: "pods/log", Namespaced: true, Verbs: metav1.Verbs{"get"}},
+			},
+		},
+		{
+			GroupVersion: "tenancy.kcp.dev/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "workspaces", Verbs: verbs},
+			},
+		},
+		{
+			GroupVersion: "authorization.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "subjectaccessreviews", Verbs: metav1.Verbs{"create"}},
+			},
+		},
+		{
+			GroupVersion: "apis.kcp.dev/v1alpha1",
+			APIResources: []metav1.APIResource{
+				{Name: "apibindings", Verbs: verbs},
+				{Name: "apibindings/status", Verbs: metav1.Verbs{"get", "update"}},
+			},
+		},
+	}
+
+	resources, err := Resources(lists, sets.NewString("events"))
+	require.NoError(t, err)
+	require.Equal(t, []Resource{
+		{GVR: schema.GroupVersionResource{Group: "apis.kcp.dev", Version: "v1alpha1", Resource: "apibindings"}, HasStatus: true},
+		{GVR: schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}, HasStatus: true},
+		{GVR: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}, Namespaced: true},
+	}, resources)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacemigration
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacecontent"
+)
+
+// copyObjects copies the objects of the given resources from source to target. Objects that
+// already exist in target are not changed, hence a failed copy can be retried.
+//
+// Owner references are restored after all objects are copied, as the copies have new UIDs.
+func copyObjects(ctx context.Context, resources []workspacecontent.Resource, source, target dynamic.Interface) error {
+	logger := klog.FromContext(ctx)
+
+	type copied struct {
+		resource workspacecontent.Resource
+		source   *unstructured.Unstructured
+		target   *unstructured.Unstructured
+	}
+	var withOwners []copied
+	uids := map[types.UID]types.UID{}
+
+	for _, r := range resources {
+		list, err := source.Resource(r.GVR).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", r.GVR, err)
+		}
+		logger.V(4).Info("copying objects", "resource", r.GVR.String(), "count", len(list.Items))
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if obj.GetDeletionTimestamp() != nil {
+				continue
+			}
+			client := target.Resource(r.GVR).Namespace(obj.GetNamespace())
+
+			created, err := client.Create(ctx, workspacecontent.PrepareForCreate(obj), metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				created, err = client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+			} else if err == nil && r.HasStatus {
+				if status, found := obj.Object["status"]; found {
+					created.Object["status"] = status
+					created, err = client.UpdateStatus(ctx, created, metav1.UpdateOptions{})
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to copy %s %s: %w", r.GVR, workspacecontent.QualifiedName(obj), err)
+			}
+
+			uids[obj.GetUID()] = created.GetUID()
+			if len(obj.GetOwnerReferences()) > 0 {
+				withOwners = append(withOwners, copied{resource: r, source: obj, target: created})
+			}
+		}
+	}
+
+	for _, c := range withOwners {
+		var refs []metav1.OwnerReference
+		for _, ref := range c.source.GetOwnerReferences() {
+			uid, found := uids[ref.UID]
+			if !found {
+				continue // owner is gone
+			}
+			ref.UID = uid
+			refs = append(refs, ref)
+		}
+		if workspacecontent.EqualOwnerReferences(refs, c.target.GetOwnerReferences()) {
+			continue
+		}
+		c.target.SetOwnerReferences(refs)
+		if _, err := target.Resource(c.resource.GVR).Namespace(c.target.GetNamespace()).Update(ctx, c.target, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to restore owner references of %s %s: %w", c.resource.GVR, workspacecontent.QualifiedName(c.target), err)
+		}
+	}
+
+	return nil
+}
+
+// deleteObjects deletes the objects of the given resources from the source shard after the cut-over,
+// in the reverse order they are copied. Finalizers are removed first, such that the controllers of
+// the source shard do not act on the deletion, e.g. by deleting the content of child workspaces of
+// deleted ClusterWorkspaces. Objects that are gone are skipped, hence a failed deletion can be retried.
+func deleteObjects(ctx context.Context, resources []workspacecontent.Resource, client dynamic.Interface) error {
+	logger := klog.FromContext(ctx)
+
+	for i := len(resources) - 1; i >= 0; i-- {
+		r := resources[i]
+		list, err := client.Resource(r.GVR).List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			continue // the CRD is gone
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", r.GVR, err)
+		}
+		logger.V(4).Info("deleting objects", "resource", r.GVR.String(), "count", len(list.Items))
+
+		for j := range list.Items {
+			obj := &list.Items[j]
+			resourceClient := client.Resource(r.GVR).Namespace(obj.GetNamespace())
+
+			if len(obj.GetFinalizers()) > 0 {
+				patch := []byte(`{"metadata":{"finalizers":null}}`)
+				if _, err := resourceClient.Patch(ctx, obj.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}); err != nil && !apierrors.IsNotFound(err) {
+					return fmt.Errorf("failed to remove finalizers of %s %s: %w", r.GVR, workspacecontent.QualifiedName(obj), err)
+				}
+			}
+			if err := resourceClient.Delete(ctx, obj.GetName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete %s %s: %w", r.GVR, workspacecontent.QualifiedName(obj), err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacemigration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacecontent"
+)
+
+func TestDeleteObjects(t *testing.T) {
+	namespaces := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	clusterWorkspaces := schema.GroupVersionResource{Group: "tenancy.kcp.dev", Version: "v1alpha1", Resource: "clusterworkspaces"}
+
+	object := func(gvr schema.GroupVersionResource, kind, namespace, name string, finalizers ...string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(gvr.GroupVersion().String())
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetFinalizers(finalizers)
+		return obj
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		namespaces:        "NamespaceList",
+		configMaps:        "ConfigMapList",
+		clusterWorkspaces: "ClusterWorkspaceList",
+	},
+		object(namespaces, "Namespace", "", "default"),
+		object(configMaps, "ConfigMap", "default", "a"),
+		object(configMaps, "ConfigMap", "default", "b"),
+		object(clusterWorkspaces, "ClusterWorkspace", "", "child", "tenancy.kcp.dev/workspace-finalizer"),
+	)
+
+	resources := []workspacecontent.Resource{
+		{GVR: namespaces},
+		{GVR: configMaps, Namespaced: true},
+		{GVR: clusterWorkspaces},
+	}
+	require.NoError(t, deleteObjects(context.Background(), resources, client))
+
+	var deleted []string
+	for _, action := range client.Actions() {
+		switch action := action.(type) {
+		case clienttesting.PatchAction:
+			require.Equal(t, "child", action.GetName())
+			require.JSONEq(t, `{"metadata":{"finalizers":null}}`, string(action.GetPatch()), "finalizers must be removed before deletion")
+			require.Empty(t, deleted, "finalizers must be removed before deletion")
+		case clienttesting.DeleteAction:
+			deleted = append(deleted, action.GetResource().Resource+"/"+action.GetName())
+		}
+	}
+	require.Len(t, deleted, 4)
+	require.Equal(t, "clusterworkspaces/child", deleted[0], "objects must be deleted in reverse copy order")
+	require.ElementsMatch(t, []string{"configmaps/a", "configmaps/b"}, deleted[1:3])
+	require.Equal(t, "namespaces/default", deleted[3], "objects must be deleted in reverse copy order")
+
+	for _, gvr := range []schema.GroupVersionResource{namespaces, configMaps, clusterWorkspaces} {
+		list, err := client.Resource(gvr).List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Empty(t, list.Items)
+	}
+
+	// retrying after a partial deletion succeeds
+	require.NoError(t, deleteObjects(context.Background(), resources, client))
+}
@@ -0,0 +1,405 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacemigration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+	tenancyv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacecontent"
+)
+
+const (
+	ControllerName = "kcp-workspacemigration"
+
+	// fenceGracePeriod is the time between fencing a workspace and copying its objects.
+	fenceGracePeriod = 10 * time.Second
+)
+
+// ShardClientsFunc returns clients for the given logical cluster on the given shard.
+type ShardClientsFunc func(shard *tenancyv1alpha1.ClusterWorkspaceShard, clusterName logicalcluster.Name) (dynamic.Interface, discovery.DiscoveryInterface, error)
+
+// NewController returns a controller that moves workspaces between shards as requested by
+// WorkspaceMigrations, and creates WorkspaceMigrations for ClusterWorkspaces whose spec.shard.name
+// does not match the shard they are scheduled to.
+func NewController(
+	kcpClusterClient kcpclientset.ClusterInterface,
+	workspaceMigrationInformer tenancyinformers.WorkspaceMigrationClusterInformer,
+	clusterWorkspaceInformer tenancyinformers.ClusterWorkspaceClusterInformer,
+	clusterWorkspaceShardInformer tenancyinformers.ClusterWorkspaceShardClusterInformer,
+	shardName string,
+	shardClients ShardClientsFunc,
+) (*Controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+
+	c := &Controller{
+		queue:                    queue,
+		kcpClusterClient:         kcpClusterClient,
+		workspaceMigrationLister: workspaceMigrationInformer.Lister(),
+		clusterWorkspaceLister:   clusterWorkspaceInformer.Lister(),
+		shardClients:             shardClients,
+	}
+	c.reconciler = &reconciler{
+		shardName: shardName,
+		getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+			return c.clusterWorkspaceLister.Cluster(clusterName).Get(name)
+		},
+		getShard: func(name string) (*tenancyv1alpha1.ClusterWorkspaceShard, error) {
+			return clusterWorkspaceShardInformer.Lister().Cluster(tenancyv1alpha1.RootCluster).Get(name)
+		},
+		setFence:          c.setFence,
+		setLocationTarget: c.setLocationTarget,
+		copyWorkspace:     c.copyWorkspace,
+		deleteWorkspace:   c.deleteWorkspace,
+		fenceGracePeriod:  fenceGracePeriod,
+		now:               time.Now,
+	}
+
+	// migrations and the migrated ClusterWorkspaces share the same key
+	workspaceMigrationInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueue(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueue(obj) },
+		DeleteFunc: func(obj interface{}) { c.enqueue(obj) },
+	})
+	clusterWorkspaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueue(obj) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueue(obj) },
+	})
+
+	return c, nil
+}
+
+// Controller moves workspaces between shards.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	kcpClusterClient kcpclientset.ClusterInterface
+
+	workspaceMigrationLister tenancyv1alpha1listers.WorkspaceMigrationClusterLister
+	clusterWorkspaceLister   tenancyv1alpha1listers.ClusterWorkspaceClusterLister
+	shardClients             ShardClientsFunc
+
+	reconciler *reconciler
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(2).Info("queueing WorkspaceMigration")
+	c.queue.Add(key)
+}
+
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.Until(func() { c.startWorker(ctx) }, time.Second, ctx.Done())
+	}
+
+	<-ctx.Done()
+}
+
+func (c *Controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(1).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	clusterName, namespace, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		logger.Error(err, "invalid key")
+		return nil
+	}
+	if namespace != "" {
+		logger.Error(errors.New("namespace found in key for cluster-wide WorkspaceMigration object"), "invalid key")
+		return nil
+	}
+
+	obj, err := c.workspaceMigrationLister.Cluster(clusterName).Get(name)
+	if kerrors.IsNotFound(err) {
+		return c.processWorkspace(ctx, clusterName, name)
+	} else if err != nil {
+		return err
+	}
+
+	if restart, err := c.restartForShardConstraint(ctx, obj); err != nil || restart {
+		return err
+	}
+
+	previous := obj
+	obj = obj.DeepCopy()
+
+	logger = logging.WithObject(logger, obj)
+	ctx = klog.NewContext(ctx, logger)
+
+	requeueAfter, reconcileErr := c.reconciler.reconcile(ctx, obj)
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+
+	// If the object being reconciled changed as a result, update it.
+	var updateErr error
+	if !equality.Semantic.DeepEqual(previous.Status, obj.Status) {
+		updateErr = c.patchStatus(ctx, previous, obj)
+	}
+
+	logger.V(6).Info("processed WorkspaceMigration")
+	return utilerrors.NewAggregate([]error{reconcileErr, updateErr})
+}
+
+// processWorkspace lifts the fence of a workspace whose migration got deleted, and creates a
+// migration for a workspace whose spec.shard.name does not match its current shard.
+func (c *Controller) processWorkspace(ctx context.Context, clusterName logicalcluster.Name, name string) error {
+	logger := klog.FromContext(ctx)
+
+	workspace, err := c.clusterWorkspaceLister.Cluster(clusterName).Get(name)
+	if kerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if _, fenced := workspace.Annotations[tenancyv1alpha1.WorkspaceMigrationFenceAnnotationKey]; fenced {
+		logging.WithObject(logger, workspace).Info("lifting fence of workspace without WorkspaceMigration")
+		return c.setFence(ctx, workspace, "")
+	}
+
+	target, needed := migrationTarget(workspace)
+	if !needed {
+		return nil
+	}
+	migration := &tenancyv1alpha1.WorkspaceMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: workspace.Name,
+		},
+		Spec: tenancyv1alpha1.WorkspaceMigrationSpec{
+			TargetShard: target,
+		},
+	}
+	logging.WithObject(logger, workspace).Info("creating WorkspaceMigration for spec.shard.name", "target", target)
+	_, err = c.kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().WorkspaceMigrations().Create(ctx, migration, metav1.CreateOptions{})
+	if kerrors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// restartForShardConstraint deletes a finished migration if spec.shard.name of the workspace asks for
+// another shard. A new migration is created when the deletion is observed.
+func (c *Controller) restartForShardConstraint(ctx context.Context, migration *tenancyv1alpha1.WorkspaceMigration) (bool, error) {
+	if migration.Status.Phase != tenancyv1alpha1.WorkspaceMigrationPhaseCompleted && migration.Status.Phase != tenancyv1alpha1.WorkspaceMigrationPhaseFailed {
+		return false, nil
+	}
+	workspace, err := c.clusterWorkspaceLister.Cluster(logicalcluster.From(migration)).Get(migration.Name)
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if target, needed := migrationTarget(workspace); !needed || target == migration.Spec.TargetShard {
+		return false, nil
+	}
+
+	logging.WithObject(klog.FromContext(ctx), migration).Info("deleting finished WorkspaceMigration for new spec.shard.name")
+	err = c.kcpClusterClient.Cluster(logicalcluster.From(migration)).TenancyV1alpha1().WorkspaceMigrations().Delete(ctx, migration.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &migration.UID},
+	})
+	if kerrors.IsNotFound(err) {
+		return true, nil
+	}
+	return true, err
+}
+
+// migrationTarget returns the shard a ready workspace has to be moved to according to its spec.shard.name.
+func migrationTarget(workspace *tenancyv1alpha1.ClusterWorkspace) (string, bool) {
+	if workspace.Spec.Shard == nil || workspace.Spec.Shard.Name == "" {
+		return "", false
+	}
+	if workspace.Status.Phase != tenancyv1alpha1.ClusterWorkspacePhaseReady || workspace.Status.Location.Current == "" {
+		return "", false
+	}
+	if workspace.Status.Location.Current == workspace.Spec.Shard.Name {
+		return "", false
+	}
+	return workspace.Spec.Shard.Name, true
+}
+
+func (c *Controller) patchStatus(ctx context.Context, previous, obj *tenancyv1alpha1.WorkspaceMigration) error {
+	clusterName := logicalcluster.From(obj)
+
+	oldData, err := json.Marshal(tenancyv1alpha1.WorkspaceMigration{
+		Status: previous.Status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to Marshal old data for workspace migration %s|%s: %w", clusterName, obj.Name, err)
+	}
+
+	newData, err := json.Marshal(tenancyv1alpha1.WorkspaceMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:             previous.UID,
+			ResourceVersion: previous.ResourceVersion,
+		}, // to ensure they appear in the patch as preconditions
+		Status: obj.Status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to Marshal new data for workspace migration %s|%s: %w", clusterName, obj.Name, err)
+	}
+
+	patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+	if err != nil {
+		return fmt.Errorf("failed to create patch for workspace migration %s|%s: %w", clusterName, obj.Name, err)
+	}
+	_, err = c.kcpClusterClient.Cluster(clusterName).TenancyV1alpha1().WorkspaceMigrations().Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status")
+	return err
+}
+
+func (c *Controller) setFence(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, target string) error {
+	var value interface{}
+	if target != "" {
+		value = target
+	}
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": workspace.ResourceVersion,
+			"annotations": map[string]interface{}{
+				tenancyv1alpha1.WorkspaceMigrationFenceAnnotationKey: value,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.kcpClusterClient.Cluster(logicalcluster.From(workspace)).TenancyV1alpha1().ClusterWorkspaces().Patch(ctx, workspace.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
+
+func (c *Controller) setLocationTarget(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, target string) error {
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"resourceVersion": workspace.ResourceVersion,
+		},
+		"status": map[string]interface{}{
+			"location": map[string]interface{}{
+				"target": target,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.kcpClusterClient.Cluster(logicalcluster.From(workspace)).TenancyV1alpha1().ClusterWorkspaces().Patch(ctx, workspace.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, "status")
+	return err
+}
+
+func (c *Controller) copyWorkspace(ctx context.Context, clusterName logicalcluster.Name, source, target *tenancyv1alpha1.ClusterWorkspaceShard) error {
+	sourceClient, sourceDiscovery, err := c.shardClients(source, clusterName)
+	if err != nil {
+		return err
+	}
+	targetClient, _, err := c.shardClients(target, clusterName)
+	if err != nil {
+		return err
+	}
+
+	lists, err := sourceDiscovery.ServerPreferredResources()
+	if err != nil {
+		return fmt.Errorf("failed to discover resources of %s on shard %q: %w", clusterName, source.Name, err)
+	}
+	resources, err := workspacecontent.Resources(lists, nil)
+	if err != nil {
+		return err
+	}
+	return copyObjects(ctx, resources, sourceClient, targetClient)
+}
+
+func (c *Controller) deleteWorkspace(ctx context.Context, clusterName logicalcluster.Name, shard *tenancyv1alpha1.ClusterWorkspaceShard) error {
+	client, discovery, err := c.shardClients(shard, clusterName)
+	if err != nil {
+		return err
+	}
+
+	lists, err := discovery.ServerPreferredResources()
+	if err != nil {
+		return fmt.Errorf("failed to discover resources of %s on shard %q: %w", clusterName, shard.Name, err)
+	}
+	resources, err := workspacecontent.Resources(lists, nil)
+	if err != nil {
+		return err
+	}
+	return deleteObjects(ctx, resources, client)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacemigration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/conditions"
+)
+
+type reconciler struct {
+	// shardName is the shard the controller runs on. It has the ClusterWorkspaces of the workspaces it
+	// holds in its informers, hence its admission plugin can fence the children of these workspaces.
+	shardName string
+
+	getClusterWorkspace func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error)
+	getShard            func(name string) (*tenancyv1alpha1.ClusterWorkspaceShard, error)
+	// setFence sets the fence annotation of the workspace to the given target shard, or removes it if target is empty.
+	setFence          func(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, target string) error
+	setLocationTarget func(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, target string) error
+	copyWorkspace     func(ctx context.Context, clusterName logicalcluster.Name, source, target *tenancyv1alpha1.ClusterWorkspaceShard) error
+	deleteWorkspace   func(ctx context.Context, clusterName logicalcluster.Name, shard *tenancyv1alpha1.ClusterWorkspaceShard) error
+
+	// fenceGracePeriod is the time to wait after fencing before copying, for the fence to reach
+	// the informers of the admission plugin.
+	fenceGracePeriod time.Duration
+	now              func() time.Time
+}
+
+// reconcile advances the migration by at most one phase. It returns the duration after which
+// the migration must be reconciled again, or zero if an event will trigger the next phase.
+func (r *reconciler) reconcile(ctx context.Context, migration *tenancyv1alpha1.WorkspaceMigration) (time.Duration, error) {
+	logger := klog.FromContext(ctx)
+	clusterName := logicalcluster.From(migration)
+	target := migration.Spec.TargetShard
+
+	if migration.Status.Phase == tenancyv1alpha1.WorkspaceMigrationPhaseCompleted || migration.Status.Phase == tenancyv1alpha1.WorkspaceMigrationPhaseFailed {
+		return 0, nil
+	}
+
+	workspace, err := r.getClusterWorkspace(clusterName, migration.Name)
+	if apierrors.IsNotFound(err) {
+		if migration.Status.Phase == "" || migration.Status.Phase == tenancyv1alpha1.WorkspaceMigrationPhasePending {
+			migration.Status.Phase = tenancyv1alpha1.WorkspaceMigrationPhasePending
+			conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationCompleted, tenancyv1alpha1.WorkspaceMigrationReasonWorkspaceNotReady, conditionsv1alpha1.ConditionSeverityInfo, "ClusterWorkspace %s|%s does not exist.", clusterName, migration.Name)
+			return 0, nil
+		}
+		migration.Status.Phase = tenancyv1alpha1.WorkspaceMigrationPhaseFailed
+		conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationCompleted, tenancyv1alpha1.WorkspaceMigrationReasonWorkspaceNotReady, conditionsv1alpha1.ConditionSeverityError, "ClusterWorkspace %s|%s got deleted.", clusterName, migration.Name)
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	switch migration.Status.Phase {
+	case "", tenancyv1alpha1.WorkspaceMigrationPhasePending:
+		migration.Status.Phase = tenancyv1alpha1.WorkspaceMigrationPhasePending
+
+		if workspace.Status.Phase != tenancyv1alpha1.ClusterWorkspacePhaseReady {
+			conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationCompleted, tenancyv1alpha1.WorkspaceMigrationReasonWorkspaceNotReady, conditionsv1alpha1.ConditionSeverityInfo, "ClusterWorkspace %s|%s is not ready.", clusterName, migration.Name)
+			return 0, nil
+		}
+		if _, err := r.getShard(target); apierrors.IsNotFound(err) {
+			conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationCompleted, tenancyv1alpha1.WorkspaceMigrationReasonShardNotFound, conditionsv1alpha1.ConditionSeverityError, "ClusterWorkspaceShard %q does not exist.", target)
+			return time.Minute, nil
+		} else if err != nil {
+			return 0, err
+		}
+
+		if workspace.Status.Location.Current == target {
+			migration.Status.Phase = tenancyv1alpha1.WorkspaceMigrationPhaseCompleted
+			conditions.MarkTrue(migration, tenancyv1alpha1.WorkspaceMigrationCompleted)
+			return 0, nil
+		}
+
+		// the fence is enforced by the source shard, from the ClusterWorkspace in its informers.
+		if workspace.Status.Location.Current != r.shardName {
+			migration.Status.Phase = tenancyv1alpha1.WorkspaceMigrationPhaseFailed
+			conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationCompleted, tenancyv1alpha1.WorkspaceMigrationReasonFenceNotEnforceable, conditionsv1alpha1.ConditionSeverityError,
+				"ClusterWorkspace %s|%s is on shard %q, which cannot fence writes because the parent workspace is on shard %q.", clusterName, migration.Name, workspace.Status.Location.Current, r.shardName)
+			return 0, nil
+		}
+
+		logger.Info("starting migration", "source", workspace.Status.Location.Current, "target", target)
+		migration.Status.SourceShard = workspace.Status.Location.Current
+		migration.Status.Phase = tenancyv1alpha1.WorkspaceMigrationPhaseFencing
+		conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationCompleted, tenancyv1alpha1.WorkspaceMigrationReasonInProgress, conditionsv1alpha1.ConditionSeverityInfo, "Fencing writes to the workspace.")
+		return 0, nil
+
+	case tenancyv1alpha1.WorkspaceMigrationPhaseFencing:
+		if workspace.Annotations[tenancyv1alpha1.WorkspaceMigrationFenceAnnotationKey] != target {
+			if err := r.setFence(ctx, workspace, target); err != nil {
+				return 0, err
+			}
+		}
+
+		migration.Status.Phase = tenancyv1alpha1.WorkspaceMigrationPhaseCopying
+		conditions.MarkTrue(migration, tenancyv1alpha1.WorkspaceMigrationFenced)
+		conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationCompleted, tenancyv1alpha1.WorkspaceMigrationReasonInProgress, conditionsv1alpha1.ConditionSeverityInfo, "Copying objects to shard %q.", target)
+		return r.fenceGracePeriod, nil
+
+	case tenancyv1alpha1.WorkspaceMigrationPhaseCopying:
+		if fenced := conditions.Get(migration, tenancyv1alpha1.WorkspaceMigrationFenced); fenced != nil {
+			if wait := fenced.LastTransitionTime.Add(r.fenceGracePeriod).Sub(r.now()); wait > 0 {
+				return wait, nil
+			}
+		}
+
+		targetShard, err := r.getShard(target)
+		if apierrors.IsNotFound(err) {
+			return 0, r.fail(ctx, migration, workspace, tenancyv1alpha1.WorkspaceMigrationReasonShardNotFound, fmt.Sprintf("ClusterWorkspaceShard %q got deleted.", target))
+		} else if err != nil {
+			return 0, err
+		}
+		sourceShard, err := r.getShard(migration.Status.SourceShard)
+		if apierrors.IsNotFound(err) {
+			return 0, r.fail(ctx, migration, workspace, tenancyv1alpha1.WorkspaceMigrationReasonShardNotFound, fmt.Sprintf("ClusterWorkspaceShard %q got deleted.", migration.Status.SourceShard))
+		} else if err != nil {
+			return 0, err
+		}
+
+		if err := r.copyWorkspace(ctx, clusterName.Join(migration.Name), sourceShard, targetShard); err != nil {
+			conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationCompleted, tenancyv1alpha1.WorkspaceMigrationReasonCopyFailed, conditionsv1alpha1.ConditionSeverityWarning, "Failed to copy objects to shard %q: %v.", target, err)
+			return 0, err
+		}
+
+		logger.Info("copied workspace, cutting over", "target", target)
+		migration.Status.Phase = tenancyv1alpha1.WorkspaceMigrationPhaseCuttingOver
+		conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationCompleted, tenancyv1alpha1.WorkspaceMigrationReasonInProgress, conditionsv1alpha1.ConditionSeverityInfo, "Switching workspace to shard %q.", target)
+		return 0, nil
+
+	case tenancyv1alpha1.WorkspaceMigrationPhaseCuttingOver:
+		if workspace.Status.Location.Current != target {
+			if workspace.Status.Location.Target != target {
+				if err := r.setLocationTarget(ctx, workspace, target); err != nil {
+					return 0, err
+				}
+			}
+			return 0, nil // the workspace update triggers the next reconciliation
+		}
+
+		sourceShard, err := r.getShard(migration.Status.SourceShard)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return 0, err
+		}
+		if err == nil {
+			if err := r.deleteWorkspace(ctx, clusterName.Join(migration.Name), sourceShard); err != nil {
+				conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationCompleted, tenancyv1alpha1.WorkspaceMigrationReasonCleanupFailed, conditionsv1alpha1.ConditionSeverityWarning, "Failed to delete objects from shard %q: %v.", sourceShard.Name, err)
+				return 0, err
+			}
+		}
+
+		if err := r.setFence(ctx, workspace, ""); err != nil {
+			return 0, err
+		}
+		logger.Info("migration completed", "target", target)
+		migration.Status.Phase = tenancyv1alpha1.WorkspaceMigrationPhaseCompleted
+		conditions.MarkTrue(migration, tenancyv1alpha1.WorkspaceMigrationCompleted)
+		conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationFenced, tenancyv1alpha1.WorkspaceMigrationReasonFenceLifted, conditionsv1alpha1.ConditionSeverityInfo, "Workspace is served by shard %q.", target)
+	}
+
+	return 0, nil
+}
+
+// fail marks the migration as failed and lifts the fence. The workspace stays on the source shard.
+func (r *reconciler) fail(ctx context.Context, migration *tenancyv1alpha1.WorkspaceMigration, workspace *tenancyv1alpha1.ClusterWorkspace, reason, message string) error {
+	if _, fenced := workspace.Annotations[tenancyv1alpha1.WorkspaceMigrationFenceAnnotationKey]; fenced {
+		if err := r.setFence(ctx, workspace, ""); err != nil {
+			return err
+		}
+	}
+	migration.Status.Phase = tenancyv1alpha1.WorkspaceMigrationPhaseFailed
+	conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationCompleted, reason, conditionsv1alpha1.ConditionSeverityError, "%s", message)
+	conditions.MarkFalse(migration, tenancyv1alpha1.WorkspaceMigrationFenced, tenancyv1alpha1.WorkspaceMigrationReasonFenceLifted, conditionsv1alpha1.ConditionSeverityInfo, "Fence lifted, the workspace stays on shard %q.", migration.Status.SourceShard)
+	return nil
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacemigration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Now()
+	fencedAt := func(t time.Time) conditionsv1alpha1.Conditions {
+		return conditionsv1alpha1.Conditions{{
+			Type:               tenancyv1alpha1.WorkspaceMigrationFenced,
+			Status:             "True",
+			LastTransitionTime: metav1.NewTime(t),
+		}}
+	}
+
+	tests := map[string]struct {
+		phase      tenancyv1alpha1.WorkspaceMigrationPhase
+		conditions conditionsv1alpha1.Conditions
+		workspace  *tenancyv1alpha1.ClusterWorkspace
+		copyErr    error
+		deleteErr  error
+
+		wantPhase          tenancyv1alpha1.WorkspaceMigrationPhase
+		wantRequeue        time.Duration
+		wantErr            bool
+		wantFence          *string
+		wantLocationTarget string
+		wantCopied         bool
+		wantDeleted        bool
+	}{
+		"pending without workspace": {
+			wantPhase: tenancyv1alpha1.WorkspaceMigrationPhasePending,
+		},
+		"pending with unready workspace": {
+			workspace: workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseInitializing, "alpha", ""),
+			wantPhase: tenancyv1alpha1.WorkspaceMigrationPhasePending,
+		},
+		"pending with ready workspace": {
+			workspace: workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady, "alpha", ""),
+			wantPhase: tenancyv1alpha1.WorkspaceMigrationPhaseFencing,
+		},
+		"pending with workspace already on target": {
+			workspace: workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady, "beta", ""),
+			wantPhase: tenancyv1alpha1.WorkspaceMigrationPhaseCompleted,
+		},
+		"pending with workspace on another shard than its parent": {
+			workspace: workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady, "gamma", ""),
+			wantPhase: tenancyv1alpha1.WorkspaceMigrationPhaseFailed,
+		},
+		"fencing": {
+			phase:       tenancyv1alpha1.WorkspaceMigrationPhaseFencing,
+			workspace:   workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady, "alpha", ""),
+			wantPhase:   tenancyv1alpha1.WorkspaceMigrationPhaseCopying,
+			wantRequeue: 10 * time.Second,
+			wantFence:   stringPtr("beta"),
+		},
+		"copying within grace period": {
+			phase:       tenancyv1alpha1.WorkspaceMigrationPhaseCopying,
+			conditions:  fencedAt(now.Add(-4 * time.Second)),
+			workspace:   workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady, "alpha", "beta"),
+			wantPhase:   tenancyv1alpha1.WorkspaceMigrationPhaseCopying,
+			wantRequeue: 6 * time.Second,
+		},
+		"copying": {
+			phase:      tenancyv1alpha1.WorkspaceMigrationPhaseCopying,
+			conditions: fencedAt(now.Add(-time.Minute)),
+			workspace:  workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady, "alpha", "beta"),
+			wantPhase:  tenancyv1alpha1.WorkspaceMigrationPhaseCuttingOver,
+			wantCopied: true,
+		},
+		"copying fails": {
+			phase:      tenancyv1alpha1.WorkspaceMigrationPhaseCopying,
+			conditions: fencedAt(now.Add(-time.Minute)),
+			workspace:  workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady, "alpha", "beta"),
+			copyErr:    errors.New("boom"),
+			wantPhase:  tenancyv1alpha1.WorkspaceMigrationPhaseCopying,
+			wantErr:    true,
+			wantCopied: true,
+		},
+		"workspace deleted while copying": {
+			phase:     tenancyv1alpha1.WorkspaceMigrationPhaseCopying,
+			wantPhase: tenancyv1alpha1.WorkspaceMigrationPhaseFailed,
+		},
+		"cutting over": {
+			phase:              tenancyv1alpha1.WorkspaceMigrationPhaseCuttingOver,
+			workspace:          workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady, "alpha", "beta"),
+			wantPhase:          tenancyv1alpha1.WorkspaceMigrationPhaseCuttingOver,
+			wantLocationTarget: "beta",
+		},
+		"cut over": {
+			phase:       tenancyv1alpha1.WorkspaceMigrationPhaseCuttingOver,
+			workspace:   workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady, "beta", "beta"),
+			wantPhase:   tenancyv1alpha1.WorkspaceMigrationPhaseCompleted,
+			wantFence:   stringPtr(""),
+			wantDeleted: true,
+		},
+		"deleting from source fails": {
+			phase:       tenancyv1alpha1.WorkspaceMigrationPhaseCuttingOver,
+			workspace:   workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady, "beta", "beta"),
+			deleteErr:   errors.New("boom"),
+			wantPhase:   tenancyv1alpha1.WorkspaceMigrationPhaseCuttingOver,
+			wantErr:     true,
+			wantDeleted: true,
+		},
+		"completed": {
+			phase:     tenancyv1alpha1.WorkspaceMigrationPhaseCompleted,
+			workspace: workspace("ws", tenancyv1alpha1.ClusterWorkspacePhaseReady, "alpha", ""),
+			wantPhase: tenancyv1alpha1.WorkspaceMigrationPhaseCompleted,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var fence *string
+			var locationTarget string
+			var copied, deleted bool
+			r := &reconciler{
+				shardName: "alpha",
+				getClusterWorkspace: func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+					if tt.workspace == nil || tt.workspace.Name != name {
+						return nil, apierrors.NewNotFound(tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspaces").GroupResource(), name)
+					}
+					return tt.workspace, nil
+				},
+				getShard: func(name string) (*tenancyv1alpha1.ClusterWorkspaceShard, error) {
+					if name != "alpha" && name != "beta" {
+						return nil, apierrors.NewNotFound(tenancyv1alpha1.SchemeGroupVersion.WithResource("clusterworkspaceshards").GroupResource(), name)
+					}
+					return &tenancyv1alpha1.ClusterWorkspaceShard{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+				},
+				setFence: func(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, target string) error {
+					fence = &target
+					return nil
+				},
+				setLocationTarget: func(ctx context.Context, workspace *tenancyv1alpha1.ClusterWorkspace, target string) error {
+					locationTarget = target
+					return nil
+				},
+				copyWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, source, target *tenancyv1alpha1.ClusterWorkspaceShard) error {
+					require.Equal(t, logicalcluster.New("root:org:ws"), clusterName)
+					require.Equal(t, "alpha", source.Name)
+					require.Equal(t, "beta", target.Name)
+					copied = true
+					return tt.copyErr
+				},
+				deleteWorkspace: func(ctx context.Context, clusterName logicalcluster.Name, shard *tenancyv1alpha1.ClusterWorkspaceShard) error {
+					require.Equal(t, logicalcluster.New("root:org:ws"), clusterName)
+					require.Equal(t, "alpha", shard.Name, "objects must be deleted from the source shard")
+					deleted = true
+					return tt.deleteErr
+				},
+				fenceGracePeriod: 10 * time.Second,
+				now:              func() time.Time { return now },
+			}
+
+			migration := &tenancyv1alpha1.WorkspaceMigration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ws",
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey: "root:org",
+					},
+				},
+				Spec: tenancyv1alpha1.WorkspaceMigrationSpec{
+					TargetShard: "beta",
+				},
+				Status: tenancyv1alpha1.WorkspaceMigrationStatus{
+					Phase:       tt.phase,
+					Conditions:  tt.conditions,
+					SourceShard: "alpha",
+				},
+			}
+			requeue, err := r.reconcile(context.Background(), migration)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantPhase, migration.Status.Phase)
+			require.Equal(t, tt.wantRequeue, requeue)
+			require.Equal(t, tt.wantFence, fence)
+			require.Equal(t, tt.wantLocationTarget, locationTarget)
+			require.Equal(t, tt.wantCopied, copied)
+			require.Equal(t, tt.wantDeleted, deleted)
+		})
+	}
+}
+
+func workspace(name string, phase tenancyv1alpha1.ClusterWorkspacePhaseType, current, target string) *tenancyv1alpha1.ClusterWorkspace {
+	return &tenancyv1alpha1.ClusterWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "root:org",
+			},
+		},
+		Status: tenancyv1alpha1.ClusterWorkspaceStatus{
+			Phase: phase,
+			Location: tenancyv1alpha1.ClusterWorkspaceLocation{
+				Current: current,
+				Target:  target,
+			},
+		},
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -46,6 +46,7 @@ import (
 	"github.com/kcp-dev/kcp/pkg/client"
 	schedulingv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/scheduling/v1alpha1"
 	schedulingv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/scheduling/v1alpha1"
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexport"
 )
@@ -221,6 +222,10 @@ func (c *controller) process(ctx context.Context, key string) error {
 		return nil
 	}
 
+	if !kcpfeatures.DefaultWorkspaceFeatureGate.Enabled(kcpfeatures.LocationAPI, clusterName) {
+		return nil // the scheduling APIs are disabled for this workspace
+	}
+
 	obj, err := c.namespaceLister.Cluster(clusterName).Get(name)
 	if err != nil {
 		if errors.IsNotFound(err) {
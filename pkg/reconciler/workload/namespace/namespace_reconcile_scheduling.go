@@ -73,7 +73,9 @@ func (r *placementSchedulingReconciler) reconcile(ctx context.Context, ns *corev
 		if !foundScheduled {
 			continue
 		}
-		scheduledSyncTargets.Insert(currentScheduled)
+		// placements in All mode schedule to multiple synctargets, and a synctarget selected
+		// by multiple placements gets a single state label.
+		scheduledSyncTargets.Insert(workloadv1alpha1.SyncTargetKeysFromPlacementAnnotation(currentScheduled)...)
 	}
 
 	// 2. find the scheduled synctarget to the ns, including synced, removing
@@ -19,6 +19,7 @@ package namespace
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -135,6 +136,24 @@ func TestScheduling(t *testing.T) {
 				workloadv1alpha1.ClusterResourceStateLabelPrefix + "aQA9mRmZ5RuT9vKRZokxZTm1Yk9SqKyfOMoTEr": string(workloadv1alpha1.ResourceStateSync),
 			},
 		},
+		{
+			name: "schedule to all synctargets of a placement",
+			annotations: map[string]string{
+				schedulingv1alpha1.PlacementAnnotationKey: "",
+			},
+			labels: map[string]string{
+				workloadv1alpha1.ClusterResourceStateLabelPrefix + "34sZi3721YwBLDHUuNVIOLxuYp5nEZBpsTQyDq": string(workloadv1alpha1.ResourceStateSync),
+			},
+			placement: newPlacement("test-placement", "test-location", "test-cluster", "test-cluster-2"),
+			wantPatch: true,
+			expectedAnnotations: map[string]string{
+				schedulingv1alpha1.PlacementAnnotationKey: "",
+			},
+			expectedLabels: map[string]string{
+				workloadv1alpha1.ClusterResourceStateLabelPrefix + "34sZi3721YwBLDHUuNVIOLxuYp5nEZBpsTQyDq": string(workloadv1alpha1.ResourceStateSync),
+				workloadv1alpha1.ClusterResourceStateLabelPrefix + "aQA9mRmZ5RuT9vKRZokxZTm1Yk9SqKyfOMoTEr": string(workloadv1alpha1.ResourceStateSync),
+			},
+		},
 		{
 			name: "scheduled cluster is removing",
 			annotations: map[string]string{
@@ -339,7 +358,7 @@ func TestMultiplePlacements(t *testing.T) {
 	}
 }
 
-func newPlacement(name, location, synctarget string) *schedulingv1alpha1.Placement {
+func newPlacement(name, location string, synctargets ...string) *schedulingv1alpha1.Placement {
 	placement := &schedulingv1alpha1.Placement{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
@@ -354,9 +373,15 @@ func newPlacement(name, location, synctarget string) *schedulingv1alpha1.Placeme
 		},
 	}
 
-	if len(synctarget) > 0 {
+	var keys []string
+	for _, synctarget := range synctargets {
+		if len(synctarget) > 0 {
+			keys = append(keys, workloadv1alpha1.ToSyncTargetKey(logicalcluster.New(""), synctarget))
+		}
+	}
+	if len(keys) > 0 {
 		placement.Annotations = map[string]string{
-			workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey: workloadv1alpha1.ToSyncTargetKey(logicalcluster.New(""), synctarget),
+			workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey: strings.Join(keys, ","),
 		}
 	}
 
@@ -39,6 +39,7 @@ import (
 	workloadv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/workload/v1alpha1"
 	schedulingv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/scheduling/v1alpha1"
 	workloadv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/workload/v1alpha1"
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	"github.com/kcp-dev/kcp/pkg/logging"
 )
 
@@ -101,6 +102,7 @@ func NewController(
 				oldClusterCopy.Status.LastSyncerHeartbeatTime = nil
 				oldClusterCopy.Status.VirtualWorkspaces = nil
 				oldClusterCopy.Status.Capacity = nil
+				oldClusterCopy.Status.Allocatable = nil
 
 				newCluster := obj.(*workloadv1alpha1.SyncTarget)
 				newClusterCopy := *newCluster
@@ -108,6 +110,7 @@ func NewController(
 				newClusterCopy.Status.LastSyncerHeartbeatTime = nil
 				newClusterCopy.Status.VirtualWorkspaces = nil
 				newClusterCopy.Status.Capacity = nil
+				newClusterCopy.Status.Allocatable = nil
 
 				// compare ignoring heart-beat
 				if !reflect.DeepEqual(oldClusterCopy, newClusterCopy) {
@@ -255,6 +258,10 @@ func (c *controller) process(ctx context.Context, key string) error {
 		runtime.HandleError(err)
 		return nil
 	}
+
+	if !kcpfeatures.DefaultWorkspaceFeatureGate.Enabled(kcpfeatures.LocationAPI, clusterName) {
+		return nil // the scheduling APIs are disabled for this workspace
+	}
 	obj, err := c.placementLister.Cluster(clusterName).Get(name)
 	if err != nil {
 		if errors.IsNotFound(err) {
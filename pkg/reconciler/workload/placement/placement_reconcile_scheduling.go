@@ -20,9 +20,12 @@ import (
 	"context"
 	"encoding/json"
 	"math/rand"
+	"sort"
+	"strings"
 
 	"github.com/kcp-dev/logicalcluster/v2"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -56,6 +59,11 @@ func (r *placementSchedulingReconciler) reconcile(ctx context.Context, placement
 		return reconcileStatusStop, placement, err
 	}
 
+	// in All mode, schedule to every valid synctarget, like a DaemonSet
+	if placement.Spec.Mode == schedulingv1alpha1.PlacementModeAll {
+		return r.reconcileAll(ctx, clusterName, placement, syncTargetClusterName, syncTargets)
+	}
+
 	// no valid synctarget, clean the annotation.
 	if foundScheduled && len(syncTargets) == 0 {
 		expectedAnnotations[workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey] = nil
@@ -74,12 +82,12 @@ func (r *placementSchedulingReconciler) reconcile(ctx context.Context, placement
 		}
 	}
 
-	// 3. randomly select one as the scheduled cluster
+	// 3. randomly select one as the scheduled cluster, weighted by the allocatable resources of the sync targets
 	// TODO(qiujian16): we currently schedule each in each location independently. It cannot guarantee 1 cluster is scheduled per location
 	// when the same synctargets are in multiple locations, we need to rethink whether we need a better algorithm or we need location
 	// to be exclusive.
 	if len(syncTargets) > 0 {
-		scheduledSyncTarget := syncTargets[rand.Intn(len(syncTargets))]
+		scheduledSyncTarget := pickSyncTarget(syncTargets, rand.Int63n)
 		expectedAnnotations[workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey] = workloadv1alpha1.ToSyncTargetKey(syncTargetClusterName, scheduledSyncTarget.Name)
 		updated, err := r.patchPlacementAnnotation(ctx, clusterName, placement, expectedAnnotations)
 		return reconcileStatusContinue, updated, err
@@ -88,6 +96,59 @@ func (r *placementSchedulingReconciler) reconcile(ctx context.Context, placement
 	return reconcileStatusContinue, placement, nil
 }
 
+// reconcileAll stores the keys of all the given SyncTargets in the internal.workload.kcp.dev/synctarget
+// annotation, such that SyncTargets joining or leaving the location are scheduled or unscheduled.
+func (r *placementSchedulingReconciler) reconcileAll(ctx context.Context, clusterName logicalcluster.Name, placement *schedulingv1alpha1.Placement, syncTargetClusterName logicalcluster.Name, syncTargets []*workloadv1alpha1.SyncTarget) (reconcileStatus, *schedulingv1alpha1.Placement, error) {
+	keys := make([]string, 0, len(syncTargets))
+	for _, syncTarget := range syncTargets {
+		keys = append(keys, workloadv1alpha1.ToSyncTargetKey(syncTargetClusterName, syncTarget.Name))
+	}
+	sort.Strings(keys)
+	expectedScheduled := strings.Join(keys, ",")
+
+	currentScheduled, foundScheduled := placement.Annotations[workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey]
+	if (len(keys) == 0 && !foundScheduled) || (len(keys) > 0 && expectedScheduled == currentScheduled) {
+		return reconcileStatusContinue, placement, nil
+	}
+
+	expectedAnnotations := map[string]interface{}{} // nil means to remove the key
+	if len(keys) == 0 {
+		expectedAnnotations[workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey] = nil
+	} else {
+		expectedAnnotations[workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey] = expectedScheduled
+	}
+	updated, err := r.patchPlacementAnnotation(ctx, clusterName, placement, expectedAnnotations)
+	return reconcileStatusContinue, updated, err
+}
+
+// pickSyncTarget selects a SyncTarget with a probability proportional to the allocatable CPU it reports, such
+// that bigger physical clusters get more placements. If not every SyncTarget reports allocatable CPU, e.g.
+// with older syncers, a SyncTarget is picked uniformly.
+func pickSyncTarget(syncTargets []*workloadv1alpha1.SyncTarget, int63n func(int64) int64) *workloadv1alpha1.SyncTarget {
+	weights := make([]int64, len(syncTargets))
+	var total int64
+	for i, syncTarget := range syncTargets {
+		if syncTarget.Status.Allocatable == nil {
+			return syncTargets[int63n(int64(len(syncTargets)))]
+		}
+		cpu, found := (*syncTarget.Status.Allocatable)[corev1.ResourceCPU]
+		if !found || cpu.MilliValue() <= 0 {
+			return syncTargets[int63n(int64(len(syncTargets)))]
+		}
+		weights[i] = cpu.MilliValue()
+		total += weights[i]
+	}
+
+	n := int63n(total)
+	for i, weight := range weights {
+		if n < weight {
+			return syncTargets[i]
+		}
+		n -= weight
+	}
+	return syncTargets[len(syncTargets)-1]
+}
+
 func (r *placementSchedulingReconciler) getAllValidSyncTargetsForPlacement(clusterName logicalcluster.Name, placement *schedulingv1alpha1.Placement) (logicalcluster.Name, []*workloadv1alpha1.SyncTarget, error) {
 	if placement.Status.Phase == schedulingv1alpha1.PlacementPending || placement.Status.SelectedLocation == nil {
 		return logicalcluster.Name{}, nil, nil
@@ -19,21 +19,24 @@ package placement
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/kcp-dev/logicalcluster/v2"
 	"github.com/stretchr/testify/require"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/scheduling/v1alpha1"
 	conditionsapi "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
 	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 )
 
 func TestSchedulingReconcile(t *testing.T) {
@@ -93,6 +96,43 @@ func TestSchedulingReconcile(t *testing.T) {
 				workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey: "aPkhvUbGK0xoZIjMnM2pA0AuV1g7i4tBwxu5m4",
 			},
 		},
+		{
+			name:        "schedule all synctargets",
+			placement:   inAllMode(newPlacement("test", "test-location", "")),
+			location:    newLocation("test-location"),
+			syncTargets: []*workloadv1alpha1.SyncTarget{newSyncTarget("c1", true), newSyncTarget("c2", true), newSyncTarget("c3", false)},
+			wantPatch:   true,
+			expectedAnnotations: map[string]string{
+				workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey: "aPkhvUbGK0xoZIjMnM2pA0AuV1g7i4tBwxu5m4,aQtdeEWVcqU7h7AKnYMm3KRQ96U4oU2W04yeOa",
+			},
+		},
+		{
+			name:        "all synctargets scheduled",
+			placement:   inAllMode(newPlacement("test", "test-location", "c2", "c1")),
+			location:    newLocation("test-location"),
+			syncTargets: []*workloadv1alpha1.SyncTarget{newSyncTarget("c1", true), newSyncTarget("c2", true)},
+			expectedAnnotations: map[string]string{
+				workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey: "aPkhvUbGK0xoZIjMnM2pA0AuV1g7i4tBwxu5m4,aQtdeEWVcqU7h7AKnYMm3KRQ96U4oU2W04yeOa",
+			},
+		},
+		{
+			name:        "synctarget joining in all mode",
+			placement:   inAllMode(newPlacement("test", "test-location", "c1")),
+			location:    newLocation("test-location"),
+			syncTargets: []*workloadv1alpha1.SyncTarget{newSyncTarget("c1", true), newSyncTarget("c2", true)},
+			wantPatch:   true,
+			expectedAnnotations: map[string]string{
+				workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey: "aPkhvUbGK0xoZIjMnM2pA0AuV1g7i4tBwxu5m4,aQtdeEWVcqU7h7AKnYMm3KRQ96U4oU2W04yeOa",
+			},
+		},
+		{
+			name:                "unschedule all synctargets",
+			placement:           inAllMode(newPlacement("test", "test-location", "c2", "c1")),
+			location:            newLocation("test-location"),
+			syncTargets:         []*workloadv1alpha1.SyncTarget{newSyncTarget("c1", false), newSyncTarget("c2", false)},
+			wantPatch:           true,
+			expectedAnnotations: map[string]string{},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -136,7 +176,7 @@ func TestSchedulingReconcile(t *testing.T) {
 	}
 }
 
-func newPlacement(name, location, synctarget string) *schedulingv1alpha1.Placement {
+func newPlacement(name, location string, synctargets ...string) *schedulingv1alpha1.Placement {
 	placement := &schedulingv1alpha1.Placement{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
@@ -151,15 +191,26 @@ func newPlacement(name, location, synctarget string) *schedulingv1alpha1.Placeme
 		},
 	}
 
-	if len(synctarget) > 0 {
+	var keys []string
+	for _, synctarget := range synctargets {
+		if len(synctarget) > 0 {
+			keys = append(keys, workloadv1alpha1.ToSyncTargetKey(logicalcluster.New(""), synctarget))
+		}
+	}
+	if len(keys) > 0 {
 		placement.Annotations = map[string]string{
-			workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey: workloadv1alpha1.ToSyncTargetKey(logicalcluster.New(""), synctarget),
+			workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey: strings.Join(keys, ","),
 		}
 	}
 
 	return placement
 }
 
+func inAllMode(placement *schedulingv1alpha1.Placement) *schedulingv1alpha1.Placement {
+	placement.Spec.Mode = schedulingv1alpha1.PlacementModeAll
+	return placement
+}
+
 func newLocation(name string) *schedulingv1alpha1.Location {
 	return &schedulingv1alpha1.Location{
 		ObjectMeta: metav1.ObjectMeta{
@@ -184,3 +235,48 @@ func newSyncTarget(name string, ready bool) *workloadv1alpha1.SyncTarget {
 
 	return syncTarget
 }
+
+func TestPickSyncTarget(t *testing.T) {
+	withCPU := func(name, cpu string) *workloadv1alpha1.SyncTarget {
+		syncTarget := &workloadv1alpha1.SyncTarget{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if cpu != "" {
+			syncTarget.Status.Allocatable = &corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}
+		}
+		return syncTarget
+	}
+
+	tests := map[string]struct {
+		syncTargets []*workloadv1alpha1.SyncTarget
+		random      int64
+		wantMax     int64
+		want        string
+	}{
+		"weighted, first": {
+			syncTargets: []*workloadv1alpha1.SyncTarget{withCPU("small", "1"), withCPU("big", "3")},
+			random:      999,
+			wantMax:     4000,
+			want:        "small",
+		},
+		"weighted, second": {
+			syncTargets: []*workloadv1alpha1.SyncTarget{withCPU("small", "1"), withCPU("big", "3")},
+			random:      1000,
+			wantMax:     4000,
+			want:        "big",
+		},
+		"uniform without allocatable": {
+			syncTargets: []*workloadv1alpha1.SyncTarget{withCPU("small", "1"), withCPU("unknown", "")},
+			random:      1,
+			wantMax:     2,
+			want:        "unknown",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := pickSyncTarget(tc.syncTargets, func(n int64) int64 {
+				require.Equal(t, tc.wantMax, n)
+				return tc.random
+			})
+			require.Equal(t, tc.want, got.Name)
+		})
+	}
+}
@@ -90,7 +90,7 @@ func NewController(
 			expectedSyncTargetKeys := sets.String{}
 			for _, placement := range placements {
 				if val := placement.Annotations[workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey]; val != "" {
-					expectedSyncTargetKeys.Insert(val)
+					expectedSyncTargetKeys.Insert(workloadv1alpha1.SyncTargetKeysFromPlacementAnnotation(val)...)
 				}
 			}
 			return expectedSyncTargetKeys, err
@@ -524,12 +524,9 @@ func (c *Controller) enqueuePlacement(obj interface{}) {
 		runtime.HandleError(fmt.Errorf("expected a Placement, got a %T", obj))
 		return
 	}
-	syncTargetKey := placement.Annotations[workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey]
-	if syncTargetKey == "" {
-		return
+	for _, syncTargetKey := range workloadv1alpha1.SyncTargetKeysFromPlacementAnnotation(placement.Annotations[workloadv1alpha1.InternalSyncTargetPlacementAnnotationKey]) {
+		c.enqueueSyncTargetKey(syncTargetKey)
 	}
-
-	c.enqueueSyncTargetKey(syncTargetKey)
 }
 
 func indexByLocationWorkspace(obj interface{}) ([]string, error) {
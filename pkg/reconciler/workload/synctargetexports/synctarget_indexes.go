@@ -25,6 +25,7 @@ import (
 	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/client"
 	reconcilerapiexport "github.com/kcp-dev/kcp/pkg/reconciler/workload/apiexport"
+	"github.com/kcp-dev/kcp/pkg/reference"
 )
 
 // indexAPIExportsByAPIResourceSchemasFunc is an index function that maps an APIExport to its spec.latestResourceSchemas.
@@ -58,15 +59,8 @@ func getExportKeys(synctarget *workloadv1alpha1.SyncTarget) []string {
 	}
 
 	var keys []string
-	for _, export := range synctarget.Spec.SupportedAPIExports {
-		if export.Workspace == nil {
-			continue
-		}
-		if len(export.Workspace.Path) == 0 {
-			keys = append(keys, client.ToClusterAwareKey(lcluster, export.Workspace.ExportName))
-			continue
-		}
-		keys = append(keys, client.ToClusterAwareKey(logicalcluster.New(export.Workspace.Path), export.Workspace.ExportName))
+	for _, ref := range reference.SupportedAPIExportsOf(synctarget) {
+		keys = append(keys, client.ToClusterAwareKey(reference.ClusterFor(lcluster, ref), ref.Name))
 	}
 
 	return keys
@@ -97,8 +97,10 @@ func NewController(
 			oldCluster := old.(*workloadv1alpha1.SyncTarget)
 			newCluster := obj.(*workloadv1alpha1.SyncTarget)
 
-			// only enqueue when syncedResource or supportedAPIExported are changed.
+			// only enqueue when syncedResource, supportedAPIExported, or included or excluded resources are changed.
 			if !equality.Semantic.DeepEqual(oldCluster.Spec.SupportedAPIExports, newCluster.Spec.SupportedAPIExports) ||
+				!equality.Semantic.DeepEqual(oldCluster.Spec.IncludedResources, newCluster.Spec.IncludedResources) ||
+				!equality.Semantic.DeepEqual(oldCluster.Spec.ExcludedResources, newCluster.Spec.ExcludedResources) ||
 				!equality.Semantic.DeepEqual(oldCluster.Status.SyncedResources, newCluster.Status.SyncedResources) {
 				c.enqueueSyncTarget(obj, "")
 			}
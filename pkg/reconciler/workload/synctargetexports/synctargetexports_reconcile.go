@@ -23,6 +23,7 @@ import (
 	"github.com/kcp-dev/logicalcluster/v2"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 
@@ -52,12 +53,15 @@ func (e *exportReconciler) reconcile(ctx context.Context, syncTarget *workloadv1
 			errs = append(errs, err)
 		}
 
-		for _, schema := range export.Spec.LatestResourceSchemas {
-			syncedResource, err := e.convertSchemaToSyncedResource(exportCluster, schema, export.Status.IdentityHash)
+		for _, schemaName := range export.Spec.LatestResourceSchemas {
+			syncedResource, err := e.convertSchemaToSyncedResource(exportCluster, schemaName, export.Status.IdentityHash)
 			if err != nil {
 				klog.Warningf("cannot get schema: %v", err)
 				continue
 			}
+			if !syncTarget.Spec.IsResourceSynced(schema.GroupResource{Group: syncedResource.Group, Resource: syncedResource.Resource}) {
+				continue
+			}
 			syncedResources = append(syncedResources, syncedResource)
 		}
 	}
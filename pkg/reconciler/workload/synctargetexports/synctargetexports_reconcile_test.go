@@ -76,6 +76,26 @@ func TestSyncTargetExportReconcile(t *testing.T) {
 				{GroupResource: apisv1alpha1.GroupResource{Group: "", Resource: "services"}, Versions: []string{"v1"}},
 			},
 		},
+		{
+			name: "excluded resources",
+			syncTarget: func() *workloadv1alpha1.SyncTarget {
+				syncTarget := newSyncTarget([]apisv1alpha1.ExportReference{
+					{
+						Workspace: &apisv1alpha1.WorkspaceExportReference{ExportName: "kubernetes"},
+					},
+				}, nil)
+				syncTarget.Spec.ExcludedResources = []workloadv1alpha1.ResourcePattern{{Group: "apps", Resource: "*"}}
+				return syncTarget
+			}(),
+			export: newAPIExport("kubernetes", []string{"v1.service", "apps.v1.deployment"}, ""),
+			schemas: []*apisv1alpha1.APIResourceSchema{
+				newResourceSchema("apps.v1.deployment", "apps", "deployments", []apisv1alpha1.APIResourceVersion{{Name: "v1", Served: true}}),
+				newResourceSchema("v1.service", "", "services", []apisv1alpha1.APIResourceVersion{{Name: "v1", Served: true}}),
+			},
+			wantSyncedResources: []workloadv1alpha1.ResourceToSync{
+				{GroupResource: apisv1alpha1.GroupResource{Group: "", Resource: "services"}, Versions: []string{"v1"}},
+			},
+		},
 		{
 			name: "update existing",
 			syncTarget: newSyncTarget([]apisv1alpha1.ExportReference{
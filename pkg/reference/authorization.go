@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reference
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// Authorize checks whether the user may use the given verb on the referenced object. The authorizer
+// must authorize in the logical cluster of the referenced object, e.g. a delegated authorizer doing
+// SubjectAccessReviews in that workspace. It returns false if the access is not allowed.
+func Authorize(ctx context.Context, authz authorizer.Authorizer, u user.Info, ref tenancyv1alpha1.WorkspaceObjectReference, verb string) (bool, error) {
+	attr := authorizer.AttributesRecord{
+		User:            u,
+		Verb:            verb,
+		APIGroup:        ref.Group,
+		Resource:        ref.Resource,
+		Name:            ref.Name,
+		ResourceRequest: true,
+	}
+
+	decision, _, err := authz.Authorize(ctx, attr)
+	if err != nil {
+		return false, fmt.Errorf("unable to determine access to %s: %w", ref.Resource, err)
+	}
+	return decision == authorizer.DecisionAllow, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reference
+
+import (
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+// ForAPIExport returns the WorkspaceObjectReference of an APIExport reference.
+func ForAPIExport(ref apisv1alpha1.WorkspaceExportReference) tenancyv1alpha1.WorkspaceObjectReference {
+	return tenancyv1alpha1.WorkspaceObjectReference{
+		Path:     ref.Path,
+		Group:    apisv1alpha1.SchemeGroupVersion.Group,
+		Resource: "apiexports",
+		Name:     ref.ExportName,
+	}
+}
+
+// APIExportOf returns the reference of an APIBinding to its APIExport, and the logical cluster of
+// that APIExport. It returns false if the APIBinding does not reference an APIExport.
+func APIExportOf(apiBinding *apisv1alpha1.APIBinding) (tenancyv1alpha1.WorkspaceObjectReference, logicalcluster.Name, bool) {
+	if apiBinding.Spec.Reference.Workspace == nil {
+		return tenancyv1alpha1.WorkspaceObjectReference{}, logicalcluster.Name{}, false
+	}
+	ref := ForAPIExport(*apiBinding.Spec.Reference.Workspace)
+	return ref, ClusterFor(logicalcluster.From(apiBinding), ref), true
+}
+
+// SupportedAPIExportsOf returns the references of a SyncTarget to the APIExports it supports.
+// References without a workspace are skipped.
+func SupportedAPIExportsOf(syncTarget *workloadv1alpha1.SyncTarget) []tenancyv1alpha1.WorkspaceObjectReference {
+	var refs []tenancyv1alpha1.WorkspaceObjectReference
+	for _, export := range syncTarget.Spec.SupportedAPIExports {
+		if export.Workspace == nil {
+			continue
+		}
+		refs = append(refs, ForAPIExport(*export.Workspace))
+	}
+	return refs
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reference
+
+import (
+	"fmt"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/informer"
+)
+
+// ClusterFor returns the logical cluster a reference points to, relative to the workspace of
+// the referencing object.
+func ClusterFor(from logicalcluster.Name, ref tenancyv1alpha1.WorkspaceObjectReference) logicalcluster.Name {
+	return ClusterForPath(from, ref.Path)
+}
+
+// ClusterForPath returns the logical cluster of an absolute workspace path, or the workspace of the
+// referencing object if the path is empty.
+func ClusterForPath(from logicalcluster.Name, path string) logicalcluster.Name {
+	if path == "" {
+		return from
+	}
+	return logicalcluster.New(path)
+}
+
+// Resolver resolves WorkspaceObjectReferences to the referenced objects.
+type Resolver struct {
+	listers func() map[schema.GroupVersionResource]kcpcache.GenericClusterLister
+}
+
+// NewResolver returns a Resolver looking up objects in the informers of the given
+// dynamic discovery shared informer factory. Only objects on the local shard are found.
+func NewResolver(ddsif *informer.DynamicDiscoverySharedInformerFactory) *Resolver {
+	return &Resolver{
+		listers: func() map[schema.GroupVersionResource]kcpcache.GenericClusterLister {
+			listers, _ := ddsif.Listers()
+			return listers
+		},
+	}
+}
+
+// Resolve returns the object a reference points to, relative to the workspace of the referencing
+// object. It returns a NotFound error if the object or its resource does not exist.
+func (r *Resolver) Resolve(from logicalcluster.Name, ref tenancyv1alpha1.WorkspaceObjectReference) (*unstructured.Unstructured, error) {
+	gr := schema.GroupResource{Group: ref.Group, Resource: ref.Resource}
+	cluster := ClusterFor(from, ref)
+
+	for gvr, lister := range r.listers() {
+		if gvr.GroupResource() != gr {
+			continue
+		}
+
+		obj, err := lister.ByCluster(cluster).Get(ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("unexpected type %T for %s %s|%s", obj, gr, cluster, ref.Name)
+		}
+		return u, nil
+	}
+
+	return nil, apierrors.NewNotFound(gr, ref.Name)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reference
+
+import (
+	"regexp"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+var resourceRegExp = regexp.MustCompile(`^[a-z][-a-z0-9]*[a-z0-9]$`)
+
+// ValidateWorkspacePath validates an absolute workspace path, e.g. root:org:ws.
+func ValidateWorkspacePath(path string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if path == "" {
+		return allErrs
+	}
+	cluster := logicalcluster.New(path)
+	if !cluster.IsValid() || cluster == logicalcluster.Wildcard {
+		allErrs = append(allErrs, field.Invalid(fldPath, path, "must be a valid workspace path"))
+		return allErrs
+	}
+	if cluster != tenancyv1alpha1.RootCluster && !cluster.HasPrefix(tenancyv1alpha1.RootCluster) {
+		allErrs = append(allErrs, field.Invalid(fldPath, path, "must be an absolute path starting with root"))
+	}
+
+	return allErrs
+}
+
+// ValidateWorkspaceObjectReference validates a WorkspaceObjectReference.
+func ValidateWorkspaceObjectReference(ref tenancyv1alpha1.WorkspaceObjectReference, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, ValidateWorkspacePath(ref.Path, fldPath.Child("path"))...)
+
+	if ref.Group != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(ref.Group) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("group"), ref.Group, msg))
+		}
+	}
+
+	if ref.Resource == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("resource"), ""))
+	} else if !resourceRegExp.MatchString(ref.Resource) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("resource"), ref.Resource, "must be a lower case resource name"))
+	}
+
+	if ref.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), ""))
+	} else {
+		for _, msg := range validation.IsDNS1123Subdomain(ref.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), ref.Name, msg))
+		}
+	}
+
+	return allErrs
+}
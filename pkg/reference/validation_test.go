@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reference
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestValidateWorkspaceObjectReference(t *testing.T) {
+	tests := map[string]struct {
+		ref        tenancyv1alpha1.WorkspaceObjectReference
+		wantFields []string
+	}{
+		"valid": {
+			ref: tenancyv1alpha1.WorkspaceObjectReference{Path: "root:org:ws", Group: "apis.kcp.dev", Resource: "apiexports", Name: "kubernetes"},
+		},
+		"valid without path and group": {
+			ref: tenancyv1alpha1.WorkspaceObjectReference{Resource: "configmaps", Name: "foo"},
+		},
+		"valid root path": {
+			ref: tenancyv1alpha1.WorkspaceObjectReference{Path: "root", Resource: "configmaps", Name: "foo"},
+		},
+		"relative path": {
+			ref:        tenancyv1alpha1.WorkspaceObjectReference{Path: "org:ws", Resource: "configmaps", Name: "foo"},
+			wantFields: []string{"ref.path"},
+		},
+		"wildcard path": {
+			ref:        tenancyv1alpha1.WorkspaceObjectReference{Path: "*", Resource: "configmaps", Name: "foo"},
+			wantFields: []string{"ref.path"},
+		},
+		"invalid group": {
+			ref:        tenancyv1alpha1.WorkspaceObjectReference{Group: "Foo_Bar", Resource: "configmaps", Name: "foo"},
+			wantFields: []string{"ref.group"},
+		},
+		"missing resource and name": {
+			ref:        tenancyv1alpha1.WorkspaceObjectReference{Path: "root:org"},
+			wantFields: []string{"ref.resource", "ref.name"},
+		},
+		"invalid resource and name": {
+			ref:        tenancyv1alpha1.WorkspaceObjectReference{Resource: "ConfigMaps", Name: "Foo"},
+			wantFields: []string{"ref.resource", "ref.name"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			errs := ValidateWorkspaceObjectReference(tt.ref, field.NewPath("ref"))
+			fields := []string{}
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			if len(tt.wantFields) == 0 {
+				require.Empty(t, fields)
+				return
+			}
+			require.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
+
+func TestClusterFor(t *testing.T) {
+	from := logicalcluster.New("root:org:ws")
+	require.Equal(t, from, ClusterFor(from, tenancyv1alpha1.WorkspaceObjectReference{Resource: "configmaps", Name: "foo"}))
+	require.Equal(t, logicalcluster.New("root:other"), ClusterFor(from, tenancyv1alpha1.WorkspaceObjectReference{Path: "root:other", Resource: "configmaps", Name: "foo"}))
+}
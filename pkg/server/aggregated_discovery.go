@@ -0,0 +1,346 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
+	"k8s.io/apiextensions-apiserver/pkg/kcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/memory"
+)
+
+const (
+	// aggregatedDiscoveryGroup is the group of the aggregated discovery types, passed as "g" media type parameter.
+	aggregatedDiscoveryGroup = "apidiscovery.k8s.io"
+	// aggregatedDiscoveryKind is the kind of the aggregated discovery document, passed as "as" media type parameter.
+	aggregatedDiscoveryKind = "APIGroupDiscoveryList"
+
+	// aggregatedDiscoveryCacheSize bounds the number of workspaces whose aggregated discovery documents are kept.
+	aggregatedDiscoveryCacheSize = 4096
+	// aggregatedDiscoveryCacheTTL makes sure documents of deleted workspaces eventually go away.
+	aggregatedDiscoveryCacheTTL = time.Hour
+)
+
+// aggregatedDiscoveryVersions are the versions of the aggregated discovery format that can be served. They
+// share the same schema.
+var aggregatedDiscoveryVersions = []string{"v2", "v2beta1"}
+
+// APIGroupDiscoveryList is the aggregated discovery document of a workspace. It mirrors the type of the same
+// name in apidiscovery.k8s.io, which is not available in the Kubernetes version kcp is based on.
+type APIGroupDiscoveryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []APIGroupDiscovery `json:"items"`
+}
+
+// APIGroupDiscovery holds the versions of an API group in their preferred order. The name of the object is the group.
+type APIGroupDiscovery struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Versions          []APIVersionDiscovery `json:"versions,omitempty"`
+}
+
+// APIVersionDiscovery holds the resources of an API group version.
+type APIVersionDiscovery struct {
+	Version   string                 `json:"version"`
+	Resources []APIResourceDiscovery `json:"resources,omitempty"`
+	// Freshness is "Current", or "Stale" if the resources of the version could not be determined.
+	Freshness string `json:"freshness,omitempty"`
+}
+
+// APIResourceDiscovery describes a resource and its subresources.
+type APIResourceDiscovery struct {
+	Resource         string                    `json:"resource"`
+	ResponseKind     *metav1.GroupVersionKind  `json:"responseKind"`
+	Scope            string                    `json:"scope"`
+	SingularResource string                    `json:"singularResource"`
+	Verbs            []string                  `json:"verbs"`
+	ShortNames       []string                  `json:"shortNames,omitempty"`
+	Categories       []string                  `json:"categories,omitempty"`
+	Subresources     []APISubresourceDiscovery `json:"subresources,omitempty"`
+}
+
+// APISubresourceDiscovery describes a subresource of a resource.
+type APISubresourceDiscovery struct {
+	Subresource  string                   `json:"subresource"`
+	ResponseKind *metav1.GroupVersionKind `json:"responseKind,omitempty"`
+	Verbs        []string                 `json:"verbs"`
+}
+
+// WithAggregatedDiscovery serves /apis in the aggregated discovery format to clients asking for it via
+// the Accept header, i.e. with application/json;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscoveryList.
+// The resources of groups coming from CRDs and APIBindings are built from the given CRD lister, all other
+// groups are discovered in-process through the wrapped handler. Hence, clients get the discovery of a
+// workspace in one round-trip, independently of the number of APIBindings. The documents are cached until
+// the CRDs of the workspace change.
+//
+// Clients not asking for the aggregated format, or asking for it at /api, get the legacy discovery documents.
+func WithAggregatedDiscovery(apiHandler http.Handler, crdLister func() kcp.ClusterAwareCRDClusterLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet || (req.URL.Path != "/apis" && req.URL.Path != "/apis/") {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+		version, ok := aggregatedDiscoveryVersionFor(req.Header.Get("Accept"))
+		if !ok {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+
+		clusterName, err := request.ClusterNameFrom(req.Context())
+		if err != nil {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+
+		crds, err := crdLister().Cluster(clusterName).List(req.Context(), labels.Everything())
+		if err != nil {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewInternalError(fmt.Errorf("unable to serve aggregated discovery: error listing CustomResourceDefinitions: %w", err)),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		bs, err := aggregatedDiscoveryDocuments.get(apiHandler, req, clusterName, version, crds)
+		if err != nil {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewInternalError(err),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		etag := fmt.Sprintf("%q", fmt.Sprintf("%X", sha256.Sum256(bs)))
+		w.Header().Set("Vary", "Accept")
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", fmt.Sprintf("application/json;g=%s;v=%s;as=%s", aggregatedDiscoveryGroup, version, aggregatedDiscoveryKind))
+		if req.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(bs)
+	}
+}
+
+// aggregatedDiscoveryCache keeps the serialized aggregated discovery documents of workspaces, keyed by the
+// workspace, the format version and the hash of the CRDs of the workspace. All other groups are built-in and
+// the same for all workspaces, so the document only changes with the CRDs.
+type aggregatedDiscoveryCache struct {
+	documents *memory.IdleCache[string, []byte]
+}
+
+// aggregatedDiscoveryDocuments is the cache shared by all workspaces of the shard.
+var aggregatedDiscoveryDocuments = &aggregatedDiscoveryCache{
+	documents: memory.NewIdleCache[string, []byte](aggregatedDiscoveryCacheSize, aggregatedDiscoveryCacheTTL),
+}
+
+func init() {
+	memory.Register("workspace-aggregated-discovery", aggregatedDiscoveryDocuments.documents.Evict)
+}
+
+// get returns the serialized aggregated discovery document of the workspace, from the cache if possible.
+// Documents with stale group versions are not cached, for the next request to discover them again.
+func (c *aggregatedDiscoveryCache) get(apiHandler http.Handler, req *http.Request, clusterName logicalcluster.Name, version string, crds []*apiextensionsv1.CustomResourceDefinition) ([]byte, error) {
+	key := strings.Join([]string{clusterName.String(), version, openAPIV3HashFor(crds)}, "|")
+	if cached, ok := c.documents.Get(key); ok {
+		return cached, nil
+	}
+
+	groups := &metav1.APIGroupList{}
+	if code, err := discoverInProcess(apiHandler, req, "/apis", groups); err != nil {
+		return nil, fmt.Errorf("unable to serve aggregated discovery: error getting /apis (code %d): %w", code, err)
+	}
+
+	stale := false
+	discovery := aggregatedDiscoveryFor(groups, crds, func(gv schema.GroupVersion) (*metav1.APIResourceList, error) {
+		resources := &metav1.APIResourceList{}
+		if _, err := discoverInProcess(apiHandler, req, "/apis/"+gv.String(), resources); err != nil {
+			stale = true
+			return nil, err
+		}
+		return resources, nil
+	})
+	discovery.APIVersion = aggregatedDiscoveryGroup + "/" + version
+	discovery.Kind = aggregatedDiscoveryKind
+
+	bs, err := json.Marshal(discovery)
+	if err != nil {
+		return nil, err
+	}
+	if !stale {
+		c.documents.Add(key, bs)
+	}
+	return bs, nil
+}
+
+// aggregatedDiscoveryVersionFor returns the first version of the aggregated discovery format accepted by
+// the given Accept header, if any.
+func aggregatedDiscoveryVersionFor(accept string) (string, bool) {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil || mediaType != "application/json" {
+			continue
+		}
+		if params["g"] != aggregatedDiscoveryGroup || params["as"] != aggregatedDiscoveryKind {
+			continue
+		}
+		for _, v := range aggregatedDiscoveryVersions {
+			if params["v"] == v {
+				return v, true
+			}
+		}
+	}
+	return "", false
+}
+
+// discoverInProcess gets the legacy discovery document at the given path through the handler, on behalf of
+// the user of the original request, and decodes it into into.
+func discoverInProcess(apiHandler http.Handler, req *http.Request, path string, into interface{}) (int, error) {
+	subReq := utilnet.CloneRequest(req)
+	subReq.URL.Path = path
+	subReq.URL.RawPath = ""
+	subReq.Header.Set("Accept", "application/json")
+	subReq.Header.Del("If-None-Match")
+
+	writer := newInMemoryResponseWriter()
+	apiHandler.ServeHTTP(writer, subReq)
+	if writer.respCode != http.StatusOK {
+		return writer.respCode, fmt.Errorf("unexpected response: %s", writer.String())
+	}
+	if err := json.Unmarshal(writer.data, into); err != nil {
+		return writer.respCode, err
+	}
+	return writer.respCode, nil
+}
+
+// aggregatedDiscoveryFor builds the aggregated discovery document for the given groups. Resources of group
+// versions served by the given CRDs are derived from the CRDs, those of other group versions are retrieved
+// via discover. Group versions that cannot be discovered are marked as stale.
+func aggregatedDiscoveryFor(groups *metav1.APIGroupList, crds []*apiextensionsv1.CustomResourceDefinition, discover func(gv schema.GroupVersion) (*metav1.APIResourceList, error)) *APIGroupDiscoveryList {
+	crdsByGroup := map[string][]*apiextensionsv1.CustomResourceDefinition{}
+	for _, crd := range crds {
+		crdsByGroup[crd.Spec.Group] = append(crdsByGroup[crd.Spec.Group], crd)
+	}
+
+	ret := &APIGroupDiscoveryList{Items: []APIGroupDiscovery{}}
+	for _, group := range groups.Groups {
+		groupDiscovery := APIGroupDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: group.Name},
+		}
+		for _, version := range preferredVersionsFirst(group) {
+			gv := schema.GroupVersion{Group: group.Name, Version: version}
+			versionDiscovery := APIVersionDiscovery{Version: version, Freshness: "Current"}
+
+			var resources []metav1.APIResource
+			if groupCRDs, found := crdsByGroup[group.Name]; found {
+				resources = apiextensionsapiserver.APIResourcesForGroupVersion(group.Name, version, groupCRDs)
+			} else if list, err := discover(gv); err != nil {
+				versionDiscovery.Freshness = "Stale"
+			} else {
+				resources = list.APIResources
+			}
+			versionDiscovery.Resources = aggregatedResourcesFor(gv, resources)
+
+			groupDiscovery.Versions = append(groupDiscovery.Versions, versionDiscovery)
+		}
+		ret.Items = append(ret.Items, groupDiscovery)
+	}
+	return ret
+}
+
+// preferredVersionsFirst returns the versions of the group with the preferred version first.
+func preferredVersionsFirst(group metav1.APIGroup) []string {
+	versions := make([]string, 0, len(group.Versions))
+	if group.PreferredVersion.Version != "" {
+		versions = append(versions, group.PreferredVersion.Version)
+	}
+	for _, v := range group.Versions {
+		if v.Version != group.PreferredVersion.Version {
+			versions = append(versions, v.Version)
+		}
+	}
+	return versions
+}
+
+// aggregatedResourcesFor converts legacy discovery resources of a group version into aggregated discovery
+// resources, attaching "<resource>/<subresource>" entries to their parent resource.
+func aggregatedResourcesFor(gv schema.GroupVersion, resources []metav1.APIResource) []APIResourceDiscovery {
+	responseKind := func(r metav1.APIResource) *metav1.GroupVersionKind {
+		gvk := metav1.GroupVersionKind{Group: gv.Group, Version: gv.Version, Kind: r.Kind}
+		if r.Group != "" || r.Version != "" {
+			gvk.Group, gvk.Version = r.Group, r.Version
+		}
+		return &gvk
+	}
+
+	var ret []APIResourceDiscovery
+	index := map[string]int{}
+	for _, r := range resources {
+		if strings.Contains(r.Name, "/") {
+			continue
+		}
+		scope := "Cluster"
+		if r.Namespaced {
+			scope = "Namespaced"
+		}
+		index[r.Name] = len(ret)
+		ret = append(ret, APIResourceDiscovery{
+			Resource:         r.Name,
+			ResponseKind:     responseKind(r),
+			Scope:            scope,
+			SingularResource: r.SingularName,
+			Verbs:            r.Verbs,
+			ShortNames:       r.ShortNames,
+			Categories:       r.Categories,
+		})
+	}
+	for _, r := range resources {
+		parent, subresource, found := strings.Cut(r.Name, "/")
+		if !found {
+			continue
+		}
+		i, found := index[parent]
+		if !found {
+			continue
+		}
+		ret[i].Subresources = append(ret[i].Subresources, APISubresourceDiscovery{
+			Subresource:  subresource,
+			ResponseKind: responseKind(r),
+			Verbs:        r.Verbs,
+		})
+	}
+	return ret
+}
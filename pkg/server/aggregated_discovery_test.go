@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kcp-dev/kcp/pkg/memory"
+)
+
+func TestAggregatedDiscoveryVersionFor(t *testing.T) {
+	tests := map[string]struct {
+		accept      string
+		wantVersion string
+		wantOK      bool
+	}{
+		"legacy": {
+			accept: "application/json",
+		},
+		"empty": {},
+		"v2beta1 with fallback": {
+			accept:      "application/json;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscoveryList,application/json",
+			wantVersion: "v2beta1",
+			wantOK:      true,
+		},
+		"v2 preferred over v2beta1": {
+			accept:      "application/json;g=apidiscovery.k8s.io;v=v2;as=APIGroupDiscoveryList, application/json;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscoveryList",
+			wantVersion: "v2",
+			wantOK:      true,
+		},
+		"unknown version": {
+			accept: "application/json;g=apidiscovery.k8s.io;v=v1;as=APIGroupDiscoveryList",
+		},
+		"protobuf": {
+			accept: "application/vnd.kubernetes.protobuf;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscoveryList",
+		},
+		"wrong kind": {
+			accept: "application/json;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscovery",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			version, ok := aggregatedDiscoveryVersionFor(tt.accept)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.wantVersion, version)
+		})
+	}
+}
+
+func TestAggregatedDiscoveryFor(t *testing.T) {
+	groups := &metav1.APIGroupList{Groups: []metav1.APIGroup{
+		{
+			Name: "rbac.authorization.k8s.io",
+			Versions: []metav1.GroupVersionForDiscovery{
+				{GroupVersion: "rbac.authorization.k8s.io/v1beta1", Version: "v1beta1"},
+				{GroupVersion: "rbac.authorization.k8s.io/v1", Version: "v1"},
+			},
+			PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "rbac.authorization.k8s.io/v1", Version: "v1"},
+		},
+		{
+			Name:             "broken.example.io",
+			Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "broken.example.io/v1", Version: "v1"}},
+			PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "broken.example.io/v1", Version: "v1"},
+		},
+	}}
+
+	discover := func(gv schema.GroupVersion) (*metav1.APIResourceList, error) {
+		if gv.Group == "broken.example.io" {
+			return nil, errors.New("boom")
+		}
+		return &metav1.APIResourceList{
+			GroupVersion: gv.String(),
+			APIResources: []metav1.APIResource{
+				{Name: "roles", SingularName: "role", Namespaced: true, Kind: "Role", Verbs: []string{"get", "list"}},
+				{Name: "roles/status", Namespaced: true, Kind: "Role", Verbs: []string{"get"}},
+				{Name: "clusterroles", SingularName: "clusterrole", Kind: "ClusterRole", Verbs: []string{"get"}},
+				{Name: "clusterroles/scale", Group: "autoscaling", Version: "v1", Kind: "Scale", Verbs: []string{"get"}},
+			},
+		}, nil
+	}
+
+	discovery := aggregatedDiscoveryFor(groups, nil, discover)
+	require.Len(t, discovery.Items, 2)
+
+	rbac := discovery.Items[0]
+	require.Equal(t, "rbac.authorization.k8s.io", rbac.Name)
+	require.Len(t, rbac.Versions, 2)
+	require.Equal(t, "v1", rbac.Versions[0].Version, "expected the preferred version first")
+	require.Equal(t, "v1beta1", rbac.Versions[1].Version)
+
+	v1 := rbac.Versions[0]
+	require.Equal(t, "Current", v1.Freshness)
+	require.Equal(t, []APIResourceDiscovery{
+		{
+			Resource:         "roles",
+			ResponseKind:     &metav1.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+			Scope:            "Namespaced",
+			SingularResource: "role",
+			Verbs:            []string{"get", "list"},
+			Subresources: []APISubresourceDiscovery{
+				{
+					Subresource:  "status",
+					ResponseKind: &metav1.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+					Verbs:        []string{"get"},
+				},
+			},
+		},
+		{
+			Resource:         "clusterroles",
+			ResponseKind:     &metav1.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+			Scope:            "Cluster",
+			SingularResource: "clusterrole",
+			Verbs:            []string{"get"},
+			Subresources: []APISubresourceDiscovery{
+				{
+					Subresource:  "scale",
+					ResponseKind: &metav1.GroupVersionKind{Group: "autoscaling", Version: "v1", Kind: "Scale"},
+					Verbs:        []string{"get"},
+				},
+			},
+		},
+	}, v1.Resources)
+
+	broken := discovery.Items[1]
+	require.Len(t, broken.Versions, 1)
+	require.Equal(t, "Stale", broken.Versions[0].Freshness)
+	require.Empty(t, broken.Versions[0].Resources)
+}
+
+func TestAggregatedDiscoveryCache(t *testing.T) {
+	calls := map[string]int{}
+	broken := true
+	apiHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls[req.URL.Path]++
+		var body interface{}
+		switch req.URL.Path {
+		case "/apis":
+			body = &metav1.APIGroupList{Groups: []metav1.APIGroup{{
+				Name:             "rbac.authorization.k8s.io",
+				Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "rbac.authorization.k8s.io/v1", Version: "v1"}},
+				PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "rbac.authorization.k8s.io/v1", Version: "v1"},
+			}}}
+		case "/apis/rbac.authorization.k8s.io/v1":
+			if broken {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			body = &metav1.APIResourceList{GroupVersion: "rbac.authorization.k8s.io/v1"}
+		}
+		bs, err := json.Marshal(body)
+		require.NoError(t, err)
+		_, _ = w.Write(bs)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/apis", nil)
+
+	c := &aggregatedDiscoveryCache{documents: memory.NewIdleCache[string, []byte](10, time.Hour)}
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "widgets.example.io",
+			UID:             "uid",
+			ResourceVersion: "1",
+			Annotations:     map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+	}
+	crds := []*apiextensionsv1.CustomResourceDefinition{crd}
+	clusterName := logicalcluster.New("root:org")
+
+	_, err := c.get(apiHandler, req, clusterName, "v2beta1", crds)
+	require.NoError(t, err)
+	_, err = c.get(apiHandler, req, clusterName, "v2beta1", crds)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls["/apis"], "documents with stale group versions must not be cached")
+
+	broken = false
+	first, err := c.get(apiHandler, req, clusterName, "v2beta1", crds)
+	require.NoError(t, err)
+	second, err := c.get(apiHandler, req, clusterName, "v2beta1", crds)
+	require.NoError(t, err)
+	require.Equal(t, 3, calls["/apis"], "expected the document to be served from the cache")
+	require.Equal(t, first, second)
+
+	_, err = c.get(apiHandler, req, clusterName, "v2", crds)
+	require.NoError(t, err)
+	require.Equal(t, 4, calls["/apis"], "expected a document per format version")
+
+	changed := crd.DeepCopy()
+	changed.ResourceVersion = "2"
+	_, err = c.get(apiHandler, req, clusterName, "v2beta1", []*apiextensionsv1.CustomResourceDefinition{changed})
+	require.NoError(t, err)
+	require.Equal(t, 5, calls["/apis"], "expected a changed CRD to invalidate the document")
+
+	require.Equal(t, 3, c.documents.Evict(0, 0))
+}
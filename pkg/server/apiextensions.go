@@ -18,9 +18,13 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	_ "net/http/pprof"
 	"strings"
+	"time"
 
 	"github.com/kcp-dev/logicalcluster/v2"
 
@@ -34,13 +38,17 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/component-base/featuregate"
 	"k8s.io/klog/v2"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	schedulingv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/scheduling/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	apisv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
 	tenancyv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 	"github.com/kcp-dev/kcp/pkg/server/filters"
@@ -59,6 +67,14 @@ type apiBindingAwareCRDClusterLister struct {
 	apiBindingIndexer    cache.Indexer
 	apiExportIndexer     cache.Indexer
 	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	eventRecorder        *crdResolutionEventRecorder
+
+	// wildcardPartialMetadata caches the CRDs selected for wildcard partial metadata requests.
+	wildcardPartialMetadata wildcardCRDCache
+	// partialMetadataHashes are the pruned schema hashes of all CRDs, maintained by CRD events.
+	partialMetadataHashes *partialMetadataSchemaHashes
+	// greatestCommonSchemas caches the greatest common schemas of the bound CRDs of wildcard identity requests.
+	greatestCommonSchemas greatestCommonSchemaCache
 }
 
 func (a *apiBindingAwareCRDClusterLister) Cluster(name logicalcluster.Name) kcp.ClusterAwareCRDLister {
@@ -78,11 +94,32 @@ type apiBindingAwareCRDLister struct {
 
 var _ kcp.ClusterAwareCRDLister = &apiBindingAwareCRDLister{}
 
+// featureGatedAPIGroups are the API groups that are only served in workspaces with the respective feature enabled.
+var featureGatedAPIGroups = map[string]featuregate.Feature{
+	schedulingv1alpha1.SchemeGroupVersion.Group: kcpfeatures.LocationAPI,
+}
+
+// isFeatureEnabledForGroup returns whether the feature gating the given API group, if any, is enabled in the
+// logical cluster. Wildcard requests are not gated.
+func isFeatureEnabledForGroup(clusterName logicalcluster.Name, group string) bool {
+	feature, found := featureGatedAPIGroups[group]
+	if !found || clusterName == logicalcluster.Wildcard {
+		return true
+	}
+	return kcpfeatures.DefaultWorkspaceFeatureGate.Enabled(feature, clusterName)
+}
+
 // List lists all CustomResourceDefinitions that come in via APIBindings as well as all in the current
 // logical cluster retrieved from the context.
-func (c *apiBindingAwareCRDLister) List(ctx context.Context, selector labels.Selector) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+func (c *apiBindingAwareCRDLister) List(ctx context.Context, selector labels.Selector) (_ []*apiextensionsv1.CustomResourceDefinition, err error) {
 	logger := klog.FromContext(ctx)
 	clusterName := c.cluster
+
+	path := crdResolutionLocal
+	if clusterName == logicalcluster.Wildcard {
+		path = crdResolutionWildcardFull
+	}
+	defer func(start time.Time) { recordCRDLookup("list", path, start, err) }(time.Now())
 	logger = logger.WithValues("workspace", clusterName.String())
 
 	crdName := func(crd *apiextensionsv1.CustomResourceDefinition) string {
@@ -104,10 +141,28 @@ func (c *apiBindingAwareCRDLister) List(ctx context.Context, selector labels.Sel
 		seen.Insert(crdName(crd))
 	}
 
+	var localCRDs []*apiextensionsv1.CustomResourceDefinition
+	if clusterName != SystemCRDLogicalCluster {
+		localCRDs, err = c.crdLister.Cluster(clusterName).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Local CRDs shadow the resources of APIBindings with the Shadow local CRD policy.
+	localCRDNames := sets.NewString()
+	if clusterName != logicalcluster.Wildcard {
+		for _, crd := range localCRDs {
+			localCRDNames.Insert(crdName(crd))
+		}
+	}
+	shadowed := sets.NewString()
+
 	apiBindings, err := c.apiBindingLister.Cluster(clusterName).List(labels.Everything())
 	if err != nil {
 		return nil, err
 	}
+	// APIBindings binding the same resources are resolved by their conflict policy.
+	apibinding.SortByConflictPrecedence(apiBindings)
 	for _, apiBinding := range apiBindings {
 
 		for _, boundResource := range apiBinding.Status.BoundResources {
@@ -119,6 +174,11 @@ func (c *apiBindingAwareCRDLister) List(ctx context.Context, selector labels.Sel
 			})
 			crd, err := c.crdLister.Cluster(apibinding.ShadowWorkspaceName).Get(boundResource.Schema.UID)
 			if err != nil {
+				if apierrors.IsNotFound(err) {
+					unavailableErr := newBoundCRDUnavailableError(boundResource.Resource+"."+boundResource.Group, apiBinding, boundResource.Schema.UID)
+					c.eventRecorder.recordBoundCRDUnavailable(ctx, apiBinding, unavailableErr)
+					err = unavailableErr
+				}
 				logger.Error(err, "error getting bound CRD")
 				continue
 			}
@@ -127,10 +187,26 @@ func (c *apiBindingAwareCRDLister) List(ctx context.Context, selector labels.Sel
 				continue
 			}
 
-			// system CRDs take priority over APIBindings from the local workspace.
+			if !isFeatureEnabledForGroup(clusterName, boundResource.Group) {
+				continue
+			}
+
+			// system CRDs and APIBindings of higher precedence take priority.
 			if seen.Has(crdName(crd)) {
-				// Came from system
-				logger.Info("skipping APIBinding CRD because it came in via system CRDs")
+				logger.Info("skipping APIBinding CRD because it came in via system CRDs or another APIBinding")
+				continue
+			}
+
+			// local CRDs deliberately shadowing an APIBinding of higher precedence take priority.
+			if shadowed.Has(crdName(crd)) || apibinding.ShadowedByLocalCRDs(apiBinding) && localCRDNames.Has(crdName(crd)) {
+				logger.Info("skipping APIBinding CRD because it is shadowed by a local CRD")
+				shadowed.Insert(crdName(crd))
+				continue
+			}
+
+			// cluster-scoped resources of APIBindings restricted to namespaces are neither served nor discovered.
+			if !isServedInRequestNamespace(ctx, apiBinding, crd) {
+				logger.V(4).Info("skipping APIBinding CRD because the APIBinding is restricted to namespaces")
 				continue
 			}
 
@@ -138,7 +214,7 @@ func (c *apiBindingAwareCRDLister) List(ctx context.Context, selector labels.Sel
 
 			// Add the APIExport identity hash as an annotation to the CRD so the RESTOptionsGetter can assign
 			// the correct etcd resource prefix.
-			crd = decorateCRDWithBinding(crd, boundResource.Schema.IdentityHash, apiBinding.DeletionTimestamp)
+			crd = decorateCRDWithBinding(crd, boundResource.Schema.StorageIdentity(), apiBinding.DeletionTimestamp)
 
 			ret = append(ret, crd)
 			seen.Insert(crdName(crd))
@@ -146,18 +222,15 @@ func (c *apiBindingAwareCRDLister) List(ctx context.Context, selector labels.Sel
 	}
 
 	if clusterName != SystemCRDLogicalCluster {
-		crds, err := c.crdLister.Cluster(clusterName).List(labels.Everything())
-		if err != nil {
-			return nil, err
-		}
-		for _, crd := range crds {
+		for _, crd := range localCRDs {
 			logger := logging.WithObject(logger, crd)
 
 			if !selector.Matches(labels.Set(crd.Labels)) {
 				continue
 			}
 
-			// system CRDs and local APIBindings take priority over CRDs from the local workspace.
+			// system CRDs and local APIBindings take priority over CRDs from the local workspace,
+			// unless the APIBindings allow to be shadowed.
 			if seen.Has(crdName(crd)) {
 				logger.Info("skipping local CRD because it came in via APIBindings or system CRDs")
 				continue
@@ -192,12 +265,20 @@ func (c *apiBindingAwareCRDLister) Refresh(crd *apiextensionsv1.CustomResourceDe
 		refreshed.Annotations[apisv1alpha1.AnnotationAPIIdentityKey] = "placeholder"
 	}
 
+	// If crd had the greatest common schema of multiple bound CRDs, keep it and its serving storage
+	if strings.HasSuffix(string(crd.UID), greatestCommonSchemaUIDSuffix) {
+		refreshed.UID = crd.UID
+		refreshed.Spec.Versions = crd.Spec.Versions
+	}
+
 	// If crd was only partial metadata, make sure refreshed is too
 	if _, partialMetadata := crd.Annotations[annotationKeyPartialMetadata]; partialMetadata {
 		makePartialMetadataCRD(refreshed)
 
-		if strings.HasSuffix(string(crd.UID), ".wildcard.partial-metadata") {
+		// keep the common printer columns of a wildcard partial metadata CRD
+		if strings.HasSuffix(string(crd.UID), WildcardPartialMetadataUIDSuffix) {
 			refreshed.UID = crd.UID
+			refreshed.Spec.Versions = crd.Spec.Versions
 		}
 	}
 
@@ -205,14 +286,12 @@ func (c *apiBindingAwareCRDLister) Refresh(crd *apiextensionsv1.CustomResourceDe
 }
 
 // Get gets a CustomResourceDefinition.
-func (c *apiBindingAwareCRDLister) Get(ctx context.Context, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
-	var (
-		crd *apiextensionsv1.CustomResourceDefinition
-		err error
-	)
-
+func (c *apiBindingAwareCRDLister) Get(ctx context.Context, name string) (crd *apiextensionsv1.CustomResourceDefinition, err error) {
 	clusterName := c.cluster
 
+	path := crdResolutionSystem
+	defer func(start time.Time) { recordCRDLookup("get", path, start, err) }(time.Now())
+
 	// Priority 1: system CRD
 	crd, err = c.getSystemCRD(clusterName, name)
 	if err != nil && !apierrors.IsNotFound(err) {
@@ -227,14 +306,18 @@ func (c *apiBindingAwareCRDLister) Get(ctx context.Context, name string) (*apiex
 		identity := IdentityFromContext(ctx)
 		if clusterName == logicalcluster.Wildcard && identity != "" {
 			// Priority 2: APIBinding CRD
-			crd, err = c.getForIdentityWildcard(name, identity)
+			path = crdResolutionIdentity
+			crd, err = c.getForIdentityWildcard(ctx, name, identity)
 		} else if clusterName == logicalcluster.Wildcard && partialMetadataRequest {
 			// Priority 3: partial metadata wildcard request
+			path = crdResolutionWildcardPartial
 			crd, err = c.getForWildcardPartialMetadata(name)
 		} else if clusterName != logicalcluster.Wildcard {
 			// Priority 4: normal CRD request
-			crd, err = c.get(clusterName, name, identity)
+			path = crdResolutionLocal
+			crd, err = c.get(ctx, clusterName, name, identity)
 		} else {
+			path = crdResolutionWildcardFull
 			return nil, apierrors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
 		}
 	}
@@ -248,7 +331,8 @@ func (c *apiBindingAwareCRDLister) Get(ctx context.Context, name string) (*apiex
 		makePartialMetadataCRD(crd)
 
 		if clusterName == logicalcluster.Wildcard {
-			crd.UID = types.UID(name + ".wildcard.partial-metadata")
+			// All CRDs of the name share the UID, and with it the serving storage and watch cache.
+			crd.UID = types.UID(name + WildcardPartialMetadataUIDSuffix)
 		}
 	}
 
@@ -314,7 +398,7 @@ func makePartialMetadataCRD(crd *apiextensionsv1.CustomResourceDefinition) {
 // getForIdentityWildcard handles finding the right CRD for an incoming wildcard request with identity, such as
 //
 //	/clusters/*/apis/$group/$version/$resource:$identity.
-func (c *apiBindingAwareCRDLister) getForIdentityWildcard(name, identity string) (*apiextensionsv1.CustomResourceDefinition, error) {
+func (c *apiBindingAwareCRDLister) getForIdentityWildcard(ctx context.Context, name, identity string) (*apiextensionsv1.CustomResourceDefinition, error) {
 	group, resource := crdNameToGroupResource(name)
 
 	indexKey := identityGroupResourceKeyFunc(identity, group, resource)
@@ -324,41 +408,157 @@ func (c *apiBindingAwareCRDLister) getForIdentityWildcard(name, identity string)
 		return nil, err
 	}
 
-	if len(apiBindings) == 0 {
+	apiBinding, boundCRDName := selectAPIBindingForIdentityWildcard(apiBindings, identity, group, resource)
+	if apiBinding == nil {
 		return nil, apierrors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
 	}
 
-	// TODO(ncdc): if there are multiple bindings that match on identity/group/resource, do we need to consider some
-	// sort of greatest-common-denominator for the CRD/schema?
-	apiBinding := apiBindings[0].(*apisv1alpha1.APIBinding)
+	crd, err := c.crdLister.Cluster(apibinding.ShadowWorkspaceName).Get(boundCRDName)
+	if apierrors.IsNotFound(err) {
+		err := newBoundCRDUnavailableError(name, apiBinding, boundCRDName)
+		c.eventRecorder.recordBoundCRDUnavailable(ctx, apiBinding, err)
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
 
-	var boundCRDName string
+	// Bindings of the same identity might have bound different schemas, e.g. while an APIExport rolls out a new
+	// APIResourceSchema. Serve a schema valid for all of them.
+	if otherNames := boundCRDNamesForIdentityWildcard(apiBindings, identity, group, resource).Delete(boundCRDName); otherNames.Len() > 0 {
+		others := make([]*apiextensionsv1.CustomResourceDefinition, 0, otherNames.Len())
+		for _, otherName := range otherNames.List() {
+			other, err := c.crdLister.Cluster(apibinding.ShadowWorkspaceName).Get(otherName)
+			if err != nil {
+				return nil, err
+			}
+			others = append(others, other)
+		}
 
-	for _, r := range apiBinding.Status.BoundResources {
-		if r.Group == group && r.Resource == resource && r.Schema.IdentityHash == identity {
-			boundCRDName = r.Schema.UID
-			break
+		gcd, err := c.greatestCommonSchemas.get(name, identity, crd, others)
+		if err != nil {
+			// The skew is surfaced on the APIExport. Serve the schema of the selected binding meanwhile.
+			klog.FromContext(ctx).V(2).Info("unable to compute greatest common schema for wildcard request", "name", name, "identity", identity, "reason", err.Error())
+		} else {
+			crd = gcd
 		}
 	}
 
-	if boundCRDName == "" {
-		return nil, apierrors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
+	// Add the APIExport storage identity hash as an annotation to the CRD so the RESTOptionsGetter can assign
+	// the correct etcd resource prefix. Use a shallow copy because deep copy is expensive (but deep copy the annotations).
+	crd = decorateCRDWithBinding(crd, storageIdentityFor(apiBinding, identity, group, resource), apiBinding.DeletionTimestamp)
+
+	return crd, nil
+}
+
+// selectAPIBindingForIdentityWildcard chooses the APIBinding, and the name of its bound CRD, serving wildcard
+// requests for the given identity, group and resource when multiple APIBindings match. Bindings that are not
+// being deleted are preferred, as a deleting binding marks its CRD as terminating for all consumers. Among
+// those, the bound CRD shared by most bindings wins, and ties are broken by logical cluster for stability.
+func selectAPIBindingForIdentityWildcard(objs []interface{}, identity, group, resource string) (*apisv1alpha1.APIBinding, string) {
+	type candidate struct {
+		apiBinding   *apisv1alpha1.APIBinding
+		boundCRDName string
 	}
 
-	crd, err := c.crdLister.Cluster(apibinding.ShadowWorkspaceName).Get(boundCRDName)
-	if err != nil {
-		return nil, err
+	candidates := make([]candidate, 0, len(objs))
+	counts := map[string]int{}
+	for _, obj := range objs {
+		apiBinding := obj.(*apisv1alpha1.APIBinding)
+		boundCRDName := boundCRDNameFor(apiBinding, identity, group, resource)
+		if boundCRDName == "" {
+			continue
+		}
+		candidates = append(candidates, candidate{apiBinding: apiBinding, boundCRDName: boundCRDName})
+		if apiBinding.DeletionTimestamp.IsZero() {
+			counts[boundCRDName]++
+		}
 	}
 
-	// Add the APIExport identity hash as an annotation to the CRD so the RESTOptionsGetter can assign
-	// the correct etcd resource prefix. Use a shallow copy because deep copy is expensive (but deep copy the annotations).
-	crd = decorateCRDWithBinding(crd, identity, apiBinding.DeletionTimestamp)
+	var best *candidate
+	for i := range candidates {
+		c := &candidates[i]
+		if best == nil {
+			best = c
+			continue
+		}
 
-	return crd, nil
+		deleting, bestDeleting := !c.apiBinding.DeletionTimestamp.IsZero(), !best.apiBinding.DeletionTimestamp.IsZero()
+		switch {
+		case deleting != bestDeleting:
+			if !deleting {
+				best = c
+			}
+		case counts[c.boundCRDName] != counts[best.boundCRDName]:
+			if counts[c.boundCRDName] > counts[best.boundCRDName] {
+				best = c
+			}
+		default:
+			if logicalcluster.From(c.apiBinding).String() < logicalcluster.From(best.apiBinding).String() {
+				best = c
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, ""
+	}
+	return best.apiBinding, best.boundCRDName
+}
+
+// boundCRDNamesForIdentityWildcard returns the names of the bound CRDs of all APIBindings not being deleted for the
+// given identity, group and resource.
+func boundCRDNamesForIdentityWildcard(objs []interface{}, identity, group, resource string) sets.String {
+	names := sets.NewString()
+	for _, obj := range objs {
+		apiBinding := obj.(*apisv1alpha1.APIBinding)
+		if !apiBinding.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if boundCRDName := boundCRDNameFor(apiBinding, identity, group, resource); boundCRDName != "" {
+			names.Insert(boundCRDName)
+		}
+	}
+	return names
+}
+
+// boundCRDNameFor returns the name of the bound CRD of the APIBinding for the given identity, group and resource,
+// or the empty string if the APIBinding does not bind it.
+func boundCRDNameFor(apiBinding *apisv1alpha1.APIBinding, identity, group, resource string) string {
+	for _, r := range apiBinding.Status.BoundResources {
+		if r.Group == group && r.Resource == resource && r.Schema.IdentityHash == identity {
+			return r.Schema.UID
+		}
+	}
+	return ""
+}
+
+// storageIdentityFor returns the identity hash under which the objects bound by the APIBinding for the given
+// identity, group and resource are stored. It differs from identity after the APIExport identity was rotated.
+func storageIdentityFor(apiBinding *apisv1alpha1.APIBinding, identity, group, resource string) string {
+	for _, r := range apiBinding.Status.BoundResources {
+		if r.Group == group && r.Resource == resource && r.Schema.IdentityHash == identity {
+			return r.Schema.StorageIdentity()
+		}
+	}
+	return identity
 }
 
 const annotationKeyPartialMetadata = "crd.kcp.dev/partial-metadata"
 
+// WildcardPartialMetadataUIDSuffix is the suffix of the UIDs of the CRDs serving wildcard partial metadata
+// requests. The crdHandler does not tear down the serving storage of CRDs with such a UID as the storage of a
+// deleted CRD.
+const WildcardPartialMetadataUIDSuffix = ".wildcard.partial-metadata"
+
+// getForWildcardPartialMetadata returns a CRD to serve wildcard partial metadata requests for name. CRDs of the
+// same name in different logical clusters mostly have identical schemas after pruning to partial metadata.
+// Hence, the CRD is chosen from the group sharing the most common pruned schema, and in a stable way, so that
+// all requests end up on the same serving storage instead of one per CRD. The additional printer columns of
+// the returned CRD are those all CRDs serving the respective version agree on, so that table output does not
+// depend on which CRD was chosen.
+//
+// The selection is cached until the set of CRDs of the name changes. The pruned schema hashes are computed on
+// CRD events, and the number of CRDs hashed per call when events are lagging is bounded, see wildcardCRDCache.
 func (c *apiBindingAwareCRDLister) getForWildcardPartialMetadata(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 	objs, err := c.crdIndexer.ByIndex(byGroupResourceName, name)
 	if err != nil {
@@ -369,23 +569,148 @@ func (c *apiBindingAwareCRDLister) getForWildcardPartialMetadata(name string) (*
 		return nil, apierrors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
 	}
 
-	return objs[0].(*apiextensionsv1.CustomResourceDefinition), nil
+	return c.wildcardPartialMetadata.get(name, objs, c.partialMetadataHashes.get, selectForWildcardPartialMetadata)
+}
+
+// selectForWildcardPartialMetadata returns the CRD of the most common pruned schema hash, of the lowest logical
+// cluster, with the printer columns all CRDs agree on.
+func selectForWildcardPartialMetadata(objs []interface{}, hashes map[logicalcluster.Name]cachedSchemaHash) *apiextensionsv1.CustomResourceDefinition {
+	counts := make(map[string]int, len(objs))
+	for _, h := range hashes {
+		counts[h.hash]++
+	}
+
+	var (
+		best     *apiextensionsv1.CustomResourceDefinition
+		bestHash string
+	)
+	for _, obj := range objs {
+		crd := obj.(*apiextensionsv1.CustomResourceDefinition)
+		hash := hashes[logicalcluster.From(crd)].hash
+		switch {
+		case best == nil,
+			counts[hash] > counts[bestHash],
+			counts[hash] == counts[bestHash] && hash < bestHash,
+			hash == bestHash && logicalcluster.From(crd).String() < logicalcluster.From(best).String():
+			best, bestHash = crd, hash
+		}
+	}
+
+	return withCommonPrinterColumns(best, objs)
+}
+
+// withCommonPrinterColumns returns crd with the additional printer columns of every version reduced to those
+// all of the given CRDs serving that version have as well. crd is not mutated, and returned as is if no column
+// is dropped.
+func withCommonPrinterColumns(crd *apiextensionsv1.CustomResourceDefinition, objs []interface{}) *apiextensionsv1.CustomResourceDefinition {
+	var versions []apiextensionsv1.CustomResourceDefinitionVersion
+	for i, v := range crd.Spec.Versions {
+		columns := v.AdditionalPrinterColumns
+		for _, obj := range objs {
+			other := obj.(*apiextensionsv1.CustomResourceDefinition)
+			for _, ov := range other.Spec.Versions {
+				if ov.Name == v.Name && ov.Served {
+					columns = intersectPrinterColumns(columns, ov.AdditionalPrinterColumns)
+				}
+			}
+		}
+		if len(columns) == len(v.AdditionalPrinterColumns) {
+			continue
+		}
+		if versions == nil {
+			versions = make([]apiextensionsv1.CustomResourceDefinitionVersion, len(crd.Spec.Versions))
+			copy(versions, crd.Spec.Versions)
+		}
+		versions[i].AdditionalPrinterColumns = columns
+	}
+	if versions == nil {
+		return crd
+	}
+
+	out := *crd
+	out.Spec.Versions = versions
+	return &out
+}
+
+// intersectPrinterColumns returns the columns of a, in order, that have an equivalent column in b.
+func intersectPrinterColumns(a, b []apiextensionsv1.CustomResourceColumnDefinition) []apiextensionsv1.CustomResourceColumnDefinition {
+	var ret []apiextensionsv1.CustomResourceColumnDefinition
+	for _, ac := range a {
+		for _, bc := range b {
+			if ac.Name == bc.Name && ac.Type == bc.Type && ac.Format == bc.Format && ac.JSONPath == bc.JSONPath {
+				ret = append(ret, ac)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// partialMetadataSchemaHash returns a hash of everything of the CRD that matters when serving partial
+// metadata, i.e. all of the CRD but the version schemas.
+func partialMetadataSchemaHash(crd *apiextensionsv1.CustomResourceDefinition) string {
+	type version struct {
+		Name                     string                                           `json:"name"`
+		Served                   bool                                             `json:"served"`
+		Storage                  bool                                             `json:"storage"`
+		AdditionalPrinterColumns []apiextensionsv1.CustomResourceColumnDefinition `json:"additionalPrinterColumns,omitempty"`
+	}
+	pruned := struct {
+		Group    string                                        `json:"group"`
+		Names    apiextensionsv1.CustomResourceDefinitionNames `json:"names"`
+		Scope    apiextensionsv1.ResourceScope                 `json:"scope"`
+		Versions []version                                     `json:"versions"`
+	}{
+		Group: crd.Spec.Group,
+		Names: crd.Spec.Names,
+		Scope: crd.Spec.Scope,
+	}
+	for _, v := range crd.Spec.Versions {
+		pruned.Versions = append(pruned.Versions, version{Name: v.Name, Served: v.Served, Storage: v.Storage, AdditionalPrinterColumns: v.AdditionalPrinterColumns})
+	}
+
+	bs, err := json.Marshal(&pruned)
+	if err != nil {
+		// cannot happen, the struct only contains marshallable types
+		panic(err)
+	}
+	sum := sha256.Sum256(bs)
+	return hex.EncodeToString(sum[:8])
 }
 
 func (c *apiBindingAwareCRDLister) getSystemCRD(_ logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 	return c.crdLister.Cluster(SystemCRDLogicalCluster).Get(name)
 }
 
-func (c *apiBindingAwareCRDLister) get(clusterName logicalcluster.Name, name, identity string) (*apiextensionsv1.CustomResourceDefinition, error) {
+// apiBindingsFor returns the APIBindings of the logical cluster that might bind the group and resource. With an
+// identity, this is answered directly by the identity/group/resource/cluster index, without listing all APIBindings.
+func (c *apiBindingAwareCRDLister) apiBindingsFor(clusterName logicalcluster.Name, identity, group, resource string) ([]*apisv1alpha1.APIBinding, error) {
+	if identity == "" {
+		return c.apiBindingLister.Cluster(clusterName).List(labels.Everything())
+	}
+
+	objs, err := c.apiBindingIndexer.ByIndex(byIdentityGroupResourceCluster, identityGroupResourceClusterKeyFunc(identity, group, resource, clusterName))
+	if err != nil {
+		return nil, err
+	}
+	apiBindings := make([]*apisv1alpha1.APIBinding, 0, len(objs))
+	for _, obj := range objs {
+		apiBindings = append(apiBindings, obj.(*apisv1alpha1.APIBinding))
+	}
+	return apiBindings, nil
+}
+
+func (c *apiBindingAwareCRDLister) get(ctx context.Context, clusterName logicalcluster.Name, name, identity string) (*apiextensionsv1.CustomResourceDefinition, error) {
 	var crd *apiextensionsv1.CustomResourceDefinition
 
 	// Priority 1: see if it comes from any APIBindings
 	group, resource := crdNameToGroupResource(name)
 
-	apiBindings, err := c.apiBindingLister.Cluster(clusterName).List(labels.Everything())
+	apiBindings, err := c.apiBindingsFor(clusterName, identity, group, resource)
 	if err != nil {
 		return nil, err
 	}
+	apibinding.SortByConflictPrecedence(apiBindings)
 	for _, apiBinding := range apiBindings {
 
 		for _, boundResource := range apiBinding.Status.BoundResources {
@@ -394,19 +719,42 @@ func (c *apiBindingAwareCRDLister) get(clusterName logicalcluster.Name, name, id
 			matchingIdentity := identity == "" || boundResource.Schema.IdentityHash == identity
 
 			if boundResource.Group == group && boundResource.Resource == resource && matchingIdentity {
+				if !isFeatureEnabledForGroup(clusterName, group) {
+					return nil, apierrors.NewNotFound(schema.GroupResource{Group: apiextensionsv1.SchemeGroupVersion.Group, Resource: "customresourcedefinitions"}, name)
+				}
+
+				if identity == "" && apibinding.ShadowedByLocalCRDs(apiBinding) {
+					// A CRD in the current logical cluster deliberately shadows the APIBinding.
+					localCRD, err := c.crdLister.Cluster(clusterName).Get(name)
+					if err != nil && !apierrors.IsNotFound(err) {
+						return nil, err
+					}
+					if localCRD != nil {
+						return localCRD, nil
+					}
+				}
+
 				crd, err = c.crdLister.Cluster(apibinding.ShadowWorkspaceName).Get(boundResource.Schema.UID)
 				if err != nil && apierrors.IsNotFound(err) {
 					// If we got here, it means there is supposed to be a CRD coming from an APIBinding, but
-					// the CRD doesn't exist for some reason.
-					return nil, apierrors.NewServiceUnavailable(fmt.Sprintf("%s is currently unavailable", name))
+					// the CRD doesn't exist for some reason. Tell the user why.
+					err := newBoundCRDUnavailableError(name, apiBinding, boundResource.Schema.UID)
+					c.eventRecorder.recordBoundCRDUnavailable(ctx, apiBinding, err)
+					return nil, err
 				} else if err != nil {
 					// something went wrong w/the lister - could only happen if meta.Accessor() fails on an item in the store.
 					return nil, err
 				}
 
+				// Requests of the API service provider, with identity, are not restricted to the namespaces
+				// of the APIBinding.
+				if identity == "" && !isServedInRequestNamespace(ctx, apiBinding, crd) {
+					return nil, apierrors.NewNotFound(schema.GroupResource{Group: apiextensionsv1.SchemeGroupVersion.Group, Resource: "customresourcedefinitions"}, name)
+				}
+
 				// Add the APIExport identity hash as an annotation to the CRD so the RESTOptionsGetter can assign
 				// the correct etcd resource prefix.
-				crd = decorateCRDWithBinding(crd, boundResource.Schema.IdentityHash, apiBinding.DeletionTimestamp)
+				crd = decorateCRDWithBinding(crd, boundResource.Schema.StorageIdentity(), apiBinding.DeletionTimestamp)
 
 				return crd, nil
 			}
@@ -429,6 +777,23 @@ func (c *apiBindingAwareCRDLister) get(clusterName logicalcluster.Name, name, id
 	return nil, apierrors.NewNotFound(schema.GroupResource{Group: apiextensionsv1.SchemeGroupVersion.Group, Resource: "customresourcedefinitions"}, name)
 }
 
+// isServedInRequestNamespace returns whether the resource of a CRD bound by the APIBinding is served for the
+// request in ctx, given the namespaces the APIBinding is restricted to. Namespaced resources are served in these
+// namespaces and across all namespaces, e.g. for discovery and lists. Cluster-scoped resources are not served.
+func isServedInRequestNamespace(ctx context.Context, apiBinding *apisv1alpha1.APIBinding, crd *apiextensionsv1.CustomResourceDefinition) bool {
+	if len(apiBinding.Spec.Namespaces) == 0 {
+		return true
+	}
+	if crd.Spec.Scope != apiextensionsv1.NamespaceScoped {
+		return false
+	}
+	info, found := genericapirequest.RequestInfoFrom(ctx)
+	if !found || info.Namespace == "" {
+		return true
+	}
+	return sets.NewString(apiBinding.Spec.Namespaces...).Has(info.Namespace)
+}
+
 func crdNameToGroupResource(name string) (group, resource string) {
 	parts := strings.SplitN(name, ".", 2)
 
@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/conditions"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
+)
+
+const (
+	// CauseTypeAPIBindingNotBound is the status cause of a CRD resolution failure when the APIBinding
+	// binding the resource has not completed its initial binding.
+	CauseTypeAPIBindingNotBound metav1.CauseType = "APIBindingNotInitialBindingCompleted"
+	// CauseTypeBoundSchemaMissing is the status cause of a CRD resolution failure when the CRD of the bound
+	// APIResourceSchema is missing in the shadow workspace.
+	CauseTypeBoundSchemaMissing metav1.CauseType = "BoundSchemaMissing"
+
+	// crdResolutionEventInterval is the minimal interval between two identical events about a CRD
+	// resolution failure of the same APIBinding. Failures are found on the request path, potentially
+	// on every request.
+	crdResolutionEventInterval = 5 * time.Minute
+)
+
+// newBoundCRDUnavailableError returns the error for a resource that the APIBinding binds through the schema
+// with the given UID, but whose CRD is missing in the shadow workspace. The status details carry the causes, so that users can find out
+// what is wrong with the binding.
+func newBoundCRDUnavailableError(name string, apiBinding *apisv1alpha1.APIBinding, schemaUID string) *apierrors.StatusError {
+	causes := boundCRDUnavailableCauses(apiBinding, schemaUID)
+	messages := make([]string, 0, len(causes))
+	for _, c := range causes {
+		messages = append(messages, c.Message)
+	}
+
+	err := apierrors.NewServiceUnavailable(fmt.Sprintf("%s is currently unavailable: %s", name, strings.Join(messages, "; ")))
+	err.ErrStatus.Details = &metav1.StatusDetails{
+		Group:  apiextensionsv1.SchemeGroupVersion.Group,
+		Kind:   "customresourcedefinitions",
+		Name:   name,
+		Causes: causes,
+	}
+	return err
+}
+
+// boundCRDUnavailableCauses returns the status causes why the bound CRD of the resource is missing.
+func boundCRDUnavailableCauses(apiBinding *apisv1alpha1.APIBinding, schemaUID string) []metav1.StatusCause {
+	var causes []metav1.StatusCause
+	clusterName := logicalcluster.From(apiBinding)
+
+	if !conditions.IsTrue(apiBinding, apisv1alpha1.InitialBindingCompleted) {
+		message := fmt.Sprintf("APIBinding %s|%s is not %s", clusterName, apiBinding.Name, apisv1alpha1.InitialBindingCompleted)
+		if reason := conditions.GetReason(apiBinding, apisv1alpha1.InitialBindingCompleted); reason != "" {
+			message += fmt.Sprintf(" (%s: %s)", reason, conditions.GetMessage(apiBinding, apisv1alpha1.InitialBindingCompleted))
+		}
+		causes = append(causes, metav1.StatusCause{
+			Type:    CauseTypeAPIBindingNotBound,
+			Message: message,
+			Field:   "status.conditions",
+		})
+	}
+
+	causes = append(causes, metav1.StatusCause{
+		Type:    CauseTypeBoundSchemaMissing,
+		Message: fmt.Sprintf("schema UID %s of APIBinding %s|%s is missing in shadow workspace %s", schemaUID, clusterName, apiBinding.Name, apibinding.ShadowWorkspaceName),
+		Field:   "status.boundResources",
+	})
+
+	return causes
+}
+
+// crdResolutionEventRecorder records CRD resolution failures as events on the APIBindings causing them.
+// Identical events are recorded at most once per interval.
+type crdResolutionEventRecorder struct {
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface
+	interval          time.Duration
+	now               func() time.Time
+
+	lock     sync.Mutex
+	recorded map[string]time.Time
+}
+
+func newCRDResolutionEventRecorder(kubeClusterClient kcpkubernetesclientset.ClusterInterface) *crdResolutionEventRecorder {
+	return &crdResolutionEventRecorder{
+		kubeClusterClient: kubeClusterClient,
+		interval:          crdResolutionEventInterval,
+		now:               time.Now,
+		recorded:          map[string]time.Time{},
+	}
+}
+
+// recordBoundCRDUnavailable records a warning event with the causes of err on the APIBinding. It does not block,
+// and it is a no-op on a nil recorder.
+func (r *crdResolutionEventRecorder) recordBoundCRDUnavailable(ctx context.Context, apiBinding *apisv1alpha1.APIBinding, err *apierrors.StatusError) {
+	if r == nil || err.ErrStatus.Details == nil {
+		return
+	}
+
+	clusterName := logicalcluster.From(apiBinding)
+	for _, cause := range err.ErrStatus.Details.Causes {
+		if !r.shouldRecord(strings.Join([]string{clusterName.String(), apiBinding.Name, string(cause.Type), cause.Message}, "|")) {
+			continue
+		}
+
+		now := metav1.NewTime(r.now())
+		event := &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: apiBinding.Name + ".",
+				Namespace:    metav1.NamespaceDefault,
+			},
+			InvolvedObject: corev1.ObjectReference{
+				APIVersion: apisv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "APIBinding",
+				Name:       apiBinding.Name,
+				UID:        apiBinding.UID,
+			},
+			Reason:         string(cause.Type),
+			Message:        cause.Message,
+			Type:           corev1.EventTypeWarning,
+			Source:         corev1.EventSource{Component: "kcp"},
+			FirstTimestamp: now,
+			LastTimestamp:  now,
+			Count:          1,
+		}
+		logger := klog.FromContext(ctx).WithValues("apibinding", clusterName.Join(apiBinding.Name).String(), "reason", cause.Type)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if _, err := r.kubeClusterClient.Cluster(clusterName).CoreV1().Events(event.Namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+				logger.V(2).Info("failed to record CRD resolution event", "err", err)
+			}
+		}()
+	}
+}
+
+// shouldRecord returns whether an event with the given key has not been recorded within the interval,
+// and marks it as recorded.
+func (r *crdResolutionEventRecorder) shouldRecord(key string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+	for k, t := range r.recorded {
+		if now.Sub(t) >= r.interval {
+			delete(r.recorded, k)
+		}
+	}
+	if _, found := r.recorded[key]; found {
+		return false
+	}
+	r.recorded[key] = now
+	return true
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kcp-dev/kcp/pkg/schemacompat"
+)
+
+// greatestCommonSchemaUIDSuffix is the suffix of the UIDs of greatest common schema CRDs. It ends in
+// WildcardPartialMetadataUIDSuffix, such that the crdHandler does not tear down their serving storage as the
+// storage of a deleted CRD.
+const greatestCommonSchemaUIDSuffix = ".gcd" + WildcardPartialMetadataUIDSuffix
+
+// greatestCommonSchemaCRD returns a copy of crd whose version schemas only accept what the schemas of crd and of
+// all others accept, i.e. the greatest common denominator of the bound CRDs of the APIBindings sharing an identity.
+// Only versions served by all CRDs are kept. It fails if the CRDs disagree on the storage version, or if a schema
+// cannot be narrowed, e.g. because the type of a field differs.
+//
+// All requests resulting in the same versions share the UID of the returned CRD, and with it the serving storage.
+func greatestCommonSchemaCRD(name string, crd *apiextensionsv1.CustomResourceDefinition, others []*apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+	out := crd.DeepCopy()
+
+	for _, other := range others {
+		otherVersions := make(map[string]apiextensionsv1.CustomResourceDefinitionVersion, len(other.Spec.Versions))
+		for _, v := range other.Spec.Versions {
+			otherVersions[v.Name] = v
+		}
+
+		versions := make([]apiextensionsv1.CustomResourceDefinitionVersion, 0, len(out.Spec.Versions))
+		for _, v := range out.Spec.Versions {
+			ov, found := otherVersions[v.Name]
+			if v.Storage != (found && ov.Storage) {
+				return nil, fmt.Errorf("bound CRDs %s and %s have different storage versions", crd.Name, other.Name)
+			}
+			if !found || !ov.Served {
+				if !v.Storage {
+					continue
+				}
+				v.Served = false
+			}
+
+			switch {
+			case ov.Schema == nil || ov.Schema.OpenAPIV3Schema == nil:
+			case v.Schema == nil || v.Schema.OpenAPIV3Schema == nil:
+				v.Schema = ov.Schema.DeepCopy()
+			default:
+				fldPath := field.NewPath("spec", "versions").Key(v.Name).Child("schema", "openAPIV3Schema")
+				gcd, err := schemacompat.EnsureStructuralSchemaCompatibility(fldPath, v.Schema.OpenAPIV3Schema, ov.Schema.OpenAPIV3Schema, true)
+				if err != nil {
+					return nil, fmt.Errorf("bound CRDs %s and %s have incompatible schemas: %w", crd.Name, other.Name, err)
+				}
+				v.Schema = &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: gcd}
+			}
+
+			versions = append(versions, v)
+		}
+		out.Spec.Versions = versions
+	}
+
+	served := false
+	for _, v := range out.Spec.Versions {
+		served = served || v.Served
+	}
+	if !served {
+		return nil, fmt.Errorf("bound CRDs for %s do not serve any common version", name)
+	}
+
+	out.UID = types.UID(name + "." + versionsHash(out.Spec.Versions) + greatestCommonSchemaUIDSuffix)
+
+	return out, nil
+}
+
+// versionsHash returns a stable hash of the given CRD versions, i.e. of their schemas.
+func versionsHash(versions []apiextensionsv1.CustomResourceDefinitionVersion) string {
+	bs, err := json.Marshal(versions)
+	if err != nil {
+		// cannot happen, the versions only contain marshallable types
+		panic(err)
+	}
+	sum := sha256.Sum256(bs)
+	return hex.EncodeToString(sum[:8])
+}
+
+// greatestCommonSchemaCache caches greatest common schema CRDs by the schemas of the CRDs they are computed
+// from, per CRD name and identity. The schema of a CRD is only hashed again when its resourceVersion changes,
+// and the greatest common schema is only computed again when a schema changes.
+//
+// The zero value is ready to use.
+type greatestCommonSchemaCache struct {
+	lock    sync.Mutex
+	entries map[string]*greatestCommonSchemaCacheEntry
+}
+
+// greatestCommonSchemaCacheEntry is immutable once stored.
+type greatestCommonSchemaCacheEntry struct {
+	// key identifies the schemas the greatest common schema is computed from.
+	key string
+	// versions and uid of the greatest common schema CRD, if err is nil.
+	versions []apiextensionsv1.CustomResourceDefinitionVersion
+	uid      types.UID
+	err      error
+	// hashes are the schema hashes by CRD name.
+	hashes map[string]cachedSchemaHash
+}
+
+// get returns a copy of crd with the greatest common schema of crd and others, see greatestCommonSchemaCRD.
+func (c *greatestCommonSchemaCache) get(name, identity string, crd *apiextensionsv1.CustomResourceDefinition, others []*apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+	entryKey := identity + "/" + name
+
+	c.lock.Lock()
+	entry := c.entries[entryKey]
+	c.lock.Unlock()
+
+	var previous map[string]cachedSchemaHash
+	if entry != nil {
+		previous = entry.hashes
+	}
+	hashes := make(map[string]cachedSchemaHash, len(others)+1)
+	hash := func(crd *apiextensionsv1.CustomResourceDefinition) string {
+		h, found := previous[crd.Name]
+		if !found || h.resourceVersion != crd.ResourceVersion {
+			h = cachedSchemaHash{resourceVersion: crd.ResourceVersion, hash: versionsHash(crd.Spec.Versions)}
+		}
+		hashes[crd.Name] = h
+		return h.hash
+	}
+
+	// the schema of crd comes first, as the versions of the result are ordered like its versions
+	otherHashes := make([]string, 0, len(others))
+	for _, other := range others {
+		otherHashes = append(otherHashes, hash(other))
+	}
+	sort.Strings(otherHashes)
+	key := hash(crd) + ":" + strings.Join(otherHashes, ",")
+
+	if entry == nil || entry.key != key {
+		entry = &greatestCommonSchemaCacheEntry{key: key}
+		gcd, err := greatestCommonSchemaCRD(name, crd, others)
+		if err != nil {
+			entry.err = err
+		} else {
+			entry.versions, entry.uid = gcd.Spec.Versions, gcd.UID
+		}
+	}
+	entry = &greatestCommonSchemaCacheEntry{key: entry.key, versions: entry.versions, uid: entry.uid, err: entry.err, hashes: hashes}
+
+	c.lock.Lock()
+	if c.entries == nil {
+		c.entries = map[string]*greatestCommonSchemaCacheEntry{}
+	}
+	c.entries[entryKey] = entry
+	c.lock.Unlock()
+
+	if entry.err != nil {
+		return nil, entry.err
+	}
+
+	// the versions are shared by all copies, and must not be mutated
+	out := *crd
+	out.UID = entry.uid
+	out.Spec.Versions = entry.versions
+	return &out, nil
+}
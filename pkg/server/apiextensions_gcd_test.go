@@ -0,0 +1,199 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGreatestCommonSchemaCRD(t *testing.T) {
+	newCRD := func(uid string, versions ...apiextensionsv1.CustomResourceDefinitionVersion) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: uid, UID: "uid", ResourceVersion: "1"},
+			Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Versions: versions},
+		}
+	}
+	newVersion := func(name string, served, storage bool, props ...string) apiextensionsv1.CustomResourceDefinitionVersion {
+		spec := apiextensionsv1.JSONSchemaProps{Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{}}
+		for _, p := range props {
+			typ := "string"
+			if parts := strings.SplitN(p, ":", 2); len(parts) == 2 {
+				p, typ = parts[0], parts[1]
+			}
+			spec.Properties[p] = apiextensionsv1.JSONSchemaProps{Type: typ}
+		}
+		return apiextensionsv1.CustomResourceDefinitionVersion{
+			Name:    name,
+			Served:  served,
+			Storage: storage,
+			Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+				Type:       "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{"spec": spec},
+			}},
+		}
+	}
+	specProperties := func(v apiextensionsv1.CustomResourceDefinitionVersion) []string {
+		var ret []string
+		for p := range v.Schema.OpenAPIV3Schema.Properties["spec"].Properties {
+			ret = append(ret, p)
+		}
+		return ret
+	}
+
+	t.Run("properties are intersected", func(t *testing.T) {
+		crd := newCRD("a", newVersion("v1", true, true, "foo", "bar"))
+		other := newCRD("b", newVersion("v1", true, true, "foo", "baz"))
+
+		gcd, err := greatestCommonSchemaCRD("widgets.example.io", crd, []*apiextensionsv1.CustomResourceDefinition{other})
+		require.NoError(t, err)
+		require.Len(t, gcd.Spec.Versions, 1)
+		require.ElementsMatch(t, []string{"foo"}, specProperties(gcd.Spec.Versions[0]))
+		require.True(t, strings.HasSuffix(string(gcd.UID), greatestCommonSchemaUIDSuffix))
+		require.Len(t, crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties, 2, "input must not be mutated")
+	})
+
+	t.Run("only common served versions are kept", func(t *testing.T) {
+		crd := newCRD("a", newVersion("v1", true, true, "foo"), newVersion("v2", true, false, "foo"))
+		other := newCRD("b", newVersion("v1", false, true, "foo"))
+
+		gcd, err := greatestCommonSchemaCRD("widgets.example.io", crd, []*apiextensionsv1.CustomResourceDefinition{other})
+		require.Error(t, err, "no common served version")
+		require.Nil(t, gcd)
+
+		other = newCRD("b", newVersion("v1", true, true, "foo"))
+		gcd, err = greatestCommonSchemaCRD("widgets.example.io", crd, []*apiextensionsv1.CustomResourceDefinition{other})
+		require.NoError(t, err)
+		require.Len(t, gcd.Spec.Versions, 1)
+		require.Equal(t, "v1", gcd.Spec.Versions[0].Name)
+	})
+
+	t.Run("different storage versions fail", func(t *testing.T) {
+		crd := newCRD("a", newVersion("v1", true, true, "foo"), newVersion("v2", true, false, "foo"))
+		other := newCRD("b", newVersion("v1", true, false, "foo"), newVersion("v2", true, true, "foo"))
+
+		_, err := greatestCommonSchemaCRD("widgets.example.io", crd, []*apiextensionsv1.CustomResourceDefinition{other})
+		require.Error(t, err)
+	})
+
+	t.Run("type changes fail", func(t *testing.T) {
+		crd := newCRD("a", newVersion("v1", true, true, "foo"))
+		other := newCRD("b", newVersion("v1", true, true, "foo:integer"))
+
+		_, err := greatestCommonSchemaCRD("widgets.example.io", crd, []*apiextensionsv1.CustomResourceDefinition{other})
+		require.Error(t, err)
+	})
+
+	t.Run("UID is stable for the same schemas", func(t *testing.T) {
+		a := newCRD("a", newVersion("v1", true, true, "foo"))
+		b := newCRD("b", newVersion("v1", true, true, "foo", "bar"))
+
+		gcd1, err := greatestCommonSchemaCRD("widgets.example.io", a, []*apiextensionsv1.CustomResourceDefinition{b})
+		require.NoError(t, err)
+		gcd2, err := greatestCommonSchemaCRD("widgets.example.io", b, []*apiextensionsv1.CustomResourceDefinition{a})
+		require.NoError(t, err)
+		require.Equal(t, gcd1.UID, gcd2.UID)
+
+		b.ResourceVersion = "2"
+		gcd3, err := greatestCommonSchemaCRD("widgets.example.io", a, []*apiextensionsv1.CustomResourceDefinition{b})
+		require.NoError(t, err)
+		require.Equal(t, gcd1.UID, gcd3.UID, "UID must not change without schema changes")
+
+		b.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{newVersion("v1", true, true, "foo", "baz")}
+		gcd4, err := greatestCommonSchemaCRD("widgets.example.io", a, []*apiextensionsv1.CustomResourceDefinition{b})
+		require.NoError(t, err)
+		require.Equal(t, gcd1.UID, gcd4.UID, "UID must not change if the common schema does not")
+
+		b.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{newVersion("v1", true, true, "foo:integer")}
+		_, err = greatestCommonSchemaCRD("widgets.example.io", a, []*apiextensionsv1.CustomResourceDefinition{b})
+		require.Error(t, err)
+
+		b.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{newVersion("v1", true, true, "foo", "bar")}
+		a.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{newVersion("v1", true, true, "foo", "bar")}
+		gcd5, err := greatestCommonSchemaCRD("widgets.example.io", a, []*apiextensionsv1.CustomResourceDefinition{b})
+		require.NoError(t, err)
+		require.NotEqual(t, gcd1.UID, gcd5.UID, "UID must change with the common schema")
+	})
+
+	t.Run("UID is kept by the crdHandler", func(t *testing.T) {
+		a := newCRD("a", newVersion("v1", true, true, "foo"))
+		b := newCRD("b", newVersion("v1", true, true, "foo"))
+
+		gcd, err := greatestCommonSchemaCRD("widgets.example.io", a, []*apiextensionsv1.CustomResourceDefinition{b})
+		require.NoError(t, err)
+		require.True(t, strings.HasSuffix(string(gcd.UID), WildcardPartialMetadataUIDSuffix))
+	})
+}
+
+func TestGreatestCommonSchemaCache(t *testing.T) {
+	newCRD := func(name, resourceVersion string, props ...string) *apiextensionsv1.CustomResourceDefinition {
+		spec := apiextensionsv1.JSONSchemaProps{Type: "object", Properties: map[string]apiextensionsv1.JSONSchemaProps{}}
+		for _, p := range props {
+			spec.Properties[p] = apiextensionsv1.JSONSchemaProps{Type: "string"}
+		}
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name, ResourceVersion: resourceVersion},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+					Type:       "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{"spec": spec},
+				}},
+			}}},
+		}
+	}
+
+	var c greatestCommonSchemaCache
+	a := newCRD("a", "1", "foo")
+	b := newCRD("b", "1", "foo", "bar")
+
+	gcd1, err := c.get("widgets.example.io", "identity", a, []*apiextensionsv1.CustomResourceDefinition{b})
+	require.NoError(t, err)
+	require.Equal(t, "a", gcd1.Name)
+	entry := c.entries["identity/widgets.example.io"]
+	require.NotNil(t, entry)
+
+	gcd2, err := c.get("widgets.example.io", "identity", a, []*apiextensionsv1.CustomResourceDefinition{b})
+	require.NoError(t, err)
+	require.Equal(t, gcd1.UID, gcd2.UID)
+	require.True(t, &gcd1.Spec.Versions[0] == &gcd2.Spec.Versions[0], "the cached versions must be reused")
+
+	// a new resourceVersion without schema change reuses the cached versions
+	b = newCRD("b", "2", "foo", "bar")
+	gcd3, err := c.get("widgets.example.io", "identity", a, []*apiextensionsv1.CustomResourceDefinition{b})
+	require.NoError(t, err)
+	require.True(t, &gcd1.Spec.Versions[0] == &gcd3.Spec.Versions[0], "the cached versions must be reused")
+	require.Equal(t, "2", c.entries["identity/widgets.example.io"].hashes["b"].resourceVersion)
+
+	// a schema change computes the greatest common schema again
+	b = newCRD("b", "3", "bar")
+	_, err = c.get("widgets.example.io", "identity", a, []*apiextensionsv1.CustomResourceDefinition{b})
+	require.NoError(t, err)
+	require.NotEqual(t, entry.key, c.entries["identity/widgets.example.io"].key)
+
+	// other identities are cached separately
+	_, err = c.get("widgets.example.io", "other", a, []*apiextensionsv1.CustomResourceDefinition{b})
+	require.NoError(t, err)
+	require.Len(t, c.entries, 2)
+}
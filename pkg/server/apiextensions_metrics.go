@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+// The resolution paths of apiBindingAwareCRDLister, in the order Get tries them.
+const (
+	crdResolutionSystem          = "system"
+	crdResolutionIdentity        = "identity"
+	crdResolutionWildcardPartial = "wildcard-partial"
+	crdResolutionWildcardFull    = "wildcard-full"
+	crdResolutionLocal           = "local"
+)
+
+var (
+	crdListerRequests = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "kcp",
+			Name:           "crd_lister_requests_total",
+			Help:           "Number of CRD lookups of the APIBinding aware CRD lister, by operation, resolution path and result.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"operation", "path", "result"}, // result is one of "found", "not_found" or "error"
+	)
+
+	crdListerDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem:      "kcp",
+			Name:           "crd_lister_duration_seconds",
+			Help:           "Duration in seconds of CRD lookups of the APIBinding aware CRD lister, by operation and resolution path.",
+			StabilityLevel: metrics.ALPHA,
+			Buckets:        metrics.ExponentialBuckets(0.00001, 4, 10),
+		},
+		[]string{"operation", "path"},
+	)
+
+	crdListerWildcardHashedCRDs = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      "kcp",
+			Name:           "crd_lister_wildcard_hashed_crds",
+			Help:           "Number of CRDs whose pruned schema was hashed during a wildcard lookup of the APIBinding aware CRD lister. Zero if the cached selection was used.",
+			StabilityLevel: metrics.ALPHA,
+			Buckets:        []float64{0, 1, 10, 100, 1000, 10000},
+		},
+	)
+)
+
+var registerCRDListerMetrics sync.Once
+
+func init() {
+	registerCRDListerMetrics.Do(func() {
+		legacyregistry.MustRegister(crdListerRequests)
+		legacyregistry.MustRegister(crdListerDuration)
+		legacyregistry.MustRegister(crdListerWildcardHashedCRDs)
+	})
+}
+
+// recordCRDLookup records a CRD lookup of the given operation that was resolved by path and started at start.
+func recordCRDLookup(operation, path string, start time.Time, err error) {
+	result := "found"
+	switch {
+	case apierrors.IsNotFound(err):
+		result = "not_found"
+	case err != nil:
+		result = "error"
+	}
+
+	crdListerRequests.WithLabelValues(operation, path, result).Inc()
+	crdListerDuration.WithLabelValues(operation, path).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestRecordCRDLookup(t *testing.T) {
+	crdListerRequests.Reset()
+	crdListerDuration.Reset()
+
+	requests := func(path, result string) float64 {
+		v, err := testutil.GetCounterMetricValue(crdListerRequests.WithLabelValues("get", path, result))
+		require.NoError(t, err)
+		return v
+	}
+
+	recordCRDLookup("get", crdResolutionSystem, time.Now(), nil)
+	recordCRDLookup("get", crdResolutionLocal, time.Now(), nil)
+	recordCRDLookup("get", crdResolutionLocal, time.Now(), apierrors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), "widgets.example.io"))
+	recordCRDLookup("get", crdResolutionIdentity, time.Now().Add(-time.Second), errors.New("boom"))
+
+	require.Equal(t, float64(1), requests(crdResolutionSystem, "found"))
+	require.Equal(t, float64(1), requests(crdResolutionLocal, "found"))
+	require.Equal(t, float64(1), requests(crdResolutionLocal, "not_found"))
+	require.Equal(t, float64(1), requests(crdResolutionIdentity, "error"))
+	require.Equal(t, float64(0), requests(crdResolutionWildcardPartial, "found"))
+
+	v, err := testutil.GetHistogramMetricValue(crdListerDuration.WithLabelValues("get", crdResolutionIdentity))
+	require.NoError(t, err)
+	require.InDelta(t, 1, v, 0.5)
+}
@@ -17,17 +17,29 @@ limitations under the License.
 package server
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
 	"github.com/stretchr/testify/require"
 
 	apiextensionshelpers "k8s.io/apiextensions-apiserver/pkg/apihelpers"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kcpapiextensionsv1listers "k8s.io/apiextensions-apiserver/pkg/client/kcp/listers/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/kcp-dev/kcp/pkg/admission/reservedcrdgroups"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	apisv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 )
 
 func TestSystemCRDsLogicalClusterName(t *testing.T) {
@@ -131,3 +143,367 @@ func TestDecorateCRDWithBinding(t *testing.T) {
 		})
 	}
 }
+
+func TestGetForWildcardPartialMetadata(t *testing.T) {
+	newCRD := func(cluster string, versions ...string) *apiextensionsv1.CustomResourceDefinition {
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "widgets.example.io",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "example.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+				Scope: apiextensionsv1.NamespaceScoped,
+			},
+		}
+		for _, v := range versions {
+			crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{
+				Name:    v,
+				Served:  true,
+				Storage: v == versions[0],
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{Type: "object", Description: cluster},
+				},
+			})
+		}
+		return crd
+	}
+
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{byGroupResourceName: indexCRDByGroupResourceName})
+	require.NoError(t, indexer.Add(newCRD("root:c", "v1")))
+	require.NoError(t, indexer.Add(newCRD("root:a", "v1", "v2")))
+	require.NoError(t, indexer.Add(newCRD("root:b", "v1")))
+	lister := &apiBindingAwareCRDLister{apiBindingAwareCRDClusterLister: &apiBindingAwareCRDClusterLister{crdIndexer: indexer}}
+
+	crd, err := lister.getForWildcardPartialMetadata("widgets.example.io")
+	require.NoError(t, err)
+	require.Equal(t, "root:b", logicalcluster.From(crd).String(), "expected the lowest cluster of the most common pruned schema")
+
+	// schemas do not matter for partial metadata
+	require.Equal(t, partialMetadataSchemaHash(newCRD("root:b", "v1")), partialMetadataSchemaHash(newCRD("root:c", "v1")))
+	require.NotEqual(t, partialMetadataSchemaHash(newCRD("root:a", "v1", "v2")), partialMetadataSchemaHash(newCRD("root:c", "v1")))
+}
+
+func TestLocalCRDShadowingAPIBinding(t *testing.T) {
+	boundCRD := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "uid-widgets",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: apibinding.ShadowWorkspaceName.String()},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+		},
+	}
+	localCRD := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "widgets.example.io",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:ws"},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: "Widget"},
+		},
+	}
+
+	for _, tc := range []struct {
+		name       string
+		policy     apisv1alpha1.APIBindingLocalCRDPolicy
+		wantLocal  bool
+		wantListed string
+	}{
+		{name: "bound CRD takes precedence by default", wantLocal: false, wantListed: "uid-widgets"},
+		{name: "bound CRD takes precedence with Reject", policy: apisv1alpha1.APIBindingLocalCRDPolicyReject, wantLocal: false, wantListed: "uid-widgets"},
+		{name: "local CRD shadows the bound CRD with Shadow", policy: apisv1alpha1.APIBindingLocalCRDPolicyShadow, wantLocal: true, wantListed: "widgets.example.io"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			apiBinding := &apisv1alpha1.APIBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "widgets",
+					Annotations: map[string]string{logicalcluster.AnnotationKey: "root:ws"},
+				},
+				Spec: apisv1alpha1.APIBindingSpec{LocalCRDPolicy: tc.policy},
+				Status: apisv1alpha1.APIBindingStatus{
+					BoundResources: []apisv1alpha1.BoundAPIResource{{
+						Group:    "example.io",
+						Resource: "widgets",
+						Schema:   apisv1alpha1.BoundAPIResourceSchema{UID: "uid-widgets", IdentityHash: "hash"},
+					}},
+				},
+			}
+
+			crdIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+			require.NoError(t, crdIndexer.Add(boundCRD))
+			require.NoError(t, crdIndexer.Add(localCRD))
+			apiBindingIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+			require.NoError(t, apiBindingIndexer.Add(apiBinding))
+
+			lister := &apiBindingAwareCRDLister{
+				apiBindingAwareCRDClusterLister: &apiBindingAwareCRDClusterLister{
+					crdLister:        kcpapiextensionsv1listers.NewCustomResourceDefinitionClusterLister(crdIndexer),
+					apiBindingLister: apisv1alpha1listers.NewAPIBindingClusterLister(apiBindingIndexer),
+				},
+				cluster: logicalcluster.New("root:ws"),
+			}
+
+			crd, err := lister.get(context.Background(), logicalcluster.New("root:ws"), "widgets.example.io", "")
+			require.NoError(t, err)
+			require.Equal(t, tc.wantLocal, crd.Name == "widgets.example.io", "unexpected CRD %s", crd.Name)
+
+			crds, err := lister.List(context.Background(), labels.Everything())
+			require.NoError(t, err)
+			require.Len(t, crds, 1)
+			require.Equal(t, tc.wantListed, crds[0].Name)
+		})
+	}
+}
+
+func TestNamespaceRestrictedAPIBinding(t *testing.T) {
+	newCRD := func(name, plural string, scope apiextensionsv1.ResourceScope) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{logicalcluster.AnnotationKey: apibinding.ShadowWorkspaceName.String()},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "example.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: plural},
+				Scope: scope,
+			},
+		}
+	}
+	apiBinding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "widgets",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:ws"},
+		},
+		Spec: apisv1alpha1.APIBindingSpec{Namespaces: []string{"team-a"}},
+		Status: apisv1alpha1.APIBindingStatus{
+			BoundResources: []apisv1alpha1.BoundAPIResource{
+				{Group: "example.io", Resource: "widgets", Schema: apisv1alpha1.BoundAPIResourceSchema{UID: "uid-widgets", IdentityHash: "hash"}},
+				{Group: "example.io", Resource: "gadgets", Schema: apisv1alpha1.BoundAPIResourceSchema{UID: "uid-gadgets", IdentityHash: "hash"}},
+			},
+		},
+	}
+
+	crdIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+	require.NoError(t, crdIndexer.Add(newCRD("uid-widgets", "widgets", apiextensionsv1.NamespaceScoped)))
+	require.NoError(t, crdIndexer.Add(newCRD("uid-gadgets", "gadgets", apiextensionsv1.ClusterScoped)))
+	apiBindingIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+	require.NoError(t, apiBindingIndexer.Add(apiBinding))
+
+	lister := &apiBindingAwareCRDLister{
+		apiBindingAwareCRDClusterLister: &apiBindingAwareCRDClusterLister{
+			crdLister:        kcpapiextensionsv1listers.NewCustomResourceDefinitionClusterLister(crdIndexer),
+			apiBindingLister: apisv1alpha1listers.NewAPIBindingClusterLister(apiBindingIndexer),
+		},
+		cluster: logicalcluster.New("root:ws"),
+	}
+	inNamespace := func(ns string) context.Context {
+		return request.WithRequestInfo(context.Background(), &request.RequestInfo{IsResourceRequest: true, Namespace: ns})
+	}
+
+	for _, tc := range []struct {
+		name    string
+		ctx     context.Context
+		crdName string
+		wantErr bool
+	}{
+		{name: "namespaced resource in a bound namespace", ctx: inNamespace("team-a"), crdName: "widgets.example.io"},
+		{name: "namespaced resource in another namespace", ctx: inNamespace("team-b"), crdName: "widgets.example.io", wantErr: true},
+		{name: "namespaced resource across all namespaces", ctx: inNamespace(""), crdName: "widgets.example.io"},
+		{name: "cluster-scoped resource", ctx: inNamespace(""), crdName: "gadgets.example.io", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := lister.get(tc.ctx, logicalcluster.New("root:ws"), tc.crdName, "")
+			if tc.wantErr {
+				require.True(t, apierrors.IsNotFound(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	crds, err := lister.List(context.Background(), labels.Everything())
+	require.NoError(t, err)
+	require.Len(t, crds, 1, "cluster-scoped resources are not discovered")
+	require.Equal(t, "uid-widgets", crds[0].Name)
+}
+
+func TestBoundCRDUnavailable(t *testing.T) {
+	apiBinding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "widgets",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:ws"},
+		},
+		Status: apisv1alpha1.APIBindingStatus{
+			BoundResources: []apisv1alpha1.BoundAPIResource{{
+				Group:    "example.io",
+				Resource: "widgets",
+				Schema:   apisv1alpha1.BoundAPIResourceSchema{UID: "uid-widgets", IdentityHash: "hash"},
+			}},
+			Conditions: conditionsv1alpha1.Conditions{{
+				Type:    apisv1alpha1.InitialBindingCompleted,
+				Status:  "False",
+				Reason:  apisv1alpha1.WaitingForEstablishedReason,
+				Message: "waiting for the CRDs to be established",
+			}},
+		},
+	}
+
+	crdIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+	apiBindingIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{kcpcache.ClusterIndexName: kcpcache.ClusterIndexFunc})
+	require.NoError(t, apiBindingIndexer.Add(apiBinding))
+
+	lister := &apiBindingAwareCRDLister{
+		apiBindingAwareCRDClusterLister: &apiBindingAwareCRDClusterLister{
+			crdLister:        kcpapiextensionsv1listers.NewCustomResourceDefinitionClusterLister(crdIndexer),
+			apiBindingLister: apisv1alpha1listers.NewAPIBindingClusterLister(apiBindingIndexer),
+		},
+		cluster: logicalcluster.New("root:ws"),
+	}
+
+	_, err := lister.get(context.Background(), logicalcluster.New("root:ws"), "widgets.example.io", "")
+	require.True(t, apierrors.IsServiceUnavailable(err), "unexpected error: %v", err)
+	require.Contains(t, err.Error(), "APIBinding root:ws|widgets is not InitialBindingCompleted (WaitingForEstablished: waiting for the CRDs to be established)")
+	require.Contains(t, err.Error(), "schema UID uid-widgets of APIBinding root:ws|widgets is missing in shadow workspace")
+
+	status := err.(apierrors.APIStatus).Status()
+	require.NotNil(t, status.Details)
+	var causes []metav1.CauseType
+	for _, c := range status.Details.Causes {
+		causes = append(causes, c.Type)
+	}
+	require.Equal(t, []metav1.CauseType{CauseTypeAPIBindingNotBound, CauseTypeBoundSchemaMissing}, causes)
+
+	// with the binding completed, only the missing schema is a cause
+	apiBinding.Status.Conditions[0].Status = "True"
+	_, err = lister.get(context.Background(), logicalcluster.New("root:ws"), "widgets.example.io", "")
+	require.Len(t, err.(apierrors.APIStatus).Status().Details.Causes, 1)
+}
+
+func TestCRDResolutionEventRecorderShouldRecord(t *testing.T) {
+	now := time.Now()
+	r := newCRDResolutionEventRecorder(nil)
+	r.now = func() time.Time { return now }
+
+	require.True(t, r.shouldRecord("a"))
+	require.False(t, r.shouldRecord("a"), "identical events are recorded once per interval")
+	require.True(t, r.shouldRecord("b"))
+
+	now = now.Add(crdResolutionEventInterval)
+	require.True(t, r.shouldRecord("a"), "events are recorded again after the interval")
+}
+
+func TestWithCommonPrinterColumns(t *testing.T) {
+	age := apiextensionsv1.CustomResourceColumnDefinition{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"}
+	size := apiextensionsv1.CustomResourceColumnDefinition{Name: "Size", Type: "integer", JSONPath: ".spec.size"}
+	sizeString := apiextensionsv1.CustomResourceColumnDefinition{Name: "Size", Type: "string", JSONPath: ".spec.size"}
+	color := apiextensionsv1.CustomResourceColumnDefinition{Name: "Color", Type: "string", JSONPath: ".spec.color"}
+
+	newCRD := func(cluster string, columns ...apiextensionsv1.CustomResourceColumnDefinition) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "widgets.example.io",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1", Served: true, Storage: true, AdditionalPrinterColumns: columns},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		crds []*apiextensionsv1.CustomResourceDefinition
+		want []apiextensionsv1.CustomResourceColumnDefinition
+	}{
+		"identical columns": {
+			crds: []*apiextensionsv1.CustomResourceDefinition{newCRD("root:a", size, age), newCRD("root:b", size, age)},
+			want: []apiextensionsv1.CustomResourceColumnDefinition{size, age},
+		},
+		"columns missing in another CRD are dropped": {
+			crds: []*apiextensionsv1.CustomResourceDefinition{newCRD("root:a", size, color, age), newCRD("root:b", age, size)},
+			want: []apiextensionsv1.CustomResourceColumnDefinition{size, age},
+		},
+		"columns with a different type are dropped": {
+			crds: []*apiextensionsv1.CustomResourceDefinition{newCRD("root:a", size, age), newCRD("root:b", sizeString, age)},
+			want: []apiextensionsv1.CustomResourceColumnDefinition{age},
+		},
+		"no common columns": {
+			crds: []*apiextensionsv1.CustomResourceDefinition{newCRD("root:a", size), newCRD("root:b", color)},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			objs := make([]interface{}, 0, len(tc.crds))
+			for _, crd := range tc.crds {
+				objs = append(objs, crd)
+			}
+			original := tc.crds[0].DeepCopy()
+
+			got := withCommonPrinterColumns(tc.crds[0], objs)
+			require.Equal(t, tc.want, got.Spec.Versions[0].AdditionalPrinterColumns)
+			require.Equal(t, original, tc.crds[0], "input CRD must not be mutated")
+		})
+	}
+}
+
+func TestSelectAPIBindingForIdentityWildcard(t *testing.T) {
+	now := metav1.Now()
+	newBinding := func(cluster, schemaUID string, deleting bool) *apisv1alpha1.APIBinding {
+		b := &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "binding",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: cluster},
+			},
+			Status: apisv1alpha1.APIBindingStatus{
+				BoundResources: []apisv1alpha1.BoundAPIResource{{
+					Group:    "example.io",
+					Resource: "widgets",
+					Schema:   apisv1alpha1.BoundAPIResourceSchema{UID: schemaUID, IdentityHash: "id"},
+				}},
+			},
+		}
+		if deleting {
+			b.DeletionTimestamp = &now
+		}
+		return b
+	}
+
+	tests := map[string]struct {
+		bindings    []interface{}
+		wantCluster string
+		wantCRD     string
+	}{
+		"no binding": {},
+		"deleting bindings lose": {
+			bindings:    []interface{}{newBinding("root:a", "uid-1", true), newBinding("root:b", "uid-1", false)},
+			wantCluster: "root:b",
+			wantCRD:     "uid-1",
+		},
+		"most common bound CRD wins": {
+			bindings:    []interface{}{newBinding("root:a", "uid-1", false), newBinding("root:c", "uid-2", false), newBinding("root:b", "uid-2", false)},
+			wantCluster: "root:b",
+			wantCRD:     "uid-2",
+		},
+		"ties are broken by cluster": {
+			bindings:    []interface{}{newBinding("root:b", "uid-2", false), newBinding("root:a", "uid-1", false)},
+			wantCluster: "root:a",
+			wantCRD:     "uid-1",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apiBinding, crdName := selectAPIBindingForIdentityWildcard(tc.bindings, "id", "example.io", "widgets")
+			if tc.wantCluster == "" {
+				require.Nil(t, apiBinding)
+				return
+			}
+			require.Equal(t, tc.wantCluster, logicalcluster.From(apiBinding).String())
+			require.Equal(t, tc.wantCRD, crdName)
+		})
+	}
+}
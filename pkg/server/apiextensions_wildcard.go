@@ -0,0 +1,222 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// maxWildcardCRDsHashedPerLookup bounds the number of CRDs whose pruned schema is hashed during one wildcard
+// lookup. Hashes are kept across lookups, so a lookup hitting the limit is answered with 429 and the retry
+// continues where it stopped.
+var maxWildcardCRDsHashedPerLookup = 1000
+
+// wildcardCRDCache caches the CRD selected for wildcard requests by CRD name, together with the pruned schema
+// hash of every CRD it was selected from. A selection is reused as long as the set of CRDs of the name, by
+// logical cluster and resourceVersion, does not change. When it changes, only the changed CRDs are hashed
+// again. Hence, with many workspaces defining the same group and resource, a lookup is O(1) amortized
+// besides iterating the index.
+//
+// The zero value is ready to use.
+type wildcardCRDCache struct {
+	lock    sync.Mutex
+	entries map[string]*wildcardCRDCacheEntry
+}
+
+// wildcardCRDCacheEntry is immutable once stored.
+type wildcardCRDCacheEntry struct {
+	// key identifies the set of CRDs crd was selected from. Zero if crd is nil.
+	key resourceVersionSetKey
+	// crd is the selected CRD, or nil if the selection was not finished.
+	crd *apiextensionsv1.CustomResourceDefinition
+	// hashes are the pruned schema hashes by logical cluster.
+	hashes map[logicalcluster.Name]cachedSchemaHash
+}
+
+type cachedSchemaHash struct {
+	resourceVersion string
+	hash            string
+}
+
+// resourceVersionSetKey identifies a set of CRDs by logical cluster and resourceVersion, independently of
+// their order.
+type resourceVersionSetKey struct {
+	count int
+	sum   uint64
+}
+
+func newResourceVersionSetKey(objs []interface{}) resourceVersionSetKey {
+	key := resourceVersionSetKey{count: len(objs)}
+	for _, obj := range objs {
+		crd := obj.(*apiextensionsv1.CustomResourceDefinition)
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(logicalcluster.From(crd).String() + "\x00" + crd.ResourceVersion))
+		key.sum += h.Sum64()
+	}
+	return key
+}
+
+// get returns the CRD selected by selectFn from objs, the CRDs of name. selectFn is only called if the set of
+// CRDs changed since the last call, with the pruned schema hashes of the CRDs by logical cluster.
+func (c *wildcardCRDCache) get(
+	name string,
+	objs []interface{},
+	hashFn func(crd *apiextensionsv1.CustomResourceDefinition) string,
+	selectFn func(objs []interface{}, hashes map[logicalcluster.Name]cachedSchemaHash) *apiextensionsv1.CustomResourceDefinition,
+) (*apiextensionsv1.CustomResourceDefinition, error) {
+	key := newResourceVersionSetKey(objs)
+
+	c.lock.Lock()
+	entry := c.entries[name]
+	c.lock.Unlock()
+
+	if entry != nil && entry.crd != nil && entry.key == key {
+		crdListerWildcardHashedCRDs.Observe(0)
+		return entry.crd, nil
+	}
+
+	var previous map[logicalcluster.Name]cachedSchemaHash
+	if entry != nil {
+		previous = entry.hashes
+	}
+
+	hashes := make(map[logicalcluster.Name]cachedSchemaHash, len(objs))
+	hashed := 0
+	for _, obj := range objs {
+		crd := obj.(*apiextensionsv1.CustomResourceDefinition)
+		clusterName := logicalcluster.From(crd)
+		if h, found := previous[clusterName]; found && h.resourceVersion == crd.ResourceVersion {
+			hashes[clusterName] = h
+			continue
+		}
+
+		if hashed >= maxWildcardCRDsHashedPerLookup {
+			crdListerWildcardHashedCRDs.Observe(float64(hashed))
+
+			// keep what was hashed so far, for the retry
+			for clusterName, h := range previous {
+				if _, found := hashes[clusterName]; !found {
+					hashes[clusterName] = h
+				}
+			}
+			c.store(name, &wildcardCRDCacheEntry{hashes: hashes})
+
+			return nil, apierrors.NewTooManyRequests(fmt.Sprintf("too many CustomResourceDefinitions named %s to resolve a wildcard request at once", name), 1)
+		}
+
+		hashes[clusterName] = cachedSchemaHash{resourceVersion: crd.ResourceVersion, hash: hashFn(crd)}
+		hashed++
+	}
+	crdListerWildcardHashedCRDs.Observe(float64(hashed))
+
+	crd := selectFn(objs, hashes)
+	c.store(name, &wildcardCRDCacheEntry{key: key, crd: crd, hashes: hashes})
+
+	return crd, nil
+}
+
+func (c *wildcardCRDCache) store(name string, entry *wildcardCRDCacheEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[string]*wildcardCRDCacheEntry{}
+	}
+	c.entries[name] = entry
+}
+
+// partialMetadataSchemaHashes holds the pruned schema hash of every CRD, computed on CRD events such that wildcard
+// lookups do not have to marshal and hash CRDs.
+type partialMetadataSchemaHashes struct {
+	lock   sync.RWMutex
+	hashes map[string]cachedSchemaHash
+}
+
+func newPartialMetadataSchemaHashes() *partialMetadataSchemaHashes {
+	return &partialMetadataSchemaHashes{
+		hashes: map[string]cachedSchemaHash{},
+	}
+}
+
+// eventHandler returns the handler to register with the CRD informer.
+func (h *partialMetadataSchemaHashes) eventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.update,
+		UpdateFunc: func(_, obj interface{}) { h.update(obj) },
+		DeleteFunc: h.delete,
+	}
+}
+
+func (h *partialMetadataSchemaHashes) update(obj interface{}) {
+	crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+	if !ok {
+		return
+	}
+	key, err := kcpcache.MetaClusterNamespaceKeyFunc(crd)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	hash := cachedSchemaHash{resourceVersion: crd.ResourceVersion, hash: partialMetadataSchemaHash(crd)}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.hashes[key] = hash
+}
+
+func (h *partialMetadataSchemaHashes) delete(obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.hashes, key)
+}
+
+// get returns the pruned schema hash of crd. It is only computed if the event of this resourceVersion of crd has
+// not been handled yet, or if h is nil.
+func (h *partialMetadataSchemaHashes) get(crd *apiextensionsv1.CustomResourceDefinition) string {
+	if h == nil {
+		return partialMetadataSchemaHash(crd)
+	}
+	key, err := kcpcache.MetaClusterNamespaceKeyFunc(crd)
+	if err != nil {
+		return partialMetadataSchemaHash(crd)
+	}
+
+	h.lock.RLock()
+	hash, found := h.hashes[key]
+	h.lock.RUnlock()
+
+	if found && hash.resourceVersion == crd.ResourceVersion {
+		return hash.hash
+	}
+	return partialMetadataSchemaHash(crd)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWildcardCRDCache(t *testing.T) {
+	newCRD := func(cluster, resourceVersion string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "widgets.example.io",
+				ResourceVersion: resourceVersion,
+				Annotations:     map[string]string{logicalcluster.AnnotationKey: cluster},
+			},
+		}
+	}
+
+	var objs []interface{}
+	for i := 0; i < 5; i++ {
+		objs = append(objs, newCRD(fmt.Sprintf("root:ws%d", i), "1"))
+	}
+
+	var hashed, selected int
+	hashFn := func(crd *apiextensionsv1.CustomResourceDefinition) string {
+		hashed++
+		return crd.ResourceVersion
+	}
+	selectFn := func(objs []interface{}, hashes map[logicalcluster.Name]cachedSchemaHash) *apiextensionsv1.CustomResourceDefinition {
+		selected++
+		require.Len(t, hashes, len(objs))
+		return objs[0].(*apiextensionsv1.CustomResourceDefinition)
+	}
+
+	var c wildcardCRDCache
+
+	crd, err := c.get("widgets.example.io", objs, hashFn, selectFn)
+	require.NoError(t, err)
+	require.Equal(t, "root:ws0", logicalcluster.From(crd).String())
+	require.Equal(t, 5, hashed)
+	require.Equal(t, 1, selected)
+
+	t.Log("Unchanged CRDs, in a different order, are neither hashed nor selected again")
+	reordered := append([]interface{}{objs[4]}, objs[:4]...)
+	crd, err = c.get("widgets.example.io", reordered, hashFn, selectFn)
+	require.NoError(t, err)
+	require.Equal(t, "root:ws0", logicalcluster.From(crd).String())
+	require.Equal(t, 5, hashed)
+	require.Equal(t, 1, selected)
+
+	t.Log("Only the changed CRD is hashed again")
+	objs[2] = newCRD("root:ws2", "2")
+	_, err = c.get("widgets.example.io", objs, hashFn, selectFn)
+	require.NoError(t, err)
+	require.Equal(t, 6, hashed)
+	require.Equal(t, 2, selected)
+
+	t.Log("Lookups hashing too many CRDs are rejected, but make progress")
+	old := maxWildcardCRDsHashedPerLookup
+	maxWildcardCRDsHashedPerLookup = 2
+	t.Cleanup(func() { maxWildcardCRDsHashedPerLookup = old })
+	for i := 5; i < 8; i++ {
+		objs = append(objs, newCRD(fmt.Sprintf("root:ws%d", i), "1"))
+	}
+	for i := range objs[:5] {
+		objs[i] = newCRD(fmt.Sprintf("root:ws%d", i), "3")
+	}
+
+	_, err = c.get("widgets.example.io", objs, hashFn, selectFn)
+	require.True(t, apierrors.IsTooManyRequests(err), "expected 429, got: %v", err)
+	require.Equal(t, 8, hashed)
+	_, err = c.get("widgets.example.io", objs, hashFn, selectFn)
+	require.True(t, apierrors.IsTooManyRequests(err), "expected 429, got: %v", err)
+	require.Equal(t, 10, hashed)
+	_, err = c.get("widgets.example.io", objs, hashFn, selectFn)
+	require.True(t, apierrors.IsTooManyRequests(err), "expected 429, got: %v", err)
+	require.Equal(t, 12, hashed)
+	_, err = c.get("widgets.example.io", objs, hashFn, selectFn)
+	require.NoError(t, err)
+	require.Equal(t, 14, hashed)
+	require.Equal(t, 3, selected)
+}
+
+func TestPartialMetadataSchemaHashes(t *testing.T) {
+	newCRD := func(resourceVersion, kind string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "widgets.example.io",
+				ResourceVersion: resourceVersion,
+				Annotations:     map[string]string{logicalcluster.AnnotationKey: "root:org"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "example.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Kind: kind},
+			},
+		}
+	}
+
+	h := newPartialMetadataSchemaHashes()
+	crd := newCRD("1", "Widget")
+	h.update(crd)
+
+	// the hash of the event is served, i.e. not computed again
+	h.hashes["root:org|widgets.example.io"] = cachedSchemaHash{resourceVersion: "1", hash: "from-event"}
+	require.Equal(t, "from-event", h.get(crd))
+
+	// lagging events are compensated by computing the hash
+	changed := newCRD("2", "Gadget")
+	require.Equal(t, partialMetadataSchemaHash(changed), h.get(changed))
+	require.NotEqual(t, partialMetadataSchemaHash(crd), h.get(changed))
+
+	h.update(changed)
+	require.Equal(t, cachedSchemaHash{resourceVersion: "2", hash: partialMetadataSchemaHash(changed)}, h.hashes["root:org|widgets.example.io"])
+
+	h.delete(changed)
+	require.Empty(t, h.hashes)
+
+	// without event handler, hashes are computed
+	var nilHashes *partialMetadataSchemaHashes
+	require.Equal(t, partialMetadataSchemaHash(crd), nilHashes.get(crd))
+}
@@ -27,9 +27,15 @@ import (
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	"github.com/kcp-dev/logicalcluster/v2"
 
+	corev1 "k8s.io/api/core/v1"
+	flowcontrolv1beta2 "k8s.io/api/flowcontrol/v1beta2"
 	apiextensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
 	kcpapiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/kcp/clientset/versioned"
 	kcpapiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/kcp/informers/externalversions"
+	"k8s.io/apiextensions-apiserver/pkg/kcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/endpoints/filters"
@@ -38,6 +44,7 @@ import (
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	serverstorage "k8s.io/apiserver/pkg/server/storage"
 	"k8s.io/apiserver/pkg/util/webhook"
+	kubernetesinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
@@ -48,6 +55,8 @@ import (
 
 	kcpadmissioninitializers "github.com/kcp-dev/kcp/pkg/admission/initializers"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/authorization"
 	bootstrappolicy "github.com/kcp-dev/kcp/pkg/authorization/bootstrap"
 	cacheclient "github.com/kcp-dev/kcp/pkg/cache/client"
@@ -58,6 +67,7 @@ import (
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacequota"
 	"github.com/kcp-dev/kcp/pkg/server/bootstrap"
 	kcpfilters "github.com/kcp-dev/kcp/pkg/server/filters"
 	kcpserveroptions "github.com/kcp-dev/kcp/pkg/server/options"
@@ -106,6 +116,11 @@ type ExtraConfig struct {
 	preHandlerChainMux   *handlerChainMuxes
 	quotaAdmissionStopCh chan struct{}
 
+	// WorkspaceStorageUsage is the etcd storage of the workspaces of this shard, as of the last scan.
+	WorkspaceStorageUsage *clusterworkspacequota.StorageUsage
+	// WorkspaceObjectCounts counts the objects of the workspaces of this shard by resource.
+	WorkspaceObjectCounts *clusterworkspacequota.ObjectCounts
+
 	// URL getters depending on genericspiserver.ExternalAddress which is initialized on server run
 	ShardBaseURL             func() string
 	ShardExternalURL         func() string
@@ -125,6 +140,11 @@ type ExtraConfig struct {
 	//
 	// TemporaryRootShardKcpSharedInformerFactory bring data from the root shard
 	TemporaryRootShardKcpSharedInformerFactory kcpinformers.SharedInformerFactory
+
+	// PerClusterKubeSharedInformerFactory is for controllers serving only a few logical clusters. Its informers
+	// list and watch single logical clusters, and are started and stopped per logical cluster by the controllers
+	// using them.
+	PerClusterKubeSharedInformerFactory *informer.PerClusterSharedInformerFactory[kubernetesinformers.SharedInformerFactory]
 }
 
 type completedConfig struct {
@@ -263,6 +283,7 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 		c.KcpClusterClient,
 		resyncPeriod,
 	)
+	c.PerClusterKubeSharedInformerFactory = informer.NewPerClusterKubeSharedInformerFactory(c.KubeClusterClient, resyncPeriod)
 	c.DeepSARClient, err = kcpkubernetesclientset.NewForConfig(authorization.WithDeepSARConfig(rest.CopyConfig(c.GenericConfig.LoopbackClientConfig)))
 	if err != nil {
 		return nil, err
@@ -284,6 +305,22 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 		return nil, err
 	}
 
+	// Setup dynamic discovery informers, early enough to be injected into admission plugins
+	c.DynamicDiscoverySharedInformerFactory, err = informer.NewDynamicDiscoverySharedInformerFactory(
+		c.GenericConfig.LoopbackClientConfig,
+		func(obj interface{}) bool { return true },
+		c.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
+		indexers.AppendOrDie(
+			cache.Indexers{
+				indexers.BySyncerFinalizerKey:           indexers.IndexBySyncerFinalizerKey,
+				indexers.ByClusterResourceStateLabelKey: indexers.IndexByClusterResourceStateLabelKey,
+			},
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := opts.Authorization.ApplyTo(c.GenericConfig, c.KubeSharedInformerFactory, c.KcpSharedInformerFactory); err != nil {
 		return nil, err
 	}
@@ -292,6 +329,10 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	c.GenericConfig.Authentication.Authenticator = WithServiceAccountClusterBinding(c.GenericConfig.Authentication.Authenticator)
+	if kcpfeatures.DefaultFeatureGate.Enabled(kcpfeatures.WorkspaceAuthentication) {
+		c.GenericConfig.Authentication.Authenticator = WithWorkspaceAuthentication(c.GenericConfig.Authentication.Authenticator, c.KcpSharedInformerFactory.Tenancy().V1alpha1().WorkspaceAuthenticationConfigurations(), opts.Extra.WorkspaceAuthenticationAllowPrivateNetworks)
+	}
 	if sets.NewString(opts.Extra.BatteriesIncluded...).Has(batteries.User) {
 		c.userToken = userToken
 	}
@@ -328,12 +369,91 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 	// is called multiple times, but only one of the handler chain will actually be used. Hence, we wrap it
 	// to give handlers below one mux.Handle func to call.
 	c.preHandlerChainMux = &handlerChainMuxes{}
+	shardInformer := c.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceShards()
+	if len(c.Options.Extra.RootShardKubeconfigFile) > 0 {
+		// shards only exist on the root shard
+		shardInformer = c.TemporaryRootShardKcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceShards()
+	}
+	namespaceLister := c.KubeSharedInformerFactory.Core().V1().Namespaces().Lister()
+	flowSchemaLister := c.KubeSharedInformerFactory.Flowcontrol().V1beta2().FlowSchemas().Lister()
+	priorityLevelLister := c.KubeSharedInformerFactory.Flowcontrol().V1beta2().PriorityLevelConfigurations().Lister()
+	syncTargetIndexer := c.KcpSharedInformerFactory.Workload().V1alpha1().SyncTargets().Informer().GetIndexer()
+	clusterWorkspaceLister := c.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces().Lister()
+	clusterWorkspaceIndexer := c.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces().Informer().GetIndexer()
+	indexers.AddIfNotPresentOrDie(clusterWorkspaceIndexer, cache.Indexers{
+		indexers.ClusterWorkspacesByAliasPath: indexers.IndexClusterWorkspacesByAliasPath,
+	})
+	clusterWorkspaceTypeLister := c.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceTypes().Lister()
+	apiBindingIndexer := c.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings().Informer().GetIndexer()
+	indexers.AddIfNotPresentOrDie(apiBindingIndexer, cache.Indexers{
+		indexers.APIBindingByBoundResources: indexers.IndexAPIBindingByBoundResources,
+	})
 	c.GenericConfig.BuildHandlerChainFunc = func(apiHandler http.Handler, genericConfig *genericapiserver.Config) (secure http.Handler) {
+		syncerTunneler := tunneler.NewTunneler()
+
+		apiHandler = WithAggregatedDiscovery(apiHandler, func() kcp.ClusterAwareCRDClusterLister {
+			// the CRD lister is set up after the handler chain func, but before it is called
+			return c.ApiExtensions.ExtraConfig.ClusterAwareCRDLister
+		})
+		apiHandler = WithOpenAPIV3(apiHandler, func() kcp.ClusterAwareCRDClusterLister {
+			return c.ApiExtensions.ExtraConfig.ClusterAwareCRDLister
+		})
+		apiHandler = WithShardDiscovery(apiHandler, shardInformer.Lister())
+		apiHandler = WithWorkspaceShard(apiHandler, opts.Extra.ShardName, shardInformer.Lister())
 		apiHandler = WithWildcardListWatchGuard(apiHandler)
+		apiHandler = WithAuditEventWorkspaceAnnotations(apiHandler, opts.Extra.ShardName,
+			func(clusterName logicalcluster.Name, gr schema.GroupResource) string {
+				bindings, err := indexers.ByIndex[*apisv1alpha1.APIBinding](apiBindingIndexer, indexers.APIBindingByBoundResources, indexers.APIBindingBoundResourceValue(clusterName, gr.Group, gr.Resource))
+				if err != nil || len(bindings) != 1 {
+					return ""
+				}
+				for _, r := range bindings[0].Status.BoundResources {
+					if r.Group == gr.Group && r.Resource == gr.Resource {
+						return r.Schema.IdentityHash
+					}
+				}
+				return ""
+			},
+		)
 		apiHandler = WithRequestIdentity(apiHandler)
+		if kcpfeatures.DefaultFeatureGate.Enabled(kcpfeatures.SyncerTunnel) {
+			apiHandler = syncerTunneler.WithSubresourceProxy(apiHandler,
+				func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error) {
+					return namespaceLister.Cluster(clusterName).Get(name)
+				},
+				func(syncTargetKey string) (*workloadv1alpha1.SyncTarget, error) {
+					syncTargets, err := indexers.ByIndex[*workloadv1alpha1.SyncTarget](syncTargetIndexer, indexers.SyncTargetsBySyncTargetKey, syncTargetKey)
+					if err != nil {
+						return nil, err
+					}
+					if len(syncTargets) != 1 {
+						return nil, apierrors.NewServiceUnavailable(fmt.Sprintf("sync target %q not found", syncTargetKey))
+					}
+					return syncTargets[0], nil
+				},
+			)
+		}
+		if kcpfeatures.DefaultFeatureGate.Enabled(kcpfeatures.WorkspacePriorityAndFairness) {
+			apiHandler = WithWorkspacePriorityAndFairness(apiHandler, genericConfig.LongRunningFunc,
+				func(clusterName logicalcluster.Name) ([]*flowcontrolv1beta2.FlowSchema, error) {
+					return flowSchemaLister.Cluster(clusterName).List(labels.Everything())
+				},
+				func(clusterName logicalcluster.Name, name string) (*flowcontrolv1beta2.PriorityLevelConfiguration, error) {
+					return priorityLevelLister.Cluster(clusterName).Get(name)
+				},
+			)
+		}
 		apiHandler = authorization.WithDeepSubjectAccessReview(apiHandler)
 
 		apiHandler = genericapiserver.DefaultBuildHandlerChainFromAuthz(apiHandler, genericConfig)
+		apiHandler = WithWorkspaceAuditPolicy(apiHandler, genericConfig.AuditBackend, genericConfig.AuditPolicyRuleEvaluator, genericConfig.LongRunningFunc,
+			func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+				return clusterWorkspaceLister.Cluster(clusterName).Get(name)
+			},
+			func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+				return clusterWorkspaceTypeLister.Cluster(clusterName).Get(name)
+			},
+		)
 
 		if opts.HomeWorkspaces.Enabled {
 			apiHandler = WithHomeWorkspaces(
@@ -367,10 +487,11 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 		apiHandler = mux
 
 		if kcpfeatures.DefaultFeatureGate.Enabled(kcpfeatures.SyncerTunnel) {
-			apiHandler = tunneler.WithSyncerTunnel(apiHandler)
+			apiHandler = syncerTunneler.WithSyncerTunnel(apiHandler)
 		}
 
 		apiHandler = WithWorkspaceProjection(apiHandler)
+		apiHandler = WithWorkspaceAliases(apiHandler, clusterWorkspaceLister, clusterWorkspaceIndexer)
 		apiHandler = kcpfilters.WithAuditEventClusterAnnotation(apiHandler)
 		apiHandler = WithAuditAnnotation(apiHandler) // Must run before any audit annotation is made
 		apiHandler = kcpfilters.WithClusterScope(apiHandler)
@@ -387,9 +508,12 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 	quotaConfiguration := generic.NewConfiguration(nil, quotainstall.DefaultIgnoredResources())
 
 	c.ExtraConfig.quotaAdmissionStopCh = make(chan struct{})
+	c.WorkspaceStorageUsage = clusterworkspacequota.NewStorageUsage()
+	c.WorkspaceObjectCounts = clusterworkspacequota.NewObjectCounts(c.DynamicDiscoverySharedInformerFactory)
 
 	admissionPluginInitializers := []admission.PluginInitializer{
 		kcpadmissioninitializers.NewKcpInformersInitializer(c.KcpSharedInformerFactory),
+		kcpadmissioninitializers.NewApiExtensionsInformersInitializer(c.ApiExtensionsSharedInformerFactory),
 		kcpadmissioninitializers.NewKubeClusterClientInitializer(c.KubeClusterClient),
 		kcpadmissioninitializers.NewKcpClusterClientInitializer(c.KcpClusterClient),
 		kcpadmissioninitializers.NewDeepSARClientInitializer(c.DeepSARClient),
@@ -397,6 +521,11 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 		// with the default secure port, when the config is later completed.
 		kcpadmissioninitializers.NewKubeQuotaConfigurationInitializer(quotaConfiguration),
 		kcpadmissioninitializers.NewServerShutdownInitializer(c.quotaAdmissionStopCh),
+		kcpadmissioninitializers.NewDynamicDiscoverySharedInformerFactoryInitializer(c.DynamicDiscoverySharedInformerFactory),
+		kcpadmissioninitializers.NewShardNameInitializer(opts.Extra.ShardName),
+		kcpadmissioninitializers.NewWorkspaceStorageUsageInitializer(c.WorkspaceStorageUsage),
+		kcpadmissioninitializers.NewWorkspaceObjectCountsInitializer(c.WorkspaceObjectCounts),
+		kcpadmissioninitializers.NewWorkspaceAuthenticationAllowPrivateNetworksInitializer(opts.Extra.WorkspaceAuthenticationAllowPrivateNetworks),
 	}
 
 	c.ShardBaseURL = func() string {
@@ -422,6 +551,8 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	// let WorkspaceRestores preserve the UIDs of the restored objects
+	c.Apis.GenericConfig.RESTOptionsGetter = &restoreUIDRESTOptionsGetter{delegate: c.Apis.GenericConfig.RESTOptionsGetter}
 
 	// If additional API servers are added, they should be gated.
 	c.ApiExtensions, err = genericcontrolplane.CreateAPIExtensionsConfig(
@@ -446,10 +577,19 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 		return nil, fmt.Errorf("configure api extensions: %w", err)
 	}
 
-	c.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions().Informer().GetIndexer().AddIndexers(cache.Indexers{byGroupResourceName: indexCRDByGroupResourceName})       //nolint:errcheck
-	c.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings().Informer().GetIndexer().AddIndexers(cache.Indexers{byIdentityGroupResource: indexAPIBindingByIdentityGroupResource})                   //nolint:errcheck
+	c.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions().Informer().GetIndexer().AddIndexers(cache.Indexers{byGroupResourceName: indexCRDByGroupResourceName}) //nolint:errcheck
+	c.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings().Informer().GetIndexer().AddIndexers(cache.Indexers{
+		byIdentityGroupResource:        indexAPIBindingByIdentityGroupResource,
+		byIdentityGroupResourceCluster: indexAPIBindingByIdentityGroupResourceCluster,
+	}) //nolint:errcheck
 	c.KcpSharedInformerFactory.Workload().V1alpha1().SyncTargets().Informer().GetIndexer().AddIndexers(cache.Indexers{indexers.SyncTargetsBySyncTargetKey: indexers.IndexSyncTargetsBySyncTargetKey}) //nolint:errcheck
 
+	kcpfeatures.DefaultWorkspaceFeatureGate.SetClusterWorkspaceGetter(func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		return c.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces().Lister().Cluster(clusterName).Get(name)
+	})
+
+	partialMetadataHashes := newPartialMetadataSchemaHashes()
+	c.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions().Informer().AddEventHandler(partialMetadataHashes.eventHandler())
 	c.ApiExtensions.ExtraConfig.ClusterAwareCRDLister = &apiBindingAwareCRDClusterLister{
 		kcpClusterClient:  c.KcpClusterClient,
 		crdLister:         c.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions().Lister(),
@@ -461,7 +601,20 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
 			return c.KcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas().Lister().Cluster(clusterName).Get(name)
 		},
+		eventRecorder:         newCRDResolutionEventRecorder(c.KubeClusterClient),
+		partialMetadataHashes: partialMetadataHashes,
+	}
+	if opts.Extra.IdentityEncryptionConfigFile != "" {
+		transformers, err := loadIdentityTransformers(opts.Extra.IdentityEncryptionConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load identity encryption config: %w", err)
+		}
+		c.ApiExtensions.ExtraConfig.CRDRESTOptionsGetter = &identityEncryptionRESTOptionsGetter{
+			delegate:     c.ApiExtensions.ExtraConfig.CRDRESTOptionsGetter,
+			transformers: transformers,
+		}
 	}
+	c.ApiExtensions.ExtraConfig.CRDRESTOptionsGetter = &restoreUIDRESTOptionsGetter{delegate: c.ApiExtensions.ExtraConfig.CRDRESTOptionsGetter}
 	c.ApiExtensions.ExtraConfig.Client = c.ApiExtensionsClusterClient
 	c.ApiExtensions.ExtraConfig.Informers = c.ApiExtensionsSharedInformerFactory
 	c.ApiExtensions.ExtraConfig.TableConverterProvider = NewTableConverterProvider()
@@ -18,6 +18,7 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	_ "net/http/pprof"
@@ -28,17 +29,27 @@ import (
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	kcpmetadata "github.com/kcp-dev/client-go/metadata"
 	"github.com/kcp-dev/logicalcluster/v2"
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
 
 	corev1 "k8s.io/api/core/v1"
 	kcpapiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/kcp/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
 	certutil "k8s.io/client-go/util/cert"
 	"k8s.io/client-go/util/keyutil"
+	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/controller/certificates/rootcacertpublisher"
 	"k8s.io/kubernetes/pkg/controller/clusterroleaggregation"
@@ -46,30 +57,44 @@ import (
 	serviceaccountcontroller "k8s.io/kubernetes/pkg/controller/serviceaccount"
 	"k8s.io/kubernetes/pkg/serviceaccount"
 
+	configshard "github.com/kcp-dev/kcp/config/shard"
+	configsystemcrds "github.com/kcp-dev/kcp/config/system-crds"
 	configuniversal "github.com/kcp-dev/kcp/config/universal"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	bootstrappolicy "github.com/kcp-dev/kcp/pkg/authorization/bootstrap"
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	apisv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/informer"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibindingdeletion"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexport"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexportusage"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiresource"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiresourceschemaretention"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/crdcleanup"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/identitycache"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/permissionclaimlabel"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/systemcrds"
 	"github.com/kcp-dev/kcp/pkg/reconciler/cache/replication"
+	"github.com/kcp-dev/kcp/pkg/reconciler/eventttl"
 	"github.com/kcp-dev/kcp/pkg/reconciler/garbagecollector"
+	crossworkspacegarbagecollector "github.com/kcp-dev/kcp/pkg/reconciler/garbagecollector/crossworkspace"
 	"github.com/kcp-dev/kcp/pkg/reconciler/kubequota"
 	schedulinglocationstatus "github.com/kcp-dev/kcp/pkg/reconciler/scheduling/location"
 	schedulingplacement "github.com/kcp-dev/kcp/pkg/reconciler/scheduling/placement"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/bootstrap"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspace"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacedeletion"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacedeletion/deletion"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacequota"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspaceshard"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacetemplate"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacetombstone"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacetype"
 	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/initialization"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacebackup"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/workspacemigration"
 	workloadsapiexport "github.com/kcp-dev/kcp/pkg/reconciler/workload/apiexport"
 	workloadsapiexportcreate "github.com/kcp-dev/kcp/pkg/reconciler/workload/apiexportcreate"
 	"github.com/kcp-dev/kcp/pkg/reconciler/workload/heartbeat"
@@ -79,6 +104,7 @@ import (
 	synctargetcontroller "github.com/kcp-dev/kcp/pkg/reconciler/workload/synctarget"
 	"github.com/kcp-dev/kcp/pkg/reconciler/workload/synctargetexports"
 	initializingworkspacesbuilder "github.com/kcp-dev/kcp/pkg/virtual/initializingworkspaces/builder"
+	statuscoordination "github.com/kcp-dev/kcp/tmc/pkg/reconciler/coordination/status"
 )
 
 func postStartHookName(controllerName string) string {
@@ -290,7 +316,7 @@ func readCA(file string) ([]byte, error) {
 	return rootCA, err
 }
 
-func (s *Server) installWorkspaceDeletionController(ctx context.Context, config *rest.Config) error {
+func (s *Server) installWorkspaceDeletionController(ctx context.Context, config *rest.Config, ddsif *informer.DynamicDiscoverySharedInformerFactory) error {
 	config = rest.CopyConfig(config)
 	config = rest.AddUserAgent(config, clusterworkspacedeletion.ControllerName)
 	kcpClusterClient, err := kcpclientset.NewForConfig(config)
@@ -310,6 +336,21 @@ func (s *Server) installWorkspaceDeletionController(ctx context.Context, config
 		}
 		return discoveryClient.ServerPreferredResources()
 	}
+	countObjectsFn := func(clusterName logicalcluster.Name) (map[schema.GroupVersionResource]int, error) {
+		listers, notSynced := ddsif.Listers()
+		counts := make(map[schema.GroupVersionResource]int, len(listers))
+		for gvr, lister := range listers {
+			objs, err := lister.ByCluster(clusterName).List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			counts[gvr] = len(objs)
+		}
+		if len(notSynced) > 0 {
+			return counts, fmt.Errorf("informers not synced for %v", notSynced)
+		}
+		return counts, nil
+	}
 	kubeClusterClient, err := kcpkubernetesclientset.NewForConfig(config)
 	if err != nil {
 		return err
@@ -319,10 +360,22 @@ func (s *Server) installWorkspaceDeletionController(ctx context.Context, config
 		kcpClusterClient,
 		metadataClusterClient,
 		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceTombstones(),
 		discoverResourcesFn,
+		countObjectsFn,
+		s.Options.Controllers.WorkspaceDeletion.Retention,
+	)
+
+	// the tombstone controller runs without retention too, to delete the content of workspaces retained before.
+	workspaceTombstoneController := clusterworkspacetombstone.NewController(
+		kubeClusterClient,
+		kcpClusterClient,
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceTombstones(),
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		deletion.NewWorkspacedResourcesDeleter(metadataClusterClient, discoverResourcesFn, countObjectsFn),
 	)
 
-	return s.AddPostStartHook(postStartHookName(clusterworkspacedeletion.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+	if err := s.AddPostStartHook(postStartHookName(clusterworkspacedeletion.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
 		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(clusterworkspacedeletion.ControllerName))
 		if err := s.waitForSync(hookContext.StopCh); err != nil {
 			logger.Error(err, "failed to finish post-start-hook")
@@ -331,6 +384,19 @@ func (s *Server) installWorkspaceDeletionController(ctx context.Context, config
 
 		go workspaceDeletionController.Start(ctx, 10)
 		return nil
+	}); err != nil {
+		return err
+	}
+
+	return s.AddPostStartHook(postStartHookName(clusterworkspacetombstone.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(clusterworkspacetombstone.ControllerName))
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go workspaceTombstoneController.Start(ctx, 2)
+		return nil
 	})
 }
 
@@ -365,6 +431,35 @@ func (s *Server) installWorkloadResourceScheduler(ctx context.Context, config *r
 	})
 }
 
+func (s *Server) installStatusCoordinationController(ctx context.Context, config *rest.Config, ddsif *informer.DynamicDiscoverySharedInformerFactory) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, statuscoordination.ControllerName)
+	dynamicClusterClient, err := kcpdynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := statuscoordination.NewController(
+		dynamicClusterClient,
+		ddsif,
+		statuscoordination.DefaultStatusMergingStrategies,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.AddPostStartHook(postStartHookName(statuscoordination.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(statuscoordination.ControllerName))
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(ctx, 2)
+		return nil
+	})
+}
+
 func (s *Server) installWorkspaceScheduler(ctx context.Context, config *rest.Config) error {
 	// NOTE: keep `config` unaltered so there isn't cross-use between controllers installed here.
 	clusterWorkspaceConfig := rest.CopyConfig(config)
@@ -428,6 +523,25 @@ func (s *Server) installWorkspaceScheduler(ctx context.Context, config *rest.Con
 
 	}
 
+	// every shard reports its own load into its ClusterWorkspaceShard in the root workspace
+	loadReporter := clusterworkspaceshard.NewLoadReporter(
+		s.Options.Extra.ShardName,
+		s.RootShardKcpClusterClient,
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		legacyregistry.DefaultGatherer,
+	)
+	if err := s.AddPostStartHook(postStartHookName(clusterworkspaceshard.LoadReporterName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(clusterworkspaceshard.LoadReporterName))
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+		go loadReporter.Start(ctx)
+		return nil
+	}); err != nil {
+		return err
+	}
+
 	workspaceTypeConfig := rest.CopyConfig(config)
 	workspaceTypeConfig = rest.AddUserAgent(workspaceTypeConfig, clusterworkspacetype.ControllerName)
 	kcpClusterClient, err = kcpclientset.NewForConfig(workspaceTypeConfig)
@@ -582,6 +696,14 @@ func (s *Server) installAPIBindingController(ctx context.Context, config *rest.C
 		return err
 	}
 
+	// with the cache server enabled, APIExports and APIResourceSchemas of all shards become visible
+	var cacheApiExportInformer apisv1alpha1informers.APIExportClusterInformer
+	var cacheApiResourceSchemaInformer apisv1alpha1informers.APIResourceSchemaClusterInformer
+	if s.Options.Cache.Enabled {
+		cacheApiExportInformer = s.CacheKcpSharedInformerFactory.Apis().V1alpha1().APIExports()
+		cacheApiResourceSchemaInformer = s.CacheKcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas()
+	}
+
 	c, err := apibinding.NewController(
 		crdClusterClient,
 		kcpClusterClient,
@@ -592,6 +714,8 @@ func (s *Server) installAPIBindingController(ctx context.Context, config *rest.C
 		s.KcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas(),
 		s.TemporaryRootShardKcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
 		s.TemporaryRootShardKcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas(),
+		cacheApiExportInformer,
+		cacheApiResourceSchemaInformer,
 		s.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
 	)
 	if err != nil {
@@ -673,6 +797,7 @@ func (s *Server) installAPIBindingController(ctx context.Context, config *rest.C
 		dynamicClusterClient,
 		ddsif,
 		s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		s.KubeSharedInformerFactory.Core().V1().Namespaces(),
 	)
 	if err != nil {
 		return err
@@ -770,6 +895,68 @@ func (s *Server) installAPIBinderController(ctx context.Context, config *rest.Co
 	})
 }
 
+func (s *Server) installClusterWorkspaceTemplateController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	// Clients used to create the objects of the templates within the initializing workspace
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, clusterworkspacetemplate.ControllerName)
+	// TODO(ncdc): support standalone vw server when --shard-virtual-workspace-url is set
+	config.Host += initializingworkspacesbuilder.URLFor(tenancyv1alpha1.ClusterWorkspaceTemplatesInitializer)
+	initializingWorkspacesKcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	informerClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	workspaceClients := func(clusterName logicalcluster.Name) (dynamic.Interface, meta.RESTMapper, error) {
+		logicalClusterConfig := rest.CopyConfig(config)
+		logicalClusterConfig.Host += clusterName.Path()
+		dynamicClient, err := dynamic.NewForConfig(logicalClusterConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(logicalClusterConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dynamicClient, restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)), nil
+	}
+
+	// This informer factory is created here because it is specifically against the initializing workspaces virtual
+	// workspace.
+	initializingWorkspacesKcpInformers := kcpinformers.NewSharedInformerFactoryWithOptions(
+		informerClient,
+		resyncPeriod,
+	)
+
+	c, err := clusterworkspacetemplate.NewController(
+		initializingWorkspacesKcpClusterClient,
+		workspaceClients,
+		initializingWorkspacesKcpInformers.Tenancy().V1alpha1().ClusterWorkspaces(),
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceTypes(),
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceTemplates(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return server.AddPostStartHook(postStartHookName(clusterworkspacetemplate.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(clusterworkspacetemplate.ControllerName))
+
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		initializingWorkspacesKcpInformers.Start(hookContext.StopCh)
+		initializingWorkspacesKcpInformers.WaitForCacheSync(hookContext.StopCh)
+
+		go c.Start(goContext(hookContext), 2)
+		return nil
+	})
+}
+
 func (s *Server) installCRDCleanupController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
 	config = rest.CopyConfig(config)
 	config = rest.AddUserAgent(config, crdcleanup.ControllerName)
@@ -801,6 +988,124 @@ func (s *Server) installCRDCleanupController(ctx context.Context, config *rest.C
 	})
 }
 
+func (s *Server) installSystemCRDController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, systemcrds.ControllerName)
+
+	crdClusterClient, err := kcpapiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	dynamicClusterClient, err := kcpdynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	crds, err := configsystemcrds.CRDs()
+	if err != nil {
+		return err
+	}
+
+	c, err := systemcrds.NewController(
+		SystemCRDLogicalCluster,
+		crds,
+		crdClusterClient,
+		s.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
+		s.RootShardKcpClusterClient,
+		dynamicClusterClient,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := server.AddReadyzChecks(c); err != nil {
+		return err
+	}
+
+	return server.AddPostStartHook(postStartHookName(systemcrds.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(systemcrds.ControllerName))
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 1)
+
+		return nil
+	})
+}
+
+func (s *Server) installAPIResourceSchemaRetentionController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, apiresourceschemaretention.ControllerName)
+
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := apiresourceschemaretention.NewController(
+		kcpClusterClient,
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas(),
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return server.AddPostStartHook(postStartHookName(apiresourceschemaretention.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(apiresourceschemaretention.ControllerName))
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
+func (s *Server) installAPIExportUsageController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer, ddsif *informer.DynamicDiscoverySharedInformerFactory) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, apiexportusage.ControllerName)
+
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	// with the cache server enabled, the usage of APIExports is aggregated from the APIBindings of all shards
+	var cacheApiBindingInformer apisv1alpha1informers.APIBindingClusterInformer
+	if s.Options.Cache.Enabled {
+		cacheApiBindingInformer = s.CacheKcpSharedInformerFactory.Apis().V1alpha1().APIBindings()
+	}
+
+	c, err := apiexportusage.NewController(
+		kcpClusterClient,
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		cacheApiBindingInformer,
+		ddsif,
+	)
+	if err != nil {
+		return err
+	}
+
+	return server.AddPostStartHook(postStartHookName(apiexportusage.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(apiexportusage.ControllerName))
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
 func (s *Server) installAPIExportController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
 	config = rest.CopyConfig(config)
 	config = rest.AddUserAgent(config, apiexport.ControllerName)
@@ -823,6 +1128,7 @@ func (s *Server) installAPIExportController(ctx context.Context, config *rest.Co
 		s.KubeSharedInformerFactory.Core().V1().Namespaces(),
 		s.KubeSharedInformerFactory.Core().V1().Secrets(),
 		s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		s.Options.Controllers.APIExport.IdentityProviders(),
 	)
 	if err != nil {
 		return err
@@ -1160,6 +1466,219 @@ func (s *Server) installKubeQuotaController(
 	return nil
 }
 
+func (s *Server) installClusterWorkspaceQuotaController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, clusterworkspacequota.ControllerName)
+
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	// usage in status is informational, admission computes it on its own.
+	const usageResyncPeriod = 30 * time.Second
+
+	c, err := clusterworkspacequota.NewController(
+		kcpClusterClient,
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceQuotas(),
+		s.WorkspaceObjectCounts.Count,
+		clusterworkspacequota.NewListObjectsFunc(s.DynamicDiscoverySharedInformerFactory),
+		s.WorkspaceStorageUsage.Get,
+		usageResyncPeriod,
+	)
+	if err != nil {
+		return err
+	}
+
+	return server.AddPostStartHook(postStartHookName(clusterworkspacequota.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(clusterworkspacequota.ControllerName))
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
+func (s *Server) installWorkspaceStorageScanner(ctx context.Context, server *genericapiserver.GenericAPIServer) error {
+	const name = "kcp-workspace-storage-scanner"
+	etcdConfig := s.Options.GenericControlPlane.Etcd.StorageConfig
+
+	var tlsConfig *tls.Config
+	if etcdConfig.Transport.CertFile != "" || etcdConfig.Transport.TrustedCAFile != "" {
+		tlsInfo := transport.TLSInfo{
+			CertFile:      etcdConfig.Transport.CertFile,
+			KeyFile:       etcdConfig.Transport.KeyFile,
+			TrustedCAFile: etcdConfig.Transport.TrustedCAFile,
+		}
+		var err error
+		tlsConfig, err = tlsInfo.ClientConfig()
+		if err != nil {
+			return err
+		}
+	}
+
+	return server.AddPostStartHook(postStartHookName(name), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(name))
+		ctx := klog.NewContext(goContext(hookContext), logger)
+
+		// etcd is up once the server started, including an embedded one.
+		etcdClient, err := clientv3.New(clientv3.Config{
+			Endpoints:   etcdConfig.Transport.ServerList,
+			DialTimeout: 20 * time.Second,
+			TLS:         tlsConfig,
+			Context:     ctx,
+		})
+		if err != nil {
+			logger.Error(err, "failed to connect to etcd")
+			return nil // storage usage is informational unless limited, which is not enforced without a scan
+		}
+
+		go func() {
+			defer etcdClient.Close()
+			s.WorkspaceStorageUsage.Run(ctx, etcdClient.KV, etcdConfig.Prefix, s.Options.Controllers.WorkspaceQuota.StorageScanInterval)
+		}()
+
+		return nil
+	})
+}
+
+func (s *Server) installWorkspaceMigrationController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	logger := klog.FromContext(ctx).WithValues("controller", workspacemigration.ControllerName)
+	if len(s.Options.Extra.ShardKubeconfigFile) == 0 {
+		logger.Info("not starting controller, --shard-kubeconfig-file is not set")
+		return nil
+	}
+
+	// the shard kubeconfig holds admin credentials valid on all shards
+	shardConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(&clientcmd.ClientConfigLoadingRules{ExplicitPath: s.Options.Extra.ShardKubeconfigFile}, &clientcmd.ConfigOverrides{CurrentContext: "system:admin"}).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load the kubeconfig from: %s, err: %w", s.Options.Extra.ShardKubeconfigFile, err)
+	}
+	shardConfig = rest.AddUserAgent(shardConfig, workspacemigration.ControllerName)
+	shardClients := func(shard *tenancyv1alpha1.ClusterWorkspaceShard, clusterName logicalcluster.Name) (dynamic.Interface, discovery.DiscoveryInterface, error) {
+		config := rest.CopyConfig(shardConfig)
+		config.Host = shard.Spec.BaseURL + clusterName.Path()
+		dynamicClient, err := dynamic.NewForConfig(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dynamicClient, discoveryClient, nil
+	}
+
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, workspacemigration.ControllerName)
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := workspacemigration.NewController(
+		kcpClusterClient,
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().WorkspaceMigrations(),
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceShards(),
+		s.Options.Extra.ShardName,
+		shardClients,
+	)
+	if err != nil {
+		return err
+	}
+
+	return server.AddPostStartHook(postStartHookName(workspacemigration.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(workspacemigration.ControllerName))
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 1)
+
+		return nil
+	})
+}
+
+func (s *Server) installWorkspaceBackupController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	logger := klog.FromContext(ctx).WithValues("controller", workspacebackup.ControllerName)
+	if len(s.Options.Extra.WorkspaceBackupStore) == 0 {
+		logger.Info("not starting controller, --workspace-backup-store is not set")
+		return nil
+	}
+	store, err := workspacebackup.NewStore(s.Options.Extra.WorkspaceBackupStore)
+	if err != nil {
+		return err
+	}
+
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, workspacebackup.ControllerName)
+
+	// without the shard kubeconfig, only workspaces on this shard can be backed up and restored
+	shardConfig := config
+	if len(s.Options.Extra.ShardKubeconfigFile) > 0 {
+		shardConfig, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(&clientcmd.ClientConfigLoadingRules{ExplicitPath: s.Options.Extra.ShardKubeconfigFile}, &clientcmd.ConfigOverrides{CurrentContext: "system:admin"}).ClientConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load the kubeconfig from: %s, err: %w", s.Options.Extra.ShardKubeconfigFile, err)
+		}
+		shardConfig = rest.AddUserAgent(shardConfig, workspacebackup.ControllerName)
+	}
+	shardClients := func(shard *tenancyv1alpha1.ClusterWorkspaceShard, clusterName logicalcluster.Name) (dynamic.Interface, discovery.DiscoveryInterface, error) {
+		config := rest.CopyConfig(shardConfig)
+		if len(s.Options.Extra.ShardKubeconfigFile) > 0 {
+			config.Host = shard.Spec.BaseURL + clusterName.Path()
+		} else if shard.Name == s.Options.Extra.ShardName {
+			config.Host += clusterName.Path()
+		} else {
+			return nil, nil, fmt.Errorf("workspace %s is on shard %q, but --shard-kubeconfig-file is not set", clusterName, shard.Name)
+		}
+		dynamicClient, err := dynamic.NewForConfig(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dynamicClient, discoveryClient, nil
+	}
+
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := workspacebackup.NewController(
+		kcpClusterClient,
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().WorkspaceBackups(),
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().WorkspaceRestores(),
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaceShards(),
+		shardClients,
+		store,
+	)
+	if err != nil {
+		return err
+	}
+
+	return server.AddPostStartHook(postStartHookName(workspacebackup.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(workspacebackup.ControllerName))
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 1)
+
+		return nil
+	})
+}
+
 func (s *Server) installApiExportIdentityController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
 	if s.Options.Extra.ShardName == tenancyv1alpha1.RootShard {
 		return nil
@@ -1170,7 +1689,8 @@ func (s *Server) installApiExportIdentityController(ctx context.Context, config
 	if err != nil {
 		return err
 	}
-	c, err := identitycache.NewApiExportIdentityProviderController(kubeClusterClient, s.TemporaryRootShardKcpSharedInformerFactory.Apis().V1alpha1().APIExports(), s.KubeSharedInformerFactory.Core().V1().ConfigMaps())
+	systemShardKubeInformers := s.PerClusterKubeSharedInformerFactory.Cluster(configshard.SystemShardCluster)
+	c, err := identitycache.NewApiExportIdentityProviderController(kubeClusterClient, s.TemporaryRootShardKcpSharedInformerFactory.Apis().V1alpha1().APIExports(), systemShardKubeInformers.Core().V1().ConfigMaps())
 	if err != nil {
 		return err
 	}
@@ -1181,6 +1701,9 @@ func (s *Server) installApiExportIdentityController(ctx context.Context, config
 			return nil // don't klog.Fatal. This only happens when context is cancelled.
 		}
 
+		s.PerClusterKubeSharedInformerFactory.Start(configshard.SystemShardCluster)
+		s.PerClusterKubeSharedInformerFactory.WaitForCacheSync(configshard.SystemShardCluster, hookContext.StopCh)
+
 		go c.Start(goContext(hookContext), 1)
 		return nil
 	})
@@ -1262,6 +1785,65 @@ func (s *Server) installGarbageCollectorController(ctx context.Context, config *
 	})
 }
 
+func (s *Server) installCrossWorkspaceGarbageCollectorController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, crossworkspacegarbagecollector.ControllerName)
+
+	metadataClient, err := kcpmetadata.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c := crossworkspacegarbagecollector.NewController(
+		s.Options.Extra.ShardName,
+		metadataClient,
+		s.DynamicDiscoverySharedInformerFactory,
+		s.KcpSharedInformerFactory.Tenancy().V1alpha1().ClusterWorkspaces(),
+	)
+
+	return server.AddPostStartHook(postStartHookName(crossworkspacegarbagecollector.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(crossworkspacegarbagecollector.ControllerName))
+
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
+func (s *Server) installEventTTLController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, eventttl.ControllerName)
+
+	kubeClusterClient, err := kcpkubernetesclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c := eventttl.NewController(
+		kubeClusterClient,
+		s.DynamicDiscoverySharedInformerFactory,
+		s.Options.Controllers.EventTTL.TenantEventTTL,
+	)
+
+	return server.AddPostStartHook(postStartHookName(eventttl.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(eventttl.ControllerName))
+
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext))
+
+		return nil
+	})
+}
+
 func (s *Server) waitForSync(stop <-chan struct{}) error {
 	// Wait for shared informer factories to by synced.
 	// factory. Otherwise, informer list calls may go into backoff (before the CRDs are ready) and
@@ -26,16 +26,15 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/emicklei/go-restful"
 	"github.com/kcp-dev/logicalcluster/v2"
-	jwt2 "gopkg.in/square/go-jose.v2/jwt"
 
 	apiextensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
 	"k8s.io/apiextensions-apiserver/pkg/kcp"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -46,11 +45,15 @@ import (
 	apiserverdiscovery "k8s.io/apiserver/pkg/endpoints/discovery"
 	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
 	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/genericcontrolplane/aggregator"
 
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancyhelper "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1/helper"
 	tenancyv1beta1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1beta1"
+	tenancyv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/indexers"
 )
 
 var (
@@ -138,6 +141,53 @@ func WithWorkspaceProjection(apiHandler http.Handler) http.HandlerFunc {
 	}
 }
 
+// WithWorkspaceAliases rewrites the logical cluster of requests to workspace aliases to the logical
+// cluster of the aliased workspace. A workspace with the name of an alias always wins, and expired
+// aliases or aliases claimed by several workspaces do not resolve.
+func WithWorkspaceAliases(apiHandler http.Handler, clusterWorkspaceLister tenancyv1alpha1listers.ClusterWorkspaceClusterLister, clusterWorkspaceIndexer cache.Indexer) http.HandlerFunc {
+	lookupAlias := func(alias logicalcluster.Name) (logicalcluster.Name, bool) {
+		parent, name := alias.Split()
+		if _, err := clusterWorkspaceLister.Cluster(parent).Get(name); err == nil {
+			return logicalcluster.Name{}, false
+		}
+
+		workspaces, err := indexers.ByIndex[*tenancyv1alpha1.ClusterWorkspace](clusterWorkspaceIndexer, indexers.ClusterWorkspacesByAliasPath, alias.String())
+		if err != nil {
+			return logicalcluster.Name{}, false
+		}
+		now := time.Now()
+		var targets []logicalcluster.Name
+		for _, ws := range workspaces {
+			for _, active := range tenancyhelper.ActiveWorkspaceAliases(ws, now) {
+				if active == alias {
+					targets = append(targets, logicalcluster.From(ws).Join(ws.Name))
+				}
+			}
+		}
+		if len(targets) != 1 {
+			return logicalcluster.Name{}, false
+		}
+		return targets[0], true
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		cluster := request.ClusterFrom(req.Context())
+		if cluster == nil || cluster.Name.Empty() || cluster.Wildcard {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+
+		if resolved := tenancyhelper.ResolveWorkspaceAliases(cluster.Name, lookupAlias); resolved != cluster.Name {
+			klog.FromContext(req.Context()).WithValues("alias", cluster.Name, "cluster", resolved).V(4).Info("resolved workspace alias")
+			resolvedCluster := *cluster
+			resolvedCluster.Name = resolved
+			req = req.WithContext(request.WithCluster(req.Context(), resolvedCluster))
+		}
+
+		apiHandler.ServeHTTP(w, req)
+	}
+}
+
 func WithWildcardListWatchGuard(apiHandler http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		cluster := request.ClusterFrom(req.Context())
@@ -193,36 +243,19 @@ func WithInClusterServiceAccountRequestRewrite(handler http.Handler) http.Handle
 			return
 		}
 
-		prefix := "Bearer "
-		token := req.Header.Get("Authorization")
-		if !strings.HasPrefix(token, prefix) {
-			handler.ServeHTTP(w, req)
-			return
-		}
-		token = token[len(prefix):]
-
-		var claims map[string]interface{}
-		decoded, err := jwt2.ParseSigned(token)
-		if err != nil { // just ignore
+		token, ok := bearerToken(req)
+		if !ok {
 			handler.ServeHTTP(w, req)
 			return
 		}
-		if err = decoded.UnsafeClaimsWithoutVerification(&claims); err != nil {
+		clusterName, ok := serviceAccountTokenClusterName(token)
+		if !ok {
 			handler.ServeHTTP(w, req)
 			return
 		}
 
-		clusterName, ok, err := unstructured.NestedString(claims, "kubernetes.io", "clusterName") // bound
-		if err != nil || !ok {
-			clusterName, ok, err = unstructured.NestedString(claims, "kubernetes.io/serviceaccount/clusterName") // legacy
-			if err != nil || !ok {
-				handler.ServeHTTP(w, req)
-				return
-			}
-		}
-
-		req.URL.Path = path.Join("/clusters", clusterName, req.URL.Path)
-		req.RequestURI = path.Join("/clusters", clusterName, req.RequestURI)
+		req.URL.Path = path.Join("/clusters", clusterName.String(), req.URL.Path)
+		req.RequestURI = path.Join("/clusters", clusterName.String(), req.RequestURI)
 
 		handler.ServeHTTP(w, req)
 	})
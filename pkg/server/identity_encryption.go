@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/server/options/encryptionconfig"
+	"k8s.io/apiserver/pkg/storage/value"
+	"sigs.k8s.io/yaml"
+)
+
+// IdentityEncryptionConfiguration maps APIExport identities to encryption provider configurations.
+// Resources bound through one of the identities are encrypted at rest with the providers configured
+// for the respective resource in the configuration of that identity.
+type IdentityEncryptionConfiguration struct {
+	Identities []IdentityEncryption `json:"identities"`
+}
+
+// IdentityEncryption attaches an encryption provider configuration to an APIExport identity.
+type IdentityEncryption struct {
+	// IdentityHash is the identity hash the objects of the APIExport are stored under, i.e. status.storageIdentityHash
+	// of the APIExport if set, status.identityHash otherwise.
+	IdentityHash string `json:"identityHash"`
+	// EncryptionProviderConfig is the path of an EncryptionConfiguration file. Relative paths are
+	// resolved relative to the identity encryption configuration file.
+	EncryptionProviderConfig string `json:"encryptionProviderConfig"`
+}
+
+// loadIdentityTransformers reads the identity encryption configuration file and returns the storage transformers
+// by identity and resource.
+func loadIdentityTransformers(filename string) (map[string]map[schema.GroupResource]value.Transformer, error) {
+	bs, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var config IdentityEncryptionConfiguration
+	if err := yaml.UnmarshalStrict(bs, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	ret := make(map[string]map[schema.GroupResource]value.Transformer, len(config.Identities))
+	for _, identity := range config.Identities {
+		if identity.IdentityHash == "" || strings.Contains(identity.IdentityHash, "/") {
+			return nil, fmt.Errorf("invalid identity hash %q in %s", identity.IdentityHash, filename)
+		}
+		if _, found := ret[identity.IdentityHash]; found {
+			return nil, fmt.Errorf("duplicate identity hash %q in %s", identity.IdentityHash, filename)
+		}
+
+		providerConfig := identity.EncryptionProviderConfig
+		if !filepath.IsAbs(providerConfig) {
+			providerConfig = filepath.Join(filepath.Dir(filename), providerConfig)
+		}
+		transformers, err := encryptionconfig.GetTransformerOverrides(providerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load encryption provider config of identity %q: %w", identity.IdentityHash, err)
+		}
+		ret[identity.IdentityHash] = transformers
+	}
+	return ret, nil
+}
+
+// identityEncryptionRESTOptionsGetter is a CRD RESTOptionsGetter that encrypts resources bound through configured
+// APIExport identities with the transformers of the respective identity.
+type identityEncryptionRESTOptionsGetter struct {
+	delegate     generic.RESTOptionsGetter
+	transformers map[string]map[schema.GroupResource]value.Transformer
+}
+
+func (g *identityEncryptionRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	ret, err := g.delegate.GetRESTOptions(resource)
+	if err != nil {
+		return ret, err
+	}
+
+	byIdentity := map[string]value.Transformer{}
+	for identity, transformers := range g.transformers {
+		if transformer, found := transformers[resource]; found {
+			byIdentity[identity] = transformer
+		}
+	}
+	if len(byIdentity) == 0 {
+		return ret, nil
+	}
+
+	fallback := ret.StorageConfig.Transformer
+	if fallback == nil {
+		fallback = value.IdentityTransformer
+	}
+	ret.StorageConfig.Transformer = &identityTransformer{
+		// the bound resource instances of identity i are stored under <prefix>/<group>/<resource>/<i>/...
+		keyPrefix:  path.Join("/", ret.StorageConfig.Prefix, resource.Group, resource.Resource) + "/",
+		byIdentity: byIdentity,
+		fallback:   fallback,
+	}
+	return ret, nil
+}
+
+// identityTransformer selects the transformer by the APIExport identity in the etcd key, which the storage
+// passes as authenticated data.
+type identityTransformer struct {
+	keyPrefix  string
+	byIdentity map[string]value.Transformer
+	fallback   value.Transformer
+}
+
+var _ value.Transformer = &identityTransformer{}
+
+func (t *identityTransformer) TransformFromStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, bool, error) {
+	return t.transformerFor(dataCtx).TransformFromStorage(ctx, data, dataCtx)
+}
+
+func (t *identityTransformer) TransformToStorage(ctx context.Context, data []byte, dataCtx value.Context) ([]byte, error) {
+	return t.transformerFor(dataCtx).TransformToStorage(ctx, data, dataCtx)
+}
+
+func (t *identityTransformer) transformerFor(dataCtx value.Context) value.Transformer {
+	key := string(dataCtx.AuthenticatedData())
+	if !strings.HasPrefix(key, t.keyPrefix) {
+		return t.fallback
+	}
+	identity := strings.TrimPrefix(key, t.keyPrefix)
+	if i := strings.Index(identity, "/"); i >= 0 {
+		identity = identity[:i]
+	}
+	if transformer, found := t.byIdentity[identity]; found {
+		return transformer
+	}
+	return t.fallback
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/value"
+)
+
+const testEncryptionConfig = `apiVersion: apiserver.config.k8s.io/v1
+kind: EncryptionConfiguration
+resources:
+- resources:
+  - widgets.example.io
+  providers:
+  - aescbc:
+      keys:
+      - name: key1
+        secret: MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=
+  - identity: {}
+`
+
+type restOptionsGetterFunc func(resource schema.GroupResource) (generic.RESTOptions, error)
+
+func (f restOptionsGetterFunc) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	return f(resource)
+}
+
+func TestIdentityEncryption(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "widgets.yaml"), []byte(testEncryptionConfig), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "identities.yaml"), []byte(`identities:
+- identityHash: abc123
+  encryptionProviderConfig: widgets.yaml
+`), 0600))
+
+	transformers, err := loadIdentityTransformers(filepath.Join(dir, "identities.yaml"))
+	require.NoError(t, err)
+
+	getter := &identityEncryptionRESTOptionsGetter{
+		delegate: restOptionsGetterFunc(func(resource schema.GroupResource) (generic.RESTOptions, error) {
+			return generic.RESTOptions{
+				StorageConfig: &storagebackend.ConfigForResource{Config: storagebackend.Config{Prefix: "/registry"}},
+			}, nil
+		}),
+		transformers: transformers,
+	}
+
+	opts, err := getter.GetRESTOptions(schema.GroupResource{Group: "apps", Resource: "deployments"})
+	require.NoError(t, err)
+	require.Nil(t, opts.StorageConfig.Transformer, "resources not configured for any identity must not be wrapped")
+
+	opts, err = getter.GetRESTOptions(schema.GroupResource{Group: "example.io", Resource: "widgets"})
+	require.NoError(t, err)
+	transformer := opts.StorageConfig.Transformer
+	require.NotNil(t, transformer)
+
+	tests := map[string]struct {
+		key           string
+		wantEncrypted bool
+	}{
+		"bound through the configured identity": {key: "/registry/example.io/widgets/abc123/root:org/default/foo", wantEncrypted: true},
+		"bound through another identity":        {key: "/registry/example.io/widgets/def456/root:org/default/foo"},
+		"identity prefix only":                  {key: "/registry/example.io/widgets/abc1234/root:org/default/foo"},
+		"local CRD":                             {key: "/registry/example.io/widgets/customresources/root:org/default/foo"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			dataCtx := value.DefaultContext(tc.key)
+			data := []byte(`{"kind":"Widget"}`)
+
+			stored, err := transformer.TransformToStorage(ctx, data, dataCtx)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantEncrypted, bytes.HasPrefix(stored, []byte("k8s:enc:aescbc:v1:key1:")))
+
+			read, _, err := transformer.TransformFromStorage(ctx, stored, dataCtx)
+			require.NoError(t, err)
+			require.Equal(t, data, read)
+		})
+	}
+}
+
+func TestLoadIdentityTransformersErrors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "widgets.yaml"), []byte(testEncryptionConfig), 0600))
+
+	tests := map[string]string{
+		"missing identity hash": `identities:
+- encryptionProviderConfig: widgets.yaml
+`,
+		"duplicate identity hash": `identities:
+- identityHash: abc123
+  encryptionProviderConfig: widgets.yaml
+- identityHash: abc123
+  encryptionProviderConfig: widgets.yaml
+`,
+		"missing encryption provider config": `identities:
+- identityHash: abc123
+  encryptionProviderConfig: missing.yaml
+`,
+		"unknown field": `identities:
+- identityHash: abc123
+  encryptionConfig: widgets.yaml
+`,
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(dir, "identities.yaml")
+			require.NoError(t, os.WriteFile(filename, []byte(config), 0600))
+			_, err := loadIdentityTransformers(filename)
+			require.Error(t, err)
+		})
+	}
+}
@@ -19,6 +19,8 @@ package server
 import (
 	"fmt"
 
+	"github.com/kcp-dev/logicalcluster/v2"
+
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
@@ -27,6 +29,8 @@ import (
 const (
 	byGroupResourceName     = "byGroupResourceName" // <plural>.<group>, core group uses "core"
 	byIdentityGroupResource = "byIdentityGroupResource"
+	// byIdentityGroupResourceCluster indexes APIBindings by the bound resources they provide to their logical cluster.
+	byIdentityGroupResourceCluster = "byIdentityGroupResourceCluster"
 )
 
 func indexCRDByGroupResourceName(obj interface{}) ([]string, error) {
@@ -60,3 +64,23 @@ func indexAPIBindingByIdentityGroupResource(obj interface{}) ([]string, error) {
 func identityGroupResourceKeyFunc(identity, group, resource string) string {
 	return fmt.Sprintf("%s/%s/%s", identity, group, resource)
 }
+
+func indexAPIBindingByIdentityGroupResourceCluster(obj interface{}) ([]string, error) {
+	apiBinding, ok := obj.(*apisv1alpha1.APIBinding)
+	if !ok {
+		return []string{}, fmt.Errorf("obj is supposed to be an APIBinding, but is %T", obj)
+	}
+
+	var ret []string
+
+	clusterName := logicalcluster.From(apiBinding)
+	for _, r := range apiBinding.Status.BoundResources {
+		ret = append(ret, identityGroupResourceClusterKeyFunc(r.Schema.IdentityHash, r.Group, r.Resource, clusterName))
+	}
+
+	return ret, nil
+}
+
+func identityGroupResourceClusterKeyFunc(identity, group, resource string, clusterName logicalcluster.Name) string {
+	return fmt.Sprintf("%s/%s/%s/%s", identity, group, resource, clusterName)
+}
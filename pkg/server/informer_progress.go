@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/kcp-dev/kcp/pkg/informer"
+)
+
+const maxReportedNotSyncedInformers = 10
+
+// informerSyncProgress is a readyz check reporting the progress of the staged start of the informers in the
+// kcp-start-informers post-start hook. It passes once all stages have finished and the dynamic informers for
+// built-in types and system CRDs have synced. The informers for resources only served by bound CRDs are started
+// rate limited and do not block readiness.
+type informerSyncProgress struct {
+	stage           atomic.Value
+	syncedCh        <-chan struct{}
+	dynamicProgress func() informer.InformerProgress
+}
+
+func newInformerSyncProgress(syncedCh <-chan struct{}, dynamicProgress func() informer.InformerProgress) *informerSyncProgress {
+	p := &informerSyncProgress{
+		syncedCh:        syncedCh,
+		dynamicProgress: dynamicProgress,
+	}
+	p.setStage("pending")
+	return p
+}
+
+func (p *informerSyncProgress) setStage(stage string) {
+	p.stage.Store(stage)
+}
+
+func (p *informerSyncProgress) Name() string {
+	return "kcp-informers-synced"
+}
+
+func (p *informerSyncProgress) Check(_ *http.Request) error {
+	select {
+	case <-p.syncedCh:
+	default:
+		return fmt.Errorf("starting informers, current stage: %s", p.stage.Load())
+	}
+
+	progress := p.dynamicProgress()
+	if progress.Total == 0 {
+		// there are always informers for built-in types once the dynamic informers have been calculated
+		return fmt.Errorf("starting dynamic informers")
+	}
+	if len(progress.NotSynced) == 0 {
+		return nil
+	}
+
+	notSynced := make([]string, 0, maxReportedNotSyncedInformers)
+	for i, gvr := range progress.NotSynced {
+		if i == maxReportedNotSyncedInformers {
+			notSynced = append(notSynced, "...")
+			break
+		}
+		notSynced = append(notSynced, gvr.String())
+	}
+	return fmt.Errorf("%d of %d dynamic informers synced (%d deferred informers for bound resources pending), waiting for: %s",
+		progress.Synced, progress.Total, progress.DeferredNotSynced, strings.Join(notSynced, ", "))
+}
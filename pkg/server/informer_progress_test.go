@@ -0,0 +1,54 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kcp-dev/kcp/pkg/informer"
+)
+
+func TestInformerSyncProgress(t *testing.T) {
+	syncedCh := make(chan struct{})
+	dynamic := informer.InformerProgress{
+		Total:             4,
+		Synced:            1,
+		NotSynced:         []schema.GroupVersionResource{{Version: "v1", Resource: "secrets"}},
+		DeferredNotSynced: 2,
+	}
+	p := newInformerSyncProgress(syncedCh, func() informer.InformerProgress { return dynamic })
+
+	require.EqualError(t, p.Check(nil), "starting informers, current stage: pending")
+
+	p.setStage("system-crds")
+	require.EqualError(t, p.Check(nil), "starting informers, current stage: system-crds")
+
+	close(syncedCh)
+	dynamicProgress := dynamic
+	dynamic = informer.InformerProgress{}
+	require.EqualError(t, p.Check(nil), "starting dynamic informers")
+
+	dynamic = dynamicProgress
+	require.EqualError(t, p.Check(nil), "1 of 4 dynamic informers synced (2 deferred informers for bound resources pending), waiting for: /v1, Resource=secrets")
+
+	dynamic = informer.InformerProgress{Total: 4, Synced: 2, DeferredNotSynced: 2}
+	require.NoError(t, p.Check(nil), "deferred informers for bound resources must not block readiness")
+}
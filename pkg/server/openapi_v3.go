@@ -0,0 +1,221 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/kcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+)
+
+const openAPIV3Prefix = "/openapi/v3"
+
+// OpenAPIV3Discovery is the index of the OpenAPI v3 specs of a workspace. It mirrors the type of the same
+// name in kube-openapi.
+type OpenAPIV3Discovery struct {
+	Paths map[string]OpenAPIV3DiscoveryGroupVersion `json:"paths"`
+}
+
+// OpenAPIV3DiscoveryGroupVersion points to the OpenAPI v3 spec of a group version.
+type OpenAPIV3DiscoveryGroupVersion struct {
+	ServerRelativeURL string `json:"serverRelativeURL"`
+}
+
+// WithOpenAPIV3 serves /openapi/v3 of a workspace. The specs of group versions coming from CRDs and APIBindings
+// are built from the CRDs the given lister returns for the workspace, i.e. with the schemas of the
+// APIResourceSchemas bound in that workspace, and not those of the first APIBinding or CRD of the same name on
+// the shard. All other group versions, i.e. the built-in ones, are served by the wrapped handler.
+//
+// The server relative URLs of the index include the workspace path, so that clients following them stay
+// in the workspace.
+func WithOpenAPIV3(apiHandler http.Handler, crdLister func() kcp.ClusterAwareCRDClusterLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet || !strings.HasPrefix(req.URL.Path, openAPIV3Prefix) {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+		clusterName, err := request.ClusterNameFrom(req.Context())
+		if err != nil || clusterName == logicalcluster.Wildcard {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+
+		subPath := strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, openAPIV3Prefix), "/")
+		var gv schema.GroupVersion
+		if subPath != "" {
+			parts := strings.Split(subPath, "/")
+			if len(parts) != 3 || parts[0] != "apis" {
+				// the core group and unknown paths
+				apiHandler.ServeHTTP(w, req)
+				return
+			}
+			gv = schema.GroupVersion{Group: parts[1], Version: parts[2]}
+		}
+
+		crds, err := crdLister().Cluster(clusterName).List(req.Context(), labels.Everything())
+		if err != nil {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewInternalError(fmt.Errorf("unable to serve OpenAPI v3: error listing CustomResourceDefinitions: %w", err)),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		if subPath == "" {
+			delegated := &OpenAPIV3Discovery{}
+			if _, err := discoverInProcess(apiHandler, req, openAPIV3Prefix, delegated); err != nil {
+				// serve the CRDs at least
+				klog.FromContext(req.Context()).V(4).Info("unable to get the OpenAPI v3 index of built-in APIs", "err", err)
+			}
+			writeOpenAPIV3JSON(w, req, openAPIV3IndexFor(clusterName, delegated, crds), "")
+			return
+		}
+
+		groupCRDs := crdsForGroupVersion(crds, gv)
+		if len(groupCRDs) == 0 {
+			if crdsServeGroup(crds, gv.Group) {
+				responsewriters.ErrorNegotiated(apierrors.NewNotFound(schema.GroupResource{}, subPath), errorCodecs, schema.GroupVersion{}, w, req)
+				return
+			}
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+
+		bs, hash, err := openAPIV3Specs.groupVersionSpec(req.Context(), gv, groupCRDs)
+		if err != nil {
+			responsewriters.ErrorNegotiated(apierrors.NewInternalError(err), errorCodecs, schema.GroupVersion{}, w, req)
+			return
+		}
+		writeOpenAPIV3(w, req, bs, hash)
+	}
+}
+
+// writeOpenAPIV3JSON writes obj as JSON, with an ETag. If hash is the hash of the served spec, and the client
+// asked for that hash, the response is cacheable forever.
+func writeOpenAPIV3JSON(w http.ResponseWriter, req *http.Request, obj interface{}, hash string) {
+	bs, err := json.Marshal(obj)
+	if err != nil {
+		responsewriters.ErrorNegotiated(apierrors.NewInternalError(err), errorCodecs, schema.GroupVersion{}, w, req)
+		return
+	}
+	writeOpenAPIV3(w, req, bs, hash)
+}
+
+// writeOpenAPIV3 writes the given serialized spec, with an ETag, like writeOpenAPIV3JSON.
+func writeOpenAPIV3(w http.ResponseWriter, req *http.Request, bs []byte, hash string) {
+	etag := fmt.Sprintf("%q", fmt.Sprintf("%X", sha256.Sum256(bs)))
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", "application/json")
+	if hash != "" && req.URL.Query().Get("hash") == hash {
+		w.Header().Set("Cache-Control", "public, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "no-cache, private")
+	}
+	if req.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(bs)
+}
+
+// openAPIV3IndexFor returns the OpenAPI v3 index of the workspace. The group versions of groups served by
+// the CRDs replace those of the delegated index. All URLs are prefixed with the workspace path.
+func openAPIV3IndexFor(clusterName logicalcluster.Name, delegated *OpenAPIV3Discovery, crds []*apiextensionsv1.CustomResourceDefinition) *OpenAPIV3Discovery {
+	prefix := clusterName.Path()
+	ret := &OpenAPIV3Discovery{Paths: map[string]OpenAPIV3DiscoveryGroupVersion{}}
+
+	crdGroups := map[string]bool{}
+	for _, crd := range crds {
+		crdGroups[crd.Spec.Group] = true
+	}
+	for path, gv := range delegated.Paths {
+		if parts := strings.Split(path, "/"); len(parts) == 3 && parts[0] == "apis" && crdGroups[parts[1]] {
+			continue
+		}
+		ret.Paths[path] = OpenAPIV3DiscoveryGroupVersion{ServerRelativeURL: prefix + gv.ServerRelativeURL}
+	}
+
+	versions := map[schema.GroupVersion]bool{}
+	for _, crd := range crds {
+		for _, v := range crd.Spec.Versions {
+			if v.Served {
+				versions[schema.GroupVersion{Group: crd.Spec.Group, Version: v.Name}] = true
+			}
+		}
+	}
+	for gv := range versions {
+		path := "apis/" + gv.String()
+		ret.Paths[path] = OpenAPIV3DiscoveryGroupVersion{
+			ServerRelativeURL: prefix + openAPIV3Prefix + "/" + path + "?hash=" + openAPIV3HashFor(crdsForGroupVersion(crds, gv)),
+		}
+	}
+
+	return ret
+}
+
+// crdsForGroupVersion returns the CRDs serving the given group version.
+func crdsForGroupVersion(crds []*apiextensionsv1.CustomResourceDefinition, gv schema.GroupVersion) []*apiextensionsv1.CustomResourceDefinition {
+	var ret []*apiextensionsv1.CustomResourceDefinition
+	for _, crd := range crds {
+		if crd.Spec.Group != gv.Group {
+			continue
+		}
+		for _, v := range crd.Spec.Versions {
+			if v.Name == gv.Version && v.Served {
+				ret = append(ret, crd)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// crdsServeGroup returns whether any of the CRDs is of the given group.
+func crdsServeGroup(crds []*apiextensionsv1.CustomResourceDefinition, group string) bool {
+	for _, crd := range crds {
+		if crd.Spec.Group == group {
+			return true
+		}
+	}
+	return false
+}
+
+// openAPIV3HashFor returns a hash identifying the spec built from the given CRDs. It changes whenever one
+// of the CRDs changes, or another CRD serves the group version, without building the spec.
+func openAPIV3HashFor(crds []*apiextensionsv1.CustomResourceDefinition) string {
+	keys := make([]string, 0, len(crds))
+	for _, crd := range crds {
+		keys = append(keys, strings.Join([]string{logicalcluster.From(crd).String(), crd.Name, string(crd.UID), crd.ResourceVersion}, "/"))
+	}
+	sort.Strings(keys)
+	return fmt.Sprintf("%X", sha256.Sum256([]byte(strings.Join(keys, ","))))
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/controller/openapi/builder"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/klog/v2"
+	"k8s.io/kube-openapi/pkg/spec3"
+
+	"github.com/kcp-dev/kcp/pkg/memory"
+)
+
+const (
+	// openAPIV3CacheSize bounds the number of CRD versions and group versions whose OpenAPI v3 specs are kept.
+	openAPIV3CacheSize = 4096
+	// openAPIV3CacheTTL makes sure specs of deleted or changed CRDs eventually go away.
+	openAPIV3CacheTTL = time.Hour
+)
+
+// openAPIV3Cache keeps the OpenAPI v3 specs of workspaces in two layers: the spec of every CRD version, keyed by
+// the identity and resourceVersion of the CRD, and the merged and serialized spec of every group version, keyed
+// by the hash of the CRDs serving it. When a single CRD or binding changes, only the group versions it serves get
+// a new hash and are merged again, and only the changed CRD is built again. Group versions built from the same
+// CRDs, e.g. bound from the same APIExport, are shared between workspaces.
+type openAPIV3Cache struct {
+	crdSpecs          *memory.IdleCache[string, *spec3.OpenAPI]
+	groupVersionSpecs *memory.IdleCache[string, []byte]
+
+	buildCRDSpec func(crd *apiextensionsv1.CustomResourceDefinition, version string) (*spec3.OpenAPI, error)
+}
+
+func newOpenAPIV3Cache() *openAPIV3Cache {
+	return &openAPIV3Cache{
+		crdSpecs:          memory.NewIdleCache[string, *spec3.OpenAPI](openAPIV3CacheSize, openAPIV3CacheTTL),
+		groupVersionSpecs: memory.NewIdleCache[string, []byte](openAPIV3CacheSize, openAPIV3CacheTTL),
+		buildCRDSpec: func(crd *apiextensionsv1.CustomResourceDefinition, version string) (*spec3.OpenAPI, error) {
+			return builder.BuildOpenAPIV3(crd, version, builder.Options{V2: false})
+		},
+	}
+}
+
+// openAPIV3Specs is the cache shared by all workspaces of the shard.
+var openAPIV3Specs = newOpenAPIV3Cache()
+
+// groupVersionSpec returns the serialized OpenAPI v3 spec of the given group version, merged from the specs of
+// the given CRDs serving it, and its hash.
+func (c *openAPIV3Cache) groupVersionSpec(ctx context.Context, gv schema.GroupVersion, crds []*apiextensionsv1.CustomResourceDefinition) ([]byte, string, error) {
+	hash := openAPIV3HashFor(crds)
+	key := gv.String() + "|" + hash
+	if cached, ok := c.groupVersionSpecs.Get(key); ok {
+		openAPIV3CacheRequests.WithLabelValues("groupversion", "hit").Inc()
+		return cached, hash, nil
+	}
+	openAPIV3CacheRequests.WithLabelValues("groupversion", "miss").Inc()
+
+	start := time.Now()
+	defer func() {
+		openAPIV3BuildDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	specs := make([]*spec3.OpenAPI, 0, len(crds))
+	for _, crd := range crds {
+		spec, err := c.crdSpec(crd, gv.Version)
+		if err != nil {
+			klog.FromContext(ctx).V(2).Info("unable to build OpenAPI v3 spec of CRD", "crd", crd.Name, "version", gv.Version, "err", err)
+			continue
+		}
+		specs = append(specs, spec)
+	}
+	merged, err := builder.MergeSpecsV3(specs...)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to merge OpenAPI v3 specs of %s: %w", gv, err)
+	}
+	bs, err := json.Marshal(merged)
+	if err != nil {
+		return nil, "", err
+	}
+
+	c.groupVersionSpecs.Add(key, bs)
+	return bs, hash, nil
+}
+
+// crdSpec returns the OpenAPI v3 spec of the given version of the CRD, from the cache if possible.
+func (c *openAPIV3Cache) crdSpec(crd *apiextensionsv1.CustomResourceDefinition, version string) (*spec3.OpenAPI, error) {
+	key := strings.Join([]string{logicalcluster.From(crd).String(), crd.Name, string(crd.UID), crd.ResourceVersion, version}, "|")
+	if cached, ok := c.crdSpecs.Get(key); ok {
+		openAPIV3CacheRequests.WithLabelValues("crd", "hit").Inc()
+		return cached, nil
+	}
+	openAPIV3CacheRequests.WithLabelValues("crd", "miss").Inc()
+
+	spec, err := c.buildCRDSpec(crd, version)
+	if err != nil {
+		return nil, err
+	}
+	c.crdSpecs.Add(key, spec)
+	return spec, nil
+}
+
+var (
+	openAPIV3BuildDuration = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      "workspace",
+			Name:           "openapi_v3_build_duration_seconds",
+			Help:           "Duration in seconds of rebuilding the OpenAPI v3 spec of a group version of a workspace.",
+			StabilityLevel: metrics.ALPHA,
+			Buckets:        metrics.ExponentialBuckets(0.001, 2, 15),
+		},
+	)
+
+	openAPIV3CacheRequests = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "workspace",
+			Name:           "openapi_v3_cache_requests_total",
+			Help:           "Number of OpenAPI v3 spec lookups of workspaces, by the part looked up and whether it was served from the cache.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"part", "result"}, // part is either "crd" or "groupversion", result either "hit" or "miss"
+	)
+)
+
+var registerOpenAPIV3Metrics sync.Once
+
+func init() {
+	registerOpenAPIV3Metrics.Do(func() {
+		legacyregistry.MustRegister(openAPIV3BuildDuration)
+		legacyregistry.MustRegister(openAPIV3CacheRequests)
+
+		memory.Register("workspace-openapi-v3-crd-specs", openAPIV3Specs.crdSpecs.Evict)
+		memory.Register("workspace-openapi-v3-groupversion-specs", openAPIV3Specs.groupVersionSpecs.Evict)
+	})
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func TestOpenAPIV3IndexFor(t *testing.T) {
+	newCRD := func(name, group, resourceVersion string, versions ...string) *apiextensionsv1.CustomResourceDefinition {
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				ResourceVersion: resourceVersion,
+				Annotations:     map[string]string{logicalcluster.AnnotationKey: "system:bound-crds"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{Group: group},
+		}
+		for _, v := range versions {
+			crd.Spec.Versions = append(crd.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{Name: v, Served: true})
+		}
+		return crd
+	}
+
+	delegated := &OpenAPIV3Discovery{Paths: map[string]OpenAPIV3DiscoveryGroupVersion{
+		"api/v1":                {ServerRelativeURL: "/openapi/v3/api/v1?hash=A"},
+		"apis/apps/v1":          {ServerRelativeURL: "/openapi/v3/apis/apps/v1?hash=B"},
+		"apis/example.io/v1":    {ServerRelativeURL: "/openapi/v3/apis/example.io/v1?hash=C"},
+		"apis/example.io/v1old": {ServerRelativeURL: "/openapi/v3/apis/example.io/v1old?hash=D"},
+	}}
+	crds := []*apiextensionsv1.CustomResourceDefinition{
+		newCRD("uid-widgets", "example.io", "1", "v1", "v2"),
+		newCRD("uid-gadgets", "example.io", "1", "v1"),
+	}
+
+	index := openAPIV3IndexFor(logicalcluster.New("root:org:ws"), delegated, crds)
+
+	paths := make([]string, 0, len(index.Paths))
+	for path, gv := range index.Paths {
+		paths = append(paths, path)
+		require.True(t, strings.HasPrefix(gv.ServerRelativeURL, "/clusters/root:org:ws/openapi/v3/"+path+"?hash="), "unexpected URL %s", gv.ServerRelativeURL)
+	}
+	require.ElementsMatch(t, []string{"api/v1", "apis/apps/v1", "apis/example.io/v1", "apis/example.io/v2"}, paths, "groups of CRDs replace the delegated ones")
+
+	// the hash changes with the CRDs serving the group version
+	v1 := index.Paths["apis/example.io/v1"].ServerRelativeURL
+	require.NotEqual(t, v1, index.Paths["apis/example.io/v2"].ServerRelativeURL)
+	crds[1] = newCRD("uid-gadgets", "example.io", "2", "v1")
+	index = openAPIV3IndexFor(logicalcluster.New("root:org:ws"), delegated, crds)
+	require.NotEqual(t, v1, index.Paths["apis/example.io/v1"].ServerRelativeURL)
+}
+
+func TestOpenAPIV3CacheRebuildsChangedGroupVersions(t *testing.T) {
+	newCRD := func(name, group, resourceVersion string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				UID:             "uid-" + types.UID(name),
+				ResourceVersion: resourceVersion,
+				Annotations:     map[string]string{logicalcluster.AnnotationKey: "system:bound-crds"},
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{Group: group},
+		}
+	}
+
+	built := map[string]int{}
+	c := newOpenAPIV3Cache()
+	c.buildCRDSpec = func(crd *apiextensionsv1.CustomResourceDefinition, version string) (*spec3.OpenAPI, error) {
+		built[crd.Name+"@"+crd.ResourceVersion]++
+		return &spec3.OpenAPI{
+			Version:    "3.0.0",
+			Paths:      &spec3.Paths{Paths: map[string]*spec3.Path{"/apis/" + crd.Spec.Group + "/" + version + "/" + crd.Name: {}}},
+			Components: &spec3.Components{Schemas: map[string]*spec.Schema{crd.Name + "." + crd.ResourceVersion: {}}},
+		}, nil
+	}
+
+	ctx := context.Background()
+	examples := schema.GroupVersion{Group: "example.io", Version: "v1"}
+	others := schema.GroupVersion{Group: "other.io", Version: "v1"}
+	widgets, gadgets, things := newCRD("widgets", "example.io", "1"), newCRD("gadgets", "example.io", "1"), newCRD("things", "other.io", "1")
+
+	examplesSpec, examplesHash, err := c.groupVersionSpec(ctx, examples, []*apiextensionsv1.CustomResourceDefinition{widgets, gadgets})
+	require.NoError(t, err)
+	require.Contains(t, string(examplesSpec), "/apis/example.io/v1/widgets")
+	require.Contains(t, string(examplesSpec), "/apis/example.io/v1/gadgets")
+	othersSpec, othersHash, err := c.groupVersionSpec(ctx, others, []*apiextensionsv1.CustomResourceDefinition{things})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"widgets@1": 1, "gadgets@1": 1, "things@1": 1}, built)
+
+	// unchanged group versions are served from the cache, also for other workspaces binding the same CRDs
+	spec, hash, err := c.groupVersionSpec(ctx, examples, []*apiextensionsv1.CustomResourceDefinition{widgets, gadgets})
+	require.NoError(t, err)
+	require.Equal(t, examplesSpec, spec)
+	require.Equal(t, examplesHash, hash)
+	require.Equal(t, map[string]int{"widgets@1": 1, "gadgets@1": 1, "things@1": 1}, built)
+
+	// a changed CRD only rebuilds its own spec, and only the group version it serves is merged again
+	gadgets = newCRD("gadgets", "example.io", "2")
+	spec, hash, err = c.groupVersionSpec(ctx, examples, []*apiextensionsv1.CustomResourceDefinition{widgets, gadgets})
+	require.NoError(t, err)
+	require.NotEqual(t, examplesHash, hash)
+	require.Contains(t, string(spec), "gadgets.2")
+	spec, hash, err = c.groupVersionSpec(ctx, others, []*apiextensionsv1.CustomResourceDefinition{things})
+	require.NoError(t, err)
+	require.Equal(t, othersSpec, spec)
+	require.Equal(t, othersHash, hash)
+	require.Equal(t, map[string]int{"widgets@1": 1, "gadgets@1": 1, "gadgets@2": 1, "things@1": 1}, built)
+}
@@ -103,8 +103,9 @@ func (s *Authorization) ApplyTo(config *genericapiserver.Config, informer kcpkub
 	// kcp authorizers
 	bootstrapAuth, bootstrapRules := authorization.NewBootstrapPolicyAuthorizer(informer)
 	localAuth, localResolver := authorization.NewLocalAuthorizer(informer)
+	inheritedAuth := authorization.NewInheritedAuthorizer(informer)
 	apiBindingAuth, err := authorization.NewMaximalPermissionPolicyAuthorizer(informer, kcpinformer,
-		union.New(bootstrapAuth, localAuth),
+		union.New(bootstrapAuth, localAuth, inheritedAuth),
 	)
 	if err != nil {
 		return err
@@ -112,7 +113,7 @@ func (s *Authorization) ApplyTo(config *genericapiserver.Config, informer kcpkub
 
 	authorizers = append(authorizers,
 		authorization.NewTopLevelOrganizationAccessAuthorizer(informer, workspaceLister,
-			authorization.NewWorkspaceContentAuthorizer(informer, workspaceLister,
+			authorization.NewWorkspaceContentAuthorizer(informer, workspaceLister, inheritedAuth,
 				authorization.NewSystemCRDAuthorizer(
 					apiBindingAuth,
 				),
@@ -29,7 +29,11 @@ import (
 	"k8s.io/klog/v2"
 	kcmoptions "k8s.io/kubernetes/cmd/kube-controller-manager/app/options"
 
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexport"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiresource"
+	"github.com/kcp-dev/kcp/pkg/reconciler/eventttl"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacedeletion"
+	"github.com/kcp-dev/kcp/pkg/reconciler/tenancy/clusterworkspacequota"
 	"github.com/kcp-dev/kcp/pkg/reconciler/workload/heartbeat"
 )
 
@@ -37,12 +41,20 @@ type Controllers struct {
 	EnableAll           bool
 	IndividuallyEnabled []string
 	ApiResource         ApiResourceController
+	APIExport           APIExportController
 	SyncTargetHeartbeat SyncTargetHeartbeatController
+	EventTTL            EventTTLController
+	WorkspaceDeletion   WorkspaceDeletionController
+	WorkspaceQuota      WorkspaceQuotaController
 	SAController        kcmoptions.SAControllerOptions
 }
 
 type ApiResourceController = apiresource.Options
+type APIExportController = apiexport.Options
 type SyncTargetHeartbeatController = heartbeat.Options
+type EventTTLController = eventttl.Options
+type WorkspaceDeletionController = clusterworkspacedeletion.Options
+type WorkspaceQuotaController = clusterworkspacequota.Options
 
 var kcmDefaults *kcmoptions.KubeControllerManagerOptions
 
@@ -60,7 +72,11 @@ func NewControllers() *Controllers {
 		EnableAll: true,
 
 		ApiResource:         *apiresource.DefaultOptions(),
+		APIExport:           *apiexport.DefaultOptions(),
 		SyncTargetHeartbeat: *heartbeat.DefaultOptions(),
+		EventTTL:            *eventttl.DefaultOptions(),
+		WorkspaceDeletion:   *clusterworkspacedeletion.DefaultOptions(),
+		WorkspaceQuota:      *clusterworkspacequota.DefaultOptions(),
 		SAController:        *kcmDefaults.SAController,
 	}
 }
@@ -72,7 +88,11 @@ func (c *Controllers) AddFlags(fs *pflag.FlagSet) {
 	fs.MarkHidden("unsupported-run-individual-controllers") //nolint:errcheck
 
 	apiresource.BindOptions(&c.ApiResource, fs)
+	apiexport.BindOptions(&c.APIExport, fs)
 	heartbeat.BindOptions(&c.SyncTargetHeartbeat, fs)
+	eventttl.BindOptions(&c.EventTTL, fs)
+	clusterworkspacedeletion.BindOptions(&c.WorkspaceDeletion, fs)
+	clusterworkspacequota.BindOptions(&c.WorkspaceQuota, fs)
 
 	c.SAController.AddFlags(fs)
 }
@@ -109,9 +129,21 @@ func (c *Controllers) Validate() []error {
 	if err := c.ApiResource.Validate(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := c.APIExport.Validate(); err != nil {
+		errs = append(errs, err)
+	}
 	if err := c.SyncTargetHeartbeat.Validate(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := c.EventTTL.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.WorkspaceDeletion.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.WorkspaceQuota.Validate(); err != nil {
+		errs = append(errs, err)
+	}
 	if saErrs := c.SAController.Validate(); saErrs != nil {
 		errs = append(errs, saErrs...)
 	}
@@ -129,16 +129,23 @@ var (
 		"tracing-config-file", // File with apiserver tracing configuration.
 
 		// KCP flags
-		"profiler-address",            // [Address]:port to bind the profiler to
-		"root-directory",              // Root directory.
-		"shard-base-url",              // Base URL to this kcp shard. Defaults to external address.
-		"shard-external-url",          // URL used by outside clients to talk to this kcp shard. Defaults to external address.
-		"shard-virtual-workspace-url", // An external URL address of a virtual workspace server associated with this shard. Defaults to shard's base address.
-		"shard-name",                  // A name of this kcp shard.
-		"shard-kubeconfig-file",       // Kubeconfig holding admin(!) credentials to peer kcp shards.
-		"root-shard-kubeconfig-file",  // Kubeconfig holding admin(!) credentials to the root kcp shard.
-		"experimental-bind-free-port", // Bind to a free port. --secure-bind-port must be 0. Use the admin.kubeconfig to extract the chosen port.
-		"batteries-included",          // A list of batteries included (= default objects that might be unwanted in production, but very helpful in trying out kcp or development).
+		"profiler-address",                     // [Address]:port to bind the profiler to
+		"root-directory",                       // Root directory.
+		"shard-base-url",                       // Base URL to this kcp shard. Defaults to external address.
+		"shard-external-url",                   // URL used by outside clients to talk to this kcp shard. Defaults to external address.
+		"shard-virtual-workspace-url",          // An external URL address of a virtual workspace server associated with this shard. Defaults to shard's base address.
+		"shard-name",                           // A name of this kcp shard.
+		"shard-kubeconfig-file",                // Kubeconfig holding admin(!) credentials to peer kcp shards.
+		"root-shard-kubeconfig-file",           // Kubeconfig holding admin(!) credentials to the root kcp shard.
+		"experimental-bind-free-port",          // Bind to a free port. --secure-bind-port must be 0. Use the admin.kubeconfig to extract the chosen port.
+		"batteries-included",                   // A list of batteries included (= default objects that might be unwanted in production, but very helpful in trying out kcp or development).
+		"memory-governor-limit",                // Memory limit the memory governor evicts caches for, e.g. 4Gi. Defaults to GOMEMLIMIT. If neither is set, the memory governor is disabled.
+		"memory-governor-pressure-threshold",   // Fraction of the memory limit the heap must exceed for idle caches to be evicted.
+		"memory-governor-check-interval",       // Interval at which the memory governor checks the heap size.
+		"memory-governor-cache-floor",          // Number of entries every cache keeps when evicted under memory pressure.
+		"memory-governor-idle-timeout",         // Time cache entries must not have been used for to be evicted under memory pressure.
+		"apiexport-identity-encryption-config", // File mapping APIExport identity hashes to encryption provider configuration files. Resources bound through a listed identity are encrypted at rest with the providers of that identity.
+		"workspace-backup-store",               // URL of the object store WorkspaceBackups are written to, e.g. file:///var/lib/kcp/backups or s3://bucket/backups?region=eu-west-1. Credentials of s3:// stores are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables. WorkspaceBackups and WorkspaceRestores are not processed if unset.
 
 		// secure serving flags
 		"bind-address",                     // The IP address on which to listen for the --secure-port port. The associated interface(s) must be reachable by the rest of the cluster, and by CLI/web clients. If blank or an unspecified address (0.0.0.0 or ::), all interfaces will be used.
@@ -173,10 +180,14 @@ var (
 		// KCP Controllers flags
 		"auto-publish-apis",                      // If true, the APIs imported from physical clusters will be published automatically as CRDs
 		"apiresource-controller-threads",         // Number of threads to use for the apiresource controller.
+		"apiexport-identity-provider-dirs",       // Identity providers for APIExports referencing their identity via spec.identity.providerRef, as <provider>=<directory> pairs. The identity of a reference is read from the file in the directory named like the key of the reference, e.g. as projected by an external secret manager.
 		"run-controllers",                        // Run the controllers in-process
 		"run-virtual-workspaces",                 // Run the virtual workspaces apiservers in-process
 		"unsupported-run-individual-controllers", // Run individual controllers in-process. The controller names can change at any time.
 		"sync-target-heartbeat-threshold",        // Amount of time to wait for a successful heartbeat before marking the cluster as not ready.
+		"tenant-event-ttl",                       // Amount of time to retain events of tenant workspaces. Must be shorter than --event-ttl to have an effect. 0 means events of tenant workspaces are retained as long as all other events.
+		"workspace-deletion-retention",           // Amount of time to retain the content of deleted workspaces. During that time, the content is inaccessible, and the workspace can be restored by users allowed to undelete clusterworkspaces. 0 means the content is deleted immediately.
+		"workspace-storage-scan-interval",        // Interval in which the etcd keys of the shard are scanned to account the storage of each workspace. 0 disables the accounting, and with it the tenancy.kcp.dev/storage and tenancy.kcp.dev/objects limits of ClusterWorkspaceQuotas.
 
 		// KCP Cache Server flags
 		"cache-server-kubeconfig-file", // Kubeconfig for the cache server this instance connects to (defaults to loop back configuration).
@@ -37,6 +37,7 @@ import (
 	kcpadmission "github.com/kcp-dev/kcp/pkg/admission"
 	etcdoptions "github.com/kcp-dev/kcp/pkg/embeddedetcd/options"
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
+	"github.com/kcp-dev/kcp/pkg/memory"
 	"github.com/kcp-dev/kcp/pkg/server/options/batteries"
 )
 
@@ -65,7 +66,15 @@ type ExtraOptions struct {
 	DiscoveryPollInterval    time.Duration
 	ExperimentalBindFreePort bool
 
+	IdentityEncryptionConfigFile string
+
+	WorkspaceBackupStore string
+
+	WorkspaceAuthenticationAllowPrivateNetworks bool
+
 	BatteriesIncluded []string
+
+	MemoryGovernor memory.Options
 }
 
 type completedOptions struct {
@@ -109,6 +118,7 @@ func NewOptions(rootDir string) *Options {
 			DiscoveryPollInterval:    60 * time.Second,
 			ExperimentalBindFreePort: false,
 			BatteriesIncluded:        batteries.Defaults.List(),
+			MemoryGovernor:           *memory.DefaultOptions(),
 		},
 	}
 
@@ -178,6 +188,14 @@ func (o *Options) rawFlags() cliflag.NamedFlagSets {
 	fs.BoolVar(&o.Extra.ExperimentalBindFreePort, "experimental-bind-free-port", o.Extra.ExperimentalBindFreePort, "Bind to a free port. --secure-port must be 0. Use the admin.kubeconfig to extract the chosen port.")
 	fs.MarkHidden("experimental-bind-free-port") //nolint:errcheck
 
+	fs.StringVar(&o.Extra.IdentityEncryptionConfigFile, "apiexport-identity-encryption-config", o.Extra.IdentityEncryptionConfigFile, "File mapping APIExport identity hashes to encryption provider configuration files. Resources bound through a listed identity are encrypted at rest with the providers of that identity.")
+
+	fs.StringVar(&o.Extra.WorkspaceBackupStore, "workspace-backup-store", o.Extra.WorkspaceBackupStore, "URL of the object store WorkspaceBackups are written to, e.g. file:///var/lib/kcp/backups or s3://bucket/backups?region=eu-west-1. Credentials of s3:// stores are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables. WorkspaceBackups and WorkspaceRestores are not processed if unset.")
+
+	fs.BoolVar(&o.Extra.WorkspaceAuthenticationAllowPrivateNetworks, "workspace-authentication-allow-private-networks", o.Extra.WorkspaceAuthenticationAllowPrivateNetworks, "Allow the OIDC issuers and TokenReview webhooks of WorkspaceAuthenticationConfigurations on loopback, link-local and private addresses, and the use of proxies from the environment to reach them. Only enable this if workspace owners are trusted not to probe the network of kcp.")
+
+	memory.BindOptions(&o.Extra.MemoryGovernor, fs)
+
 	fs.StringSliceVar(&o.Extra.BatteriesIncluded, "batteries-included", o.Extra.BatteriesIncluded, fmt.Sprintf(
 		`A list of batteries included (= default objects that might be unwanted in production, but are very helpful in trying out kcp or for development). These are the possible values: %s.
 
@@ -209,6 +227,9 @@ func (o *CompletedOptions) Validate() []error {
 	errs = append(errs, o.Virtual.Validate()...)
 	errs = append(errs, o.HomeWorkspaces.Validate()...)
 	errs = append(errs, o.Cache.Validate()...)
+	if err := o.Extra.MemoryGovernor.Validate(); err != nil {
+		errs = append(errs, err)
+	}
 
 	differential := false
 	for i, b := range o.Extra.BatteriesIncluded {
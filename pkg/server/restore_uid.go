@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// restoreUIDRESTOptionsGetter is a RESTOptionsGetter whose storage creates objects carrying the restore UID
+// annotation with the UID of the annotation, such that WorkspaceRestores can preserve the UIDs of the objects
+// in the backup. The UID assigned by the registry is replaced right before the object is persisted. The
+// reserved metadata admission plugin restricts the annotation to system:masters.
+type restoreUIDRESTOptionsGetter struct {
+	delegate generic.RESTOptionsGetter
+}
+
+func (g *restoreUIDRESTOptionsGetter) GetRESTOptions(resource schema.GroupResource) (generic.RESTOptions, error) {
+	ret, err := g.delegate.GetRESTOptions(resource)
+	if err != nil {
+		return ret, err
+	}
+
+	decorator := ret.Decorator
+	if decorator == nil {
+		decorator = generic.UndecoratedStorage
+	}
+	ret.Decorator = func(
+		config *storagebackend.ConfigForResource,
+		resourcePrefix string,
+		keyFunc func(obj runtime.Object) (string, error),
+		newFunc func() runtime.Object,
+		newListFunc func() runtime.Object,
+		getAttrsFunc storage.AttrFunc,
+		trigger storage.IndexerFuncs,
+		indexers *cache.Indexers,
+	) (storage.Interface, factory.DestroyFunc, error) {
+		s, destroy, err := decorator(config, resourcePrefix, keyFunc, newFunc, newListFunc, getAttrsFunc, trigger, indexers)
+		if err != nil {
+			return s, destroy, err
+		}
+		return &restoreUIDStorage{Interface: s}, destroy, nil
+	}
+	return ret, nil
+}
+
+// restoreUIDStorage applies the restore UID annotation on create.
+type restoreUIDStorage struct {
+	storage.Interface
+}
+
+func (s *restoreUIDStorage) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	applyRestoreUID(obj)
+	return s.Interface.Create(ctx, key, obj, out, ttl)
+}
+
+// applyRestoreUID sets the UID of the object to the value of the restore UID annotation, and removes the annotation.
+func applyRestoreUID(obj runtime.Object) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	annotations := accessor.GetAnnotations()
+	uid, found := annotations[tenancyv1alpha1.WorkspaceRestoreUIDAnnotationKey]
+	if !found {
+		return
+	}
+	delete(annotations, tenancyv1alpha1.WorkspaceRestoreUIDAnnotationKey)
+	accessor.SetAnnotations(annotations)
+	if uid != "" {
+		accessor.SetUID(types.UID(uid))
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/generic"
+	"k8s.io/apiserver/pkg/storage"
+	"k8s.io/apiserver/pkg/storage/storagebackend"
+	"k8s.io/apiserver/pkg/storage/storagebackend/factory"
+	"k8s.io/client-go/tools/cache"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+type createRecorder struct {
+	storage.Interface
+	created []runtime.Object
+}
+
+func (r *createRecorder) Create(ctx context.Context, key string, obj, out runtime.Object, ttl uint64) error {
+	r.created = append(r.created, obj.DeepCopyObject())
+	return nil
+}
+
+func TestRestoreUID(t *testing.T) {
+	recorder := &createRecorder{}
+	getter := &restoreUIDRESTOptionsGetter{
+		delegate: restOptionsGetterFunc(func(resource schema.GroupResource) (generic.RESTOptions, error) {
+			return generic.RESTOptions{
+				Decorator: func(*storagebackend.ConfigForResource, string, func(obj runtime.Object) (string, error), func() runtime.Object, func() runtime.Object, storage.AttrFunc, storage.IndexerFuncs, *cache.Indexers) (storage.Interface, factory.DestroyFunc, error) {
+					return recorder, func() {}, nil
+				},
+			}, nil
+		}),
+	}
+	opts, err := getter.GetRESTOptions(schema.GroupResource{Resource: "configmaps"})
+	require.NoError(t, err)
+	s, _, err := opts.Decorator(nil, "", nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		annotations     map[string]string
+		wantUID         string
+		wantAnnotations map[string]string
+	}{
+		"without annotation": {
+			annotations:     map[string]string{"foo": "bar"},
+			wantUID:         "assigned",
+			wantAnnotations: map[string]string{"foo": "bar"},
+		},
+		"with annotation": {
+			annotations:     map[string]string{"foo": "bar", tenancyv1alpha1.WorkspaceRestoreUIDAnnotationKey: "restored"},
+			wantUID:         "restored",
+			wantAnnotations: map[string]string{"foo": "bar"},
+		},
+		"with empty annotation": {
+			annotations:     map[string]string{tenancyv1alpha1.WorkspaceRestoreUIDAnnotationKey: ""},
+			wantUID:         "assigned",
+			wantAnnotations: map[string]string{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder.created = nil
+			obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", UID: "assigned", Annotations: tt.annotations}}
+			require.NoError(t, s.Create(context.Background(), "/registry/configmaps/root:org/default/cm", obj, &corev1.ConfigMap{}, 0))
+
+			require.Len(t, recorder.created, 1)
+			created := recorder.created[0].(*corev1.ConfigMap)
+			require.Equal(t, tt.wantUID, string(created.UID))
+			require.Equal(t, tt.wantAnnotations, created.Annotations)
+		})
+	}
+}
@@ -30,7 +30,6 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/genericcontrolplane"
 
@@ -41,9 +40,7 @@ import (
 	systemcrds "github.com/kcp-dev/kcp/config/system-crds"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	bootstrappolicy "github.com/kcp-dev/kcp/pkg/authorization/bootstrap"
-	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
-	"github.com/kcp-dev/kcp/pkg/indexers"
-	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/memory"
 )
 
 const resyncPeriod = 10 * time.Hour
@@ -56,6 +53,8 @@ type Server struct {
 	syncedCh             chan struct{}
 	syncedOptionalCh     chan struct{}
 	rootPhase1FinishedCh chan struct{}
+
+	informerSyncProgress *informerSyncProgress
 }
 
 func (s *Server) AddPostStartHook(name string, hook genericapiserver.PostStartHookFunc) error {
@@ -69,6 +68,7 @@ func NewServer(c CompletedConfig) (*Server, error) {
 		syncedOptionalCh:     make(chan struct{}),
 		rootPhase1FinishedCh: make(chan struct{}),
 	}
+	s.informerSyncProgress = newInformerSyncProgress(s.syncedCh, c.DynamicDiscoverySharedInformerFactory.Progress)
 
 	var err error
 	s.ServerChain, err = genericcontrolplane.CreateServerChain(c.MiniAggregator, c.Apis, c.ApiExtensions)
@@ -84,21 +84,6 @@ func NewServer(c CompletedConfig) (*Server, error) {
 		),
 	)
 
-	s.DynamicDiscoverySharedInformerFactory, err = informer.NewDynamicDiscoverySharedInformerFactory(
-		s.MiniAggregator.GenericAPIServer.LoopbackClientConfig,
-		func(obj interface{}) bool { return true },
-		s.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
-		indexers.AppendOrDie(
-			cache.Indexers{
-				indexers.BySyncerFinalizerKey:           indexers.IndexBySyncerFinalizerKey,
-				indexers.ByClusterResourceStateLabelKey: indexers.IndexByClusterResourceStateLabelKey,
-			},
-		),
-	)
-	if err != nil {
-		return nil, err
-	}
-
 	return s, nil
 }
 
@@ -111,12 +96,17 @@ func (s *Server) Run(ctx context.Context) error {
 		return err
 	}
 
+	if err := delegationChainHead.AddReadyzChecks(s.informerSyncProgress); err != nil {
+		return err
+	}
+
 	hookName := "kcp-start-informers"
 	if err := s.AddPostStartHook(hookName, func(hookContext genericapiserver.PostStartHookContext) error {
 		logger := logger.WithValues("postStartHook", hookName)
 		ctx = klog.NewContext(ctx, logger)
 
 		logger.Info("starting kube informers")
+		s.informerSyncProgress.setStage("kube-informers")
 		s.KubeSharedInformerFactory.Start(hookContext.StopCh)
 		s.ApiExtensionsSharedInformerFactory.Start(hookContext.StopCh)
 
@@ -132,6 +122,7 @@ func (s *Server) Run(ctx context.Context) error {
 		logger.Info("finished starting kube informers")
 
 		logger.Info("bootstrapping system CRDs")
+		s.informerSyncProgress.setStage("system-crds")
 		if err := wait.PollInfiniteWithContext(goContext(hookContext), time.Second, func(ctx context.Context) (bool, error) {
 			if err := systemcrds.Bootstrap(ctx,
 				s.ApiExtensionsClusterClient.Cluster(SystemCRDLogicalCluster),
@@ -150,6 +141,7 @@ func (s *Server) Run(ctx context.Context) error {
 		logger.Info("finished bootstrapping system CRDs")
 
 		logger.Info("bootstrapping the shard workspace")
+		s.informerSyncProgress.setStage("shard-workspace")
 		if err := wait.PollInfiniteWithContext(goContext(hookContext), time.Second, func(ctx context.Context) (bool, error) {
 			if err := configshard.Bootstrap(ctx,
 				s.ApiExtensionsClusterClient.Cluster(configshard.SystemShardCluster).Discovery(),
@@ -170,6 +162,7 @@ func (s *Server) Run(ctx context.Context) error {
 		go s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings().Informer().Run(hookContext.StopCh)
 
 		logger.Info("starting APIExport and APIBinding informers")
+		s.informerSyncProgress.setStage("apiexport-apibinding-informers")
 		if err := wait.PollInfiniteWithContext(goContext(hookContext), time.Millisecond*100, func(ctx context.Context) (bool, error) {
 			exportsSynced := s.KcpSharedInformerFactory.Apis().V1alpha1().APIExports().Informer().HasSynced()
 			bindingsSynced := s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings().Informer().HasSynced()
@@ -196,6 +189,7 @@ func (s *Server) Run(ctx context.Context) error {
 			logger.Info("bootstrapped root workspace phase 0")
 
 			logger.Info("getting kcp APIExport identities")
+			s.informerSyncProgress.setStage("kcp-identities")
 			if err := wait.PollImmediateInfiniteWithContext(goContext(hookContext), time.Millisecond*500, func(ctx context.Context) (bool, error) {
 				if err := s.resolveIdentities(ctx); err != nil {
 					logger.V(3).Info("failed to resolve identities, keeping trying", "err", err)
@@ -209,6 +203,7 @@ func (s *Server) Run(ctx context.Context) error {
 			logger.Info("finished getting kcp APIExport identities")
 		} else if len(s.Options.Extra.RootShardKubeconfigFile) > 0 {
 			logger.Info("starting setting up kcp informers for the root shard")
+			s.informerSyncProgress.setStage("root-shard-informers")
 
 			go s.TemporaryRootShardKcpSharedInformerFactory.Apis().V1alpha1().APIExports().Informer().Run(hookContext.StopCh)
 			go s.TemporaryRootShardKcpSharedInformerFactory.Apis().V1alpha1().APIBindings().Informer().Run(hookContext.StopCh)
@@ -248,14 +243,22 @@ func (s *Server) Run(ctx context.Context) error {
 			logger.Info("finished starting kcp informers for the root shard")
 		}
 
+		s.informerSyncProgress.setStage("kcp-informers")
 		s.KcpSharedInformerFactory.Start(hookContext.StopCh)
 		s.KcpSharedInformerFactory.WaitForCacheSync(hookContext.StopCh)
 
-		// create or update shard
+		// create or update shard, acknowledging the system CRD schemas shipped with this binary
+		systemCRDs, err := systemcrds.CRDs()
+		if err != nil {
+			return err
+		}
 		shard := &tenancyv1alpha1.ClusterWorkspaceShard{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:        s.Options.Extra.ShardName,
-				Annotations: map[string]string{logicalcluster.AnnotationKey: tenancyv1alpha1.RootCluster.String()},
+				Name: s.Options.Extra.ShardName,
+				Annotations: map[string]string{
+					logicalcluster.AnnotationKey:         tenancyv1alpha1.RootCluster.String(),
+					systemcrds.ShardSchemasAnnotationKey: systemcrds.FormatShardSchemas(systemCRDs),
+				},
 			},
 			Spec: tenancyv1alpha1.ClusterWorkspaceShardSpec{
 				BaseURL:             s.CompletedConfig.ShardBaseURL(),
@@ -280,6 +283,10 @@ func (s *Server) Run(ctx context.Context) error {
 			existingShard.Spec.BaseURL = shard.Spec.BaseURL
 			existingShard.Spec.ExternalURL = shard.Spec.ExternalURL
 			existingShard.Spec.VirtualWorkspaceURL = shard.Spec.VirtualWorkspaceURL
+			if existingShard.Annotations == nil {
+				existingShard.Annotations = map[string]string{}
+			}
+			existingShard.Annotations[systemcrds.ShardSchemasAnnotationKey] = shard.Annotations[systemcrds.ShardSchemasAnnotationKey]
 			if _, err := s.RootShardKcpClusterClient.Cluster(tenancyv1alpha1.RootCluster).TenancyV1alpha1().ClusterWorkspaceShards().Update(ctx, existingShard, metav1.UpdateOptions{}); err != nil {
 				logger.Error(err, "failed updating ClusterWorkspaceShard in the root workspace")
 				return false, nil
@@ -301,6 +308,7 @@ func (s *Server) Run(ctx context.Context) error {
 
 		logger.Info("starting dynamic metadata informer worker")
 		go s.DynamicDiscoverySharedInformerFactory.StartWorker(goContext(hookContext))
+		go s.WorkspaceObjectCounts.Run(goContext(hookContext))
 
 		logger.Info("synced all informers, ready to start controllers")
 		close(s.syncedCh)
@@ -348,6 +356,19 @@ func (s *Server) Run(ctx context.Context) error {
 		}
 	}
 
+	governor, err := memory.NewGovernor(&s.Options.Extra.MemoryGovernor)
+	if err != nil {
+		return err
+	}
+	if governor != nil {
+		go governor.Start(ctx)
+	}
+
+	go func() {
+		<-ctx.Done()
+		s.PerClusterKubeSharedInformerFactory.Shutdown()
+	}()
+
 	// ========================================================================================================
 	// TODO: split apart everything after this line, into their own commands, optional launched in this process
 
@@ -430,7 +451,7 @@ func (s *Server) Run(ctx context.Context) error {
 		if err := s.installWorkspaceScheduler(ctx, controllerConfig); err != nil {
 			return err
 		}
-		if err := s.installWorkspaceDeletionController(ctx, controllerConfig); err != nil {
+		if err := s.installWorkspaceDeletionController(ctx, controllerConfig, s.DynamicDiscoverySharedInformerFactory); err != nil {
 			return err
 		}
 	}
@@ -441,6 +462,12 @@ func (s *Server) Run(ctx context.Context) error {
 		}
 	}
 
+	if s.Options.Controllers.EnableAll || enabled.Has("status-coordination") {
+		if err := s.installStatusCoordinationController(ctx, controllerConfig, s.DynamicDiscoverySharedInformerFactory); err != nil {
+			return err
+		}
+	}
+
 	if s.Options.Controllers.EnableAll || enabled.Has("apibinding") {
 		if err := s.installAPIBindingController(ctx, controllerConfig, delegationChainHead, s.DynamicDiscoverySharedInformerFactory); err != nil {
 			return err
@@ -450,38 +477,62 @@ func (s *Server) Run(ctx context.Context) error {
 		}
 	}
 
+	if s.Options.Controllers.EnableAll || enabled.Has("systemcrds") {
+		if err := s.installSystemCRDController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
+	}
+
 	if s.Options.Controllers.EnableAll || enabled.Has("apiexport") {
 		if err := s.installAPIExportController(ctx, controllerConfig, delegationChainHead); err != nil {
 			return err
 		}
 	}
 
+	if s.Options.Controllers.EnableAll || enabled.Has("apiexportusage") {
+		if err := s.installAPIExportUsageController(ctx, controllerConfig, delegationChainHead, s.DynamicDiscoverySharedInformerFactory); err != nil {
+			return err
+		}
+	}
+
+	if s.Options.Controllers.EnableAll || enabled.Has("apiresourceschemaretention") {
+		if err := s.installAPIResourceSchemaRetentionController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
+	}
+
 	if s.Options.Controllers.EnableAll || enabled.Has("apibinder") {
 		if err := s.installAPIBinderController(ctx, controllerConfig, delegationChainHead); err != nil {
 			return err
 		}
 	}
 
-	if kcpfeatures.DefaultFeatureGate.Enabled(kcpfeatures.LocationAPI) {
-		if s.Options.Controllers.EnableAll || enabled.Has("scheduling") {
-			if err := s.installWorkloadNamespaceScheduler(ctx, controllerConfig, delegationChainHead); err != nil {
-				return err
-			}
-			if err := s.installWorkloadPlacementScheduler(ctx, controllerConfig, delegationChainHead); err != nil {
-				return err
-			}
-			if err := s.installSchedulingLocationStatusController(ctx, controllerConfig, delegationChainHead); err != nil {
-				return err
-			}
-			if err := s.installSchedulingPlacementController(ctx, controllerConfig, delegationChainHead); err != nil {
-				return err
-			}
-			if err := s.installWorkloadsAPIExportController(ctx, controllerConfig, delegationChainHead); err != nil {
-				return err
-			}
-			if err := s.installWorkloadsAPIExportCreateController(ctx, controllerConfig, delegationChainHead); err != nil {
-				return err
-			}
+	if s.Options.Controllers.EnableAll || enabled.Has("clusterworkspacetemplate") {
+		if err := s.installClusterWorkspaceTemplateController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
+	}
+
+	// The scheduling controllers run even if the LocationAPI feature is disabled, because it can be enabled per
+	// workspace. They skip workspaces with the feature disabled.
+	if s.Options.Controllers.EnableAll || enabled.Has("scheduling") {
+		if err := s.installWorkloadNamespaceScheduler(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
+		if err := s.installWorkloadPlacementScheduler(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
+		if err := s.installSchedulingLocationStatusController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
+		if err := s.installSchedulingPlacementController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
+		if err := s.installWorkloadsAPIExportController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
+		if err := s.installWorkloadsAPIExportCreateController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
 		}
 	}
 
@@ -491,10 +542,43 @@ func (s *Server) Run(ctx context.Context) error {
 		}
 	}
 
+	if s.Options.Controllers.EnableAll || enabled.Has("clusterworkspacequota") {
+		if err := s.installClusterWorkspaceQuotaController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
+	}
+
+	if s.Options.Controllers.WorkspaceQuota.StorageScanInterval > 0 && (s.Options.Controllers.EnableAll || enabled.Has("workspacestoragescanner")) {
+		if err := s.installWorkspaceStorageScanner(ctx, delegationChainHead); err != nil {
+			return err
+		}
+	}
+
+	if s.Options.Controllers.EnableAll || enabled.Has("workspacemigration") {
+		if err := s.installWorkspaceMigrationController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
+	}
+
+	if s.Options.Controllers.EnableAll || enabled.Has("workspacebackup") {
+		if err := s.installWorkspaceBackupController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
+	}
+
 	if s.Options.Controllers.EnableAll || enabled.Has("garbagecollector") {
 		if err := s.installGarbageCollectorController(ctx, controllerConfig, delegationChainHead); err != nil {
 			return err
 		}
+		if err := s.installCrossWorkspaceGarbageCollectorController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
+	}
+
+	if s.Options.Controllers.EventTTL.TenantEventTTL > 0 && (s.Options.Controllers.EnableAll || enabled.Has("eventttl")) {
+		if err := s.installEventTTLController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
 	}
 
 	if s.Options.Virtual.Enabled {
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	jwt2 "gopkg.in/square/go-jose.v2/jwt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// ServiceAccountClusterNameUserExtraKey is the user extra key holding the logical cluster a ServiceAccount
+// token was issued in.
+const ServiceAccountClusterNameUserExtraKey = "authentication.kcp.dev/cluster-name"
+
+// WithServiceAccountClusterBinding wraps the given authenticator such that ServiceAccount tokens only authenticate
+// requests to the logical cluster they were issued in. ServiceAccount tokens issued by kcp carry the logical cluster
+// of the ServiceAccount as a claim. Without this check, a token of a ServiceAccount in one workspace would
+// authenticate as system:serviceaccount:<namespace>:<name> in every other workspace, and hence could be replayed
+// against workspaces having a ServiceAccount of the same name.
+//
+// Requests that are not scoped to a logical cluster are passed through. The logical cluster is recorded in the
+// user extra under ServiceAccountClusterNameUserExtraKey.
+func WithServiceAccountClusterBinding(delegate authenticator.Request) authenticator.Request {
+	return authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+		resp, ok, err := delegate.AuthenticateRequest(req)
+		if err != nil || !ok {
+			return resp, ok, err
+		}
+		if _, _, err := serviceaccount.SplitUsername(resp.User.GetName()); err != nil {
+			// not a ServiceAccount
+			return resp, ok, nil
+		}
+
+		token, found := bearerToken(req)
+		if !found {
+			return nil, false, fmt.Errorf("unable to determine the logical cluster of ServiceAccount %q", resp.User.GetName())
+		}
+		tokenClusterName, found := serviceAccountTokenClusterName(token)
+		if !found {
+			return nil, false, fmt.Errorf("token of ServiceAccount %q is not bound to a logical cluster", resp.User.GetName())
+		}
+
+		if cluster := request.ClusterFrom(req.Context()); cluster != nil && !cluster.Name.Empty() && cluster.Name != tokenClusterName {
+			return nil, false, fmt.Errorf("token of ServiceAccount %q of logical cluster %q cannot be used in logical cluster %q", resp.User.GetName(), tokenClusterName, cluster.Name)
+		}
+
+		extra := make(map[string][]string, len(resp.User.GetExtra())+1)
+		for k, v := range resp.User.GetExtra() {
+			extra[k] = v
+		}
+		extra[ServiceAccountClusterNameUserExtraKey] = []string{tokenClusterName.String()}
+		resp.User = &user.DefaultInfo{
+			Name:   resp.User.GetName(),
+			UID:    resp.User.GetUID(),
+			Groups: resp.User.GetGroups(),
+			Extra:  extra,
+		}
+
+		return resp, true, nil
+	})
+}
+
+// bearerToken returns the bearer token of the request, if any.
+func bearerToken(req *http.Request) (string, bool) {
+	prefix := "Bearer "
+	token := req.Header.Get("Authorization")
+	if !strings.HasPrefix(token, prefix) {
+		return "", false
+	}
+	return token[len(prefix):], true
+}
+
+// serviceAccountTokenClusterName returns the logical cluster encoded in a ServiceAccount token issued by kcp. The
+// signature of the token is not verified.
+func serviceAccountTokenClusterName(token string) (logicalcluster.Name, bool) {
+	decoded, err := jwt2.ParseSigned(token)
+	if err != nil {
+		return logicalcluster.Name{}, false
+	}
+	var claims map[string]interface{}
+	if err := decoded.UnsafeClaimsWithoutVerification(&claims); err != nil {
+		return logicalcluster.Name{}, false
+	}
+
+	clusterName, ok, err := unstructured.NestedString(claims, "kubernetes.io", "clusterName") // bound
+	if err != nil || !ok {
+		clusterName, ok, err = unstructured.NestedString(claims, "kubernetes.io/serviceaccount/clusterName") // legacy
+		if err != nil || !ok {
+			return logicalcluster.Name{}, false
+		}
+	}
+	if clusterName == "" {
+		return logicalcluster.Name{}, false
+	}
+
+	return logicalcluster.New(clusterName), true
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	jwt2 "gopkg.in/square/go-jose.v2/jwt"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestWithServiceAccountClusterBinding(t *testing.T) {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("0123456789abcdef0123456789abcdef")}, nil)
+	require.NoError(t, err)
+	token := func(claims map[string]interface{}) string {
+		s, err := jwt2.Signed(signer).Claims(claims).CompactSerialize()
+		require.NoError(t, err)
+		return s
+	}
+
+	boundToken := token(map[string]interface{}{"kubernetes.io": map[string]interface{}{"clusterName": "root:org:ws"}})
+	legacyToken := token(map[string]interface{}{"kubernetes.io/serviceaccount/clusterName": "root:org:ws"})
+	unboundToken := token(map[string]interface{}{"sub": "system:serviceaccount:default:default"})
+
+	serviceAccount := &user.DefaultInfo{Name: "system:serviceaccount:default:default", Groups: []string{"system:serviceaccounts"}}
+	someUser := &user.DefaultInfo{Name: "user"}
+
+	tests := map[string]struct {
+		user    user.Info
+		token   string
+		cluster *request.Cluster
+
+		wantOK    bool
+		wantError bool
+		wantExtra []string
+	}{
+		"service account in its own cluster": {
+			user:      serviceAccount,
+			token:     boundToken,
+			cluster:   &request.Cluster{Name: logicalcluster.New("root:org:ws")},
+			wantOK:    true,
+			wantExtra: []string{"root:org:ws"},
+		},
+		"legacy service account token in its own cluster": {
+			user:      serviceAccount,
+			token:     legacyToken,
+			cluster:   &request.Cluster{Name: logicalcluster.New("root:org:ws")},
+			wantOK:    true,
+			wantExtra: []string{"root:org:ws"},
+		},
+		"service account in another cluster": {
+			user:      serviceAccount,
+			token:     boundToken,
+			cluster:   &request.Cluster{Name: logicalcluster.New("root:org:other")},
+			wantError: true,
+		},
+		"service account in the wildcard cluster": {
+			user:      serviceAccount,
+			token:     boundToken,
+			cluster:   &request.Cluster{Name: logicalcluster.Wildcard, Wildcard: true},
+			wantError: true,
+		},
+		"service account without cluster scoped request": {
+			user:      serviceAccount,
+			token:     boundToken,
+			wantOK:    true,
+			wantExtra: []string{"root:org:ws"},
+		},
+		"service account token without cluster": {
+			user:      serviceAccount,
+			token:     unboundToken,
+			cluster:   &request.Cluster{Name: logicalcluster.New("root:org:ws")},
+			wantError: true,
+		},
+		"other users are passed through": {
+			user:    someUser,
+			token:   "opaque",
+			cluster: &request.Cluster{Name: logicalcluster.New("root:org:other")},
+			wantOK:  true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			delegate := authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+				return &authenticator.Response{User: tc.user}, true, nil
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			if tc.cluster != nil {
+				req = req.WithContext(request.WithCluster(req.Context(), *tc.cluster))
+			}
+
+			resp, ok, err := WithServiceAccountClusterBinding(delegate).AuthenticateRequest(req)
+			if tc.wantError {
+				require.Error(t, err)
+				require.False(t, ok)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantOK, ok)
+			require.Equal(t, tc.user.GetName(), resp.User.GetName())
+			require.Equal(t, tc.wantExtra, resp.User.GetExtra()[ServiceAccountClusterNameUserExtraKey])
+		})
+	}
+}
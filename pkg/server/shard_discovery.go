@@ -0,0 +1,208 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	virtualcommandoptions "github.com/kcp-dev/kcp/cmd/virtual-workspaces/options"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancylisters "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+	apiexportbuilder "github.com/kcp-dev/kcp/pkg/virtual/apiexport/builder"
+	"github.com/kcp-dev/kcp/pkg/virtual/initializingworkspaces"
+	syncerbuilder "github.com/kcp-dev/kcp/pkg/virtual/syncer/builder"
+)
+
+// ShardDiscoveryPath is the non-resource path in the root workspace serving the shard discovery document.
+const ShardDiscoveryPath = "/shards"
+
+// WorkspaceShardPath is the non-resource path in every workspace serving the endpoints of the shard
+// the workspace is served by.
+const WorkspaceShardPath = "/shard"
+
+// shardVirtualWorkspaceNames are the virtual workspaces every shard serves below its virtual workspace URL.
+var shardVirtualWorkspaceNames = []string{
+	"workspaces",
+	syncerbuilder.SyncerVirtualWorkspaceName,
+	syncerbuilder.UpsyncerVirtualWorkspaceName,
+	apiexportbuilder.VirtualWorkspaceName,
+	initializingworkspaces.VirtualWorkspaceName,
+}
+
+// ShardDiscovery is the document served at ShardDiscoveryPath. It lists the active
+// shards of the installation together with the endpoints of their virtual workspaces.
+type ShardDiscovery struct {
+	Shards []ShardEndpoints `json:"shards"`
+}
+
+// ShardEndpoints describes how to reach a single shard.
+type ShardEndpoints struct {
+	Name                string                      `json:"name"`
+	BaseURL             string                      `json:"baseURL"`
+	ExternalURL         string                      `json:"externalURL,omitempty"`
+	VirtualWorkspaceURL string                      `json:"virtualWorkspaceURL,omitempty"`
+	VirtualWorkspaces   []VirtualWorkspaceEndpoints `json:"virtualWorkspaces,omitempty"`
+}
+
+// VirtualWorkspaceEndpoints is the base URL of a named virtual workspace of a shard.
+type VirtualWorkspaceEndpoints struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// WithShardDiscovery serves the shard discovery document at ShardDiscoveryPath in the root
+// workspace. It must be placed behind authentication and authorization, such that only
+// callers allowed to get the "/shards" non-resource URL in the root workspace can read it.
+func WithShardDiscovery(apiHandler http.Handler, shardLister tenancylisters.ClusterWorkspaceShardClusterLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		cluster := request.ClusterFrom(req.Context())
+		if cluster == nil || cluster.Name != tenancyv1alpha1.RootCluster || req.URL.Path != ShardDiscoveryPath {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+
+		if req.Method != http.MethodGet {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewMethodNotSupported(schema.GroupResource{Resource: "shards"}, req.Method),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		shards, err := shardLister.Cluster(tenancyv1alpha1.RootCluster).List(labels.Everything())
+		if err != nil {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewInternalError(fmt.Errorf("failed to list ClusterWorkspaceShards: %w", err)),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		bs, err := json.Marshal(shardDiscoveryFor(shards))
+		if err != nil {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewInternalError(err),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(bs)
+	}
+}
+
+func shardDiscoveryFor(shards []*tenancyv1alpha1.ClusterWorkspaceShard) *ShardDiscovery {
+	discovery := &ShardDiscovery{Shards: []ShardEndpoints{}}
+	for _, shard := range shards {
+		if shard.DeletionTimestamp != nil {
+			continue
+		}
+
+		discovery.Shards = append(discovery.Shards, shardEndpointsFor(shard))
+	}
+
+	sort.Slice(discovery.Shards, func(i, j int) bool {
+		return discovery.Shards[i].Name < discovery.Shards[j].Name
+	})
+
+	return discovery
+}
+
+func shardEndpointsFor(shard *tenancyv1alpha1.ClusterWorkspaceShard) ShardEndpoints {
+	endpoints := ShardEndpoints{
+		Name:                shard.Name,
+		BaseURL:             shard.Spec.BaseURL,
+		ExternalURL:         shard.Spec.ExternalURL,
+		VirtualWorkspaceURL: shard.Spec.VirtualWorkspaceURL,
+	}
+
+	virtualWorkspaceURL := shard.Spec.VirtualWorkspaceURL
+	if virtualWorkspaceURL == "" {
+		virtualWorkspaceURL = shard.Spec.BaseURL
+	}
+	if u, err := url.Parse(virtualWorkspaceURL); err == nil && virtualWorkspaceURL != "" {
+		for _, name := range shardVirtualWorkspaceNames {
+			vwURL := *u
+			vwURL.Path = path.Join(u.Path, virtualcommandoptions.DefaultRootPathPrefix, name)
+			endpoints.VirtualWorkspaces = append(endpoints.VirtualWorkspaces, VirtualWorkspaceEndpoints{
+				Name: name,
+				URL:  vwURL.String(),
+			})
+		}
+	}
+
+	return endpoints
+}
+
+// WithWorkspaceShard serves the endpoints of this shard, named shardName, at WorkspaceShardPath in every
+// workspace. Behind the front-proxy, this is the shard the workspace is currently routed to. It must be
+// placed behind authentication and authorization, such that only callers allowed to get the "/shard"
+// non-resource URL in the workspace can read it.
+func WithWorkspaceShard(apiHandler http.Handler, shardName string, shardLister tenancylisters.ClusterWorkspaceShardClusterLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		cluster := request.ClusterFrom(req.Context())
+		if cluster == nil || cluster.Name.Empty() || cluster.Name == logicalcluster.Wildcard || req.URL.Path != WorkspaceShardPath {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+
+		if req.Method != http.MethodGet {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewMethodNotSupported(schema.GroupResource{Resource: "shard"}, req.Method),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		shard, err := shardLister.Cluster(tenancyv1alpha1.RootCluster).Get(shardName)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				err = apierrors.NewInternalError(fmt.Errorf("failed to get ClusterWorkspaceShard %s: %w", shardName, err))
+			}
+			responsewriters.ErrorNegotiated(err, errorCodecs, schema.GroupVersion{}, w, req)
+			return
+		}
+
+		bs, err := json.Marshal(shardEndpointsFor(shard))
+		if err != nil {
+			responsewriters.ErrorNegotiated(
+				apierrors.NewInternalError(err),
+				errorCodecs, schema.GroupVersion{}, w, req,
+			)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(bs)
+	}
+}
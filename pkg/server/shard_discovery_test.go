@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancylisters "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
+)
+
+func TestShardDiscoveryFor(t *testing.T) {
+	now := metav1.Now()
+	shards := []*tenancyv1alpha1.ClusterWorkspaceShard{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "beta"},
+			Spec: tenancyv1alpha1.ClusterWorkspaceShardSpec{
+				BaseURL:             "https://beta:6443",
+				VirtualWorkspaceURL: "https://vw.beta/prefix",
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "alpha"},
+			Spec: tenancyv1alpha1.ClusterWorkspaceShardSpec{
+				BaseURL:     "https://alpha:6443",
+				ExternalURL: "https://proxy",
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "deleted", DeletionTimestamp: &now},
+			Spec: tenancyv1alpha1.ClusterWorkspaceShardSpec{
+				BaseURL: "https://deleted:6443",
+			},
+		},
+	}
+
+	discovery := shardDiscoveryFor(shards)
+	require.Len(t, discovery.Shards, 2)
+
+	require.Equal(t, "alpha", discovery.Shards[0].Name)
+	require.Equal(t, "https://proxy", discovery.Shards[0].ExternalURL)
+	require.Len(t, discovery.Shards[0].VirtualWorkspaces, len(shardVirtualWorkspaceNames))
+	require.Equal(t, VirtualWorkspaceEndpoints{Name: "workspaces", URL: "https://alpha:6443/services/workspaces"}, discovery.Shards[0].VirtualWorkspaces[0])
+
+	require.Equal(t, "beta", discovery.Shards[1].Name)
+	require.Equal(t, VirtualWorkspaceEndpoints{Name: "apiexport", URL: "https://vw.beta/prefix/services/apiexport"}, discovery.Shards[1].VirtualWorkspaces[3])
+}
+
+func TestWithWorkspaceShard(t *testing.T) {
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(&tenancyv1alpha1.ClusterWorkspaceShard{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "alpha",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: tenancyv1alpha1.RootCluster.String()},
+		},
+		Spec: tenancyv1alpha1.ClusterWorkspaceShardSpec{BaseURL: "https://alpha:6443"},
+	}))
+	lister := tenancylisters.NewClusterWorkspaceShardClusterLister(indexer)
+
+	tests := map[string]struct {
+		shardName    string
+		cluster      logicalcluster.Name
+		method       string
+		path         string
+		wantCode     int
+		wantDelegate bool
+	}{
+		"served in a workspace":         {shardName: "alpha", cluster: logicalcluster.New("root:org:ws"), method: http.MethodGet, path: WorkspaceShardPath, wantCode: http.StatusOK},
+		"unregistered shard":            {shardName: "beta", cluster: logicalcluster.New("root:org:ws"), method: http.MethodGet, path: WorkspaceShardPath, wantCode: http.StatusNotFound},
+		"other methods are not allowed": {shardName: "alpha", cluster: logicalcluster.New("root:org:ws"), method: http.MethodPost, path: WorkspaceShardPath, wantCode: http.StatusMethodNotAllowed},
+		"other paths are delegated":     {shardName: "alpha", cluster: logicalcluster.New("root:org:ws"), method: http.MethodGet, path: "/shards", wantDelegate: true},
+		"wildcard is delegated":         {shardName: "alpha", cluster: logicalcluster.Wildcard, method: http.MethodGet, path: WorkspaceShardPath, wantDelegate: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			delegated := false
+			handler := WithWorkspaceShard(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				delegated = true
+			}), tc.shardName, lister)
+
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req = req.WithContext(request.WithCluster(req.Context(), request.Cluster{Name: tc.cluster}))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.wantDelegate, delegated)
+			if tc.wantDelegate {
+				return
+			}
+			require.Equal(t, tc.wantCode, rec.Code)
+			if tc.wantCode == http.StatusOK {
+				var endpoints ShardEndpoints
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &endpoints))
+				require.Equal(t, "alpha", endpoints.Name)
+				require.Equal(t, "https://alpha:6443", endpoints.BaseURL)
+			}
+		})
+	}
+}
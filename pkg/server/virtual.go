@@ -87,6 +87,9 @@ func (s *Server) installVirtualWorkspaces(
 		return err
 	}
 	rootAPIServerConfig.GenericConfig.ExternalAddress = externalAddress
+	if rootAPIServerConfig.ExtraConfig.TrustedProxies, err = s.Options.Virtual.VirtualWorkspaces.TrustedProxyNetworks(); err != nil {
+		return err
+	}
 
 	completedRootAPIServerConfig := rootAPIServerConfig.Complete()
 	completedRootAPIServerConfig.GenericConfig.AuditBackend = server.AuditBackend
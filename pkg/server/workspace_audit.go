@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	kaudit "k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/filters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+const (
+	// workspaceAuditPolicyAnnotation is the audit event annotation naming the ClusterWorkspace or
+	// ClusterWorkspaceType whose audit policy caused the event.
+	workspaceAuditPolicyAnnotation = "tenancy.kcp.dev/audit-policy"
+
+	// shardAuditAnnotation is the audit event annotation naming the shard that served the request.
+	shardAuditAnnotation = "tenancy.kcp.dev/shard"
+
+	// identityAuditAnnotation is the audit event annotation holding the identity hash of the APIExport
+	// the requested resource is bound from.
+	identityAuditAnnotation = "apis.kcp.dev/identity"
+)
+
+// WithAuditEventWorkspaceAnnotations annotates audit events with the shard serving the request and, for
+// resources bound from an APIExport, with the identity of that APIExport. The identity is taken from the
+// request path of identity requests (e.g. wildcard requests of the APIExport virtual workspace), and from
+// the APIBinding of the resource in the requested logical cluster otherwise.
+//
+// The logical cluster itself is annotated by filters.WithAuditEventClusterAnnotation. It must run after
+// WithRequestIdentity.
+func WithAuditEventWorkspaceAnnotations(
+	handler http.Handler,
+	shardName string,
+	getBoundResourceIdentity func(clusterName logicalcluster.Name, gr schema.GroupResource) string,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		if shardName != "" {
+			kaudit.AddAuditAnnotation(ctx, shardAuditAnnotation, shardName)
+		}
+		if identity := requestIdentity(req, getBoundResourceIdentity); identity != "" {
+			kaudit.AddAuditAnnotation(ctx, identityAuditAnnotation, identity)
+		}
+
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// requestIdentity returns the APIExport identity of the requested resource, or an empty string for
+// non-resource requests and resources not bound from an APIExport.
+func requestIdentity(req *http.Request, getBoundResourceIdentity func(clusterName logicalcluster.Name, gr schema.GroupResource) string) string {
+	ctx := req.Context()
+	if identity := IdentityFromContext(ctx); identity != "" {
+		return identity
+	}
+
+	requestInfo, ok := request.RequestInfoFrom(ctx)
+	if !ok || !requestInfo.IsResourceRequest {
+		return ""
+	}
+	cluster := request.ClusterFrom(ctx)
+	if cluster == nil || cluster.Name.Empty() || cluster.Wildcard {
+		return ""
+	}
+	return getBoundResourceIdentity(cluster.Name, schema.GroupResource{Group: requestInfo.APIGroup, Resource: requestInfo.Resource})
+}
+
+// WithWorkspaceAuditPolicy emits audit events for requests targeting workspaces with an audit policy,
+// see tenancyv1alpha1.AuditPolicy, whenever that policy asks for a higher level than the server-wide
+// audit policy. The server-wide audit events are emitted in any case.
+//
+// It must run after authentication and after the cluster is determined, because the workspace policy
+// is evaluated per request.
+func WithWorkspaceAuditPolicy(
+	handler http.Handler,
+	sink kaudit.Sink,
+	globalEvaluator kaudit.PolicyRuleEvaluator,
+	longRunningCheck request.LongRunningRequestCheck,
+	getClusterWorkspace func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error),
+	getClusterWorkspaceType func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error),
+) http.Handler {
+	if sink == nil {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		cluster := request.ClusterFrom(ctx)
+		if cluster == nil || cluster.Name.Empty() || cluster.Name == logicalcluster.Wildcard {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		policy, source := workspaceAuditPolicy(cluster.Name, getClusterWorkspace, getClusterWorkspaceType)
+		if policy == nil {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		attrs, err := filters.GetAuthorizerAttributes(ctx)
+		if err != nil {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		evaluator := workspaceAuditPolicyEvaluator{policy: policy}
+		if !auditLevelExceeds(evaluator, globalEvaluator, attrs) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		annotated := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			kaudit.AddAuditAnnotation(req.Context(), workspaceAuditPolicyAnnotation, source)
+			handler.ServeHTTP(w, req)
+		})
+		filters.WithAudit(annotated, sink, evaluator, longRunningCheck).ServeHTTP(w, req)
+	})
+}
+
+// workspaceAuditPolicy returns the audit policy of the given logical cluster, taken from its ClusterWorkspace or,
+// if that has none, from its ClusterWorkspaceType, and a description of where the policy was taken from.
+func workspaceAuditPolicy(
+	clusterName logicalcluster.Name,
+	getClusterWorkspace func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error),
+	getClusterWorkspaceType func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error),
+) (*tenancyv1alpha1.AuditPolicy, string) {
+	parent, name := clusterName.Split()
+	if parent.Empty() {
+		// the root workspace has no ClusterWorkspace
+		return nil, ""
+	}
+	ws, err := getClusterWorkspace(parent, name)
+	if err != nil {
+		return nil, ""
+	}
+	if ws.Spec.AuditPolicy != nil {
+		return ws.Spec.AuditPolicy, fmt.Sprintf("clusterworkspace:%s", clusterName)
+	}
+
+	typeRef := ws.Spec.Type
+	if typeRef.Name == "" {
+		return nil, ""
+	}
+	cwt, err := getClusterWorkspaceType(logicalcluster.New(typeRef.Path), tenancyv1alpha1.ObjectName(typeRef.Name))
+	if err != nil || cwt.Spec.AuditPolicy == nil {
+		return nil, ""
+	}
+	return cwt.Spec.AuditPolicy, fmt.Sprintf("clusterworkspacetype:%s:%s", typeRef.Path, typeRef.Name)
+}
+
+// auditLevelExceeds returns true if the workspace evaluator yields a higher level for the given request than
+// the global one.
+func auditLevelExceeds(workspace, global kaudit.PolicyRuleEvaluator, attrs authorizer.Attributes) bool {
+	workspaceLevel := workspace.EvaluatePolicyRule(attrs).Level
+	if workspaceLevel == auditinternal.LevelNone {
+		return false
+	}
+	if global == nil {
+		return true
+	}
+	return global.EvaluatePolicyRule(attrs).Level.Less(workspaceLevel)
+}
+
+// workspaceAuditPolicyEvaluator is an audit policy evaluator applying the level and stages of a workspace
+// audit policy to every request.
+type workspaceAuditPolicyEvaluator struct {
+	policy *tenancyv1alpha1.AuditPolicy
+}
+
+func (e workspaceAuditPolicyEvaluator) EvaluatePolicyRule(_ authorizer.Attributes) kaudit.RequestAuditConfigWithLevel {
+	omitStages := make([]auditinternal.Stage, 0, len(e.policy.OmitStages))
+	for _, stage := range e.policy.OmitStages {
+		omitStages = append(omitStages, auditinternal.Stage(stage))
+	}
+	return kaudit.RequestAuditConfigWithLevel{
+		Level: auditinternal.Level(e.policy.Level),
+		RequestAuditConfig: kaudit.RequestAuditConfig{
+			OmitStages: omitStages,
+		},
+	}
+}
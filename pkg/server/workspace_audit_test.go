@@ -0,0 +1,197 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	kaudit "k8s.io/apiserver/pkg/audit"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func TestWorkspaceAuditPolicy(t *testing.T) {
+	verbose := &tenancyv1alpha1.AuditPolicy{Level: tenancyv1alpha1.AuditLevelRequestResponse}
+	metadata := &tenancyv1alpha1.AuditPolicy{Level: tenancyv1alpha1.AuditLevelMetadata}
+
+	workspaces := map[logicalcluster.Name]*tenancyv1alpha1.ClusterWorkspace{
+		logicalcluster.New("root:org:regulated"): {
+			ObjectMeta: metav1.ObjectMeta{Name: "regulated"},
+			Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+				Type:        tenancyv1alpha1.ClusterWorkspaceTypeReference{Name: "audited", Path: "root"},
+				AuditPolicy: metadata,
+			},
+		},
+		logicalcluster.New("root:org:typed"): {
+			ObjectMeta: metav1.ObjectMeta{Name: "typed"},
+			Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+				Type: tenancyv1alpha1.ClusterWorkspaceTypeReference{Name: "audited", Path: "root"},
+			},
+		},
+		logicalcluster.New("root:org:plain"): {
+			ObjectMeta: metav1.ObjectMeta{Name: "plain"},
+			Spec: tenancyv1alpha1.ClusterWorkspaceSpec{
+				Type: tenancyv1alpha1.ClusterWorkspaceTypeReference{Name: "universal", Path: "root"},
+			},
+		},
+	}
+	types := map[string]*tenancyv1alpha1.ClusterWorkspaceType{
+		"audited":   {Spec: tenancyv1alpha1.ClusterWorkspaceTypeSpec{AuditPolicy: verbose}},
+		"universal": {},
+	}
+	getClusterWorkspace := func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspace, error) {
+		if ws, ok := workspaces[clusterName.Join(name)]; ok {
+			return ws, nil
+		}
+		return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspaces"), name)
+	}
+	getClusterWorkspaceType := func(clusterName logicalcluster.Name, name string) (*tenancyv1alpha1.ClusterWorkspaceType, error) {
+		require.Equal(t, "root", clusterName.String())
+		if cwt, ok := types[name]; ok {
+			return cwt, nil
+		}
+		return nil, apierrors.NewNotFound(tenancyv1alpha1.Resource("clusterworkspacetypes"), name)
+	}
+
+	tests := map[string]struct {
+		clusterName logicalcluster.Name
+		wantPolicy  *tenancyv1alpha1.AuditPolicy
+		wantSource  string
+	}{
+		"workspace policy takes precedence": {
+			clusterName: logicalcluster.New("root:org:regulated"),
+			wantPolicy:  metadata,
+			wantSource:  "clusterworkspace:root:org:regulated",
+		},
+		"type policy": {
+			clusterName: logicalcluster.New("root:org:typed"),
+			wantPolicy:  verbose,
+			wantSource:  "clusterworkspacetype:root:audited",
+		},
+		"no policy": {
+			clusterName: logicalcluster.New("root:org:plain"),
+		},
+		"unknown workspace": {
+			clusterName: logicalcluster.New("root:org:unknown"),
+		},
+		"root": {
+			clusterName: logicalcluster.New("root"),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy, source := workspaceAuditPolicy(tt.clusterName, getClusterWorkspace, getClusterWorkspaceType)
+			require.Equal(t, tt.wantPolicy, policy)
+			require.Equal(t, tt.wantSource, source)
+		})
+	}
+}
+
+func TestAuditLevelExceeds(t *testing.T) {
+	evaluator := func(level tenancyv1alpha1.AuditLevel) kaudit.PolicyRuleEvaluator {
+		return workspaceAuditPolicyEvaluator{policy: &tenancyv1alpha1.AuditPolicy{Level: level}}
+	}
+
+	tests := map[string]struct {
+		workspace kaudit.PolicyRuleEvaluator
+		global    kaudit.PolicyRuleEvaluator
+		want      bool
+	}{
+		"more verbose than global":     {workspace: evaluator(tenancyv1alpha1.AuditLevelRequestResponse), global: evaluator(tenancyv1alpha1.AuditLevelMetadata), want: true},
+		"as verbose as global":         {workspace: evaluator(tenancyv1alpha1.AuditLevelMetadata), global: evaluator(tenancyv1alpha1.AuditLevelMetadata)},
+		"less verbose than global":     {workspace: evaluator(tenancyv1alpha1.AuditLevelNone), global: evaluator(tenancyv1alpha1.AuditLevelRequest)},
+		"no global policy":             {workspace: evaluator(tenancyv1alpha1.AuditLevelMetadata), want: true},
+		"none without a global policy": {workspace: evaluator(tenancyv1alpha1.AuditLevelNone)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, auditLevelExceeds(tt.workspace, tt.global, &authorizer.AttributesRecord{}))
+		})
+	}
+}
+
+func TestWorkspaceAuditPolicyEvaluator(t *testing.T) {
+	e := workspaceAuditPolicyEvaluator{policy: &tenancyv1alpha1.AuditPolicy{
+		Level:      tenancyv1alpha1.AuditLevelRequest,
+		OmitStages: []tenancyv1alpha1.AuditStage{tenancyv1alpha1.AuditStageRequestReceived},
+	}}
+	config := e.EvaluatePolicyRule(&authorizer.AttributesRecord{})
+	require.Equal(t, auditinternal.LevelRequest, config.Level)
+	require.Equal(t, []auditinternal.Stage{auditinternal.StageRequestReceived}, config.OmitStages)
+}
+
+func TestRequestIdentity(t *testing.T) {
+	getBoundResourceIdentity := func(clusterName logicalcluster.Name, gr schema.GroupResource) string {
+		if clusterName == logicalcluster.New("root:consumer") && gr == (schema.GroupResource{Group: "example.com", Resource: "widgets"}) {
+			return "bound-identity"
+		}
+		return ""
+	}
+
+	tests := map[string]struct {
+		cluster     request.Cluster
+		requestInfo *request.RequestInfo
+		identity    string
+		want        string
+	}{
+		"identity in path": {
+			cluster:     request.Cluster{Name: logicalcluster.Wildcard, Wildcard: true},
+			requestInfo: &request.RequestInfo{IsResourceRequest: true, APIGroup: "example.com", Resource: "widgets"},
+			identity:    "path-identity",
+			want:        "path-identity",
+		},
+		"bound resource": {
+			cluster:     request.Cluster{Name: logicalcluster.New("root:consumer")},
+			requestInfo: &request.RequestInfo{IsResourceRequest: true, APIGroup: "example.com", Resource: "widgets"},
+			want:        "bound-identity",
+		},
+		"unbound resource": {
+			cluster:     request.Cluster{Name: logicalcluster.New("root:consumer")},
+			requestInfo: &request.RequestInfo{IsResourceRequest: true, Resource: "configmaps"},
+		},
+		"wildcard without identity": {
+			cluster:     request.Cluster{Name: logicalcluster.Wildcard, Wildcard: true},
+			requestInfo: &request.RequestInfo{IsResourceRequest: true, APIGroup: "example.com", Resource: "widgets"},
+		},
+		"non-resource request": {
+			cluster:     request.Cluster{Name: logicalcluster.New("root:consumer")},
+			requestInfo: &request.RequestInfo{Path: "/healthz"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			ctx := request.WithCluster(req.Context(), tt.cluster)
+			ctx = request.WithRequestInfo(ctx, tt.requestInfo)
+			if tt.identity != "" {
+				ctx = WithIdentity(ctx, tt.identity)
+			}
+
+			require.Equal(t, tt.want, requestIdentity(req.WithContext(ctx), getBoundResourceIdentity))
+		})
+	}
+}
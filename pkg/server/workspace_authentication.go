@@ -0,0 +1,377 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	tokencache "k8s.io/apiserver/pkg/authentication/token/cache"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	genericoptions "k8s.io/apiserver/pkg/server/options"
+	"k8s.io/apiserver/plugin/pkg/authenticator/token/oidc"
+	"k8s.io/apiserver/plugin/pkg/authenticator/token/webhook"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kcp-dev/kcp/pkg/admission/workspaceauthentication"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	tenancyinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/tenancy/v1alpha1"
+)
+
+// WorkspaceAuthenticationIssuerUserExtraKey is the user extra key holding the issuer URL of users
+// authenticated by a WorkspaceAuthenticationConfiguration.
+const WorkspaceAuthenticationIssuerUserExtraKey = "authentication.kcp.dev/issuer"
+
+// webhookTokenCacheTTL is how long the results of TokenReview webhooks are cached, as with the default
+// of --authentication-token-webhook-cache-ttl.
+const webhookTokenCacheTTL = 2 * time.Minute
+
+// closableTokenAuthenticator is a token authenticator holding resources, e.g. the key set refresher
+// of an OIDC authenticator.
+type closableTokenAuthenticator interface {
+	authenticator.Token
+	Close()
+}
+
+// WithWorkspaceAuthentication wraps the given authenticator such that bearer tokens rejected by it are
+// authenticated by the OIDC issuers configured through WorkspaceAuthenticationConfigurations in the logical
+// cluster of the request, or in one of its ancestors. The configurations of the closest workspace are tried
+// first.
+//
+// Configurations with a TokenReview webhook are only honored in organization workspaces. The webhook receives
+// the tokens, hence a workspace below an organization must not be able to collect the tokens of its ancestors'
+// users.
+//
+// Usernames and groups of workspace issuers are always prefixed with the logical cluster of the configuration
+// and the issuer, such that they cannot assert server-wide users and groups, e.g. of the system: prefix, nor
+// those of other workspaces' issuers.
+//
+// Issuers and webhooks are only reached via https, and unless allowPrivateNetworks is set, not on loopback,
+// link-local or private addresses. Per logical cluster, only the first MaxConfigurationsPerWorkspace
+// configurations by name are tried.
+//
+// The authenticators of deleted configurations are closed.
+func WithWorkspaceAuthentication(delegate authenticator.Request, configInformer tenancyinformers.WorkspaceAuthenticationConfigurationClusterInformer, allowPrivateNetworks bool) authenticator.Request {
+	a := newWorkspaceAuthenticator(delegate, func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceAuthenticationConfiguration, error) {
+		return configInformer.Lister().Cluster(clusterName).List(labels.Everything())
+	}, allowPrivateNetworks)
+	configInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+			if err != nil {
+				utilruntime.HandleError(err)
+				return
+			}
+			clusterName, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+			if err != nil {
+				utilruntime.HandleError(err)
+				return
+			}
+			a.forget(clusterName, name)
+		},
+	})
+	return a
+}
+
+func newWorkspaceAuthenticator(delegate authenticator.Request, listConfigs func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceAuthenticationConfiguration, error), allowPrivateNetworks bool) *workspaceAuthenticator {
+	return &workspaceAuthenticator{
+		delegate:    delegate,
+		listConfigs: listConfigs,
+		newTokenAuthenticator: func(config *tenancyv1alpha1.WorkspaceAuthenticationConfiguration) (closableTokenAuthenticator, error) {
+			return newTokenAuthenticator(config, allowPrivateNetworks)
+		},
+		authenticators: map[string]*cachedTokenAuthenticator{},
+	}
+}
+
+type workspaceAuthenticator struct {
+	delegate              authenticator.Request
+	listConfigs           func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceAuthenticationConfiguration, error)
+	newTokenAuthenticator func(config *tenancyv1alpha1.WorkspaceAuthenticationConfiguration) (closableTokenAuthenticator, error)
+
+	lock           sync.Mutex
+	authenticators map[string]*cachedTokenAuthenticator
+}
+
+type cachedTokenAuthenticator struct {
+	resourceVersion string
+	authenticator   closableTokenAuthenticator
+}
+
+func (a *workspaceAuthenticator) AuthenticateRequest(req *http.Request) (*authenticator.Response, bool, error) {
+	resp, ok, delegateErr := a.delegate.AuthenticateRequest(req)
+	if ok {
+		return resp, ok, delegateErr
+	}
+
+	token, found := bearerToken(req)
+	if !found {
+		return resp, ok, delegateErr
+	}
+	cluster := request.ClusterFrom(req.Context())
+	if cluster == nil || cluster.Name.Empty() || cluster.Name == logicalcluster.Wildcard {
+		return resp, ok, delegateErr
+	}
+
+	errs := []error{}
+	if delegateErr != nil {
+		errs = append(errs, delegateErr)
+	}
+	for clusterName, hasParent := cluster.Name, true; hasParent; clusterName, hasParent = clusterName.Parent() {
+		configs, err := a.listConfigs(clusterName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// admission caps the configurations, but might race with concurrent creations.
+		configs = append([]*tenancyv1alpha1.WorkspaceAuthenticationConfiguration(nil), configs...)
+		sort.Slice(configs, func(i, j int) bool { return configs[i].Name < configs[j].Name })
+		if len(configs) > workspaceauthentication.MaxConfigurationsPerWorkspace {
+			configs = configs[:workspaceauthentication.MaxConfigurationsPerWorkspace]
+		}
+		for _, config := range configs {
+			if config.Spec.Webhook != nil && !isOrganization(clusterName) {
+				continue
+			}
+			tokenAuthenticator, err := a.tokenAuthenticatorFor(clusterName, config)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			resp, ok, err := tokenAuthenticator.AuthenticateToken(req.Context(), token)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("issuer %q of logical cluster %q: %w", issuerOf(config), clusterName, err))
+				continue
+			}
+			if !ok {
+				continue
+			}
+			resp = &authenticator.Response{
+				Audiences: resp.Audiences,
+				User:      workspaceUser(resp.User, clusterName, config),
+			}
+			return resp, true, nil
+		}
+	}
+
+	return nil, false, utilerrors.NewAggregate(errs)
+}
+
+// tokenAuthenticatorFor returns the cached authenticator of the given configuration, or creates a new one if the
+// configuration is new or has changed. Replaced authenticators are closed.
+func (a *workspaceAuthenticator) tokenAuthenticatorFor(clusterName logicalcluster.Name, config *tenancyv1alpha1.WorkspaceAuthenticationConfiguration) (authenticator.Token, error) {
+	key := clusterName.String() + "|" + config.Name
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if cached, found := a.authenticators[key]; found {
+		if cached.resourceVersion == config.ResourceVersion {
+			return cached.authenticator, nil
+		}
+		cached.authenticator.Close()
+		delete(a.authenticators, key)
+	}
+
+	tokenAuthenticator, err := a.newTokenAuthenticator(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WorkspaceAuthenticationConfiguration %s|%s: %w", clusterName, config.Name, err)
+	}
+	a.authenticators[key] = &cachedTokenAuthenticator{
+		resourceVersion: config.ResourceVersion,
+		authenticator:   tokenAuthenticator,
+	}
+	return tokenAuthenticator, nil
+}
+
+// forget closes and drops the authenticator of the given configuration, e.g. when it got deleted.
+func (a *workspaceAuthenticator) forget(clusterName logicalcluster.Name, name string) {
+	key := clusterName.String() + "|" + name
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if cached, found := a.authenticators[key]; found {
+		cached.authenticator.Close()
+		delete(a.authenticators, key)
+	}
+}
+
+// workspaceUser returns a copy of the given user of the given configuration with the prefixes of
+// the configuration applied after boundPrefixOf, in the system:authenticated group, and with the
+// issuer recorded under WorkspaceAuthenticationIssuerUserExtraKey.
+func workspaceUser(u user.Info, clusterName logicalcluster.Name, config *tenancyv1alpha1.WorkspaceAuthenticationConfiguration) user.Info {
+	prefix := boundPrefixOf(clusterName, config)
+
+	groups := make([]string, 0, len(u.GetGroups())+1)
+	groups = append(groups, user.AllAuthenticated)
+	for _, g := range u.GetGroups() {
+		groups = append(groups, prefix+config.Spec.GroupsPrefix+g)
+	}
+
+	extra := make(map[string][]string, len(u.GetExtra())+1)
+	for k, v := range u.GetExtra() {
+		extra[k] = v
+	}
+	extra[WorkspaceAuthenticationIssuerUserExtraKey] = []string{issuerOf(config)}
+
+	return &user.DefaultInfo{
+		Name:   prefix + config.Spec.UsernamePrefix + u.GetName(),
+		UID:    u.GetUID(),
+		Groups: groups,
+		Extra:  extra,
+	}
+}
+
+// boundPrefixOf returns the prefix of all usernames and groups of the given configuration of the given
+// logical cluster, "<logical cluster>|<issuer or webhook URL>#".
+func boundPrefixOf(clusterName logicalcluster.Name, config *tenancyv1alpha1.WorkspaceAuthenticationConfiguration) string {
+	return clusterName.String() + "|" + issuerOf(config) + "#"
+}
+
+// isOrganization returns true if the given logical cluster is a child of the root workspace.
+func isOrganization(clusterName logicalcluster.Name) bool {
+	parent, hasParent := clusterName.Parent()
+	return hasParent && parent == tenancyv1alpha1.RootCluster
+}
+
+// issuerOf returns the OIDC issuer URL or the webhook URL of the given configuration.
+func issuerOf(config *tenancyv1alpha1.WorkspaceAuthenticationConfiguration) string {
+	if config.Spec.Webhook != nil {
+		return config.Spec.Webhook.URL
+	}
+	return config.Spec.IssuerURL
+}
+
+func newTokenAuthenticator(config *tenancyv1alpha1.WorkspaceAuthenticationConfiguration, allowPrivateNetworks bool) (closableTokenAuthenticator, error) {
+	// admission checks this too, but configurations might predate it.
+	if u, err := url.Parse(issuerOf(config)); err != nil || u.Scheme != "https" {
+		return nil, fmt.Errorf("issuer %q is not an https URL", issuerOf(config))
+	}
+
+	if config.Spec.Webhook != nil {
+		return newWebhookAuthenticator(config, allowPrivateNetworks)
+	}
+	return newOIDCAuthenticator(config, allowPrivateNetworks)
+}
+
+func newOIDCAuthenticator(config *tenancyv1alpha1.WorkspaceAuthenticationConfiguration, allowPrivateNetworks bool) (closableTokenAuthenticator, error) {
+	usernameClaim := config.Spec.UsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = "sub"
+	}
+
+	var roots *x509.CertPool
+	if config.Spec.CertificateAuthority != "" {
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM([]byte(config.Spec.CertificateAuthority)) {
+			return nil, fmt.Errorf("certificate authority of issuer %q holds no PEM encoded certificates", config.Spec.IssuerURL)
+		}
+	}
+	transport := utilnet.SetTransportDefaults(&http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: roots},
+		DialContext:     restrictedDialer(allowPrivateNetworks).DialContext,
+		Proxy:           restrictedProxy(allowPrivateNetworks),
+	})
+
+	// the prefixes are applied by workspaceUser.
+	return oidc.New(oidc.Options{
+		IssuerURL:     config.Spec.IssuerURL,
+		ClientID:      config.Spec.ClientID,
+		UsernameClaim: usernameClaim,
+		GroupsClaim:   config.Spec.GroupsClaim,
+		Client:        &http.Client{Transport: transport, Timeout: 30 * time.Second},
+	})
+}
+
+func newWebhookAuthenticator(config *tenancyv1alpha1.WorkspaceAuthenticationConfiguration, allowPrivateNetworks bool) (closableTokenAuthenticator, error) {
+	clientConfig := &rest.Config{
+		Host: config.Spec.Webhook.URL,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: []byte(config.Spec.Webhook.CertificateAuthority),
+		},
+		Timeout: 10 * time.Second,
+		Dial:    restrictedDialer(allowPrivateNetworks).DialContext,
+		Proxy:   restrictedProxy(allowPrivateNetworks),
+	}
+	webhookAuthenticator, err := webhook.New(clientConfig, "v1", nil, *genericoptions.DefaultAuthWebhookRetryBackoff())
+	if err != nil {
+		return nil, err
+	}
+
+	// the prefixes are applied by workspaceUser.
+	return &unclosableTokenAuthenticator{
+		Token: tokencache.New(webhookAuthenticator, false, webhookTokenCacheTTL, webhookTokenCacheTTL),
+	}, nil
+}
+
+// restrictedDialer returns a dialer refusing connections to the addresses of workspaceauthentication.IsPrivateIP,
+// unless allowPrivateNetworks is set. The check applies to the resolved address, hence also to host names
+// resolving to such addresses and to redirects.
+func restrictedDialer(allowPrivateNetworks bool) *net.Dialer {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if allowPrivateNetworks {
+		return dialer
+	}
+	dialer.Control = func(network, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		if ip := net.ParseIP(host); ip == nil || workspaceauthentication.IsPrivateIP(ip) {
+			return fmt.Errorf("connecting to %s is not allowed for workspace authentication", host)
+		}
+		return nil
+	}
+	return dialer
+}
+
+// restrictedProxy returns the proxy function for issuer and webhook requests. A proxy from the environment
+// would be dialed instead of the issuer, hence it is only used if allowPrivateNetworks is set.
+func restrictedProxy(allowPrivateNetworks bool) func(*http.Request) (*url.URL, error) {
+	if allowPrivateNetworks {
+		return utilnet.NewProxierWithNoProxyCIDR(http.ProxyFromEnvironment)
+	}
+	return func(*http.Request) (*url.URL, error) {
+		return nil, nil
+	}
+}
+
+// unclosableTokenAuthenticator is a token authenticator without resources to release.
+type unclosableTokenAuthenticator struct {
+	authenticator.Token
+}
+
+func (a *unclosableTokenAuthenticator) Close() {}
@@ -0,0 +1,260 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kcp-dev/kcp/pkg/admission/workspaceauthentication"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// fakeTokenAuthenticator accepts the tokens in its map.
+type fakeTokenAuthenticator struct {
+	users  map[string]*user.DefaultInfo
+	closed bool
+}
+
+func (f *fakeTokenAuthenticator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	if u, found := f.users[token]; found {
+		return &authenticator.Response{User: u}, true, nil
+	}
+	return nil, false, nil
+}
+
+func (f *fakeTokenAuthenticator) Close() {
+	f.closed = true
+}
+
+func TestWithWorkspaceAuthentication(t *testing.T) {
+	config := func(name, issuer, resourceVersion string) *tenancyv1alpha1.WorkspaceAuthenticationConfiguration {
+		return &tenancyv1alpha1.WorkspaceAuthenticationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name, ResourceVersion: resourceVersion},
+			Spec:       tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec{IssuerURL: issuer},
+		}
+	}
+	prefixedConfig := func(name, issuer, usernamePrefix, groupsPrefix string) *tenancyv1alpha1.WorkspaceAuthenticationConfiguration {
+		c := config(name, issuer, "1")
+		c.Spec.UsernamePrefix = usernamePrefix
+		c.Spec.GroupsPrefix = groupsPrefix
+		return c
+	}
+	webhookConfig := func(name, url string) *tenancyv1alpha1.WorkspaceAuthenticationConfiguration {
+		return &tenancyv1alpha1.WorkspaceAuthenticationConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name, ResourceVersion: "1"},
+			Spec:       tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec{Webhook: &tenancyv1alpha1.WorkspaceAuthenticationWebhook{URL: url}},
+		}
+	}
+	configs := map[logicalcluster.Name][]*tenancyv1alpha1.WorkspaceAuthenticationConfiguration{
+		logicalcluster.New("root:org"):      {config("org", "https://org.example.com", "1"), webhookConfig("org-webhook", "https://idp.org.example.com")},
+		logicalcluster.New("root:other"):    {config("other", "https://other.example.com", "1")},
+		logicalcluster.New("root:acme"):     {prefixedConfig("acme", "https://acme.example.com", "acme:", "acme:")},
+		logicalcluster.New("root:org:team"): {config("team", "https://team.example.com", "1"), webhookConfig("team-webhook", "https://idp.team.example.com")},
+	}
+	authenticators := map[string]*fakeTokenAuthenticator{
+		"https://org.example.com": {users: map[string]*user.DefaultInfo{
+			"org-token":    {Name: "alice", Groups: []string{"devs", "system:masters"}},
+			"system-token": {Name: "system:admin"},
+			"server-user":  {Name: "server-user"},
+		}},
+		"https://other.example.com": {users: map[string]*user.DefaultInfo{
+			"other-token": {Name: "bob"},
+		}},
+		"https://acme.example.com": {users: map[string]*user.DefaultInfo{
+			"acme-token": {Name: "frank", Groups: []string{"devs"}},
+		}},
+		"https://team.example.com": {users: map[string]*user.DefaultInfo{
+			"team-token": {Name: "carol"},
+		}},
+		"https://idp.org.example.com": {users: map[string]*user.DefaultInfo{
+			"org-webhook-token": {Name: "dave"},
+		}},
+		"https://idp.team.example.com": {users: map[string]*user.DefaultInfo{
+			"team-webhook-token": {Name: "eve"},
+		}},
+	}
+
+	// only the first MaxConfigurationsPerWorkspace configurations by name are tried
+	for i := workspaceauthentication.MaxConfigurationsPerWorkspace; i >= 0; i-- {
+		issuer := fmt.Sprintf("https://idp-%02d.example.com", i)
+		configs[logicalcluster.New("root:many")] = append(configs[logicalcluster.New("root:many")], config(fmt.Sprintf("idp-%02d", i), issuer, "1"))
+		authenticators[issuer] = &fakeTokenAuthenticator{users: map[string]*user.DefaultInfo{
+			fmt.Sprintf("idp-%02d-token", i): {Name: "grace"},
+		}}
+	}
+
+	delegate := authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+		if token, _ := bearerToken(req); token == "server-token" {
+			return &authenticator.Response{User: &user.DefaultInfo{Name: "server-user"}}, true, nil
+		}
+		return nil, false, nil
+	})
+	a := newWorkspaceAuthenticator(delegate, func(clusterName logicalcluster.Name) ([]*tenancyv1alpha1.WorkspaceAuthenticationConfiguration, error) {
+		return configs[clusterName], nil
+	}, false)
+	a.newTokenAuthenticator = func(config *tenancyv1alpha1.WorkspaceAuthenticationConfiguration) (closableTokenAuthenticator, error) {
+		return authenticators[issuerOf(config)], nil
+	}
+
+	authenticate := func(cluster, token string) (user.Info, bool, error) {
+		req := httptest.NewRequest(http.MethodGet, "/api", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if cluster != "" {
+			req = req.WithContext(request.WithCluster(req.Context(), request.Cluster{Name: logicalcluster.New(cluster)}))
+		}
+		resp, ok, err := a.AuthenticateRequest(req)
+		if !ok {
+			return nil, ok, err
+		}
+		return resp.User, ok, err
+	}
+
+	tests := map[string]struct {
+		cluster string
+		token   string
+
+		wantOK     bool
+		wantError  bool
+		wantUser   string
+		wantGroups []string
+	}{
+		"server-wide token":             {cluster: "root:org", token: "server-token", wantOK: true, wantUser: "server-user"},
+		"issuer of the workspace":       {cluster: "root:org", token: "org-token", wantOK: true, wantUser: "root:org|https://org.example.com#alice", wantGroups: []string{user.AllAuthenticated, "root:org|https://org.example.com#devs", "root:org|https://org.example.com#system:masters"}},
+		"issuer of an ancestor":         {cluster: "root:org:team:sub", token: "org-token", wantOK: true, wantUser: "root:org|https://org.example.com#alice", wantGroups: []string{user.AllAuthenticated, "root:org|https://org.example.com#devs", "root:org|https://org.example.com#system:masters"}},
+		"issuer of a child":             {cluster: "root:org", token: "team-token"},
+		"issuer of a sibling":           {cluster: "root:org", token: "other-token"},
+		"no cluster":                    {token: "org-token"},
+		"system user":                   {cluster: "root:org", token: "system-token", wantOK: true, wantUser: "root:org|https://org.example.com#system:admin", wantGroups: []string{user.AllAuthenticated}},
+		"server-wide user":              {cluster: "root:org", token: "server-user", wantOK: true, wantUser: "root:org|https://org.example.com#server-user", wantGroups: []string{user.AllAuthenticated}},
+		"configured prefixes":           {cluster: "root:acme", token: "acme-token", wantOK: true, wantUser: "root:acme|https://acme.example.com#acme:frank", wantGroups: []string{user.AllAuthenticated, "root:acme|https://acme.example.com#acme:devs"}},
+		"unknown token":                 {cluster: "root:org:team", token: "unknown"},
+		"issuer of a nested workspace":  {cluster: "root:org:team", token: "team-token", wantOK: true, wantUser: "root:org:team|https://team.example.com#carol", wantGroups: []string{user.AllAuthenticated}},
+		"webhook of an organization":    {cluster: "root:org:team", token: "org-webhook-token", wantOK: true, wantUser: "root:org|https://idp.org.example.com#dave", wantGroups: []string{user.AllAuthenticated}},
+		"webhook of a nested workspace": {cluster: "root:org:team", token: "team-webhook-token"},
+		"last allowed configuration":    {cluster: "root:many", token: fmt.Sprintf("idp-%02d-token", workspaceauthentication.MaxConfigurationsPerWorkspace-1), wantOK: true, wantUser: fmt.Sprintf("root:many|https://idp-%02d.example.com#grace", workspaceauthentication.MaxConfigurationsPerWorkspace-1)},
+		"configuration over the limit":  {cluster: "root:many", token: fmt.Sprintf("idp-%02d-token", workspaceauthentication.MaxConfigurationsPerWorkspace)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			u, ok, err := authenticate(tt.cluster, tt.token)
+			require.Equal(t, tt.wantError, err != nil, "unexpected error: %v", err)
+			require.Equal(t, tt.wantOK, ok)
+			if !tt.wantOK {
+				return
+			}
+			require.Equal(t, tt.wantUser, u.GetName())
+			if tt.wantGroups != nil {
+				require.Equal(t, tt.wantGroups, u.GetGroups())
+				require.Len(t, u.GetExtra()[WorkspaceAuthenticationIssuerUserExtraKey], 1)
+			}
+		})
+	}
+
+	// changed configurations replace their authenticator
+	configs[logicalcluster.New("root:org")] = []*tenancyv1alpha1.WorkspaceAuthenticationConfiguration{config("org", "https://other.example.com", "2")}
+	_, ok, err := authenticate("root:org", "other-token")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, authenticators["https://org.example.com"].closed)
+
+	// deleted configurations close their authenticator
+	a.forget(logicalcluster.New("root:org"), "unknown")
+	require.False(t, authenticators["https://other.example.com"].closed, "only the authenticator of the deleted configuration must be closed")
+	a.forget(logicalcluster.New("root:org"), "org")
+	require.True(t, authenticators["https://other.example.com"].closed)
+	require.NotContains(t, a.authenticators, "root:org|org")
+}
+
+func TestWebhookAuthenticator(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		review := &authenticationv1.TokenReview{}
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if review.Spec.Token == "alice-token" {
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: "alice", Groups: []string{"devs"}},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(review) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	a, err := newWebhookAuthenticator(&tenancyv1alpha1.WorkspaceAuthenticationConfiguration{
+		Spec: tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec{
+			Webhook: &tenancyv1alpha1.WorkspaceAuthenticationWebhook{
+				URL:                  server.URL,
+				CertificateAuthority: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+			},
+			GroupsPrefix: "idp:",
+		},
+	}, true)
+	require.NoError(t, err)
+	defer a.Close()
+
+	resp, ok, err := a.AuthenticateToken(context.Background(), "alice-token")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "alice", resp.User.GetName(), "prefixes are applied by the workspace authenticator")
+	require.Equal(t, []string{"devs"}, resp.User.GetGroups())
+
+	_, ok, err = a.AuthenticateToken(context.Background(), "unknown")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestRestrictedDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	_, err = restrictedDialer(false).DialContext(context.Background(), "tcp", listener.Addr().String())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed for workspace authentication")
+
+	conn, err := restrictedDialer(true).DialContext(context.Background(), "tcp", listener.Addr().String())
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestNewTokenAuthenticatorRequiresHTTPS(t *testing.T) {
+	_, err := newTokenAuthenticator(&tenancyv1alpha1.WorkspaceAuthenticationConfiguration{
+		Spec: tenancyv1alpha1.WorkspaceAuthenticationConfigurationSpec{
+			Webhook: &tenancyv1alpha1.WorkspaceAuthenticationWebhook{URL: "http://idp.example.com"},
+		},
+	}, false)
+	require.Error(t, err)
+}
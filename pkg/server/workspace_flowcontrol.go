@@ -0,0 +1,312 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	flowcontrolv1beta2 "k8s.io/api/flowcontrol/v1beta2"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/filters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+)
+
+// workspaceFlowControlQueueWaitLimit is how long a request waits for a seat of a queuing priority level
+// of its workspace before it is rejected.
+const workspaceFlowControlQueueWaitLimit = 15 * time.Second
+
+// WithWorkspacePriorityAndFairness throttles requests by the FlowSchemas and PriorityLevelConfigurations
+// of the logical cluster they target, so that a noisy workspace is limited independently of all other
+// workspaces. Requests not matching a FlowSchema of their logical cluster, long-running requests and
+// wildcard requests are not affected, and remain subject to the server-wide priority and fairness only.
+//
+// Within a workspace, the FlowSchema with the lowest matchingPrecedence matching the request selects the
+// priority level. For a Limited priority level, assuredConcurrencyShares is the number of requests of the
+// workspace executing concurrently at that level. Further requests are rejected, or with a Queue limit
+// response wait for up to queueLengthLimit requests, and are rejected after 15 seconds.
+//
+// It must run after authentication and after the cluster is determined.
+func WithWorkspacePriorityAndFairness(
+	handler http.Handler,
+	longRunningCheck request.LongRunningRequestCheck,
+	listFlowSchemas func(clusterName logicalcluster.Name) ([]*flowcontrolv1beta2.FlowSchema, error),
+	getPriorityLevel func(clusterName logicalcluster.Name, name string) (*flowcontrolv1beta2.PriorityLevelConfiguration, error),
+) http.Handler {
+	limiters := &workspaceLimiters{}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		cluster := request.ClusterFrom(ctx)
+		if cluster == nil || cluster.Name.Empty() || cluster.Wildcard || cluster.Name == logicalcluster.Wildcard {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		requestInfo, ok := request.RequestInfoFrom(ctx)
+		if !ok || longRunningCheck(req, requestInfo) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		attrs, err := filters.GetAuthorizerAttributes(ctx)
+		if err != nil {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		flowSchemas, err := listFlowSchemas(cluster.Name)
+		if err != nil || len(flowSchemas) == 0 {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		flowSchema := matchingFlowSchema(flowSchemas, attrs)
+		if flowSchema == nil {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		priorityLevel, err := getPriorityLevel(cluster.Name, flowSchema.Spec.PriorityLevelConfiguration.Name)
+		if err != nil || priorityLevel.Spec.Type != flowcontrolv1beta2.PriorityLevelEnablementLimited || priorityLevel.Spec.Limited == nil {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		limiter := limiters.get(cluster.Name, priorityLevel)
+		if !limiter.acquire(ctx) {
+			klog.FromContext(ctx).V(4).Info("rejecting request by workspace priority level", "cluster", cluster.Name, "flowSchema", flowSchema.Name, "priorityLevel", priorityLevel.Name)
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Too many requests in this workspace, please try again later.", http.StatusTooManyRequests)
+			return
+		}
+		defer limiter.release()
+
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// matchingFlowSchema returns the FlowSchema with the lowest matchingPrecedence, then name, matching the
+// request, or nil if none does.
+func matchingFlowSchema(flowSchemas []*flowcontrolv1beta2.FlowSchema, attrs authorizer.Attributes) *flowcontrolv1beta2.FlowSchema {
+	sorted := make([]*flowcontrolv1beta2.FlowSchema, len(flowSchemas))
+	copy(sorted, flowSchemas)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Spec.MatchingPrecedence != sorted[j].Spec.MatchingPrecedence {
+			return sorted[i].Spec.MatchingPrecedence < sorted[j].Spec.MatchingPrecedence
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	for _, fs := range sorted {
+		for _, rule := range fs.Spec.Rules {
+			if matchesPolicyRule(rule, attrs) {
+				return fs
+			}
+		}
+	}
+	return nil
+}
+
+func matchesPolicyRule(rule flowcontrolv1beta2.PolicyRulesWithSubjects, attrs authorizer.Attributes) bool {
+	if !matchesSubjects(rule.Subjects, attrs.GetUser()) {
+		return false
+	}
+	if attrs.IsResourceRequest() {
+		for _, r := range rule.ResourceRules {
+			if matchesResourceRule(r, attrs) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, r := range rule.NonResourceRules {
+		if matchesNonResourceRule(r, attrs) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesSubjects(subjects []flowcontrolv1beta2.Subject, u user.Info) bool {
+	if u == nil {
+		return false
+	}
+	for _, s := range subjects {
+		switch s.Kind {
+		case flowcontrolv1beta2.SubjectKindUser:
+			if s.User != nil && (s.User.Name == flowcontrolv1beta2.NameAll || s.User.Name == u.GetName()) {
+				return true
+			}
+		case flowcontrolv1beta2.SubjectKindGroup:
+			if s.Group == nil {
+				continue
+			}
+			for _, g := range u.GetGroups() {
+				if s.Group.Name == flowcontrolv1beta2.NameAll || s.Group.Name == g {
+					return true
+				}
+			}
+		case flowcontrolv1beta2.SubjectKindServiceAccount:
+			if s.ServiceAccount == nil {
+				continue
+			}
+			if s.ServiceAccount.Name == flowcontrolv1beta2.NameAll {
+				if namespace, _, err := serviceaccount.SplitUsername(u.GetName()); err == nil && namespace == s.ServiceAccount.Namespace {
+					return true
+				}
+			} else if serviceaccount.MatchesUsername(s.ServiceAccount.Namespace, s.ServiceAccount.Name, u.GetName()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesResourceRule(rule flowcontrolv1beta2.ResourcePolicyRule, attrs authorizer.Attributes) bool {
+	resource := attrs.GetResource()
+	if attrs.GetSubresource() != "" {
+		resource += "/" + attrs.GetSubresource()
+	}
+	if !matchesAny(rule.Verbs, attrs.GetVerb(), flowcontrolv1beta2.VerbAll) ||
+		!matchesAny(rule.APIGroups, attrs.GetAPIGroup(), flowcontrolv1beta2.APIGroupAll) ||
+		!matchesAny(rule.Resources, resource, flowcontrolv1beta2.ResourceAll) {
+		return false
+	}
+	if attrs.GetNamespace() == "" {
+		return rule.ClusterScope
+	}
+	return matchesAny(rule.Namespaces, attrs.GetNamespace(), flowcontrolv1beta2.NamespaceEvery)
+}
+
+func matchesNonResourceRule(rule flowcontrolv1beta2.NonResourcePolicyRule, attrs authorizer.Attributes) bool {
+	if !matchesAny(rule.Verbs, attrs.GetVerb(), flowcontrolv1beta2.VerbAll) {
+		return false
+	}
+	for _, u := range rule.NonResourceURLs {
+		if u == flowcontrolv1beta2.NonResourceAll || u == attrs.GetPath() {
+			return true
+		}
+		if strings.HasSuffix(u, "*") && strings.HasPrefix(attrs.GetPath(), strings.TrimSuffix(u, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(values []string, value, all string) bool {
+	for _, v := range values {
+		if v == all || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// workspaceLimiters holds the concurrency limiters of the priority levels of all workspaces.
+type workspaceLimiters struct {
+	lock     sync.Mutex
+	limiters map[workspacePriorityLevel]*workspaceLimiter
+}
+
+type workspacePriorityLevel struct {
+	clusterName logicalcluster.Name
+	name        string
+}
+
+// get returns the limiter of the given priority level of the given logical cluster. It is replaced when
+// the priority level configuration changes, while requests executing at the old one release their seats
+// there.
+func (l *workspaceLimiters) get(clusterName logicalcluster.Name, pl *flowcontrolv1beta2.PriorityLevelConfiguration) *workspaceLimiter {
+	seats := int(pl.Spec.Limited.AssuredConcurrencyShares)
+	if seats < 1 {
+		seats = 1
+	}
+	queueLength := 0
+	if r := pl.Spec.Limited.LimitResponse; r.Type == flowcontrolv1beta2.LimitResponseTypeQueue && r.Queuing != nil {
+		queueLength = int(r.Queuing.QueueLengthLimit)
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	key := workspacePriorityLevel{clusterName: clusterName, name: pl.Name}
+	if limiter, found := l.limiters[key]; found && cap(limiter.seats) == seats && limiter.queueLength == queueLength {
+		return limiter
+	}
+	if l.limiters == nil {
+		l.limiters = map[workspacePriorityLevel]*workspaceLimiter{}
+	}
+	limiter := &workspaceLimiter{
+		seats:       make(chan struct{}, seats),
+		queueLength: queueLength,
+	}
+	l.limiters[key] = limiter
+	return limiter
+}
+
+// workspaceLimiter limits the concurrency of the requests of one priority level of one workspace.
+type workspaceLimiter struct {
+	seats       chan struct{}
+	queueLength int
+
+	lock   sync.Mutex
+	queued int
+}
+
+// acquire takes a seat, possibly waiting in the queue. It returns false if the request is rejected.
+func (l *workspaceLimiter) acquire(ctx context.Context) bool {
+	select {
+	case l.seats <- struct{}{}:
+		return true
+	default:
+	}
+
+	l.lock.Lock()
+	if l.queued >= l.queueLength {
+		l.lock.Unlock()
+		return false
+	}
+	l.queued++
+	l.lock.Unlock()
+	defer func() {
+		l.lock.Lock()
+		l.queued--
+		l.lock.Unlock()
+	}()
+
+	timer := time.NewTimer(workspaceFlowControlQueueWaitLimit)
+	defer timer.Stop()
+	select {
+	case l.seats <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (l *workspaceLimiter) release() {
+	<-l.seats
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	flowcontrolv1beta2 "k8s.io/api/flowcontrol/v1beta2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestMatchingFlowSchema(t *testing.T) {
+	flowSchema := func(name string, precedence int32, subject flowcontrolv1beta2.Subject, rule flowcontrolv1beta2.ResourcePolicyRule) *flowcontrolv1beta2.FlowSchema {
+		return &flowcontrolv1beta2.FlowSchema{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: flowcontrolv1beta2.FlowSchemaSpec{
+				MatchingPrecedence: precedence,
+				Rules: []flowcontrolv1beta2.PolicyRulesWithSubjects{{
+					Subjects:      []flowcontrolv1beta2.Subject{subject},
+					ResourceRules: []flowcontrolv1beta2.ResourcePolicyRule{rule},
+				}},
+			},
+		}
+	}
+	allUsers := flowcontrolv1beta2.Subject{Kind: flowcontrolv1beta2.SubjectKindGroup, Group: &flowcontrolv1beta2.GroupSubject{Name: user.AllAuthenticated}}
+	controller := flowcontrolv1beta2.Subject{Kind: flowcontrolv1beta2.SubjectKindServiceAccount, ServiceAccount: &flowcontrolv1beta2.ServiceAccountSubject{Namespace: "ci", Name: flowcontrolv1beta2.NameAll}}
+	allResources := flowcontrolv1beta2.ResourcePolicyRule{
+		Verbs:        []string{flowcontrolv1beta2.VerbAll},
+		APIGroups:    []string{flowcontrolv1beta2.APIGroupAll},
+		Resources:    []string{flowcontrolv1beta2.ResourceAll},
+		ClusterScope: true,
+		Namespaces:   []string{flowcontrolv1beta2.NamespaceEvery},
+	}
+	configMaps := flowcontrolv1beta2.ResourcePolicyRule{
+		Verbs:      []string{"create", "update"},
+		APIGroups:  []string{""},
+		Resources:  []string{"configmaps"},
+		Namespaces: []string{"default"},
+	}
+
+	flowSchemas := []*flowcontrolv1beta2.FlowSchema{
+		flowSchema("catch-all", 10000, allUsers, allResources),
+		flowSchema("ci-configmaps", 100, controller, configMaps),
+		flowSchema("ci", 500, controller, allResources),
+	}
+
+	tests := map[string]struct {
+		attrs authorizer.AttributesRecord
+		want  string
+	}{
+		"ci writing configmaps": {
+			attrs: authorizer.AttributesRecord{
+				User:            &user.DefaultInfo{Name: "system:serviceaccount:ci:builder", Groups: []string{user.AllAuthenticated}},
+				Verb:            "create",
+				Namespace:       "default",
+				Resource:        "configmaps",
+				ResourceRequest: true,
+			},
+			want: "ci-configmaps",
+		},
+		"ci reading configmaps": {
+			attrs: authorizer.AttributesRecord{
+				User:            &user.DefaultInfo{Name: "system:serviceaccount:ci:builder", Groups: []string{user.AllAuthenticated}},
+				Verb:            "get",
+				Namespace:       "default",
+				Resource:        "configmaps",
+				ResourceRequest: true,
+			},
+			want: "ci",
+		},
+		"other service account": {
+			attrs: authorizer.AttributesRecord{
+				User:            &user.DefaultInfo{Name: "system:serviceaccount:default:builder", Groups: []string{user.AllAuthenticated}},
+				Verb:            "create",
+				Namespace:       "default",
+				Resource:        "configmaps",
+				ResourceRequest: true,
+			},
+			want: "catch-all",
+		},
+		"unauthenticated": {
+			attrs: authorizer.AttributesRecord{
+				User:            &user.DefaultInfo{Name: user.Anonymous, Groups: []string{user.AllUnauthenticated}},
+				Verb:            "list",
+				Resource:        "namespaces",
+				ResourceRequest: true,
+			},
+		},
+		"non-resource request": {
+			attrs: authorizer.AttributesRecord{
+				User: &user.DefaultInfo{Name: "system:serviceaccount:ci:builder", Groups: []string{user.AllAuthenticated}},
+				Verb: "get",
+				Path: "/version",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := matchingFlowSchema(flowSchemas, tt.attrs)
+			if tt.want == "" {
+				require.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			require.Equal(t, tt.want, got.Name)
+		})
+	}
+}
+
+func TestWorkspacePriorityAndFairness(t *testing.T) {
+	noisy := logicalcluster.New("root:org:noisy")
+	quiet := logicalcluster.New("root:org:quiet")
+
+	listFlowSchemas := func(clusterName logicalcluster.Name) ([]*flowcontrolv1beta2.FlowSchema, error) {
+		if clusterName != noisy {
+			return nil, nil
+		}
+		return []*flowcontrolv1beta2.FlowSchema{{
+			ObjectMeta: metav1.ObjectMeta{Name: "everyone"},
+			Spec: flowcontrolv1beta2.FlowSchemaSpec{
+				PriorityLevelConfiguration: flowcontrolv1beta2.PriorityLevelConfigurationReference{Name: "tenant"},
+				Rules: []flowcontrolv1beta2.PolicyRulesWithSubjects{{
+					Subjects: []flowcontrolv1beta2.Subject{{Kind: flowcontrolv1beta2.SubjectKindUser, User: &flowcontrolv1beta2.UserSubject{Name: flowcontrolv1beta2.NameAll}}},
+					ResourceRules: []flowcontrolv1beta2.ResourcePolicyRule{{
+						Verbs:        []string{flowcontrolv1beta2.VerbAll},
+						APIGroups:    []string{flowcontrolv1beta2.APIGroupAll},
+						Resources:    []string{flowcontrolv1beta2.ResourceAll},
+						ClusterScope: true,
+					}},
+				}},
+			},
+		}}, nil
+	}
+	getPriorityLevel := func(clusterName logicalcluster.Name, name string) (*flowcontrolv1beta2.PriorityLevelConfiguration, error) {
+		if clusterName != noisy || name != "tenant" {
+			return nil, apierrors.NewNotFound(flowcontrolv1beta2.Resource("prioritylevelconfigurations"), name)
+		}
+		return &flowcontrolv1beta2.PriorityLevelConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: flowcontrolv1beta2.PriorityLevelConfigurationSpec{
+				Type: flowcontrolv1beta2.PriorityLevelEnablementLimited,
+				Limited: &flowcontrolv1beta2.LimitedPriorityConfiguration{
+					AssuredConcurrencyShares: 1,
+					LimitResponse:            flowcontrolv1beta2.LimitResponse{Type: flowcontrolv1beta2.LimitResponseTypeReject},
+				},
+			},
+		}, nil
+	}
+
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	handler := WithWorkspacePriorityAndFairness(
+		http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Query().Get("block") == "true" {
+				close(entered)
+				<-unblock
+			}
+		}),
+		func(*http.Request, *request.RequestInfo) bool { return false },
+		listFlowSchemas,
+		getPriorityLevel,
+	)
+
+	serve := func(clusterName logicalcluster.Name, block bool) int {
+		path := "/api/v1/namespaces"
+		if block {
+			path += "?block=true"
+		}
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		ctx := request.WithCluster(req.Context(), request.Cluster{Name: clusterName})
+		ctx = request.WithUser(ctx, &user.DefaultInfo{Name: "alice"})
+		ctx = request.WithRequestInfo(ctx, &request.RequestInfo{IsResourceRequest: true, Verb: "list", APIVersion: "v1", Resource: "namespaces"})
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req.WithContext(ctx))
+		return rec.Code
+	}
+
+	done := make(chan int)
+	go func() { done <- serve(noisy, true) }()
+	<-entered
+
+	require.Equal(t, http.StatusTooManyRequests, serve(noisy, false), "the only seat of the noisy workspace is taken")
+	require.Equal(t, http.StatusOK, serve(quiet, false), "the quiet workspace is not limited")
+
+	close(unblock)
+	require.Equal(t, http.StatusOK, <-done)
+	require.Equal(t, http.StatusOK, serve(noisy, false), "the seat of the noisy workspace is released")
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storagegc
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+)
+
+// scanPageSize is the number of keys read from etcd per request.
+const scanPageSize = 500
+
+// identityHashRegexp matches identity hashes of APIExports, i.e. hex encoded sha256 sums.
+var identityHashRegexp = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Usage is the storage occupied by the objects of one identity of one group resource.
+type Usage struct {
+	Identity      string
+	GroupResource schema.GroupResource
+	Keys          int
+	Bytes         int64
+}
+
+// prefix returns the etcd prefix of the objects of u below the given etcd prefix.
+func (u Usage) prefix(etcdPrefix string) string {
+	return path.Join("/", etcdPrefix, u.GroupResource.Group, u.GroupResource.Resource, u.Identity) + "/"
+}
+
+// Run garbage collects the etcd storage of APIExport identities that are neither referenced by an APIExport
+// nor by an APIBinding of the shard anymore, and writes a report of the reclaimed space to out. With
+// DryRun, nothing is deleted.
+func Run(ctx context.Context, o *Options, out io.Writer) error {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.Kubeconfig
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: o.Context}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclientset.NewForConfig(rest.AddUserAgent(rest.CopyConfig(cfg), "kcp-storage-gc"))
+	if err != nil {
+		return err
+	}
+
+	var tlsConfig *tls.Config
+	if o.EtcdCertFile != "" || o.EtcdCAFile != "" {
+		tlsInfo := transport.TLSInfo{
+			CertFile:      o.EtcdCertFile,
+			KeyFile:       o.EtcdKeyFile,
+			TrustedCAFile: o.EtcdCAFile,
+		}
+		tlsConfig, err = tlsInfo.ClientConfig()
+		if err != nil {
+			return err
+		}
+	}
+	etcdClient, err := clientv3.New(clientv3.Config{
+		Endpoints:   o.EtcdServers,
+		DialTimeout: o.DialTimeout,
+		TLS:         tlsConfig,
+		Context:     ctx,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to etcd: %w", err)
+	}
+	defer etcdClient.Close()
+
+	return run(ctx, o, etcdClient, kcpClusterClient, out)
+}
+
+func run(ctx context.Context, o *Options, etcdClient *clientv3.Client, kcpClusterClient kcpclientset.ClusterInterface, out io.Writer) error {
+	logger := klog.FromContext(ctx)
+
+	// Scan before listing the live identities: objects of an identity are only written after the APIExport
+	// and the APIBinding referencing it exist, so an identity found in etcd is either seen as live below, or
+	// its APIExport and APIBindings are gone.
+	usages, err := scan(ctx, etcdClient.KV, o.EtcdPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to scan etcd: %w", err)
+	}
+	logger.V(2).Info("scanned etcd", "identities", len(usages))
+
+	exports, err := kcpClusterClient.ApisV1alpha1().APIExports().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list APIExports: %w", err)
+	}
+	bindings, err := kcpClusterClient.ApisV1alpha1().APIBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list APIBindings: %w", err)
+	}
+
+	orphans := Orphaned(usages, LiveIdentities(exports.Items, bindings.Items))
+	if !o.DryRun {
+		for _, u := range orphans {
+			prefix := u.prefix(o.EtcdPrefix)
+			logger.Info("deleting orphaned identity storage", "prefix", prefix, "keys", u.Keys, "bytes", u.Bytes)
+			if _, err := etcdClient.Delete(ctx, prefix, clientv3.WithPrefix()); err != nil {
+				return fmt.Errorf("failed to delete %s: %w", prefix, err)
+			}
+		}
+	}
+
+	return report(out, orphans, o.DryRun)
+}
+
+// scan returns the storage usage of all identities under the given etcd prefix. The objects of identity i
+// of a group resource are stored under <prefix>/<group>/<resource>/<i>/..., all other keys are skipped.
+func scan(ctx context.Context, kv clientv3.KV, etcdPrefix string) ([]Usage, error) {
+	prefix := strings.TrimSuffix(etcdPrefix, "/") + "/"
+	rangeEnd := clientv3.GetPrefixRangeEnd(prefix)
+
+	usages := map[Usage]*Usage{}
+	key := prefix
+	for {
+		resp, err := kv.Get(ctx, key, clientv3.WithRange(rangeEnd), clientv3.WithLimit(scanPageSize), clientv3.WithSerializable())
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Kvs {
+			identity, gr, ok := ParseKey(etcdPrefix, string(item.Key))
+			if !ok {
+				continue
+			}
+			k := Usage{Identity: identity, GroupResource: gr}
+			u, found := usages[k]
+			if !found {
+				u = &Usage{Identity: identity, GroupResource: gr}
+				usages[k] = u
+			}
+			u.Keys++
+			u.Bytes += int64(len(item.Key) + len(item.Value))
+		}
+		if !resp.More || len(resp.Kvs) == 0 {
+			break
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+
+	ret := make([]Usage, 0, len(usages))
+	for _, u := range usages {
+		ret = append(ret, *u)
+	}
+	return ret, nil
+}
+
+// ParseKey returns the identity and the group resource of an etcd key of a bound resource object, i.e.
+// <prefix>/<group>/<resource>/<identity>/..., or false if the key does not belong to an identity.
+func ParseKey(etcdPrefix, key string) (string, schema.GroupResource, bool) {
+	prefix := strings.TrimSuffix(etcdPrefix, "/") + "/"
+	if !strings.HasPrefix(key, prefix) {
+		return "", schema.GroupResource{}, false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(key, prefix), "/", 4)
+	if len(parts) < 4 || !identityHashRegexp.MatchString(parts[2]) {
+		return "", schema.GroupResource{}, false
+	}
+	return parts[2], schema.GroupResource{Group: parts[0], Resource: parts[1]}, true
+}
+
+// LiveIdentities returns the identities referenced by the given APIExports and APIBindings, including
+// those objects are stored under after an identity rotation.
+func LiveIdentities(exports []apisv1alpha1.APIExport, bindings []apisv1alpha1.APIBinding) sets.String {
+	live := sets.NewString()
+	for _, export := range exports {
+		live.Insert(export.Status.IdentityHash, export.Status.StorageIdentityHash, export.Annotations[apisv1alpha1.AnnotationRotateIdentityKey])
+		for _, claim := range export.Spec.PermissionClaims {
+			live.Insert(claim.IdentityHash)
+		}
+	}
+	for _, binding := range bindings {
+		for _, r := range binding.Status.BoundResources {
+			live.Insert(r.Schema.IdentityHash, r.Schema.StorageIdentity())
+		}
+		for _, claim := range binding.Spec.PermissionClaims {
+			live.Insert(claim.IdentityHash)
+		}
+	}
+	live.Delete("")
+	return live
+}
+
+// Orphaned returns the usages of identities not in live, sorted by identity and group resource.
+func Orphaned(usages []Usage, live sets.String) []Usage {
+	var orphans []Usage
+	for _, u := range usages {
+		if !live.Has(u.Identity) {
+			orphans = append(orphans, u)
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].Identity != orphans[j].Identity {
+			return orphans[i].Identity < orphans[j].Identity
+		}
+		return orphans[i].GroupResource.String() < orphans[j].GroupResource.String()
+	})
+	return orphans
+}
+
+func report(out io.Writer, orphans []Usage, dryRun bool) error {
+	if len(orphans) == 0 {
+		_, err := fmt.Fprintln(out, "No orphaned identities found.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "IDENTITY\tRESOURCE\tKEYS\tBYTES")
+	var keys int
+	var bytes int64
+	for _, u := range orphans {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", u.Identity, u.GroupResource, u.Keys, u.Bytes)
+		keys += u.Keys
+		bytes += u.Bytes
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	verb := "Reclaimed"
+	if dryRun {
+		verb = "Would reclaim"
+	}
+	_, err := fmt.Fprintf(out, "\n%s %d bytes in %d keys. The space is returned to the filesystem after the next etcd compaction and defragmentation.\n", verb, bytes, keys)
+	return err
+}
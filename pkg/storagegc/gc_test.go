@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storagegc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+var (
+	identityA = strings.Repeat("a", 64)
+	identityB = strings.Repeat("b", 64)
+	identityC = strings.Repeat("c", 64)
+	identityD = strings.Repeat("d", 64)
+)
+
+func TestParseKey(t *testing.T) {
+	tests := map[string]struct {
+		key          string
+		wantIdentity string
+		wantGR       schema.GroupResource
+		wantOK       bool
+	}{
+		"bound resource object": {
+			key:          "/registry/example.io/widgets/" + identityA + "/root:org/default/foo",
+			wantIdentity: identityA,
+			wantGR:       schema.GroupResource{Group: "example.io", Resource: "widgets"},
+			wantOK:       true,
+		},
+		"cluster-scoped bound resource object": {
+			key:          "/registry/example.io/gadgets/" + identityA + "/root:org/foo",
+			wantIdentity: identityA,
+			wantGR:       schema.GroupResource{Group: "example.io", Resource: "gadgets"},
+			wantOK:       true,
+		},
+		"built-in resource": {
+			key: "/registry/apps/deployments/root:org/default/foo",
+		},
+		"other prefix": {
+			key: "/other/example.io/widgets/" + identityA + "/root:org/default/foo",
+		},
+		"no object below the identity": {
+			key: "/registry/example.io/widgets/" + identityA,
+		},
+		"not a hash": {
+			key: "/registry/example.io/widgets/" + strings.Repeat("z", 64) + "/root:org/default/foo",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			identity, gr, ok := ParseKey("/registry/", tt.key)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.wantIdentity, identity)
+			require.Equal(t, tt.wantGR, gr)
+		})
+	}
+}
+
+func TestOrphaned(t *testing.T) {
+	exports := []apisv1alpha1.APIExport{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "rotated"},
+			Status:     apisv1alpha1.APIExportStatus{IdentityHash: identityA, StorageIdentityHash: identityB},
+		},
+	}
+	bindings := []apisv1alpha1.APIBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foreign-shard"},
+			Status: apisv1alpha1.APIBindingStatus{
+				BoundResources: []apisv1alpha1.BoundAPIResource{
+					{Group: "example.io", Resource: "gadgets", Schema: apisv1alpha1.BoundAPIResourceSchema{IdentityHash: identityC}},
+				},
+			},
+		},
+	}
+	widgets := schema.GroupResource{Group: "example.io", Resource: "widgets"}
+	gadgets := schema.GroupResource{Group: "example.io", Resource: "gadgets"}
+	usages := []Usage{
+		{Identity: identityD, GroupResource: widgets, Keys: 2, Bytes: 20},
+		{Identity: identityB, GroupResource: widgets, Keys: 1, Bytes: 10},
+		{Identity: identityC, GroupResource: gadgets, Keys: 1, Bytes: 10},
+		{Identity: identityD, GroupResource: gadgets, Keys: 3, Bytes: 30},
+	}
+
+	require.Equal(t, []Usage{
+		{Identity: identityD, GroupResource: gadgets, Keys: 3, Bytes: 30},
+		{Identity: identityD, GroupResource: widgets, Keys: 2, Bytes: 20},
+	}, Orphaned(usages, LiveIdentities(exports, bindings)))
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storagegc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Options are the options of a storage garbage collection run.
+type Options struct {
+	// Kubeconfig is the kubeconfig of the kcp shard the etcd belongs to. It must point to the base URL of
+	// the shard, without a /clusters/<name> suffix, and hold credentials allowed to list APIExports and
+	// APIBindings in all workspaces.
+	Kubeconfig string
+	// Context is the kubeconfig context to use. Defaults to the current context.
+	Context string
+
+	// EtcdServers are the etcd endpoints of the shard.
+	EtcdServers []string
+	// EtcdPrefix is the prefix of all resource paths in etcd, i.e. the --etcd-prefix of the shard.
+	EtcdPrefix string
+	// EtcdCertFile is the client certificate to authenticate against etcd.
+	EtcdCertFile string
+	// EtcdKeyFile is the key of EtcdCertFile.
+	EtcdKeyFile string
+	// EtcdCAFile is the CA bundle to verify the etcd serving certificates.
+	EtcdCAFile string
+	// DialTimeout is the timeout to connect to etcd.
+	DialTimeout time.Duration
+
+	// DryRun only reports the orphaned identities, without deleting anything.
+	DryRun bool
+}
+
+// NewOptions returns the default options.
+func NewOptions() *Options {
+	return &Options{
+		EtcdPrefix:  "/registry",
+		DialTimeout: 20 * time.Second,
+		DryRun:      true,
+	}
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Kubeconfig of the kcp shard. Defaults to the in-cluster config or $KUBECONFIG.")
+	fs.StringVar(&o.Context, "context", o.Context, "Kubeconfig context to use.")
+	fs.StringSliceVar(&o.EtcdServers, "etcd-servers", o.EtcdServers, "List of etcd servers of the kcp shard (scheme://ip:port), comma separated.")
+	fs.StringVar(&o.EtcdPrefix, "etcd-prefix", o.EtcdPrefix, "The prefix of all resource paths in etcd, as passed to the kcp shard.")
+	fs.StringVar(&o.EtcdCertFile, "etcd-certfile", o.EtcdCertFile, "SSL certification file used to secure etcd communication.")
+	fs.StringVar(&o.EtcdKeyFile, "etcd-keyfile", o.EtcdKeyFile, "SSL key file used to secure etcd communication.")
+	fs.StringVar(&o.EtcdCAFile, "etcd-cafile", o.EtcdCAFile, "SSL Certificate Authority file used to secure etcd communication.")
+	fs.DurationVar(&o.DialTimeout, "etcd-dial-timeout", o.DialTimeout, "Timeout to connect to etcd.")
+	fs.BoolVar(&o.DryRun, "dry-run", o.DryRun, "Only report orphaned identities and the space they occupy, without deleting them.")
+}
+
+func (o *Options) Validate() []error {
+	var errs []error
+
+	if len(o.EtcdServers) == 0 {
+		errs = append(errs, fmt.Errorf("--etcd-servers must be set"))
+	}
+	if !strings.HasPrefix(o.EtcdPrefix, "/") {
+		errs = append(errs, fmt.Errorf("--etcd-prefix must start with /"))
+	}
+	if (o.EtcdCertFile == "") != (o.EtcdKeyFile == "") {
+		errs = append(errs, fmt.Errorf("--etcd-certfile and --etcd-keyfile must be set together"))
+	}
+	if o.DialTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("--etcd-dial-timeout must be >0"))
+	}
+
+	return errs
+}
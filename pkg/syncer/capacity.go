@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncer
+
+import (
+	"encoding/json"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// downstreamCapacity sums up the capacity and allocatable resources of the ready and schedulable nodes
+// of the downstream cluster. Nothing is returned if there is no such node.
+func downstreamCapacity(nodes []corev1.Node) (capacity, allocatable corev1.ResourceList) {
+	for i := range nodes {
+		node := &nodes[i]
+		if node.Spec.Unschedulable || !isNodeReady(node) {
+			continue
+		}
+		if capacity == nil {
+			capacity, allocatable = corev1.ResourceList{}, corev1.ResourceList{}
+		}
+		addResourceList(capacity, node.Status.Capacity)
+		addResourceList(allocatable, node.Status.Allocatable)
+	}
+	return capacity, allocatable
+}
+
+func isNodeReady(node *corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func addResourceList(sum, add corev1.ResourceList) {
+	for name, quantity := range add {
+		if existing, found := sum[name]; found {
+			existing.Add(quantity)
+			sum[name] = existing
+		} else {
+			sum[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+type jsonPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// heartbeatPatch returns the JSON patch for the status of the SyncTarget with the given UID, setting the
+// heartbeat time and, if known, the capacity and allocatable resources of the downstream cluster.
+func heartbeatPatch(uid string, now time.Time, capacity, allocatable corev1.ResourceList) ([]byte, error) {
+	ops := []jsonPatchOp{
+		{Op: "test", Path: "/metadata/uid", Value: uid},
+		{Op: "replace", Path: "/status/lastSyncerHeartbeatTime", Value: now.Format(time.RFC3339)},
+	}
+	if capacity != nil {
+		ops = append(ops,
+			jsonPatchOp{Op: "add", Path: "/status/capacity", Value: capacity},
+			jsonPatchOp{Op: "add", Path: "/status/allocatable", Value: allocatable},
+		)
+	}
+	return json.Marshal(ops)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func newNode(cpu string, ready, unschedulable bool) corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Node{
+		Spec: corev1.NodeSpec{Unschedulable: unschedulable},
+		Status: corev1.NodeStatus{
+			Capacity:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func TestDownstreamCapacity(t *testing.T) {
+	capacity, allocatable := downstreamCapacity([]corev1.Node{
+		newNode("2", true, false),
+		newNode("1500m", true, false),
+		newNode("8", false, false),
+		newNode("8", true, true),
+	})
+	require.Equal(t, int64(3500), capacity.Cpu().MilliValue())
+	require.Equal(t, int64(3500), allocatable.Cpu().MilliValue())
+
+	capacity, allocatable = downstreamCapacity([]corev1.Node{newNode("8", false, false)})
+	require.Nil(t, capacity)
+	require.Nil(t, allocatable)
+}
+
+func TestHeartbeatPatch(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	bs, err := heartbeatPatch("uid", now, nil, nil)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"op":"test","path":"/metadata/uid","value":"uid"},{"op":"replace","path":"/status/lastSyncerHeartbeatTime","value":"2022-10-01T12:00:00Z"}]`, string(bs))
+
+	capacity := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}
+	bs, err = heartbeatPatch("uid", now, capacity, capacity)
+	require.NoError(t, err)
+	var ops []jsonPatchOp
+	require.NoError(t, json.Unmarshal(bs, &ops))
+	require.Len(t, ops, 4)
+	require.Equal(t, "/status/capacity", ops[2].Path)
+	require.Equal(t, "/status/allocatable", ops[3].Path)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package globaldns
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/logging"
+)
+
+const (
+	controllerName = "kcp-workload-syncer-global-dns"
+
+	resyncPeriod = 10 * time.Hour
+)
+
+// Controller publishes the load balancer addresses of the Services synced to the physical cluster
+// as external-dns DNSEndpoints under a name that is independent of the sync target. When the
+// Service moves to another sync target, the record is garbage collected with the downstream
+// Service and published by the syncer of the new sync target.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	zone string
+
+	getService             func(namespace, name string) (runtime.Object, error)
+	getDownstreamNamespace func(name string) (runtime.Object, error)
+	getDNSEndpoint         func(namespace, name string) (runtime.Object, error)
+	createDNSEndpoint      func(ctx context.Context, obj *unstructured.Unstructured) error
+	updateDNSEndpoint      func(ctx context.Context, obj *unstructured.Unstructured) error
+	deleteDNSEndpoint      func(ctx context.Context, namespace, name string) error
+	dnsEndpointInformers   dynamicinformer.DynamicSharedInformerFactory
+}
+
+// NewController returns a global DNS controller publishing records in the given zone. The
+// downstream informers must not have been started yet.
+func NewController(
+	syncerLogger logr.Logger,
+	zone string,
+	downstreamClient dynamic.Interface,
+	downstreamInformers dynamicinformer.DynamicSharedInformerFactory,
+) *Controller {
+	logger := logging.WithReconciler(syncerLogger, controllerName)
+
+	// DNSEndpoints are not labeled for the sync target, hence need their own informers.
+	dnsEndpointInformers := dynamicinformer.NewFilteredDynamicSharedInformerFactory(downstreamClient, resyncPeriod, metav1.NamespaceAll, func(o *metav1.ListOptions) {
+		o.LabelSelector = ServiceLabel
+	})
+	serviceLister := downstreamInformers.ForResource(ServiceGVR).Lister()
+	namespaceLister := downstreamInformers.ForResource(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}).Lister()
+	dnsEndpointLister := dnsEndpointInformers.ForResource(DNSEndpointGVR).Lister()
+
+	c := &Controller{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName),
+
+		zone: zone,
+
+		getService: func(namespace, name string) (runtime.Object, error) {
+			return serviceLister.ByNamespace(namespace).Get(name)
+		},
+		getDownstreamNamespace: func(name string) (runtime.Object, error) {
+			return namespaceLister.Get(name)
+		},
+		getDNSEndpoint: func(namespace, name string) (runtime.Object, error) {
+			return dnsEndpointLister.ByNamespace(namespace).Get(name)
+		},
+		createDNSEndpoint: func(ctx context.Context, obj *unstructured.Unstructured) error {
+			_, err := downstreamClient.Resource(DNSEndpointGVR).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+			return err
+		},
+		updateDNSEndpoint: func(ctx context.Context, obj *unstructured.Unstructured) error {
+			_, err := downstreamClient.Resource(DNSEndpointGVR).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
+			return err
+		},
+		deleteDNSEndpoint: func(ctx context.Context, namespace, name string) error {
+			return downstreamClient.Resource(DNSEndpointGVR).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		},
+		dnsEndpointInformers: dnsEndpointInformers,
+	}
+
+	logger.V(2).Info("Set up downstream service informer", "zone", zone)
+	downstreamInformers.ForResource(ServiceGVR).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueue(obj, logger) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueue(obj, logger) },
+	})
+	dnsEndpointInformers.ForResource(DNSEndpointGVR).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, obj interface{}) { c.enqueue(obj, logger) },
+		DeleteFunc: func(obj interface{}) { c.enqueue(obj, logger) },
+	})
+
+	return c
+}
+
+// enqueue enqueues the Service of the given Service or DNSEndpoint. Both have the same key.
+func (c *Controller) enqueue(obj interface{}, logger logr.Logger) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	logging.WithQueueKey(logger, key).V(2).Info("queueing Service")
+	c.queue.Add(key)
+}
+
+// Start starts N worker processes processing work items.
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), controllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	c.dnsEndpointInformers.Start(ctx.Done())
+	c.dnsEndpointInformers.WaitForCacheSync(ctx.Done())
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+// startWorker processes work items until stopCh is closed.
+func (c *Controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(1).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		utilruntime.HandleError(fmt.Errorf("%s failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+
+	return true
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package globaldns
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
+)
+
+func (c *Controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	desired, err := c.desiredDNSEndpoint(namespace, name)
+	if err != nil {
+		return err
+	}
+
+	obj, err := c.getDNSEndpoint(namespace, name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if apierrors.IsNotFound(err) {
+		if desired == nil {
+			return nil
+		}
+		logger.V(2).Info("publishing global DNS record", "hostname", desiredHostname(desired))
+		if err := c.createDNSEndpoint(ctx, desired); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+	}
+
+	existing, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	if desired == nil {
+		// e.g. the service lost its load balancer
+		logger.V(2).Info("removing global DNS record")
+		if err := c.deleteDNSEndpoint(ctx, namespace, name); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+	if equality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) {
+		return nil
+	}
+	updated := existing.DeepCopy()
+	updated.Object["spec"] = desired.Object["spec"]
+	logger.V(2).Info("updating global DNS record", "hostname", desiredHostname(desired))
+	return c.updateDNSEndpoint(ctx, updated)
+}
+
+// desiredDNSEndpoint returns the DNSEndpoint for the given downstream service, or nil if
+// there should be none.
+func (c *Controller) desiredDNSEndpoint(namespace, name string) (*unstructured.Unstructured, error) {
+	obj, err := c.getService(namespace, name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	unstr, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type %T", obj)
+	}
+	if unstr.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+	service := &corev1.Service{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstr.Object, service); err != nil {
+		return nil, err
+	}
+
+	nsObj, err := c.getDownstreamNamespace(namespace)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	nsMeta, err := meta.Accessor(nsObj)
+	if err != nil {
+		return nil, err
+	}
+	locator, found, err := shared.LocatorFromAnnotations(nsMeta.GetAnnotations())
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	return dnsEndpoint(service, ServiceHostname(c.zone, locator.Workspace, locator.Namespace, service.Name)), nil
+}
+
+func desiredHostname(obj *unstructured.Unstructured) string {
+	endpoints, _, _ := unstructured.NestedSlice(obj.Object, "spec", "endpoints")
+	if len(endpoints) == 0 {
+		return ""
+	}
+	hostname, _, _ := unstructured.NestedString(endpoints[0].(map[string]interface{}), "dnsName")
+	return hostname
+}
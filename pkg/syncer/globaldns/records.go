@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package globaldns
+
+import (
+	"crypto/sha256"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/martinlindhe/base36"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ServiceLabel is set on published DNSEndpoints. Its value is the name of the Service in the
+// same namespace the record is published for.
+const ServiceLabel = "experimental.workload.kcp.dev/global-dns"
+
+// RecordTTL is the TTL in seconds of published records. It is short to make placement changes
+// visible to clients quickly.
+const RecordTTL = 60
+
+var (
+	ServiceGVR     = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+	DNSEndpointGVR = schema.GroupVersionResource{Group: "externaldns.k8s.io", Version: "v1alpha1", Resource: "dnsendpoints"}
+)
+
+// WorkspaceID returns a stable DNS label for the workspace. It does not depend on the sync
+// target, hence stays the same when the workloads of the workspace move between sync targets.
+func WorkspaceID(workspace logicalcluster.Name) string {
+	hash := sha256.Sum224([]byte(workspace.String()))
+	return strings.ToLower(base36.EncodeBytes(hash[:]))[:12]
+}
+
+// ServiceHostname returns the global name of the service in the namespace of the workspace.
+func ServiceHostname(zone string, workspace logicalcluster.Name, namespace, service string) string {
+	return strings.Join([]string{service, namespace, WorkspaceID(workspace), strings.TrimSuffix(zone, ".")}, ".")
+}
+
+// dnsEndpoint returns the external-dns DNSEndpoint publishing the load balancer addresses of the
+// given downstream service under the given hostname, or nil if the service has no address (yet).
+func dnsEndpoint(service *corev1.Service, hostname string) *unstructured.Unstructured {
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return nil
+	}
+
+	ips, hosts := sets.NewString(), sets.NewString()
+	for _, ingress := range service.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			ips.Insert(ingress.IP)
+		}
+		if ingress.Hostname != "" {
+			hosts.Insert(ingress.Hostname)
+		}
+	}
+
+	var endpoint map[string]interface{}
+	switch {
+	case ips.Len() > 0:
+		endpoint = map[string]interface{}{
+			"dnsName":    hostname,
+			"recordType": "A",
+			"recordTTL":  int64(RecordTTL),
+			"targets":    toInterfaces(ips.List()),
+		}
+	case hosts.Len() > 0:
+		// a CNAME cannot have multiple targets
+		endpoint = map[string]interface{}{
+			"dnsName":    hostname,
+			"recordType": "CNAME",
+			"recordTTL":  int64(RecordTTL),
+			"targets":    toInterfaces(hosts.List()[:1]),
+		}
+	default:
+		return nil
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"endpoints": []interface{}{endpoint},
+		},
+	}}
+	obj.SetAPIVersion(DNSEndpointGVR.GroupVersion().String())
+	obj.SetKind("DNSEndpoint")
+	obj.SetNamespace(service.Namespace)
+	obj.SetName(service.Name)
+	obj.SetLabels(map[string]string{ServiceLabel: service.Name})
+	obj.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(service, corev1.SchemeGroupVersion.WithKind("Service")),
+	})
+	return obj
+}
+
+func toInterfaces(ss []string) []interface{} {
+	ret := make([]interface{}, 0, len(ss))
+	for _, s := range ss {
+		ret = append(ret, s)
+	}
+	return ret
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package globaldns
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceHostname(t *testing.T) {
+	workspace := logicalcluster.New("root:org:ws")
+	hostname := ServiceHostname("global.example.com.", workspace, "default", "web")
+	require.Equal(t, "web.default."+WorkspaceID(workspace)+".global.example.com", hostname)
+	require.Len(t, WorkspaceID(workspace), 12)
+	require.NotEqual(t, WorkspaceID(workspace), WorkspaceID(logicalcluster.New("root:org:other")))
+}
+
+func TestDNSEndpoint(t *testing.T) {
+	service := func(serviceType corev1.ServiceType, ingress ...corev1.LoadBalancerIngress) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "kcp-01c0zzvlqsi7n", UID: "uid"},
+			Spec:       corev1.ServiceSpec{Type: serviceType},
+			Status:     corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: ingress}},
+		}
+	}
+
+	tests := map[string]struct {
+		service  *corev1.Service
+		wantSpec interface{}
+	}{
+		"cluster ip service": {
+			service: service(corev1.ServiceTypeClusterIP),
+		},
+		"pending load balancer": {
+			service: service(corev1.ServiceTypeLoadBalancer),
+		},
+		"load balancer ips": {
+			service: service(corev1.ServiceTypeLoadBalancer, corev1.LoadBalancerIngress{IP: "10.0.0.2"}, corev1.LoadBalancerIngress{IP: "10.0.0.1", Hostname: "lb.example.com"}),
+			wantSpec: map[string]interface{}{"endpoints": []interface{}{map[string]interface{}{
+				"dnsName":    "web.default.ws.global.example.com",
+				"recordType": "A",
+				"recordTTL":  int64(RecordTTL),
+				"targets":    []interface{}{"10.0.0.1", "10.0.0.2"},
+			}}},
+		},
+		"load balancer hostnames": {
+			service: service(corev1.ServiceTypeLoadBalancer, corev1.LoadBalancerIngress{Hostname: "b.elb.example.com"}, corev1.LoadBalancerIngress{Hostname: "a.elb.example.com"}),
+			wantSpec: map[string]interface{}{"endpoints": []interface{}{map[string]interface{}{
+				"dnsName":    "web.default.ws.global.example.com",
+				"recordType": "CNAME",
+				"recordTTL":  int64(RecordTTL),
+				"targets":    []interface{}{"a.elb.example.com"},
+			}}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := dnsEndpoint(tt.service, "web.default.ws.global.example.com")
+			if tt.wantSpec == nil {
+				require.Nil(t, obj)
+				return
+			}
+			require.NotNil(t, obj)
+			require.Equal(t, "DNSEndpoint", obj.GetKind())
+			require.Equal(t, "kcp-01c0zzvlqsi7n", obj.GetNamespace())
+			require.Equal(t, "web", obj.GetName())
+			require.Equal(t, map[string]string{ServiceLabel: "web"}, obj.GetLabels())
+			require.Len(t, obj.GetOwnerReferences(), 1)
+			require.Equal(t, "Service", obj.GetOwnerReferences()[0].Kind)
+			require.Equal(t, tt.wantSpec, obj.Object["spec"])
+		})
+	}
+}
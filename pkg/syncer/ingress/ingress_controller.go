@@ -0,0 +1,218 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/logging"
+)
+
+const (
+	controllerName = "kcp-workload-syncer-ingress"
+
+	resyncPeriod = 10 * time.Hour
+)
+
+// Controller materializes the Ingresses synced to the physical cluster as the exposure
+// resources of the cluster, i.e. OpenShift Routes or Gateway API HTTPRoutes, and publishes
+// the resulting hostnames in the Ingress status, from where the status syncer syncs them
+// back to kcp.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	mode    Mode
+	gateway GatewayReference
+
+	getIngress            func(namespace, name string) (runtime.Object, error)
+	listMaterialized      func(namespace, ingressName string) ([]runtime.Object, error)
+	createMaterialized    func(ctx context.Context, obj *unstructured.Unstructured) error
+	updateMaterialized    func(ctx context.Context, obj *unstructured.Unstructured) error
+	deleteMaterialized    func(ctx context.Context, namespace, name string) error
+	updateIngressStatus   func(ctx context.Context, ingress *unstructured.Unstructured) error
+	materializedInformers dynamicinformer.DynamicSharedInformerFactory
+}
+
+// NewController returns an ingress materialization controller. The downstream informers
+// must not have been started yet.
+func NewController(
+	syncerLogger logr.Logger,
+	mode Mode,
+	gateway GatewayReference,
+	downstreamClient dynamic.Interface,
+	downstreamInformers dynamicinformer.DynamicSharedInformerFactory,
+) (*Controller, error) {
+	if mode != ModeRoute && mode != ModeGateway {
+		return nil, fmt.Errorf("unsupported ingress materialization mode %q", mode)
+	}
+	if mode == ModeGateway && (gateway.Namespace == "" || gateway.Name == "") {
+		return nil, fmt.Errorf("ingress materialization mode %q requires a gateway", mode)
+	}
+
+	logger := logging.WithReconciler(syncerLogger, controllerName)
+	gvr := mode.GVR()
+
+	// materialized objects are not labeled for the sync target, hence need their own informers.
+	materializedInformers := dynamicinformer.NewFilteredDynamicSharedInformerFactory(downstreamClient, resyncPeriod, metav1.NamespaceAll, func(o *metav1.ListOptions) {
+		o.LabelSelector = IngressLabel
+	})
+	ingressLister := downstreamInformers.ForResource(IngressGVR).Lister()
+	materializedLister := materializedInformers.ForResource(gvr).Lister()
+
+	c := &Controller{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName),
+
+		mode:    mode,
+		gateway: gateway,
+
+		getIngress: func(namespace, name string) (runtime.Object, error) {
+			return ingressLister.ByNamespace(namespace).Get(name)
+		},
+		listMaterialized: func(namespace, ingressName string) ([]runtime.Object, error) {
+			return materializedLister.ByNamespace(namespace).List(labels.SelectorFromSet(labels.Set{IngressLabel: ingressName}))
+		},
+		createMaterialized: func(ctx context.Context, obj *unstructured.Unstructured) error {
+			_, err := downstreamClient.Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+			return err
+		},
+		updateMaterialized: func(ctx context.Context, obj *unstructured.Unstructured) error {
+			_, err := downstreamClient.Resource(gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
+			return err
+		},
+		deleteMaterialized: func(ctx context.Context, namespace, name string) error {
+			return downstreamClient.Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		},
+		updateIngressStatus: func(ctx context.Context, ingress *unstructured.Unstructured) error {
+			_, err := downstreamClient.Resource(IngressGVR).Namespace(ingress.GetNamespace()).UpdateStatus(ctx, ingress, metav1.UpdateOptions{})
+			return err
+		},
+		materializedInformers: materializedInformers,
+	}
+
+	logger.V(2).Info("Set up downstream ingress informer", "mode", mode)
+	downstreamInformers.ForResource(IngressGVR).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueue(obj, logger) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueue(obj, logger) },
+	})
+	materializedInformers.ForResource(gvr).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.enqueueOwner(obj, logger) },
+		UpdateFunc: func(_, obj interface{}) { c.enqueueOwner(obj, logger) },
+		DeleteFunc: func(obj interface{}) { c.enqueueOwner(obj, logger) },
+	})
+
+	return c, nil
+}
+
+func (c *Controller) enqueue(obj interface{}, logger logr.Logger) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	logging.WithQueueKey(logger, key).V(2).Info("queueing Ingress")
+	c.queue.Add(key)
+}
+
+// enqueueOwner enqueues the Ingress a materialized object belongs to.
+func (c *Controller) enqueueOwner(obj interface{}, logger logr.Logger) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	materialized, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("unexpected object type %T", obj))
+		return
+	}
+	ingressName := materialized.GetLabels()[IngressLabel]
+	if ingressName == "" {
+		return
+	}
+
+	key := materialized.GetNamespace() + "/" + ingressName
+	logging.WithQueueKey(logger, key).V(2).Info("queueing Ingress because of materialized object", "name", materialized.GetName())
+	c.queue.Add(key)
+}
+
+// Start starts N worker processes processing work items.
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), controllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	c.materializedInformers.Start(ctx.Done())
+	c.materializedInformers.WaitForCacheSync(ctx.Done())
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+// startWorker processes work items until stopCh is closed.
+func (c *Controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(1).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		utilruntime.HandleError(fmt.Errorf("%s failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+
+	return true
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+func (c *Controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	obj, err := c.getIngress(namespace, name)
+	if apierrors.IsNotFound(err) {
+		// materialized objects are garbage collected through their owner reference
+		return nil
+	} else if err != nil {
+		return err
+	}
+	unstr, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	if unstr.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	ingress := &networkingv1.Ingress{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstr.Object, ingress); err != nil {
+		return err
+	}
+
+	existingObjs, err := c.listMaterialized(namespace, name)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*unstructured.Unstructured, len(existingObjs))
+	for _, o := range existingObjs {
+		u, ok := o.(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected object type %T", o)
+		}
+		existing[u.GetName()] = u
+	}
+
+	var current []*unstructured.Unstructured
+	for _, desired := range materialize(c.mode, ingress, c.gateway) {
+		found, ok := existing[desired.GetName()]
+		delete(existing, desired.GetName())
+		if !ok {
+			logger.V(2).Info("creating materialized object", "name", desired.GetName())
+			if err := c.createMaterialized(ctx, desired); err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+			continue
+		}
+		current = append(current, found)
+		if equality.Semantic.DeepEqual(found.Object["spec"], desired.Object["spec"]) {
+			continue
+		}
+		updated := found.DeepCopy()
+		updated.Object["spec"] = desired.Object["spec"]
+		logger.V(2).Info("updating materialized object", "name", updated.GetName())
+		if err := c.updateMaterialized(ctx, updated); err != nil {
+			return err
+		}
+	}
+	for name := range existing {
+		logger.V(2).Info("deleting stale materialized object", "name", name)
+		if err := c.deleteMaterialized(ctx, namespace, name); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	status := loadBalancerStatus(hostnames(c.mode, current))
+	if equality.Semantic.DeepEqual(ingress.Status.LoadBalancer, status) {
+		return nil
+	}
+	statusObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&status)
+	if err != nil {
+		return err
+	}
+	updated := unstr.DeepCopy()
+	if err := unstructured.SetNestedField(updated.Object, statusObj, "status", "loadBalancer"); err != nil {
+		return err
+	}
+	logger.V(2).Info("publishing ingress hostnames", "hostnames", status.Ingress)
+	return c.updateIngressStatus(ctx, updated)
+}
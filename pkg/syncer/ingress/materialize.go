@@ -0,0 +1,296 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Mode is the way Ingresses are exposed on a physical cluster.
+type Mode string
+
+const (
+	// ModeIngress keeps the synced Ingress as is, for clusters with an ingress controller.
+	ModeIngress Mode = "Ingress"
+	// ModeRoute materializes an OpenShift Route for every Ingress path.
+	ModeRoute Mode = "Route"
+	// ModeGateway materializes a Gateway API HTTPRoute for every Ingress rule, attached to
+	// a configured Gateway.
+	ModeGateway Mode = "Gateway"
+)
+
+// IngressLabel is set on materialized objects. Its value is the name of the Ingress in
+// the same namespace the object is materialized from.
+const IngressLabel = "experimental.workload.kcp.dev/ingress"
+
+var (
+	IngressGVR   = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}
+	RouteGVR     = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+	HTTPRouteGVR = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "httproutes"}
+)
+
+// GVR returns the resource objects are materialized as in the given mode, or an
+// empty GVR if the Ingress is used as is.
+func (m Mode) GVR() schema.GroupVersionResource {
+	switch m {
+	case ModeRoute:
+		return RouteGVR
+	case ModeGateway:
+		return HTTPRouteGVR
+	default:
+		return schema.GroupVersionResource{}
+	}
+}
+
+// GatewayReference references the Gateway HTTPRoutes are attached to.
+type GatewayReference struct {
+	Namespace string
+	Name      string
+}
+
+// materialize returns the objects exposing the ingress in the given mode.
+func materialize(mode Mode, ingress *networkingv1.Ingress, gateway GatewayReference) []*unstructured.Unstructured {
+	var objs []*unstructured.Unstructured
+	switch mode {
+	case ModeRoute:
+		objs = routesForIngress(ingress)
+	case ModeGateway:
+		objs = httpRoutesForIngress(ingress, gateway)
+	}
+
+	for _, obj := range objs {
+		obj.SetNamespace(ingress.Namespace)
+		obj.SetLabels(map[string]string{IngressLabel: ingress.Name})
+		obj.SetOwnerReferences([]metav1.OwnerReference{
+			*metav1.NewControllerRef(ingress, networkingv1.SchemeGroupVersion.WithKind("Ingress")),
+		})
+	}
+	return objs
+}
+
+func routesForIngress(ingress *networkingv1.Ingress) []*unstructured.Unstructured {
+	tlsHosts := sets.NewString()
+	for _, tls := range ingress.Spec.TLS {
+		tlsHosts.Insert(tls.Hosts...)
+	}
+
+	var routes []*unstructured.Unstructured
+	if len(ingress.Spec.Rules) == 0 && ingress.Spec.DefaultBackend != nil {
+		if route := route(ingress.Name, "", "", ingress.Spec.DefaultBackend, false); route != nil {
+			routes = append(routes, route)
+		}
+	}
+	for i, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for j, path := range rule.HTTP.Paths {
+			name := fmt.Sprintf("%s-%d-%d", ingress.Name, i, j)
+			if route := route(name, rule.Host, path.Path, &path.Backend, tlsHosts.Has(rule.Host)); route != nil {
+				routes = append(routes, route)
+			}
+		}
+	}
+	return routes
+}
+
+func route(name, host, path string, backend *networkingv1.IngressBackend, tls bool) *unstructured.Unstructured {
+	if backend.Service == nil {
+		// resource backends cannot be expressed as routes
+		return nil
+	}
+
+	spec := map[string]interface{}{
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   backend.Service.Name,
+			"weight": int64(100),
+		},
+	}
+	if host != "" {
+		spec["host"] = host
+	}
+	if path != "" && path != "/" {
+		spec["path"] = path
+	}
+	if backend.Service.Port.Name != "" {
+		spec["port"] = map[string]interface{}{"targetPort": backend.Service.Port.Name}
+	} else if backend.Service.Port.Number != 0 {
+		spec["port"] = map[string]interface{}{"targetPort": int64(backend.Service.Port.Number)}
+	}
+	if tls {
+		spec["tls"] = map[string]interface{}{
+			"termination":                   "edge",
+			"insecureEdgeTerminationPolicy": "Redirect",
+		}
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetAPIVersion(RouteGVR.GroupVersion().String())
+	obj.SetKind("Route")
+	obj.SetName(name)
+	return obj
+}
+
+func httpRoutesForIngress(ingress *networkingv1.Ingress, gateway GatewayReference) []*unstructured.Unstructured {
+	parentRefs := []interface{}{
+		map[string]interface{}{
+			"namespace": gateway.Namespace,
+			"name":      gateway.Name,
+		},
+	}
+
+	var httpRoutes []*unstructured.Unstructured
+	if len(ingress.Spec.Rules) == 0 && ingress.Spec.DefaultBackend != nil {
+		if backendRef := backendRef(ingress.Spec.DefaultBackend); backendRef != nil {
+			httpRoutes = append(httpRoutes, httpRoute(ingress.Name, parentRefs, "", []interface{}{
+				map[string]interface{}{"backendRefs": []interface{}{backendRef}},
+			}))
+		}
+	}
+	for i, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		var rules []interface{}
+		for _, path := range rule.HTTP.Paths {
+			backendRef := backendRef(&path.Backend)
+			if backendRef == nil {
+				continue
+			}
+			rules = append(rules, map[string]interface{}{
+				"matches":     []interface{}{map[string]interface{}{"path": pathMatch(path)}},
+				"backendRefs": []interface{}{backendRef},
+			})
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		httpRoutes = append(httpRoutes, httpRoute(fmt.Sprintf("%s-%d", ingress.Name, i), parentRefs, rule.Host, rules))
+	}
+	return httpRoutes
+}
+
+func httpRoute(name string, parentRefs []interface{}, host string, rules []interface{}) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"parentRefs": parentRefs,
+		"rules":      rules,
+	}
+	if host != "" {
+		spec["hostnames"] = []interface{}{host}
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetAPIVersion(HTTPRouteGVR.GroupVersion().String())
+	obj.SetKind("HTTPRoute")
+	obj.SetName(name)
+	return obj
+}
+
+func backendRef(backend *networkingv1.IngressBackend) map[string]interface{} {
+	// HTTPRoute backends need a port number, named service ports cannot be expressed
+	if backend.Service == nil || backend.Service.Port.Number == 0 {
+		return nil
+	}
+	return map[string]interface{}{
+		"name": backend.Service.Name,
+		"port": int64(backend.Service.Port.Number),
+	}
+}
+
+func pathMatch(path networkingv1.HTTPIngressPath) map[string]interface{} {
+	value := path.Path
+	if value == "" {
+		value = "/"
+	}
+	matchType := "PathPrefix"
+	if path.PathType != nil && *path.PathType == networkingv1.PathTypeExact {
+		matchType = "Exact"
+	}
+	return map[string]interface{}{
+		"type":  matchType,
+		"value": value,
+	}
+}
+
+// hostnames returns the sorted hostnames the materialized objects are admitted for.
+func hostnames(mode Mode, objs []*unstructured.Unstructured) []string {
+	hosts := sets.NewString()
+	for _, obj := range objs {
+		switch mode {
+		case ModeRoute:
+			ingresses, _, _ := unstructured.NestedSlice(obj.Object, "status", "ingress")
+			for _, i := range ingresses {
+				routeIngress, ok := i.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				host, _, _ := unstructured.NestedString(routeIngress, "host")
+				if host != "" && hasTrueCondition(routeIngress, "Admitted") {
+					hosts.Insert(host)
+				}
+			}
+		case ModeGateway:
+			parents, _, _ := unstructured.NestedSlice(obj.Object, "status", "parents")
+			accepted := false
+			for _, p := range parents {
+				if parent, ok := p.(map[string]interface{}); ok && hasTrueCondition(parent, "Accepted") {
+					accepted = true
+					break
+				}
+			}
+			if !accepted {
+				continue
+			}
+			routeHosts, _, _ := unstructured.NestedStringSlice(obj.Object, "spec", "hostnames")
+			hosts.Insert(routeHosts...)
+		}
+	}
+	return hosts.List()
+}
+
+func hasTrueCondition(obj map[string]interface{}, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj, "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == conditionType && condition["status"] == string(metav1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadBalancerStatus returns the ingress load balancer status publishing the given hostnames.
+func loadBalancerStatus(hosts []string) corev1.LoadBalancerStatus {
+	sort.Strings(hosts)
+	var status corev1.LoadBalancerStatus
+	for _, host := range hosts {
+		status.Ingress = append(status.Ingress, corev1.LoadBalancerIngress{Hostname: host})
+	}
+	return status
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testIngress() *networkingv1.Ingress {
+	exact := networkingv1.PathTypeExact
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "kcp-01c0zzvlqsi7n", UID: "uid"},
+		Spec: networkingv1.IngressSpec{
+			TLS: []networkingv1.IngressTLS{{Hosts: []string{"secure.example.com"}}},
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: "secure.example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+						{Path: "/", Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 8080}}}},
+						{Path: "/api", PathType: &exact, Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "api", Port: networkingv1.ServiceBackendPort{Name: "http"}}}},
+					}}},
+				},
+				{
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+						{Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 8080}}}},
+					}}},
+				},
+			},
+		},
+	}
+}
+
+func TestMaterializeRoutes(t *testing.T) {
+	objs := materialize(ModeRoute, testIngress(), GatewayReference{})
+	require.Len(t, objs, 3)
+
+	for _, obj := range objs {
+		require.Equal(t, "route.openshift.io/v1", obj.GetAPIVersion())
+		require.Equal(t, "Route", obj.GetKind())
+		require.Equal(t, "kcp-01c0zzvlqsi7n", obj.GetNamespace())
+		require.Equal(t, map[string]string{IngressLabel: "web"}, obj.GetLabels())
+		require.Len(t, obj.GetOwnerReferences(), 1)
+		require.Equal(t, "Ingress", obj.GetOwnerReferences()[0].Kind)
+	}
+
+	require.Equal(t, "web-0-0", objs[0].GetName())
+	require.Equal(t, map[string]interface{}{
+		"host": "secure.example.com",
+		"to":   map[string]interface{}{"kind": "Service", "name": "web", "weight": int64(100)},
+		"port": map[string]interface{}{"targetPort": int64(8080)},
+		"tls":  map[string]interface{}{"termination": "edge", "insecureEdgeTerminationPolicy": "Redirect"},
+	}, objs[0].Object["spec"])
+
+	require.Equal(t, "web-0-1", objs[1].GetName())
+	require.Equal(t, map[string]interface{}{
+		"host": "secure.example.com",
+		"path": "/api",
+		"to":   map[string]interface{}{"kind": "Service", "name": "api", "weight": int64(100)},
+		"port": map[string]interface{}{"targetPort": "http"},
+		"tls":  map[string]interface{}{"termination": "edge", "insecureEdgeTerminationPolicy": "Redirect"},
+	}, objs[1].Object["spec"])
+
+	require.Equal(t, "web-1-0", objs[2].GetName())
+	require.Equal(t, map[string]interface{}{
+		"to":   map[string]interface{}{"kind": "Service", "name": "web", "weight": int64(100)},
+		"port": map[string]interface{}{"targetPort": int64(8080)},
+	}, objs[2].Object["spec"])
+}
+
+func TestMaterializeHTTPRoutes(t *testing.T) {
+	objs := materialize(ModeGateway, testIngress(), GatewayReference{Namespace: "gateways", Name: "public"})
+	require.Len(t, objs, 2)
+
+	parentRefs := []interface{}{map[string]interface{}{"namespace": "gateways", "name": "public"}}
+
+	require.Equal(t, "web-0", objs[0].GetName())
+	require.Equal(t, "HTTPRoute", objs[0].GetKind())
+	// the named service port cannot be expressed as backend reference
+	require.Equal(t, map[string]interface{}{
+		"parentRefs": parentRefs,
+		"hostnames":  []interface{}{"secure.example.com"},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches":     []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/"}}},
+				"backendRefs": []interface{}{map[string]interface{}{"name": "web", "port": int64(8080)}},
+			},
+		},
+	}, objs[0].Object["spec"])
+
+	require.Equal(t, "web-1", objs[1].GetName())
+	require.Equal(t, map[string]interface{}{
+		"parentRefs": parentRefs,
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches":     []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/"}}},
+				"backendRefs": []interface{}{map[string]interface{}{"name": "web", "port": int64(8080)}},
+			},
+		},
+	}, objs[1].Object["spec"])
+}
+
+func TestHostnames(t *testing.T) {
+	condition := func(conditionType, status string) []interface{} {
+		return []interface{}{map[string]interface{}{"type": conditionType, "status": status}}
+	}
+
+	tests := map[string]struct {
+		mode Mode
+		objs []*unstructured.Unstructured
+		want []string
+	}{
+		"admitted routes": {
+			mode: ModeRoute,
+			objs: []*unstructured.Unstructured{
+				{Object: map[string]interface{}{"status": map[string]interface{}{"ingress": []interface{}{
+					map[string]interface{}{"host": "web-ns.apps.example.com", "conditions": condition("Admitted", "True")},
+					map[string]interface{}{"host": "web-ns.shard.example.com", "conditions": condition("Admitted", "False")},
+				}}}},
+				{Object: map[string]interface{}{"status": map[string]interface{}{"ingress": []interface{}{
+					map[string]interface{}{"host": "secure.example.com", "conditions": condition("Admitted", "True")},
+					map[string]interface{}{"host": "web-ns.apps.example.com", "conditions": condition("Admitted", "True")},
+				}}}},
+				{Object: map[string]interface{}{}},
+			},
+			want: []string{"secure.example.com", "web-ns.apps.example.com"},
+		},
+		"accepted http routes": {
+			mode: ModeGateway,
+			objs: []*unstructured.Unstructured{
+				{Object: map[string]interface{}{
+					"spec":   map[string]interface{}{"hostnames": []interface{}{"secure.example.com"}},
+					"status": map[string]interface{}{"parents": []interface{}{map[string]interface{}{"conditions": condition("Accepted", "True")}}},
+				}},
+				{Object: map[string]interface{}{
+					"spec":   map[string]interface{}{"hostnames": []interface{}{"pending.example.com"}},
+					"status": map[string]interface{}{"parents": []interface{}{map[string]interface{}{"conditions": condition("Accepted", "False")}}},
+				}},
+			},
+			want: []string{"secure.example.com"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.want, hostnames(tt.mode, tt.objs))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"fmt"
+	"plugin"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MutatorSymbolName is the name of the symbol a mutator plugin has to export. The symbol
+// must be a variable of a type implementing Mutator.
+const MutatorSymbolName = "Mutator"
+
+// Mutator mutates objects of certain resources while they are synced. It allows cluster
+// specific adjustments like storage classes or runtime classes without changing the syncer.
+//
+// Mutators are loaded from Go plugins, i.e. they have to be built with -buildmode=plugin
+// against the same kcp version and the same Go version as the syncer.
+type Mutator interface {
+	// GVRs returns the resources the mutator applies to.
+	GVRs() []schema.GroupVersionResource
+
+	// MutateDownstream mutates an object synced from kcp before it is applied to the
+	// physical cluster.
+	MutateDownstream(obj *unstructured.Unstructured) error
+
+	// MutateUpstream mutates an object of the physical cluster before its status is
+	// synced to kcp.
+	MutateUpstream(obj *unstructured.Unstructured) error
+}
+
+// Mutators is an ordered list of mutators.
+type Mutators []Mutator
+
+// LoadMutators loads the mutator plugins from the given paths, in order.
+func LoadMutators(paths []string) (Mutators, error) {
+	mutators := make(Mutators, 0, len(paths))
+	for _, path := range paths {
+		p, err := plugin.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open mutator plugin %q: %w", path, err)
+		}
+		sym, err := p.Lookup(MutatorSymbolName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load mutator plugin %q: %w", path, err)
+		}
+		// the symbol of an exported variable is a pointer to it
+		m, ok := sym.(*Mutator)
+		if !ok || *m == nil {
+			return nil, fmt.Errorf("failed to load mutator plugin %q: symbol %s is %T, not a non-nil plugins.Mutator variable", path, MutatorSymbolName, sym)
+		}
+		mutators = append(mutators, *m)
+	}
+	return mutators, nil
+}
+
+// MutateDownstream runs the downstream mutation of all mutators applying to the given resource.
+func (ms Mutators) MutateDownstream(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	for _, m := range ms {
+		if !appliesTo(m, gvr) {
+			continue
+		}
+		if err := m.MutateDownstream(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MutateUpstream runs the upstream mutation of all mutators applying to the given resource.
+func (ms Mutators) MutateUpstream(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	for _, m := range ms {
+		if !appliesTo(m, gvr) {
+			continue
+		}
+		if err := m.MutateUpstream(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppliesTo returns whether any mutator applies to the given resource.
+func (ms Mutators) AppliesTo(gvr schema.GroupVersionResource) bool {
+	for _, m := range ms {
+		if appliesTo(m, gvr) {
+			return true
+		}
+	}
+	return false
+}
+
+func appliesTo(m Mutator, gvr schema.GroupVersionResource) bool {
+	for _, r := range m.GVRs() {
+		if r == gvr {
+			return true
+		}
+	}
+	return false
+}
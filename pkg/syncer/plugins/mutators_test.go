@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	pvcs = schema.GroupVersionResource{Version: "v1", Resource: "persistentvolumeclaims"}
+	pods = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+)
+
+type fakeMutator struct {
+	gvrs       []schema.GroupVersionResource
+	field      string
+	value      string
+	downstream bool
+	err        error
+}
+
+func (m *fakeMutator) GVRs() []schema.GroupVersionResource {
+	return m.gvrs
+}
+
+func (m *fakeMutator) MutateDownstream(obj *unstructured.Unstructured) error {
+	if !m.downstream {
+		return nil
+	}
+	return m.mutate(obj)
+}
+
+func (m *fakeMutator) MutateUpstream(obj *unstructured.Unstructured) error {
+	if m.downstream {
+		return nil
+	}
+	return m.mutate(obj)
+}
+
+func (m *fakeMutator) mutate(obj *unstructured.Unstructured) error {
+	if m.err != nil {
+		return m.err
+	}
+	value, _, _ := unstructured.NestedString(obj.Object, "spec", m.field)
+	return unstructured.SetNestedField(obj.Object, value+m.value, "spec", m.field)
+}
+
+func TestMutators(t *testing.T) {
+	tests := map[string]struct {
+		mutators       Mutators
+		gvr            schema.GroupVersionResource
+		wantDownstream map[string]interface{}
+		wantUpstream   map[string]interface{}
+		wantErr        bool
+	}{
+		"no mutators": {
+			gvr:            pvcs,
+			wantDownstream: map[string]interface{}{},
+			wantUpstream:   map[string]interface{}{},
+		},
+		"mutators are applied in order": {
+			mutators: Mutators{
+				&fakeMutator{gvrs: []schema.GroupVersionResource{pvcs}, field: "storageClassName", value: "fast", downstream: true},
+				&fakeMutator{gvrs: []schema.GroupVersionResource{pods, pvcs}, field: "storageClassName", value: "-ssd", downstream: true},
+				&fakeMutator{gvrs: []schema.GroupVersionResource{pvcs}, field: "volumeName", value: "local"},
+			},
+			gvr:            pvcs,
+			wantDownstream: map[string]interface{}{"storageClassName": "fast-ssd"},
+			wantUpstream:   map[string]interface{}{"volumeName": "local"},
+		},
+		"mutators of other resources are skipped": {
+			mutators: Mutators{
+				&fakeMutator{gvrs: []schema.GroupVersionResource{pods}, field: "runtimeClassName", value: "gvisor", downstream: true},
+				&fakeMutator{gvrs: []schema.GroupVersionResource{pods}, field: "runtimeClassName", value: "gvisor"},
+			},
+			gvr:            pvcs,
+			wantDownstream: map[string]interface{}{},
+			wantUpstream:   map[string]interface{}{},
+		},
+		"error": {
+			mutators: Mutators{
+				&fakeMutator{gvrs: []schema.GroupVersionResource{pvcs}, downstream: true, err: errors.New("boom")},
+				&fakeMutator{gvrs: []schema.GroupVersionResource{pvcs}, err: errors.New("boom")},
+			},
+			gvr:     pvcs,
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			downstream := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+			errDownstream := tt.mutators.MutateDownstream(tt.gvr, downstream)
+			upstream := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+			errUpstream := tt.mutators.MutateUpstream(tt.gvr, upstream)
+			if tt.wantErr {
+				require.Error(t, errDownstream)
+				require.Error(t, errUpstream)
+				return
+			}
+			require.NoError(t, errDownstream)
+			require.NoError(t, errUpstream)
+			require.Equal(t, tt.wantDownstream, downstream.Object["spec"])
+			require.Equal(t, tt.wantUpstream, upstream.Object["spec"])
+		})
+	}
+}
+
+func TestLoadMutatorsMissingPlugin(t *testing.T) {
+	_, err := LoadMutators([]string{"/does/not/exist.so"})
+	require.Error(t, err)
+}
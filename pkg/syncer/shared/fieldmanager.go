@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shared
+
+import (
+	"strings"
+)
+
+const (
+	// SyncerFieldManagerPrefix is the prefix of the field manager recorded in the managed fields
+	// of upstream resources written by a syncer through the syncer virtual workspace.
+	// The full field manager name is suffixed with the SyncTarget key, so that the field
+	// ownership of each SyncTarget stays distinct from the other ones, and from the user.
+	SyncerFieldManagerPrefix = "syncer-"
+
+	// StatusCoordinationFieldManager is the field manager of the aggregated status written to
+	// upstream resources synced to several SyncTargets. As the syncers, it never conflicts
+	// with the promotion of a SyncTarget status to the upstream resource.
+	StatusCoordinationFieldManager = "kcp-status-coordination"
+)
+
+// SyncerFieldManager returns the deterministic field manager used for the writes
+// of the syncer of the given SyncTarget.
+func SyncerFieldManager(syncTargetKey string) string {
+	return SyncerFieldManagerPrefix + syncTargetKey
+}
+
+// IsSyncerFieldManager returns true if the given field manager is the field manager of any syncer.
+func IsSyncerFieldManager(fieldManager string) bool {
+	return strings.HasPrefix(fieldManager, SyncerFieldManagerPrefix)
+}
@@ -26,10 +26,18 @@ import (
 	"github.com/martinlindhe/base36"
 
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 )
 
 const (
 	NamespaceLocatorAnnotation = "kcp.dev/namespace-locator"
+
+	// DownstreamNamespaceAnnotation is set by the syncer virtual workspace on namespaced resources, to the
+	// name of the namespace on the physical cluster according to the namespace naming strategy of the
+	// SyncTarget, if that is not the default hash strategy.
+	DownstreamNamespaceAnnotation = "kcp.dev/downstream-namespace"
 )
 
 // NamespaceLocator stores a logical cluster and namespace and is used
@@ -105,3 +113,27 @@ func PhysicalClusterNamespaceName(l NamespaceLocator) (string, error) {
 	// keep the namespaces short enough.
 	return fmt.Sprintf("kcp-%s", base36hash[:12]), nil
 }
+
+// DownstreamNamespaceName returns the namespace name on a physical cluster for the NamespaceLocator,
+// according to the given namespace naming strategy of the SyncTarget. The name is repeatable.
+func DownstreamNamespaceName(strategy workloadv1alpha1.NamespaceNamingStrategy, l NamespaceLocator) (string, error) {
+	switch strategy {
+	case workloadv1alpha1.NamespaceNamingPassthrough:
+		return l.Namespace, nil
+	case workloadv1alpha1.NamespaceNamingWorkspacePrefixed:
+		name := strings.ReplaceAll(l.Workspace.String(), ":", "-") + "-" + l.Namespace
+		if len(name) <= validation.DNS1123LabelMaxLength {
+			return name, nil
+		}
+		// keep the name unique by the hash of the locator
+		hashed, err := PhysicalClusterNamespaceName(l)
+		if err != nil {
+			return "", err
+		}
+		suffix := strings.TrimPrefix(hashed, "kcp-")
+		prefix := strings.TrimRight(name[:validation.DNS1123LabelMaxLength-len(suffix)-1], "-")
+		return prefix + "-" + suffix, nil
+	default:
+		return PhysicalClusterNamespaceName(l)
+	}
+}
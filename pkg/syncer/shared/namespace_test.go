@@ -22,6 +22,8 @@ import (
 	"testing"
 
 	"github.com/kcp-dev/logicalcluster/v2"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 )
 
 func TestLocatorFromAnnotations(t *testing.T) {
@@ -99,3 +101,53 @@ func TestLocatorFromAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func TestDownstreamNamespaceName(t *testing.T) {
+	locator := NewNamespaceLocator(logicalcluster.New("root:org:team"), logicalcluster.New("root:org"), "uid", "cluster", "default")
+	hashed, err := PhysicalClusterNamespaceName(locator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	long := locator
+	long.Workspace = logicalcluster.New("root:" + strings.Repeat("a", 60))
+
+	tests := []struct {
+		name     string
+		strategy workloadv1alpha1.NamespaceNamingStrategy
+		locator  NamespaceLocator
+		want     string
+	}{
+		{name: "default", locator: locator, want: hashed},
+		{name: "hash", strategy: workloadv1alpha1.NamespaceNamingHash, locator: locator, want: hashed},
+		{name: "passthrough", strategy: workloadv1alpha1.NamespaceNamingPassthrough, locator: locator, want: "default"},
+		{name: "workspace prefixed", strategy: workloadv1alpha1.NamespaceNamingWorkspacePrefixed, locator: locator, want: "root-org-team-default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DownstreamNamespaceName(tt.strategy, tt.locator)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("DownstreamNamespaceName() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("workspace prefixed too long", func(t *testing.T) {
+		got, err := DownstreamNamespaceName(workloadv1alpha1.NamespaceNamingWorkspacePrefixed, long)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 63 || !strings.HasPrefix(got, "root-aaaa") {
+			t.Errorf("DownstreamNamespaceName() got = %q, want a 63 characters name prefixed by the workspace", got)
+		}
+		again, err := DownstreamNamespaceName(workloadv1alpha1.NamespaceNamingWorkspacePrefixed, long)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != again {
+			t.Errorf("DownstreamNamespaceName() is not repeatable: %q vs %q", got, again)
+		}
+	})
+}
@@ -49,6 +49,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/syncer/plugins"
 	"github.com/kcp-dev/kcp/pkg/syncer/resourcesync"
 	"github.com/kcp-dev/kcp/pkg/syncer/shared"
 	"github.com/kcp-dev/kcp/pkg/syncer/spec/dns"
@@ -64,8 +65,9 @@ const (
 type Controller struct {
 	queue workqueue.RateLimitingInterface
 
-	mutators     mutatorGvrMap
-	dnsProcessor *dns.DNSProcessor
+	mutators       mutatorGvrMap
+	pluginMutators plugins.Mutators
+	dnsProcessor   *dns.DNSProcessor
 
 	upstreamClient            kcpdynamic.ClusterInterface
 	downstreamClient          dynamic.Interface
@@ -92,7 +94,8 @@ func NewSpecSyncer(syncerLogger logr.Logger, syncTargetWorkspace logicalcluster.
 	serviceLister listerscorev1.ServiceLister,
 	endpointLister listerscorev1.EndpointsLister,
 	dnsNamespace string,
-	dnsImage string) (*Controller, error) {
+	dnsImage string,
+	pluginMutators plugins.Mutators) (*Controller, error) {
 
 	c := Controller{
 		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName),
@@ -107,6 +110,8 @@ func NewSpecSyncer(syncerLogger logr.Logger, syncTargetWorkspace logicalcluster.
 		syncTargetUID:             syncTargetUID,
 		syncTargetKey:             syncTargetKey,
 		advancedSchedulingEnabled: advancedSchedulingEnabled,
+
+		pluginMutators: pluginMutators,
 	}
 
 	namespaceGVR := schema.GroupVersionResource{
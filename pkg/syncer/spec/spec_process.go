@@ -124,6 +124,7 @@ func (c *Controller) process(ctx context.Context, gvr schema.GroupVersionResourc
 	}
 
 	var downstreamNamespace string
+	var downstreamNamespaceFound bool
 	// Only look for the downstream namespace if the resource is namespaced, avoid in case of cluster-scoped.
 	if upstreamNamespace != "" {
 		downstreamNamespaces, err := c.downstreamNSInformer.Informer().GetIndexer().ByIndex(byNamespaceLocatorIndexName, string(jsonNSLocator))
@@ -135,6 +136,7 @@ func (c *Controller) process(ctx context.Context, gvr schema.GroupVersionResourc
 			namespace := downstreamNamespaces[0].(*unstructured.Unstructured)
 			logger.WithValues(DownstreamName, namespace.GetName()).V(4).Info("Found downstream namespace for upstream namespace")
 			downstreamNamespace = namespace.GetName()
+			downstreamNamespaceFound = true
 		} else if len(downstreamNamespaces) > 1 {
 			// This should never happen unless there's some namespace collision.
 			var namespacesCollisions []string
@@ -187,6 +189,13 @@ func (c *Controller) process(ctx context.Context, gvr schema.GroupVersionResourc
 		return nil, fmt.Errorf("object to synchronize is expected to be Unstructured, but is %T", obj)
 	}
 
+	// The syncer virtual workspace names the downstream namespace if the SyncTarget does not use the default
+	// namespace naming strategy. Existing downstream namespaces keep their name.
+	if namespaceName := upstreamObj.GetAnnotations()[shared.DownstreamNamespaceAnnotation]; namespaceName != "" && downstreamNamespace != "" && !downstreamNamespaceFound {
+		downstreamNamespace = namespaceName
+		logger = logger.WithValues(DownstreamNamespace, downstreamNamespace)
+	}
+
 	if downstreamNamespace != "" {
 		if err := c.ensureDownstreamNamespaceExists(ctx, downstreamNamespace, upstreamObj); err != nil {
 			return nil, err
@@ -419,6 +428,9 @@ func (c *Controller) applyToDownstream(ctx context.Context, gvr schema.GroupVers
 			return err
 		}
 	}
+	if err := c.pluginMutators.MutateDownstream(gvr, downstreamObj); err != nil {
+		return err
+	}
 
 	downstreamObj.SetName(transformedName)
 	downstreamObj.SetUID("")
@@ -429,6 +441,7 @@ func (c *Controller) applyToDownstream(ctx context.Context, gvr schema.GroupVers
 	// Strip cluster name annotation
 	downstreamAnnotations := downstreamObj.GetAnnotations()
 	delete(downstreamAnnotations, logicalcluster.AnnotationKey)
+	delete(downstreamAnnotations, shared.DownstreamNamespaceAnnotation)
 	//TODO(jmprusi): To be removed when switching to the syncer Virtual Workspace transformations.
 	delete(downstreamAnnotations, workloadv1alpha1.InternalClusterStatusAnnotationPrefix+c.syncTargetKey)
 	// If the resource is cluster-scoped, we need to add the namespaceLocator annotation to get be able to
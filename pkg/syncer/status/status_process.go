@@ -153,6 +153,13 @@ func (c *Controller) process(ctx context.Context, gvr schema.GroupVersionResourc
 func (c *Controller) updateStatusInUpstream(ctx context.Context, gvr schema.GroupVersionResource, upstreamNamespace, upstreamName string, upstreamLogicalCluster logicalcluster.Name, downstreamObj *unstructured.Unstructured) error {
 	logger := klog.FromContext(ctx)
 
+	if c.pluginMutators.AppliesTo(gvr) {
+		downstreamObj = downstreamObj.DeepCopy()
+		if err := c.pluginMutators.MutateUpstream(gvr, downstreamObj); err != nil {
+			return err
+		}
+	}
+
 	downstreamStatus, statusExists, err := unstructured.NestedFieldCopy(downstreamObj.UnstructuredContent(), "status")
 	if err != nil {
 		return err
@@ -26,6 +26,7 @@ import (
 	kcpdynamicinformer "github.com/kcp-dev/client-go/dynamic/dynamicinformer"
 	"github.com/kcp-dev/logicalcluster/v2"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,13 +38,17 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/pkg/version"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 	kcpclusterclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
+	"github.com/kcp-dev/kcp/pkg/syncer/globaldns"
+	"github.com/kcp-dev/kcp/pkg/syncer/ingress"
 	"github.com/kcp-dev/kcp/pkg/syncer/namespace"
+	"github.com/kcp-dev/kcp/pkg/syncer/plugins"
 	"github.com/kcp-dev/kcp/pkg/syncer/resourcesync"
 	"github.com/kcp-dev/kcp/pkg/syncer/spec"
 	"github.com/kcp-dev/kcp/pkg/syncer/status"
@@ -53,6 +58,16 @@ import (
 const (
 	AdvancedSchedulingFeatureAnnotation = "featuregates.experimental.workload.kcp.dev/advancedscheduling"
 
+	// IngressMaterializationAnnotation on a SyncTarget selects how synced Ingresses are exposed on the
+	// physical cluster: Ingress (default), Route or Gateway.
+	IngressMaterializationAnnotation = "experimental.workload.kcp.dev/ingress-materialization"
+	// IngressGatewayAnnotation on a SyncTarget references the Gateway as <namespace>/<name> that
+	// HTTPRoutes are attached to in the Gateway ingress materialization mode.
+	IngressGatewayAnnotation = "experimental.workload.kcp.dev/ingress-gateway"
+	// GlobalDNSZoneAnnotation on a SyncTarget enables publishing the load balancer Services synced
+	// to it as external-dns records in the given zone, under a name independent of the SyncTarget.
+	GlobalDNSZoneAnnotation = "experimental.workload.kcp.dev/global-dns-zone"
+
 	resyncPeriod = 10 * time.Hour
 
 	// TODO(marun) Coordinate this value with the interval configured for the heartbeat controller
@@ -71,6 +86,7 @@ type SyncerConfig struct {
 	SyncTargetUID                 string
 	DownstreamNamespaceCleanDelay time.Duration
 	DNSImage                      string
+	MutatorPlugins                []string
 }
 
 func StartSyncer(ctx context.Context, cfg *SyncerConfig, numSyncerThreads int, importPollInterval time.Duration, syncerNamespace string) error {
@@ -78,6 +94,11 @@ func StartSyncer(ctx context.Context, cfg *SyncerConfig, numSyncerThreads int, i
 	logger = logger.WithValues(SyncTargetWorkspace, cfg.SyncTargetWorkspace, SyncTargetName, cfg.SyncTargetName)
 	logger.V(2).Info("starting syncer")
 
+	pluginMutators, err := plugins.LoadMutators(cfg.MutatorPlugins)
+	if err != nil {
+		return err
+	}
+
 	kcpVersion := version.Get().GitVersion
 
 	bootstrapConfig := rest.CopyConfig(cfg.UpstreamConfig)
@@ -231,18 +252,40 @@ func StartSyncer(ctx context.Context, cfg *SyncerConfig, numSyncerThreads int, i
 
 	specSyncer, err := spec.NewSpecSyncer(logger, cfg.SyncTargetWorkspace, cfg.SyncTargetName, syncTargetKey, upstreamURL, advancedSchedulingEnabled,
 		upstreamDynamicClusterClient, downstreamDynamicClient, downstreamKubeClient, upstreamInformers, downstreamInformers, downstreamNamespaceController, syncerInformers, syncTarget.GetUID(),
-		serviceAccountLister, roleLister, roleBindingLister, deploymentLister, serviceLister, endpointLister, syncerNamespace, cfg.DNSImage)
+		serviceAccountLister, roleLister, roleBindingLister, deploymentLister, serviceLister, endpointLister, syncerNamespace, cfg.DNSImage, pluginMutators)
 	if err != nil {
 		return err
 	}
 
 	logger.Info("Creating status syncer")
 	statusSyncer, err := status.NewStatusSyncer(logger, cfg.SyncTargetWorkspace, cfg.SyncTargetName, syncTargetKey, advancedSchedulingEnabled,
-		upstreamDynamicClusterClient, downstreamDynamicClient, downstreamInformers, syncerInformers, syncTarget.GetUID())
+		upstreamDynamicClusterClient, downstreamDynamicClient, downstreamInformers, syncerInformers, syncTarget.GetUID(), pluginMutators)
 	if err != nil {
 		return err
 	}
 
+	var ingressController *ingress.Controller
+	if mode := ingress.Mode(syncTarget.GetAnnotations()[IngressMaterializationAnnotation]); mode != "" && mode != ingress.ModeIngress {
+		logger.Info("Creating ingress controller", "mode", mode)
+		var gateway ingress.GatewayReference
+		if ref := syncTarget.GetAnnotations()[IngressGatewayAnnotation]; ref != "" {
+			gateway.Namespace, gateway.Name, err = cache.SplitMetaNamespaceKey(ref)
+			if err != nil {
+				return fmt.Errorf("invalid %s annotation %q: %w", IngressGatewayAnnotation, ref, err)
+			}
+		}
+		ingressController, err = ingress.NewController(logger, mode, gateway, downstreamDynamicClient, downstreamInformers)
+		if err != nil {
+			return err
+		}
+	}
+
+	var globalDNSController *globaldns.Controller
+	if zone := syncTarget.GetAnnotations()[GlobalDNSZoneAnnotation]; zone != "" {
+		logger.Info("Creating global DNS controller", "zone", zone)
+		globalDNSController = globaldns.NewController(logger, zone, downstreamDynamicClient, downstreamInformers)
+	}
+
 	upstreamInformers.Start(ctx.Done())
 	downstreamInformers.Start(ctx.Done())
 	kcpInformerFactory.Start(ctx.Done())
@@ -258,6 +301,12 @@ func StartSyncer(ctx context.Context, cfg *SyncerConfig, numSyncerThreads int, i
 	go specSyncer.Start(ctx, numSyncerThreads)
 	go statusSyncer.Start(ctx, numSyncerThreads)
 	go downstreamNamespaceController.Start(ctx, numSyncerThreads)
+	if ingressController != nil {
+		go ingressController.Start(ctx, numSyncerThreads)
+	}
+	if globalDNSController != nil {
+		go globalDNSController.Start(ctx, numSyncerThreads)
+	}
 
 	if kcpfeatures.DefaultFeatureGate.Enabled(kcpfeatures.SyncerTunnel) {
 		go startSyncerTunnel(ctx, upstreamConfig, downstreamConfig, cfg.SyncTargetWorkspace, cfg.SyncTargetName)
@@ -270,8 +319,21 @@ func StartSyncer(ctx context.Context, cfg *SyncerConfig, numSyncerThreads int, i
 		// TODO(marun) Figure out a strategy for backoff to avoid a thundering herd problem with lots of syncers
 		// Attempt to heartbeat every second until successful. Errors are logged instead of being returned so the
 		// poll error can be safely ignored.
+		// Report the capacity of the downstream cluster along with the heartbeat. This is best effort, the
+		// syncer might not be allowed to list nodes with an older RBAC setup.
+		var capacity, allocatable corev1.ResourceList
+		if nodes, err := downstreamKubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{ResourceVersion: "0"}); err != nil {
+			logger.V(4).Info("failed to list downstream nodes, not reporting capacity", "err", err)
+		} else {
+			capacity, allocatable = downstreamCapacity(nodes.Items)
+		}
+
 		_ = wait.PollImmediateInfiniteWithContext(ctx, 1*time.Second, func(ctx context.Context) (bool, error) {
-			patchBytes := []byte(fmt.Sprintf(`[{"op":"test","path":"/metadata/uid","value":%q},{"op":"replace","path":"/status/lastSyncerHeartbeatTime","value":%q}]`, cfg.SyncTargetUID, time.Now().Format(time.RFC3339)))
+			patchBytes, err := heartbeatPatch(cfg.SyncTargetUID, time.Now(), capacity, allocatable)
+			if err != nil {
+				logger.Error(err, "failed to create heartbeat patch")
+				return false, nil //nolint:nilerr
+			}
 			syncTarget, err = kcpBootstrapClient.WorkloadV1alpha1().SyncTargets().Patch(ctx, cfg.SyncTargetName, types.JSONPatchType, patchBytes, metav1.PatchOptions{}, "status")
 			if err != nil {
 				logger.Error(err, "failed to set status.lastSyncerHeartbeatTime")
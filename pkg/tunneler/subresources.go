@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunneler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
+)
+
+var (
+	errorScheme = runtime.NewScheme()
+	errorCodecs = serializer.NewCodecFactory(errorScheme)
+)
+
+// WithSubresourceProxy routes pods/portforward and services/proxy requests against workspace objects
+// through the syncer tunnel to the physical cluster the namespace is synced to. The request must have
+// been authorized already, i.e. the handler has to be wrapped by the authorization filter.
+//
+// If the namespace is synced to multiple sync targets, the request goes to the first of them, ordered
+// by sync target key.
+func (tn *Tunneler) WithSubresourceProxy(
+	apiHandler http.Handler,
+	getNamespace func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error),
+	getSyncTarget func(syncTargetKey string) (*workloadv1alpha1.SyncTarget, error),
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		info, ok := request.RequestInfoFrom(ctx)
+		if !ok || !isTunneledSubresource(info) {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+		cluster := request.ClusterFrom(ctx)
+		if cluster == nil || cluster.Name.Empty() || cluster.Name == logicalcluster.Wildcard {
+			apiHandler.ServeHTTP(w, req)
+			return
+		}
+
+		gv := schema.GroupVersion{Group: info.APIGroup, Version: info.APIVersion}
+		ns, err := getNamespace(cluster.Name, info.Namespace)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, errorCodecs, gv, w, req)
+			return
+		}
+
+		syncTargetKey := syncedSyncTargetKey(ns)
+		if syncTargetKey == "" {
+			err := apierrors.NewServiceUnavailable(fmt.Sprintf("namespace %q is not synced to any sync target", info.Namespace))
+			responsewriters.ErrorNegotiated(err, errorCodecs, gv, w, req)
+			return
+		}
+		syncTarget, err := getSyncTarget(syncTargetKey)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, errorCodecs, gv, w, req)
+			return
+		}
+
+		locator := shared.NewNamespaceLocator(cluster.Name, logicalcluster.From(syncTarget), syncTarget.UID, syncTarget.Name, info.Namespace)
+		downstreamNamespace, err := shared.DownstreamNamespaceName(syncTarget.Spec.NamespaceNaming, locator)
+		if err != nil {
+			responsewriters.ErrorNegotiated(apierrors.NewInternalError(err), errorCodecs, gv, w, req)
+			return
+		}
+
+		// info.Parts is <resource>/<name>/<subresource>[/<proxied path>]
+		path := "/api/v1/namespaces/" + downstreamNamespace + "/" + strings.Join(info.Parts, "/")
+		if strings.HasSuffix(req.URL.Path, "/") && !strings.HasSuffix(path, "/") {
+			path += "/"
+		}
+
+		klog.FromContext(ctx).V(4).Info("proxying subresource request through syncer tunnel", "syncTarget", syncTargetKey, "downstreamNamespace", downstreamNamespace, "path", path)
+		tn.Proxy(w, req, logicalcluster.From(syncTarget).String(), syncTarget.Name, path)
+	}
+}
+
+func isTunneledSubresource(info *request.RequestInfo) bool {
+	if !info.IsResourceRequest || info.APIGroup != "" || info.APIVersion != "v1" || info.Namespace == "" || info.Name == "" {
+		return false
+	}
+	return (info.Resource == "pods" && info.Subresource == "portforward") ||
+		(info.Resource == "services" && info.Subresource == "proxy")
+}
+
+// syncedSyncTargetKey returns the first sync target key the namespace is synced to.
+func syncedSyncTargetKey(ns *corev1.Namespace) string {
+	var keys []string
+	for k, v := range ns.Labels {
+		if !strings.HasPrefix(k, workloadv1alpha1.ClusterResourceStateLabelPrefix) {
+			continue
+		}
+		if workloadv1alpha1.ResourceState(v) != workloadv1alpha1.ResourceStateSync {
+			continue
+		}
+		keys = append(keys, strings.TrimPrefix(k, workloadv1alpha1.ClusterResourceStateLabelPrefix))
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	return keys[0]
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunneler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
+)
+
+func TestWithSubresourceProxy(t *testing.T) {
+	consumer := logicalcluster.New("root:consumer")
+	syncTarget := &workloadv1alpha1.SyncTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "d001",
+			UID:         "uid",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "ws"},
+		},
+	}
+	syncTargetKey := workloadv1alpha1.ToSyncTargetKey(logicalcluster.New("ws"), "d001")
+	downstreamNamespace, err := shared.PhysicalClusterNamespaceName(shared.NewNamespaceLocator(consumer, logicalcluster.New("ws"), "uid", "d001", "synced"))
+	require.NoError(t, err)
+
+	namespaces := map[string]*corev1.Namespace{
+		"synced": {ObjectMeta: metav1.ObjectMeta{Name: "synced", Labels: map[string]string{
+			workloadv1alpha1.ClusterResourceStateLabelPrefix + syncTargetKey: string(workloadv1alpha1.ResourceStateSync),
+		}}},
+		"pending": {ObjectMeta: metav1.ObjectMeta{Name: "pending", Labels: map[string]string{
+			workloadv1alpha1.ClusterResourceStateLabelPrefix + syncTargetKey: "",
+		}}},
+	}
+
+	// physical cluster echoing the request path
+	backend := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s?%s", r.URL.Path, r.URL.RawQuery)
+	}))
+	defer backend.Close()
+
+	// kcp
+	tn := NewTunneler()
+	requestInfoFactory := &request.RequestInfoFactory{APIPrefixes: sets.NewString("api", "apis"), GrouplessAPIPrefixes: sets.NewString("api")}
+	apiHandler := tn.WithSubresourceProxy(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}),
+		func(clusterName logicalcluster.Name, name string) (*corev1.Namespace, error) {
+			if ns, found := namespaces[name]; found && clusterName == consumer {
+				return ns, nil
+			}
+			return nil, apierrors.NewNotFound(corev1.Resource("namespaces"), name)
+		},
+		func(key string) (*workloadv1alpha1.SyncTarget, error) {
+			require.Equal(t, syncTargetKey, key)
+			return syncTarget, nil
+		},
+	)
+	publicServer := httptest.NewTLSServer(tn.WithSyncerTunnel(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, err := requestInfoFactory.NewRequestInfo(r)
+		require.NoError(t, err)
+		ctx := request.WithRequestInfo(r.Context(), info)
+		ctx = request.WithCluster(ctx, request.Cluster{Name: consumer})
+		apiHandler.ServeHTTP(w, r.WithContext(ctx))
+	})))
+	defer publicServer.Close()
+
+	// syncer
+	dstURL, err := SyncerTunnelURL(publicServer.URL, "ws", "d001")
+	require.NoError(t, err)
+	l, err := NewListener(publicServer.Client(), dstURL)
+	require.NoError(t, err)
+	defer l.Close()
+	backendURL, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+	proxy := httputil.NewSingleHostReverseProxy(backendURL)
+	proxy.Transport = backend.Client().Transport
+	server := &http.Server{Handler: proxy}
+	defer server.Close()
+	//nolint:errcheck
+	go server.Serve(l)
+	// wait for the reverse connection to be established
+	time.Sleep(1 * time.Second)
+
+	tests := map[string]struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		"service proxy": {
+			path:       "/api/v1/namespaces/synced/services/web:8080/proxy/healthz?verbose=true",
+			wantStatus: http.StatusOK,
+			wantBody:   "/api/v1/namespaces/" + downstreamNamespace + "/services/web:8080/proxy/healthz?verbose=true",
+		},
+		"pod port-forward": {
+			path:       "/api/v1/namespaces/synced/pods/web-1/portforward?ports=8080",
+			wantStatus: http.StatusOK,
+			wantBody:   "/api/v1/namespaces/" + downstreamNamespace + "/pods/web-1/portforward?ports=8080",
+		},
+		"namespace not synced": {
+			path:       "/api/v1/namespaces/pending/services/web/proxy/",
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		"namespace not found": {
+			path:       "/api/v1/namespaces/unknown/services/web/proxy/",
+			wantStatus: http.StatusNotFound,
+		},
+		"other subresource": {
+			path:       "/api/v1/namespaces/synced/pods/web-1/log",
+			wantStatus: http.StatusTeapot,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := publicServer.Client().Get(publicServer.URL + tt.path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus, resp.StatusCode, string(body))
+			if tt.wantBody != "" {
+				require.Equal(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
@@ -109,12 +109,29 @@ func SyncerTunnelURL(host, ws, target string) (string, error) {
 	return host + defaultTunnelPathPrefix + "/" + ws + "/apis/" + workloadv1alpha1.SchemeGroupVersion.String() + "/synctargets/" + target, nil
 }
 
-// HTTP Handler that handles reverse connections and reverse proxy requests using 2 different paths:
+// Tunneler holds the reverse connections of the syncers, and proxies requests through them.
+type Tunneler struct {
+	pool *tunnelPool
+}
+
+// NewTunneler returns a Tunneler without any connected syncers.
+func NewTunneler() *Tunneler {
+	return &Tunneler{
+		pool: newTunnelPool(),
+	}
+}
+
+// WithSyncerTunnel is a shortcut for NewTunneler().WithSyncerTunnel(apiHandler).
+func WithSyncerTunnel(apiHandler http.Handler) http.HandlerFunc {
+	return NewTunneler().WithSyncerTunnel(apiHandler)
+}
+
+// WithSyncerTunnel returns an HTTP Handler that handles reverse connections and reverse proxy requests using 2 different paths:
 //
 // https://host/services/syncer-tunnels/clusters/<ws>/apis/workload.kcp.dev/v1alpha1/synctargets/<name>/connect establish reverse connections and queue them so it can be consumed by the dialer
 // https://host/services/syncer-tunnels/clusters/<ws>/apis/workload.kcp.dev/v1alpha1/synctargets/<name>/proxy/{path} proxies the {path} through the reverse connection identified by the cluster and syncer name
-func WithSyncerTunnel(apiHandler http.Handler) http.HandlerFunc {
-	pool := newTunnelPool()
+func (tn *Tunneler) WithSyncerTunnel(apiHandler http.Handler) http.HandlerFunc {
+	pool := tn.pool
 	return func(w http.ResponseWriter, r *http.Request) {
 		// fall through, syncer tunnels URL start by /services/tunnels
 		if !strings.HasPrefix(r.URL.Path, defaultTunnelPathPrefix) {
@@ -195,39 +212,12 @@ func WithSyncerTunnel(apiHandler http.Handler) http.HandlerFunc {
 			klog.V(5).Infof("Connection from %s done", r.RemoteAddr)
 
 		case cmdTunnelProxy:
-			target, err := url.Parse("http://" + syncerName)
-			if err != nil {
-				http.Error(w, "wrong url", http.StatusInternalServerError)
-				return
+			// strip the non-proxied path
+			proxypath := "/"
+			if len(path) > 7 {
+				proxypath += strings.Join(path[7:], "/")
 			}
-			d := pool.getDialer(clusterName, syncerName)
-			if d == nil || isClosedChan(d.Done()) {
-				http.Error(w, "syncer tunnels: syncer not connected", http.StatusInternalServerError)
-				return
-			}
-			proxy := httputil.NewSingleHostReverseProxy(target)
-			director := proxy.Director
-			proxy.Transport = &http.Transport{
-				Proxy:               nil,    // no proxies
-				DialContext:         d.Dial, // use a reverse connection
-				ForceAttemptHTTP2:   false,  // this is a tunneled connection
-				DisableKeepAlives:   true,   // one connection per reverse connection
-				MaxIdleConnsPerHost: -1,
-			}
-			// only proxy the proxied path and don't forward the authentication header
-			proxy.Director = func(req *http.Request) {
-				// strip the non-proxied path
-				proxypath := "/"
-				if len(path) > 7 {
-					proxypath += strings.Join(path[7:], "/")
-				}
-				req.URL.Path = proxypath
-				// TODO: strip authorization header?????
-				req.Header.Del("Authorization")
-				director(req)
-			}
-			proxy.ServeHTTP(w, r)
-			klog.V(5).Infof("proxy server closed %v ", err)
+			tn.Proxy(w, r, clusterName, syncerName, proxypath)
 		default:
 			http.Error(w, "syncer tunnels: unsupported command", http.StatusInternalServerError)
 			return
@@ -235,6 +225,39 @@ func WithSyncerTunnel(apiHandler http.Handler) http.HandlerFunc {
 	}
 }
 
+// Proxy proxies the request to the given path of the physical cluster of the sync target through
+// the reverse connection of its syncer. The authorization header of the request is not forwarded.
+func (tn *Tunneler) Proxy(w http.ResponseWriter, r *http.Request, clusterName, syncerName, path string) {
+	target, err := url.Parse("http://" + syncerName)
+	if err != nil {
+		http.Error(w, "wrong url", http.StatusInternalServerError)
+		return
+	}
+	d := tn.pool.getDialer(clusterName, syncerName)
+	if d == nil || isClosedChan(d.Done()) {
+		http.Error(w, "syncer tunnels: syncer not connected", http.StatusInternalServerError)
+		return
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+	proxy.Transport = &http.Transport{
+		Proxy:               nil,    // no proxies
+		DialContext:         d.Dial, // use a reverse connection
+		ForceAttemptHTTP2:   false,  // this is a tunneled connection
+		DisableKeepAlives:   true,   // one connection per reverse connection
+		MaxIdleConnsPerHost: -1,
+	}
+	// only proxy the proxied path and don't forward the authentication header
+	proxy.Director = func(req *http.Request) {
+		req.URL.Path = path
+		// TODO: strip authorization header?????
+		req.Header.Del("Authorization")
+		director(req)
+	}
+	proxy.ServeHTTP(w, r)
+	klog.V(5).Infof("proxy server closed for %s-%s", clusterName, syncerName)
+}
+
 // flushWriter
 type flushWriter struct {
 	w io.Writer
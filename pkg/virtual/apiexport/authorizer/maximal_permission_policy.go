@@ -86,12 +86,17 @@ func (a *maximalPermissionAuthorizer) Authorize(ctx context.Context, attr author
 		return authorizer.DecisionNoOpinion, "", err
 	}
 
-	claimedIdentityHash, found := getClaimedIdentity(claimingAPIExport, attr)
+	claim, found := getClaim(claimingAPIExport, attr)
 	if !found {
 		// it's a resource in the claiming API export, hence unclaimed
 		return authorizer.DecisionAllow, fmt.Sprintf("unclaimed resource in API export: %q, workspace :%q",
 			claimingAPIExport.Name, logicalcluster.From(claimingAPIExport)), nil
 	}
+	if namespace := attr.GetNamespace(); namespace != "" && !mayClaimNamespace(claim, namespace) {
+		return authorizer.DecisionDeny, fmt.Sprintf("namespace %q not claimed by API export: %q, workspace :%q",
+			namespace, claimingAPIExport.Name, logicalcluster.From(claimingAPIExport)), nil
+	}
+	claimedIdentityHash := claim.IdentityHash
 	if claimedIdentityHash == "" {
 		// it's a native k8s resource (secret, configmap, ...), or a system kcp CRD resource (apis.kcp.dev)
 		// For neither case a maximum permission policy can exist.
@@ -143,14 +148,29 @@ func (a *maximalPermissionAuthorizer) Authorize(ctx context.Context, attr author
 	return authorizer.DecisionAllow, "all claimed API exports granted access", nil
 }
 
-func getClaimedIdentity(apiExport *apisv1alpha1.APIExport, attr authorizer.Attributes) (string, bool) {
+func getClaim(apiExport *apisv1alpha1.APIExport, attr authorizer.Attributes) (*apisv1alpha1.PermissionClaim, bool) {
 	for i := range apiExport.Spec.PermissionClaims {
 		if apiExport.Spec.PermissionClaims[i].Resource == attr.GetResource() &&
 			apiExport.Spec.PermissionClaims[i].Group == attr.GetAPIGroup() {
-			return apiExport.Spec.PermissionClaims[i].IdentityHash, true
+			return &apiExport.Spec.PermissionClaims[i], true
+		}
+	}
+	return nil, false
+}
+
+// mayClaimNamespace returns whether the claim possibly selects objects in the given namespace.
+// Namespace selectors cannot be evaluated here, the objects they select carry the claim label
+// and are filtered by it in the storage.
+func mayClaimNamespace(claim *apisv1alpha1.PermissionClaim, namespace string) bool {
+	if claim.All || len(claim.ResourceSelector) == 0 {
+		return true
+	}
+	for _, selector := range claim.ResourceSelector {
+		if selector.Namespace == "" || selector.Namespace == namespace {
+			return true
 		}
 	}
-	return "", false
+	return false
 }
 
 func prefixAttributes(attr authorizer.Attributes) *authorizer.AttributesRecord {
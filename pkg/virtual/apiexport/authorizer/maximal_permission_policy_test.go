@@ -312,6 +312,71 @@ func TestMaximalPermissionPolicyAuthorizer(t *testing.T) {
 			expectedDecision: authorizer.DecisionNoOpinion,
 			expectedReason:   `API export: "fooExport", workspace: "someWorkspace" RBAC decision: access denied`,
 		},
+		{
+			name: "claimed resource in unclaimed namespace",
+			attr: &authorizer.AttributesRecord{
+				User:      &user.DefaultInfo{},
+				APIGroup:  "",
+				Resource:  "configmaps",
+				Namespace: "other",
+			},
+			apidomainKey: "foo/bar",
+			getAPIExport: func(clusterName, apiExportName string) (*apisv1alpha1.APIExport, error) {
+				return &apisv1alpha1.APIExport{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "fooExport",
+						Annotations: map[string]string{
+							logicalcluster.AnnotationKey: "someWorkspace",
+						},
+					},
+					Spec: apisv1alpha1.APIExportSpec{
+						PermissionClaims: []apisv1alpha1.PermissionClaim{
+							{
+								GroupResource:    apisv1alpha1.GroupResource{Resource: "configmaps"},
+								ResourceSelector: []apisv1alpha1.ResourceSelector{{Namespace: "claimed"}},
+							},
+						},
+					},
+				}, nil
+			},
+
+			expectedDecision: authorizer.DecisionDeny,
+			expectedReason:   `namespace "other" not claimed by API export: "fooExport", workspace :"someWorkspace"`,
+		},
+		{
+			name: "claimed resource in namespace possibly selected by label",
+			attr: &authorizer.AttributesRecord{
+				User:      &user.DefaultInfo{},
+				APIGroup:  "",
+				Resource:  "configmaps",
+				Namespace: "other",
+			},
+			apidomainKey: "foo/bar",
+			getAPIExport: func(clusterName, apiExportName string) (*apisv1alpha1.APIExport, error) {
+				return &apisv1alpha1.APIExport{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "fooExport",
+						Annotations: map[string]string{
+							logicalcluster.AnnotationKey: "someWorkspace",
+						},
+					},
+					Spec: apisv1alpha1.APIExportSpec{
+						PermissionClaims: []apisv1alpha1.PermissionClaim{
+							{
+								GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"},
+								ResourceSelector: []apisv1alpha1.ResourceSelector{
+									{Namespace: "claimed"},
+									{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"provider": "cert-manager"}}},
+								},
+							},
+						},
+					},
+				}, nil
+			},
+
+			expectedDecision: authorizer.DecisionAllow,
+			expectedReason:   `unclaimable resource, identity hash not set in claiming API export: "fooExport", workspace :"someWorkspace"`,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := dynamiccontext.WithAPIDomainKey(context.Background(), dynamiccontext.APIDomainKey(tc.apidomainKey))
@@ -26,6 +26,7 @@ import (
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	"github.com/kcp-dev/logicalcluster/v2"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
@@ -34,6 +35,7 @@ import (
 	"k8s.io/klog/v2"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/client"
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
 	virtualapiexportauth "github.com/kcp-dev/kcp/pkg/virtual/apiexport/authorizer"
@@ -89,6 +91,7 @@ func BuildVirtualWorkspace(
 				kcpClusterClient,
 				wildcardKcpInformers.Apis().V1alpha1().APIResourceSchemas(),
 				wildcardKcpInformers.Apis().V1alpha1().APIExports(),
+				wildcardKcpInformers.Apis().V1alpha1().APIBindings(),
 				func(apiResourceSchema *apisv1alpha1.APIResourceSchema, version string, identityHash string, optionalLabelRequirements labels.Requirements) (apidefinition.APIDefinition, error) {
 					ctx, cancelFn := context.WithCancel(context.Background())
 
@@ -134,6 +137,7 @@ func BuildVirtualWorkspace(
 				for name, informer := range map[string]cache.SharedIndexInformer{
 					"apiresourceschemas": wildcardKcpInformers.Apis().V1alpha1().APIResourceSchemas().Informer(),
 					"apiexports":         wildcardKcpInformers.Apis().V1alpha1().APIExports().Informer(),
+					"apibindings":        wildcardKcpInformers.Apis().V1alpha1().APIBindings().Informer(),
 				} {
 					if !cache.WaitForNamedCacheSync(name, hookContext.StopCh, informer.HasSynced) {
 						klog.Errorf("informer not synced")
@@ -150,6 +154,10 @@ func BuildVirtualWorkspace(
 			return apiReconciler, nil
 		},
 		Authorizer: newAuthorizer(kubeClusterClient, deepSARClient, wildcardKcpInformers),
+
+		AccessPolicy: func(ctx context.Context) (*apisv1alpha1.VirtualWorkspaceAccess, error) {
+			return resolveAccessPolicy(ctx, wildcardKcpInformers.Apis().V1alpha1().APIExports().Informer().GetIndexer())
+		},
 	}
 
 	return []rootapiserver.NamedVirtualWorkspace{
@@ -211,6 +219,25 @@ func digestUrl(urlPath, rootPathPrefix string) (
 	return genericapirequest.Cluster{Name: clusterName, Wildcard: clusterName == logicalcluster.Wildcard}, dynamiccontext.APIDomainKey(key), strings.TrimSuffix(urlPath, realPath), true
 }
 
+// resolveAccessPolicy returns the virtual workspace access policy of the APIExport of the request.
+func resolveAccessPolicy(ctx context.Context, apiExportIndexer cache.Indexer) (*apisv1alpha1.VirtualWorkspaceAccess, error) {
+	apiDomainKey := dynamiccontext.APIDomainKeyFrom(ctx)
+	parts := strings.SplitN(string(apiDomainKey), "/", 2)
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("invalid API domain key: %q", apiDomainKey)
+	}
+	apiExportClusterName, apiExportName := logicalcluster.New(parts[0]), parts[1]
+
+	obj, exists, err := apiExportIndexer.GetByKey(client.ToClusterAwareKey(apiExportClusterName, apiExportName))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiexports"), apiExportName)
+	}
+	return obj.(*apisv1alpha1.APIExport).Spec.VirtualWorkspaceAccess, nil
+}
+
 func newAuthorizer(kubeClusterClient, deepSARClient kcpkubernetesclientset.ClusterInterface, kcpinformers kcpinformers.SharedInformerFactory) authorizer.Authorizer {
 	maximalPermissionAuth := virtualapiexportauth.NewMaximalPermissionAuthorizer(deepSARClient, kcpinformers.Apis().V1alpha1().APIExports())
 	return virtualapiexportauth.NewAPIExportsContentAuthorizer(maximalPermissionAuth, kubeClusterClient)
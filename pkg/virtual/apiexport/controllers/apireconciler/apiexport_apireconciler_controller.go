@@ -19,6 +19,7 @@ package apireconciler
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
 	"github.com/kcp-dev/logicalcluster/v2"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
@@ -40,6 +42,7 @@ import (
 	apisv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/memory"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
 	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
 )
@@ -56,6 +59,7 @@ func NewAPIReconciler(
 	kcpClusterClient kcpclientset.ClusterInterface,
 	apiResourceSchemaInformer apisv1alpha1informers.APIResourceSchemaClusterInformer,
 	apiExportInformer apisv1alpha1informers.APIExportClusterInformer,
+	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
 	createAPIDefinition CreateAPIDefinitionFunc,
 	createAPIBindingAPIDefinition func(ctx context.Context, clusterName logicalcluster.Name, apiExportName string) (apidefinition.APIDefinition, error),
 ) (*APIReconciler, error) {
@@ -70,12 +74,19 @@ func NewAPIReconciler(
 		apiExportLister:  apiExportInformer.Lister(),
 		apiExportIndexer: apiExportInformer.Informer().GetIndexer(),
 
+		getAPIBindingsForAPIExport: func(clusterName logicalcluster.Name, name string) ([]interface{}, error) {
+			clusterPathAndName := indexers.ClusterPathAndAPIExportName(clusterName.String(), name)
+			return apiBindingInformer.Informer().GetIndexer().ByIndex(indexers.APIBindingsByAPIExport, clusterPathAndName)
+		},
+
 		queue: queue,
 
 		createAPIDefinition:           createAPIDefinition,
 		createAPIBindingAPIDefinition: createAPIBindingAPIDefinition,
 
-		apiSets: map[dynamiccontext.APIDomainKey]apidefinition.APIDefinitionSet{},
+		apiSets:  map[dynamiccontext.APIDomainKey]apidefinition.APIDefinitionSet{},
+		evicted:  map[dynamiccontext.APIDomainKey]bool{},
+		lastUsed: memory.NewIdleTracker[dynamiccontext.APIDomainKey](),
 	}
 
 	indexers.AddIfNotPresentOrDie(
@@ -85,6 +96,13 @@ func NewAPIReconciler(
 		},
 	)
 
+	indexers.AddIfNotPresentOrDie(
+		apiBindingInformer.Informer().GetIndexer(),
+		cache.Indexers{
+			indexers.APIBindingsByAPIExport: indexers.IndexAPIBindingByAPIExport,
+		},
+	)
+
 	logger := logging.WithReconciler(klog.Background(), ControllerName)
 
 	apiExportInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -108,10 +126,33 @@ func NewAPIReconciler(
 		},
 	})
 
+	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueAPIBinding(obj, logger)
+		},
+		UpdateFunc: func(oldObj, obj interface{}) {
+			old, ok := oldObj.(*apisv1alpha1.APIBinding)
+			if !ok {
+				return
+			}
+			binding, ok := obj.(*apisv1alpha1.APIBinding)
+			if !ok {
+				return
+			}
+			// only the accepted permission claims matter
+			if !equality.Semantic.DeepEqual(old.Spec.PermissionClaims, binding.Spec.PermissionClaims) {
+				c.enqueueAPIBinding(obj, logger)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueAPIBinding(obj, logger)
+		},
+	})
+
 	return c, nil
 }
 
-// APIReconciler is a controller watching APIExports and APIResourceSchemas, and updates the
+// APIReconciler is a controller watching APIExports, APIResourceSchemas and APIBindings, and updates the
 // API definitions driving the virtual workspace.
 type APIReconciler struct {
 	kcpClusterClient kcpclientset.ClusterInterface
@@ -122,13 +163,22 @@ type APIReconciler struct {
 	apiExportLister  apisv1alpha1listers.APIExportClusterLister
 	apiExportIndexer cache.Indexer
 
+	getAPIBindingsForAPIExport func(clusterName logicalcluster.Name, name string) ([]interface{}, error)
+
 	queue workqueue.RateLimitingInterface
 
 	createAPIDefinition           CreateAPIDefinitionFunc
 	createAPIBindingAPIDefinition func(ctx context.Context, clusterName logicalcluster.Name, apiExportName string) (apidefinition.APIDefinition, error)
 
-	mutex   sync.RWMutex // protects the map, not the values!
+	// reconcileLock serializes the reconciliation of the worker with on-demand rebuilds and evictions.
+	reconcileLock sync.Mutex
+
+	mutex   sync.RWMutex // protects the maps, not the values!
 	apiSets map[dynamiccontext.APIDomainKey]apidefinition.APIDefinitionSet
+	// evicted holds the API domains whose definitions were evicted under memory pressure. They are
+	// rebuilt on the next request.
+	evicted  map[dynamiccontext.APIDomainKey]bool
+	lastUsed *memory.IdleTracker[dynamiccontext.APIDomainKey]
 }
 
 func (c *APIReconciler) enqueueAPIResourceSchema(obj interface{}, logger logr.Logger) {
@@ -170,6 +220,25 @@ func (c *APIReconciler) enqueueAPIExport(obj interface{}, logger logr.Logger) {
 	c.queue.Add(key)
 }
 
+// enqueueAPIBinding enqueues the APIExport an APIBinding points to, as its accepted permission claims
+// determine the claimed resources served.
+func (c *APIReconciler) enqueueAPIBinding(obj interface{}, logger logr.Logger) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	binding, ok := obj.(*apisv1alpha1.APIBinding)
+	if !ok {
+		return
+	}
+	if binding.Spec.Reference.Workspace == nil || len(binding.Spec.PermissionClaims) == 0 {
+		return
+	}
+
+	key := kcpcache.ToClusterAwareKey(binding.Spec.Reference.Workspace.Path, "", binding.Spec.Reference.Workspace.ExportName)
+	logging.WithQueueKey(logging.WithObject(logger, binding), key).V(2).Info("queueing APIExport because of APIBinding")
+	c.queue.Add(key)
+}
+
 func (c *APIReconciler) startWorker(ctx context.Context) {
 	for c.processNextWorkItem(ctx) {
 	}
@@ -186,6 +255,9 @@ func (c *APIReconciler) Start(ctx context.Context) {
 
 	go wait.Until(func() { c.startWorker(ctx) }, time.Second, ctx.Done())
 
+	memory.Register("virtual-apiexport-api-definitions", c.evictIdle)
+	defer memory.Unregister("virtual-apiexport-api-definitions")
+
 	// stop all watches if the controller is stopped
 	defer func() {
 		c.mutex.Lock()
@@ -246,6 +318,20 @@ func (c *APIReconciler) process(ctx context.Context, key string) error {
 		return nil // nothing we can do here
 	}
 
+	c.reconcileLock.Lock()
+	defer c.reconcileLock.Unlock()
+
+	c.mutex.Lock()
+	evicted := c.evicted[apiDomainKey]
+	if apiExport == nil {
+		delete(c.evicted, apiDomainKey)
+	}
+	c.mutex.Unlock()
+	if evicted && apiExport != nil {
+		logger.V(4).Info("skipping evicted APIs, they are rebuilt on the next request")
+		return nil
+	}
+
 	if apiExport != nil {
 		logger = logging.WithObject(logger, apiExport)
 	}
@@ -254,10 +340,66 @@ func (c *APIReconciler) process(ctx context.Context, key string) error {
 	return c.reconcile(ctx, apiExport, apiDomainKey)
 }
 
-func (c *APIReconciler) GetAPIDefinitionSet(_ context.Context, key dynamiccontext.APIDomainKey) (apidefinition.APIDefinitionSet, bool, error) {
+func (c *APIReconciler) GetAPIDefinitionSet(ctx context.Context, key dynamiccontext.APIDomainKey) (apidefinition.APIDefinitionSet, bool, error) {
 	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	apiSet, ok := c.apiSets[key]
+	evicted := c.evicted[key]
+	c.mutex.RUnlock()
+
+	if !ok && evicted {
+		var err error
+		if apiSet, ok, err = c.rebuild(ctx, key); err != nil {
+			return nil, false, err
+		}
+	}
+	if ok {
+		c.lastUsed.Touch(key)
+	}
+	return apiSet, ok, nil
+}
+
+// rebuild reconciles the evicted API domain again, and returns its definitions.
+func (c *APIReconciler) rebuild(ctx context.Context, key dynamiccontext.APIDomainKey) (apidefinition.APIDefinitionSet, bool, error) {
+	clusterName, apiExportName, found := strings.Cut(string(key), "/")
+	if !found {
+		return nil, false, nil
+	}
+
+	c.mutex.Lock()
+	delete(c.evicted, key)
+	c.mutex.Unlock()
+
+	klog.FromContext(ctx).V(2).Info("rebuilding evicted APIs", "apiDomainKey", key)
+	if err := c.process(ctx, kcpcache.ToClusterAwareKey(clusterName, "", apiExportName)); err != nil {
+		return nil, false, err
+	}
 
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 	apiSet, ok := c.apiSets[key]
 	return apiSet, ok, nil
 }
+
+// evictIdle tears down the definitions of the API domains not requested for at least idleFor, and
+// returns the number of API domains evicted. It is a memory.EvictFunc.
+func (c *APIReconciler) evictIdle(idleFor time.Duration, keep int) int {
+	c.reconcileLock.Lock()
+	defer c.reconcileLock.Unlock()
+
+	evicted := 0
+	for _, key := range c.lastUsed.Idle(idleFor, keep) {
+		c.mutex.Lock()
+		apiSet, found := c.apiSets[key]
+		if found {
+			delete(c.apiSets, key)
+			c.evicted[key] = true
+		}
+		c.mutex.Unlock()
+
+		if found {
+			apiSet.TearDownAll()
+			evicted++
+		}
+	}
+	return evicted
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apireconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	apisv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/memory"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
+	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
+)
+
+type fakeAPIDefinition struct {
+	apidefinition.APIDefinition
+	tornDown bool
+}
+
+func (d *fakeAPIDefinition) TearDown() {
+	d.tornDown = true
+}
+
+func TestEvictIdle(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "widgets",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash"},
+	}
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(export))
+	key, err := kcpcache.MetaClusterNamespaceKeyFunc(export)
+	require.NoError(t, err)
+	apiDomainKey := dynamiccontext.APIDomainKey("root:org/widgets")
+
+	var created []*fakeAPIDefinition
+	c := &APIReconciler{
+		apiExportLister: apisv1alpha1listers.NewAPIExportClusterLister(indexer),
+		getAPIBindingsForAPIExport: func(clusterName logicalcluster.Name, name string) ([]interface{}, error) {
+			return nil, nil
+		},
+		createAPIBindingAPIDefinition: func(ctx context.Context, clusterName logicalcluster.Name, apiExportName string) (apidefinition.APIDefinition, error) {
+			d := &fakeAPIDefinition{}
+			created = append(created, d)
+			return d, nil
+		},
+		apiSets:  map[dynamiccontext.APIDomainKey]apidefinition.APIDefinitionSet{},
+		evicted:  map[dynamiccontext.APIDomainKey]bool{},
+		lastUsed: memory.NewIdleTracker[dynamiccontext.APIDomainKey](),
+	}
+	ctx := context.Background()
+
+	require.NoError(t, c.process(ctx, key))
+	require.Len(t, created, 1)
+	_, found, err := c.GetAPIDefinitionSet(ctx, apiDomainKey)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	require.Equal(t, 0, c.evictIdle(time.Hour, 0), "recently used APIs must not be evicted")
+	require.Equal(t, 0, c.evictIdle(0, 1), "keep must be respected")
+	require.Equal(t, 1, c.evictIdle(0, 0))
+	require.True(t, created[0].tornDown, "evicted APIs must be torn down")
+
+	require.NoError(t, c.process(ctx, key))
+	require.Len(t, created, 1, "evicted APIs must not be rebuilt by the controller")
+
+	apiSet, found, err := c.GetAPIDefinitionSet(ctx, apiDomainKey)
+	require.NoError(t, err)
+	require.True(t, found, "evicted APIs must be rebuilt on request")
+	require.Len(t, created, 2)
+	require.Len(t, apiSet, 1)
+
+	_, found, err = c.GetAPIDefinitionSet(ctx, "root:org/unknown")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, indexer.Delete(export))
+	require.NoError(t, c.process(ctx, key))
+	require.True(t, created[1].tornDown)
+	require.Equal(t, 0, c.evictIdle(0, 0), "APIs of deleted APIExports must not be tracked anymore")
+}
@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	"github.com/kcp-dev/kcp/pkg/apis/apis"
@@ -45,7 +46,7 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiExport *apisv1alpha1.A
 
 	if apiExport == nil || apiExport.Status.IdentityHash == "" {
 		c.mutex.RLock()
-		_, found := c.apiSets[apiDomainKey]
+		oldSet, found := c.apiSets[apiDomainKey]
 		c.mutex.RUnlock()
 
 		if !found {
@@ -54,10 +55,13 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiExport *apisv1alpha1.A
 		}
 
 		// remove the APIDomain
-		c.mutex.Lock()
-		defer c.mutex.Unlock()
 		logger.V(2).Info("deleting APIs for API domain key")
+		c.mutex.Lock()
 		delete(c.apiSets, apiDomainKey)
+		c.mutex.Unlock()
+		c.lastUsed.Forget(apiDomainKey)
+
+		oldSet.TearDownAll()
 		return nil
 	}
 
@@ -77,10 +81,21 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiExport *apisv1alpha1.A
 
 	clusterName := logicalcluster.From(apiExport)
 
+	acceptedClaims, err := c.acceptedPermissionClaims(clusterName, apiExport.Name)
+	if err != nil {
+		return err
+	}
+
 	// Find schemas for claimed resources
 	claims := map[schema.GroupResource]apisv1alpha1.PermissionClaim{}
 	claimsAPIBindings := false
 	for _, pc := range apiExport.Spec.PermissionClaims {
+		// Claimed resources are only served once a consumer has accepted the claim.
+		if !acceptedClaims.Has(permissionClaimKey(pc)) {
+			logger.V(4).Info("permission claim is not accepted by any APIBinding", "claim", pc)
+			continue
+		}
+
 		// APIExport resources have priority over claimed resources
 		gr := schema.GroupResource{Group: pc.Group, Resource: pc.Resource}
 		if _, found := apiResourceSchemas[gr]; found {
@@ -162,10 +177,9 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiExport *apisv1alpha1.A
 		}
 	}
 
-	// reconcile APIs for APIResourceSchemas
+	// reconcile APIs for APIResourceSchemas. Definitions of unchanged schemas, identities and claims are
+	// taken over from the old set, so that their storage and the established watches are preserved.
 	newSet := apidefinition.APIDefinitionSet{}
-	newGVRs := []string{}
-	preservedGVR := []string{}
 	for _, apiResourceSchema := range apiResourceSchemas {
 		for _, version := range apiResourceSchema.Spec.Versions {
 			if !version.Served {
@@ -178,17 +192,6 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiExport *apisv1alpha1.A
 				Resource: apiResourceSchema.Spec.Names.Plural,
 			}
 
-			oldDef, found := oldSet[gvr]
-			if found {
-				oldDef := oldDef.(apiResourceSchemaApiDefinition)
-				if oldDef.UID == apiResourceSchema.UID && oldDef.IdentityHash == apiExport.Status.IdentityHash {
-					// this is the same schema and identity as before. no need to update.
-					newSet[gvr] = oldDef
-					preservedGVR = append(preservedGVR, gvrString(gvr))
-					continue
-				}
-			}
-
 			var labelReqs labels.Requirements
 			if c, ok := claims[gvr.GroupResource()]; ok {
 				key, label, err := permissionclaims.ToLabelKeyAndValue(clusterName, apiExport.Name, c)
@@ -207,6 +210,17 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiExport *apisv1alpha1.A
 				labelReqs = labels.Requirements{*req}
 			}
 
+			key := apiDefinitionKey{
+				UID:           apiResourceSchema.UID,
+				IdentityHash:  identities[gvr.GroupResource()],
+				LabelSelector: labels.NewSelector().Add(labelReqs...).String(),
+			}
+			if oldDef, found := oldSet[gvr]; found && oldDef.(apiResourceSchemaApiDefinition).apiDefinitionKey == key {
+				// this is the same schema, identity and claim as before. no need to update.
+				newSet[gvr] = oldDef
+				continue
+			}
+
 			logger.Info("creating API definition", "gvr", gvr, "labels", labelReqs)
 			apiDefinition, err := c.createAPIDefinition(apiResourceSchema, version.Name, identities[gvr.GroupResource()], labelReqs)
 			if err != nil {
@@ -216,59 +230,56 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiExport *apisv1alpha1.A
 			}
 
 			newSet[gvr] = apiResourceSchemaApiDefinition{
-				APIDefinition: apiDefinition,
-				UID:           apiResourceSchema.UID,
-				IdentityHash:  apiExport.Status.IdentityHash,
+				APIDefinition:    apiDefinition,
+				apiDefinitionKey: key,
 			}
-			newGVRs = append(newGVRs, gvrString(gvr))
 		}
 	}
 
 	if !claimsAPIBindings {
-		d, err := c.createAPIBindingAPIDefinition(ctx, clusterName, apiExport.Name)
-		if err != nil {
+		// the apibindings definition only depends on the APIExport, i.e. on the API domain.
+		gvr := apisv1alpha1.SchemeGroupVersion.WithResource("apibindings")
+		if oldDef, found := oldSet[gvr]; found && oldDef.(apiResourceSchemaApiDefinition).apiDefinitionKey == (apiDefinitionKey{}) {
+			newSet[gvr] = oldDef
+		} else if d, err := c.createAPIBindingAPIDefinition(ctx, clusterName, apiExport.Name); err != nil {
 			// TODO(ncdc): would be nice to expose some sort of user-visible error
 			logger.Error(err, "error creating api definition for apibindings")
-		}
-
-		gvr := apisv1alpha1.SchemeGroupVersion.WithResource("apibindings")
-		newSet[gvr] = apiResourceSchemaApiDefinition{
-			APIDefinition: d,
-		}
-		newGVRs = append(newGVRs, gvrString(gvr))
-	}
-
-	// cleanup old definitions
-	removedGVRs := []string{}
-	for gvr, oldDef := range oldSet {
-		if newDef, found := newSet[gvr]; !found || oldDef.(apiResourceSchemaApiDefinition).APIDefinition != newDef.(apiResourceSchemaApiDefinition).APIDefinition {
-			removedGVRs = append(removedGVRs, gvrString(gvr))
-			oldDef.TearDown()
+		} else {
+			newSet[gvr] = apiResourceSchemaApiDefinition{
+				APIDefinition: d,
+			}
 		}
 	}
 
-	logger.V(2).Info("updating APIs", "new", newGVRs, "preserved", preservedGVR, "removed", removedGVRs)
+	diff := apidefinition.Diff(oldSet, newSet)
+	logger.V(2).Info("updating APIs", "new", apidefinition.Strings(diff.Added), "preserved", apidefinition.Strings(diff.Preserved), "removed", apidefinition.Strings(diff.Removed))
 
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
 	c.apiSets[apiDomainKey] = newSet
+	c.mutex.Unlock()
+	if oldSet == nil {
+		// start tracking use, for the definitions to be evicted if not requested
+		c.lastUsed.Touch(apiDomainKey)
+	}
+
+	// only tear down the definitions that are not served anymore
+	diff.TearDown(oldSet)
 
 	return nil
 }
 
 type apiResourceSchemaApiDefinition struct {
 	apidefinition.APIDefinition
-
-	UID          types.UID
-	IdentityHash string
+	apiDefinitionKey
 }
 
-func gvrString(gvr schema.GroupVersionResource) string {
-	group := gvr.Group
-	if group == "" {
-		group = "core"
-	}
-	return fmt.Sprintf("%s.%s.%s", gvr.Resource, gvr.Version, group)
+// apiDefinitionKey holds everything a definition is created from besides the API domain. A definition
+// of the old set is reused if its key did not change. The zero key is the one of the apibindings
+// definition of APIExports not claiming apibindings.
+type apiDefinitionKey struct {
+	UID           types.UID
+	IdentityHash  string
+	LabelSelector string
 }
 
 func (c *APIReconciler) getSchemasFromAPIExport(apiExport *apisv1alpha1.APIExport) (map[schema.GroupResource]*apisv1alpha1.APIResourceSchema, error) {
@@ -287,3 +298,28 @@ func (c *APIReconciler) getSchemasFromAPIExport(apiExport *apisv1alpha1.APIExpor
 
 	return apiResourceSchemas, nil
 }
+
+// acceptedPermissionClaims returns the keys of the permission claims of the given APIExport that at least
+// one of its APIBindings has accepted.
+func (c *APIReconciler) acceptedPermissionClaims(clusterName logicalcluster.Name, apiExportName string) (sets.String, error) {
+	objs, err := c.getAPIBindingsForAPIExport(clusterName, apiExportName)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := sets.NewString()
+	for _, obj := range objs {
+		binding := obj.(*apisv1alpha1.APIBinding)
+		for _, pc := range binding.Spec.PermissionClaims {
+			if pc.State == apisv1alpha1.ClaimAccepted {
+				accepted.Insert(permissionClaimKey(pc.PermissionClaim))
+			}
+		}
+	}
+	return accepted, nil
+}
+
+// permissionClaimKey identifies the resource claimed by a permission claim.
+func permissionClaimKey(pc apisv1alpha1.PermissionClaim) string {
+	return pc.Group + "/" + pc.Resource + "/" + pc.IdentityHash
+}
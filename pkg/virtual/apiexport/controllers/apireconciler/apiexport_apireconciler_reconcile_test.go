@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apireconciler
+
+import (
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestAcceptedPermissionClaims(t *testing.T) {
+	configmaps := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}}
+	secrets := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"}}
+	widgets := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Group: "example.io", Resource: "widgets"}, IdentityHash: "id"}
+
+	newBinding := func(claims ...apisv1alpha1.AcceptablePermissionClaim) *apisv1alpha1.APIBinding {
+		return &apisv1alpha1.APIBinding{Spec: apisv1alpha1.APIBindingSpec{PermissionClaims: claims}}
+	}
+
+	c := &APIReconciler{
+		getAPIBindingsForAPIExport: func(clusterName logicalcluster.Name, name string) ([]interface{}, error) {
+			return []interface{}{
+				newBinding(
+					apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: configmaps, State: apisv1alpha1.ClaimAccepted},
+					apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: secrets, State: apisv1alpha1.ClaimRejected},
+				),
+				newBinding(
+					apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: widgets, State: apisv1alpha1.ClaimAccepted},
+				),
+				newBinding(),
+			}, nil
+		},
+	}
+
+	accepted, err := c.acceptedPermissionClaims(logicalcluster.New("root:org:provider"), "export")
+	require.NoError(t, err)
+	require.True(t, accepted.Has(permissionClaimKey(configmaps)))
+	require.False(t, accepted.Has(permissionClaimKey(secrets)))
+	require.True(t, accepted.Has(permissionClaimKey(widgets)))
+
+	otherIdentity := widgets
+	otherIdentity.IdentityHash = "other"
+	require.False(t, accepted.Has(permissionClaimKey(otherIdentity)))
+}
@@ -54,3 +54,25 @@ func (a *virtualWorkspaceAuthorizer) Authorize(ctx context.Context, attrs author
 	// ResolveRootPath method of one of the virtual workspaces.
 	return authorizer.DecisionNoOpinion, "", fmt.Errorf("virtual Workspace %q not found", virtualWorkspaceName)
 }
+
+// NewNonVirtualWorkspaceAuthorizer returns an authorizer that delegates requests which are not resolved to a
+// virtual workspace, e.g. /metrics or /healthz, to the given authorizer, and has no opinion about all others.
+func NewNonVirtualWorkspaceAuthorizer(delegate authorizer.Authorizer) authorizer.Authorizer {
+	return &nonVirtualWorkspaceAuthorizer{
+		delegate: delegate,
+	}
+}
+
+var _ authorizer.Authorizer = (*nonVirtualWorkspaceAuthorizer)(nil)
+
+type nonVirtualWorkspaceAuthorizer struct {
+	delegate authorizer.Authorizer
+}
+
+func (a *nonVirtualWorkspaceAuthorizer) Authorize(ctx context.Context, attrs authorizer.Attributes) (authorized authorizer.Decision, reason string, err error) {
+	if virtualWorkspaceName, _ := virtualcontext.VirtualWorkspaceNameFrom(ctx); virtualWorkspaceName != "" {
+		return authorizer.DecisionNoOpinion, "Path resolved to a virtual workspace", nil
+	}
+
+	return a.delegate.Authorize(ctx, attrs)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidefinition
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// APIDefinitionSetDiff describes how an APIDefinitionSet differs from the previous set of the same API domain.
+type APIDefinitionSetDiff struct {
+	// Added are the GVRs with a definition that was not in the previous set.
+	Added []schema.GroupVersionResource
+	// Preserved are the GVRs whose definition was taken over from the previous set. Their storage
+	// and established watches stay untouched.
+	Preserved []schema.GroupVersionResource
+	// Removed are the GVRs whose definition of the previous set is not in the new set anymore,
+	// either because the GVR is not served anymore, or because its definition got replaced.
+	Removed []schema.GroupVersionResource
+}
+
+// Diff compares the definitions of the two sets per GVR. Definitions are compared with ==, i.e. a
+// definition is preserved if the new set holds the very same definition as the old one.
+func Diff(oldSet, newSet APIDefinitionSet) APIDefinitionSetDiff {
+	var diff APIDefinitionSetDiff
+	for gvr, newDef := range newSet {
+		if oldDef, found := oldSet[gvr]; found && oldDef == newDef {
+			diff.Preserved = append(diff.Preserved, gvr)
+		} else {
+			diff.Added = append(diff.Added, gvr)
+		}
+	}
+	for gvr, oldDef := range oldSet {
+		if newDef, found := newSet[gvr]; !found || oldDef != newDef {
+			diff.Removed = append(diff.Removed, gvr)
+		}
+	}
+	sortGVRs(diff.Added)
+	sortGVRs(diff.Preserved)
+	sortGVRs(diff.Removed)
+	return diff
+}
+
+// TearDown tears down the definitions of the given set that the diff removed. Call it only after
+// the new set replaced the old one, so that no request is served by a torn down definition.
+func (d APIDefinitionSetDiff) TearDown(oldSet APIDefinitionSet) {
+	for _, gvr := range d.Removed {
+		if def := oldSet[gvr]; def != nil {
+			def.TearDown()
+		}
+	}
+}
+
+// TearDownAll tears down all definitions of the set, e.g. when its API domain goes away.
+func (s APIDefinitionSet) TearDownAll() {
+	for _, def := range s {
+		if def != nil {
+			def.TearDown()
+		}
+	}
+}
+
+// Strings returns the GVRs of the given list in <resource>.<version>.<group> notation, for logging.
+func Strings(gvrs []schema.GroupVersionResource) []string {
+	ret := make([]string, 0, len(gvrs))
+	for _, gvr := range gvrs {
+		group := gvr.Group
+		if group == "" {
+			group = "core"
+		}
+		ret = append(ret, fmt.Sprintf("%s.%s.%s", gvr.Resource, gvr.Version, group))
+	}
+	return ret
+}
+
+func sortGVRs(gvrs []schema.GroupVersionResource) {
+	sort.Slice(gvrs, func(i, j int) bool {
+		return gvrs[i].String() < gvrs[j].String()
+	})
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidefinition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type fakeAPIDefinition struct {
+	APIDefinition
+	tornDown bool
+}
+
+func (d *fakeAPIDefinition) TearDown() {
+	d.tornDown = true
+}
+
+func TestDiff(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.io", Version: "v1", Resource: "widgets"}
+	gadgets := schema.GroupVersionResource{Group: "example.io", Version: "v1", Resource: "gadgets"}
+	gizmos := schema.GroupVersionResource{Group: "example.io", Version: "v1", Resource: "gizmos"}
+	configmaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	oldWidgets, oldGadgets, oldGizmos := &fakeAPIDefinition{}, &fakeAPIDefinition{}, &fakeAPIDefinition{}
+	newGadgets, newConfigMaps := &fakeAPIDefinition{}, &fakeAPIDefinition{}
+
+	oldSet := APIDefinitionSet{widgets: oldWidgets, gadgets: oldGadgets, gizmos: oldGizmos}
+	newSet := APIDefinitionSet{widgets: oldWidgets, gadgets: newGadgets, configmaps: newConfigMaps}
+
+	diff := Diff(oldSet, newSet)
+	require.Equal(t, []schema.GroupVersionResource{configmaps, gadgets}, diff.Added)
+	require.Equal(t, []schema.GroupVersionResource{widgets}, diff.Preserved)
+	require.Equal(t, []schema.GroupVersionResource{gadgets, gizmos}, diff.Removed)
+	require.Equal(t, []string{"configmaps.v1.core", "gadgets.v1.example.io"}, Strings(diff.Added))
+
+	diff.TearDown(oldSet)
+	require.False(t, oldWidgets.tornDown, "preserved definition must not be torn down")
+	require.True(t, oldGadgets.tornDown, "replaced definition must be torn down")
+	require.True(t, oldGizmos.tornDown, "removed definition must be torn down")
+	require.False(t, newGadgets.tornDown)
+	require.False(t, newConfigMaps.tornDown)
+
+	newSet.TearDownAll()
+	require.True(t, oldWidgets.tornDown)
+	require.True(t, newGadgets.tornDown)
+	require.True(t, newConfigMaps.tornDown)
+}
+
+func TestDiffWithoutOldSet(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.io", Version: "v1", Resource: "widgets"}
+	diff := Diff(nil, APIDefinitionSet{widgets: &fakeAPIDefinition{}})
+	require.Equal(t, []schema.GroupVersionResource{widgets}, diff.Added)
+	require.Empty(t, diff.Preserved)
+	require.Empty(t, diff.Removed)
+}
@@ -57,6 +57,8 @@ type APIDefinition interface {
 type APIDefinitionSet map[schema.GroupVersionResource]APIDefinition
 
 // APIDefinitionSetGetter provides access to the API definitions of a API domain, based on the API domain key.
+// Errors implementing the APIStatus interface of k8s.io/apimachinery/pkg/api/errors are returned to the client
+// as they are, all other errors as internal errors.
 type APIDefinitionSetGetter interface {
 	GetAPIDefinitionSet(ctx context.Context, key dynamiccontext.APIDomainKey) (apis APIDefinitionSet, apisExist bool, err error)
 }
@@ -0,0 +1,179 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metainternalversionscheme "k8s.io/apimachinery/pkg/apis/meta/internalversion/scheme"
+	metainternalversionvalidation "k8s.io/apimachinery/pkg/apis/meta/internalversion/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+	"k8s.io/klog/v2"
+)
+
+// listChunkSize is the number of objects requested from the storage per chunk of a chunked list.
+var listChunkSize int64 = 500
+
+// isChunkedList returns whether a list request is served in chunks, i.e. whether it spans all logical
+// clusters, does not paginate itself and asks for plain JSON. Tables and partial object metadata are
+// served by the generic list handler.
+func isChunkedList(req *http.Request) bool {
+	cluster := apirequest.ClusterFrom(req.Context())
+	if cluster == nil || !cluster.Wildcard {
+		return false
+	}
+	query := req.URL.Query()
+	if limit := query.Get("limit"); limit != "" && limit != "0" {
+		return false
+	}
+	if query.Get("continue") != "" {
+		return false
+	}
+	return acceptsPlainJSON(req.Header.Get("Accept"))
+}
+
+// acceptsPlainJSON returns whether the first media range of the given Accept header is satisfied by
+// plain JSON.
+func acceptsPlainJSON(accept string) bool {
+	if accept == "" {
+		return true
+	}
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		if _, found := params["as"]; found {
+			return false
+		}
+		return mediaType == runtime.ContentTypeJSON || mediaType == "application/*" || mediaType == "*/*"
+	}
+	return false
+}
+
+// serveChunkedList serves a list from the storage in chunks of listChunkSize objects, written to the
+// response as they arrive, such that a list across many logical clusters is never held in memory as a
+// whole. The chunks are consistent, as all but the first are requested with the continue token of the
+// previous one.
+//
+// Errors after the first chunk cannot be reported anymore, as the response has started. The response
+// is aborted instead, such that clients do not mistake it for a complete list.
+func serveChunkedList(lister rest.Lister, listGVK schema.GroupVersionKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		logger := klog.FromContext(ctx)
+		if requestInfo, ok := apirequest.RequestInfoFrom(ctx); ok {
+			ctx = apirequest.WithNamespace(ctx, requestInfo.Namespace)
+		}
+		gv := listGVK.GroupVersion()
+
+		opts := metainternalversion.ListOptions{}
+		if err := metainternalversionscheme.ParameterCodec.DecodeParameters(req.URL.Query(), metav1.SchemeGroupVersion, &opts); err != nil {
+			responsewriters.ErrorNegotiated(apierrors.NewBadRequest(err.Error()), codecs, gv, w, req)
+			return
+		}
+		if errs := metainternalversionvalidation.ValidateListOptions(&opts); len(errs) > 0 {
+			responsewriters.ErrorNegotiated(apierrors.NewInvalid(schema.GroupKind{Group: metav1.GroupName, Kind: "ListOptions"}, "", errs), codecs, gv, w, req)
+			return
+		}
+		opts.Limit = listChunkSize
+
+		chunk, err := lister.List(ctx, &opts)
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, codecs, gv, w, req)
+			return
+		}
+		listMeta, err := meta.ListAccessor(chunk)
+		if err != nil {
+			responsewriters.ErrorNegotiated(apierrors.NewInternalError(err), codecs, gv, w, req)
+			return
+		}
+
+		header, err := json.Marshal(struct {
+			Kind       string          `json:"kind"`
+			APIVersion string          `json:"apiVersion"`
+			Metadata   metav1.ListMeta `json:"metadata"`
+		}{
+			Kind:       listGVK.Kind,
+			APIVersion: gv.String(),
+			Metadata:   metav1.ListMeta{ResourceVersion: listMeta.GetResourceVersion()},
+		})
+		if err != nil {
+			responsewriters.ErrorNegotiated(apierrors.NewInternalError(err), codecs, gv, w, req)
+			return
+		}
+
+		w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+		w.WriteHeader(http.StatusOK)
+		// the items are appended to the header object, without its closing brace
+		if _, err := w.Write(append(header[:len(header)-1], []byte(`,"items":[`)...)); err != nil {
+			return
+		}
+
+		first := true
+		for {
+			err := meta.EachListItem(chunk, func(obj runtime.Object) error {
+				bs, err := json.Marshal(obj)
+				if err != nil {
+					return err
+				}
+				if !first {
+					bs = append([]byte{','}, bs...)
+				}
+				first = false
+				_, err = w.Write(bs)
+				return err
+			})
+			if err != nil {
+				logger.Error(err, "failed to write chunked list")
+				panic(http.ErrAbortHandler)
+			}
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+
+			cont := listMeta.GetContinue()
+			if cont == "" {
+				break
+			}
+			opts.Continue = cont
+			// the continue token determines the resourceVersion of the following chunks
+			opts.ResourceVersion = ""
+			opts.ResourceVersionMatch = ""
+			if chunk, err = lister.List(ctx, &opts); err == nil {
+				listMeta, err = meta.ListAccessor(chunk)
+			}
+			if err != nil {
+				logger.Error(err, "failed to list chunk")
+				panic(http.ErrAbortHandler)
+			}
+		}
+
+		w.Write([]byte("]}")) //nolint:errcheck
+	}
+}
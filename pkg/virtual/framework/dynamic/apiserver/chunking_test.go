@@ -0,0 +1,172 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// chunkLister serves a list of objects in chunks, with the index of the next object as continue token.
+// It fails the failOnCall-th call, if set.
+type chunkLister struct {
+	lister
+	objects    int
+	failOnCall int
+	calls      []internalversion.ListOptions
+}
+
+func (l *chunkLister) List(ctx context.Context, options *internalversion.ListOptions) (runtime.Object, error) {
+	l.calls = append(l.calls, *options)
+	if len(l.calls) == l.failOnCall {
+		return nil, apierrors.NewResourceExpired("continue token expired")
+	}
+
+	start := 0
+	if options.Continue != "" {
+		start, _ = strconv.Atoi(options.Continue)
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetResourceVersion("42")
+	end := start + int(options.Limit)
+	if end >= l.objects {
+		end = l.objects
+	} else {
+		list.SetContinue(strconv.Itoa(end))
+	}
+	for i := start; i < end; i++ {
+		obj := unstructured.Unstructured{}
+		obj.SetAPIVersion("custom/v1")
+		obj.SetKind("CustomResource")
+		obj.SetName(fmt.Sprintf("cr-%d", i))
+		list.Items = append(list.Items, obj)
+	}
+	return list, nil
+}
+
+func TestIsChunkedList(t *testing.T) {
+	tests := map[string]struct {
+		cluster logicalcluster.Name
+		query   string
+		accept  string
+		want    bool
+	}{
+		"wildcard":                 {cluster: logicalcluster.Wildcard, want: true},
+		"wildcard, json":           {cluster: logicalcluster.Wildcard, accept: "application/json, */*", want: true},
+		"wildcard, zero limit":     {cluster: logicalcluster.Wildcard, query: "limit=0", want: true},
+		"wildcard, limit":          {cluster: logicalcluster.Wildcard, query: "limit=10"},
+		"wildcard, continue":       {cluster: logicalcluster.Wildcard, query: "continue=abc"},
+		"wildcard, table":          {cluster: logicalcluster.Wildcard, accept: "application/json;as=Table;v=v1;g=meta.k8s.io,application/json"},
+		"wildcard, protobuf":       {cluster: logicalcluster.Wildcard, accept: "application/vnd.kubernetes.protobuf, */*"},
+		"single logical cluster":   {cluster: logicalcluster.New("root:org")},
+		"wildcard, any media type": {cluster: logicalcluster.Wildcard, accept: "*/*", want: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/apis/custom/v1/customresources?"+tt.query, nil)
+			req = req.WithContext(apirequest.WithCluster(req.Context(), apirequest.Cluster{Name: tt.cluster, Wildcard: tt.cluster == logicalcluster.Wildcard}))
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			require.Equal(t, tt.want, isChunkedList(req))
+		})
+	}
+}
+
+func TestServeChunkedList(t *testing.T) {
+	listGVK := schema.GroupVersionKind{Group: "custom", Version: "v1", Kind: "CustomResourceList"}
+
+	serve := func(l *chunkLister, query string) (rec *httptest.ResponseRecorder, aborted bool) {
+		defer func() {
+			if r := recover(); r != nil {
+				require.Equal(t, http.ErrAbortHandler, r)
+				aborted = true
+			}
+		}()
+		req := httptest.NewRequest(http.MethodGet, "/apis/custom/v1/customresources?"+query, nil)
+		rec = httptest.NewRecorder()
+		serveChunkedList(l, listGVK).ServeHTTP(rec, req)
+		return rec, false
+	}
+
+	oldChunkSize := listChunkSize
+	listChunkSize = 2
+	defer func() { listChunkSize = oldChunkSize }()
+
+	for _, objects := range []int{0, 1, 2, 5} {
+		t.Run(fmt.Sprintf("%d objects", objects), func(t *testing.T) {
+			l := &chunkLister{objects: objects}
+			rec, aborted := serve(l, "resourceVersion=40&resourceVersionMatch=NotOlderThan")
+			require.False(t, aborted)
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			list := &unstructured.UnstructuredList{}
+			require.NoError(t, list.UnmarshalJSON(rec.Body.Bytes()), "invalid list: %s", rec.Body.String())
+			require.Equal(t, "custom/v1", list.GetAPIVersion())
+			require.Equal(t, "CustomResourceList", list.GetKind())
+			require.Equal(t, "42", list.GetResourceVersion())
+			require.Empty(t, list.GetContinue())
+			require.Len(t, list.Items, objects)
+			for i, item := range list.Items {
+				require.Equal(t, fmt.Sprintf("cr-%d", i), item.GetName())
+			}
+
+			require.Equal(t, "40", l.calls[0].ResourceVersion)
+			for i, call := range l.calls {
+				require.Equal(t, int64(2), call.Limit)
+				if i > 0 {
+					require.Empty(t, call.ResourceVersion, "following chunks are determined by the continue token")
+					require.Empty(t, string(call.ResourceVersionMatch))
+				}
+			}
+		})
+	}
+
+	t.Run("invalid options are reported", func(t *testing.T) {
+		l := &chunkLister{objects: 5}
+		rec, aborted := serve(l, "resourceVersionMatch=Exact")
+		require.False(t, aborted)
+		require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		require.Empty(t, l.calls)
+	})
+
+	t.Run("error on the first chunk is reported", func(t *testing.T) {
+		rec, aborted := serve(&chunkLister{objects: 5, failOnCall: 1}, "")
+		require.False(t, aborted)
+		require.Equal(t, http.StatusGone, rec.Code)
+	})
+
+	t.Run("error on a following chunk aborts the response", func(t *testing.T) {
+		_, aborted := serve(&chunkLister{objects: 5, failOnCall: 2}, "")
+		require.True(t, aborted)
+	})
+}
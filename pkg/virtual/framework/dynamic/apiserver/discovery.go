@@ -17,13 +17,12 @@ limitations under the License.
 package apiserver
 
 import (
-	"fmt"
 	"net/http"
 	"sort"
 	"strings"
 
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -79,7 +78,7 @@ func (r *versionDiscoveryHandler) ServeHTTP(w http.ResponseWriter, req *http.Req
 	apiSet, hasLocationKey, err := r.apiSetRetriever.GetAPIDefinitionSet(ctx, apiDomainKey)
 	if err != nil {
 		responsewriters.ErrorNegotiated(
-			apierrors.NewInternalError(fmt.Errorf("unable to determine API definition set: %w", err)),
+			apiDefinitionSetError(err),
 			errorCodecs, schema.GroupVersion{},
 			w, req)
 		return
@@ -121,9 +120,17 @@ func (r *versionDiscoveryHandler) ServeHTTP(w http.ResponseWriter, req *http.Req
 					Verbs:      supportedVerbs(apiDef.GetSubResourceStorage("status")),
 				})
 			}
+			if v := apiResourceSchema.Spec.Versions[i]; v.Subresources.Scale != nil {
+				apiResourcesForDiscovery = append(apiResourcesForDiscovery, metav1.APIResource{
+					Name:       apiResourceSchema.Spec.Names.Plural + "/scale",
+					Namespaced: apiResourceSchema.Spec.Scope == apiextensionsv1.NamespaceScoped,
+					Group:      autoscalingv1.GroupName,
+					Version:    "v1",
+					Kind:       "Scale",
+					Verbs:      supportedVerbs(apiDef.GetSubResourceStorage("scale")),
+				})
+			}
 		}
-
-		// TODO(david): Add scale sub-resource ???
 	}
 
 	resourceListerFunc := discovery.APIResourceListerFunc(func() []metav1.APIResource {
@@ -196,7 +203,7 @@ func (r *groupDiscoveryHandler) ServeHTTP(w http.ResponseWriter, req *http.Reque
 	apiSet, hasLocationKey, err := r.apiSetRetriever.GetAPIDefinitionSet(ctx, apiDomainKey)
 	if err != nil {
 		responsewriters.ErrorNegotiated(
-			apierrors.NewInternalError(fmt.Errorf("unable to determine API definition set: %w", err)),
+			apiDefinitionSetError(err),
 			errorCodecs, schema.GroupVersion{},
 			w, req)
 		return
@@ -262,7 +269,7 @@ func (r *rootDiscoveryHandler) ServeHTTP(w http.ResponseWriter, req *http.Reques
 	apiSet, hasLocationKey, err := r.apiSetRetriever.GetAPIDefinitionSet(ctx, apiDomainKey)
 	if err != nil {
 		responsewriters.ErrorNegotiated(
-			apierrors.NewInternalError(fmt.Errorf("unable to determine API definition set: %w", err)),
+			apiDefinitionSetError(err),
 			errorCodecs, schema.GroupVersion{},
 			w, req)
 		return
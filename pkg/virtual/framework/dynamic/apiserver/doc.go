@@ -25,6 +25,10 @@ limitations under the License.
 // - the CreateServingInfoFor method can be used by external components at any time to create an apidefs.APIDefinition and
 // add it to the apidefs.APISetRetriever that has been passed to the DynamicAPIServer
 //
+// Lists across all logical clusters without a limit are requested from the storage in chunks and streamed to the
+// client, such that they are never held in memory as a whole. Lists with a limit are paginated by the client
+// through the continue tokens of the storage.
+//
 // Parts of this package are highly inspired from k8s.io/apiextensions-apiserver/pkg/apiserver
 // https://github.com/kcp-dev/kubernetes/tree/feature-logical-clusters-1.23/staging/src/k8s.io/apiextensions-apiserver/pkg/apiserver
 package apiserver
@@ -17,6 +17,7 @@ limitations under the License.
 package apiserver
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -135,7 +136,7 @@ func (r *resourceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	apiDefs, hasLocationKey, err := r.apiSetRetriever.GetAPIDefinitionSet(ctx, locationKey)
 	if err != nil {
 		responsewriters.ErrorNegotiated(
-			apierrors.NewInternalError(fmt.Errorf("unable to determine API definition set: %w", err)),
+			apiDefinitionSetError(err),
 			errorCodecs, schema.GroupVersion{},
 			w, req)
 		return
@@ -210,7 +211,9 @@ func (r *resourceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	subresources := apiResourceVersion.Subresources
 	switch {
 	case subresource == "status" && subresources.Status != nil:
-		handlerFunc = r.serveStatus(w, req, requestInfo, apiDef, supportedTypes)
+		handlerFunc = r.serveSubresource(w, req, requestInfo, apiDef, "status", supportedTypes)
+	case subresource == "scale" && subresources.Scale != nil:
+		handlerFunc = r.serveSubresource(w, req, requestInfo, apiDef, "scale", supportedTypes)
 	case len(subresource) == 0:
 		handlerFunc = r.serveResource(w, req, requestInfo, apiDef, supportedTypes)
 	default:
@@ -238,6 +241,10 @@ func (r *resourceHandler) serveResource(w http.ResponseWriter, req *http.Request
 		}
 	case "list":
 		if listerStorage, isAble := storage.(rest.Lister); isAble {
+			if isChunkedList(req) {
+				names := apiDef.GetAPIResourceSchema().Spec.Names
+				return serveChunkedList(listerStorage, schema.GroupVersionKind{Group: requestInfo.APIGroup, Version: requestInfo.APIVersion, Kind: names.ListKind})
+			}
 			if watcherStorage, isAble := storage.(rest.Watcher); isAble {
 				forceWatch := false
 				return handlers.ListResource(listerStorage, watcherStorage, requestScope, forceWatch, r.minRequestTimeout)
@@ -280,9 +287,9 @@ func (r *resourceHandler) serveResource(w http.ResponseWriter, req *http.Request
 	return nil
 }
 
-func (r *resourceHandler) serveStatus(w http.ResponseWriter, req *http.Request, requestInfo *apirequest.RequestInfo, apiDef apidefinition.APIDefinition, supportedTypes []string) http.HandlerFunc {
-	requestScope := apiDef.GetSubResourceRequestScope("status")
-	storage := apiDef.GetSubResourceStorage("status")
+func (r *resourceHandler) serveSubresource(w http.ResponseWriter, req *http.Request, requestInfo *apirequest.RequestInfo, apiDef apidefinition.APIDefinition, subresource string, supportedTypes []string) http.HandlerFunc {
+	requestScope := apiDef.GetSubResourceRequestScope(subresource)
+	storage := apiDef.GetSubResourceStorage(subresource)
 
 	switch requestInfo.Verb {
 	case "get":
@@ -304,3 +311,14 @@ func (r *resourceHandler) serveStatus(w http.ResponseWriter, req *http.Request,
 	)
 	return nil
 }
+
+// apiDefinitionSetError returns the error to respond with when the API definitions of an API domain cannot be
+// determined. Status errors, e.g. ServiceUnavailable for an API domain that is temporarily not served, are
+// passed through.
+func apiDefinitionSetError(err error) error {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return err
+	}
+	return apierrors.NewInternalError(fmt.Errorf("unable to determine API definition set: %w", err))
+}
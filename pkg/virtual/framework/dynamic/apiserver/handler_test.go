@@ -19,6 +19,8 @@ package apiserver
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -30,6 +32,7 @@ import (
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apiextensions-apiserver/pkg/controller/openapi/builder"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -897,3 +900,14 @@ func TestBuildOpenAPIModelsForApply(t *testing.T) {
 		}
 	}
 }
+
+func TestAPIDefinitionSetError(t *testing.T) {
+	err := apiDefinitionSetError(apierrors.NewServiceUnavailable("not heartbeating"))
+	require.True(t, apierrors.IsServiceUnavailable(err))
+
+	err = apiDefinitionSetError(fmt.Errorf("wrapped: %w", apierrors.NewServiceUnavailable("not heartbeating")))
+	require.True(t, apierrors.IsServiceUnavailable(err))
+
+	err = apiDefinitionSetError(errors.New("boom"))
+	require.True(t, apierrors.IsInternalError(err))
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apiextensions-apiserver/pkg/controller/openapi/builder"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apiserver/pkg/endpoints/handlers/fieldmanager"
+	"k8s.io/apiserver/pkg/endpoints/openapi"
+	utilopenapi "k8s.io/apiserver/pkg/util/openapi"
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/memory"
+)
+
+const (
+	// openAPIModelsCacheSize bounds the number of group-versions whose OpenAPI models are kept.
+	openAPIModelsCacheSize = 4096
+	// openAPIModelsCacheTTL makes sure entries of deleted APIResourceSchemas eventually go away.
+	openAPIModelsCacheTTL = time.Hour
+)
+
+// openAPIModels are the OpenAPI derived artifacts of a single APIResourceSchema version.
+type openAPIModels struct {
+	modelsByGKV   openapi.ModelsByGKV
+	typeConverter fieldmanager.TypeConverter
+}
+
+// openAPIModelsCache caches the OpenAPI models per APIResourceSchema version. APIResourceSchema
+// specs are immutable, so an entry keyed by cluster, name and UID never has to be invalidated.
+// This keeps the rebuild of an APIDefinitionSet proportional to the group-versions that
+// actually changed, instead of recomputing the OpenAPI models of all of them.
+var openAPIModelsCache = memory.NewIdleCache[string, *openAPIModels](openAPIModelsCacheSize, openAPIModelsCacheTTL)
+
+func openAPIModelsCacheKey(apiResourceSchema *apisv1alpha1.APIResourceSchema, version string) string {
+	return fmt.Sprintf("%s|%s|%s|%s", logicalcluster.From(apiResourceSchema), apiResourceSchema.Name, apiResourceSchema.UID, version)
+}
+
+// getOrBuildOpenAPIModels returns the OpenAPI models for the given version of the schema, from the cache if possible.
+func getOrBuildOpenAPIModels(apiResourceSchema *apisv1alpha1.APIResourceSchema, apiResourceVersion *apisv1alpha1.APIResourceVersion) (*openAPIModels, error) {
+	key := openAPIModelsCacheKey(apiResourceSchema, apiResourceVersion.Name)
+	if cached, ok := openAPIModelsCache.Get(key); ok {
+		openAPIModelsRequests.WithLabelValues("hit").Inc()
+		return cached, nil
+	}
+	openAPIModelsRequests.WithLabelValues("miss").Inc()
+
+	start := time.Now()
+	defer func() {
+		openAPIModelsBuildDuration.Observe(time.Since(start).Seconds())
+	}()
+
+	s, err := buildOpenAPIV2(
+		apiResourceSchema,
+		apiResourceVersion,
+		builder.Options{
+			V2: true,
+			SkipFilterSchemaForKubectlOpenAPIV2Validation: true,
+			StripValueValidation:                          true,
+			StripNullable:                                 true,
+			AllowNonStructural:                            false})
+	if err != nil {
+		return nil, err
+	}
+
+	models := &openAPIModels{
+		typeConverter: fieldmanager.DeducedTypeConverter{},
+	}
+
+	protoModels, err := utilopenapi.ToProtoModels(s)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("error building openapi models for %s: %w", key, err))
+		protoModels = nil
+	} else {
+		models.modelsByGKV, err = openapi.GetModelsByGKV(protoModels)
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("error gathering openapi models by GKV for %s: %w", key, err))
+			models.modelsByGKV = nil
+		}
+	}
+	if protoModels != nil {
+		models.typeConverter, err = fieldmanager.NewTypeConverter(protoModels, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	openAPIModelsCache.Add(key, models)
+
+	return models, nil
+}
+
+var (
+	openAPIModelsBuildDuration = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      "virtual_workspace",
+			Name:           "openapi_models_build_duration_seconds",
+			Help:           "Duration in seconds of building the OpenAPI models of a single APIResourceSchema version.",
+			StabilityLevel: metrics.ALPHA,
+			Buckets:        metrics.ExponentialBuckets(0.001, 2, 15),
+		},
+	)
+
+	openAPIModelsRequests = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      "virtual_workspace",
+			Name:           "openapi_models_cache_requests_total",
+			Help:           "Number of OpenAPI model lookups, by whether they were served from the cache.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"result"}, // either "hit" or "miss"
+	)
+)
+
+var registerMetrics sync.Once
+
+func init() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(openAPIModelsBuildDuration)
+		legacyregistry.MustRegister(openAPIModelsRequests)
+	})
+
+	memory.Register("virtual-workspace-openapi-models", openAPIModelsCache.Evict)
+}
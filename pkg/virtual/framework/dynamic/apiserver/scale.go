@@ -0,0 +1,230 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// scaleGetter serves reads of the scale subresource of a resource on top of the storage of the main resource,
+// translating the replicas paths declared in the APIResourceSchema into an autoscaling/v1 Scale object.
+// As the main storage is used, any filtering or forwarding it applies also applies to scale.
+type scaleGetter struct {
+	getter             rest.Getter
+	specReplicasPath   string
+	statusReplicasPath string
+	labelSelectorPath  string
+}
+
+// scaleREST extends scaleGetter with updates, writing the replicas of the Scale object back into the main resource.
+type scaleREST struct {
+	*scaleGetter
+	updater rest.Updater
+}
+
+var _ rest.Getter = &scaleGetter{}
+var _ rest.Patcher = &scaleREST{}
+
+// newScaleREST returns a storage for the scale subresource described by scale. It supports the verbs
+// the main storage supports, i.e. get, and update and patch if the main storage can be updated.
+// Nil is returned if the main storage cannot get objects.
+func newScaleREST(storage rest.Storage, scale *apiextensionsv1.CustomResourceSubresourceScale) rest.Storage {
+	getter, ok := storage.(rest.Getter)
+	if !ok {
+		return nil
+	}
+	ret := &scaleGetter{
+		getter:             getter,
+		specReplicasPath:   scale.SpecReplicasPath,
+		statusReplicasPath: scale.StatusReplicasPath,
+	}
+	if scale.LabelSelectorPath != nil {
+		ret.labelSelectorPath = *scale.LabelSelectorPath
+	}
+	if updater, ok := storage.(rest.Updater); ok {
+		return &scaleREST{scaleGetter: ret, updater: updater}
+	}
+	return ret
+}
+
+// New creates a new Scale object.
+func (r *scaleGetter) New() runtime.Object {
+	return &autoscalingv1.Scale{}
+}
+
+// Destroy cleans up resources on shutdown.
+func (r *scaleGetter) Destroy() {
+	// the main storage is destroyed on its own
+}
+
+func (r *scaleGetter) Get(ctx context.Context, name string, options *metav1.GetOptions) (runtime.Object, error) {
+	obj, err := r.getter.Get(ctx, name, options)
+	if err != nil {
+		return nil, err
+	}
+	cr, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, apierrors.NewInternalError(fmt.Errorf("unexpected object type %T", obj))
+	}
+	scale, _, err := scaleFromCustomResource(cr, r.specReplicasPath, r.statusReplicasPath, r.labelSelectorPath)
+	if err != nil {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("%v", err))
+	}
+	return scale, nil
+}
+
+func (r *scaleREST) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	scaleObjInfo := &scaleUpdatedObjectInfo{
+		reqObjInfo:         objInfo,
+		specReplicasPath:   r.specReplicasPath,
+		statusReplicasPath: r.statusReplicasPath,
+		labelSelectorPath:  r.labelSelectorPath,
+	}
+
+	// subresources never allow create on update, and the validation funcs are about Scale objects,
+	// not about the custom resource passed to the main storage.
+	obj, _, err := r.updater.Update(ctx, name, scaleObjInfo, nil, nil, false, options)
+	if err != nil {
+		return nil, false, err
+	}
+	cr, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, false, apierrors.NewInternalError(fmt.Errorf("unexpected object type %T", obj))
+	}
+	scale, _, err := scaleFromCustomResource(cr, r.specReplicasPath, r.statusReplicasPath, r.labelSelectorPath)
+	if err != nil {
+		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("%v", err))
+	}
+	return scale, false, nil
+}
+
+// scaleUpdatedObjectInfo transforms the existing custom resource into a Scale object, applies the requested
+// update to it, and writes the resulting replicas back into the custom resource.
+type scaleUpdatedObjectInfo struct {
+	reqObjInfo         rest.UpdatedObjectInfo
+	specReplicasPath   string
+	statusReplicasPath string
+	labelSelectorPath  string
+}
+
+func (i *scaleUpdatedObjectInfo) Preconditions() *metav1.Preconditions {
+	return i.reqObjInfo.Preconditions()
+}
+
+func (i *scaleUpdatedObjectInfo) UpdatedObject(ctx context.Context, oldObj runtime.Object) (runtime.Object, error) {
+	cr, ok := oldObj.DeepCopyObject().(*unstructured.Unstructured)
+	if !ok {
+		return nil, apierrors.NewInternalError(fmt.Errorf("unexpected object type %T", oldObj))
+	}
+
+	oldScale, _, err := scaleFromCustomResource(cr, i.specReplicasPath, i.statusReplicasPath, i.labelSelectorPath)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := i.reqObjInfo.UpdatedObject(ctx, oldScale)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, apierrors.NewBadRequest("nil update passed to Scale")
+	}
+	scale, ok := obj.(*autoscalingv1.Scale)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("wrong object passed to Scale update: %v", obj))
+	}
+
+	if scale.Spec.Replicas < 0 {
+		return nil, apierrors.NewInvalid(
+			autoscalingv1.SchemeGroupVersion.WithKind("Scale").GroupKind(),
+			scale.Name,
+			field.ErrorList{field.Invalid(field.NewPath("spec", "replicas"), scale.Spec.Replicas, "must be greater than or equal to 0")},
+		)
+	}
+
+	if err := unstructured.SetNestedField(cr.Object, int64(scale.Spec.Replicas), splitReplicasPath(i.specReplicasPath)...); err != nil {
+		return nil, err
+	}
+	if len(scale.ResourceVersion) != 0 {
+		// the resourceVersion of the Scale object is the one of the custom resource, keep it for optimistic concurrency
+		cr.SetResourceVersion(scale.ResourceVersion)
+	}
+	return cr, nil
+}
+
+// scaleFromCustomResource returns the Scale object for the given custom resource, and whether the spec replicas
+// were found at specReplicasPath.
+func scaleFromCustomResource(cr *unstructured.Unstructured, specReplicasPath, statusReplicasPath, labelSelectorPath string) (*autoscalingv1.Scale, bool, error) {
+	specReplicas, foundSpecReplicas, err := unstructured.NestedInt64(cr.UnstructuredContent(), splitReplicasPath(specReplicasPath)...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	statusReplicas, _, err := unstructured.NestedInt64(cr.UnstructuredContent(), splitReplicasPath(statusReplicasPath)...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var labelSelector string
+	if len(labelSelectorPath) > 0 {
+		labelSelector, _, err = unstructured.NestedString(cr.UnstructuredContent(), splitReplicasPath(labelSelectorPath)...)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	scale := &autoscalingv1.Scale{
+		// populate apiVersion and kind so conversion recognizes we are already in the desired GVK
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: autoscalingv1.SchemeGroupVersion.String(),
+			Kind:       "Scale",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              cr.GetName(),
+			Namespace:         cr.GetNamespace(),
+			UID:               cr.GetUID(),
+			ResourceVersion:   cr.GetResourceVersion(),
+			CreationTimestamp: cr.GetCreationTimestamp(),
+			Annotations:       cr.GetAnnotations(),
+		},
+		Spec: autoscalingv1.ScaleSpec{
+			Replicas: int32(specReplicas),
+		},
+		Status: autoscalingv1.ScaleStatus{
+			Replicas: int32(statusReplicas),
+			Selector: labelSelector,
+		},
+	}
+
+	return scale, foundSpecReplicas, nil
+}
+
+// splitReplicasPath turns a JSON path like .spec.replicas into its fields.
+func splitReplicasPath(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "."), ".")
+}
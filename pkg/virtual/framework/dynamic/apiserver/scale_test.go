@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+func newScalableResource(replicas, statusReplicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata": map[string]interface{}{
+			"name":            "foo",
+			"namespace":       "default",
+			"resourceVersion": "42",
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+		},
+		"status": map[string]interface{}{
+			"replicas": statusReplicas,
+			"selector": "app=foo",
+		},
+	}}
+}
+
+func TestScaleFromCustomResource(t *testing.T) {
+	tests := map[string]struct {
+		obj                *unstructured.Unstructured
+		labelSelectorPath  string
+		wantSpecReplicas   int32
+		wantStatusReplicas int32
+		wantSelector       string
+		wantFoundSpec      bool
+		wantErr            bool
+	}{
+		"replicas and selector": {
+			obj:                newScalableResource(3, 2),
+			labelSelectorPath:  ".status.selector",
+			wantSpecReplicas:   3,
+			wantStatusReplicas: 2,
+			wantSelector:       "app=foo",
+			wantFoundSpec:      true,
+		},
+		"no label selector path": {
+			obj:                newScalableResource(3, 2),
+			wantSpecReplicas:   3,
+			wantStatusReplicas: 2,
+			wantFoundSpec:      true,
+		},
+		"missing spec replicas": {
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+			}},
+		},
+		"spec replicas of wrong type": {
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+				"spec":     map[string]interface{}{"replicas": "three"},
+			}},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			scale, found, err := scaleFromCustomResource(tt.obj, ".spec.replicas", ".status.replicas", tt.labelSelectorPath)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantFoundSpec, found)
+			require.Equal(t, tt.wantSpecReplicas, scale.Spec.Replicas)
+			require.Equal(t, tt.wantStatusReplicas, scale.Status.Replicas)
+			require.Equal(t, tt.wantSelector, scale.Status.Selector)
+			require.Equal(t, tt.obj.GetName(), scale.Name)
+			require.Equal(t, "Scale", scale.Kind)
+		})
+	}
+}
+
+func TestScaleUpdatedObjectInfo(t *testing.T) {
+	tests := map[string]struct {
+		replicas            int32
+		resourceVersion     string
+		wantReplicas        int64
+		wantResourceVersion string
+		wantInvalid         bool
+	}{
+		"scale up": {
+			replicas:            5,
+			wantReplicas:        5,
+			wantResourceVersion: "42",
+		},
+		"scale to zero with resource version": {
+			replicas:            0,
+			resourceVersion:     "41",
+			wantReplicas:        0,
+			wantResourceVersion: "41",
+		},
+		"negative replicas": {
+			replicas:    -1,
+			wantInvalid: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			old := newScalableResource(3, 2)
+			scale, _, err := scaleFromCustomResource(old, ".spec.replicas", ".status.replicas", "")
+			require.NoError(t, err)
+			scale.Spec.Replicas = tt.replicas
+			scale.ResourceVersion = tt.resourceVersion
+
+			objInfo := &scaleUpdatedObjectInfo{
+				reqObjInfo:         rest.DefaultUpdatedObjectInfo(scale),
+				specReplicasPath:   ".spec.replicas",
+				statusReplicasPath: ".status.replicas",
+			}
+			obj, err := objInfo.UpdatedObject(context.Background(), old)
+			if tt.wantInvalid {
+				require.True(t, apierrors.IsInvalid(err), "expected invalid error, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+
+			updated, ok := obj.(*unstructured.Unstructured)
+			require.True(t, ok, "expected unstructured, got %T", obj)
+			replicas, _, err := unstructured.NestedInt64(updated.Object, "spec", "replicas")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantReplicas, replicas)
+			require.Equal(t, tt.wantResourceVersion, updated.GetResourceVersion())
+
+			replicas, _, err = unstructured.NestedInt64(old.Object, "spec", "replicas")
+			require.NoError(t, err)
+			require.Equal(t, int64(3), replicas, "old object must not be mutated")
+		})
+	}
+}
+
+func TestScaleUpdatedObjectInfoWrongType(t *testing.T) {
+	objInfo := &scaleUpdatedObjectInfo{
+		reqObjInfo:         rest.DefaultUpdatedObjectInfo(newScalableResource(1, 1)),
+		specReplicasPath:   ".spec.replicas",
+		statusReplicasPath: ".status.replicas",
+	}
+	_, err := objInfo.UpdatedObject(context.Background(), newScalableResource(3, 2))
+	require.True(t, apierrors.IsBadRequest(err), "expected bad request, got %v", err)
+}
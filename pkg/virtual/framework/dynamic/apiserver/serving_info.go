@@ -21,6 +21,7 @@ import (
 
 	"github.com/kcp-dev/logicalcluster/v2"
 
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	apiextensionsinternal "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
@@ -36,14 +37,13 @@ import (
 	"k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apiserver/pkg/endpoints/handlers"
-	"k8s.io/apiserver/pkg/endpoints/handlers/fieldmanager"
-	"k8s.io/apiserver/pkg/endpoints/openapi"
 	"k8s.io/apiserver/pkg/features"
 	"k8s.io/apiserver/pkg/registry/rest"
 	genericapiserver "k8s.io/apiserver/pkg/server"
-	utilopenapi "k8s.io/apiserver/pkg/util/openapi"
+	scaleclient "k8s.io/client-go/scale"
 	"k8s.io/klog/v2"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 	"k8s.io/kube-openapi/pkg/validation/strfmt"
@@ -97,39 +97,11 @@ func CreateServingInfoFor(genericConfig genericapiserver.CompletedConfig, apiRes
 		return nil, fmt.Errorf("the server could not properly serve the CR schema") // validation should avoid this
 	}
 
-	s, err := buildOpenAPIV2(
-		apiResourceSchema,
-		apiResourceVersion,
-		builder.Options{
-			V2: true,
-			SkipFilterSchemaForKubectlOpenAPIV2Validation: true,
-			StripValueValidation:                          true,
-			StripNullable:                                 true,
-			AllowNonStructural:                            false})
+	models, err := getOrBuildOpenAPIModels(apiResourceSchema, apiResourceVersion)
 	if err != nil {
 		return nil, err
 	}
-
-	var modelsByGKV openapi.ModelsByGKV
-
-	openAPIModels, err := utilopenapi.ToProtoModels(s)
-	if err != nil {
-		utilruntime.HandleError(fmt.Errorf("error building openapi models for %s: %w", gvk.String(), err))
-		openAPIModels = nil
-	} else {
-		modelsByGKV, err = openapi.GetModelsByGKV(openAPIModels)
-		if err != nil {
-			utilruntime.HandleError(fmt.Errorf("error gathering openapi models by GKV for %s: %w", gvk.String(), err))
-			modelsByGKV = nil
-		}
-	}
-	var typeConverter fieldmanager.TypeConverter = fieldmanager.DeducedTypeConverter{}
-	if openAPIModels != nil {
-		typeConverter, err = fieldmanager.NewTypeConverter(openAPIModels, false)
-		if err != nil {
-			return nil, err
-		}
-	}
+	modelsByGKV, typeConverter := models.modelsByGKV, models.typeConverter
 
 	safeConverter, unsafeConverter := &nopConverter{}, &nopConverter{}
 	if err != nil {
@@ -180,6 +152,10 @@ func CreateServingInfoFor(genericConfig genericapiserver.CompletedConfig, apiRes
 		subResourcesValidators["status"] = statusValidator
 	}
 
+	if scale := apiResourceVersion.Subresources.Scale; scale != nil {
+		equivalentResourceRegistry.RegisterKindFor(gvr, "scale", autoscalingv1.SchemeGroupVersion.WithKind("Scale"))
+	}
+
 	table, err := tableconvertor.New(apiResourceVersion.AdditionalPrinterColumns)
 	if err != nil {
 		klog.V(2).Infof("The CRD for %s|%s has an invalid printer specification, falling back to default printing: %v", logicalcluster.From(apiResourceSchema), gvk.String(), err)
@@ -292,12 +268,37 @@ func CreateServingInfoFor(genericConfig genericapiserver.CompletedConfig, apiRes
 		}
 	}
 
+	var scaleScope handlers.RequestScope
+	var scaleStorage rest.Storage
+	if scale := apiResourceVersion.Subresources.Scale; scale != nil {
+		// rest providers may bring their own scale storage, otherwise it is served on top of the main storage
+		scaleStorage = subresourceStorages["scale"]
+		if scaleStorage == nil {
+			scaleStorage = newScaleREST(storage, scale)
+		}
+
+		// shallow copy
+		scaleScope = *requestScope
+		scaleConverter := scaleclient.NewScaleConverter()
+		scaleScope.Subresource = "scale"
+		scaleScope.Serializer = serializer.NewCodecFactory(scaleConverter.Scheme())
+		scaleScope.Kind = autoscalingv1.SchemeGroupVersion.WithKind("Scale")
+		scaleScope.Namer = handlers.ContextBasedNaming{
+			Namer:         runtime.Namer(meta.NewAccessor()),
+			ClusterScoped: clusterScoped,
+		}
+		// server-side apply is not supported for scale, the replicas are owned by the main resource
+		scaleScope.FieldManager = nil
+	}
+
 	ret := &servingInfo{
 		apiResourceSchema:  apiResourceSchema,
 		storage:            storage,
 		statusStorage:      statusStorage,
+		scaleStorage:       scaleStorage,
 		requestScope:       requestScope,
 		statusRequestScope: &statusScope,
+		scaleRequestScope:  &scaleScope,
 		logicalClusterName: logicalcluster.From(apiResourceSchema),
 	}
 
@@ -311,9 +312,11 @@ type servingInfo struct {
 
 	storage       rest.Storage
 	statusStorage rest.Storage
+	scaleStorage  rest.Storage
 
 	requestScope       *handlers.RequestScope
 	statusRequestScope *handlers.RequestScope
+	scaleRequestScope  *handlers.RequestScope
 }
 
 // Implement APIDefinition interface
@@ -328,8 +331,11 @@ func (apiDef *servingInfo) GetStorage() rest.Storage {
 	return apiDef.storage
 }
 func (apiDef *servingInfo) GetSubResourceStorage(subresource string) rest.Storage {
-	if subresource == "status" {
+	switch subresource {
+	case "status":
 		return apiDef.statusStorage
+	case "scale":
+		return apiDef.scaleStorage
 	}
 	return nil
 }
@@ -337,8 +343,11 @@ func (apiDef *servingInfo) GetRequestScope() *handlers.RequestScope {
 	return apiDef.requestScope
 }
 func (apiDef *servingInfo) GetSubResourceRequestScope(subresource string) *handlers.RequestScope {
-	if subresource == "status" {
+	switch subresource {
+	case "status":
 		return apiDef.statusRequestScope
+	case "scale":
+		return apiDef.scaleRequestScope
 	}
 	return nil
 }
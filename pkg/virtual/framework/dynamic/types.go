@@ -17,9 +17,12 @@ limitations under the License.
 package dynamic
 
 import (
+	"context"
+
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
 )
@@ -37,4 +40,17 @@ type DynamicVirtualWorkspace struct {
 	// Usually it would also set up some logic that will call the apiserver.CreateServingInfoFor() method
 	// to add an apidefinition.APIDefinition in the apidefinition.APIDefinitionSetGetter on some event.
 	BootstrapAPISetManagement func(mainConfig genericapiserver.CompletedConfig) (apidefinition.APIDefinitionSetGetter, error)
+
+	// AccessPolicy optionally returns the access policy of the API domain of a request. If it is nil,
+	// every client can reach the virtual workspace.
+	AccessPolicy framework.AccessPolicyResolverFunc
+}
+
+var _ framework.AccessPolicyResolver = (*DynamicVirtualWorkspace)(nil)
+
+func (vw *DynamicVirtualWorkspace) ResolveAccessPolicy(ctx context.Context) (*apisv1alpha1.VirtualWorkspaceAccess, error) {
+	if vw.AccessPolicy == nil {
+		return nil, nil
+	}
+	return vw.AccessPolicy(ctx)
 }
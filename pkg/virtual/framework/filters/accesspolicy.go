@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework"
+	virtualcontext "github.com/kcp-dev/kcp/pkg/virtual/framework/context"
+)
+
+// WithAccessPolicy rejects requests to a virtual workspace whose access policy for the API domain of the
+// request does not allow the client, by source address and user. Virtual workspaces without resolver in
+// resolvers, keyed by virtual workspace name, are not restricted. The client address is taken from the
+// X-Forwarded-For header only for requests of peers within trustedProxies, e.g. the kcp front-proxy.
+//
+// The filter must run after authentication, and after the virtual workspace name and API domain have been
+// added to the request context.
+func WithAccessPolicy(handler http.Handler, resolvers map[string]framework.AccessPolicyResolver, trustedProxies []*net.IPNet, codecs runtime.NegotiatedSerializer) http.Handler {
+	if len(resolvers) == 0 {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		name, found := virtualcontext.VirtualWorkspaceNameFrom(ctx)
+		if !found {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		resolver, found := resolvers[name]
+		if !found {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		policy, err := resolver.ResolveAccessPolicy(ctx)
+		if err != nil {
+			if _, ok := err.(apierrors.APIStatus); !ok {
+				err = apierrors.NewInternalError(err)
+			}
+			responsewriters.ErrorNegotiated(err, codecs, schema.GroupVersion{}, w, req)
+			return
+		}
+		if policy == nil {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		u, _ := genericapirequest.UserFrom(ctx)
+		if reason := denied(policy, clientIP(req, trustedProxies), u); reason != "" {
+			klog.FromContext(ctx).V(4).Info("request denied by virtual workspace access policy", "virtualWorkspace", name, "reason", reason)
+			responsewriters.ErrorNegotiated(
+				apierrors.NewForbidden(schema.GroupResource{}, "", fmt.Errorf("access to virtual workspace %s denied: %s", name, reason)),
+				codecs, schema.GroupVersion{}, w, req)
+			return
+		}
+
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// denied returns why the policy does not allow a client of the given address and user, or the empty string
+// if it does.
+func denied(policy *apisv1alpha1.VirtualWorkspaceAccess, ip net.IP, u user.Info) string {
+	if len(policy.SourceCIDRs) > 0 {
+		if ip == nil {
+			return "unknown client address"
+		}
+		inRange := false
+		for _, cidr := range policy.SourceCIDRs {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return fmt.Sprintf("invalid source CIDR %q", cidr)
+			}
+			if ipNet.Contains(ip) {
+				inRange = true
+			}
+		}
+		if !inRange {
+			return fmt.Sprintf("client address %s not allowed", ip)
+		}
+	}
+
+	if len(policy.Users) == 0 && len(policy.Groups) == 0 {
+		return ""
+	}
+	if u == nil {
+		return "unauthenticated user"
+	}
+	for _, name := range policy.Users {
+		if name == u.GetName() {
+			return ""
+		}
+	}
+	for _, group := range policy.Groups {
+		for _, g := range u.GetGroups() {
+			if group == g {
+				return ""
+			}
+		}
+	}
+	return fmt.Sprintf("user %q not allowed", u.GetName())
+}
+
+// clientIP returns the address of the client of the request. For requests of a trusted proxy, e.g. the kcp
+// front-proxy, this is the last entry of the X-Forwarded-For header which the proxy appends. Earlier entries
+// are ignored, as the client can set them. For requests of other peers, the header is ignored, as they can
+// forge it.
+func clientIP(req *http.Request, trustedProxies []*net.IPNet) net.IP {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	peer := net.ParseIP(host)
+	if peer == nil || !contains(trustedProxies, peer) {
+		return peer
+	}
+
+	if values := req.Header.Values("X-Forwarded-For"); len(values) > 0 {
+		entries := strings.Split(values[len(values)-1], ",")
+		if ip := net.ParseIP(strings.TrimSpace(entries[len(entries)-1])); ip != nil {
+			return ip
+		}
+	}
+	return peer
+}
+
+func contains(networks []*net.IPNet, ip net.IP) bool {
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework"
+	virtualcontext "github.com/kcp-dev/kcp/pkg/virtual/framework/context"
+)
+
+func TestWithAccessPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	scheme.AddUnversionedTypes(metav1.Unversioned, &metav1.Status{})
+	codecs := serializer.NewCodecFactory(scheme)
+
+	syncer := &user.DefaultInfo{Name: "system:serviceaccount:default:syncer", Groups: []string{"system:serviceaccounts"}}
+	alice := &user.DefaultInfo{Name: "alice", Groups: []string{"system:authenticated"}}
+
+	_, proxies, err := net.ParseCIDR("192.168.1.0/24")
+	require.NoError(t, err)
+	trustedProxies := []*net.IPNet{proxies}
+
+	tests := map[string]struct {
+		virtualWorkspace string
+		policy           *apisv1alpha1.VirtualWorkspaceAccess
+		remoteAddr       string
+		forwardedFor     string
+		user             user.Info
+
+		wantStatus int
+	}{
+		"no policy": {
+			virtualWorkspace: "syncer",
+			remoteAddr:       "192.168.1.1:1234",
+			user:             alice,
+			wantStatus:       http.StatusOK,
+		},
+		"other virtual workspace": {
+			virtualWorkspace: "workspaces",
+			policy:           &apisv1alpha1.VirtualWorkspaceAccess{Users: []string{syncer.Name}},
+			remoteAddr:       "192.168.1.1:1234",
+			user:             alice,
+			wantStatus:       http.StatusOK,
+		},
+		"allowed user": {
+			virtualWorkspace: "syncer",
+			policy:           &apisv1alpha1.VirtualWorkspaceAccess{Users: []string{syncer.Name}},
+			remoteAddr:       "192.168.1.1:1234",
+			user:             syncer,
+			wantStatus:       http.StatusOK,
+		},
+		"allowed group": {
+			virtualWorkspace: "syncer",
+			policy:           &apisv1alpha1.VirtualWorkspaceAccess{Groups: []string{"system:serviceaccounts"}},
+			remoteAddr:       "192.168.1.1:1234",
+			user:             syncer,
+			wantStatus:       http.StatusOK,
+		},
+		"other user": {
+			virtualWorkspace: "syncer",
+			policy:           &apisv1alpha1.VirtualWorkspaceAccess{Users: []string{syncer.Name}, Groups: []string{"system:serviceaccounts"}},
+			remoteAddr:       "192.168.1.1:1234",
+			user:             alice,
+			wantStatus:       http.StatusForbidden,
+		},
+		"allowed network": {
+			virtualWorkspace: "syncer",
+			policy:           &apisv1alpha1.VirtualWorkspaceAccess{SourceCIDRs: []string{"10.0.0.0/8", "192.168.1.0/24"}},
+			remoteAddr:       "192.168.1.1:1234",
+			user:             alice,
+			wantStatus:       http.StatusOK,
+		},
+		"other network": {
+			virtualWorkspace: "syncer",
+			policy:           &apisv1alpha1.VirtualWorkspaceAccess{SourceCIDRs: []string{"10.0.0.0/8"}},
+			remoteAddr:       "192.168.1.1:1234",
+			user:             alice,
+			wantStatus:       http.StatusForbidden,
+		},
+		"allowed network behind proxy": {
+			virtualWorkspace: "syncer",
+			policy:           &apisv1alpha1.VirtualWorkspaceAccess{SourceCIDRs: []string{"10.0.0.0/8"}},
+			remoteAddr:       "192.168.1.1:1234",
+			forwardedFor:     "192.168.1.1, 10.1.2.3",
+			user:             alice,
+			wantStatus:       http.StatusOK,
+		},
+		"forged forwarded address": {
+			virtualWorkspace: "syncer",
+			policy:           &apisv1alpha1.VirtualWorkspaceAccess{SourceCIDRs: []string{"10.0.0.0/8"}},
+			remoteAddr:       "192.168.1.1:1234",
+			forwardedFor:     "10.1.2.3, 192.168.1.1",
+			user:             alice,
+			wantStatus:       http.StatusForbidden,
+		},
+		"forwarded address of untrusted peer": {
+			virtualWorkspace: "syncer",
+			policy:           &apisv1alpha1.VirtualWorkspaceAccess{SourceCIDRs: []string{"10.0.0.0/8"}},
+			remoteAddr:       "172.16.0.1:1234",
+			forwardedFor:     "10.1.2.3",
+			user:             alice,
+			wantStatus:       http.StatusForbidden,
+		},
+		"untrusted peer in allowed network": {
+			virtualWorkspace: "syncer",
+			policy:           &apisv1alpha1.VirtualWorkspaceAccess{SourceCIDRs: []string{"172.16.0.0/12"}},
+			remoteAddr:       "172.16.0.1:1234",
+			forwardedFor:     "10.1.2.3",
+			user:             alice,
+			wantStatus:       http.StatusOK,
+		},
+		"allowed network, other user": {
+			virtualWorkspace: "syncer",
+			policy:           &apisv1alpha1.VirtualWorkspaceAccess{SourceCIDRs: []string{"192.168.1.0/24"}, Users: []string{syncer.Name}},
+			remoteAddr:       "192.168.1.1:1234",
+			user:             alice,
+			wantStatus:       http.StatusForbidden,
+		},
+		"invalid CIDR": {
+			virtualWorkspace: "syncer",
+			policy:           &apisv1alpha1.VirtualWorkspaceAccess{SourceCIDRs: []string{"192.168.1.1"}},
+			remoteAddr:       "192.168.1.1:1234",
+			user:             alice,
+			wantStatus:       http.StatusForbidden,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resolvers := map[string]framework.AccessPolicyResolver{
+				"syncer": framework.AccessPolicyResolverFunc(func(ctx context.Context) (*apisv1alpha1.VirtualWorkspaceAccess, error) {
+					return tc.policy, nil
+				}),
+			}
+			handler := WithAccessPolicy(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}), resolvers, trustedProxies, codecs)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/configmaps", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tc.forwardedFor)
+			}
+			ctx := virtualcontext.WithVirtualWorkspaceName(req.Context(), tc.virtualWorkspace)
+			ctx = genericapirequest.WithUser(ctx, tc.user)
+			req = req.WithContext(ctx)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			require.Equal(t, tc.wantStatus, rec.Code, rec.Body.String())
+		})
+	}
+}
@@ -55,6 +55,12 @@ import (
 var noxusGVR = schema.GroupVersionResource{Group: "mygroup.example.com", Resource: "noxus", Version: "v1beta1"}
 
 func newStorage(t *testing.T, clusterClient kcpdynamic.ClusterInterface, apiExportIdentityHash string, patchConflictRetryBackoff *wait.Backoff) (mainStorage, statusStorage rest.Storage) {
+	return newStorageWithWrapper(t, clusterClient, apiExportIdentityHash, patchConflictRetryBackoff, func(_ schema.GroupResource, store *forwardingregistry.StoreFuncs) *forwardingregistry.StoreFuncs {
+		return store
+	})
+}
+
+func newStorageWithWrapper(t *testing.T, clusterClient kcpdynamic.ClusterInterface, apiExportIdentityHash string, patchConflictRetryBackoff *wait.Backoff, wrapper forwardingregistry.StorageWrapper) (mainStorage, statusStorage rest.Storage) {
 	gvr := noxusGVR
 	groupVersion := gvr.GroupVersion()
 
@@ -110,9 +116,7 @@ func newStorage(t *testing.T, clusterClient kcpdynamic.ClusterInterface, apiExpo
 		nil,
 		clusterClient,
 		patchConflictRetryBackoff,
-		func(_ schema.GroupResource, store *forwardingregistry.StoreFuncs) *forwardingregistry.StoreFuncs {
-			return store
-		})
+		wrapper)
 }
 
 func createResource(namespace, name string) *unstructured.Unstructured {
@@ -157,6 +161,17 @@ func TestGet(t *testing.T) {
 	require.Truef(t, apiequality.Semantic.DeepEqual(resource, result), "expected:\n%V\nactual:\n%V", resource, result)
 }
 
+func TestGetWildcard(t *testing.T) {
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), createResource("default", "foo"))
+	storage, _ := newStorage(t, fakeClient, "", nil)
+	ctx := request.WithNamespace(context.Background(), "default")
+	ctx = request.WithCluster(ctx, request.Cluster{Name: logicalcluster.Wildcard, Wildcard: true})
+
+	getter := storage.(rest.Getter)
+	_, err := getter.Get(ctx, "foo", &metav1.GetOptions{})
+	require.True(t, errors.IsBadRequest(err), "expected bad request, got %v", err)
+}
+
 func TestList(t *testing.T) {
 	resources := []runtime.Object{createResource("default", "foo"), createResource("default", "foo2")}
 	fakeClient := kcpfakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), resources...)
@@ -272,6 +272,10 @@ func clientGetter(dynamicClusterClient kcpdynamic.ClusterInterface, namespaceSco
 		}
 		gvr := resource
 		clusterName := cluster.Name
+		if clusterName == logicalcluster.Wildcard {
+			// object names are only unique within a logical cluster
+			return nil, apiErrorBadRequest(fmt.Errorf("only LIST and WATCH requests can be cross-cluster, other requests must target the logical cluster of the object: %s", gvr.String()))
+		}
 		if apiExportIdentityHash != "" {
 			gvr.Resource += ":" + apiExportIdentityHash
 		}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/registry/rest"
 )
 
 func WithStaticLabelSelector(labelSelector labels.Requirements) StorageWrapper {
@@ -75,6 +76,26 @@ func WithLabelSelector(labelSelectorFrom func(ctx context.Context) labels.Requir
 			return delegateWatcher.Watch(ctx, options)
 		}
 
+		// deletes must not reach objects that are filtered out
+		if delegateDeleter := storage.GracefulDeleterFunc; delegateDeleter != nil {
+			storage.GracefulDeleterFunc = func(ctx context.Context, name string, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions) (runtime.Object, bool, error) {
+				if _, err := storage.GetterFunc.Get(ctx, name, &metav1.GetOptions{}); err != nil {
+					return nil, false, err
+				}
+				return delegateDeleter.Delete(ctx, name, deleteValidation, options)
+			}
+		}
+		if delegateCollectionDeleter := storage.CollectionDeleterFunc; delegateCollectionDeleter != nil {
+			storage.CollectionDeleterFunc = func(ctx context.Context, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions, listOptions *internalversion.ListOptions) (runtime.Object, error) {
+				selector := listOptions.LabelSelector
+				if selector == nil {
+					selector = labels.Everything()
+				}
+				listOptions.LabelSelector = selector.Add(labelSelectorFrom(ctx)...)
+				return delegateCollectionDeleter.DeleteCollection(ctx, deleteValidation, options, listOptions)
+			}
+		}
+
 		return storage
 	}
 }
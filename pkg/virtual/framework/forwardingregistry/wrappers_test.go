@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingregistry_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	kcpfakedynamic "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/dynamic/fake"
+	kcptesting "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/testing"
+	"k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
+)
+
+func createLabelledResource(namespace, name string, labels map[string]interface{}) *unstructured.Unstructured {
+	resource := createResource(namespace, name)
+	resource.Object["metadata"].(map[string]interface{})["labels"] = labels
+	return resource
+}
+
+func ownerRequirements(t *testing.T) labels.Requirements {
+	t.Helper()
+
+	requirements, selectable := labels.SelectorFromSet(labels.Set{"owner": "me"}).Requirements()
+	require.True(t, selectable)
+	return requirements
+}
+
+func TestWithLabelSelectorList(t *testing.T) {
+	resources := []runtime.Object{
+		createLabelledResource("default", "mine-foo", map[string]interface{}{"owner": "me", "app": "foo"}),
+		createLabelledResource("default", "mine-bar", map[string]interface{}{"owner": "me", "app": "bar"}),
+		createLabelledResource("default", "theirs-foo", map[string]interface{}{"owner": "them", "app": "foo"}),
+	}
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), resources...)
+	storage, _ := newStorageWithWrapper(t, fakeClient, "", nil, forwardingregistry.WithStaticLabelSelector(ownerRequirements(t)))
+	ctx := request.WithNamespace(context.Background(), "default")
+	ctx = request.WithCluster(ctx, request.Cluster{Name: logicalcluster.New("test")})
+
+	lister := storage.(rest.Lister)
+	result, err := lister.List(ctx, &internalversion.ListOptions{LabelSelector: labels.SelectorFromSet(labels.Set{"app": "foo"})})
+	require.NoError(t, err)
+	require.IsType(t, &unstructured.UnstructuredList{}, result)
+	items := result.(*unstructured.UnstructuredList).Items
+	require.Len(t, items, 1)
+	require.Equal(t, "mine-foo", items[0].GetName())
+
+	// both the requested and the static selector are pushed down to the delegate
+	require.Len(t, fakeClient.Actions(), 1)
+	listAction, ok := fakeClient.Actions()[0].(kcptesting.ListAction)
+	require.True(t, ok, "expected a list action, got %T", fakeClient.Actions()[0])
+	require.Equal(t, "app=foo,owner=me", listAction.GetListRestrictions().Labels.String())
+}
+
+func TestWithLabelSelectorWatch(t *testing.T) {
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	fakeWatcher := watch.NewFake()
+	defer fakeWatcher.Stop()
+	fakeClient.PrependWatchReactor("noxus", kcptesting.DefaultWatchReactor(fakeWatcher, nil))
+	storage, _ := newStorageWithWrapper(t, fakeClient, "", nil, forwardingregistry.WithStaticLabelSelector(ownerRequirements(t)))
+	ctx := request.WithNamespace(context.Background(), "default")
+	ctx = request.WithCluster(ctx, request.Cluster{Name: logicalcluster.New("test")})
+
+	watcher := storage.(rest.Watcher)
+	w, err := watcher.Watch(ctx, &internalversion.ListOptions{})
+	require.NoError(t, err)
+	defer w.Stop()
+
+	require.Len(t, fakeClient.Actions(), 1)
+	watchAction, ok := fakeClient.Actions()[0].(kcptesting.WatchAction)
+	require.True(t, ok, "expected a watch action, got %T", fakeClient.Actions()[0])
+	require.Equal(t, "owner=me", watchAction.GetWatchRestrictions().Labels.String())
+}
+
+func TestWithLabelSelectorGet(t *testing.T) {
+	resources := []runtime.Object{
+		createLabelledResource("default", "mine", map[string]interface{}{"owner": "me"}),
+		createLabelledResource("default", "theirs", map[string]interface{}{"owner": "them"}),
+	}
+	fakeClient := kcpfakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), resources...)
+	storage, _ := newStorageWithWrapper(t, fakeClient, "", nil, forwardingregistry.WithStaticLabelSelector(ownerRequirements(t)))
+	ctx := request.WithNamespace(context.Background(), "default")
+	ctx = request.WithCluster(ctx, request.Cluster{Name: logicalcluster.New("test")})
+
+	getter := storage.(rest.Getter)
+	_, err := getter.Get(ctx, "mine", &metav1.GetOptions{})
+	require.NoError(t, err)
+
+	_, err = getter.Get(ctx, "theirs", &metav1.GetOptions{})
+	require.EqualError(t, err, `noxus.mygroup.example.com "theirs" not found`)
+}
@@ -18,6 +18,7 @@ package rootapiserver
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -37,6 +38,7 @@ import (
 
 	"github.com/kcp-dev/kcp/pkg/virtual/framework"
 	virtualcontext "github.com/kcp-dev/kcp/pkg/virtual/framework/context"
+	virtualfilters "github.com/kcp-dev/kcp/pkg/virtual/framework/filters"
 )
 
 var (
@@ -57,6 +59,10 @@ type RootAPIExtraConfig struct {
 	informerStart func(stopCh <-chan struct{})
 
 	VirtualWorkspaces []NamedVirtualWorkspace
+
+	// TrustedProxies are the networks of the proxies whose X-Forwarded-For header is trusted to carry
+	// the client address for the access policies of the virtual workspaces.
+	TrustedProxies []*net.IPNet
 }
 
 type NamedVirtualWorkspace struct {
@@ -162,7 +168,7 @@ func asHealthChecks(workspaces []NamedVirtualWorkspace) []healthz.HealthChecker
 func (c completedConfig) getRootHandlerChain(delegateAPIServer genericapiserver.DelegationTarget) func(http.Handler, *genericapiserver.Config) http.Handler {
 	return func(apiHandler http.Handler, genericConfig *genericapiserver.Config) http.Handler {
 		delegateAfterDefaultHandlerChain := genericapiserver.DefaultBuildHandlerChain(
-			http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			virtualfilters.WithAccessPolicy(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 				if _, virtualWorkspaceNameExists := virtualcontext.VirtualWorkspaceNameFrom(req.Context()); virtualWorkspaceNameExists {
 					delegatedHandler := delegateAPIServer.UnprotectedHandler()
 					if delegatedHandler != nil {
@@ -171,7 +177,7 @@ func (c completedConfig) getRootHandlerChain(delegateAPIServer genericapiserver.
 					return
 				}
 				apiHandler.ServeHTTP(w, req)
-			}), c.GenericConfig.Config)
+			}), accessPolicyResolvers(c.ExtraConfig.VirtualWorkspaces), c.ExtraConfig.TrustedProxies, errorCodecs), c.GenericConfig.Config)
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 			requestContext := req.Context()
 			// detect old kubectl plugins and inject warning headers
@@ -202,6 +208,17 @@ func (c completedConfig) getRootHandlerChain(delegateAPIServer genericapiserver.
 	}
 }
 
+// accessPolicyResolvers returns the virtual workspaces restricting the clients that can reach them, by name.
+func accessPolicyResolvers(workspaces []NamedVirtualWorkspace) map[string]framework.AccessPolicyResolver {
+	resolvers := map[string]framework.AccessPolicyResolver{}
+	for _, vw := range workspaces {
+		if resolver, ok := vw.VirtualWorkspace.(framework.AccessPolicyResolver); ok {
+			resolvers[vw.Name] = resolver
+		}
+	}
+	return resolvers
+}
+
 func NewRootAPIConfig(recommendedConfig *genericapiserver.RecommendedConfig, informerStarts []InformerStart, virtualWorkspaces []NamedVirtualWorkspace) (*RootAPIConfig, error) {
 	// TODO: genericConfig.ExternalAddress = ... allow a command line flag or it to be overridden by a top-level multiroot apiServer
 
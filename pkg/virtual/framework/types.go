@@ -21,6 +21,8 @@ import (
 
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 )
 
 // RootPathResolverFunc is the type of a function that, based on the URL path of a request,
@@ -63,6 +65,25 @@ type ReadyChecker interface {
 	IsReady() error
 }
 
+// AccessPolicyResolverFunc is the type of a function that returns the access policy of the
+// API domain a request was resolved to, based on the request context completed by the RootPathResolver.
+type AccessPolicyResolverFunc func(ctx context.Context) (*apisv1alpha1.VirtualWorkspaceAccess, error)
+
+func (f AccessPolicyResolverFunc) ResolveAccessPolicy(ctx context.Context) (*apisv1alpha1.VirtualWorkspaceAccess, error) {
+	return f(ctx)
+}
+
+var _ AccessPolicyResolver = AccessPolicyResolverFunc(nil)
+
+// AccessPolicyResolver is optionally implemented by virtual workspaces which restrict the clients that can
+// reach their API domains, e.g. to the syncer of a SyncTarget. The policy is enforced by the
+// filters.WithAccessPolicy filter of the root API server.
+type AccessPolicyResolver interface {
+	// ResolveAccessPolicy returns the access policy of the API domain of the request context, or nil
+	// if every client can reach it.
+	ResolveAccessPolicy(ctx context.Context) (*apisv1alpha1.VirtualWorkspaceAccess, error)
+}
+
 // VirtualWorkspace is the definition of a virtual workspace
 // that will be registered and made available, at a given prefix,
 // inside a Root API server as a delegated API Server.
@@ -18,6 +18,7 @@ package options
 
 import (
 	"fmt"
+	"net"
 
 	kcpkubernetesinformers "github.com/kcp-dev/client-go/informers"
 	"github.com/spf13/pflag"
@@ -39,6 +40,10 @@ type Options struct {
 	Syncer                 *synceroptions.Syncer
 	APIExport              *apiexportoptions.APIExport
 	InitializingWorkspaces *initializingworkspacesoptions.InitializingWorkspaces
+
+	// TrustedProxyCIDRs are the networks of the proxies, e.g. the kcp front-proxy, whose
+	// X-Forwarded-For header is trusted to carry the client address.
+	TrustedProxyCIDRs []string
 }
 
 func NewOptions() *Options {
@@ -57,6 +62,9 @@ func (v *Options) Validate() []error {
 	errs = append(errs, v.Syncer.Validate(virtualWorkspacesFlagPrefix)...)
 	errs = append(errs, v.APIExport.Validate(virtualWorkspacesFlagPrefix)...)
 	errs = append(errs, v.InitializingWorkspaces.Validate(virtualWorkspacesFlagPrefix)...)
+	if _, err := v.TrustedProxyNetworks(); err != nil {
+		errs = append(errs, err)
+	}
 
 	return errs
 }
@@ -64,6 +72,23 @@ func (v *Options) Validate() []error {
 func (v *Options) AddFlags(fs *pflag.FlagSet) {
 	v.Workspaces.AddFlags(fs, virtualWorkspacesFlagPrefix)
 	v.InitializingWorkspaces.AddFlags(fs, virtualWorkspacesFlagPrefix)
+
+	fs.StringSliceVar(&v.TrustedProxyCIDRs, virtualWorkspacesFlagPrefix+"trusted-proxy-cidrs", v.TrustedProxyCIDRs,
+		"CIDRs of the proxies, e.g. the kcp front-proxy, whose X-Forwarded-For header is trusted to carry the client address "+
+			"when enforcing the source CIDRs of virtual workspace access policies. Requests from other peers are matched by their own address.")
+}
+
+// TrustedProxyNetworks returns the parsed TrustedProxyCIDRs.
+func (v *Options) TrustedProxyNetworks() ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(v.TrustedProxyCIDRs))
+	for _, cidr := range v.TrustedProxyCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%strusted-proxy-cidrs entry %q: %w", virtualWorkspacesFlagPrefix, cidr, err)
+		}
+		networks = append(networks, ipNet)
+	}
+	return networks, nil
 }
 
 func (o *Options) NewVirtualWorkspaces(
@@ -22,12 +22,12 @@ import (
 	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/cache"
 
 	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/rootapiserver"
 	"github.com/kcp-dev/kcp/pkg/virtual/syncer/controllers/apireconciler"
@@ -63,6 +63,11 @@ func BuildVirtualWorkspace(
 		return nil
 	}
 
+	indexers.AddIfNotPresentOrDie(wildcardKcpInformers.Workload().V1alpha1().SyncTargets().Informer().GetIndexer(), cache.Indexers{
+		indexers.SyncTargetsBySyncTargetKey: indexers.IndexSyncTargetsBySyncTargetKey,
+	})
+	syncTargetIndexer := wildcardKcpInformers.Workload().V1alpha1().SyncTargets().Informer().GetIndexer()
+
 	if err := wildcardKcpInformers.Apis().V1alpha1().APIExports().Informer().AddIndexers(cache.Indexers{
 		apireconciler.IndexAPIExportsByAPIResourceSchema: apireconciler.IndexAPIExportsByAPIResourceSchemas,
 	}); err != nil {
@@ -86,7 +91,18 @@ func BuildVirtualWorkspace(
 				restProviderBuilder:   NewSyncerRestProvider,
 				allowedAPIFilter:      nil,
 				transformer: &transformations.SyncerResourceTransformer{
-					TransformationProvider:   &transformations.SpecDiffTransformation{},
+					TransformationProvider: transformations.TransformationChain{
+						&transformations.SpecDiffTransformation{},
+						&transformations.SyncTargetTransformation{
+							GetSyncTarget: func(syncTargetKey string) (*workloadv1alpha1.SyncTarget, error) {
+								syncTargets, err := indexers.ByIndex[*workloadv1alpha1.SyncTarget](syncTargetIndexer, indexers.SyncTargetsBySyncTargetKey, syncTargetKey)
+								if err != nil || len(syncTargets) == 0 {
+									return nil, err
+								}
+								return syncTargets[0], nil
+							},
+						},
+					},
 					SummarizingRulesProvider: &transformations.DefaultSummarizingRules{},
 				},
 				storageWrapperBuilder: forwardingregistry.WithStaticLabelSelector,
@@ -98,10 +114,7 @@ func BuildVirtualWorkspace(
 				virtualWorkspaceName:  UpsyncerVirtualWorkspaceName,
 				filteredResourceState: workloadv1alpha1.ResourceStateUpsync,
 				restProviderBuilder:   NewUpSyncerRestProvider,
-				allowedAPIFilter: func(apiGroupResource schema.GroupResource) bool {
-					// Only allow persistentvolumes to be Upsynced.
-					return apiGroupResource.Group == "" && apiGroupResource.Resource == "persistentvolumes"
-				},
+				allowedAPIFilter:      upsyncer.IsUpsyncAllowed,
 				transformer:           &upsyncer.UpsyncerResourceTransformer{},
 				storageWrapperBuilder: upsyncer.WithStaticLabelSelectorAndInWriteCallsCheck,
 			}).buildVirtualWorkspace(),
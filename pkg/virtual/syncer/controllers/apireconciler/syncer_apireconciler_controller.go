@@ -51,10 +51,20 @@ const (
 	ControllerName                     = "kcp-virtual-syncer-api-reconciler-"
 	IndexSyncTargetsByExport           = ControllerName + "ByExport"
 	IndexAPIExportsByAPIResourceSchema = ControllerName + "ByAPIResourceSchema"
+
+	// numWorkers is the number of workers reconciling SyncTargets. The work queue never hands out a SyncTarget
+	// to two workers at once, so a SyncTarget that is expensive to reconcile, e.g. because it supports many
+	// APIExports, occupies at most one worker, while the others keep serving the remaining SyncTargets.
+	numWorkers = 4
+
+	// minReconcileInterval is the minimal time between two reconciliations of a SyncTarget. Changes in between
+	// are collapsed into one reconciliation, and the SyncTarget goes to the back of the queue, so that a
+	// SyncTarget changing all the time cannot starve the others.
+	minReconcileInterval = time.Second
 )
 
 type CreateAPIDefinitionFunc func(syncTargetWorkspace logicalcluster.Name, syncTargetName string, apiResourceSchema *apisv1alpha1.APIResourceSchema, version string, identityHash string) (apidefinition.APIDefinition, error)
-type AllowedAPIfilterFunc func(apiGroupResource schema.GroupResource) bool
+type AllowedAPIfilterFunc func(syncTarget *workloadv1alpha1.SyncTarget, apiGroupResource schema.GroupResource) bool
 
 func NewAPIReconciler(
 	virtualWorkspaceName string,
@@ -80,12 +90,15 @@ func NewAPIReconciler(
 		apiExportLister:  apiExportInformer.Lister(),
 		apiExportIndexer: apiExportInformer.Informer().GetIndexer(),
 
-		queue: queue,
+		queue:          queue,
+		lastReconciled: map[string]time.Time{},
+		now:            time.Now,
 
 		createAPIDefinition: createAPIDefinition,
 		allowedAPIfilter:    allowedAPIfilter,
 
-		apiSets: map[dynamiccontext.APIDomainKey]apidefinition.APIDefinitionSet{},
+		apiSets:     map[dynamiccontext.APIDomainKey]apidefinition.APIDefinitionSet{},
+		unavailable: map[dynamiccontext.APIDomainKey]string{},
 	}
 
 	logger := logging.WithReconciler(klog.Background(), ControllerName+virtualWorkspaceName)
@@ -96,9 +109,16 @@ func NewAPIReconciler(
 			oldCluster := old.(*workloadv1alpha1.SyncTarget)
 			newCluster := obj.(*workloadv1alpha1.SyncTarget)
 
-			// only enqueue when syncedResource is changed.
+			// only enqueue when syncedResource, included or excluded resources, upsyncedResources or the heartbeat health is changed.
 			if !equality.Semantic.DeepEqual(oldCluster.Status.SyncedResources, newCluster.Status.SyncedResources) {
 				c.enqueueSyncTarget(obj, logger, "")
+			} else if !equality.Semantic.DeepEqual(oldCluster.Spec.IncludedResources, newCluster.Spec.IncludedResources) ||
+				!equality.Semantic.DeepEqual(oldCluster.Spec.ExcludedResources, newCluster.Spec.ExcludedResources) {
+				c.enqueueSyncTarget(obj, logger, " because of included or excluded resources")
+			} else if !equality.Semantic.DeepEqual(oldCluster.Spec.UpsyncedResources, newCluster.Spec.UpsyncedResources) {
+				c.enqueueSyncTarget(obj, logger, " because of upsynced resources")
+			} else if heartbeatExpired(oldCluster) != heartbeatExpired(newCluster) {
+				c.enqueueSyncTarget(obj, logger, " because of heartbeat health")
 			}
 		},
 		DeleteFunc: func(obj interface{}) { c.enqueueSyncTarget(obj, logger, "") },
@@ -135,11 +155,18 @@ type APIReconciler struct {
 
 	queue workqueue.RateLimitingInterface
 
+	lastReconciledLock sync.Mutex
+	// lastReconciled holds the time of the last reconciliation of the SyncTarget keys, see minReconcileInterval.
+	lastReconciled map[string]time.Time
+	now            func() time.Time
+
 	createAPIDefinition CreateAPIDefinitionFunc
 	allowedAPIfilter    AllowedAPIfilterFunc
 
-	mutex   sync.RWMutex // protects the map, not the values!
+	mutex   sync.RWMutex // protects the maps, not the values!
 	apiSets map[dynamiccontext.APIDomainKey]apidefinition.APIDefinitionSet
+	// unavailable holds the reason why the APIs of an API domain are not served, e.g. an expired heartbeat.
+	unavailable map[dynamiccontext.APIDomainKey]string
 }
 
 func (c *APIReconciler) enqueueSyncTarget(obj interface{}, logger logr.Logger, logSuffix string) {
@@ -150,7 +177,33 @@ func (c *APIReconciler) enqueueSyncTarget(obj interface{}, logger logr.Logger, l
 	}
 
 	logging.WithQueueKey(logger, key).V(2).Info(fmt.Sprintf("queueing SyncTarget%s", logSuffix))
-	c.queue.Add(key)
+	c.queue.AddAfter(key, c.delayFor(key))
+}
+
+// delayFor returns how long to wait before the SyncTarget key can be reconciled again.
+func (c *APIReconciler) delayFor(key string) time.Duration {
+	c.lastReconciledLock.Lock()
+	defer c.lastReconciledLock.Unlock()
+
+	last, found := c.lastReconciled[key]
+	if !found {
+		return 0
+	}
+	if delay := last.Add(minReconcileInterval).Sub(c.now()); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+func (c *APIReconciler) setLastReconciled(key string, deleted bool) {
+	c.lastReconciledLock.Lock()
+	defer c.lastReconciledLock.Unlock()
+
+	if deleted {
+		delete(c.lastReconciled, key)
+		return
+	}
+	c.lastReconciled[key] = c.now()
 }
 
 func (c *APIReconciler) enqueueAPIExport(obj interface{}, logger logr.Logger, logSuffix string) {
@@ -199,14 +252,16 @@ func (c *APIReconciler) startWorker(ctx context.Context) {
 
 func (c *APIReconciler) Start(ctx context.Context) {
 	defer runtime.HandleCrash()
-	defer c.queue.ShutDown()
+	defer c.ShutDown()
 
 	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName+c.virtualWorkspaceName)
 	ctx = klog.NewContext(ctx, logger)
 	logger.Info("Starting controller")
 	defer logger.Info("Shutting down controller")
 
-	go wait.Until(func() { c.startWorker(ctx) }, time.Second, ctx.Done())
+	for i := 0; i < numWorkers; i++ {
+		go wait.Until(func() { c.startWorker(ctx) }, time.Second, ctx.Done())
+	}
 
 	// stop all watches if the controller is stopped
 	defer func() {
@@ -262,11 +317,13 @@ func (c *APIReconciler) process(ctx context.Context, key string) error {
 	syncTarget, err := c.syncTargetLister.Cluster(clusterName).Get(syncTargetName)
 	if apierrors.IsNotFound(err) {
 		c.removeAPIDefinitionSet(apiDomainKey)
+		c.setLastReconciled(key, true)
 		return nil
 	}
 	if err != nil {
 		return err
 	}
+	c.setLastReconciled(key, false)
 
 	if err := c.reconcile(ctx, apiDomainKey, syncTarget); err != nil {
 		return err
@@ -279,13 +336,20 @@ func (c *APIReconciler) GetAPIDefinitionSet(_ context.Context, key dynamiccontex
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 
+	if reason, found := c.unavailable[key]; found {
+		return nil, true, apierrors.NewServiceUnavailable(reason)
+	}
+
 	apiSet, ok := c.apiSets[key]
 	return apiSet, ok, nil
 }
 
 func (c *APIReconciler) removeAPIDefinitionSet(key dynamiccontext.APIDomainKey) {
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
+	oldSet := c.apiSets[key]
 	delete(c.apiSets, key)
+	delete(c.unavailable, key)
+	c.mutex.Unlock()
+
+	oldSet.TearDownAll()
 }
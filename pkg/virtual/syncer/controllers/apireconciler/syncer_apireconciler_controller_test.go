@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apireconciler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+func TestDelayFor(t *testing.T) {
+	now := time.Date(2022, 11, 20, 0, 0, 0, 0, time.UTC)
+	c := &APIReconciler{
+		lastReconciled: map[string]time.Time{},
+		now:            func() time.Time { return now },
+	}
+
+	require.Equal(t, time.Duration(0), c.delayFor("root:org|target"), "never reconciled")
+
+	c.setLastReconciled("root:org|target", false)
+	require.Equal(t, minReconcileInterval, c.delayFor("root:org|target"))
+	require.Equal(t, time.Duration(0), c.delayFor("root:org|other"), "other SyncTargets are not delayed")
+
+	now = now.Add(minReconcileInterval / 4)
+	require.Equal(t, minReconcileInterval*3/4, c.delayFor("root:org|target"))
+
+	now = now.Add(minReconcileInterval)
+	require.Equal(t, time.Duration(0), c.delayFor("root:org|target"))
+
+	c.setLastReconciled("root:org|target", false)
+	c.setLastReconciled("root:org|target", true)
+	require.Equal(t, time.Duration(0), c.delayFor("root:org|target"), "deleted SyncTargets are forgotten")
+	require.Empty(t, c.lastReconciled)
+}
+
+func TestEnqueueSyncTarget(t *testing.T) {
+	syncTarget := func(clusterName, name string) *workloadv1alpha1.SyncTarget {
+		return &workloadv1alpha1.SyncTarget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{logicalcluster.AnnotationKey: clusterName},
+			},
+		}
+	}
+
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	c := &APIReconciler{
+		queue:          queue,
+		lastReconciled: map[string]time.Time{},
+		now:            time.Now,
+	}
+	logger := klog.Background()
+
+	// a busy SyncTarget that was just reconciled is queued behind the others, however often it changes.
+	c.setLastReconciled("root:busy|target", false)
+	for i := 0; i < 3; i++ {
+		c.enqueueSyncTarget(syncTarget("root:busy", "target"), logger, "")
+	}
+	c.enqueueSyncTarget(syncTarget("root:quiet", "target"), logger, "")
+	c.enqueueSyncTarget(syncTarget("root:quiet", "other"), logger, "")
+
+	require.Equal(t, 2, queue.Len())
+	for _, want := range []string{"root:quiet|target", "root:quiet|other"} {
+		key, _ := queue.Get()
+		require.Equal(t, want, key)
+		queue.Done(key)
+	}
+
+	// the changes of the busy SyncTarget are collapsed into one reconciliation.
+	require.Eventually(t, func() bool { return queue.Len() == 1 }, 3*minReconcileInterval, 10*time.Millisecond)
+	key, _ := queue.Get()
+	require.Equal(t, "root:busy|target", key)
+	queue.Done(key)
+	require.Never(t, func() bool { return queue.Len() > 0 }, minReconcileInterval, 100*time.Millisecond)
+}
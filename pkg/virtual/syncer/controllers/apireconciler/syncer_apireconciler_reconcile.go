@@ -31,6 +31,7 @@ import (
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/client"
+	"github.com/kcp-dev/kcp/pkg/conditions"
 	"github.com/kcp-dev/kcp/pkg/logging"
 	"github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/apidefinition"
 	dynamiccontext "github.com/kcp-dev/kcp/pkg/virtual/framework/dynamic/context"
@@ -44,12 +45,33 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiDomainKey dynamicconte
 
 	logger := klog.FromContext(ctx)
 
+	// Stop serving the APIs of a SyncTarget whose syncer stopped heart-beating. Requests fail with 503
+	// instead of operating on stale data until the heartbeat is healthy again.
+	if heartbeatExpired(syncTarget) {
+		logging.WithObject(logger, syncTarget).WithValues("APIDomainKey", apiDomainKey).V(2).Info("Tearing down APIs for SyncTarget with expired heartbeat")
+
+		c.mutex.Lock()
+		delete(c.apiSets, apiDomainKey)
+		c.unavailable[apiDomainKey] = fmt.Sprintf("SyncTarget %s|%s is not heartbeating: %s", logicalcluster.From(syncTarget), syncTarget.Name, conditions.GetMessage(syncTarget, workloadv1alpha1.HeartbeatHealthy))
+		c.mutex.Unlock()
+
+		oldSet.TearDownAll()
+		return nil
+	}
+
 	// collect APIResourceSchemas by syncTarget.
 	apiResourceSchemas, schemaIdentites, err := c.getAllAcceptedResourceSchemas(syncTarget)
 	if err != nil {
 		return err
 	}
 
+	// only serve the resources of the APIExports that the SyncTarget includes and does not exclude.
+	for gr := range apiResourceSchemas {
+		if !syncTarget.Spec.IsResourceSynced(gr) {
+			delete(apiResourceSchemas, gr)
+		}
+	}
+
 	// add built-in apiResourceSchema
 	for _, apiResourceSchema := range syncerbuiltin.SyncerSchemas {
 		shallow := *apiResourceSchema
@@ -63,13 +85,12 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiDomainKey dynamicconte
 		}] = &shallow
 	}
 
-	// reconcile APIs for APIResourceSchemas
+	// reconcile APIs for APIResourceSchemas. Definitions of unchanged schemas are taken over from
+	// the old set, so that their storage and the established watches are preserved.
 	newSet := apidefinition.APIDefinitionSet{}
-	newGVRs := []string{}
-	preservedGVR := []string{}
 	for gr, apiResourceSchema := range apiResourceSchemas {
 
-		if c.allowedAPIfilter != nil && !c.allowedAPIfilter(gr) {
+		if c.allowedAPIfilter != nil && !c.allowedAPIfilter(syncTarget, gr) {
 			continue
 		}
 
@@ -96,7 +117,6 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiDomainKey dynamicconte
 				if oldDef.UID == apiResourceSchema.UID && oldDef.IdentityHash == schemaIdentites[gr] {
 					// this is the same schema and identity as before. no need to update.
 					newSet[gvr] = oldDef
-					preservedGVR = append(preservedGVR, gvrString(gvr))
 					continue
 				}
 			}
@@ -112,28 +132,30 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiDomainKey dynamicconte
 				UID:           apiResourceSchema.UID,
 				IdentityHash:  schemaIdentites[gr],
 			}
-			newGVRs = append(newGVRs, gvrString(gvr))
-		}
-	}
-
-	// cleanup old definitions
-	removedGVRs := []string{}
-	for gvr, oldDef := range oldSet {
-		if _, found := newSet[gvr]; !found || oldDef != newSet[gvr] {
-			removedGVRs = append(removedGVRs, gvrString(gvr))
-			oldDef.TearDown()
 		}
 	}
 
-	logging.WithObject(logger, syncTarget).WithValues("APIDomainKey", apiDomainKey).V(2).Info("Updating APIs for SyncTarget and APIDomainKey", "newGVRs", newGVRs, "preservedGVRs", preservedGVR, "removedGVRs", removedGVRs)
+	diff := apidefinition.Diff(oldSet, newSet)
+	logging.WithObject(logger, syncTarget).WithValues("APIDomainKey", apiDomainKey).V(2).Info("Updating APIs for SyncTarget and APIDomainKey", "newGVRs", apidefinition.Strings(diff.Added), "preservedGVRs", apidefinition.Strings(diff.Preserved), "removedGVRs", apidefinition.Strings(diff.Removed))
 
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
 	c.apiSets[apiDomainKey] = newSet
+	delete(c.unavailable, apiDomainKey)
+	c.mutex.Unlock()
+
+	// only tear down the definitions that are not served anymore
+	diff.TearDown(oldSet)
 
 	return nil
 }
 
+// heartbeatExpired returns true if the heartbeat controller has marked the heartbeat of the SyncTarget
+// as missed. SyncTargets without heartbeat condition yet, e.g. because their syncer is just starting, are
+// considered healthy.
+func heartbeatExpired(syncTarget *workloadv1alpha1.SyncTarget) bool {
+	return conditions.IsFalse(syncTarget, workloadv1alpha1.HeartbeatHealthy)
+}
+
 type apiResourceSchemaApiDefinition struct {
 	apidefinition.APIDefinition
 
@@ -141,14 +163,6 @@ type apiResourceSchemaApiDefinition struct {
 	IdentityHash string
 }
 
-func gvrString(gvr schema.GroupVersionResource) string {
-	group := gvr.Group
-	if group == "" {
-		group = "core"
-	}
-	return fmt.Sprintf("%s.%s.%s", gvr.Resource, gvr.Version, group)
-}
-
 // getAllAcceptedResourceSchemas return all resourceSchemas from APIExports defined in this syncTarget filtered by the status.syncedResource
 // of syncTarget such that only resources with accepted state is returned, together with their identityHash.
 func (c *APIReconciler) getAllAcceptedResourceSchemas(syncTarget *workloadv1alpha1.SyncTarget) (map[schema.GroupResource]*apisv1alpha1.APIResourceSchema, map[schema.GroupResource]string, error) {
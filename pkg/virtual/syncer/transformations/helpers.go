@@ -18,11 +18,20 @@ package transformations
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
 
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
 )
 
 // getSyncerViewFields builds a map whose keys are the summarizing field paths,
@@ -72,3 +81,109 @@ func setSyncerViewFields(kcpResource *unstructured.Unstructured, syncTargetKey s
 	kcpResource.SetAnnotations(annotations)
 	return nil
 }
+
+// conflictingFieldManagers returns the sorted list of field managers, other than syncers,
+// that own the given field in the upstream resource, when the upstream value of this field
+// is different from the value reported by the Syncer.
+// It returns nil if the upstream value is the same, since promoting it would be a no-op.
+func conflictingFieldManagers(upstreamResource *unstructured.Unstructured, field Field, syncerViewFieldValue interface{}) ([]string, error) {
+	upstreamFieldValue, exists, err := field.Get(upstreamResource)
+	if err != nil {
+		return nil, err
+	}
+	if !exists || equality.Semantic.DeepEqual(upstreamFieldValue, syncerViewFieldValue) {
+		return nil, nil
+	}
+
+	managers := sets.NewString()
+	for _, entry := range upstreamResource.GetManagedFields() {
+		if shared.IsSyncerFieldManager(entry.Manager) || entry.Manager == shared.StatusCoordinationFieldManager || entry.FieldsV1 == nil {
+			continue
+		}
+		var ownedFields map[string]interface{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &ownedFields); err != nil {
+			return nil, fmt.Errorf("unable to decode managed fields of field manager %q: %w", entry.Manager, err)
+		}
+		if ownsField(ownedFields, strings.Split(field.Path(), ".")) {
+			managers.Insert(entry.Manager)
+		}
+	}
+	return managers.List(), nil
+}
+
+// ownsField returns true if the given FieldsV1 set contains the field with the given path,
+// or any of its sub-fields.
+func ownsField(ownedFields map[string]interface{}, pathElements []string) bool {
+	current := ownedFields
+	for _, element := range pathElements {
+		next, found := current["f:"+element]
+		if !found {
+			return false
+		}
+		if current, found = next.(map[string]interface{}); !found {
+			return false
+		}
+	}
+	return true
+}
+
+// getSyncerFieldConflicts returns the conditions stored in the conflicts.syncer.internal.kcp.dev/<syncTargetKey> annotation.
+func getSyncerFieldConflicts(upstreamResource *unstructured.Unstructured, syncTargetKey string) (conditionsv1alpha1.Conditions, error) {
+	value, found := upstreamResource.GetAnnotations()[v1alpha1.InternalSyncerFieldConflictsAnnotationPrefix+syncTargetKey]
+	if !found {
+		return nil, nil
+	}
+
+	var result conditionsv1alpha1.Conditions
+	if err := json.Unmarshal([]byte(value), &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// setSyncerFieldConflicts reports the given field conflicts as a SyncerFieldConflict condition
+// in the conflicts.syncer.internal.kcp.dev/<syncTargetKey> annotation, or removes the annotation
+// if there is no conflict anymore.
+// The last transition time of an existing condition is kept if its content did not change.
+func setSyncerFieldConflicts(upstreamResource *unstructured.Unstructured, syncTargetKey string, conflicts []string) error {
+	annotations := upstreamResource.GetAnnotations()
+	annotationName := v1alpha1.InternalSyncerFieldConflictsAnnotationPrefix + syncTargetKey
+
+	if len(conflicts) == 0 {
+		if _, found := annotations[annotationName]; found {
+			delete(annotations, annotationName)
+			upstreamResource.SetAnnotations(annotations)
+		}
+		return nil
+	}
+
+	existingConditions, err := getSyncerFieldConflicts(upstreamResource, syncTargetKey)
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(conflicts)
+	condition := conditionsv1alpha1.Condition{
+		Type:    v1alpha1.SyncerFieldConflict,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1alpha1.FieldManagedByAnotherManagerReason,
+		Message: fmt.Sprintf("fields not promoted to the upstream resource: %s", strings.Join(conflicts, "; ")),
+	}
+	condition.LastTransitionTime = metav1.NewTime(time.Now().UTC().Truncate(time.Second))
+	for _, existing := range existingConditions {
+		if existing.Type == condition.Type && existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+
+	annotationValue, err := json.Marshal(conditionsv1alpha1.Conditions{condition})
+	if err != nil {
+		return err
+	}
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[annotationName] = string(annotationValue)
+	upstreamResource.SetAnnotations(annotations)
+	return nil
+}
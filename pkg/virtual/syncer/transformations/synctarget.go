@@ -0,0 +1,226 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transformations
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kcp-dev/kcp/pkg/apis/workload/helpers"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
+)
+
+var _ Transformation = TransformationChain(nil)
+var _ TransformationProvider = TransformationChain(nil)
+
+// TransformationChain is a Transformation that applies the given transformations in order,
+// each one receiving the Syncer View produced by the previous one.
+type TransformationChain []Transformation
+
+func (c TransformationChain) TransformationFor(resource metav1.Object) (Transformation, error) {
+	return c, nil
+}
+
+func (c TransformationChain) ToSyncerView(syncTargetKey string, gvr schema.GroupVersionResource, newUpstreamResource *unstructured.Unstructured, overridenSyncerViewFields map[string]interface{}, requestedSyncing map[string]helpers.SyncIntent) (newSyncerViewResource *unstructured.Unstructured, err error) {
+	newSyncerViewResource = newUpstreamResource
+	for _, t := range c {
+		newSyncerViewResource, err = t.ToSyncerView(syncTargetKey, gvr, newSyncerViewResource, overridenSyncerViewFields, requestedSyncing)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newSyncerViewResource, nil
+}
+
+var _ Transformation = (*SyncTargetTransformation)(nil)
+var _ TransformationProvider = (*SyncTargetTransformation)(nil)
+
+// SyncTargetTransformation applies the transformations declared in the spec of the SyncTarget
+// the resource is synced to.
+type SyncTargetTransformation struct {
+	// GetSyncTarget returns the SyncTarget with the given key, or nil if it doesn't exist.
+	GetSyncTarget func(syncTargetKey string) (*workloadv1alpha1.SyncTarget, error)
+}
+
+func (t *SyncTargetTransformation) TransformationFor(resource metav1.Object) (Transformation, error) {
+	return t, nil
+}
+
+func (t *SyncTargetTransformation) ToSyncerView(syncTargetKey string, gvr schema.GroupVersionResource, newUpstreamResource *unstructured.Unstructured, overridenSyncerViewFields map[string]interface{}, requestedSyncing map[string]helpers.SyncIntent) (newSyncerViewResource *unstructured.Unstructured, err error) {
+	syncTarget, err := t.GetSyncTarget(syncTargetKey)
+	if err != nil {
+		return nil, err
+	}
+	if syncTarget == nil {
+		return newUpstreamResource, nil
+	}
+
+	for _, transformation := range syncTarget.Spec.Transformations {
+		if !transformationAppliesTo(transformation, gvr) {
+			continue
+		}
+		if err := applyResourceTransformation(transformation, gvr, newUpstreamResource); err != nil {
+			return nil, fmt.Errorf("failed to apply transformations of SyncTarget %s: %w", syncTarget.Name, err)
+		}
+	}
+
+	if err := setDownstreamNamespace(syncTarget, newUpstreamResource); err != nil {
+		return nil, fmt.Errorf("failed to name the downstream namespace for SyncTarget %s: %w", syncTarget.Name, err)
+	}
+	return newUpstreamResource, nil
+}
+
+// setDownstreamNamespace tells the syncer the name of the namespace on the physical cluster of a namespaced
+// resource, if the SyncTarget does not use the default namespace naming strategy.
+func setDownstreamNamespace(syncTarget *workloadv1alpha1.SyncTarget, resource *unstructured.Unstructured) error {
+	strategy := syncTarget.Spec.NamespaceNaming
+	if strategy == "" || strategy == workloadv1alpha1.NamespaceNamingHash || resource.GetNamespace() == "" {
+		return nil
+	}
+
+	locator := shared.NewNamespaceLocator(logicalcluster.From(resource), logicalcluster.From(syncTarget), syncTarget.UID, syncTarget.Name, resource.GetNamespace())
+	name, err := shared.DownstreamNamespaceName(strategy, locator)
+	if err != nil {
+		return err
+	}
+	annotations := resource.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[shared.DownstreamNamespaceAnnotation] = name
+	resource.SetAnnotations(annotations)
+	return nil
+}
+
+func transformationAppliesTo(transformation workloadv1alpha1.ResourceTransformation, gvr schema.GroupVersionResource) bool {
+	if len(transformation.Resources) == 0 {
+		return true
+	}
+	for _, gr := range transformation.Resources {
+		if gr.Group == gvr.Group && gr.Resource == gvr.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// podTemplatePaths are the paths of the pod specs tolerations are added to.
+var podTemplatePaths = [][]string{
+	{"spec", "template", "spec"},
+	{"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+func applyResourceTransformation(transformation workloadv1alpha1.ResourceTransformation, gvr schema.GroupVersionResource, resource *unstructured.Unstructured) error {
+	for _, field := range transformation.RemoveFields {
+		path := strings.Split(field, ".")
+		if path[0] == "metadata" {
+			return fmt.Errorf("removing metadata field %q is not allowed", field)
+		}
+		unstructured.RemoveNestedField(resource.Object, path...)
+	}
+
+	if len(transformation.Labels) > 0 {
+		labels := resource.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string, len(transformation.Labels))
+		}
+		for k, v := range transformation.Labels {
+			labels[k] = v
+		}
+		resource.SetLabels(labels)
+	}
+
+	if len(transformation.Annotations) > 0 {
+		annotations := resource.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, len(transformation.Annotations))
+		}
+		for k, v := range transformation.Annotations {
+			annotations[k] = v
+		}
+		resource.SetAnnotations(annotations)
+	}
+
+	if len(transformation.Tolerations) == 0 {
+		return nil
+	}
+	if gvr.Group == "" && gvr.Resource == "pods" {
+		return addTolerations(resource, transformation.Tolerations, "spec")
+	}
+	for _, path := range podTemplatePaths {
+		if _, found, err := unstructured.NestedMap(resource.Object, path...); err != nil {
+			return err
+		} else if found {
+			return addTolerations(resource, transformation.Tolerations, path...)
+		}
+	}
+	return nil
+}
+
+// addTolerations adds the given tolerations to the pod spec at the given path,
+// skipping those already matched by an existing toleration.
+func addTolerations(resource *unstructured.Unstructured, tolerations []corev1.Toleration, podSpecPath ...string) error {
+	tolerationsPath := append(append([]string{}, podSpecPath...), "tolerations")
+	existing, _, err := unstructured.NestedSlice(resource.Object, tolerationsPath...)
+	if err != nil {
+		return err
+	}
+
+	var existingTolerations []corev1.Toleration
+	for _, e := range existing {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected toleration type %T at %s", e, strings.Join(tolerationsPath, "."))
+		}
+		var toleration corev1.Toleration
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &toleration); err != nil {
+			return err
+		}
+		existingTolerations = append(existingTolerations, toleration)
+	}
+
+	for i := range tolerations {
+		toleration := tolerations[i]
+		matched := false
+		for j := range existingTolerations {
+			if existingTolerations[j].MatchToleration(&toleration) {
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&toleration)
+		if err != nil {
+			return err
+		}
+		existing = append(existing, u)
+		existingTolerations = append(existingTolerations, toleration)
+	}
+
+	return unstructured.SetNestedSlice(resource.Object, existing, tolerationsPath...)
+}
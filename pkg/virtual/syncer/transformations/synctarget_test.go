@@ -0,0 +1,231 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transformations
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/workload/helpers"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+func TestSyncTargetTransformation(t *testing.T) {
+	deploymentsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	podsGVR := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+	tests := map[string]struct {
+		transformations []workloadv1alpha1.ResourceTransformation
+		namespaceNaming workloadv1alpha1.NamespaceNamingStrategy
+		gvr             schema.GroupVersionResource
+		resource        map[string]interface{}
+		expected        map[string]interface{}
+		wantErr         bool
+	}{
+		"no transformations": {
+			gvr: deploymentsGVR,
+			resource: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+			},
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+			},
+		},
+		"labels, annotations and removed fields": {
+			transformations: []workloadv1alpha1.ResourceTransformation{{
+				RemoveFields: []string{"spec.paused"},
+				Labels:       map[string]string{"team": "a"},
+				Annotations:  map[string]string{"owner": "b"},
+			}},
+			gvr: deploymentsGVR,
+			resource: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":   "foo",
+					"labels": map[string]interface{}{"app": "foo"},
+				},
+				"spec": map[string]interface{}{"paused": true, "replicas": int64(1)},
+			},
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":        "foo",
+					"labels":      map[string]interface{}{"app": "foo", "team": "a"},
+					"annotations": map[string]interface{}{"owner": "b"},
+				},
+				"spec": map[string]interface{}{"replicas": int64(1)},
+			},
+		},
+		"transformation for another resource": {
+			transformations: []workloadv1alpha1.ResourceTransformation{{
+				Resources: []apisv1alpha1.GroupResource{{Resource: "pods"}},
+				Labels:    map[string]string{"team": "a"},
+			}},
+			gvr: deploymentsGVR,
+			resource: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+			},
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+			},
+		},
+		"tolerations added to pod template": {
+			transformations: []workloadv1alpha1.ResourceTransformation{{
+				Tolerations: []corev1.Toleration{
+					{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "kcp", Effect: corev1.TaintEffectNoSchedule},
+					{Key: "existing", Operator: corev1.TolerationOpExists},
+				},
+			}},
+			gvr: deploymentsGVR,
+			resource: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"tolerations": []interface{}{
+								map[string]interface{}{"key": "existing", "operator": "Exists"},
+							},
+						},
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"tolerations": []interface{}{
+								map[string]interface{}{"key": "existing", "operator": "Exists"},
+								map[string]interface{}{"key": "dedicated", "operator": "Equal", "value": "kcp", "effect": "NoSchedule"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"tolerations added to pod": {
+			transformations: []workloadv1alpha1.ResourceTransformation{{
+				Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+			}},
+			gvr: podsGVR,
+			resource: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+				"spec":     map[string]interface{}{},
+			},
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+				"spec": map[string]interface{}{
+					"tolerations": []interface{}{
+						map[string]interface{}{"key": "dedicated", "operator": "Exists"},
+					},
+				},
+			},
+		},
+		"downstream namespace of a namespaced resource": {
+			namespaceNaming: workloadv1alpha1.NamespaceNamingWorkspacePrefixed,
+			gvr:             deploymentsGVR,
+			resource: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":        "foo",
+					"namespace":   "default",
+					"annotations": map[string]interface{}{"kcp.dev/cluster": "root:org:team"},
+				},
+			},
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "default",
+					"annotations": map[string]interface{}{
+						"kcp.dev/cluster":              "root:org:team",
+						"kcp.dev/downstream-namespace": "root-org-team-default",
+					},
+				},
+			},
+		},
+		"no downstream namespace with the hash strategy": {
+			namespaceNaming: workloadv1alpha1.NamespaceNamingHash,
+			gvr:             deploymentsGVR,
+			resource: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo", "namespace": "default"},
+			},
+			expected: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo", "namespace": "default"},
+			},
+		},
+		"removing metadata is rejected": {
+			transformations: []workloadv1alpha1.ResourceTransformation{{
+				RemoveFields: []string{"metadata.labels"},
+			}},
+			gvr: deploymentsGVR,
+			resource: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "foo"},
+			},
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			transformation := &SyncTargetTransformation{
+				GetSyncTarget: func(syncTargetKey string) (*workloadv1alpha1.SyncTarget, error) {
+					require.Equal(t, "key", syncTargetKey)
+					return &workloadv1alpha1.SyncTarget{
+						Spec: workloadv1alpha1.SyncTargetSpec{Transformations: tc.transformations, NamespaceNaming: tc.namespaceNaming},
+					}, nil
+				},
+			}
+			result, err := transformation.ToSyncerView("key", tc.gvr, &unstructured.Unstructured{Object: tc.resource}, nil, nil)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result.Object)
+		})
+	}
+}
+
+func TestTransformationChain(t *testing.T) {
+	var calls []string
+	recorder := func(name string, err error) Transformation {
+		return transformationFunc(func(resource *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			calls = append(calls, name)
+			return resource, err
+		})
+	}
+
+	chain := TransformationChain{recorder("first", nil), recorder("second", nil)}
+	_, err := chain.ToSyncerView("key", schema.GroupVersionResource{}, &unstructured.Unstructured{}, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second"}, calls)
+
+	calls = nil
+	chain = TransformationChain{recorder("first", errors.New("boom")), recorder("second", nil)}
+	_, err = chain.ToSyncerView("key", schema.GroupVersionResource{}, &unstructured.Unstructured{}, nil, nil)
+	require.Error(t, err)
+	require.Equal(t, []string{"first"}, calls)
+}
+
+type transformationFunc func(resource *unstructured.Unstructured) (*unstructured.Unstructured, error)
+
+func (f transformationFunc) ToSyncerView(_ string, _ schema.GroupVersionResource, upstreamResource *unstructured.Unstructured, _ map[string]interface{}, _ map[string]helpers.SyncIntent) (*unstructured.Unstructured, error) {
+	return f(upstreamResource)
+}
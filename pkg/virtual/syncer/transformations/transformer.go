@@ -225,6 +225,7 @@ func (rt *SyncerResourceTransformer) BeforeWrite(client dynamic.ResourceInterfac
 
 		if annotations := existingUpstreamResource.GetAnnotations(); annotations != nil {
 			delete(annotations, v1alpha1.InternalSyncerViewAnnotationPrefix+syncTargetKey)
+			delete(annotations, v1alpha1.InternalSyncerFieldConflictsAnnotationPrefix+syncTargetKey)
 			delete(annotations, v1alpha1.InternalClusterDeletionTimestampAnnotationPrefix+syncTargetKey)
 			delete(annotations, v1alpha1.ClusterSpecDiffAnnotationPrefix)
 			existingUpstreamResource.SetAnnotations(annotations)
@@ -314,6 +315,7 @@ func (rt *SyncerResourceTransformer) BeforeWrite(client dynamic.ResourceInterfac
 	}
 
 	syncerViewFields := make(map[string]interface{})
+	var fieldConflicts []string
 
 	statusSubresource := len(subresources) == 1 && subresources[0] == "status"
 	for _, field := range fieldsToSummarize {
@@ -323,6 +325,18 @@ func (rt *SyncerResourceTransformer) BeforeWrite(client dynamic.ResourceInterfac
 			if existingValue, exists := existingSyncerViewFields[field.Path()]; exists {
 				logger.Info("keeping the previous syncer view field value")
 				syncerViewFields[field.Path()] = existingValue
+
+				if field.CanPromoteToUpstream() && len(newSyncing) == 1 && existingValue != promotedToUpstream {
+					// The previous value could not be promoted: keep reporting the conflict as long as it exists.
+					conflictingManagers, err := conflictingFieldManagers(existingUpstreamResource, field, existingValue)
+					if err != nil {
+						logger.Error(err, errorMessage)
+						return nil, kerrors.NewInternalError(fmt.Errorf("unable to check field managers of field %s on upstream resource %s|%s/%s for SyncTarget %s: %w", field.Path(), logicalcluster.From(existingUpstreamResource), existingUpstreamResource.GetNamespace(), existingUpstreamResource.GetName(), syncTargetKey, err))
+					}
+					if len(conflictingManagers) > 0 {
+						fieldConflicts = append(fieldConflicts, fmt.Sprintf("%s (managed by %s)", field.Path(), strings.Join(conflictingManagers, ", ")))
+					}
+				}
 			}
 			continue
 		}
@@ -339,7 +353,20 @@ func (rt *SyncerResourceTransformer) BeforeWrite(client dynamic.ResourceInterfac
 		}
 
 		if field.CanPromoteToUpstream() {
+			var conflictingManagers []string
 			if len(newSyncing) == 1 {
+				conflictingManagers, err = conflictingFieldManagers(existingUpstreamResource, field, syncerViewFieldValue)
+				if err != nil {
+					logger.Error(err, errorMessage)
+					return nil, kerrors.NewInternalError(fmt.Errorf("unable to check field managers of field %s on upstream resource %s|%s/%s for SyncTarget %s: %w", field.Path(), logicalcluster.From(existingUpstreamResource), existingUpstreamResource.GetNamespace(), existingUpstreamResource.GetName(), syncTargetKey, err))
+				}
+			}
+			if len(conflictingManagers) > 0 {
+				// The upstream value is owned by someone else than the syncers: don't silently overwrite it,
+				// but keep the syncer value in the syncer view, and report the conflict.
+				logger.Info("resource is scheduled on a single syncTarget, but the field is managed by other field managers with a different value => don't promote the field", "managers", conflictingManagers)
+				fieldConflicts = append(fieldConflicts, fmt.Sprintf("%s (managed by %s)", field.Path(), strings.Join(conflictingManagers, ", ")))
+			} else if len(newSyncing) == 1 {
 				logger.Info("resource is scheduled on a single syncTarget => promote the field")
 				// There is only one SyncTarget, so let's promote the field value to the upstream resource
 				if err := field.Set(existingUpstreamResource, syncerViewFieldValue); err != nil {
@@ -362,6 +389,11 @@ func (rt *SyncerResourceTransformer) BeforeWrite(client dynamic.ResourceInterfac
 		return nil, kerrors.NewInternalError(fmt.Errorf("unable to set syncer view fields on upstream resource %s|%s/%s for SyncTarget %s: %w", logicalcluster.From(existingUpstreamResource), existingUpstreamResource.GetNamespace(), existingUpstreamResource.GetName(), syncTargetKey, err))
 	}
 
+	if err := setSyncerFieldConflicts(existingUpstreamResource, syncTargetKey, fieldConflicts); err != nil {
+		logger.Error(err, errorMessage)
+		return nil, kerrors.NewInternalError(fmt.Errorf("unable to set syncer field conflicts on upstream resource %s|%s/%s for SyncTarget %s: %w", logicalcluster.From(existingUpstreamResource), existingUpstreamResource.GetNamespace(), existingUpstreamResource.GetName(), syncTargetKey, err))
+	}
+
 	if syncerViewHasSyncerFinalizer && !upstreamObjectSyncerFinalizers.Has(syncerFinalizerName) {
 		logger.Info("adding the syncer finalizer to the upstream resource")
 		existingUpstreamResource.SetFinalizers(sets.NewString(append(existingUpstreamResource.GetFinalizers(), syncerFinalizerName)...).List())
@@ -417,15 +449,32 @@ func (rt *SyncerResourceTransformer) AfterRead(_ dynamic.ResourceInterface, ctx
 
 	logger.Info("cleaning the upstream resource before calling the transformation")
 
-	// Remove the syncer view diff annotation from the syncer view resource
+	// Remove the syncer view diff annotation from the syncer view resource, as well as the per-SyncTarget
+	// annotations of other SyncTargets, such that each SyncTarget only gets its own copy of the resource.
 	annotations := cleanedUpstreamResource.GetAnnotations()
 	for name := range annotations {
-		if strings.HasPrefix(name, v1alpha1.InternalSyncerViewAnnotationPrefix) {
+		if strings.HasPrefix(name, v1alpha1.InternalSyncerViewAnnotationPrefix) || strings.HasPrefix(name, v1alpha1.InternalSyncerFieldConflictsAnnotationPrefix) ||
+			isOfOtherSyncTarget(name, syncTargetKey, perSyncTargetAnnotationPrefixes...) {
 			delete(annotations, name)
 		}
 	}
 	cleanedUpstreamResource.SetAnnotations(annotations)
 
+	// Remove the state labels of other SyncTargets. They would otherwise leak to the downstream
+	// resource, and cause updates downstream whenever the resource state changes for another SyncTarget.
+	if labels := cleanedUpstreamResource.GetLabels(); labels != nil {
+		removed := false
+		for name := range labels {
+			if isOfOtherSyncTarget(name, syncTargetKey, v1alpha1.ClusterResourceStateLabelPrefix) {
+				delete(labels, name)
+				removed = true
+			}
+		}
+		if removed {
+			cleanedUpstreamResource.SetLabels(labels)
+		}
+	}
+
 	transformedSyncerViewResource := cleanedUpstreamResource
 	if transformation, err := rt.TransformationFor(upstreamResource); err != nil {
 		logger.Error(err, errorMessage)
@@ -512,3 +561,23 @@ func (rt *SyncerResourceTransformer) AfterRead(_ dynamic.ResourceInterface, ctx
 	logger.Info("resource transformed")
 	return transformedSyncerViewResource, nil
 }
+
+// perSyncTargetAnnotationPrefixes are the prefixes of the annotations that are suffixed by a SyncTarget key
+// and only meaningful to the Syncer of that SyncTarget.
+var perSyncTargetAnnotationPrefixes = []string{
+	v1alpha1.InternalClusterDeletionTimestampAnnotationPrefix,
+	v1alpha1.ClusterFinalizerAnnotationPrefix,
+	v1alpha1.InternalClusterStatusAnnotationPrefix,
+	v1alpha1.ClusterSpecDiffAnnotationPrefix,
+}
+
+// isOfOtherSyncTarget returns whether name is one of the given prefixes followed by a SyncTarget key
+// other than syncTargetKey.
+func isOfOtherSyncTarget(name, syncTargetKey string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return strings.TrimPrefix(name, prefix) != syncTargetKey
+		}
+	}
+	return false
+}
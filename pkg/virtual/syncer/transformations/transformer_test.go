@@ -293,6 +293,22 @@ func (rb resourceBuilder) field(name string, value interface{}) resourceBuilder
 	}
 }
 
+func (rb resourceBuilder) managedFields(manager, fields string) resourceBuilder {
+	return func() *unstructured.Unstructured {
+		r := rb()
+		managedFields := r.GetManagedFields()
+		managedFields = append(managedFields, metav1.ManagedFieldsEntry{
+			Manager:    manager,
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			APIVersion: r.GetAPIVersion(),
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+		})
+		r.SetManagedFields(managedFields)
+		return r
+	}
+}
+
 func resource(apiVersion, kind, name string) resourceBuilder {
 	return func() *unstructured.Unstructured {
 		return &unstructured.Unstructured{
@@ -337,6 +353,8 @@ func (msr *mockedSummarizingRules) SummarizingRulesFor(resource metav1.Object) (
 
 var deletionTimestamp = metav1.Now()
 
+var syncerFieldConflictsAnnotation = `[{"type":"SyncerFieldConflict","status":"True","lastTransitionTime":"2022-11-01T00:00:00Z","reason":"FieldManagedByAnotherManager","message":"fields not promoted to the upstream resource: status (managed by user-controller)"}]`
+
 func TestSyncerResourceTransformer(t *testing.T) {
 	testCases := []struct {
 		name                  string
@@ -408,6 +426,37 @@ func TestSyncerResourceTransformer(t *testing.T) {
 				annotation("deletion.internal.workload.kcp.dev/syncTargetKey", deletionTimestamp.Format(time.RFC3339)).
 				deletionTimestamp(&deletionTimestamp)(),
 		},
+		{
+			name:          "get - scheduled to multiple synctargets - other synctargets removed",
+			gvr:           gvr("group", "version", "resources"),
+			synctargetKey: "syncTargetKey",
+			availableResources: []runtime.Object{
+				resource("group/version", "Resource", "aThing").
+					label("app", "test").
+					label("state.workload.kcp.dev/syncTargetKey", "Sync").
+					label("state.workload.kcp.dev/syncTargetKey2", "Sync").
+					annotation("experimental.spec-diff.workload.kcp.dev/syncTargetKey", `[{"op":"replace","path":"/replicas","value":2}]`).
+					annotation("experimental.spec-diff.workload.kcp.dev/syncTargetKey2", `[{"op":"replace","path":"/replicas","value":3}]`).
+					annotation("finalizers.workload.kcp.dev/syncTargetKey2", "custom").
+					annotation("deletion.internal.workload.kcp.dev/syncTargetKey2", deletionTimestamp.Format(time.RFC3339))(),
+			},
+			action: func(ctx context.Context, transformingClient dynamic.NamespaceableResourceInterface) (result interface{}, err error) {
+				return transformingClient.Get(ctx, "aThing", metav1.GetOptions{})
+			},
+			expectedClientActions: []clienttesting.Action{
+				clienttesting.GetActionImpl{
+					ActionImpl: clienttesting.ActionImpl{
+						Verb:     "get",
+						Resource: gvr("group", "version", "resources"),
+					},
+					Name: "aThing",
+				},
+			},
+			expectedResult: resource("group/version", "Resource", "aThing").
+				label("app", "test").
+				label("state.workload.kcp.dev/syncTargetKey", "Sync").
+				annotation("experimental.spec-diff.workload.kcp.dev/syncTargetKey", `[{"op":"replace","path":"/replicas","value":2}]`)(),
+		},
 		{
 			name:          "update status - no promote - success",
 			gvr:           gvr("group", "version", "resources"),
@@ -533,6 +582,102 @@ func TestSyncerResourceTransformer(t *testing.T) {
 				field("status", map[string]interface{}{"statusField": "updated"}).
 				field("added", "value")(),
 		},
+		{
+			name:          "update status - promote - conflict with another field manager",
+			gvr:           gvr("group", "version", "resources"),
+			synctargetKey: "syncTargetKey",
+			availableResources: []runtime.Object{
+				resource("group/version", "Resource", "aThing").
+					managedFields("user-controller", `{"f:status":{"f:statusField":{}}}`).
+					label("state.workload.kcp.dev/syncTargetKey", "Sync").
+					annotation("conflicts.syncer.internal.kcp.dev/syncTargetKey", syncerFieldConflictsAnnotation).
+					field("status", map[string]interface{}{"statusField": "fromUser"})(),
+			},
+			action: func(ctx context.Context, transformingClient dynamic.NamespaceableResourceInterface) (result interface{}, err error) {
+				return transformingClient.UpdateStatus(ctx, resource("group/version", "Resource", "aThing").
+					finalizer("workload.kcp.dev/syncer-syncTargetKey").
+					field("status", map[string]interface{}{"statusField": "updated"})(), metav1.UpdateOptions{})
+			},
+			fieldsToSummarize: []field{{FieldPath: "status", PromoteToUpstream: true}},
+			expectedClientActions: []clienttesting.Action{
+				clienttesting.GetActionImpl{
+					ActionImpl: clienttesting.ActionImpl{
+						Verb:     "get",
+						Resource: gvr("group", "version", "resources"),
+					},
+					Name: "aThing",
+				},
+				clienttesting.UpdateActionImpl{
+					ActionImpl: clienttesting.ActionImpl{
+						Verb:        "update",
+						Resource:    gvr("group", "version", "resources"),
+						Subresource: "status",
+					},
+					Object: resource("group/version", "Resource", "aThing").
+						managedFields("user-controller", `{"f:status":{"f:statusField":{}}}`).
+						finalizer("workload.kcp.dev/syncer-syncTargetKey").
+						label("state.workload.kcp.dev/syncTargetKey", "Sync").
+						annotation("conflicts.syncer.internal.kcp.dev/syncTargetKey", syncerFieldConflictsAnnotation).
+						annotation("diff.syncer.internal.kcp.dev/syncTargetKey", `{"status":{"statusField":"updated"}}`).
+						field("status", map[string]interface{}{"statusField": "fromUser"})(),
+				},
+			},
+			expectedResult: resource("group/version", "Resource", "aThing").
+				managedFields("user-controller", `{"f:status":{"f:statusField":{}}}`).
+				finalizer("workload.kcp.dev/syncer-syncTargetKey").
+				label("state.workload.kcp.dev/syncTargetKey", "Sync").
+				annotations().
+				field("status", map[string]interface{}{"statusField": "updated"})(),
+		},
+		{
+			name:          "update status - promote - same value as another field manager clears the conflict",
+			gvr:           gvr("group", "version", "resources"),
+			synctargetKey: "syncTargetKey",
+			availableResources: []runtime.Object{
+				resource("group/version", "Resource", "aThing").
+					managedFields("user-controller", `{"f:status":{"f:statusField":{}}}`).
+					managedFields("syncer-otherSyncTargetKey", `{"f:status":{"f:statusField":{}}}`).
+					label("state.workload.kcp.dev/syncTargetKey", "Sync").
+					annotation("conflicts.syncer.internal.kcp.dev/syncTargetKey", syncerFieldConflictsAnnotation).
+					field("status", map[string]interface{}{"statusField": "updated"})(),
+			},
+			action: func(ctx context.Context, transformingClient dynamic.NamespaceableResourceInterface) (result interface{}, err error) {
+				return transformingClient.UpdateStatus(ctx, resource("group/version", "Resource", "aThing").
+					finalizer("workload.kcp.dev/syncer-syncTargetKey").
+					field("status", map[string]interface{}{"statusField": "updated"})(), metav1.UpdateOptions{})
+			},
+			fieldsToSummarize: []field{{FieldPath: "status", PromoteToUpstream: true}},
+			expectedClientActions: []clienttesting.Action{
+				clienttesting.GetActionImpl{
+					ActionImpl: clienttesting.ActionImpl{
+						Verb:     "get",
+						Resource: gvr("group", "version", "resources"),
+					},
+					Name: "aThing",
+				},
+				clienttesting.UpdateActionImpl{
+					ActionImpl: clienttesting.ActionImpl{
+						Verb:        "update",
+						Resource:    gvr("group", "version", "resources"),
+						Subresource: "status",
+					},
+					Object: resource("group/version", "Resource", "aThing").
+						managedFields("user-controller", `{"f:status":{"f:statusField":{}}}`).
+						managedFields("syncer-otherSyncTargetKey", `{"f:status":{"f:statusField":{}}}`).
+						finalizer("workload.kcp.dev/syncer-syncTargetKey").
+						label("state.workload.kcp.dev/syncTargetKey", "Sync").
+						annotation("diff.syncer.internal.kcp.dev/syncTargetKey", `{"status":"##promoted##"}`).
+						field("status", map[string]interface{}{"statusField": "updated"})(),
+				},
+			},
+			expectedResult: resource("group/version", "Resource", "aThing").
+				managedFields("user-controller", `{"f:status":{"f:statusField":{}}}`).
+				managedFields("syncer-otherSyncTargetKey", `{"f:status":{"f:statusField":{}}}`).
+				finalizer("workload.kcp.dev/syncer-syncTargetKey").
+				label("state.workload.kcp.dev/syncTargetKey", "Sync").
+				annotations().
+				field("status", map[string]interface{}{"statusField": "updated"})(),
+		},
 		{
 			name:          "update status - unpromote when new synctarget joining - success",
 			gvr:           gvr("group", "version", "resources"),
@@ -581,7 +726,6 @@ func TestSyncerResourceTransformer(t *testing.T) {
 			expectedResult: resource("group/version", "Resource", "aThing").
 				finalizer("workload.kcp.dev/syncer-syncTargetKey").
 				label("state.workload.kcp.dev/syncTargetKey", "Sync").
-				label("state.workload.kcp.dev/syncTargetKey2", "").
 				annotations().
 				field("status", map[string]interface{}{"statusField": "updated"}).
 				field("added", "value")(),
@@ -739,7 +883,6 @@ func TestSyncerResourceTransformer(t *testing.T) {
 				},
 			},
 			expectedResult: resource("group/version", "Resource", "aThing").labels().annotations().
-				label("state.workload.kcp.dev/syncTargetKey2", "Sync").
 				field("added", "value")(),
 		},
 		{
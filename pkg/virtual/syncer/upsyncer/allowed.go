@@ -0,0 +1,38 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upsyncer
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+// IsUpsyncAllowed returns whether the syncer of the given SyncTarget may upsync the given resource.
+// Persistentvolumes can always be upsynced, other resources only when listed in the upsyncedResources
+// of the SyncTarget.
+func IsUpsyncAllowed(syncTarget *workloadv1alpha1.SyncTarget, apiGroupResource schema.GroupResource) bool {
+	if apiGroupResource.Group == "" && apiGroupResource.Resource == "persistentvolumes" {
+		return true
+	}
+	for _, gr := range syncTarget.Spec.UpsyncedResources {
+		if gr.Group == apiGroupResource.Group && gr.Resource == apiGroupResource.Resource {
+			return true
+		}
+	}
+	return false
+}
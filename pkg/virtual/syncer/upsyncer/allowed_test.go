@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upsyncer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+func TestIsUpsyncAllowed(t *testing.T) {
+	syncTarget := &workloadv1alpha1.SyncTarget{
+		Spec: workloadv1alpha1.SyncTargetSpec{
+			UpsyncedResources: []apisv1alpha1.GroupResource{
+				{Resource: "pods"},
+				{Group: "example.com", Resource: "widgets"},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		gr       schema.GroupResource
+		expected bool
+	}{
+		"persistentvolumes are always allowed": {gr: schema.GroupResource{Resource: "persistentvolumes"}, expected: true},
+		"listed core resource":                 {gr: schema.GroupResource{Resource: "pods"}, expected: true},
+		"listed resource with group":           {gr: schema.GroupResource{Group: "example.com", Resource: "widgets"}, expected: true},
+		"same resource in another group":       {gr: schema.GroupResource{Group: "other.com", Resource: "widgets"}, expected: false},
+		"unlisted resource":                    {gr: schema.GroupResource{Group: "apps", Resource: "deployments"}, expected: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, IsUpsyncAllowed(syncTarget, tc.gr))
+		})
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/pkg/cache"
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/syncer/shared"
+	"github.com/kcp-dev/kcp/tmc/pkg/coordination"
+)
+
+const (
+	ControllerName = "kcp-status-coordination"
+)
+
+// NewController returns a new Controller which aggregates, on the upstream resources synced to
+// several SyncTargets, the statuses reported by the Syncers of all those SyncTargets.
+// Only the resources whose GVR has a StatusMergingStrategy are considered.
+func NewController(
+	dynamicClusterClient kcpdynamic.ClusterInterface,
+	ddsif *informer.DynamicDiscoverySharedInformerFactory,
+	strategies map[schema.GroupVersionResource]StatusMergingStrategy,
+) (*Controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+
+	c := &Controller{
+		queue: queue,
+
+		getStrategy: func(gvr schema.GroupVersionResource) (StatusMergingStrategy, bool) {
+			strategy, found := strategies[gvr]
+			return strategy, found
+		},
+
+		syncerViewRetriever: coordination.NewDefaultSyncerViewManager[*unstructured.Unstructured](),
+
+		updateStatus: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+			_, err := dynamicClusterClient.Cluster(clusterName).Resource(gvr).Namespace(obj.GetNamespace()).UpdateStatus(ctx, obj, metav1.UpdateOptions{FieldManager: shared.StatusCoordinationFieldManager})
+			return err
+		},
+
+		ddsif: ddsif,
+	}
+
+	ddsif.AddEventHandler(informer.GVREventHandlerFuncs{
+		AddFunc: func(gvr schema.GroupVersionResource, obj interface{}) { c.enqueue(gvr, obj) },
+		UpdateFunc: func(gvr schema.GroupVersionResource, old, obj interface{}) {
+			oldUnstr, ok := old.(*unstructured.Unstructured)
+			if !ok {
+				return
+			}
+			newUnstr, ok := obj.(*unstructured.Unstructured)
+			if !ok {
+				return
+			}
+			if coordination.AnySyncerViewChanged(oldUnstr, newUnstr) ||
+				!reflect.DeepEqual(stateLabels(oldUnstr.GetLabels()), stateLabels(newUnstr.GetLabels())) {
+				c.enqueue(gvr, obj)
+			}
+		},
+		DeleteFunc: nil, // Nothing to do.
+	})
+
+	return c, nil
+}
+
+// Controller aggregates the status of resources synced to several SyncTargets.
+type Controller struct {
+	queue workqueue.RateLimitingInterface
+
+	getStrategy         func(gvr schema.GroupVersionResource) (StatusMergingStrategy, bool)
+	syncerViewRetriever coordination.SyncerViewRetriever[*unstructured.Unstructured]
+	updateStatus        func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error
+
+	ddsif *informer.DynamicDiscoverySharedInformerFactory
+}
+
+func stateLabels(ls map[string]string) map[string]string {
+	ret := make(map[string]string, len(ls))
+	for k, v := range ls {
+		if strings.HasPrefix(k, workloadv1alpha1.ClusterResourceStateLabelPrefix) {
+			ret[k] = v
+		}
+	}
+	return ret
+}
+
+func (c *Controller) enqueue(gvr schema.GroupVersionResource, obj interface{}) {
+	if _, found := c.getStrategy(gvr); !found {
+		return
+	}
+	key, err := kcpcache.MetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	queueKey := strings.Join([]string{gvr.Resource, gvr.Version, gvr.Group}, ".") + "::" + key
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), queueKey)
+	logger.V(2).Info("queueing resource")
+	c.queue.Add(queueKey)
+}
+
+func (c *Controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.Until(func() { c.startWorker(ctx) }, time.Second, ctx.Done())
+	}
+	<-ctx.Done()
+}
+
+func (c *Controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(1).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// key is gvr::KEY
+func (c *Controller) process(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+	parts := strings.SplitN(key, "::", 2)
+	if len(parts) != 2 {
+		logger.Info("error parsing key; dropping")
+		return nil
+	}
+	gvrstr := parts[0]
+	logger = logger.WithValues("gvr", gvrstr)
+	gvr, _ := schema.ParseResourceArg(gvrstr)
+	if gvr == nil {
+		logger.Info("error parsing GVR; dropping")
+		return nil
+	}
+	key = parts[1]
+	logger = logger.WithValues("objectKey", key)
+
+	inf, err := c.ddsif.ForResource(*gvr)
+	if err != nil {
+		return err
+	}
+
+	clusterName, namespace, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		logger.Error(err, "failed to split key, dropping")
+		return nil
+	}
+	obj, err := inf.Lister().ByCluster(clusterName).ByNamespace(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		logger.Error(err, "error getting object from indexer")
+		return err
+	}
+	unstr, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		logger.WithValues("objectType", fmt.Sprintf("%T", obj)).Info("object was not Unstructured, dropping")
+		return nil
+	}
+	unstr = unstr.DeepCopy()
+
+	return c.reconcile(klog.NewContext(ctx, logger), clusterName, *gvr, unstr)
+}
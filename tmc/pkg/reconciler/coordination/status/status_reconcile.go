@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/apis/workload/helpers"
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+)
+
+// reconcile sets the status of the upstream resource to the merge of the statuses of all the
+// SyncTargets the resource is synced to. Resources synced to a single SyncTarget are left
+// untouched: their status is promoted to the upstream resource by the Syncer itself.
+func (c *Controller) reconcile(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	logger := klog.FromContext(ctx)
+
+	strategy, found := c.getStrategy(gvr)
+	if !found {
+		return nil
+	}
+
+	syncIntents, err := helpers.GetSyncIntents(obj)
+	if err != nil {
+		return err
+	}
+	syncing := sets.NewString()
+	for syncTargetKey, syncIntent := range syncIntents {
+		if syncIntent.ResourceState == workloadv1alpha1.ResourceStateSync && syncIntent.DeletionTimestamp == nil {
+			syncing.Insert(syncTargetKey)
+		}
+	}
+	if syncing.Len() < 2 {
+		return nil
+	}
+
+	syncerViews, err := c.syncerViewRetriever.GetFilteredSyncerViews(ctx, gvr, obj, syncing.Has)
+	if err != nil {
+		return err
+	}
+	statuses := make(map[string]map[string]interface{}, len(syncerViews))
+	for syncTargetKey, syncerView := range syncerViews {
+		status, found, err := unstructured.NestedMap(syncerView.Object, "status")
+		if err != nil {
+			return fmt.Errorf("invalid status reported for SyncTarget %s: %w", syncTargetKey, err)
+		}
+		if found {
+			statuses[syncTargetKey] = status
+		}
+	}
+	if len(statuses) == 0 {
+		logger.V(4).Info("no SyncTarget reported a status yet")
+		return nil
+	}
+
+	merged, err := strategy.MergeStatus(statuses)
+	if err != nil {
+		return err
+	}
+
+	current, _, _ := unstructured.NestedMap(obj.Object, "status")
+	if equality.Semantic.DeepEqual(current, merged) {
+		return nil
+	}
+
+	logger.WithValues("syncTargets", sets.StringKeySet(statuses).List()).V(2).Info("updating the aggregated status")
+	obj.Object["status"] = merged
+	return c.updateStatus(ctx, clusterName, gvr, obj)
+}
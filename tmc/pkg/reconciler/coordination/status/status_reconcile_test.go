@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
+	"github.com/kcp-dev/kcp/tmc/pkg/coordination"
+)
+
+var deploymentsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+func deployment(labels, annotations map[string]interface{}, status map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":        "test",
+			"namespace":   "default",
+			"labels":      labels,
+			"annotations": annotations,
+		},
+	}}
+	if status != nil {
+		obj.Object["status"] = status
+	}
+	return obj
+}
+
+func TestReconcile(t *testing.T) {
+	tests := map[string]struct {
+		upstream   *unstructured.Unstructured
+		wantStatus map[string]interface{} // nil means no update
+	}{
+		"synced to a single synctarget": {
+			upstream: deployment(
+				map[string]interface{}{workloadv1alpha1.ClusterResourceStateLabelPrefix + "target1": "Sync"},
+				map[string]interface{}{workloadv1alpha1.InternalSyncerViewAnnotationPrefix + "target1": `{"status":{"replicas":2}}`},
+				nil,
+			),
+		},
+		"synced to two synctargets": {
+			upstream: deployment(
+				map[string]interface{}{
+					workloadv1alpha1.ClusterResourceStateLabelPrefix + "target1": "Sync",
+					workloadv1alpha1.ClusterResourceStateLabelPrefix + "target2": "Sync",
+				},
+				map[string]interface{}{
+					workloadv1alpha1.InternalSyncerViewAnnotationPrefix + "target1": `{"status":{"replicas":2,"readyReplicas":2}}`,
+					workloadv1alpha1.InternalSyncerViewAnnotationPrefix + "target2": `{"status":{"replicas":3,"readyReplicas":1}}`,
+				},
+				nil,
+			),
+			wantStatus: map[string]interface{}{
+				"replicas":      int64(5),
+				"readyReplicas": int64(3),
+			},
+		},
+		"aggregated status already up-to-date": {
+			upstream: deployment(
+				map[string]interface{}{
+					workloadv1alpha1.ClusterResourceStateLabelPrefix + "target1": "Sync",
+					workloadv1alpha1.ClusterResourceStateLabelPrefix + "target2": "Sync",
+				},
+				map[string]interface{}{
+					workloadv1alpha1.InternalSyncerViewAnnotationPrefix + "target1": `{"status":{"replicas":2}}`,
+					workloadv1alpha1.InternalSyncerViewAnnotationPrefix + "target2": `{"status":{"replicas":3}}`,
+				},
+				map[string]interface{}{"replicas": int64(5)},
+			),
+		},
+		"second synctarget not synced yet": {
+			upstream: deployment(
+				map[string]interface{}{
+					workloadv1alpha1.ClusterResourceStateLabelPrefix + "target1": "Sync",
+					workloadv1alpha1.ClusterResourceStateLabelPrefix + "target2": "",
+				},
+				map[string]interface{}{
+					workloadv1alpha1.InternalSyncerViewAnnotationPrefix + "target1": `{"status":{"replicas":2}}`,
+				},
+				nil,
+			),
+		},
+		"status of a synctarget being removed is ignored": {
+			upstream: deployment(
+				map[string]interface{}{
+					workloadv1alpha1.ClusterResourceStateLabelPrefix + "target1": "Sync",
+					workloadv1alpha1.ClusterResourceStateLabelPrefix + "target2": "Sync",
+					workloadv1alpha1.ClusterResourceStateLabelPrefix + "target3": "Sync",
+				},
+				map[string]interface{}{
+					workloadv1alpha1.InternalClusterDeletionTimestampAnnotationPrefix + "target3": "2022-10-01T10:00:00Z",
+					workloadv1alpha1.InternalSyncerViewAnnotationPrefix + "target1":               `{"status":{"replicas":2}}`,
+					workloadv1alpha1.InternalSyncerViewAnnotationPrefix + "target2":               `{"status":{"replicas":3}}`,
+					workloadv1alpha1.InternalSyncerViewAnnotationPrefix + "target3":               `{"status":{"replicas":4}}`,
+				},
+				nil,
+			),
+			wantStatus: map[string]interface{}{
+				"replicas": int64(5),
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var updated *unstructured.Unstructured
+			c := &Controller{
+				getStrategy: func(gvr schema.GroupVersionResource) (StatusMergingStrategy, bool) {
+					strategy, found := DefaultStatusMergingStrategies[gvr]
+					return strategy, found
+				},
+				syncerViewRetriever: coordination.NewDefaultSyncerViewManager[*unstructured.Unstructured](),
+				updateStatus: func(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+					updated = obj
+					return nil
+				},
+			}
+
+			err := c.reconcile(context.Background(), logicalcluster.New("root:org:ws"), deploymentsGVR, tc.upstream)
+			require.NoError(t, err)
+
+			if tc.wantStatus == nil {
+				require.Nil(t, updated, "status should not have been updated")
+				return
+			}
+			require.NotNil(t, updated, "status should have been updated")
+			require.Equal(t, tc.wantStatus, updated.Object["status"])
+		})
+	}
+}
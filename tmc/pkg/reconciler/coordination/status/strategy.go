@@ -0,0 +1,215 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// StatusMergingStrategy merges the statuses reported by the Syncers of the SyncTargets
+// a resource is synced to into the aggregated status of the upstream resource.
+type StatusMergingStrategy interface {
+	// MergeStatus returns the aggregated status for the given statuses, indexed by SyncTarget key.
+	MergeStatus(statuses map[string]map[string]interface{}) (map[string]interface{}, error)
+}
+
+// SummingStrategy is a StatusMergingStrategy summing up counters, typically replicas, over
+// all SyncTargets, and merging the conditions of the status.
+type SummingStrategy struct {
+	// Fields are the dot-separated paths inside the status of the integer fields that are
+	// summed up. Fields missing on every SyncTarget are left out of the aggregated status.
+	Fields []string
+
+	// MergeConditions merges status.conditions: a condition has a status if it has that status
+	// on every SyncTarget. Otherwise, its status is Unknown and the message tells how many
+	// SyncTargets report which status.
+	MergeConditions bool
+}
+
+var _ StatusMergingStrategy = &SummingStrategy{}
+
+// DefaultStatusMergingStrategies are the strategies used for the resources with replicas that are
+// commonly synced to multiple SyncTargets.
+var DefaultStatusMergingStrategies = map[schema.GroupVersionResource]StatusMergingStrategy{
+	{Group: "apps", Version: "v1", Resource: "deployments"}: &SummingStrategy{
+		Fields:          []string{"replicas", "updatedReplicas", "readyReplicas", "availableReplicas", "unavailableReplicas"},
+		MergeConditions: true,
+	},
+	{Group: "apps", Version: "v1", Resource: "statefulsets"}: &SummingStrategy{
+		Fields:          []string{"replicas", "readyReplicas", "currentReplicas", "updatedReplicas", "availableReplicas"},
+		MergeConditions: true,
+	},
+	{Group: "apps", Version: "v1", Resource: "replicasets"}: &SummingStrategy{
+		Fields:          []string{"replicas", "fullyLabeledReplicas", "readyReplicas", "availableReplicas"},
+		MergeConditions: true,
+	},
+	{Group: "apps", Version: "v1", Resource: "daemonsets"}: &SummingStrategy{
+		Fields:          []string{"currentNumberScheduled", "numberMisscheduled", "desiredNumberScheduled", "numberReady", "updatedNumberScheduled", "numberAvailable", "numberUnavailable"},
+		MergeConditions: true,
+	},
+	{Group: "batch", Version: "v1", Resource: "jobs"}: &SummingStrategy{
+		Fields:          []string{"active", "succeeded", "failed"},
+		MergeConditions: true,
+	},
+}
+
+// MergeStatus implements StatusMergingStrategy.
+func (s *SummingStrategy) MergeStatus(statuses map[string]map[string]interface{}) (map[string]interface{}, error) {
+	keys := make([]string, 0, len(statuses))
+	for key := range statuses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	merged := map[string]interface{}{}
+	for _, field := range s.Fields {
+		path := strings.Split(field, ".")
+		var sum int64
+		found := false
+		for _, key := range keys {
+			value, exists, err := unstructured.NestedFieldNoCopy(statuses[key], path...)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				continue
+			}
+			n, err := toInt64(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid field status.%s reported for SyncTarget %s: %w", field, key, err)
+			}
+			sum += n
+			found = true
+		}
+		if found {
+			if err := unstructured.SetNestedField(merged, sum, path...); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if s.MergeConditions {
+		conditions, err := mergeConditions(keys, statuses)
+		if err != nil {
+			return nil, err
+		}
+		if len(conditions) > 0 {
+			merged["conditions"] = conditions
+		}
+	}
+
+	return merged, nil
+}
+
+// mergeConditions merges the conditions of the given statuses, visiting them in the order of keys.
+func mergeConditions(keys []string, statuses map[string]map[string]interface{}) ([]interface{}, error) {
+	var types []string
+	byType := map[string][]map[string]interface{}{}
+	for _, key := range keys {
+		conditions, _, err := unstructured.NestedSlice(statuses[key], "conditions")
+		if err != nil {
+			return nil, fmt.Errorf("invalid field status.conditions reported for SyncTarget %s: %w", key, err)
+		}
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid condition reported for SyncTarget %s: %v", key, c)
+			}
+			conditionType, _ := condition["type"].(string)
+			if conditionType == "" {
+				continue
+			}
+			if _, seen := byType[conditionType]; !seen {
+				types = append(types, conditionType)
+			}
+			byType[conditionType] = append(byType[conditionType], condition)
+		}
+	}
+
+	merged := make([]interface{}, 0, len(types))
+	for _, conditionType := range types {
+		conditions := byType[conditionType]
+
+		counts := map[string]int{}
+		var reported []string
+		for _, condition := range conditions {
+			status, _ := condition["status"].(string)
+			if counts[status] == 0 {
+				reported = append(reported, status)
+			}
+			counts[status]++
+		}
+
+		// the latest transition of the SyncTargets is the transition of the merged condition
+		result := conditions[0]
+		for _, condition := range conditions[1:] {
+			if lastTransition(condition) > lastTransition(result) {
+				result = condition
+			}
+		}
+		result = copyCondition(result)
+
+		if len(reported) > 1 || len(conditions) < len(keys) {
+			var parts []string
+			for _, status := range reported {
+				parts = append(parts, fmt.Sprintf("%s on %d", status, counts[status]))
+			}
+			if missing := len(keys) - len(conditions); missing > 0 {
+				parts = append(parts, fmt.Sprintf("missing on %d", missing))
+			}
+			result["status"] = "Unknown"
+			result["reason"] = "SyncTargetsDiffer"
+			result["message"] = fmt.Sprintf("%s of %d SyncTargets", strings.Join(parts, ", "), len(keys))
+		}
+		merged = append(merged, result)
+	}
+	return merged, nil
+}
+
+// lastTransition returns the lastTransitionTime of the condition, which compares in time order as RFC3339 in UTC.
+func lastTransition(condition map[string]interface{}) string {
+	t, _ := condition["lastTransitionTime"].(string)
+	return t
+}
+
+func copyCondition(condition map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(condition))
+	for k, v := range condition {
+		result[k] = v
+	}
+	return result
+}
+
+func toInt64(value interface{}) (int64, error) {
+	switch n := value.(type) {
+	case int64:
+		return n, nil
+	case int32:
+		return int64(n), nil
+	case int:
+		return int64(n), nil
+	case float64:
+		return int64(n), nil
+	default:
+		return 0, fmt.Errorf("expected an integer, got %T", value)
+	}
+}
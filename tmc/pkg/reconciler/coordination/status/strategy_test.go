@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummingStrategyMergeStatus(t *testing.T) {
+	tests := map[string]struct {
+		strategy *SummingStrategy
+		statuses map[string]map[string]interface{}
+		want     map[string]interface{}
+		wantErr  bool
+	}{
+		"sums the fields": {
+			strategy: &SummingStrategy{Fields: []string{"replicas", "readyReplicas", "nested.count"}},
+			statuses: map[string]map[string]interface{}{
+				"target1": {"replicas": int64(2), "readyReplicas": int64(1), "nested": map[string]interface{}{"count": float64(3)}},
+				"target2": {"replicas": int64(3), "readyReplicas": int64(3)},
+			},
+			want: map[string]interface{}{
+				"replicas":      int64(5),
+				"readyReplicas": int64(4),
+				"nested":        map[string]interface{}{"count": int64(3)},
+			},
+		},
+		"fields missing everywhere are left out": {
+			strategy: &SummingStrategy{Fields: []string{"replicas", "availableReplicas"}},
+			statuses: map[string]map[string]interface{}{
+				"target1": {"replicas": int64(2)},
+				"target2": {"replicas": int64(3)},
+			},
+			want: map[string]interface{}{
+				"replicas": int64(5),
+			},
+		},
+		"invalid field": {
+			strategy: &SummingStrategy{Fields: []string{"replicas"}},
+			statuses: map[string]map[string]interface{}{
+				"target1": {"replicas": "two"},
+			},
+			wantErr: true,
+		},
+		"conditions agreeing on every synctarget": {
+			strategy: &SummingStrategy{MergeConditions: true},
+			statuses: map[string]map[string]interface{}{
+				"target1": {"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "True", "reason": "MinimumReplicasAvailable", "lastTransitionTime": "2022-10-01T10:00:00Z"},
+				}},
+				"target2": {"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "True", "reason": "MinimumReplicasAvailable", "lastTransitionTime": "2022-10-02T10:00:00Z"},
+				}},
+			},
+			want: map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "True", "reason": "MinimumReplicasAvailable", "lastTransitionTime": "2022-10-02T10:00:00Z"},
+				},
+			},
+		},
+		"conditions differing between synctargets": {
+			strategy: &SummingStrategy{MergeConditions: true},
+			statuses: map[string]map[string]interface{}{
+				"target1": {"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "True", "lastTransitionTime": "2022-10-01T10:00:00Z"},
+				}},
+				"target2": {"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "False", "message": "not enough replicas", "lastTransitionTime": "2022-10-02T10:00:00Z"},
+				}},
+				"target3": {"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "True", "lastTransitionTime": "2022-10-01T11:00:00Z"},
+				}},
+			},
+			want: map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "Unknown", "reason": "SyncTargetsDiffer", "message": "True on 2, False on 1 of 3 SyncTargets", "lastTransitionTime": "2022-10-02T10:00:00Z"},
+				},
+			},
+		},
+		"condition missing on a synctarget": {
+			strategy: &SummingStrategy{MergeConditions: true},
+			statuses: map[string]map[string]interface{}{
+				"target1": {"conditions": []interface{}{
+					map[string]interface{}{"type": "Progressing", "status": "True"},
+				}},
+				"target2": {},
+			},
+			want: map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Progressing", "status": "Unknown", "reason": "SyncTargetsDiffer", "message": "True on 1, missing on 1 of 2 SyncTargets"},
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.strategy.MergeStatus(tc.statuses)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}